@@ -0,0 +1,142 @@
+// go/target/amplitude.go
+// Amplitude Normalization - Go Implementation
+
+package rift
+
+import (
+	"fmt"
+	"math"
+	"sync/atomic"
+)
+
+// AmplitudeEpsilon is how far Σ|a|² of an amplitude vector may be from 1
+// for the vector to count as normalized
+const AmplitudeEpsilon = 1e-9
+
+// AmplitudeMode selects what Superpose does with an amplitude vector that
+// is not normalized
+type AmplitudeMode int32
+
+const (
+	AmplitudeAccept    AmplitudeMode = iota // store it as given (default)
+	AmplitudeStrict                         // fail with E_NOT_NORMALIZED
+	AmplitudeNormalize                      // scale it to Σ|a|² = 1
+)
+
+// String returns the mode name
+func (m AmplitudeMode) String() string {
+	switch m {
+	case AmplitudeAccept:
+		return "accept"
+	case AmplitudeStrict:
+		return "strict"
+	case AmplitudeNormalize:
+		return "normalize"
+	}
+	return fmt.Sprintf("AmplitudeMode(%d)", int(m))
+}
+
+// AmplitudeModeNamed returns the mode for a policy's amplitudes setting:
+// "accept" (or ""), "strict" or "normalize"
+func AmplitudeModeNamed(name string) (AmplitudeMode, error) {
+	switch name {
+	case "", "accept":
+		return AmplitudeAccept, nil
+	case "strict":
+		return AmplitudeStrict, nil
+	case "normalize":
+		return AmplitudeNormalize, nil
+	}
+	return 0, fmt.Errorf("unknown amplitude mode %q", name)
+}
+
+var amplitudeMode atomic.Int32
+
+// SetAmplitudeNormalization sets how Superpose treats amplitude vectors
+// that are not normalized. Validate fails for such a superposition in
+// every mode.
+func SetAmplitudeNormalization(mode AmplitudeMode) {
+	amplitudeMode.Store(int32(mode))
+}
+
+// AmplitudeNormalization returns the current amplitude mode
+func AmplitudeNormalization() AmplitudeMode {
+	return AmplitudeMode(amplitudeMode.Load())
+}
+
+// ============================================================================
+// Norms
+// ============================================================================
+
+// AmplitudeNorm returns Σ|a|² of an amplitude vector, the total
+// probability it describes
+func AmplitudeNorm(amplitudes []float64) float64 {
+	return sumSquares(amplitudes)
+}
+
+// NormalizeAmplitudes returns a copy of amplitudes scaled so Σ|a|² = 1,
+// keeping signs. It fails for a vector with a NaN or infinite amplitude or
+// with zero norm.
+func NormalizeAmplitudes(amplitudes []float64) ([]float64, error) {
+	if err := amplitudeValueError("normalize", amplitudes); err != nil {
+		return nil, err
+	}
+	norm := AmplitudeNorm(amplitudes)
+	if norm <= 0 {
+		return nil, govErr(CodeZeroProbability, "normalize", "amplitudes have zero norm")
+	}
+	out := make([]float64, len(amplitudes))
+	scaleInto(out, amplitudes, 1/math.Sqrt(norm))
+	return out, nil
+}
+
+// amplitudeValueError reports the first NaN or infinite amplitude
+func amplitudeValueError(op string, amplitudes []float64) *GovernanceError {
+	for i, a := range amplitudes {
+		if math.IsNaN(a) || math.IsInf(a, 0) {
+			return govErr(CodeNotNormalized, op, "amplitude %d is %v", i, a)
+		}
+	}
+	return nil
+}
+
+// amplitudeError returns why amplitudes are not a normalized vector for n
+// states, or nil. No amplitudes means equal amplitudes and is valid.
+func amplitudeError(op string, amplitudes []float64, n int) *GovernanceError {
+	if len(amplitudes) == 0 {
+		return nil
+	}
+	if len(amplitudes) != n {
+		return govErr(CodeAmplitudeMismatch, op, "%d amplitudes for %d states", len(amplitudes), n)
+	}
+	if err := amplitudeValueError(op, amplitudes); err != nil {
+		return err
+	}
+	norm := AmplitudeNorm(amplitudes)
+	if norm <= 0 {
+		return govErr(CodeZeroProbability, op, "amplitudes have zero norm")
+	}
+	if math.Abs(norm-1) > AmplitudeEpsilon {
+		return govErr(CodeNotNormalized, op, "Σ|a|² = %g, want 1", norm)
+	}
+	return nil
+}
+
+// superposeAmplitudes applies the amplitude mode to the amplitudes given
+// to Superpose
+func superposeAmplitudes(amplitudes []float64) ([]float64, error) {
+	switch AmplitudeNormalization() {
+	case AmplitudeStrict:
+		if err := amplitudeError("superpose", amplitudes, len(amplitudes)); err != nil {
+			return nil, err
+		}
+	case AmplitudeNormalize:
+		if err := amplitudeValueError("superpose", amplitudes); err != nil {
+			return nil, err
+		}
+		if math.Abs(AmplitudeNorm(amplitudes)-1) > AmplitudeEpsilon {
+			return NormalizeAmplitudes(amplitudes)
+		}
+	}
+	return amplitudes, nil
+}
@@ -0,0 +1,17 @@
+// go/target/amplitude_math_amd64.go
+// Amplitude Vector Kernels (amd64) - Go Implementation
+
+//go:build amd64 && !purego
+
+package rift
+
+// sumSquares returns Σa² of a
+func sumSquares(a []float64) float64 {
+	return sumSquaresSSE2(a)
+}
+
+// sumSquaresSSE2 is sumSquaresGeneric in SSE2, two lanes by two
+// accumulators
+//
+//go:noescape
+func sumSquaresSSE2(a []float64) float64
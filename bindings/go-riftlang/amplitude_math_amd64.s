@@ -0,0 +1,47 @@
+// go/target/amplitude_math_amd64.s
+// Amplitude Vector Kernels (amd64) - Go Assembly
+
+//go:build amd64 && !purego
+
+#include "textflag.h"
+
+// func sumSquaresSSE2(a []float64) float64
+TEXT ·sumSquaresSSE2(SB), NOSPLIT, $0-32
+	MOVQ a_base+0(FP), SI
+	MOVQ a_len+8(FP), CX
+	XORPD X0, X0
+	XORPD X1, X1
+	MOVQ CX, DX
+	SHRQ $2, DX
+	JZ   reduce
+
+loop4:
+	MOVUPD 0(SI), X2
+	MOVUPD 16(SI), X3
+	MULPD  X2, X2
+	MULPD  X3, X3
+	ADDPD  X2, X0
+	ADDPD  X3, X1
+	ADDQ   $32, SI
+	DECQ   DX
+	JNZ    loop4
+
+reduce:
+	ADDPD    X1, X0
+	MOVAPD   X0, X1
+	UNPCKHPD X1, X1
+	ADDSD    X1, X0
+	ANDQ     $3, CX
+	JZ       done
+
+loop1:
+	MOVSD 0(SI), X2
+	MULSD X2, X2
+	ADDSD X2, X0
+	ADDQ  $8, SI
+	DECQ  CX
+	JNZ   loop1
+
+done:
+	MOVSD X0, ret+24(FP)
+	RET
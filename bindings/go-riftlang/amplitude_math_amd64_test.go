@@ -0,0 +1,37 @@
+//go:build amd64 && !purego
+
+package rift
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+// TestSumSquaresSSE2 compares the assembly kernel with the generic one for
+// every length up to 64, so each tail length follows full blocks of four.
+// Integer inputs sum exactly in any order and must match bit for bit;
+// others may differ in the last bits, as the kernels associate differently.
+func TestSumSquaresSSE2(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for n := 0; n <= 64; n++ {
+		ints := make([]float64, n+1)
+		reals := make([]float64, n+1)
+		for i := range ints {
+			ints[i] = float64(r.Intn(2001) - 1000)
+			reals[i] = r.NormFloat64()
+		}
+		// Offset by one element so loads are not 16-byte aligned
+		for _, a := range [][]float64{ints[:n], ints[1:]} {
+			if got, want := sumSquaresSSE2(a), sumSquaresGeneric(a); got != want {
+				t.Errorf("n=%d integers: SSE2 %v, generic %v", len(a), got, want)
+			}
+		}
+		for _, a := range [][]float64{reals[:n], reals[1:]} {
+			got, want := sumSquaresSSE2(a), sumSquaresGeneric(a)
+			if math.Abs(got-want) > 1e-12*math.Max(1, want) {
+				t.Errorf("n=%d: SSE2 %v, generic %v", len(a), got, want)
+			}
+		}
+	}
+}
@@ -0,0 +1,11 @@
+// go/target/amplitude_math_other.go
+// Amplitude Vector Kernels (Portable) - Go Implementation
+
+//go:build !amd64 || purego
+
+package rift
+
+// sumSquares returns Σa² of a
+func sumSquares(a []float64) float64 {
+	return sumSquaresGeneric(a)
+}
@@ -0,0 +1,185 @@
+// go/target/arena.go
+// Token Arena Allocator - Go Implementation
+
+package rift
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// DefaultSlabSize is the number of token slots per arena slab
+const DefaultSlabSize = 256
+
+// ============================================================================
+// Slabs
+// ============================================================================
+
+// arenaSlot co-locates a token with its memory span so one slab allocation
+// serves both halves of the triplet
+type arenaSlot struct {
+	token RiftToken
+	span  RiftMemorySpan
+}
+
+// retire leaves the slot's token unallocated on a released span that
+// grants no access, for a Debug arena's Reset
+func (s *arenaSlot) retire() {
+	s.token.valueLock.Lock()
+	wipeValue(&s.token.Value)
+	s.token.version.Add(1)
+	s.token.valueLock.Unlock()
+	s.token.ValidationBits.Store(0)
+	atomic.StoreUint32(&s.span.AccessMask, 0)
+	s.span.Release()
+}
+
+// slabPools recycles slabs between arenas, keyed by slab size
+var slabPools sync.Map // int -> *sync.Pool
+
+func slabPool(size int) *sync.Pool {
+	if p, ok := slabPools.Load(size); ok {
+		return p.(*sync.Pool)
+	}
+	p, _ := slabPools.LoadOrStore(size, &sync.Pool{
+		New: func() interface{} {
+			slab := make([]arenaSlot, size)
+			return &slab
+		},
+	})
+	return p.(*sync.Pool)
+}
+
+// ============================================================================
+// TokenArena
+// ============================================================================
+
+// TokenArena hands out tokens from pre-allocated slabs. Tokens are only valid
+// until the next Reset, after which their slots are reused: a token kept
+// past Reset aliases whichever token is handed out next from its slot. Set
+// Debug to make such use detectable.
+type TokenArena struct {
+	lock      sync.Mutex
+	slabSize  int
+	slabs     []*[]arenaSlot
+	used      int // slots used in the last slab
+	live      int
+	intern    *internPool // nil unless interning (see SetInterning)
+	SpanBytes uint64      // bytes for each token span (default 64)
+
+	// Debug makes Reset retire its slots instead of reusing them. A retired
+	// token is unallocated on a released span that grants no access, so a
+	// token kept past Reset fails validation and governed access with
+	// E_NOT_ALLOCATED or E_PERMISSION_DENIED rather than aliasing a newer
+	// token. Retired slabs are left to the garbage collector.
+	Debug bool
+}
+
+// NewTokenArena creates an arena with slabs of slabSize tokens
+func NewTokenArena(slabSize int) *TokenArena {
+	if slabSize <= 0 {
+		slabSize = DefaultSlabSize
+	}
+	a := &TokenArena{slabSize: slabSize, SpanBytes: 64}
+	a.slabs = append(a.slabs, slabPool(slabSize).Get().(*[]arenaSlot))
+	return a
+}
+
+// NewToken allocates a token of tokenType with a span aligned for that type.
+// The token is set up as NewRiftToken sets one up, recording its source and
+// tracking it for leak detection, but its span has no backing bytes until
+// written.
+func (a *TokenArena) NewToken(tokenType int) *RiftToken {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+
+	if a.used == a.slabSize {
+		a.slabs = append(a.slabs, slabPool(a.slabSize).Get().(*[]arenaSlot))
+		a.used = 0
+	}
+	slot := &(*a.slabs[len(a.slabs)-1])[a.used]
+	a.used++
+	a.live++
+
+	spanType := SpanFixed
+	alignment := uint32(ClassicalAlignment)
+	if tokenType == TokenQGoInt || tokenType == TokenQGoChan {
+		spanType = SpanSuperposed
+		alignment = QuantumAlignment
+	}
+	slot.span = RiftMemorySpan{
+		Type:       spanType,
+		Bytes:      a.SpanBytes,
+		Alignment:  alignment,
+		Open:       true,
+		Direction:  true,
+		AccessMask: AccessCRUD,
+	}
+	slot.token = RiftToken{
+		Type:   tokenType,
+		Memory: &slot.span,
+	}
+	slot.token.ValidationBits.Store(TokenAllocated)
+	slot.token.captureSource()
+	slot.token.countCreated()
+	trackLeak(&slot.token)
+	publishEvent(EventCreated, &slot.token, "")
+	return &slot.token
+}
+
+// Reset releases every token handed out since the last Reset, zeroing the
+// slots and returning surplus slabs to the shared pool, or retiring them
+// under Debug. It also empties the string intern pool.
+func (a *TokenArena) Reset() {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+
+	for i, slab := range a.slabs {
+		n := a.slabSize
+		if i == len(a.slabs)-1 {
+			n = a.used
+		}
+		s := *slab
+		for j := 0; j < n; j++ {
+			if s[j].token.EntanglementID != 0 {
+				unregisterEntanglement(&s[j].token)
+			}
+			s[j].token.dropLive()
+			s[j].token.releaseQuota()
+			untrackLeak(&s[j].token)
+			if a.Debug {
+				s[j].retire()
+			} else {
+				s[j] = arenaSlot{}
+			}
+		}
+		if i > 0 && !a.Debug {
+			slabPool(a.slabSize).Put(slab)
+		}
+	}
+	if a.Debug {
+		slab := make([]arenaSlot, a.slabSize)
+		a.slabs = []*[]arenaSlot{&slab}
+	} else {
+		a.slabs = a.slabs[:1]
+	}
+	a.used = 0
+	a.live = 0
+	if a.intern != nil {
+		a.intern.reset()
+	}
+}
+
+// Len returns the number of live tokens
+func (a *TokenArena) Len() int {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+	return a.live
+}
+
+// Cap returns the number of token slots currently reserved
+func (a *TokenArena) Cap() int {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+	return len(a.slabs) * a.slabSize
+}
@@ -0,0 +1,149 @@
+// go/target/arena_intern.go
+// Arena String Interning - Go Implementation
+
+package rift
+
+import "sync/atomic"
+
+// ============================================================================
+// Intern Pool
+// ============================================================================
+
+// internPool holds one copy of each distinct string an arena has interned;
+// arena lock held for every method
+type internPool struct {
+	strings    map[string]string
+	bytes      uint64
+	hits       uint64
+	savedBytes uint64
+}
+
+// InternStats reports what an arena's intern pool holds and has saved.
+// Strings and Bytes cover the pool since the last Reset; Hits and
+// SavedBytes count over the life of the arena.
+type InternStats struct {
+	Strings    int    // distinct strings held
+	Bytes      uint64 // bytes of the strings held
+	Hits       uint64 // lookups answered with a string already held
+	SavedBytes uint64 // bytes those lookups did not duplicate
+}
+
+// Process-wide interning counters, read as MetricInternHits and
+// MetricInternSavedBytes
+var (
+	internHits       atomic.Uint64
+	internSavedBytes atomic.Uint64
+)
+
+// lookup returns the pooled copy of s, or of b when s is empty and b is not
+// nil, adding it if it is new
+func (p *internPool) lookup(s string, b []byte) string {
+	if b != nil {
+		if held, ok := p.strings[string(b)]; ok { // no allocation for the lookup
+			p.hit(len(held))
+			return held
+		}
+		s = string(b)
+	} else if held, ok := p.strings[s]; ok {
+		p.hit(len(held))
+		return held
+	}
+	p.strings[s] = s
+	p.bytes += uint64(len(s))
+	return s
+}
+
+// hit counts a lookup answered from the pool
+func (p *internPool) hit(n int) {
+	p.hits++
+	p.savedBytes += uint64(n)
+	internHits.Add(1)
+	internSavedBytes.Add(uint64(n))
+}
+
+// reset empties the pool, keeping its counters
+func (p *internPool) reset() {
+	clear(p.strings)
+	p.bytes = 0
+}
+
+// ============================================================================
+// TokenArena Interning
+// ============================================================================
+
+// SetInterning turns the arena's string intern pool on or off. While it is
+// on, Intern, InternBytes and NewStringToken share one copy of each distinct
+// string among the arena's tokens until the next Reset, which suits
+// tokenizer workloads creating many tokens from few distinct strings.
+// Turning it off drops the pool; strings already handed out stay valid.
+// Interning is off by default.
+func (a *TokenArena) SetInterning(on bool) {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+	switch {
+	case on && a.intern == nil:
+		a.intern = &internPool{strings: make(map[string]string)}
+	case !on:
+		a.intern = nil
+	}
+}
+
+// Interning reports whether the arena interns strings
+func (a *TokenArena) Interning() bool {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+	return a.intern != nil
+}
+
+// Intern returns the arena's copy of s, adding s to the pool if it is new.
+// It returns s itself when interning is off.
+func (a *TokenArena) Intern(s string) string {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+	if a.intern == nil {
+		return s
+	}
+	return a.intern.lookup(s, nil)
+}
+
+// InternBytes is Intern for a string held in b, as read from an input
+// buffer; it allocates only for a string not already in the pool. b may be
+// reused once InternBytes returns.
+func (a *TokenArena) InternBytes(b []byte) string {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+	if a.intern == nil {
+		return string(b)
+	}
+	if b == nil {
+		b = []byte{}
+	}
+	return a.intern.lookup("", b)
+}
+
+// NewStringToken allocates an initialized, validated TokenGoString token
+// holding s, interned when interning is on. Values set later with SetValue
+// are not interned; pass them through Intern first.
+func (a *TokenArena) NewStringToken(s string) *RiftToken {
+	token := a.NewToken(TokenGoString)
+	token.Value.StringVal = a.Intern(s)
+	token.SetBit(TokenInitialized)
+	token.Validate()
+	return token
+}
+
+// InternStats returns the intern pool's statistics, all zero when interning
+// is off
+func (a *TokenArena) InternStats() InternStats {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+	if a.intern == nil {
+		return InternStats{}
+	}
+	return InternStats{
+		Strings:    len(a.intern.strings),
+		Bytes:      a.intern.bytes,
+		Hits:       a.intern.hits,
+		SavedBytes: a.intern.savedBytes,
+	}
+}
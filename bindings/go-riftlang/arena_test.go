@@ -0,0 +1,122 @@
+package rift
+
+import (
+	"testing"
+)
+
+// TestTokenArenaReuseAfterReset pins what a token kept past Reset sees: by
+// default its slot is reused, under Debug it is retired
+func TestTokenArenaReuseAfterReset(t *testing.T) {
+	t.Run("default", func(t *testing.T) {
+		a := NewTokenArena(4)
+		stale := a.NewToken(TokenGoInt)
+		a.Reset()
+		fresh := a.NewToken(TokenGoInt)
+		if stale != fresh {
+			t.Fatal("slot not reused after Reset")
+		}
+	})
+
+	t.Run("debug", func(t *testing.T) {
+		a := NewTokenArena(4)
+		a.Debug = true
+		var stale []*RiftToken
+		for i := range 6 { // spans two slabs
+			tok := a.NewToken(TokenGoInt)
+			if err := tok.SetValue(RiftTokenValue{IntVal: int64(i)}); err != nil {
+				t.Fatal(err)
+			}
+			stale = append(stale, tok)
+		}
+		a.Reset()
+		fresh := a.NewToken(TokenGoInt)
+		if err := fresh.SetValue(RiftTokenValue{IntVal: 99}); err != nil {
+			t.Fatal(err)
+		}
+
+		for i, tok := range stale {
+			if tok == fresh {
+				t.Fatalf("token %d aliases a token handed out after Reset", i)
+			}
+			if code := ErrorCodeOf(tok.ValidateErr()); code != CodeNotAllocated {
+				t.Errorf("token %d: Validate code %v, want %v", i, code, CodeNotAllocated)
+			}
+			if _, err := tok.GetValue(); ErrorCodeOf(err) != CodePermissionDenied {
+				t.Errorf("token %d: GetValue error %v, want %v", i, err, CodePermissionDenied)
+			}
+			if err := tok.SetValue(RiftTokenValue{IntVal: 1}); ErrorCodeOf(err) != CodePermissionDenied {
+				t.Errorf("token %d: SetValue error %v, want %v", i, err, CodePermissionDenied)
+			}
+			if !tok.Memory.Released() {
+				t.Errorf("token %d: span not released", i)
+			}
+		}
+		if got, err := fresh.GetValue(); err != nil || got.IntVal != 99 {
+			t.Fatalf("fresh token = %v, %v; want 99", got.IntVal, err)
+		}
+		if a.Len() != 1 || a.Cap() != 4 {
+			t.Fatalf("Len, Cap = %d, %d; want 1, 4", a.Len(), a.Cap())
+		}
+	})
+}
+
+// TestTokenArenaLeakTracking checks arena tokens are tracked as NewRiftToken's
+// are, and untracked by Reset
+func TestTokenArenaLeakTracking(t *testing.T) {
+	SetLeakDetection(true)
+	t.Cleanup(func() { SetLeakDetection(false) })
+
+	a := NewTokenArena(4)
+	tok := a.NewToken(TokenGoInt)
+	tracked := func() bool {
+		for _, r := range Leaks() {
+			if r.Token == tok {
+				return true
+			}
+		}
+		return false
+	}
+	if !tracked() {
+		t.Fatal("arena token not tracked")
+	}
+	a.Reset()
+	if tracked() {
+		t.Fatal("arena token still tracked after Reset")
+	}
+}
+
+// BenchmarkTokenAllocation compares arena allocation with NewRiftToken
+func BenchmarkTokenAllocation(b *testing.B) {
+	b.Run("NewRiftToken", func(b *testing.B) {
+		b.ReportAllocs()
+		for b.Loop() {
+			NewRiftToken(TokenGoInt, NewRiftMemorySpan(SpanFixed, 64))
+		}
+	})
+	b.Run("arena", func(b *testing.B) {
+		a := NewTokenArena(DefaultSlabSize)
+		b.ReportAllocs()
+		n := 0
+		for b.Loop() {
+			a.NewToken(TokenGoInt)
+			if n++; n == 4*DefaultSlabSize {
+				a.Reset()
+				n = 0
+			}
+		}
+	})
+	b.Run("arena/nosource", func(b *testing.B) {
+		SetSourceCapture(false)
+		b.Cleanup(func() { SetSourceCapture(true) })
+		a := NewTokenArena(DefaultSlabSize)
+		b.ReportAllocs()
+		n := 0
+		for b.Loop() {
+			a.NewToken(TokenGoInt)
+			if n++; n == 4*DefaultSlabSize {
+				a.Reset()
+				n = 0
+			}
+		}
+	})
+}
@@ -0,0 +1,180 @@
+// go/target/audit.go
+// Governance Audit Log - Go Implementation
+
+package rift
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"reflect"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// ============================================================================
+// Audit Events
+// ============================================================================
+
+// AuditEventKind classifies a governance event
+type AuditEventKind string
+
+const (
+	AuditLock          AuditEventKind = "lock"
+	AuditUnlock        AuditEventKind = "unlock"
+	AuditSetValue      AuditEventKind = "set_value"
+	AuditDelete        AuditEventKind = "delete"
+	AuditAccessDenied  AuditEventKind = "access_denied"
+	AuditValidateFail  AuditEventKind = "validate_fail"
+	AuditSuperpose     AuditEventKind = "superpose"
+	AuditEntangle      AuditEventKind = "entangle"
+	AuditCollapse      AuditEventKind = "collapse"
+	AuditGate          AuditEventKind = "gate"
+	AuditShadowCommit  AuditEventKind = "shadow_commit"
+	AuditShadowDiscard AuditEventKind = "shadow_discard"
+	AuditPanic         AuditEventKind = "panic"
+	AuditRestart       AuditEventKind = "restart"
+	AuditLockHold      AuditEventKind = "lock_hold"
+	AuditLockOrder     AuditEventKind = "lock_order"
+	AuditConvert       AuditEventKind = "convert"
+	AuditExpire        AuditEventKind = "expire"
+	AuditRPC           AuditEventKind = "rpc"
+	AuditDecohere      AuditEventKind = "decohere"
+	AuditClone         AuditEventKind = "clone"
+	AuditTxCommit      AuditEventKind = "tx_commit"
+	AuditTxRollback    AuditEventKind = "tx_rollback"
+	AuditRelease       AuditEventKind = "release"
+)
+
+// AuditEvent is a single append-only audit record
+type AuditEvent struct {
+	Seq            uint64         `json:"seq"`
+	Time           time.Time      `json:"time"`
+	Kind           AuditEventKind `json:"kind"`
+	Goroutine      uint64         `json:"goroutine"`
+	File           string         `json:"file,omitempty"`
+	Line           int            `json:"line,omitempty"`
+	TokenType      int            `json:"tokenType"`
+	TokenBits      uint32         `json:"tokenBits"`
+	EntanglementID uint32         `json:"entanglementId,omitempty"`
+	TokenSource    string         `json:"tokenSource,omitempty"`
+	Detail         string         `json:"detail,omitempty"`
+}
+
+// ============================================================================
+// Audit Sink
+// ============================================================================
+
+var audit struct {
+	enabled atomic.Bool
+	lock    sync.Mutex
+	sink    io.Writer
+	seq     uint64
+}
+
+// SetAuditSink directs audit events to w as JSON lines; nil disables
+// auditing. Events are written by the goroutine emitting them, while
+// other emitters wait; wrap a slow w in an AsyncAuditSink to write them
+// in the background.
+func SetAuditSink(w io.Writer) {
+	audit.lock.Lock()
+	defer audit.lock.Unlock()
+	audit.sink = w
+	audit.enabled.Store(w != nil)
+}
+
+// AuditEnabled reports whether an audit sink is installed
+func AuditEnabled() bool {
+	return audit.enabled.Load()
+}
+
+// EmitAudit records an event for t (which may be nil) if auditing is
+// enabled, for packages layered on the binding such as riftserver
+func EmitAudit(kind AuditEventKind, t *RiftToken, detail string) {
+	auditEmit(kind, t, detail)
+}
+
+// auditEmit records an event for t if auditing is enabled
+func auditEmit(kind AuditEventKind, t *RiftToken, detail string) {
+	sink := scopeAudit()
+	if sink == nil && !audit.enabled.Load() {
+		return
+	}
+
+	event := AuditEvent{
+		Time:      clockNow(),
+		Kind:      kind,
+		Goroutine: goroutineID(),
+		Detail:    detail,
+	}
+	event.File, event.Line = callerOutsidePackage()
+	if t != nil {
+		event.TokenType = t.Type
+		event.TokenBits = t.ValidationBits.Load()
+		event.EntanglementID = t.EntanglementID
+		event.TokenSource = t.Source()
+	}
+
+	audit.lock.Lock()
+	defer audit.lock.Unlock()
+	if sink == nil {
+		sink = audit.sink
+	}
+	if sink == nil {
+		return
+	}
+	audit.seq++
+	event.Seq = audit.seq
+	if a, ok := sink.(*AsyncAuditSink); ok {
+		a.enqueueEvent(event)
+		return
+	}
+	line, err := json.Marshal(event)
+	if err != nil {
+		return
+	}
+	sink.Write(append(line, '\n'))
+}
+
+// ============================================================================
+// Runtime Helpers
+// ============================================================================
+
+// goroutineID parses the current goroutine ID from the stack header
+func goroutineID() uint64 {
+	var buf [64]byte
+	n := runtime.Stack(buf[:], false)
+	b := bytes.TrimPrefix(buf[:n], []byte("goroutine "))
+	if i := bytes.IndexByte(b, ' '); i > 0 {
+		b = b[:i]
+	}
+	id, _ := strconv.ParseUint(string(b), 10, 64)
+	return id
+}
+
+// packagePrefix is the symbol prefix of functions in this package
+var packagePrefix = func() string {
+	name := runtime.FuncForPC(reflect.ValueOf(SetAuditSink).Pointer()).Name()
+	return name[:strings.LastIndex(name, ".")+1]
+}()
+
+// callerOutsidePackage returns the first stack frame outside this package
+// (package tests count as outside)
+func callerOutsidePackage() (string, int) {
+	var pcs [32]uintptr
+	n := runtime.Callers(2, pcs[:])
+	frames := runtime.CallersFrames(pcs[:n])
+	for {
+		frame, more := frames.Next()
+		if !strings.HasPrefix(frame.Function, packagePrefix) || strings.HasSuffix(frame.File, "_test.go") {
+			return frame.File, frame.Line
+		}
+		if !more {
+			return "", 0
+		}
+	}
+}
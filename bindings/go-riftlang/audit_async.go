@@ -0,0 +1,305 @@
+// go/target/audit_async.go
+// Asynchronous Audit Pipeline - Go Implementation
+
+package rift
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"sync"
+	"sync/atomic"
+)
+
+// ErrAuditClosed is returned by the Write, Flush and Close methods of an
+// AsyncAuditSink that has been closed
+var ErrAuditClosed = errors.New("audit sink closed")
+
+// auditDropped counts events dropped by every AsyncAuditSink
+var auditDropped atomic.Uint64
+
+// ============================================================================
+// Options
+// ============================================================================
+
+// AuditOverflow selects what an AsyncAuditSink does with an event when its
+// queue is full
+type AuditOverflow int
+
+const (
+	// AuditBlock makes the emitting goroutine wait for room in the queue,
+	// slowing governance to the sink's pace but losing nothing. This is
+	// the default.
+	AuditBlock AuditOverflow = iota
+
+	// AuditDrop discards the event and counts it (see Dropped), so a slow
+	// sink never slows governance
+	AuditDrop
+)
+
+// AsyncAuditOptions configures an AsyncAuditSink. Zero fields take their
+// defaults.
+type AsyncAuditOptions struct {
+	QueueSize int           // events held before Overflow applies; default 4096
+	Overflow  AuditOverflow // default AuditBlock
+	BatchSize int           // most events written to the sink in one Write; default 256
+}
+
+// ============================================================================
+// AsyncAuditSink
+// ============================================================================
+
+// AsyncAuditSink is an audit sink that queues events and writes them to
+// another sink from its own goroutine, so Lock, SetValue and the other
+// audited operations do not wait on the sink's I/O. Installed with
+// SetAuditSink (or as a Scope's Audit writer), it also takes events
+// before they are encoded, leaving the JSON encoding to its goroutine.
+// Events queued together are written to the sink in one Write, as JSON
+// lines in Seq order. Call Close, or at least Flush, before exiting so
+// queued events are not lost.
+type AsyncAuditSink struct {
+	w        io.Writer
+	overflow AuditOverflow
+	batch    int
+
+	queue   chan auditItem
+	done    chan struct{} // closed when the writer goroutine exits
+	closed  atomic.Bool
+	close   sync.Once
+	dropped atomic.Uint64
+
+	lock sync.Mutex // guards err
+	err  error      // first error the sink returned
+}
+
+// auditItem is one queue entry: an event, a line written as bytes, or a
+// flush request whose channel is closed once everything before it is
+// written
+type auditItem struct {
+	event *AuditEvent
+	line  []byte
+	flush chan struct{}
+	stop  bool
+}
+
+// NewAsyncAuditSink starts a pipeline writing the events it is given to
+// w. Unless w is safe for concurrent use it must only be written by the
+// pipeline. If w has a `Flush() error` method, as a *bufio.Writer does,
+// it is called after each batch that empties the queue and on Flush.
+func NewAsyncAuditSink(w io.Writer, opts AsyncAuditOptions) *AsyncAuditSink {
+	if opts.QueueSize <= 0 {
+		opts.QueueSize = 4096
+	}
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = 256
+	}
+	a := &AsyncAuditSink{
+		w:        w,
+		overflow: opts.Overflow,
+		batch:    opts.BatchSize,
+		queue:    make(chan auditItem, opts.QueueSize),
+		done:     make(chan struct{}),
+	}
+	go a.run()
+	return a
+}
+
+// Write queues p, which should hold whole JSON lines, to be written to
+// the sink as it is. It fails only once the sink is closed; an event the
+// overflow policy drops is counted, not reported.
+func (a *AsyncAuditSink) Write(p []byte) (int, error) {
+	if !a.enqueue(auditItem{line: append([]byte(nil), p...)}) {
+		return 0, ErrAuditClosed
+	}
+	return len(p), nil
+}
+
+// Flush waits until every event queued before it is written to the sink
+// and returns the first error the sink has returned, if any
+func (a *AsyncAuditSink) Flush() error {
+	done := make(chan struct{})
+	if !a.send(auditItem{flush: done}) {
+		return ErrAuditClosed
+	}
+	select {
+	case <-done:
+	case <-a.done:
+	}
+	return a.Err()
+}
+
+// Close flushes the queue and stops the pipeline, returning the first
+// error the sink has returned. Events emitted afterwards are dropped; the
+// sink itself is not closed. Uninstall the pipeline with SetAuditSink
+// before closing it so no events are dropped.
+func (a *AsyncAuditSink) Close() error {
+	first := false
+	a.close.Do(func() {
+		first = true
+		a.closed.Store(true)
+		a.queue <- auditItem{stop: true}
+		<-a.done
+		// Events that raced with Close and queued behind the stop
+		for it, more := a.next(); more; it, more = a.next() {
+			if it.flush == nil {
+				a.drop()
+			}
+		}
+	})
+	if !first {
+		return ErrAuditClosed
+	}
+	return a.Err()
+}
+
+// Dropped returns the number of events the overflow policy discarded,
+// and those emitted after Close
+func (a *AsyncAuditSink) Dropped() uint64 {
+	return a.dropped.Load()
+}
+
+// Pending returns the number of events queued and not yet written
+func (a *AsyncAuditSink) Pending() int {
+	return len(a.queue)
+}
+
+// Err returns the first error the sink returned, or nil
+func (a *AsyncAuditSink) Err() error {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+	return a.err
+}
+
+// enqueueEvent queues an event for encoding by the pipeline; audit.lock
+// held, so events queue in Seq order
+func (a *AsyncAuditSink) enqueueEvent(event AuditEvent) {
+	a.enqueue(auditItem{event: &event})
+}
+
+// enqueue applies the overflow policy to an event, reporting false once
+// the pipeline is closed
+func (a *AsyncAuditSink) enqueue(it auditItem) bool {
+	if a.closed.Load() {
+		a.drop()
+		return false
+	}
+	if a.overflow == AuditDrop {
+		select {
+		case a.queue <- it:
+		default:
+			a.drop()
+		}
+		return true
+	}
+	if !a.send(it) {
+		a.drop()
+		return false
+	}
+	return true
+}
+
+// send queues it, waiting for room, and reports false if the pipeline
+// stops first
+func (a *AsyncAuditSink) send(it auditItem) bool {
+	if a.closed.Load() {
+		return false
+	}
+	select {
+	case a.queue <- it:
+		return true
+	case <-a.done:
+		return false
+	}
+}
+
+// drop counts a discarded event
+func (a *AsyncAuditSink) drop() {
+	a.dropped.Add(1)
+	auditDropped.Add(1)
+}
+
+// ============================================================================
+// Writer Goroutine
+// ============================================================================
+
+// run writes queued events in batches until Close
+func (a *AsyncAuditSink) run() {
+	defer close(a.done)
+	var buf []byte
+	for it := range a.queue {
+		n := 0
+		for more := true; more; it, more = a.next() {
+			if it.stop || it.flush != nil {
+				a.write(buf)
+				buf = buf[:0]
+				a.flushSink()
+				if it.stop {
+					return
+				}
+				close(it.flush)
+				continue
+			}
+			buf = it.append(buf)
+			if n++; n >= a.batch {
+				a.write(buf)
+				buf, n = buf[:0], 0
+			}
+		}
+		if len(buf) > 0 {
+			a.write(buf)
+			buf = buf[:0]
+			a.flushSink()
+		}
+	}
+}
+
+// next returns the next queued item without waiting, and whether there
+// was one
+func (a *AsyncAuditSink) next() (auditItem, bool) {
+	select {
+	case it := <-a.queue:
+		return it, true
+	default:
+		return auditItem{}, false
+	}
+}
+
+// append adds the item's JSON line to buf
+func (it auditItem) append(buf []byte) []byte {
+	if it.event == nil {
+		return append(buf, it.line...)
+	}
+	line, err := json.Marshal(it.event)
+	if err != nil {
+		return buf
+	}
+	return append(append(buf, line...), '\n')
+}
+
+// write hands a batch to the sink, keeping its first error
+func (a *AsyncAuditSink) write(buf []byte) {
+	if len(buf) == 0 {
+		return
+	}
+	if _, err := a.w.Write(buf); err != nil {
+		a.fail(err)
+	}
+}
+
+// flushSink flushes a sink that buffers
+func (a *AsyncAuditSink) flushSink() {
+	if f, ok := a.w.(interface{ Flush() error }); ok {
+		if err := f.Flush(); err != nil {
+			a.fail(err)
+		}
+	}
+}
+
+// fail records the sink's first error
+func (a *AsyncAuditSink) fail(err error) {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+	if a.err == nil {
+		a.err = err
+	}
+}
@@ -0,0 +1,99 @@
+// go/target/batch.go
+// Batch Token Operations - Go Implementation
+
+package rift
+
+// ============================================================================
+// Validation
+// ============================================================================
+
+// BatchValidate validates every token and returns their errors, one per
+// token at the same index; the entry is nil for a token that validated and
+// CodeNilToken for a nil one. Each token is validated, audited and marked
+// governed as ValidateErr does.
+func BatchValidate(tokens []*RiftToken) []error {
+	errs := make([]error, len(tokens))
+	for i, t := range tokens {
+		if t == nil {
+			errs[i] = govErr(CodeNilToken, "validate", "nil token at index %d", i)
+			continue
+		}
+		errs[i] = t.ValidateErr()
+	}
+	return errs
+}
+
+// ============================================================================
+// Locking
+// ============================================================================
+
+// BatchLock acquires the locks of tokens and returns a func releasing them.
+// The locks are taken in the deterministic order Transaction commits in,
+// whatever the order of tokens, so concurrent BatchLock calls and
+// transactions over overlapping tokens cannot deadlock on each other. A
+// token listed twice is locked once. BatchLock fails with CodeNilToken for
+// a nil token, or with CodeDeadlock, holding no locks, if deadlock
+// detection refuses one.
+func BatchLock(tokens []*RiftToken) (unlock func(), err error) {
+	locked, err := batchLock("batch lock", tokens)
+	if err != nil {
+		return nil, err
+	}
+	return func() { unlockAll(locked) }, nil
+}
+
+// batchLock locks the distinct tokens of tokens in lock order and returns
+// them in that order
+func batchLock(op string, tokens []*RiftToken) ([]*RiftToken, error) {
+	seen := make(map[*RiftToken]bool, len(tokens))
+	distinct := make([]*RiftToken, 0, len(tokens))
+	for i, t := range tokens {
+		if t == nil {
+			return nil, govErr(CodeNilToken, op, "nil token at index %d", i)
+		}
+		if !seen[t] {
+			seen[t] = true
+			distinct = append(distinct, t)
+		}
+	}
+	if err := lockSorted(op, distinct); err != nil {
+		return nil, err
+	}
+	return distinct, nil
+}
+
+// ============================================================================
+// Values
+// ============================================================================
+
+// BatchSetValue sets tokens[i] to values[i] for every i, holding the locks
+// of all the tokens, taken as BatchLock takes them, so other lock holders
+// see either none or all of the changes. Each value is set as SetValue
+// sets it, in slice order, and the errors are returned one per token at
+// the same index. A failed entry does not stop the others; use Transaction
+// to apply all the changes or none. Nil tokens fail with CodeNilToken and
+// leave the batch unapplied, as does a refused lock; values of the wrong
+// length fail every entry with CodeIndexOutOfRange.
+func BatchSetValue(tokens []*RiftToken, values []RiftTokenValue) []error {
+	errs := make([]error, len(tokens))
+	if len(values) != len(tokens) {
+		err := govErr(CodeIndexOutOfRange, "batch set", "%d values for %d tokens", len(values), len(tokens))
+		for i := range errs {
+			errs[i] = err
+		}
+		return errs
+	}
+
+	locked, err := batchLock("batch set", tokens)
+	if err != nil {
+		for i := range errs {
+			errs[i] = err
+		}
+		return errs
+	}
+	defer unlockAll(locked)
+	for i, t := range tokens {
+		errs[i] = t.SetValue(values[i])
+	}
+	return errs
+}
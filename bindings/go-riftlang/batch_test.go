@@ -0,0 +1,96 @@
+package rift
+
+import (
+	"slices"
+	"sync"
+	"testing"
+	"time"
+)
+
+// TestBatchLockOppositeOrders runs BatchLock over the same tokens listed in
+// opposite orders from many goroutines: lock ordering lets all finish
+func TestBatchLockOppositeOrders(t *testing.T) {
+	SetDeadlockDetection(DeadlockError)
+	t.Cleanup(func() { SetDeadlockDetection(DeadlockOff) })
+
+	tokens := make([]*RiftToken, 5)
+	for i := range tokens {
+		tokens[i] = newLockToken()
+	}
+	reversed := slices.Clone(tokens)
+	slices.Reverse(reversed)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		var wg sync.WaitGroup
+		for g := range 8 {
+			order := tokens
+			if g%2 == 1 {
+				order = reversed
+			}
+			wg.Go(func() {
+				for range 100 {
+					unlock, err := BatchLock(order)
+					if err != nil {
+						t.Error(err)
+						return
+					}
+					for _, tok := range order {
+						if !tok.HasBit(TokenLocked) {
+							t.Error("token in batch not locked")
+						}
+					}
+					unlock()
+				}
+			})
+		}
+		wg.Wait()
+	}()
+	select {
+	case <-done:
+	case <-time.After(30 * time.Second):
+		t.Fatal("BatchLock calls in opposite orders deadlocked")
+	}
+}
+
+func TestBatchLockDuplicatesAndNil(t *testing.T) {
+	a, b := newLockToken(), newLockToken()
+	unlock, err := BatchLock([]*RiftToken{a, b, a})
+	if err != nil {
+		t.Fatal(err)
+	}
+	unlock()
+	if a.HasBit(TokenLocked) || b.HasBit(TokenLocked) {
+		t.Fatal("tokens still locked after unlock")
+	}
+
+	if _, err := BatchLock([]*RiftToken{a, nil}); ErrorCodeOf(err) != CodeNilToken {
+		t.Fatalf("BatchLock with a nil token = %v, want %v", err, CodeNilToken)
+	}
+	if !a.TryLock() {
+		t.Fatal("failed BatchLock left a locked")
+	}
+	a.Unlock()
+}
+
+func TestBatchSetValue(t *testing.T) {
+	a, b := newLockToken(), newLockToken()
+	errs := BatchSetValue([]*RiftToken{a, b}, []RiftTokenValue{{IntVal: 1}, {IntVal: 2}})
+	for i, err := range errs {
+		if err != nil {
+			t.Fatalf("entry %d: %v", i, err)
+		}
+	}
+	if va, _ := a.GetValue(); va.IntVal != 1 {
+		t.Errorf("a = %d, want 1", va.IntVal)
+	}
+	if vb, _ := b.GetValue(); vb.IntVal != 2 {
+		t.Errorf("b = %d, want 2", vb.IntVal)
+	}
+	for i, err := range BatchSetValue([]*RiftToken{a, b}, []RiftTokenValue{{}}) {
+		if ErrorCodeOf(err) != CodeIndexOutOfRange {
+			t.Errorf("entry %d of a short batch: %v, want %v", i, err, CodeIndexOutOfRange)
+		}
+	}
+}
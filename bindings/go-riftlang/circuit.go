@@ -0,0 +1,342 @@
+// go/target/circuit.go
+// Quantum Circuit Builder - Go Implementation
+
+package rift
+
+import (
+	"fmt"
+	"math"
+	"math/cmplx"
+	"math/rand"
+	"strings"
+)
+
+// ============================================================================
+// Circuit
+// ============================================================================
+
+// stepKind is the operation of a circuit step
+type stepKind int
+
+const (
+	stepGate stepKind = iota
+	stepEntangle
+	stepMeasure
+)
+
+// circuitStep is one queued operation
+type circuitStep struct {
+	kind   stepKind
+	name   string // gate name: h, x, y, z, phase or gate
+	token  *RiftToken
+	other  *RiftToken // entangle partner
+	qubit  int
+	gate   Gate
+	theta  float64
+	result int // index into the results of a measure step
+}
+
+// String describes the step for errors
+func (s circuitStep) String() string {
+	switch s.kind {
+	case stepEntangle:
+		return "entangle"
+	case stepMeasure:
+		return "measure"
+	}
+	return fmt.Sprintf("%s on qubit %d", s.name, s.qubit)
+}
+
+// Circuit queues gates, entanglements and measurements against tokens for
+// deferred execution. Nothing touches the tokens until Run, which applies
+// the steps in order and validates every token a step involves before and
+// after it, stopping at the first failure. The builder methods return the
+// circuit so calls can be chained. A Circuit is not safe for concurrent
+// use.
+type Circuit struct {
+	steps    []circuitStep
+	widths   map[*RiftToken]int // register width of each token when queued
+	measures int
+	results  []*RiftToken
+	ran      int // steps completed by the last Run
+}
+
+// NewCircuit returns an empty circuit
+func NewCircuit() *Circuit {
+	return &Circuit{}
+}
+
+// H queues a Hadamard gate on qubit q of t
+func (c *Circuit) H(t *RiftToken, q int) *Circuit {
+	return c.add(circuitStep{kind: stepGate, name: "h", token: t, qubit: q, gate: GateHadamard})
+}
+
+// X queues a Pauli-X gate on qubit q of t
+func (c *Circuit) X(t *RiftToken, q int) *Circuit {
+	return c.add(circuitStep{kind: stepGate, name: "x", token: t, qubit: q, gate: GatePauliX})
+}
+
+// Y queues a Pauli-Y gate on qubit q of t
+func (c *Circuit) Y(t *RiftToken, q int) *Circuit {
+	return c.add(circuitStep{kind: stepGate, name: "y", token: t, qubit: q, gate: GatePauliY})
+}
+
+// Z queues a Pauli-Z gate on qubit q of t
+func (c *Circuit) Z(t *RiftToken, q int) *Circuit {
+	return c.add(circuitStep{kind: stepGate, name: "z", token: t, qubit: q, gate: GatePauliZ})
+}
+
+// Phase queues a phase shift by theta on qubit q of t
+func (c *Circuit) Phase(t *RiftToken, q int, theta float64) *Circuit {
+	return c.add(circuitStep{kind: stepGate, name: "phase", token: t, qubit: q, gate: GatePhaseShift(theta), theta: theta})
+}
+
+// Gate queues an arbitrary single-qubit gate on qubit q of t
+func (c *Circuit) Gate(t *RiftToken, q int, g Gate) *Circuit {
+	return c.add(circuitStep{kind: stepGate, name: "gate", token: t, qubit: q, gate: g})
+}
+
+// Entangle queues entangling a and b both ways under a newly allocated
+// entanglement ID, as the package-level Entangle does
+func (c *Circuit) Entangle(a, b *RiftToken) *Circuit {
+	return c.add(circuitStep{kind: stepEntangle, token: a, other: b})
+}
+
+// Measure queues a measurement of t. Its outcome is the Results entry with
+// the index of this measurement among the circuit's measurements.
+func (c *Circuit) Measure(t *RiftToken) *Circuit {
+	c.measures++
+	return c.add(circuitStep{kind: stepMeasure, token: t, result: c.measures - 1})
+}
+
+// add appends a step, noting the register widths of its tokens
+func (c *Circuit) add(s circuitStep) *Circuit {
+	if c.widths == nil {
+		c.widths = make(map[*RiftToken]int)
+	}
+	for _, t := range []*RiftToken{s.token, s.other} {
+		if _, ok := c.widths[t]; !ok && t != nil {
+			c.widths[t] = registerWidth(t)
+		}
+	}
+	c.steps = append(c.steps, s)
+	return c
+}
+
+// Len returns the number of queued steps
+func (c *Circuit) Len() int {
+	return len(c.steps)
+}
+
+// ============================================================================
+// Execution
+// ============================================================================
+
+// Run executes the circuit, measuring with the package RNG
+func (c *Circuit) Run() error {
+	return c.RunWith(nil)
+}
+
+// RunWith executes the circuit, measuring with the given RNG (nil uses the
+// package RNG; see SetRandSource) for reproducible runs. The error names
+// the failing step; steps before it have been applied and are not undone.
+func (c *Circuit) RunWith(r *rand.Rand) error {
+	c.results = c.results[:0]
+	c.ran = 0
+	for i, s := range c.steps {
+		if err := c.exec(s, r); err != nil {
+			return fmt.Errorf("circuit step %d (%s): %w", i, s, err)
+		}
+		c.ran = i + 1
+	}
+	return nil
+}
+
+// exec validates the step's tokens, applies the step and validates them
+// again
+func (c *Circuit) exec(s circuitStep, r *rand.Rand) error {
+	tokens := []*RiftToken{s.token}
+	if s.kind == stepEntangle {
+		tokens = append(tokens, s.other)
+	}
+	if err := validateAll(tokens); err != nil {
+		return err
+	}
+
+	switch s.kind {
+	case stepGate:
+		if err := s.token.ApplyGate(s.qubit, s.gate); err != nil {
+			return err
+		}
+	case stepEntangle:
+		id := NewEntanglementID()
+		if err := s.token.EntangleWithErr(s.other, id); err != nil {
+			return err
+		}
+		if err := s.other.EntangleWithErr(s.token, id); err != nil {
+			return err
+		}
+	case stepMeasure:
+		state, err := s.token.MeasureWith(r)
+		if err != nil {
+			return err
+		}
+		c.results = append(c.results, state)
+	}
+	return validateAll(tokens)
+}
+
+// validateAll validates each token
+func validateAll(tokens []*RiftToken) error {
+	for _, t := range tokens {
+		if t == nil {
+			return govErr(CodeNilToken, "circuit", "token is nil")
+		}
+		if err := t.ValidateErr(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Results returns the states observed by the measurements of the last Run,
+// in measurement order. After a failed Run it holds the measurements made
+// before the failure.
+func (c *Circuit) Results() []*RiftToken {
+	return append([]*RiftToken(nil), c.results...)
+}
+
+// Completed returns the number of steps the last Run applied
+func (c *Circuit) Completed() int {
+	return c.ran
+}
+
+// ============================================================================
+// OpenQASM Export
+// ============================================================================
+
+// QASM returns the circuit as an OpenQASM 2.0 program. Each token becomes a
+// quantum register tN, numbered in order of first use, as wide as its qubit
+// register was when first queued (and at least wide enough for the qubits
+// the circuit addresses), so export works before or after Run, and
+// each measurement measures its token's whole register into a classical
+// register cN. Named gates export as h, x, y, z and u1; other gates as U
+// with angles equal to the gate up to global phase. Entanglement links have
+// no gate equivalent and export as comments.
+func (c *Circuit) QASM() string {
+	regs := make(map[*RiftToken]int)
+	var order []*RiftToken
+	width := make(map[*RiftToken]int)
+	use := func(t *RiftToken, q int) {
+		if _, ok := regs[t]; !ok {
+			regs[t] = len(order)
+			order = append(order, t)
+			width[t] = max(c.widths[t], 1)
+		}
+		if q+1 > width[t] {
+			width[t] = q + 1
+		}
+	}
+	for _, s := range c.steps {
+		use(s.token, s.qubit)
+		if s.kind == stepEntangle {
+			use(s.other, 0)
+		}
+	}
+
+	var b strings.Builder
+	b.WriteString("OPENQASM 2.0;\ninclude \"qelib1.inc\";\n")
+	for i, t := range order {
+		fmt.Fprintf(&b, "qreg t%d[%d];\n", i, width[t])
+	}
+	for _, s := range c.steps {
+		if s.kind == stepMeasure {
+			fmt.Fprintf(&b, "creg c%d[%d];\n", s.result, width[s.token])
+		}
+	}
+
+	for _, s := range c.steps {
+		reg := regs[s.token]
+		switch s.kind {
+		case stepGate:
+			fmt.Fprintf(&b, "%s t%d[%d];\n", qasmGate(s), reg, s.qubit)
+		case stepEntangle:
+			fmt.Fprintf(&b, "// entangle t%d, t%d\n", reg, regs[s.other])
+		case stepMeasure:
+			fmt.Fprintf(&b, "measure t%d -> c%d;\n", reg, s.result)
+		}
+	}
+	return b.String()
+}
+
+// registerWidth returns the qubits of t's superposition, or 1 for a token
+// that is not a qubit register
+func registerWidth(t *RiftToken) int {
+	n := len(t.SuperposedStates)
+	if n < 2 || n&(n-1) != 0 {
+		return 1
+	}
+	return qubitCount(n)
+}
+
+// qasmGate returns the OpenQASM instruction for a gate step
+func qasmGate(s circuitStep) string {
+	switch s.name {
+	case "h", "x", "y", "z":
+		return s.name
+	case "phase":
+		return fmt.Sprintf("u1(%s)", qasmAngle(s.theta))
+	}
+	switch s.gate {
+	case GateHadamard:
+		return "h"
+	case GatePauliX:
+		return "x"
+	case GatePauliY:
+		return "y"
+	case GatePauliZ:
+		return "z"
+	}
+	theta, phi, lambda := zyzAngles(s.gate)
+	return fmt.Sprintf("U(%s,%s,%s)", qasmAngle(theta), qasmAngle(phi), qasmAngle(lambda))
+}
+
+// zyzAngles returns θ, φ, λ with U(θ,φ,λ) equal to g up to global phase,
+// where U(θ,φ,λ) = [[cos θ/2, -e^{iλ} sin θ/2], [e^{iφ} sin θ/2,
+// e^{i(φ+λ)} cos θ/2]]
+func zyzAngles(g Gate) (theta, phi, lambda float64) {
+	c, s := cmplx.Abs(g[0][0]), cmplx.Abs(g[1][0])
+	theta = 2 * math.Atan2(s, c)
+	switch {
+	case s <= gateEpsilon:
+		// Diagonal: only the relative phase of the diagonal matters
+		lambda = cmplx.Phase(g[1][1]) - cmplx.Phase(g[0][0])
+	case c <= gateEpsilon:
+		// Anti-diagonal: fix the global phase by the lower-left entry
+		lambda = cmplx.Phase(-g[0][1]) - cmplx.Phase(g[1][0])
+	default:
+		alpha := cmplx.Phase(g[0][0])
+		phi = cmplx.Phase(g[1][0]) - alpha
+		lambda = cmplx.Phase(-g[0][1]) - alpha
+	}
+	return theta, wrapAngle(phi), wrapAngle(lambda)
+}
+
+// wrapAngle maps a to (-π, π]
+func wrapAngle(a float64) float64 {
+	a = math.Mod(a, 2*math.Pi)
+	if a > math.Pi {
+		a -= 2 * math.Pi
+	} else if a <= -math.Pi {
+		a += 2 * math.Pi
+	}
+	if math.Abs(a) < gateEpsilon {
+		return 0
+	}
+	return a
+}
+
+// qasmAngle formats an angle with enough digits to round-trip
+func qasmAngle(a float64) string {
+	return fmt.Sprintf("%.17g", a)
+}
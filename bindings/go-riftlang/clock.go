@@ -0,0 +1,196 @@
+// go/target/clock.go
+// Governed Time and Clock Injection - Go Implementation
+
+package rift
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// ============================================================================
+// Clock
+// ============================================================================
+
+// Clock is the source of governed time: TTL expiry, decoherence, rate
+// quotas, lock hold times, match latency and the timestamps of audit
+// events, events and snapshots all read it. SystemClock, the default,
+// is the wall clock; a ManualClock moves only when told to, so tests of
+// time-dependent governance need no sleeps.
+type Clock interface {
+	Now() time.Time
+
+	// NewTicker returns a ticker delivering the clock's time every d, as
+	// time.NewTicker does, for background work such as the TTL reaper
+	NewTicker(d time.Duration) Ticker
+}
+
+// Ticker delivers ticks from a Clock
+type Ticker interface {
+	C() <-chan time.Time
+	Stop()
+}
+
+// SystemClock is the wall clock
+var SystemClock Clock = systemClock{}
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time { return time.Now() }
+
+func (systemClock) NewTicker(d time.Duration) Ticker {
+	return systemTicker{time.NewTicker(d)}
+}
+
+type systemTicker struct {
+	t *time.Ticker
+}
+
+func (t systemTicker) C() <-chan time.Time { return t.t.C }
+func (t systemTicker) Stop()               { t.t.Stop() }
+
+// clockHolder boxes a Clock for atomic.Pointer
+type clockHolder struct {
+	clock Clock
+}
+
+// packageClock holds the clock installed by SetClock; nil is SystemClock
+var packageClock atomic.Pointer[clockHolder]
+
+// SetClock makes c the package's clock; nil restores SystemClock. TTLs
+// already set keep their deadline, now read against c. Pattern engines
+// and engine managers given their own clock (see PatternEngine.SetClock)
+// keep it.
+func SetClock(c Clock) {
+	if c == nil || c == SystemClock {
+		packageClock.Store(nil)
+		return
+	}
+	packageClock.Store(&clockHolder{clock: c})
+}
+
+// CurrentClock returns the clock set by SetClock
+func CurrentClock() Clock {
+	if h := packageClock.Load(); h != nil {
+		return h.clock
+	}
+	return SystemClock
+}
+
+// clockNow returns the package clock's time
+func clockNow() time.Time {
+	if h := packageClock.Load(); h != nil {
+		return h.clock.Now()
+	}
+	return time.Now()
+}
+
+// clockSince returns the package clock's time elapsed since t
+func clockSince(t time.Time) time.Duration {
+	return clockNow().Sub(t)
+}
+
+// ============================================================================
+// ManualClock
+// ============================================================================
+
+// ManualClock is a deterministic Clock for tests and simulations. Its time
+// changes only through Advance and Set, or by the step set with
+// SetAutoAdvance, and its tickers tick as that time passes their periods,
+// never on their own.
+type ManualClock struct {
+	lock    sync.Mutex
+	now     time.Time
+	step    time.Duration
+	tickers map[*manualTicker]struct{}
+}
+
+// NewManualClock returns a clock stopped at start
+func NewManualClock(start time.Time) *ManualClock {
+	return &ManualClock{now: start, tickers: make(map[*manualTicker]struct{})}
+}
+
+// Now returns the clock's time, then advances it by the auto-advance
+// step, if any
+func (c *ManualClock) Now() time.Time {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	t := c.now
+	if c.step > 0 {
+		c.moveTo(t.Add(c.step))
+	}
+	return t
+}
+
+// Advance moves the clock forward by d, ticking the tickers whose periods
+// end on the way
+func (c *ManualClock) Advance(d time.Duration) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.moveTo(c.now.Add(d))
+}
+
+// Set moves the clock to t. Tickers tick if t is later than the clock's
+// time; moving it back does not tick them again for periods already
+// ticked.
+func (c *ManualClock) Set(t time.Time) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.moveTo(t)
+}
+
+// SetAutoAdvance makes every Now call advance the clock by step after
+// reading it, so code timing itself with two readings measures exactly
+// step, as match latency metrics do; 0 turns it off
+func (c *ManualClock) SetAutoAdvance(step time.Duration) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.step = step
+}
+
+// NewTicker returns a ticker ticking each time the clock passes a multiple
+// of d from now. Like a time.Ticker it holds one tick, dropping ticks
+// while its channel is full.
+func (c *ManualClock) NewTicker(d time.Duration) Ticker {
+	if d <= 0 {
+		panic("rift: non-positive interval for ManualClock.NewTicker")
+	}
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	t := &manualTicker{clock: c, c: make(chan time.Time, 1), period: d, next: c.now.Add(d)}
+	c.tickers[t] = struct{}{}
+	return t
+}
+
+// moveTo sets the time and ticks the tickers it passes; c.lock held
+func (c *ManualClock) moveTo(t time.Time) {
+	c.now = t
+	for tk := range c.tickers {
+		if t.Before(tk.next) {
+			continue
+		}
+		select {
+		case tk.c <- t:
+		default:
+		}
+		periods := t.Sub(tk.next)/tk.period + 1
+		tk.next = tk.next.Add(periods * tk.period)
+	}
+}
+
+// manualTicker is a ticker of a ManualClock
+type manualTicker struct {
+	clock  *ManualClock
+	c      chan time.Time
+	period time.Duration
+	next   time.Time // c.lock guards
+}
+
+func (t *manualTicker) C() <-chan time.Time { return t.c }
+
+func (t *manualTicker) Stop() {
+	t.clock.lock.Lock()
+	defer t.clock.lock.Unlock()
+	delete(t.clock.tickers, t)
+}
@@ -0,0 +1,146 @@
+// go/target/clone.go
+// Token Cloning - Go Implementation
+
+package rift
+
+// CloneEntanglement selects what Clone does with a token's entanglement
+// links
+type CloneEntanglement int
+
+const (
+	// CloneSever leaves the clone unentangled. This is the default.
+	CloneSever CloneEntanglement = iota
+
+	// CloneKeep entangles the clone with the original's partners under the
+	// same EntanglementID, linking each partner back to the clone
+	CloneKeep
+)
+
+// CloneOptions configures Clone
+type CloneOptions struct {
+	Entanglement CloneEntanglement
+
+	// Policy validates the clone; nil validates structurally
+	Policy *GovernancePolicy
+}
+
+// ============================================================================
+// Clone
+// ============================================================================
+
+// Clone returns a deep copy of the token: its value, superposed states,
+// amplitudes and phases, and a new root memory span with the same metadata
+// and affinity. Tokens in the value's ArrVal and the superposed states are
+// cloned too; PtrVal is copied as is. A TTL carries over as the same
+// expiry time. Entanglement links are severed or kept as opts say.
+//
+// Validation bits are not copied: the clone starts allocated, gains
+// initialized, superposed and entangled from its copied state, and is
+// governed only if it validates against opts.Policy. It is not locked,
+// persistent or a shadow, and has no hooks. Its source location is
+// Clone's caller. Clone reads the token without taking its lock; hold the
+// lock for a consistent copy.
+//
+// Cloning needs AccessRead. Qubits of an entangled register (BellPair,
+// GHZ) share one state and cannot be cloned. The clone is audited as
+// AuditClone.
+func (t *RiftToken) Clone(opts CloneOptions) (*RiftToken, error) {
+	if err := t.checkAccess("clone", AccessRead); err != nil {
+		return nil, err
+	}
+	if t.Memory != nil && t.Memory.Released() {
+		return nil, govErr(CodeNoMemory, "clone", "memory span released")
+	}
+	if t.Expired() {
+		return nil, govErr(CodeExpired, "clone", "token expired")
+	}
+
+	out, err := t.clone()
+	if err != nil {
+		return nil, err
+	}
+	if at, ok := t.ExpiresAt(); ok {
+		out.SetTTL(at.Sub(clockNow()))
+	}
+
+	detail := "entanglement severed"
+	if opts.Entanglement == CloneKeep && len(t.EntangledWith) > 0 {
+		detail = "entanglement kept"
+		for _, partner := range t.EntangledWith {
+			if err := out.EntangleWithErr(partner, t.EntanglementID); err != nil {
+				return nil, err
+			}
+			if err := partner.EntangleWithErr(out, t.EntanglementID); err != nil {
+				return nil, err
+			}
+		}
+	}
+	auditEmit(AuditClone, out, detail)
+
+	if err := out.ValidateAgainst(opts.Policy); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// clone deep-copies the token without its entanglement links, validating
+// nested clones structurally
+func (t *RiftToken) clone() (*RiftToken, error) {
+	if t.joint != nil {
+		return nil, govErr(CodeNotClonable, "clone", "qubit %d of an entangled register cannot be cloned", t.jointQubit)
+	}
+	src := t.readSource()
+
+	var memory *RiftMemorySpan
+	if m := t.Memory; m != nil {
+		memory = &RiftMemorySpan{
+			Type:       m.Type,
+			Bytes:      m.Bytes,
+			Alignment:  m.Alignment,
+			Open:       m.Open,
+			Direction:  m.Direction,
+			AccessMask: m.Mask(),
+			affinity:   m.affinity,
+		}
+		spanTree.Lock()
+		memory.data = m.contents()
+		spanTree.Unlock()
+	}
+	out := NewRiftToken(src.Type, memory)
+	out.Value = src.Value
+	out.Phase = src.Phase
+	if src.HasBit(TokenInitialized) {
+		out.SetBit(TokenInitialized)
+	}
+
+	if src.Value.ArrVal != nil {
+		out.Value.ArrVal = make([]*RiftToken, len(src.Value.ArrVal))
+		for i, elem := range src.Value.ArrVal {
+			if elem == nil {
+				continue
+			}
+			c, err := elem.clone()
+			if err != nil {
+				return nil, err
+			}
+			c.Validate()
+			out.Value.ArrVal[i] = c
+		}
+	}
+
+	if src.HasBit(TokenSuperposed) {
+		out.SuperposedStates = make([]*RiftToken, len(src.SuperposedStates))
+		for i, state := range src.SuperposedStates {
+			c, err := state.clone()
+			if err != nil {
+				return nil, err
+			}
+			out.SuperposedStates[i] = c
+		}
+		out.SuperpositionCount = src.SuperpositionCount
+		out.Amplitudes = append([]float64(nil), src.Amplitudes...)
+		out.Phases = append([]float64(nil), src.Phases...)
+		out.SetBit(TokenSuperposed)
+	}
+	return out, nil
+}
@@ -0,0 +1,95 @@
+// go/target/cmd/riftbench/main.go
+// riftbench Governance Benchmark CLI - Go Implementation
+//
+// riftbench runs the riftbench suite outside `go test` and prints results in
+// benchmark output format, so runs from two releases compare with benchstat:
+//
+//	riftbench -count 10 > new.txt
+//	riftbench -run 'Match/pairs=10000' -cpuprofile cpu.out
+//
+// CPU profiles carry the rift_bench and rift_op pprof labels; see
+// `go tool pprof -tagfocus rift_op=match cpu.out`.
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"regexp"
+	"runtime"
+	"runtime/pprof"
+
+	"github.com/obinexus/riftlang/bindings/go-riftlang/riftbench"
+)
+
+// Exit codes
+const (
+	exitOK    = 0
+	exitError = 2 // bad usage or I/O failure
+)
+
+func main() {
+	os.Exit(run(os.Args[1:]))
+}
+
+// run parses args and runs the suite, returning the exit code
+func run(args []string) int {
+	var pattern, cpuProfile, memProfile string
+	var count int
+	fl := flag.NewFlagSet("riftbench", flag.ContinueOnError)
+	fl.StringVar(&pattern, "run", "", "run only benchmarks matching `regexp`")
+	fl.IntVar(&count, "count", 1, "run each benchmark `n` times")
+	fl.StringVar(&cpuProfile, "cpuprofile", "", "write a CPU profile to `file`")
+	fl.StringVar(&memProfile, "memprofile", "", "write a heap profile to `file`")
+	if err := fl.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return exitOK
+		}
+		return exitError
+	}
+
+	var filter *regexp.Regexp
+	if pattern != "" {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "riftbench: -run: %v\n", err)
+			return exitError
+		}
+		filter = re
+	}
+
+	if cpuProfile != "" {
+		f, err := os.Create(cpuProfile)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "riftbench: %v\n", err)
+			return exitError
+		}
+		defer f.Close()
+		if err := pprof.StartCPUProfile(f); err != nil {
+			fmt.Fprintf(os.Stderr, "riftbench: %v\n", err)
+			return exitError
+		}
+		defer pprof.StopCPUProfile()
+	}
+
+	fmt.Printf("goos: %s\ngoarch: %s\npkg: github.com/obinexus/riftlang/bindings/go-riftlang/riftbench\n", runtime.GOOS, runtime.GOARCH)
+	for i := 0; i < count; i++ {
+		riftbench.Run(os.Stdout, filter)
+	}
+
+	if memProfile != "" {
+		f, err := os.Create(memProfile)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "riftbench: %v\n", err)
+			return exitError
+		}
+		defer f.Close()
+		runtime.GC()
+		if err := pprof.WriteHeapProfile(f); err != nil {
+			fmt.Fprintf(os.Stderr, "riftbench: %v\n", err)
+			return exitError
+		}
+	}
+	return exitOK
+}
@@ -0,0 +1,277 @@
+// go/target/cmd/riftfmt/main.go
+// riftfmt Governed Source Formatter CLI - Go Implementation
+//
+// riftfmt is gofmt with Rift governance: each file is read, given the
+// transform package's governance rewrites, re-formatted in gofmt style and
+// written back or printed:
+//
+//	riftfmt [flags] [path ...]
+//	riftfmt -w ./src
+//	riftfmt -l -policy governance.rift .
+//	riftfmt < main.go > main.rift.go
+//
+// Without paths it filters standard input to standard output, for editor
+// integration. Directories are walked as the go tool walks them, leaving
+// out _test.go files unless -tests is given; files named explicitly are
+// always processed. Rewriting is idempotent, so formatting governed source
+// again changes nothing.
+//
+// Files that do not parse go through the regex pattern engine instead, as
+// with riftgo, and are written unformatted. The engine holds the default
+// Go patterns, or the -patterns set, plus any patterns in the -policy.
+package main
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"go/format"
+	"io"
+	"os"
+	"path/filepath"
+
+	rift "github.com/obinexus/riftlang/bindings/go-riftlang"
+	"github.com/obinexus/riftlang/bindings/go-riftlang/transform"
+)
+
+// Exit codes
+const (
+	exitOK    = 0
+	exitError = 2 // bad usage, policy, parse or I/O failure
+)
+
+// stdinName labels standard input in -l and -d output
+const stdinName = "<standard input>"
+
+// options holds the parsed command line
+type options struct {
+	list     bool
+	write    bool
+	diff     bool
+	policy   string
+	patterns string
+	mode     string
+	rules    string
+	tests    bool
+
+	t      *transform.Transformer
+	failed bool // an error was reported for some file
+}
+
+func main() {
+	os.Exit(run(os.Args[1:]))
+}
+
+// run parses args and formats the named paths, or stdin, returning the
+// exit code
+func run(args []string) int {
+	var o options
+	fl := flag.NewFlagSet("riftfmt", flag.ContinueOnError)
+	fl.BoolVar(&o.list, "l", false, "list files whose governed formatting differs from the source")
+	fl.BoolVar(&o.write, "w", false, "write the result to the source file instead of stdout")
+	fl.BoolVar(&o.diff, "d", false, "display diffs instead of rewriting files")
+	fl.StringVar(&o.policy, "policy", "", "`file` holding a .rift governance policy")
+	fl.StringVar(&o.patterns, "patterns", "", "`file` holding the .rift pattern set for the regex engine")
+	fl.StringVar(&o.mode, "mode", "ast", "transform `mode`: ast or regex")
+	fl.StringVar(&o.rules, "rules", "var,func,go", "comma-separated AST `rules` to apply")
+	fl.BoolVar(&o.tests, "tests", false, "also format _test.go files found in directories")
+	fl.Usage = func() {
+		fmt.Fprintf(fl.Output(), "usage: riftfmt [flags] [path ...]\n\n")
+		fl.PrintDefaults()
+	}
+	if err := fl.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return exitOK
+		}
+		return exitError
+	}
+
+	var err error
+	if o.t, err = o.transformer(); err != nil {
+		fmt.Fprintf(os.Stderr, "riftfmt: %v\n", err)
+		return exitError
+	}
+
+	if fl.NArg() == 0 {
+		if o.write {
+			fmt.Fprintln(os.Stderr, "riftfmt: cannot use -w with standard input")
+			return exitError
+		}
+		src, err := io.ReadAll(os.Stdin)
+		if err == nil {
+			err = o.process(stdinName, src, false)
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "riftfmt: %v\n", err)
+			return exitError
+		}
+		return exitOK
+	}
+
+	for _, path := range fl.Args() {
+		o.walk(path)
+	}
+	if o.failed {
+		return exitError
+	}
+	return exitOK
+}
+
+// ============================================================================
+// Transformer Setup
+// ============================================================================
+
+// transformer builds the transformer described by the flags. Without a
+// policy or pattern set it uses the transform package defaults.
+func (o *options) transformer() (*transform.Transformer, error) {
+	t := transform.New()
+	var err error
+	switch o.mode {
+	case "ast":
+		t.Mode = transform.ModeAST
+	case "regex":
+		t.Mode = transform.ModeRegex
+	default:
+		return nil, fmt.Errorf("unknown -mode %q", o.mode)
+	}
+	if t.Rules, err = transform.ParseRules(o.rules); err != nil {
+		return nil, err
+	}
+	if o.policy == "" && o.patterns == "" {
+		return t, nil
+	}
+
+	engine := rift.NewPatternEngine("classical")
+	var policy *rift.Policy
+	if o.policy != "" {
+		if policy, err = rift.LoadPolicy(o.policy); err != nil {
+			return nil, err
+		}
+		if policy.Mode == "quantum" {
+			engine = rift.NewPatternEngine("quantum")
+		}
+	}
+	if o.patterns == "" {
+		for _, p := range rift.DefaultGoPatterns {
+			if _, err := engine.AddPairSubstitution(p.Left, p.Right, p.Priority, p.Substitution); err != nil {
+				return nil, err
+			}
+		}
+	} else {
+		set, err := rift.LoadPolicy(o.patterns)
+		if err != nil {
+			return nil, err
+		}
+		if err := transform.AddPatterns(engine, set.Patterns); err != nil {
+			return nil, fmt.Errorf("patterns %s: %w", o.patterns, err)
+		}
+	}
+	if policy != nil {
+		if err := transform.AddPatterns(engine, policy.Patterns); err != nil {
+			return nil, fmt.Errorf("policy %s: %w", o.policy, err)
+		}
+	}
+	t.Engine = engine
+	return t, nil
+}
+
+// ============================================================================
+// Formatting
+// ============================================================================
+
+// walk formats path, or the Go files under it if it is a directory,
+// reporting errors per file and carrying on
+func (o *options) walk(path string) {
+	info, err := os.Stat(path)
+	if err != nil {
+		o.report(err)
+		return
+	}
+	if !info.IsDir() {
+		o.report(o.file(path))
+		return
+	}
+	files, err := transform.SourceFiles(path, o.tests)
+	if err != nil {
+		o.report(err)
+	}
+	for _, rel := range files {
+		o.report(o.file(filepath.Join(path, rel)))
+	}
+}
+
+// report prints err, if any, and marks the run failed
+func (o *options) report(err error) {
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "riftfmt: %v\n", err)
+		o.failed = true
+	}
+}
+
+// file formats one source file
+func (o *options) file(path string) error {
+	src, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	return o.process(path, src, true)
+}
+
+// process formats src, read from name, and performs the selected output
+// action. Without -l, -w or -d the result goes to stdout.
+func (o *options) process(name string, src []byte, isFile bool) error {
+	out, err := o.format(name, src)
+	if err != nil {
+		return err
+	}
+
+	changed := !bytes.Equal(src, out)
+	if o.list && changed {
+		fmt.Println(name)
+	}
+	if o.write && changed && isFile {
+		if err := writeFile(name, out); err != nil {
+			return err
+		}
+	}
+	if o.diff && changed {
+		label := filepath.ToSlash(name)
+		os.Stdout.Write(transform.UnifiedDiff(label+".orig", label, src, out))
+	}
+	if !o.list && !o.write && !o.diff {
+		_, err = os.Stdout.Write(out)
+	}
+	return err
+}
+
+// format applies the governance rewrites to src and formats the result
+func (o *options) format(name string, src []byte) ([]byte, error) {
+	res, err := o.t.Source(name, src)
+	if err != nil {
+		return nil, err
+	}
+	if res.ParseErr != nil {
+		fmt.Fprintf(os.Stderr, "riftfmt: %s: regex fallback: %v\n", name, res.ParseErr)
+		return res.Output, nil
+	}
+	out, err := format.Source(res.Output)
+	if err != nil {
+		if res.Mode == transform.ModeRegex {
+			// Line rewrites need not leave valid Go; keep them unformatted
+			fmt.Fprintf(os.Stderr, "riftfmt: %s: regex output not formatted: %v\n", name, err)
+			return res.Output, nil
+		}
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+	return out, nil
+}
+
+// writeFile replaces path's contents, keeping its permissions
+func writeFile(path string, data []byte) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, info.Mode().Perm())
+}
@@ -0,0 +1,111 @@
+package main
+
+import (
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const testProg = "testdata/prog"
+
+// TestRunProgram formats a copy of a real program with -w: the result must
+// build and print what the original prints, and formatting it again must
+// change nothing
+func TestRunProgram(t *testing.T) {
+	if testing.Short() {
+		t.Skip("builds programs")
+	}
+	goTool, err := exec.LookPath("go")
+	if err != nil {
+		t.Skip("go tool not found")
+	}
+
+	dir := tempDir(t)
+	if err := os.CopyFS(dir, os.DirFS(testProg)); err != nil {
+		t.Fatal(err)
+	}
+	listed, code := captureStdout(t, func() int { return run([]string{"-l", dir}) })
+	if code != exitOK || strings.TrimSpace(listed) != filepath.Join(dir, "main.go") {
+		t.Fatalf("riftfmt -l exited %d listing %q, want main.go listed", code, listed)
+	}
+	if code := run([]string{"-w", dir}); code != exitOK {
+		t.Fatalf("riftfmt -w exited %d", code)
+	}
+
+	want := goRun(t, goTool, testProg)
+	if got := goRun(t, goTool, dir); got != want {
+		t.Errorf("formatted program printed\n%s\nthe original\n%s", got, want)
+	}
+
+	listed, code = captureStdout(t, func() int { return run([]string{"-l", dir}) })
+	if code != exitOK || listed != "" {
+		t.Errorf("riftfmt -l on formatted output exited %d listing %q, want nothing", code, listed)
+	}
+}
+
+func TestRunUsage(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+	}{
+		{"bad mode", []string{"-mode", "x", testProg}},
+		{"bad rules", []string{"-rules", "x", testProg}},
+		{"missing policy", []string{"-policy", "testdata/none.rift", testProg}},
+		{"missing path", []string{"-l", "testdata/none"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if code := run(tt.args); code != exitError {
+				t.Errorf("riftfmt %v exited %d, want %d", tt.args, code, exitError)
+			}
+		})
+	}
+}
+
+// tempDir returns a directory inside this module, so programs written to
+// it can import the rift package
+func tempDir(t *testing.T) string {
+	t.Helper()
+	dir, err := os.MkdirTemp("testdata", "run-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.RemoveAll(dir) })
+	return dir
+}
+
+// goRun builds and runs the main package in dir, returning what it prints
+func goRun(t *testing.T, goTool, dir string) string {
+	t.Helper()
+	cmd := exec.Command(goTool, "run", ".")
+	cmd.Dir = dir
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("go run %s: %v\n%s", dir, err, out)
+	}
+	return string(out)
+}
+
+// captureStdout calls fn, returning what it wrote to os.Stdout and its
+// result
+func captureStdout(t *testing.T, fn func() int) (string, int) {
+	t.Helper()
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	stdout := os.Stdout
+	os.Stdout = w
+	done := make(chan string)
+	go func() {
+		b, _ := io.ReadAll(r)
+		done <- string(b)
+	}()
+	code := fn()
+	os.Stdout = stdout
+	w.Close()
+	return <-done, code
+}
@@ -0,0 +1,33 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+type result struct {
+	id   int
+	text string
+}
+
+var prefix string = "job"
+
+func render(out chan<- result, id int, words ...string) {
+	out <- result{id, prefix + ":" + strings.Join(words, " ")}
+}
+
+func main() {
+	var count int = 3
+	out := make(chan result, count)
+	word := "first"
+	for i := 0; i < count; i++ {
+		go render(out, i, word, fmt.Sprint(i))
+		word = "next"
+	}
+	lines := make([]string, count)
+	for range count {
+		r := <-out
+		lines[r.id] = r.text
+	}
+	fmt.Println(strings.Join(lines, "\n"))
+}
@@ -0,0 +1,390 @@
+// go/target/cmd/riftgen/generate.go
+// Go Code Emission for .rift Policies - Go Implementation
+
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	rift "github.com/obinexus/riftlang/bindings/go-riftlang"
+)
+
+// tokenKind describes how to construct a token type
+type tokenKind struct {
+	typ      int
+	spanType int
+	param    string // constructor parameter type
+	field    string // RiftTokenValue field receiving the parameter
+}
+
+// tokenKinds lists the token types a policy's type blocks can describe
+var tokenKinds = []tokenKind{
+	{rift.TokenGoInt, rift.SpanFixed, "int64", "IntVal"},
+	{rift.TokenGoFloat, rift.SpanFixed, "float64", "FloatVal"},
+	{rift.TokenGoString, rift.SpanFixed, "string", "StringVal"},
+	{rift.TokenGoSlice, rift.SpanRow, "interface{}", "PtrVal"},
+	{rift.TokenGoMap, rift.SpanRow, "interface{}", "PtrVal"},
+	{rift.TokenGoChan, rift.SpanRow, "interface{}", "PtrVal"},
+	{rift.TokenQGoInt, rift.SpanSuperposed, "int64", "IntVal"},
+	{rift.TokenQGoChan, rift.SpanSuperposed, "interface{}", "PtrVal"},
+	{rift.TokenGoPtr, rift.SpanFixed, "interface{}", "PtrVal"},
+}
+
+// spanTypeNames maps span type constants to their Go identifiers
+var spanTypeNames = map[int]string{
+	rift.SpanFixed:       "rift.SpanFixed",
+	rift.SpanRow:         "rift.SpanRow",
+	rift.SpanContinuous:  "rift.SpanContinuous",
+	rift.SpanSuperposed:  "rift.SpanSuperposed",
+	rift.SpanEntangled:   "rift.SpanEntangled",
+	rift.SpanDistributed: "rift.SpanDistributed",
+}
+
+// defaultTypeBytes is the span size of a token type without a bit_width
+const defaultTypeBytes = 64
+
+// ============================================================================
+// Generator
+// ============================================================================
+
+// generator accumulates the generated source
+type generator struct {
+	policy *rift.Policy
+	buf    bytes.Buffer
+}
+
+func (g *generator) printf(format string, args ...interface{}) {
+	fmt.Fprintf(&g.buf, format, args...)
+}
+
+// generate returns the gofmt-ed Go bindings for policy
+func generate(policy *rift.Policy, pkg, source string) ([]byte, error) {
+	g := &generator{policy: policy}
+	if policy.Alignment == 0 || policy.Alignment&(policy.Alignment-1) != 0 {
+		return nil, fmt.Errorf("policy alignment %d is not a power of 2", policy.Alignment)
+	}
+
+	g.printf("// Code generated by riftgen from %s. DO NOT EDIT.\n\n", source)
+	g.printf("package %s\n\n", pkg)
+	g.printf("import (\n\"fmt\"\n")
+	if policy.MaxLockHold > 0 {
+		g.printf("\"time\"\n")
+	}
+	g.printf("\nrift %q\n)\n\n", "github.com/obinexus/riftlang/bindings/go-riftlang")
+
+	g.settings()
+	g.roles()
+	g.spans()
+	if err := g.types(); err != nil {
+		return nil, err
+	}
+	g.validation()
+	g.patterns()
+
+	code, err := format.Source(g.buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("generated code does not parse: %v", err)
+	}
+	return code, nil
+}
+
+// settings emits the policy-wide constants
+func (g *generator) settings() {
+	p := g.policy
+	g.printf("// Policy settings\nconst (\n")
+	g.printf("Mode = %q\n", p.Mode)
+	g.printf("AlignmentKind = %q\n", p.AlignmentKind)
+	g.printf("Alignment uint32 = %d\n", p.Alignment)
+	g.printf("AccessMask = %s\n", maskExpr(p.AccessMask))
+	g.printf("ValidationThreshold float64 = %s\n", floatLit(p.ValidationThreshold))
+	g.printf("EntropyThreshold float64 = %s\n", floatLit(p.EntropyThreshold))
+	g.printf("DecoherenceThreshold float64 = %s\n", floatLit(p.DecoherenceThreshold))
+	if p.MaxLockHold > 0 {
+		g.printf("MaxLockHold = time.Duration(%d) // %v\n", int64(p.MaxLockHold), p.MaxLockHold)
+	}
+	if p.MaxConcurrency > 0 {
+		g.printf("MaxConcurrency = %d\n", p.MaxConcurrency)
+	}
+	if p.OnViolation != "" {
+		g.printf("OnViolation = %q\n", p.OnViolation)
+	}
+	if p.Amplitudes != "" {
+		g.printf("Amplitudes = %q\n", p.Amplitudes)
+	}
+	g.printf(")\n\n")
+}
+
+// roles emits a mask constant per role block
+func (g *generator) roles() {
+	if len(g.policy.Roles) == 0 {
+		return
+	}
+	names := make([]string, 0, len(g.policy.Roles))
+	for name := range g.policy.Roles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	g.printf("// Role permission masks\nconst (\n")
+	for _, name := range names {
+		g.printf("Role%s = %s\n", goIdent(name), maskExpr(g.policy.Roles[name]))
+	}
+	g.printf(")\n\n")
+}
+
+// spans emits a constructor per `align span<name>` block
+func (g *generator) spans() {
+	names := make([]string, 0, len(g.policy.Spans))
+	for name := range g.policy.Spans {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		def := g.policy.Spans[name]
+		dir := "left->right"
+		if def.Direction {
+			dir = "right->left"
+		}
+		g.printf("// New%sSpan creates a span<%s>: %d bytes, %s, %s\n", goIdent(name), name, def.Bytes, spanTypeNames[def.Type], dir)
+		g.printf("func New%sSpan() *rift.RiftMemorySpan {\n", goIdent(name))
+		g.printf("span := rift.NewRiftMemorySpan(%s, %d)\n", spanTypeNames[def.Type], def.Bytes)
+		g.printf("span.Alignment = %d\n", g.alignment(def.Type, 0))
+		g.printf("span.Direction = %t\n", def.Direction)
+		g.printf("span.Open = %t\n", def.Open)
+		g.printf("span.AccessMask &= AccessMask\n")
+		g.printf("return span\n}\n\n")
+	}
+}
+
+// types emits a constructor per type block naming a token type
+func (g *generator) types() error {
+	g.printf("// typeAlignment holds each type's aligned(n) rule\n")
+	g.printf("var typeAlignment = map[int]uint32{\n")
+	aligns := make(map[int]uint32)
+	for _, k := range tokenKinds {
+		fields, ok := g.policy.Types[rift.TokenTypeName(k.typ)]
+		if !ok {
+			continue
+		}
+		if n, ok := alignedRule(fields); ok {
+			aligns[k.typ] = n
+			g.printf("rift.Token%s: %d,\n", rift.TokenTypeName(k.typ), n)
+		}
+	}
+	g.printf("}\n\n")
+
+	for _, k := range tokenKinds {
+		name := rift.TokenTypeName(k.typ)
+		fields, ok := g.policy.Types[name]
+		if !ok {
+			continue
+		}
+		typeAlign := aligns[k.typ]
+		if typeAlign&(typeAlign-1) != 0 {
+			return fmt.Errorf("type %s: aligned(%d) is not a power of 2", name, typeAlign)
+		}
+		align := g.alignment(k.spanType, typeAlign)
+		if typeAlign > 0 && align%typeAlign != 0 {
+			return fmt.Errorf("type %s: aligned(%d) conflicts with %s(%d)", name, typeAlign, g.policy.AlignmentKind, g.policy.Alignment)
+		}
+
+		bytes := uint64(defaultTypeBytes)
+		if v := fields["bit_width"]; v != nil {
+			if bits, err := strconv.ParseUint(v.Scalar, 10, 64); err == nil && bits >= 8 {
+				bytes = bits / 8
+			}
+		}
+
+		g.printf("// New%s creates a governed %s token holding v\n", name, name)
+		g.printf("func New%s(v %s) (*rift.RiftToken, error) {\n", name, k.param)
+		g.printf("span := rift.NewRiftMemorySpan(%s, %d)\n", spanTypeNames[k.spanType], bytes)
+		g.printf("span.Alignment = %d\n", align)
+		g.printf("span.AccessMask &= AccessMask\n")
+		g.printf("token := rift.NewRiftToken(rift.Token%s, span)\n", name)
+		g.printf("if err := token.SetValue(rift.RiftTokenValue{%s: v}); err != nil {\nreturn nil, err\n}\n", k.field)
+		g.printf("if err := Validate(token); err != nil {\nreturn nil, err\n}\n")
+		g.printf("if err := token.ValidateErr(); err != nil {\nreturn nil, err\n}\n")
+		g.printf("return token, nil\n}\n\n")
+	}
+	return nil
+}
+
+// validation emits Validate and ValidateSpan, the compiled forms of
+// Policy.ValidateToken and Policy.ValidateSpan
+func (g *generator) validation() {
+	g.printf(`// ValidateSpan checks a memory span against the policy's alignment and
+// access rules
+func ValidateSpan(s *rift.RiftMemorySpan) error {
+	if s == nil {
+		return fmt.Errorf("memory span is nil")
+	}
+	if !s.ValidateAlignment() {
+		return fmt.Errorf("span alignment %%d is not a power of 2", s.Alignment)
+	}
+`)
+	switch g.policy.AlignmentKind {
+	case "fixed":
+		g.printf(`	if s.Alignment != Alignment {
+		return fmt.Errorf("span alignment %%d violates fixed(%%d)", s.Alignment, Alignment)
+	}
+`)
+	case "dynamic":
+		g.printf(`	if s.Alignment < Alignment {
+		return fmt.Errorf("span alignment %%d below dynamic(%%d)", s.Alignment, Alignment)
+	}
+`)
+	}
+	g.printf(`	if s.AccessMask&^AccessMask != 0 {
+		return fmt.Errorf("span access mask 0x%%02x exceeds policy mask 0x%%02x", s.AccessMask, AccessMask)
+	}
+	return nil
+}
+
+// Validate checks a token and its memory span against the policy
+func Validate(t *rift.RiftToken) error {
+	if !t.HasBit(rift.TokenAllocated) {
+		return fmt.Errorf("token not allocated")
+	}
+	if err := ValidateSpan(t.Memory); err != nil {
+		return err
+	}
+	if n := typeAlignment[t.Type]; n > 0 && t.Memory.Alignment%%n != 0 {
+		return fmt.Errorf("token alignment %%d violates aligned(%%d)", t.Memory.Alignment, n)
+	}
+	return nil
+}
+
+`)
+}
+
+// patterns emits the policy's pattern pairs and an engine constructor
+func (g *generator) patterns() {
+	if len(g.policy.Patterns) == 0 {
+		return
+	}
+	g.printf("// Patterns are the policy's pattern declarations\n")
+	g.printf("var Patterns = []rift.PolicyPattern{\n")
+	for _, p := range g.policy.Patterns {
+		g.printf("{Left: %s, Right: %s, Priority: %d, Governed: %t},\n", strconv.Quote(p.Left), strconv.Quote(p.Right), p.Priority, p.Governed)
+	}
+	g.printf("}\n\n")
+
+	engineMode := "classical"
+	if g.policy.Mode == "quantum" {
+		engineMode = "quantum"
+	}
+	g.printf(`// NewEngine creates a pattern engine holding Patterns
+func NewEngine() (*rift.PatternEngine, error) {
+	engine := rift.NewPatternEngine(%q)
+	for _, p := range Patterns {
+		if _, err := engine.AddPairSubstitution(p.Left, p.Right, p.Priority, rift.SubstituteIndexed); err != nil {
+			return nil, err
+		}
+	}
+	return engine, nil
+}
+`, engineMode)
+}
+
+// ============================================================================
+// Helpers
+// ============================================================================
+
+// alignment returns the span alignment satisfying the policy for a span
+// type and a type's aligned(n) rule: the policy alignment when fixed,
+// otherwise the largest of the span type's default, the policy minimum and
+// the type rule
+func (g *generator) alignment(spanType int, typeAlign uint32) uint32 {
+	if g.policy.AlignmentKind == "fixed" {
+		return g.policy.Alignment
+	}
+	align := rift.NewRiftMemorySpan(spanType, 0).Alignment
+	if g.policy.AlignmentKind == "dynamic" && align < g.policy.Alignment {
+		align = g.policy.Alignment
+	}
+	if align < typeAlign {
+		align = typeAlign
+	}
+	return align
+}
+
+// alignedRule returns n from a type's `memory: aligned(n)` field
+func alignedRule(fields map[string]*rift.PolicyValue) (uint32, bool) {
+	mem := fields["memory"]
+	if mem == nil || !strings.HasPrefix(mem.Scalar, "aligned(") || !strings.HasSuffix(mem.Scalar, ")") {
+		return 0, false
+	}
+	n, err := strconv.ParseUint(strings.TrimSpace(mem.Scalar[len("aligned("):len(mem.Scalar)-1]), 10, 32)
+	if err != nil || n == 0 {
+		return 0, false
+	}
+	return uint32(n), true
+}
+
+// maskExpr renders an access mask as an expression over rift.Access*
+func maskExpr(mask uint32) string {
+	if mask == 0 {
+		return "uint32(0)"
+	}
+	names := []struct {
+		bit  uint32
+		name string
+	}{
+		{rift.AccessCreate, "rift.AccessCreate"},
+		{rift.AccessRead, "rift.AccessRead"},
+		{rift.AccessUpdate, "rift.AccessUpdate"},
+		{rift.AccessDelete, "rift.AccessDelete"},
+		{rift.AccessSuperpose, "rift.AccessSuperpose"},
+		{rift.AccessEntangle, "rift.AccessEntangle"},
+	}
+	var parts []string
+	for _, n := range names {
+		if mask&n.bit != 0 {
+			parts = append(parts, n.name)
+			mask &^= n.bit
+		}
+	}
+	if mask != 0 {
+		parts = append(parts, fmt.Sprintf("0x%02x", mask))
+	}
+	return strings.Join(parts, " | ")
+}
+
+// floatLit renders f as a Go float literal
+func floatLit(f float64) string {
+	s := strconv.FormatFloat(f, 'g', -1, 64)
+	if !strings.ContainsAny(s, ".eEn") {
+		s += ".0"
+	}
+	return s
+}
+
+// goIdent converts a .rift name (span<fixed>, go_runtime) into an exported
+// Go identifier
+func goIdent(name string) string {
+	var sb strings.Builder
+	upper := true
+	for _, r := range name {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			upper = true
+			continue
+		}
+		if upper {
+			r = unicode.ToUpper(r)
+			upper = false
+		}
+		sb.WriteRune(r)
+	}
+	id := sb.String()
+	if id == "" || unicode.IsDigit(rune(id[0])) {
+		id = "X" + id
+	}
+	return id
+}
@@ -0,0 +1,104 @@
+// go/target/cmd/riftgen/main.go
+// riftgen .rift Policy Compiler CLI - Go Implementation
+//
+// riftgen compiles a .rift governance policy into Go bindings: policy
+// constants, role masks, span presets, typed token constructors, validation
+// functions and a pattern engine constructor, so the policy is checked at
+// compile time instead of parsed at run time:
+//
+//	riftgen -pkg policy -o policy_gen.go governance.rift
+//	riftgen -pkg policy -check -o policy_gen.go governance.rift
+//
+// Without -o the code is written to standard output. -check regenerates in
+// memory and exits 1 if the file under -o is missing or stale. A
+// go:generate directive keeps bindings in step with the policy:
+//
+//	//go:generate riftgen -pkg policy -o policy_gen.go governance.rift
+package main
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	rift "github.com/obinexus/riftlang/bindings/go-riftlang"
+)
+
+// Exit codes
+const (
+	exitOK    = 0
+	exitCheck = 1 // -check found stale output
+	exitError = 2 // bad usage, policy, or I/O failure
+)
+
+func main() {
+	os.Exit(run(os.Args[1:]))
+}
+
+// run parses args and generates the bindings, returning the exit code
+func run(args []string) int {
+	var pkg, out string
+	var check bool
+	fl := flag.NewFlagSet("riftgen", flag.ContinueOnError)
+	fl.StringVar(&pkg, "pkg", "policy", "Go `package` name of the generated file")
+	fl.StringVar(&out, "o", "", "output `file`; standard output if empty")
+	fl.BoolVar(&check, "check", false, "exit 1 if the file under -o is not up to date")
+	fl.Usage = func() {
+		fmt.Fprintf(fl.Output(), "usage: riftgen [flags] policy.rift\n\n")
+		fl.PrintDefaults()
+	}
+	if err := fl.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return exitOK
+		}
+		return exitError
+	}
+	if fl.NArg() != 1 {
+		fl.Usage()
+		return exitError
+	}
+	if check && out == "" {
+		fmt.Fprintf(os.Stderr, "riftgen: -check requires -o\n")
+		return exitError
+	}
+
+	path := fl.Arg(0)
+	policy, err := rift.LoadPolicy(path)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "riftgen: %v\n", err)
+		return exitError
+	}
+	code, err := generate(policy, pkg, filepath.Base(path))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "riftgen: %s: %v\n", path, err)
+		return exitError
+	}
+
+	switch {
+	case check:
+		have, err := os.ReadFile(out)
+		switch {
+		case errors.Is(err, fs.ErrNotExist):
+			fmt.Printf("%s: missing\n", out)
+			return exitCheck
+		case err != nil:
+			fmt.Fprintf(os.Stderr, "riftgen: %v\n", err)
+			return exitError
+		case !bytes.Equal(have, code):
+			fmt.Printf("%s: out of date\n", out)
+			return exitCheck
+		}
+	case out == "":
+		os.Stdout.Write(code)
+	default:
+		if err := os.WriteFile(out, code, 0o644); err != nil {
+			fmt.Fprintf(os.Stderr, "riftgen: %v\n", err)
+			return exitError
+		}
+	}
+	return exitOK
+}
@@ -0,0 +1,247 @@
+// go/target/cmd/riftgo/main.go
+// riftgo Source Tree Transformer CLI - Go Implementation
+//
+// riftgo rewrites a tree of Go source under a .rift governance policy using
+// the transform package:
+//
+//	riftgo -policy governance.rift [-patterns patterns.rift] -o out ./src
+//	riftgo -policy governance.rift -w ./src
+//	riftgo -policy governance.rift -dry-run ./src
+//	riftgo -policy governance.rift -check -o out ./src
+//
+// -dry-run prints a unified diff of every file the transformer would change
+// and writes nothing. -check regenerates the tree in memory and exits 1 if
+// the files under -o are missing or stale, for use as a CI gate.
+//
+// The pattern set is a .rift file of `pattern "left" -> "right"` blocks that
+// feeds the regex engine used for ModeRegex and for files that fail to parse.
+// Without one the default Go patterns are used; patterns declared in the
+// policy itself are always added.
+package main
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	rift "github.com/obinexus/riftlang/bindings/go-riftlang"
+	"github.com/obinexus/riftlang/bindings/go-riftlang/transform"
+)
+
+// Exit codes
+const (
+	exitOK    = 0
+	exitCheck = 1 // -check found stale output
+	exitError = 2 // bad usage, policy, or I/O failure
+)
+
+// options holds the parsed command line
+type options struct {
+	policy   string
+	patterns string
+	out      string
+	write    bool
+	dryRun   bool
+	check    bool
+	mode     string
+	rules    string
+	tests    bool
+	src      string
+}
+
+func main() {
+	os.Exit(run(os.Args[1:]))
+}
+
+// run parses args and executes the requested action, returning the exit code
+func run(args []string) int {
+	var o options
+	fl := flag.NewFlagSet("riftgo", flag.ContinueOnError)
+	fl.StringVar(&o.policy, "policy", "", "`file` holding the .rift governance policy (required)")
+	fl.StringVar(&o.patterns, "patterns", "", "`file` holding the .rift pattern set for the regex engine")
+	fl.StringVar(&o.out, "o", "", "output `dir` for the transformed tree")
+	fl.BoolVar(&o.write, "w", false, "rewrite source files in place")
+	fl.BoolVar(&o.dryRun, "dry-run", false, "print a unified diff instead of writing")
+	fl.BoolVar(&o.check, "check", false, "exit 1 if the tree under -o is not up to date")
+	fl.StringVar(&o.mode, "mode", "ast", "transform `mode`: ast or regex")
+	fl.StringVar(&o.rules, "rules", "var,func,go", "comma-separated AST `rules` to apply")
+	fl.BoolVar(&o.tests, "tests", false, "also transform _test.go files")
+	fl.Usage = func() {
+		fmt.Fprintf(fl.Output(), "usage: riftgo -policy file.rift [flags] dir\n\n")
+		fl.PrintDefaults()
+	}
+	if err := fl.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return exitOK
+		}
+		return exitError
+	}
+	if fl.NArg() != 1 {
+		fl.Usage()
+		return exitError
+	}
+	o.src = fl.Arg(0)
+
+	if err := o.validate(); err != nil {
+		fmt.Fprintf(os.Stderr, "riftgo: %v\n", err)
+		return exitError
+	}
+	code, err := o.execute()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "riftgo: %v\n", err)
+		return exitError
+	}
+	return code
+}
+
+// validate checks that the flags name exactly one action
+func (o *options) validate() error {
+	if o.policy == "" {
+		return fmt.Errorf("-policy is required")
+	}
+	switch {
+	case o.dryRun && o.check:
+		return fmt.Errorf("-dry-run and -check are mutually exclusive")
+	case o.write && o.out != "":
+		return fmt.Errorf("-w and -o are mutually exclusive")
+	case o.check && o.out == "":
+		return fmt.Errorf("-check requires -o")
+	case !o.dryRun && !o.check && !o.write && o.out == "":
+		return fmt.Errorf("one of -o, -w, -dry-run or -check is required")
+	}
+	return nil
+}
+
+// ============================================================================
+// Transformer Setup
+// ============================================================================
+
+// transformer builds the transformer described by the policy and flags
+func (o *options) transformer() (*transform.Transformer, error) {
+	policy, err := rift.LoadPolicy(o.policy)
+	if err != nil {
+		return nil, err
+	}
+
+	t := transform.New()
+	switch o.mode {
+	case "ast":
+		t.Mode = transform.ModeAST
+	case "regex":
+		t.Mode = transform.ModeRegex
+	default:
+		return nil, fmt.Errorf("unknown -mode %q", o.mode)
+	}
+	if t.Rules, err = transform.ParseRules(o.rules); err != nil {
+		return nil, err
+	}
+
+	engineMode := "classical"
+	if policy.Mode == "quantum" {
+		engineMode = "quantum"
+	}
+	engine := rift.NewPatternEngine(engineMode)
+	if o.patterns == "" {
+		for _, p := range rift.DefaultGoPatterns {
+			if _, err := engine.AddPairSubstitution(p.Left, p.Right, p.Priority, p.Substitution); err != nil {
+				return nil, err
+			}
+		}
+	} else {
+		set, err := rift.LoadPolicy(o.patterns)
+		if err != nil {
+			return nil, err
+		}
+		if err := transform.AddPatterns(engine, set.Patterns); err != nil {
+			return nil, fmt.Errorf("patterns %s: %w", o.patterns, err)
+		}
+	}
+	if err := transform.AddPatterns(engine, policy.Patterns); err != nil {
+		return nil, fmt.Errorf("policy %s: %w", o.policy, err)
+	}
+	t.Engine = engine
+	return t, nil
+}
+
+// ============================================================================
+// Tree Walk
+// ============================================================================
+
+// execute transforms every source file and performs the selected action
+func (o *options) execute() (int, error) {
+	t, err := o.transformer()
+	if err != nil {
+		return exitError, err
+	}
+	files, err := transform.SourceFiles(o.src, o.tests)
+	if err != nil {
+		return exitError, err
+	}
+
+	stale := 0
+	for _, rel := range files {
+		src, err := os.ReadFile(filepath.Join(o.src, rel))
+		if err != nil {
+			return exitError, err
+		}
+		res, err := t.Source(rel, src)
+		if err != nil {
+			return exitError, err
+		}
+		if res.ParseErr != nil {
+			fmt.Fprintf(os.Stderr, "riftgo: %s: regex fallback: %v\n", rel, res.ParseErr)
+		}
+
+		switch {
+		case o.dryRun:
+			if !res.Unchanged {
+				os.Stdout.Write(transform.UnifiedDiff(filepath.ToSlash(filepath.Join("a", rel)),
+					filepath.ToSlash(filepath.Join("b", rel)), src, res.Output))
+			}
+
+		case o.check:
+			dst := filepath.Join(o.out, rel)
+			have, err := os.ReadFile(dst)
+			switch {
+			case errors.Is(err, fs.ErrNotExist):
+				fmt.Printf("%s: missing\n", dst)
+				stale++
+			case err != nil:
+				return exitError, err
+			case !bytes.Equal(have, res.Output):
+				fmt.Printf("%s: out of date\n", dst)
+				stale++
+			}
+
+		case o.write:
+			if !res.Unchanged {
+				if err := writeFile(filepath.Join(o.src, rel), res.Output); err != nil {
+					return exitError, err
+				}
+			}
+
+		default:
+			if err := writeFile(filepath.Join(o.out, rel), res.Output); err != nil {
+				return exitError, err
+			}
+		}
+	}
+
+	if stale > 0 {
+		fmt.Fprintf(os.Stderr, "riftgo: %d of %d files need regenerating\n", stale, len(files))
+		return exitCheck, nil
+	}
+	return exitOK, nil
+}
+
+// writeFile writes data to path, creating parent directories
+func writeFile(path string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o644)
+}
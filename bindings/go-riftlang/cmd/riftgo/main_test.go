@@ -0,0 +1,128 @@
+package main
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+const (
+	testPolicy = "testdata/governance.rift"
+	testProg   = "testdata/prog"
+)
+
+// TestRunProgram rewrites a real program with riftgo: the output must build
+// and print what the original prints, and -check must accept it
+func TestRunProgram(t *testing.T) {
+	if testing.Short() {
+		t.Skip("builds programs")
+	}
+	goTool, err := exec.LookPath("go")
+	if err != nil {
+		t.Skip("go tool not found")
+	}
+
+	out := tempDir(t)
+	if code := run([]string{"-policy", testPolicy, "-o", out, testProg}); code != exitOK {
+		t.Fatalf("riftgo -o exited %d", code)
+	}
+	for _, rel := range []string{"main.go", "report.go"} {
+		src, err := os.ReadFile(filepath.Join(testProg, rel))
+		if err != nil {
+			t.Fatal(err)
+		}
+		dst, err := os.ReadFile(filepath.Join(out, rel))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if rel == "main.go" && string(dst) == string(src) {
+			t.Errorf("%s not rewritten", rel)
+		}
+	}
+
+	want := goRun(t, goTool, testProg)
+	if got := goRun(t, goTool, out); got != want {
+		t.Errorf("rewritten program printed\n%s\nthe original\n%s", got, want)
+	}
+
+	if code := run([]string{"-policy", testPolicy, "-check", "-o", out, testProg}); code != exitOK {
+		t.Errorf("riftgo -check on fresh output exited %d", code)
+	}
+	if err := os.Remove(filepath.Join(out, "report.go")); err != nil {
+		t.Fatal(err)
+	}
+	if code := run([]string{"-policy", testPolicy, "-check", "-o", out, testProg}); code != exitCheck {
+		t.Errorf("riftgo -check on stale output exited %d, want %d", code, exitCheck)
+	}
+}
+
+// TestRunWriteInPlace rewrites a copy of the program with -w; a second run
+// must leave it unchanged
+func TestRunWriteInPlace(t *testing.T) {
+	dir := tempDir(t)
+	if err := os.CopyFS(dir, os.DirFS(testProg)); err != nil {
+		t.Fatal(err)
+	}
+	if code := run([]string{"-policy", testPolicy, "-w", dir}); code != exitOK {
+		t.Fatalf("riftgo -w exited %d", code)
+	}
+	first, err := os.ReadFile(filepath.Join(dir, "main.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if code := run([]string{"-policy", testPolicy, "-w", dir}); code != exitOK {
+		t.Fatalf("second riftgo -w exited %d", code)
+	}
+	second, err := os.ReadFile(filepath.Join(dir, "main.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(first) != string(second) {
+		t.Errorf("rewriting the output again changed it:\n%s", second)
+	}
+}
+
+func TestRunUsage(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+	}{
+		{"no policy", []string{"-o", "out", testProg}},
+		{"no action", []string{"-policy", testPolicy, testProg}},
+		{"check without -o", []string{"-policy", testPolicy, "-check", testProg}},
+		{"bad mode", []string{"-policy", testPolicy, "-mode", "x", "-dry-run", testProg}},
+		{"missing policy", []string{"-policy", "testdata/none.rift", "-dry-run", testProg}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if code := run(tt.args); code != exitError {
+				t.Errorf("riftgo %v exited %d, want %d", tt.args, code, exitError)
+			}
+		})
+	}
+}
+
+// tempDir returns a directory inside this module, so programs written to
+// it can import the rift package
+func tempDir(t *testing.T) string {
+	t.Helper()
+	dir, err := os.MkdirTemp("testdata", "run-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.RemoveAll(dir) })
+	return dir
+}
+
+// goRun builds and runs the main package in dir, returning what it prints
+func goRun(t *testing.T, goTool, dir string) string {
+	t.Helper()
+	cmd := exec.Command(goTool, "run", ".")
+	cmd.Dir = dir
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("go run %s: %v\n%s", dir, err, out)
+	}
+	return string(out)
+}
@@ -0,0 +1,42 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+)
+
+const workers = 3
+
+var (
+	greeting string  = "hello"
+	scale    float64 = 1.5
+	limits           = []int{1, 2, 3}
+)
+
+func main() {
+	var total int = 10
+	var names []string
+	out := make(chan string, workers*2)
+	var wg sync.WaitGroup
+
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go report(&wg, out, i, scale)
+	}
+	n := total
+	wg.Add(1)
+	go report(&wg, out, n, 2)
+	n = -1
+	wg.Wait()
+	close(out)
+
+	for s := range out {
+		names = append(names, s)
+	}
+	sort.Strings(names)
+	fmt.Println(greeting, total, n, limits)
+	for _, s := range names {
+		fmt.Println(s)
+	}
+}
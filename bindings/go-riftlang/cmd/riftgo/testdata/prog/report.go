@@ -0,0 +1,11 @@
+package main
+
+import (
+	"fmt"
+	"sync"
+)
+
+func report(wg *sync.WaitGroup, out chan<- string, n int, f float64) {
+	defer wg.Done()
+	out <- fmt.Sprintf("worker %d: %.1f", n, float64(n)*f)
+}
@@ -0,0 +1,131 @@
+// go/target/cmd/riftlint/main.go
+// riftlint Policy and Pattern Linter CLI - Go Implementation
+//
+// riftlint statically checks .rift policy and pattern files, and pattern
+// engine exports, for CI:
+//
+//	riftlint [flags] path ...
+//	riftlint governance.rift
+//	riftlint -json -strict ./policies
+//
+// Directories are walked as the go tool walks them, checking the .rift
+// files and engine exports in them; files named explicitly are always
+// checked, as exports if they hold a JSON object. Findings are printed one
+// per line as file:line: severity: message (check), or with -json as a
+// single document:
+//
+//	{"files": 2, "errors": 1, "warnings": 0, "diagnostics": [...]}
+//
+// riftlint exits 1 if it found errors, or warnings with -strict, and 2 if
+// it could not run or read a file. See package riftlint for the checks.
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/obinexus/riftlang/bindings/go-riftlang/riftlint"
+)
+
+// Exit codes
+const (
+	exitOK       = 0
+	exitFindings = 1 // errors, or warnings with -strict
+	exitError    = 2 // bad usage or I/O failure
+)
+
+// report is the -json output
+type report struct {
+	Files       int                   `json:"files"`
+	Errors      int                   `json:"errors"`
+	Warnings    int                   `json:"warnings"`
+	Diagnostics []riftlint.Diagnostic `json:"diagnostics"`
+}
+
+func main() {
+	os.Exit(run(os.Args[1:]))
+}
+
+// run parses args and lints the named paths, returning the exit code
+func run(args []string) int {
+	fl := flag.NewFlagSet("riftlint", flag.ContinueOnError)
+	asJSON := fl.Bool("json", false, "print the findings as one JSON document")
+	strict := fl.Bool("strict", false, "exit 1 on warnings as well as errors")
+	fl.Usage = func() {
+		fmt.Fprintf(fl.Output(), "usage: riftlint [flags] path ...\n\n")
+		fl.PrintDefaults()
+	}
+	if err := fl.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return exitOK
+		}
+		return exitError
+	}
+	if fl.NArg() == 0 {
+		fl.Usage()
+		return exitError
+	}
+
+	rep := report{Diagnostics: []riftlint.Diagnostic{}}
+	failed := false
+	for _, path := range fl.Args() {
+		files, err := expand(path)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "riftlint: %v\n", err)
+			failed = true
+		}
+		for _, file := range files {
+			diags, err := riftlint.LintFile(file)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "riftlint: %v\n", err)
+				failed = true
+				continue
+			}
+			rep.Files++
+			rep.Diagnostics = append(rep.Diagnostics, diags...)
+		}
+	}
+	for _, d := range rep.Diagnostics {
+		if d.Severity == riftlint.SeverityError {
+			rep.Errors++
+		} else {
+			rep.Warnings++
+		}
+	}
+
+	if *asJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(rep); err != nil {
+			fmt.Fprintf(os.Stderr, "riftlint: %v\n", err)
+			return exitError
+		}
+	} else {
+		for _, d := range rep.Diagnostics {
+			fmt.Println(d)
+		}
+	}
+
+	switch {
+	case failed:
+		return exitError
+	case rep.Errors > 0, *strict && rep.Warnings > 0:
+		return exitFindings
+	}
+	return exitOK
+}
+
+// expand returns path, or the files under it to lint if it is a directory
+func expand(path string) ([]string, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		return []string{path}, nil
+	}
+	return riftlint.Files(path)
+}
@@ -0,0 +1,633 @@
+// go/target/cmd/riftrepl/commands.go
+// riftrepl Commands - Go Implementation
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	rift "github.com/obinexus/riftlang/bindings/go-riftlang"
+)
+
+// ============================================================================
+// Session
+// ============================================================================
+
+// session is the state a shell's commands share
+type session struct {
+	out    io.Writer
+	engine *rift.PatternEngine
+}
+
+// newSession returns a session writing to out, its engine registered as
+// "repl" so the governance dashboard lists it
+func newSession(out io.Writer, mode rift.EngineMode) *session {
+	e := rift.NewPatternEngine(mode.String())
+	rift.RegisterEngine("repl", e)
+	return &session{out: out, engine: e}
+}
+
+// setAudit installs or removes the audit printer
+func (s *session) setAudit(on bool) {
+	if on {
+		rift.SetAuditSink(&auditPrinter{out: s.out})
+	} else {
+		rift.SetAuditSink(nil)
+	}
+}
+
+// token looks up a token of the session by name
+func (s *session) token(name string) (*rift.RiftToken, error) {
+	t, ok := rift.DefaultRegistry.Lookup(name)
+	if !ok {
+		return nil, fmt.Errorf("no token %q", name)
+	}
+	return t, nil
+}
+
+// ============================================================================
+// Command Table
+// ============================================================================
+
+// command is one shell command. Commands taking the rest of the line get
+// it as their only argument.
+type command struct {
+	usage    string
+	help     string
+	min, max int  // argument counts; max -1 is unbounded
+	rest     bool // takes the rest of the line
+	run      func(s *session, args []string) error
+}
+
+// commands are the shell's commands by name; commandOrder lists them for
+// help
+var (
+	commands     map[string]command
+	commandOrder []string
+)
+
+func init() {
+	add := func(name string, c command) {
+		commands[name] = c
+		commandOrder = append(commandOrder, name)
+	}
+	commands = make(map[string]command)
+
+	add("int", command{"NAME VALUE", "create a governed GoInt token", 2, 2, false, cmdInt})
+	add("float", command{"NAME VALUE", "create a governed GoFloat token", 2, 2, false, cmdFloat})
+	add("str", command{"NAME VALUE", "create a governed GoString token", 2, 2, false, cmdStr})
+	add("superpose", command{"NAME STATE[@AMP]...", "create a QGoInt token superposed over the states", 2, -1, false, cmdSuperpose})
+	add("entangle", command{"NAME NAME", "entangle two tokens under a new ID", 2, 2, false, cmdEntangle})
+	add("collapse", command{"NAME INDEX", "collapse a superposed token to one state", 2, 2, false, cmdCollapse})
+	add("measure", command{"NAME", "collapse a superposed token by sampling", 1, 1, false, cmdMeasure})
+	add("get", command{"NAME", "read a token's value through governance", 1, 1, false, cmdGet})
+	add("set", command{"NAME VALUE", "write a token's value through governance", 2, 2, false, cmdSet})
+	add("lock", command{"NAME", "lock a token", 1, 1, false, cmdLock})
+	add("unlock", command{"NAME", "unlock a token", 1, 1, false, cmdUnlock})
+	add("validate", command{"NAME", "validate a token, reporting why it is not governed", 1, 1, false, cmdValidate})
+	add("release", command{"NAME", "release a token and forget its name", 1, 1, false, cmdRelease})
+	add("show", command{"[NAME]", "describe one token, or every token", 0, 1, false, cmdShow})
+	add("graph", command{"", "print the entanglement graph in DOT format", 0, 0, false, cmdGraph})
+	add("load", command{"FILE", "load a .rift pattern set into the engine", 1, 1, false, cmdLoad})
+	add("pair", command{"LEFT RIGHT [PRIORITY]", "add a pattern pair; RIGHT may use $1 and {name}", 2, 3, false, cmdPair})
+	add("unpair", command{"ID", "remove a pattern pair", 1, 1, false, cmdUnpair})
+	add("pairs", command{"", "list the engine's pattern pairs", 0, 0, false, cmdPairs})
+	add("mode", command{"[MODE]", "show or set the engine mode", 0, 1, false, cmdMode})
+	add("match", command{"INPUT", "match the rest of the line", 1, 1, true, cmdMatch})
+	add("explain", command{"INPUT", "show how every pair fares against the rest of the line", 1, 1, true, cmdExplain})
+	add("transform", command{"INPUT", "rewrite every match in the rest of the line", 1, 1, true, cmdTransform})
+	add("stats", command{"", "print the engine's statistics", 0, 0, false, cmdStats})
+	add("audit", command{"on|off", "print audit events or stop printing them", 1, 1, false, cmdAudit})
+	add("help", command{"", "list the commands", 0, 0, false, cmdHelp})
+}
+
+// cmdHelp prints the command table
+func cmdHelp(s *session, _ []string) error {
+	for _, name := range commandOrder {
+		c := commands[name]
+		fmt.Fprintf(s.out, "  %-30s %s\n", strings.TrimSpace(name+" "+c.usage), c.help)
+	}
+	fmt.Fprintf(s.out, "  %-30s %s\n", "quit", "leave the shell")
+	return nil
+}
+
+// ============================================================================
+// Token Commands
+// ============================================================================
+
+// cmdInt creates a GoInt token
+func cmdInt(s *session, args []string) error {
+	v, err := strconv.ParseInt(args[1], 0, 64)
+	if err != nil {
+		return err
+	}
+	t, err := rift.Int(args[0], v)
+	if err != nil {
+		return err
+	}
+	return s.created(args[0], t)
+}
+
+// cmdFloat creates a GoFloat token
+func cmdFloat(s *session, args []string) error {
+	v, err := strconv.ParseFloat(args[1], 64)
+	if err != nil {
+		return err
+	}
+	t, err := rift.Float(args[0], v)
+	if err != nil {
+		return err
+	}
+	return s.created(args[0], t)
+}
+
+// cmdStr creates a GoString token
+func cmdStr(s *session, args []string) error {
+	t, err := rift.Str(args[0], args[1])
+	if err != nil {
+		return err
+	}
+	return s.created(args[0], t)
+}
+
+// created describes a token just created, or the governance error that
+// kept it from being governed
+func (s *session) created(name string, t *rift.RiftToken) error {
+	if err := t.ValidateErr(); err != nil {
+		return err
+	}
+	s.describe(name, t)
+	return nil
+}
+
+// cmdSuperpose creates a superposed token. Each state is an int, a float
+// or a string, optionally followed by @ and its amplitude; amplitudes are
+// given for every state or for none.
+func cmdSuperpose(s *session, args []string) error {
+	name, specs := args[0], args[1:]
+	states := make([]*rift.RiftToken, len(specs))
+	var amps []float64
+	for i, spec := range specs {
+		value, amp, hasAmp := strings.Cut(spec, "@")
+		if hasAmp {
+			a, err := strconv.ParseFloat(amp, 64)
+			if err != nil {
+				return fmt.Errorf("state %d: amplitude: %v", i, err)
+			}
+			amps = append(amps, a)
+		}
+		states[i] = stateToken(value)
+	}
+	if len(amps) != 0 && len(amps) != len(states) {
+		return fmt.Errorf("%d amplitudes for %d states", len(amps), len(states))
+	}
+
+	memory := rift.NewRiftMemorySpan(rift.SpanSuperposed, 64)
+	memory.Alignment = rift.QuantumAlignment
+	t := rift.NewRiftToken(rift.TokenQGoInt, memory)
+	if err := t.SuperposeErr(states, amps); err != nil {
+		return err
+	}
+	if _, err := rift.DefaultRegistry.RegisterErr(name, t); err != nil {
+		return err
+	}
+	s.describe(name, t)
+	return nil
+}
+
+// stateToken returns the initialized state token of a literal, as
+// rift.Superpose builds them
+func stateToken(literal string) *rift.RiftToken {
+	tokenType, value := parseLiteral(literal)
+	t := rift.NewRiftToken(tokenType, rift.NewRiftMemorySpan(rift.SpanFixed, 64))
+	t.Value = value
+	t.SetBit(rift.TokenInitialized)
+	return t
+}
+
+// parseLiteral reads a literal as an int, then a float, then a string
+func parseLiteral(literal string) (int, rift.RiftTokenValue) {
+	if i, err := strconv.ParseInt(literal, 0, 64); err == nil {
+		return rift.TokenGoInt, rift.RiftTokenValue{IntVal: i}
+	}
+	if f, err := strconv.ParseFloat(literal, 64); err == nil {
+		return rift.TokenGoFloat, rift.RiftTokenValue{FloatVal: f}
+	}
+	return rift.TokenGoString, rift.RiftTokenValue{StringVal: literal}
+}
+
+// cmdEntangle entangles two tokens
+func cmdEntangle(s *session, args []string) error {
+	a, err := s.token(args[0])
+	if err != nil {
+		return err
+	}
+	b, err := s.token(args[1])
+	if err != nil {
+		return err
+	}
+	id := rift.NewEntanglementID()
+	if err := a.EntangleWithErr(b, id); err != nil {
+		return err
+	}
+	if err := b.EntangleWithErr(a, id); err != nil {
+		return err
+	}
+	fmt.Fprintf(s.out, "entangled %s and %s under id %d\n", args[0], args[1], id)
+	return nil
+}
+
+// cmdCollapse collapses a token to the state at an index
+func cmdCollapse(s *session, args []string) error {
+	t, err := s.token(args[0])
+	if err != nil {
+		return err
+	}
+	i, err := strconv.ParseUint(args[1], 10, 32)
+	if err != nil {
+		return err
+	}
+	if err := t.CollapseErr(uint32(i)); err != nil {
+		return err
+	}
+	s.describe(args[0], t)
+	return nil
+}
+
+// cmdMeasure collapses a token by sampling
+func cmdMeasure(s *session, args []string) error {
+	t, err := s.token(args[0])
+	if err != nil {
+		return err
+	}
+	if _, err := t.Measure(); err != nil {
+		return err
+	}
+	s.describe(args[0], t)
+	return nil
+}
+
+// cmdGet reads a token's value with GetValue
+func cmdGet(s *session, args []string) error {
+	t, err := s.token(args[0])
+	if err != nil {
+		return err
+	}
+	v, err := t.GetValue()
+	if err != nil {
+		return err
+	}
+	fmt.Fprintln(s.out, formatValue(t.Type, v))
+	return nil
+}
+
+// cmdSet writes a token's value with SetValue, parsing it as the token's
+// type
+func cmdSet(s *session, args []string) error {
+	t, err := s.token(args[0])
+	if err != nil {
+		return err
+	}
+	var v rift.RiftTokenValue
+	switch t.Type {
+	case rift.TokenGoInt:
+		if v.IntVal, err = strconv.ParseInt(args[1], 0, 64); err != nil {
+			return err
+		}
+	case rift.TokenGoFloat:
+		if v.FloatVal, err = strconv.ParseFloat(args[1], 64); err != nil {
+			return err
+		}
+	case rift.TokenGoString:
+		v.StringVal = args[1]
+	default:
+		return fmt.Errorf("cannot set a %s token from the shell", rift.TokenTypeName(t.Type))
+	}
+	if err := t.SetValue(v); err != nil {
+		return err
+	}
+	s.describe(args[0], t)
+	return nil
+}
+
+// cmdLock locks a token
+func cmdLock(s *session, args []string) error {
+	t, err := s.token(args[0])
+	if err != nil {
+		return err
+	}
+	if !t.Lock() {
+		return fmt.Errorf("locking %s would deadlock", args[0])
+	}
+	return nil
+}
+
+// cmdUnlock unlocks a token
+func cmdUnlock(s *session, args []string) error {
+	t, err := s.token(args[0])
+	if err != nil {
+		return err
+	}
+	return t.UnlockErr()
+}
+
+// cmdValidate validates a token
+func cmdValidate(s *session, args []string) error {
+	t, err := s.token(args[0])
+	if err != nil {
+		return err
+	}
+	if err := t.ValidateErr(); err != nil {
+		return err
+	}
+	fmt.Fprintf(s.out, "%s is governed\n", args[0])
+	return nil
+}
+
+// cmdRelease releases a token and unregisters its name
+func cmdRelease(s *session, args []string) error {
+	t, err := s.token(args[0])
+	if err != nil {
+		return err
+	}
+	if err := t.Release(); err != nil {
+		return err
+	}
+	rift.DefaultRegistry.Unregister(args[0])
+	return nil
+}
+
+// cmdShow describes one token or all of them
+func cmdShow(s *session, args []string) error {
+	if len(args) == 1 {
+		t, err := s.token(args[0])
+		if err != nil {
+			return err
+		}
+		s.describe(args[0], t)
+		return nil
+	}
+	names := rift.DefaultRegistry.Names()
+	if len(names) == 0 {
+		fmt.Fprintln(s.out, "no tokens")
+	}
+	for _, name := range names {
+		t, _ := rift.DefaultRegistry.Lookup(name)
+		s.describe(name, t)
+	}
+	return nil
+}
+
+// cmdGraph prints the entanglement graph
+func cmdGraph(s *session, _ []string) error {
+	return rift.EntanglementGraphOf(rift.DefaultRegistry).WriteDOT(s.out)
+}
+
+// ============================================================================
+// Pattern Commands
+// ============================================================================
+
+// cmdLoad loads a pattern file
+func cmdLoad(s *session, args []string) error {
+	if err := s.engine.LoadPatterns(args[0]); err != nil {
+		return err
+	}
+	fmt.Fprintf(s.out, "%d pairs\n", s.engine.GetPairCount())
+	return nil
+}
+
+// cmdPair adds a pair
+func cmdPair(s *session, args []string) error {
+	var priority uint64
+	if len(args) == 3 {
+		var err error
+		if priority, err = strconv.ParseUint(args[2], 10, 32); err != nil {
+			return err
+		}
+	}
+	id, err := s.engine.AddPairID(args[0], args[1], uint32(priority), false)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(s.out, "pair %d\n", id)
+	return nil
+}
+
+// cmdUnpair removes a pair
+func cmdUnpair(s *session, args []string) error {
+	id, err := strconv.ParseUint(args[0], 10, 32)
+	if err != nil {
+		return err
+	}
+	return s.engine.RemovePair(uint32(id))
+}
+
+// cmdPairs lists the pairs, read back from the engine's export
+func cmdPairs(s *session, _ []string) error {
+	var buf bytes.Buffer
+	if err := s.engine.Export(&buf); err != nil {
+		return err
+	}
+	var x rift.EngineExport
+	if err := json.Unmarshal(buf.Bytes(), &x); err != nil {
+		return err
+	}
+	if len(x.Engine.Pairs) == 0 {
+		fmt.Fprintln(s.out, "no pairs")
+	}
+	for _, p := range x.Engine.Pairs {
+		fmt.Fprintf(s.out, "  %3d  %q -> %q  priority %d", p.ID, p.Left, p.Right, p.Priority)
+		if p.Group != "" {
+			fmt.Fprintf(s.out, "  group %s", p.Group)
+		}
+		fmt.Fprintln(s.out)
+	}
+	return nil
+}
+
+// cmdMode shows or sets the engine mode
+func cmdMode(s *session, args []string) error {
+	if len(args) == 1 {
+		m, err := rift.EngineModeNamed(args[0])
+		if err != nil {
+			return err
+		}
+		s.engine.SetMode(m)
+	}
+	fmt.Fprintln(s.out, s.engine.Mode())
+	return nil
+}
+
+// cmdMatch matches an input, printing each state of a quantum result
+func cmdMatch(s *session, args []string) error {
+	r := s.engine.Match(args[0])
+	if !r.Matched {
+		fmt.Fprintln(s.out, "no match")
+		return nil
+	}
+	if len(r.States) == 0 {
+		s.printMatch("", *r)
+		return nil
+	}
+	for i, st := range r.States {
+		s.printMatch(fmt.Sprintf("state %d (p=%.3f): ", i, r.Amplitudes[i]*r.Amplitudes[i]), st)
+	}
+	return nil
+}
+
+// printMatch prints one match result
+func (s *session) printMatch(prefix string, r rift.MatchResult) {
+	fmt.Fprintf(s.out, "%spair %d matched %q at %d:%d -> %q\n", prefix, r.TransformID, r.Text, r.Start, r.End, r.Output)
+	for _, name := range sortedKeys(r.Groups) {
+		fmt.Fprintf(s.out, "  %s = %q\n", name, r.Groups[name])
+	}
+}
+
+// cmdExplain prints a trace of every pair against an input
+func cmdExplain(s *session, args []string) error {
+	traces := s.engine.Explain(args[0])
+	if len(traces) == 0 {
+		fmt.Fprintln(s.out, "no pairs")
+	}
+	for _, tr := range traces {
+		mark := " "
+		if tr.Winner {
+			mark = "*"
+		}
+		fmt.Fprintf(s.out, "%s %2d  pair %d %q: %s", mark, tr.Rank, tr.TransformID, tr.Left, tr.Reason)
+		if tr.Matched {
+			fmt.Fprintf(s.out, " -> %q", tr.Output)
+		}
+		fmt.Fprintln(s.out)
+	}
+	return nil
+}
+
+// cmdTransform rewrites every match in an input
+func cmdTransform(s *session, args []string) error {
+	out, matches := s.engine.Transform(args[0])
+	fmt.Fprintf(s.out, "%q (%d matches)\n", out, len(matches))
+	return nil
+}
+
+// cmdStats prints the engine statistics
+func cmdStats(s *session, _ []string) error {
+	st := s.engine.Stats()
+	fmt.Fprintf(s.out, "pairs %d, matches %d, failures %d, average %.3fms\n",
+		st.PairCount, st.TotalMatches, st.TotalFailures, st.AverageMatchTimeMs)
+	return nil
+}
+
+// cmdAudit turns audit printing on or off
+func cmdAudit(s *session, args []string) error {
+	switch args[0] {
+	case "on":
+		s.setAudit(true)
+	case "off":
+		s.setAudit(false)
+	default:
+		return fmt.Errorf("audit takes on or off, not %q", args[0])
+	}
+	return nil
+}
+
+// ============================================================================
+// Output
+// ============================================================================
+
+// tokenBits names the validation bits in display order
+var tokenBits = []struct {
+	bit  uint32
+	name string
+}{
+	{rift.TokenAllocated, "allocated"},
+	{rift.TokenInitialized, "initialized"},
+	{rift.TokenLocked, "locked"},
+	{rift.TokenGoverned, "governed"},
+	{rift.TokenSuperposed, "superposed"},
+	{rift.TokenEntangled, "entangled"},
+	{rift.TokenPersistent, "persistent"},
+	{rift.TokenShadow, "shadow"},
+}
+
+// bitNames returns the names of the bits set in bits
+func bitNames(bits uint32) string {
+	var names []string
+	for _, b := range tokenBits {
+		if bits&b.bit != 0 {
+			names = append(names, b.name)
+		}
+	}
+	return strings.Join(names, " ")
+}
+
+// describe prints a token: its type, value or states, bits and partners.
+// The value is read without governance so ungoverned tokens show too.
+func (s *session) describe(name string, t *rift.RiftToken) {
+	bits := t.ValidationBits.Load()
+	fmt.Fprintf(s.out, "%s: %s", name, rift.TokenTypeName(t.Type))
+	if bits&rift.TokenSuperposed == 0 && bits&rift.TokenInitialized != 0 {
+		fmt.Fprintf(s.out, " = %s", formatValue(t.Type, t.Value))
+	}
+	fmt.Fprintf(s.out, " [%s]", bitNames(bits))
+	if t.EntanglementID != 0 {
+		fmt.Fprintf(s.out, " entanglement %d", t.EntanglementID)
+	}
+	fmt.Fprintln(s.out)
+
+	if bits&rift.TokenSuperposed == 0 {
+		return
+	}
+	for i, st := range t.SuperposedStates {
+		p := 1 / float64(len(t.SuperposedStates))
+		if i < len(t.Amplitudes) {
+			p = t.Amplitudes[i] * t.Amplitudes[i]
+		}
+		fmt.Fprintf(s.out, "  %d: %s (p=%.3f)\n", i, formatValue(st.Type, st.Value), p)
+	}
+	fmt.Fprintf(s.out, "  entropy %.3f bits\n", rift.CalculateEntropy(t))
+}
+
+// formatValue renders a value as a token of tokenType holds it
+func formatValue(tokenType int, v rift.RiftTokenValue) string {
+	switch {
+	case tokenType == rift.TokenGoFloat:
+		return strconv.FormatFloat(v.FloatVal, 'g', -1, 64)
+	case tokenType == rift.TokenGoString:
+		return strconv.Quote(v.StringVal)
+	case v.PtrVal != nil:
+		return fmt.Sprintf("%v", v.PtrVal)
+	}
+	return strconv.FormatInt(v.IntVal, 10)
+}
+
+// auditPrinter is the audit sink of a session: it reformats each JSON
+// event line for reading as it is written
+type auditPrinter struct {
+	out io.Writer
+}
+
+// Write prints the events in p, one JSON line each
+func (a *auditPrinter) Write(p []byte) (int, error) {
+	for _, line := range bytes.Split(bytes.TrimSpace(p), []byte("\n")) {
+		var ev rift.AuditEvent
+		if err := json.Unmarshal(line, &ev); err != nil {
+			fmt.Fprintf(a.out, "  audit: %s\n", line)
+			continue
+		}
+		fmt.Fprintf(a.out, "  audit #%d %s %s [%s]", ev.Seq, ev.Kind, rift.TokenTypeName(ev.TokenType), bitNames(ev.TokenBits))
+		if ev.EntanglementID != 0 {
+			fmt.Fprintf(a.out, " entanglement %d", ev.EntanglementID)
+		}
+		if ev.Detail != "" {
+			fmt.Fprintf(a.out, ": %s", ev.Detail)
+		}
+		fmt.Fprintln(a.out)
+	}
+	return len(p), nil
+}
@@ -0,0 +1,221 @@
+// go/target/cmd/riftrepl/main.go
+// riftrepl Interactive Governance Shell - Go Implementation
+//
+// riftrepl reads commands that create tokens, superpose, entangle and
+// collapse them, load pattern sets and run matches, printing each audit
+// event as the binding emits it:
+//
+//	riftrepl
+//	riftrepl -patterns patterns.rift -mode quantum
+//	riftrepl -seed 1 < session.txt
+//
+// Type `help` at the prompt for the commands. Arguments are separated by
+// spaces; quote them as Go strings ("a b", `\d+`) to include spaces or
+// escapes. Tokens are registered in the binding's DefaultRegistry under
+// their names, so `graph` and `show` see the whole session.
+package main
+
+import (
+	"bufio"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	rift "github.com/obinexus/riftlang/bindings/go-riftlang"
+)
+
+// Exit codes
+const (
+	exitOK    = 0
+	exitError = 2 // bad usage, pattern file, or I/O failure
+)
+
+func main() {
+	os.Exit(run(os.Args[1:]))
+}
+
+// run parses args and runs the shell on stdin, returning the exit code
+func run(args []string) int {
+	var patterns, mode string
+	var seed int64
+	var audit bool
+	fl := flag.NewFlagSet("riftrepl", flag.ContinueOnError)
+	fl.StringVar(&patterns, "patterns", "", "`file` holding a .rift pattern set to load at start")
+	fl.StringVar(&mode, "mode", "classical", "pattern engine `mode`: classical, quantum, strict or permissive")
+	fl.Int64Var(&seed, "seed", 0, "seed measurements with `n` for reproducible sessions (0 is random)")
+	fl.BoolVar(&audit, "audit", true, "print audit events as they are emitted")
+	fl.Usage = func() {
+		fmt.Fprintf(fl.Output(), "usage: riftrepl [flags]\n\n")
+		fl.PrintDefaults()
+	}
+	if err := fl.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return exitOK
+		}
+		return exitError
+	}
+	if fl.NArg() != 0 {
+		fl.Usage()
+		return exitError
+	}
+
+	m, err := rift.EngineModeNamed(mode)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "riftrepl: -mode: %v\n", err)
+		return exitError
+	}
+	if seed != 0 {
+		rift.SetDeterministic(seed)
+	}
+
+	s := newSession(os.Stdout, m)
+	if patterns != "" {
+		if err := s.engine.LoadPatterns(patterns); err != nil {
+			fmt.Fprintf(os.Stderr, "riftrepl: %v\n", err)
+			return exitError
+		}
+	}
+	if audit {
+		s.setAudit(true)
+	}
+	defer s.setAudit(false)
+
+	if err := s.serve(os.Stdin, interactive(os.Stdin)); err != nil {
+		fmt.Fprintf(os.Stderr, "riftrepl: %v\n", err)
+		return exitError
+	}
+	return exitOK
+}
+
+// interactive reports whether f is a terminal, so the prompt is only
+// printed for people and not for piped sessions
+func interactive(f *os.File) bool {
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// ============================================================================
+// Read-Eval-Print Loop
+// ============================================================================
+
+// serve runs the commands read from r until end of input or `quit`
+func (s *session) serve(r io.Reader, prompt bool) error {
+	sc := bufio.NewScanner(r)
+	for {
+		if prompt {
+			fmt.Fprint(s.out, "rift> ")
+		}
+		if !sc.Scan() {
+			break
+		}
+		if quit := s.exec(sc.Text()); quit {
+			return nil
+		}
+	}
+	if prompt {
+		fmt.Fprintln(s.out)
+	}
+	return sc.Err()
+}
+
+// exec runs one command line, printing its result or error, and reports
+// whether it was `quit`
+func (s *session) exec(line string) bool {
+	name, args, rest, err := splitCommand(line)
+	if err != nil {
+		fmt.Fprintf(s.out, "error: %v\n", err)
+		return false
+	}
+	if name == "" {
+		return false
+	}
+	if name == "quit" || name == "exit" {
+		return true
+	}
+	c, ok := commands[name]
+	if !ok {
+		fmt.Fprintf(s.out, "error: unknown command %q (try help)\n", name)
+		return false
+	}
+	if c.rest {
+		args = []string{rest}
+		if rest == "" {
+			args = nil
+		}
+	}
+	if len(args) < c.min || (c.max >= 0 && len(args) > c.max) {
+		fmt.Fprintf(s.out, "usage: %s %s\n", name, c.usage)
+		return false
+	}
+	if err := c.run(s, args); err != nil {
+		fmt.Fprintf(s.out, "error: %v\n", err)
+	}
+	return false
+}
+
+// ============================================================================
+// Parsing
+// ============================================================================
+
+// splitCommand splits a line into its command name and arguments, and
+// returns the text after the name for commands taking the rest of the
+// line, unquoted when it is a single quoted argument. A line starting
+// with # is a comment.
+func splitCommand(line string) (name string, args []string, rest string, err error) {
+	line = strings.TrimSpace(line)
+	if line == "" || line[0] == '#' {
+		return "", nil, "", nil
+	}
+	name, rest, _ = strings.Cut(line, " ")
+	rest = strings.TrimSpace(rest)
+	if args, err = splitArgs(rest); err != nil {
+		return "", nil, "", err
+	}
+	if len(args) == 1 && rest != "" && (rest[0] == '"' || rest[0] == '`') {
+		rest = args[0]
+	}
+	return name, args, rest, nil
+}
+
+// splitArgs splits s at spaces, reading arguments that start with a quote
+// as Go string literals
+func splitArgs(s string) ([]string, error) {
+	var args []string
+	for {
+		s = strings.TrimLeft(s, " \t")
+		if s == "" {
+			return args, nil
+		}
+		if s[0] == '"' || s[0] == '`' {
+			quoted, err := strconv.QuotedPrefix(s)
+			if err != nil {
+				return nil, fmt.Errorf("bad quoted argument %s", s)
+			}
+			arg, _ := strconv.Unquote(quoted)
+			args = append(args, arg)
+			s = s[len(quoted):]
+			continue
+		}
+		end := strings.IndexAny(s, " \t")
+		if end < 0 {
+			end = len(s)
+		}
+		args = append(args, s[:end])
+		s = s[end:]
+	}
+}
+
+// sortedKeys returns the keys of m in order
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
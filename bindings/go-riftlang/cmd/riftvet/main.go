@@ -0,0 +1,21 @@
+// go/target/cmd/riftvet/main.go
+// riftvet Governance Vet Tool - Go Implementation
+//
+// riftvet runs the riftvet analyzer as a go vet tool, reporting Go code
+// that bypasses Rift token governance:
+//
+//	go install github.com/obinexus/riftlang/bindings/go-riftlang/cmd/riftvet
+//	go vet -vettool=$(which riftvet) ./...
+//
+// See package riftvet for the checks.
+package main
+
+import (
+	"golang.org/x/tools/go/analysis/unitchecker"
+
+	"github.com/obinexus/riftlang/bindings/go-riftlang/riftvet"
+)
+
+func main() {
+	unitchecker.Main(riftvet.Analyzer)
+}
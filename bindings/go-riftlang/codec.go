@@ -0,0 +1,428 @@
+// go/target/codec.go
+// Token Serialization (JSON and compact binary) - Go Implementation
+
+package rift
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"math"
+)
+
+// ============================================================================
+// JSON
+// ============================================================================
+
+// spanJSON is the JSON form of a RiftMemorySpan
+type spanJSON struct {
+	Type       int    `json:"type"`
+	Bytes      uint64 `json:"bytes"`
+	Alignment  uint32 `json:"alignment"`
+	Open       bool   `json:"open"`
+	Direction  bool   `json:"direction"`
+	AccessMask uint32 `json:"accessMask"`
+}
+
+// valueJSON is the JSON form of a RiftTokenValue
+type valueJSON struct {
+	Int    int64        `json:"int,omitempty"`
+	Float  float64      `json:"float,omitempty"`
+	String string       `json:"string,omitempty"`
+	Ptr    interface{}  `json:"ptr,omitempty"`
+	Arr    []*RiftToken `json:"arr,omitempty"`
+}
+
+// tokenJSON is the JSON form of a RiftToken. Entanglement partners are
+// pointers and cannot be persisted; the entanglement ID identifies the group.
+type tokenJSON struct {
+	Type              int          `json:"type"`
+	Value             valueJSON    `json:"value"`
+	Memory            *spanJSON    `json:"memory,omitempty"`
+	ValidationBits    uint32       `json:"validationBits"`
+	SuperposedStates  []*RiftToken `json:"superposedStates,omitempty"`
+	Amplitudes        []float64    `json:"amplitudes,omitempty"`
+	Phases            []float64    `json:"phases,omitempty"`
+	Phase             float64      `json:"phase,omitempty"`
+	EntanglementCount uint32       `json:"entanglementCount,omitempty"`
+	EntanglementID    uint32       `json:"entanglementId,omitempty"`
+	SourceLine        uint32       `json:"sourceLine,omitempty"`
+	SourceColumn      uint32       `json:"sourceColumn,omitempty"`
+	SourceFile        string       `json:"sourceFile,omitempty"`
+}
+
+// MarshalJSON encodes the token with its governance state
+func (t *RiftToken) MarshalJSON() ([]byte, error) {
+	out := tokenJSON{
+		Type: t.Type,
+		Value: valueJSON{
+			Int:    t.Value.IntVal,
+			Float:  t.Value.FloatVal,
+			String: t.Value.StringVal,
+			Ptr:    t.Value.PtrVal,
+			Arr:    t.Value.ArrVal,
+		},
+		ValidationBits:    t.ValidationBits.Load(),
+		SuperposedStates:  t.SuperposedStates,
+		Amplitudes:        t.Amplitudes,
+		Phases:            t.Phases,
+		Phase:             t.Phase,
+		EntanglementCount: t.EntanglementCount,
+		EntanglementID:    t.EntanglementID,
+		SourceLine:        t.SourceLine,
+		SourceColumn:      t.SourceColumn,
+		SourceFile:        t.SourceFile,
+	}
+	if t.Memory != nil {
+		out.Memory = &spanJSON{
+			Type:       t.Memory.Type,
+			Bytes:      t.Memory.Bytes,
+			Alignment:  t.Memory.Alignment,
+			Open:       t.Memory.Open,
+			Direction:  t.Memory.Direction,
+			AccessMask: t.Memory.Mask(),
+		}
+	}
+	return json.Marshal(out)
+}
+
+// UnmarshalJSON restores a token. The Locked bit is cleared since no lock is
+// held by the restored token. The value is stored as a governed write
+// stores it, bumping the version and reaching a shared span, and a sealed
+// token is resealed, so it still validates with integrity mode on.
+func (t *RiftToken) UnmarshalJSON(data []byte) error {
+	var in tokenJSON
+	if err := json.Unmarshal(data, &in); err != nil {
+		return err
+	}
+
+	t.Type = in.Type
+	t.storeValue(RiftTokenValue{
+		IntVal:    in.Value.Int,
+		FloatVal:  in.Value.Float,
+		StringVal: in.Value.String,
+		PtrVal:    in.Value.Ptr,
+		ArrVal:    in.Value.Arr,
+	})
+	t.Memory = nil
+	if in.Memory != nil {
+		t.Memory = &RiftMemorySpan{
+			Type:       in.Memory.Type,
+			Bytes:      in.Memory.Bytes,
+			Alignment:  in.Memory.Alignment,
+			Open:       in.Memory.Open,
+			Direction:  in.Memory.Direction,
+			AccessMask: in.Memory.AccessMask,
+		}
+	}
+	t.ValidationBits.Store(in.ValidationBits &^ TokenLocked)
+	t.SuperposedStates = in.SuperposedStates
+	t.SuperpositionCount = uint32(len(in.SuperposedStates))
+	t.Amplitudes = in.Amplitudes
+	t.Phases = in.Phases
+	t.Phase = in.Phase
+	t.EntangledWith = nil
+	t.EntanglementCount = in.EntanglementCount
+	t.EntanglementID = in.EntanglementID
+	t.SourceLine = in.SourceLine
+	t.SourceColumn = in.SourceColumn
+	t.SourceFile = in.SourceFile
+	t.reseal()
+	return nil
+}
+
+// ============================================================================
+// Binary
+// ============================================================================
+
+// Binary token format:
+//
+//	"RTK" version
+//	uvarint type, uvarint validationBits
+//	byte hasSpan [uvarint type, bytes, alignment; byte open|direction<<1; uvarint accessMask]
+//	varint int, float64 float, string str, bytes ptr(JSON), uvarint n + n tokens (arr)
+//	float64 phase, uvarint n + n float64 amplitudes, uvarint n + n tokens (states)
+//	uvarint n + n float64 phases (version 2+)
+//	uvarint entanglementCount, entanglementID
+//	uvarint sourceLine, sourceColumn, string sourceFile
+//
+// Strings and nested tokens are uvarint length-prefixed; floats are 8 bytes
+// little-endian.
+const (
+	binaryMagic   = "RTK"
+	binaryVersion = 2
+)
+
+// MarshalBinary encodes the token in the compact binary format
+func (t *RiftToken) MarshalBinary() ([]byte, error) {
+	return appendToken(nil, t)
+}
+
+// UnmarshalBinary restores a token from the compact binary format, storing
+// the value and resealing as UnmarshalJSON does
+func (t *RiftToken) UnmarshalBinary(data []byte) error {
+	r := &binaryReader{buf: data}
+	if err := r.token(t); err != nil {
+		return err
+	}
+	if len(r.buf) != 0 {
+		return fmt.Errorf("trailing %d bytes after token", len(r.buf))
+	}
+	return nil
+}
+
+func appendToken(b []byte, t *RiftToken) ([]byte, error) {
+	b = append(b, binaryMagic...)
+	b = append(b, binaryVersion)
+	b = binary.AppendUvarint(b, uint64(t.Type))
+	b = binary.AppendUvarint(b, uint64(t.ValidationBits.Load()))
+
+	if t.Memory == nil {
+		b = append(b, 0)
+	} else {
+		b = append(b, 1)
+		b = binary.AppendUvarint(b, uint64(t.Memory.Type))
+		b = binary.AppendUvarint(b, t.Memory.Bytes)
+		b = binary.AppendUvarint(b, uint64(t.Memory.Alignment))
+		var flags byte
+		if t.Memory.Open {
+			flags |= 1
+		}
+		if t.Memory.Direction {
+			flags |= 2
+		}
+		b = append(b, flags)
+		b = binary.AppendUvarint(b, uint64(t.Memory.Mask()))
+	}
+
+	b = binary.AppendVarint(b, t.Value.IntVal)
+	b = appendFloat(b, t.Value.FloatVal)
+	b = appendString(b, t.Value.StringVal)
+	if t.Value.PtrVal == nil {
+		b = binary.AppendUvarint(b, 0)
+	} else {
+		ptr, err := json.Marshal(t.Value.PtrVal)
+		if err != nil {
+			return nil, fmt.Errorf("encode pointer value: %w", err)
+		}
+		b = appendString(b, string(ptr))
+	}
+	b, err := appendTokens(b, t.Value.ArrVal)
+	if err != nil {
+		return nil, err
+	}
+
+	b = appendFloat(b, t.Phase)
+	b = appendFloats(b, t.Amplitudes)
+	b, err = appendTokens(b, t.SuperposedStates)
+	if err != nil {
+		return nil, err
+	}
+	b = appendFloats(b, t.Phases)
+
+	b = binary.AppendUvarint(b, uint64(t.EntanglementCount))
+	b = binary.AppendUvarint(b, uint64(t.EntanglementID))
+	b = binary.AppendUvarint(b, uint64(t.SourceLine))
+	b = binary.AppendUvarint(b, uint64(t.SourceColumn))
+	b = appendString(b, t.SourceFile)
+	return b, nil
+}
+
+func appendTokens(b []byte, tokens []*RiftToken) ([]byte, error) {
+	b = binary.AppendUvarint(b, uint64(len(tokens)))
+	for _, tok := range tokens {
+		enc, err := appendToken(nil, tok)
+		if err != nil {
+			return nil, err
+		}
+		b = binary.AppendUvarint(b, uint64(len(enc)))
+		b = append(b, enc...)
+	}
+	return b, nil
+}
+
+func appendFloats(b []byte, fs []float64) []byte {
+	b = binary.AppendUvarint(b, uint64(len(fs)))
+	for _, f := range fs {
+		b = appendFloat(b, f)
+	}
+	return b
+}
+
+func appendFloat(b []byte, f float64) []byte {
+	return binary.LittleEndian.AppendUint64(b, math.Float64bits(f))
+}
+
+func appendString(b []byte, s string) []byte {
+	b = binary.AppendUvarint(b, uint64(len(s)))
+	return append(b, s...)
+}
+
+// binaryReader decodes the binary format with a sticky error
+type binaryReader struct {
+	buf []byte
+	err error
+}
+
+func (r *binaryReader) fail(format string, args ...interface{}) {
+	if r.err == nil {
+		r.err = fmt.Errorf(format, args...)
+	}
+}
+
+func (r *binaryReader) uvarint() uint64 {
+	if r.err != nil {
+		return 0
+	}
+	v, n := binary.Uvarint(r.buf)
+	if n <= 0 {
+		r.fail("truncated uvarint")
+		return 0
+	}
+	r.buf = r.buf[n:]
+	return v
+}
+
+func (r *binaryReader) varint() int64 {
+	if r.err != nil {
+		return 0
+	}
+	v, n := binary.Varint(r.buf)
+	if n <= 0 {
+		r.fail("truncated varint")
+		return 0
+	}
+	r.buf = r.buf[n:]
+	return v
+}
+
+func (r *binaryReader) bytes(n uint64) []byte {
+	if r.err != nil {
+		return nil
+	}
+	if uint64(len(r.buf)) < n {
+		r.fail("truncated data: need %d bytes, have %d", n, len(r.buf))
+		return nil
+	}
+	out := r.buf[:n]
+	r.buf = r.buf[n:]
+	return out
+}
+
+func (r *binaryReader) byte1() byte {
+	b := r.bytes(1)
+	if b == nil {
+		return 0
+	}
+	return b[0]
+}
+
+func (r *binaryReader) float() float64 {
+	b := r.bytes(8)
+	if b == nil {
+		return 0
+	}
+	return math.Float64frombits(binary.LittleEndian.Uint64(b))
+}
+
+func (r *binaryReader) floats(what string) []float64 {
+	n := r.uvarint()
+	if n == 0 || r.err != nil {
+		return nil
+	}
+	if n > uint64(len(r.buf))/8 {
+		r.fail("%s count %d exceeds data", what, n)
+		return nil
+	}
+	out := make([]float64, n)
+	for i := range out {
+		out[i] = r.float()
+	}
+	return out
+}
+
+func (r *binaryReader) string() string {
+	return string(r.bytes(r.uvarint()))
+}
+
+func (r *binaryReader) tokens() []*RiftToken {
+	n := r.uvarint()
+	if n == 0 || r.err != nil {
+		return nil
+	}
+	if n > uint64(len(r.buf)) {
+		r.fail("token count %d exceeds data", n)
+		return nil
+	}
+	out := make([]*RiftToken, n)
+	for i := range out {
+		sub := &binaryReader{buf: r.bytes(r.uvarint())}
+		if r.err != nil {
+			return nil
+		}
+		out[i] = &RiftToken{}
+		if err := sub.token(out[i]); err != nil {
+			r.fail("nested token %d: %v", i, err)
+			return nil
+		}
+	}
+	return out
+}
+
+func (r *binaryReader) token(t *RiftToken) error {
+	if string(r.bytes(uint64(len(binaryMagic)))) != binaryMagic {
+		r.fail("not a rift token")
+		return r.err
+	}
+	version := r.byte1()
+	if r.err == nil && (version == 0 || version > binaryVersion) {
+		return fmt.Errorf("unsupported token format version %d", version)
+	}
+
+	t.Type = int(r.uvarint())
+	t.ValidationBits.Store(uint32(r.uvarint()) &^ TokenLocked)
+
+	t.Memory = nil
+	if r.byte1() == 1 {
+		span := &RiftMemorySpan{}
+		span.Type = int(r.uvarint())
+		span.Bytes = r.uvarint()
+		span.Alignment = uint32(r.uvarint())
+		flags := r.byte1()
+		span.Open = flags&1 != 0
+		span.Direction = flags&2 != 0
+		span.AccessMask = uint32(r.uvarint())
+		t.Memory = span
+	}
+
+	value := RiftTokenValue{
+		IntVal:    r.varint(),
+		FloatVal:  r.float(),
+		StringVal: r.string(),
+	}
+	if ptr := r.string(); ptr != "" && r.err == nil {
+		if err := json.Unmarshal([]byte(ptr), &value.PtrVal); err != nil {
+			r.fail("decode pointer value: %v", err)
+		}
+	}
+	value.ArrVal = r.tokens()
+	t.storeValue(value)
+
+	t.Phase = r.float()
+	t.Amplitudes = r.floats("amplitude")
+	t.SuperposedStates = r.tokens()
+	t.SuperpositionCount = uint32(len(t.SuperposedStates))
+	t.Phases = nil
+	if version >= 2 {
+		t.Phases = r.floats("phase")
+	}
+
+	t.EntangledWith = nil
+	t.EntanglementCount = uint32(r.uvarint())
+	t.EntanglementID = uint32(r.uvarint())
+	t.SourceLine = uint32(r.uvarint())
+	t.SourceColumn = uint32(r.uvarint())
+	t.SourceFile = r.string()
+	t.reseal()
+	return r.err
+}
@@ -0,0 +1,1034 @@
+// go/target/codec_compact.go
+// Token and Match Serialization (MessagePack and CBOR) - Go Implementation
+
+package rift
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"math"
+)
+
+// ============================================================================
+// Compact Formats
+// ============================================================================
+
+// Tokens and match results encode in MessagePack and CBOR as maps keyed by
+// their JSON field names (see RiftToken.MarshalJSON), leaving out the same
+// empty fields, so consumers in other languages decode them with any
+// MessagePack or CBOR library. PtrVal is held as its JSON text, as in the
+// binary format. Decoders skip keys they do not know and accept every
+// integer and float width; CBOR tags are ignored and indefinite lengths
+// are not supported.
+//
+// The Marshal methods match the marshaler interfaces of the common Go
+// MessagePack and CBOR libraries, which pick them up; the Append methods
+// encode into a caller's buffer, so a pipeline reusing one allocates
+// nothing per token or match.
+
+// compactFormat selects MessagePack or CBOR
+type compactFormat uint8
+
+const (
+	formatMsgpack compactFormat = iota
+	formatCBOR
+)
+
+func (f compactFormat) String() string {
+	if f == formatCBOR {
+		return "cbor"
+	}
+	return "msgpack"
+}
+
+// compactMaxDepth bounds the nesting of tokens and match states, so a
+// cyclic token fails to encode and hostile input fails to decode rather
+// than exhausting the stack
+const compactMaxDepth = 512
+
+// MarshalMsgpack encodes the token with its governance state as MessagePack
+func (t *RiftToken) MarshalMsgpack() ([]byte, error) {
+	return t.AppendMsgpack(nil)
+}
+
+// AppendMsgpack appends the token's MessagePack encoding to b
+func (t *RiftToken) AppendMsgpack(b []byte) ([]byte, error) {
+	e := &compactEncoder{b: b, format: formatMsgpack}
+	if err := e.token(t); err != nil {
+		return nil, err
+	}
+	return e.b, nil
+}
+
+// UnmarshalMsgpack restores a token from MessagePack. As with
+// UnmarshalJSON the Locked bit is cleared, the value is stored as a
+// governed write, a sealed token is resealed and entanglement partners are
+// not restored.
+func (t *RiftToken) UnmarshalMsgpack(data []byte) error {
+	d := &compactDecoder{buf: data, format: formatMsgpack}
+	d.token(t)
+	return d.finish("token")
+}
+
+// MarshalCBOR encodes the token with its governance state as CBOR
+func (t *RiftToken) MarshalCBOR() ([]byte, error) {
+	return t.AppendCBOR(nil)
+}
+
+// AppendCBOR appends the token's CBOR encoding to b
+func (t *RiftToken) AppendCBOR(b []byte) ([]byte, error) {
+	e := &compactEncoder{b: b, format: formatCBOR}
+	if err := e.token(t); err != nil {
+		return nil, err
+	}
+	return e.b, nil
+}
+
+// UnmarshalCBOR restores a token from CBOR, as UnmarshalMsgpack does
+func (t *RiftToken) UnmarshalCBOR(data []byte) error {
+	d := &compactDecoder{buf: data, format: formatCBOR}
+	d.token(t)
+	return d.finish("token")
+}
+
+// MarshalMsgpack encodes the match result, with its States, as MessagePack
+func (r MatchResult) MarshalMsgpack() ([]byte, error) {
+	return r.AppendMsgpack(nil), nil
+}
+
+// AppendMsgpack appends the match result's MessagePack encoding to b.
+// Results nested deeper than any engine produces are cut off.
+func (r MatchResult) AppendMsgpack(b []byte) []byte {
+	e := &compactEncoder{b: b, format: formatMsgpack}
+	e.match(&r)
+	return e.b
+}
+
+// UnmarshalMsgpack restores a match result from MessagePack
+func (r *MatchResult) UnmarshalMsgpack(data []byte) error {
+	d := &compactDecoder{buf: data, format: formatMsgpack}
+	d.match(r)
+	return d.finish("match result")
+}
+
+// MarshalCBOR encodes the match result, with its States, as CBOR
+func (r MatchResult) MarshalCBOR() ([]byte, error) {
+	return r.AppendCBOR(nil), nil
+}
+
+// AppendCBOR appends the match result's CBOR encoding to b, as
+// AppendMsgpack does
+func (r MatchResult) AppendCBOR(b []byte) []byte {
+	e := &compactEncoder{b: b, format: formatCBOR}
+	e.match(&r)
+	return e.b
+}
+
+// UnmarshalCBOR restores a match result from CBOR
+func (r *MatchResult) UnmarshalCBOR(data []byte) error {
+	d := &compactDecoder{buf: data, format: formatCBOR}
+	d.match(r)
+	return d.finish("match result")
+}
+
+// ============================================================================
+// Encoding
+// ============================================================================
+
+// compactEncoder appends MessagePack or CBOR items to b
+type compactEncoder struct {
+	b      []byte
+	format compactFormat
+	depth  int
+}
+
+func (e *compactEncoder) token(t *RiftToken) error {
+	if e.depth++; e.depth > compactMaxDepth {
+		return fmt.Errorf("%s: tokens nested deeper than %d", e.format, compactMaxDepth)
+	}
+	defer func() { e.depth-- }()
+
+	at, n := e.beginMap(), 0
+	e.key(&n, "type")
+	e.int(int64(t.Type))
+	e.key(&n, "value")
+	if err := e.value(&t.Value); err != nil {
+		return err
+	}
+	if t.Memory != nil {
+		e.key(&n, "memory")
+		e.span(t.Memory)
+	}
+	e.key(&n, "validationBits")
+	e.uint(uint64(t.ValidationBits.Load()))
+	if len(t.SuperposedStates) > 0 {
+		e.key(&n, "superposedStates")
+		if err := e.tokens(t.SuperposedStates); err != nil {
+			return err
+		}
+	}
+	if len(t.Amplitudes) > 0 {
+		e.key(&n, "amplitudes")
+		e.floats(t.Amplitudes)
+	}
+	if len(t.Phases) > 0 {
+		e.key(&n, "phases")
+		e.floats(t.Phases)
+	}
+	if t.Phase != 0 {
+		e.key(&n, "phase")
+		e.float(t.Phase)
+	}
+	if t.EntanglementCount != 0 {
+		e.key(&n, "entanglementCount")
+		e.uint(uint64(t.EntanglementCount))
+	}
+	if t.EntanglementID != 0 {
+		e.key(&n, "entanglementId")
+		e.uint(uint64(t.EntanglementID))
+	}
+	if t.SourceLine != 0 {
+		e.key(&n, "sourceLine")
+		e.uint(uint64(t.SourceLine))
+	}
+	if t.SourceColumn != 0 {
+		e.key(&n, "sourceColumn")
+		e.uint(uint64(t.SourceColumn))
+	}
+	if t.SourceFile != "" {
+		e.key(&n, "sourceFile")
+		e.str(t.SourceFile)
+	}
+	e.endMap(at, n)
+	return nil
+}
+
+func (e *compactEncoder) value(v *RiftTokenValue) error {
+	at, n := e.beginMap(), 0
+	if v.IntVal != 0 {
+		e.key(&n, "int")
+		e.int(v.IntVal)
+	}
+	if v.FloatVal != 0 {
+		e.key(&n, "float")
+		e.float(v.FloatVal)
+	}
+	if v.StringVal != "" {
+		e.key(&n, "string")
+		e.str(v.StringVal)
+	}
+	if v.PtrVal != nil {
+		ptr, err := json.Marshal(v.PtrVal)
+		if err != nil {
+			return fmt.Errorf("encode pointer value: %w", err)
+		}
+		e.key(&n, "ptr")
+		e.str(string(ptr))
+	}
+	if len(v.ArrVal) > 0 {
+		e.key(&n, "arr")
+		if err := e.tokens(v.ArrVal); err != nil {
+			return err
+		}
+	}
+	e.endMap(at, n)
+	return nil
+}
+
+func (e *compactEncoder) span(s *RiftMemorySpan) {
+	at, n := e.beginMap(), 0
+	e.key(&n, "type")
+	e.int(int64(s.Type))
+	e.key(&n, "bytes")
+	e.uint(s.Bytes)
+	e.key(&n, "alignment")
+	e.uint(uint64(s.Alignment))
+	e.key(&n, "open")
+	e.bool(s.Open)
+	e.key(&n, "direction")
+	e.bool(s.Direction)
+	e.key(&n, "accessMask")
+	e.uint(uint64(s.Mask()))
+	e.endMap(at, n)
+}
+
+func (e *compactEncoder) tokens(tokens []*RiftToken) error {
+	e.arrayLen(len(tokens))
+	for _, tok := range tokens {
+		if tok == nil {
+			e.nil()
+			continue
+		}
+		if err := e.token(tok); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (e *compactEncoder) floats(fs []float64) {
+	e.arrayLen(len(fs))
+	for _, f := range fs {
+		e.float(f)
+	}
+}
+
+func (e *compactEncoder) match(r *MatchResult) {
+	at, n := e.beginMap(), 0
+	e.key(&n, "matched")
+	e.bool(r.Matched)
+	if r.Output != "" {
+		e.key(&n, "output")
+		e.str(r.Output)
+	}
+	if r.Priority != 0 {
+		e.key(&n, "priority")
+		e.uint(uint64(r.Priority))
+	}
+	if r.TransformID != 0 {
+		e.key(&n, "transformId")
+		e.uint(uint64(r.TransformID))
+	}
+	if r.Groups != nil {
+		e.key(&n, "groups")
+		e.mapLen(len(r.Groups))
+		for name, text := range r.Groups {
+			e.str(name)
+			e.str(text)
+		}
+	}
+	if r.Start != 0 {
+		e.key(&n, "start")
+		e.int(int64(r.Start))
+	}
+	if r.End != 0 {
+		e.key(&n, "end")
+		e.int(int64(r.End))
+	}
+	if r.Text != "" {
+		e.key(&n, "text")
+		e.str(r.Text)
+	}
+	if len(r.States) > 0 && e.depth < compactMaxDepth {
+		e.key(&n, "states")
+		e.depth++
+		e.arrayLen(len(r.States))
+		for i := range r.States {
+			e.match(&r.States[i])
+		}
+		e.depth--
+	}
+	if len(r.Amplitudes) > 0 {
+		e.key(&n, "amplitudes")
+		e.floats(r.Amplitudes)
+	}
+	if r.Version != 0 {
+		e.key(&n, "version")
+		e.uint(r.Version)
+	}
+	e.endMap(at, n)
+}
+
+// beginMap reserves a one-byte map header, patched by endMap once the
+// fields present are counted; the maps written this way have at most 15
+// entries, which both formats hold in one byte
+func (e *compactEncoder) beginMap() int {
+	e.b = append(e.b, 0)
+	return len(e.b) - 1
+}
+
+func (e *compactEncoder) endMap(at, n int) {
+	if e.format == formatCBOR {
+		e.b[at] = 0xa0 | byte(n)
+	} else {
+		e.b[at] = 0x80 | byte(n)
+	}
+}
+
+// key writes a field name, counting the field
+func (e *compactEncoder) key(n *int, name string) {
+	*n++
+	e.str(name)
+}
+
+// head writes a CBOR major type and its argument
+func (e *compactEncoder) head(major byte, v uint64) {
+	major <<= 5
+	switch {
+	case v < 24:
+		e.b = append(e.b, major|byte(v))
+	case v <= math.MaxUint8:
+		e.b = append(e.b, major|24, byte(v))
+	case v <= math.MaxUint16:
+		e.b = binary.BigEndian.AppendUint16(append(e.b, major|25), uint16(v))
+	case v <= math.MaxUint32:
+		e.b = binary.BigEndian.AppendUint32(append(e.b, major|26), uint32(v))
+	default:
+		e.b = binary.BigEndian.AppendUint64(append(e.b, major|27), v)
+	}
+}
+
+// sized writes a MessagePack length: in the fix byte when it is under
+// limit, else after the 16- or 32-bit type byte
+func (e *compactEncoder) sized(fix byte, limit int, b16, b32 byte, n int) {
+	switch {
+	case n < limit:
+		e.b = append(e.b, fix|byte(n))
+	case n <= math.MaxUint16:
+		e.b = binary.BigEndian.AppendUint16(append(e.b, b16), uint16(n))
+	default:
+		e.b = binary.BigEndian.AppendUint32(append(e.b, b32), uint32(n))
+	}
+}
+
+func (e *compactEncoder) mapLen(n int) {
+	if e.format == formatCBOR {
+		e.head(5, uint64(n))
+		return
+	}
+	e.sized(0x80, 16, 0xde, 0xdf, n)
+}
+
+func (e *compactEncoder) arrayLen(n int) {
+	if e.format == formatCBOR {
+		e.head(4, uint64(n))
+		return
+	}
+	e.sized(0x90, 16, 0xdc, 0xdd, n)
+}
+
+func (e *compactEncoder) str(s string) {
+	switch {
+	case e.format == formatCBOR:
+		e.head(3, uint64(len(s)))
+	case len(s) < 32:
+		e.b = append(e.b, 0xa0|byte(len(s)))
+	case len(s) <= math.MaxUint8:
+		e.b = append(e.b, 0xd9, byte(len(s)))
+	default:
+		e.sized(0, 0, 0xda, 0xdb, len(s))
+	}
+	e.b = append(e.b, s...)
+}
+
+func (e *compactEncoder) uint(v uint64) {
+	if e.format == formatCBOR {
+		e.head(0, v)
+		return
+	}
+	switch {
+	case v < 0x80:
+		e.b = append(e.b, byte(v))
+	case v <= math.MaxUint8:
+		e.b = append(e.b, 0xcc, byte(v))
+	case v <= math.MaxUint16:
+		e.b = binary.BigEndian.AppendUint16(append(e.b, 0xcd), uint16(v))
+	case v <= math.MaxUint32:
+		e.b = binary.BigEndian.AppendUint32(append(e.b, 0xce), uint32(v))
+	default:
+		e.b = binary.BigEndian.AppendUint64(append(e.b, 0xcf), v)
+	}
+}
+
+func (e *compactEncoder) int(v int64) {
+	switch {
+	case v >= 0:
+		e.uint(uint64(v))
+	case e.format == formatCBOR:
+		e.head(1, uint64(-1-v))
+	case v >= -32:
+		e.b = append(e.b, byte(v))
+	case v >= math.MinInt8:
+		e.b = append(e.b, 0xd0, byte(v))
+	case v >= math.MinInt16:
+		e.b = binary.BigEndian.AppendUint16(append(e.b, 0xd1), uint16(v))
+	case v >= math.MinInt32:
+		e.b = binary.BigEndian.AppendUint32(append(e.b, 0xd2), uint32(v))
+	default:
+		e.b = binary.BigEndian.AppendUint64(append(e.b, 0xd3), uint64(v))
+	}
+}
+
+func (e *compactEncoder) float(f float64) {
+	tag := byte(0xcb)
+	if e.format == formatCBOR {
+		tag = 0xfb
+	}
+	e.b = binary.BigEndian.AppendUint64(append(e.b, tag), math.Float64bits(f))
+}
+
+func (e *compactEncoder) bool(v bool) {
+	var b byte = 0xc2
+	if e.format == formatCBOR {
+		b = 0xf4
+	}
+	if v {
+		b++
+	}
+	e.b = append(e.b, b)
+}
+
+func (e *compactEncoder) nil() {
+	if e.format == formatCBOR {
+		e.b = append(e.b, 0xf6)
+	} else {
+		e.b = append(e.b, 0xc0)
+	}
+}
+
+// ============================================================================
+// Decoding
+// ============================================================================
+
+// compactKind is the kind of a decoded item
+type compactKind uint8
+
+const (
+	kindNil compactKind = iota
+	kindBool
+	kindInt // negative, or signed in MessagePack
+	kindUint
+	kindFloat
+	kindString
+	kindBytes
+	kindArray
+	kindMap
+)
+
+func (k compactKind) String() string {
+	return [...]string{"nil", "bool", "integer", "integer", "float", "string", "bytes", "array", "map"}[k]
+}
+
+// compactHead is an item's kind and its value, or its length for strings,
+// bytes, arrays and maps
+type compactHead struct {
+	kind compactKind
+	n    uint64 // uint value, length, or 1 for true
+	i    int64
+	f    float64
+}
+
+// compactDecoder decodes MessagePack or CBOR with a sticky error
+type compactDecoder struct {
+	buf    []byte
+	format compactFormat
+	err    error
+	depth  int
+}
+
+func (d *compactDecoder) fail(format string, args ...interface{}) {
+	if d.err == nil {
+		d.err = fmt.Errorf("%s: %s", d.format, fmt.Sprintf(format, args...))
+	}
+}
+
+// finish reports the decoding error, or data left after the item
+func (d *compactDecoder) finish(what string) error {
+	if d.err == nil && len(d.buf) != 0 {
+		d.fail("trailing %d bytes after %s", len(d.buf), what)
+	}
+	return d.err
+}
+
+// enter counts a level of nesting, failing past compactMaxDepth
+func (d *compactDecoder) enter() bool {
+	if d.depth++; d.depth > compactMaxDepth {
+		d.fail("items nested deeper than %d", compactMaxDepth)
+		return false
+	}
+	return d.err == nil
+}
+
+func (d *compactDecoder) leave() {
+	d.depth--
+}
+
+func (d *compactDecoder) bytes(n uint64) []byte {
+	if d.err != nil {
+		return nil
+	}
+	if uint64(len(d.buf)) < n {
+		d.fail("truncated data: need %d bytes, have %d", n, len(d.buf))
+		return nil
+	}
+	out := d.buf[:n]
+	d.buf = d.buf[n:]
+	return out
+}
+
+// be reads a size-byte big-endian unsigned integer
+func (d *compactDecoder) be(size int) uint64 {
+	var v uint64
+	for _, c := range d.bytes(uint64(size)) {
+		v = v<<8 | uint64(c)
+	}
+	return v
+}
+
+// head reads the next item's head, leaving a string's or bytes' contents
+// and the items of an array or map to be read
+func (d *compactDecoder) head() compactHead {
+	b := d.bytes(1)
+	if b == nil {
+		return compactHead{}
+	}
+	if d.format == formatCBOR {
+		return d.cborHead(b[0])
+	}
+	return d.msgpackHead(b[0])
+}
+
+func (d *compactDecoder) msgpackHead(b byte) compactHead {
+	switch {
+	case b < 0x80:
+		return compactHead{kind: kindUint, n: uint64(b)}
+	case b >= 0xe0:
+		return compactHead{kind: kindInt, i: int64(int8(b))}
+	case b < 0x90:
+		return compactHead{kind: kindMap, n: uint64(b & 0x0f)}
+	case b < 0xa0:
+		return compactHead{kind: kindArray, n: uint64(b & 0x0f)}
+	case b < 0xc0:
+		return compactHead{kind: kindString, n: uint64(b & 0x1f)}
+	}
+	switch b {
+	case 0xc0:
+		return compactHead{kind: kindNil}
+	case 0xc2, 0xc3:
+		return compactHead{kind: kindBool, n: uint64(b - 0xc2)}
+	case 0xc4, 0xc5, 0xc6:
+		return compactHead{kind: kindBytes, n: d.be(1 << (b - 0xc4))}
+	case 0xca:
+		return compactHead{kind: kindFloat, f: float64(math.Float32frombits(uint32(d.be(4))))}
+	case 0xcb:
+		return compactHead{kind: kindFloat, f: math.Float64frombits(d.be(8))}
+	case 0xcc, 0xcd, 0xce, 0xcf:
+		return compactHead{kind: kindUint, n: d.be(1 << (b - 0xcc))}
+	case 0xd0, 0xd1, 0xd2, 0xd3:
+		shift := 64 - 8<<(b-0xd0)
+		return compactHead{kind: kindInt, i: int64(d.be(1<<(b-0xd0))<<shift) >> shift}
+	case 0xd9, 0xda, 0xdb:
+		return compactHead{kind: kindString, n: d.be(1 << (b - 0xd9))}
+	case 0xdc, 0xdd:
+		return compactHead{kind: kindArray, n: d.be(2 << (b - 0xdc))}
+	case 0xde, 0xdf:
+		return compactHead{kind: kindMap, n: d.be(2 << (b - 0xde))}
+	}
+	d.fail("unsupported type byte 0x%02x", b)
+	return compactHead{}
+}
+
+func (d *compactDecoder) cborHead(b byte) compactHead {
+	for b>>5 == 6 { // tags are skipped
+		if d.cborArg(b); d.err != nil {
+			return compactHead{}
+		}
+		next := d.bytes(1)
+		if next == nil {
+			return compactHead{}
+		}
+		b = next[0]
+	}
+	major, arg := b>>5, d.cborArg(b)
+	switch major {
+	case 0:
+		return compactHead{kind: kindUint, n: arg}
+	case 1:
+		if arg > math.MaxInt64 {
+			d.fail("integer -1-%d overflows int64", arg)
+			return compactHead{}
+		}
+		return compactHead{kind: kindInt, i: -1 - int64(arg)}
+	case 2:
+		return compactHead{kind: kindBytes, n: arg}
+	case 3:
+		return compactHead{kind: kindString, n: arg}
+	case 4:
+		return compactHead{kind: kindArray, n: arg}
+	case 5:
+		return compactHead{kind: kindMap, n: arg}
+	}
+	switch info := b & 0x1f; info {
+	case 20, 21:
+		return compactHead{kind: kindBool, n: uint64(info - 20)}
+	case 22, 23:
+		return compactHead{kind: kindNil}
+	case 25:
+		return compactHead{kind: kindFloat, f: halfFloat(uint16(arg))}
+	case 26:
+		return compactHead{kind: kindFloat, f: float64(math.Float32frombits(uint32(arg)))}
+	case 27:
+		return compactHead{kind: kindFloat, f: math.Float64frombits(arg)}
+	}
+	d.fail("unsupported simple value 0x%02x", b)
+	return compactHead{}
+}
+
+// cborArg reads the argument following a CBOR initial byte
+func (d *compactDecoder) cborArg(b byte) uint64 {
+	switch info := b & 0x1f; {
+	case info < 24:
+		return uint64(info)
+	case info <= 27:
+		return d.be(1 << (info - 24))
+	case info == 31:
+		d.fail("indefinite-length items are not supported")
+	default:
+		d.fail("reserved initial byte 0x%02x", b)
+	}
+	return 0
+}
+
+// halfFloat converts an IEEE 754 half-precision float
+func halfFloat(h uint16) float64 {
+	exp, mant := int(h>>10&0x1f), float64(h&0x3ff)
+	var f float64
+	switch exp {
+	case 0:
+		f = math.Ldexp(mant, -24)
+	case 0x1f:
+		f = math.Inf(1)
+		if mant != 0 {
+			f = math.NaN()
+		}
+	default:
+		f = math.Ldexp(mant+1024, exp-25)
+	}
+	if h&0x8000 != 0 {
+		f = -f
+	}
+	return f
+}
+
+func (d *compactDecoder) mismatch(h compactHead, what, want string) {
+	if d.err == nil {
+		d.fail("%s: expected %s, found %s", what, want, h.kind)
+	}
+}
+
+func (d *compactDecoder) uint(what string) uint64 {
+	switch h := d.head(); {
+	case h.kind == kindUint:
+		return h.n
+	case h.kind == kindInt && h.i >= 0:
+		return uint64(h.i)
+	default:
+		d.mismatch(h, what, "unsigned integer")
+	}
+	return 0
+}
+
+func (d *compactDecoder) uint32(what string) uint32 {
+	v := d.uint(what)
+	if v > math.MaxUint32 {
+		d.fail("%s: %d overflows uint32", what, v)
+	}
+	return uint32(v)
+}
+
+func (d *compactDecoder) int(what string) int64 {
+	switch h := d.head(); {
+	case h.kind == kindInt:
+		return h.i
+	case h.kind == kindUint && h.n <= math.MaxInt64:
+		return int64(h.n)
+	default:
+		d.mismatch(h, what, "integer")
+	}
+	return 0
+}
+
+func (d *compactDecoder) float(what string) float64 {
+	switch h := d.head(); h.kind {
+	case kindFloat:
+		return h.f
+	case kindInt:
+		return float64(h.i)
+	case kindUint:
+		return float64(h.n)
+	default:
+		d.mismatch(h, what, "number")
+	}
+	return 0
+}
+
+func (d *compactDecoder) bool(what string) bool {
+	h := d.head()
+	if h.kind != kindBool {
+		d.mismatch(h, what, "bool")
+	}
+	return h.n == 1
+}
+
+func (d *compactDecoder) str(what string) string {
+	h := d.head()
+	if h.kind != kindString {
+		d.mismatch(h, what, "string")
+		return ""
+	}
+	return string(d.bytes(h.n))
+}
+
+// count checks that a collection of n items, each at least per bytes,
+// fits in the data left
+func (d *compactDecoder) count(n, per uint64, what string) int {
+	if d.err != nil {
+		return 0
+	}
+	if n > uint64(len(d.buf))/per {
+		d.fail("%s count %d exceeds data", what, n)
+		return 0
+	}
+	return int(n)
+}
+
+func (d *compactDecoder) mapLen(what string) int {
+	h := d.head()
+	if h.kind != kindMap {
+		d.mismatch(h, what, "map")
+		return 0
+	}
+	return d.count(h.n, 2, what)
+}
+
+func (d *compactDecoder) arrayLen(what string) int {
+	h := d.head()
+	if h.kind != kindArray {
+		d.mismatch(h, what, "array")
+		return 0
+	}
+	return d.count(h.n, 1, what)
+}
+
+// nil consumes a nil item if one is next
+func (d *compactDecoder) nil() bool {
+	if d.err != nil || len(d.buf) == 0 {
+		return false
+	}
+	switch c := d.buf[0]; {
+	case d.format == formatMsgpack && c == 0xc0,
+		d.format == formatCBOR && (c == 0xf6 || c == 0xf7):
+		d.buf = d.buf[1:]
+		return true
+	}
+	return false
+}
+
+// skip reads past an item of any kind, for keys the decoder does not know
+func (d *compactDecoder) skip() {
+	if !d.enter() {
+		return
+	}
+	defer d.leave()
+	switch h := d.head(); h.kind {
+	case kindString, kindBytes:
+		d.bytes(h.n)
+	case kindArray:
+		for n := d.count(h.n, 1, "array"); n > 0 && d.err == nil; n-- {
+			d.skip()
+		}
+	case kindMap:
+		for n := d.count(h.n, 2, "map"); n > 0 && d.err == nil; n-- {
+			d.skip()
+			d.skip()
+		}
+	}
+}
+
+func (d *compactDecoder) token(t *RiftToken) {
+	if !d.enter() {
+		return
+	}
+	defer d.leave()
+
+	var (
+		in     tokenJSON
+		value  RiftTokenValue
+		memory *RiftMemorySpan
+	)
+	for n := d.mapLen("token"); n > 0 && d.err == nil; n-- {
+		switch key := d.str("token key"); key {
+		case "type":
+			in.Type = int(d.int(key))
+		case "value":
+			d.value(&value)
+		case "memory":
+			memory = d.span()
+		case "validationBits":
+			in.ValidationBits = d.uint32(key)
+		case "superposedStates":
+			in.SuperposedStates = d.tokens(key)
+		case "amplitudes":
+			in.Amplitudes = d.floats(key)
+		case "phases":
+			in.Phases = d.floats(key)
+		case "phase":
+			in.Phase = d.float(key)
+		case "entanglementCount":
+			in.EntanglementCount = d.uint32(key)
+		case "entanglementId":
+			in.EntanglementID = d.uint32(key)
+		case "sourceLine":
+			in.SourceLine = d.uint32(key)
+		case "sourceColumn":
+			in.SourceColumn = d.uint32(key)
+		case "sourceFile":
+			in.SourceFile = d.str(key)
+		default:
+			d.skip()
+		}
+	}
+	if d.err != nil {
+		return
+	}
+
+	t.Type = in.Type
+	t.storeValue(value)
+	t.Memory = memory
+	t.ValidationBits.Store(in.ValidationBits &^ TokenLocked)
+	t.SuperposedStates = in.SuperposedStates
+	t.SuperpositionCount = uint32(len(in.SuperposedStates))
+	t.Amplitudes = in.Amplitudes
+	t.Phases = in.Phases
+	t.Phase = in.Phase
+	t.EntangledWith = nil
+	t.EntanglementCount = in.EntanglementCount
+	t.EntanglementID = in.EntanglementID
+	t.SourceLine = in.SourceLine
+	t.SourceColumn = in.SourceColumn
+	t.SourceFile = in.SourceFile
+	t.reseal()
+}
+
+func (d *compactDecoder) value(v *RiftTokenValue) {
+	for n := d.mapLen("value"); n > 0 && d.err == nil; n-- {
+		switch key := d.str("value key"); key {
+		case "int":
+			v.IntVal = d.int(key)
+		case "float":
+			v.FloatVal = d.float(key)
+		case "string":
+			v.StringVal = d.str(key)
+		case "ptr":
+			if ptr := d.str(key); ptr != "" && d.err == nil {
+				if err := json.Unmarshal([]byte(ptr), &v.PtrVal); err != nil {
+					d.fail("decode pointer value: %v", err)
+				}
+			}
+		case "arr":
+			v.ArrVal = d.tokens(key)
+		default:
+			d.skip()
+		}
+	}
+}
+
+func (d *compactDecoder) span() *RiftMemorySpan {
+	if d.nil() {
+		return nil
+	}
+	s := &RiftMemorySpan{}
+	for n := d.mapLen("memory"); n > 0 && d.err == nil; n-- {
+		switch key := d.str("memory key"); key {
+		case "type":
+			s.Type = int(d.int(key))
+		case "bytes":
+			s.Bytes = d.uint(key)
+		case "alignment":
+			s.Alignment = d.uint32(key)
+		case "open":
+			s.Open = d.bool(key)
+		case "direction":
+			s.Direction = d.bool(key)
+		case "accessMask":
+			s.AccessMask = d.uint32(key)
+		default:
+			d.skip()
+		}
+	}
+	return s
+}
+
+func (d *compactDecoder) tokens(what string) []*RiftToken {
+	n := d.arrayLen(what)
+	if n == 0 {
+		return nil
+	}
+	out := make([]*RiftToken, n)
+	for i := range out {
+		if d.nil() {
+			continue
+		}
+		out[i] = &RiftToken{}
+		if d.token(out[i]); d.err != nil {
+			return nil
+		}
+	}
+	return out
+}
+
+func (d *compactDecoder) floats(what string) []float64 {
+	n := d.arrayLen(what)
+	if n == 0 {
+		return nil
+	}
+	out := make([]float64, n)
+	for i := range out {
+		out[i] = d.float(what)
+	}
+	return out
+}
+
+func (d *compactDecoder) match(r *MatchResult) {
+	if !d.enter() {
+		return
+	}
+	defer d.leave()
+
+	var out MatchResult
+	for n := d.mapLen("match result"); n > 0 && d.err == nil; n-- {
+		switch key := d.str("match result key"); key {
+		case "matched":
+			out.Matched = d.bool(key)
+		case "output":
+			out.Output = d.str(key)
+		case "priority":
+			out.Priority = d.uint32(key)
+		case "transformId":
+			out.TransformID = d.uint32(key)
+		case "groups":
+			if d.nil() {
+				break
+			}
+			m := d.mapLen(key)
+			out.Groups = make(map[string]string, m)
+			for ; m > 0 && d.err == nil; m-- {
+				name := d.str("group name")
+				out.Groups[name] = d.str(key)
+			}
+		case "start":
+			out.Start = int(d.int(key))
+		case "end":
+			out.End = int(d.int(key))
+		case "text":
+			out.Text = d.str(key)
+		case "states":
+			if m := d.arrayLen(key); m > 0 {
+				out.States = make([]MatchResult, m)
+				for i := range out.States {
+					d.match(&out.States[i])
+				}
+			}
+		case "amplitudes":
+			out.Amplitudes = d.floats(key)
+		case "version":
+			out.Version = d.uint(key)
+		default:
+			d.skip()
+		}
+	}
+	if d.err == nil {
+		*r = out
+	}
+}
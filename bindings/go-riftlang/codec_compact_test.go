@@ -0,0 +1,205 @@
+package rift
+
+import (
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// compactCodec is one of the compact formats, for table tests
+type compactCodec struct {
+	name        string
+	marshal     func(*RiftToken) ([]byte, error)
+	unmarshal   func(*RiftToken, []byte) error
+	marshalM    func(MatchResult) ([]byte, error)
+	unmarshalM  func(*MatchResult, []byte) error
+	indefinite  []byte // an indefinite-length map, or nil
+	wrongType   []byte // an integer where a map belongs
+	deepNesting []byte // a map of maps nested past compactMaxDepth
+}
+
+func compactCodecs() []compactCodec {
+	nest := func(open, key byte) []byte {
+		var b []byte
+		for range compactMaxDepth + 2 {
+			b = append(b, open, key, 'x')
+		}
+		return b
+	}
+	return []compactCodec{
+		{
+			name:        "msgpack",
+			marshal:     (*RiftToken).MarshalMsgpack,
+			unmarshal:   (*RiftToken).UnmarshalMsgpack,
+			marshalM:    MatchResult.MarshalMsgpack,
+			unmarshalM:  (*MatchResult).UnmarshalMsgpack,
+			wrongType:   []byte{0x2a},
+			deepNesting: nest(0x81, 0xa1), // fixmap(1), fixstr(1)
+		},
+		{
+			name:        "cbor",
+			marshal:     (*RiftToken).MarshalCBOR,
+			unmarshal:   (*RiftToken).UnmarshalCBOR,
+			marshalM:    MatchResult.MarshalCBOR,
+			unmarshalM:  (*MatchResult).UnmarshalCBOR,
+			indefinite:  []byte{0xbf, 0xff},
+			wrongType:   []byte{0x18, 0x2a},
+			deepNesting: nest(0xa1, 0x61), // map(1), text(1)
+		},
+	}
+}
+
+func TestCompactTokenRoundTrip(t *testing.T) {
+	long := strings.Repeat("héllo ", 12000) // past 16-bit string lengths
+	values := []struct {
+		name      string
+		tokenType int
+		value     RiftTokenValue
+	}{
+		{"zero", TokenGoInt, RiftTokenValue{IntVal: 0}},
+		{"fixint", TokenGoInt, RiftTokenValue{IntVal: -32}},
+		{"int8", TokenGoInt, RiftTokenValue{IntVal: -33}},
+		{"int8 min", TokenGoInt, RiftTokenValue{IntVal: -128}},
+		{"int16", TokenGoInt, RiftTokenValue{IntVal: -129}},
+		{"int32", TokenGoInt, RiftTokenValue{IntVal: -32769}},
+		{"int64", TokenGoInt, RiftTokenValue{IntVal: -1 << 40}},
+		{"int64 min", TokenGoInt, RiftTokenValue{IntVal: math.MinInt64}},
+		{"int64 max", TokenGoInt, RiftTokenValue{IntVal: math.MaxInt64}},
+		{"uint16", TokenGoInt, RiftTokenValue{IntVal: 65535}},
+		{"float", TokenGoFloat, RiftTokenValue{FloatVal: -1.5e-300}},
+		{"infinity", TokenGoFloat, RiftTokenValue{FloatVal: math.Inf(-1)}},
+		{"short string", TokenGoString, RiftTokenValue{StringVal: "héllo"}},
+		{"long string", TokenGoString, RiftTokenValue{StringVal: long}},
+	}
+	for _, c := range compactCodecs() {
+		for _, v := range values {
+			t.Run(c.name+"/"+v.name, func(t *testing.T) {
+				src := NewRiftToken(v.tokenType, NewRiftMemorySpan(SpanFixed, 64))
+				if err := src.SetValue(v.value); err != nil {
+					t.Fatal(err)
+				}
+				data, err := c.marshal(src)
+				if err != nil {
+					t.Fatal(err)
+				}
+				var dst RiftToken
+				if err := c.unmarshal(&dst, data); err != nil {
+					t.Fatal(err)
+				}
+				if dst.Type != src.Type || !reflect.DeepEqual(dst.Value, src.Value) {
+					t.Errorf("decoded %s %+v, want %s %+v", TokenTypeName(dst.Type), dst.Value, TokenTypeName(src.Type), src.Value)
+				}
+				if dst.Memory == nil || dst.Memory.Bytes != 64 || dst.Memory.Mask() != AccessCRUD {
+					t.Errorf("decoded span %+v", dst.Memory)
+				}
+			})
+		}
+
+		t.Run(c.name+"/superposed", func(t *testing.T) {
+			src := Superpose(int64(-5), "two", 3.5)
+			data, err := c.marshal(src)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var dst RiftToken
+			if err := c.unmarshal(&dst, data); err != nil {
+				t.Fatal(err)
+			}
+			if len(dst.SuperposedStates) != 3 || !reflect.DeepEqual(dst.Amplitudes, src.Amplitudes) {
+				t.Fatalf("decoded %d states with amplitudes %v", len(dst.SuperposedStates), dst.Amplitudes)
+			}
+			for i, s := range dst.SuperposedStates {
+				if want := src.SuperposedStates[i]; s.Type != want.Type || !reflect.DeepEqual(s.Value, want.Value) {
+					t.Errorf("state %d = %+v, want %+v", i, s.Value, want.Value)
+				}
+			}
+		})
+	}
+}
+
+func TestCompactMatchRoundTrip(t *testing.T) {
+	src := MatchResult{
+		Matched:     true,
+		Output:      strings.Repeat("out", 100),
+		Priority:    7,
+		TransformID: 3,
+		Groups:      map[string]string{"name": "x", "value": ""},
+		Start:       2,
+		End:         40,
+		Text:        "matched",
+		Amplitudes:  []float64{0.6, -0.8},
+		States: []MatchResult{
+			{Matched: true, Output: "a", Priority: 7, TransformID: 3, End: 1, Text: "a"},
+			{Matched: true, Output: "b", Priority: 1, TransformID: 4, Groups: map[string]string{"k": "v"}},
+		},
+		Version: 1 << 40,
+	}
+	for _, c := range compactCodecs() {
+		t.Run(c.name, func(t *testing.T) {
+			data, err := c.marshalM(src)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var dst MatchResult
+			if err := c.unmarshalM(&dst, data); err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(dst, src) {
+				t.Errorf("decoded\n%+v\nwant\n%+v", dst, src)
+			}
+		})
+	}
+}
+
+// TestCompactMalformed decodes broken input: every truncation of a valid
+// encoding, trailing bytes, a value of the wrong kind, unsupported
+// indefinite lengths and nesting past the limit must fail, not panic
+func TestCompactMalformed(t *testing.T) {
+	src := Superpose(int64(1), "two")
+	src.Value.StringVal = strings.Repeat("s", 300)
+	match := MatchResult{Matched: true, Output: "o", Groups: map[string]string{"g": "v"}, States: []MatchResult{{Output: "s"}}}
+	for _, c := range compactCodecs() {
+		t.Run(c.name, func(t *testing.T) {
+			data, err := c.marshal(src)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for n := range len(data) {
+				var dst RiftToken
+				if err := c.unmarshal(&dst, data[:n]); err == nil {
+					t.Fatalf("token truncated to %d of %d bytes decoded", n, len(data))
+				}
+			}
+			mdata, err := c.marshalM(match)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for n := range len(mdata) {
+				var dst MatchResult
+				if err := c.unmarshalM(&dst, mdata[:n]); err == nil {
+					t.Fatalf("match truncated to %d of %d bytes decoded", n, len(mdata))
+				}
+			}
+
+			bad := map[string][]byte{
+				"trailing byte": append(append([]byte(nil), data...), 0),
+				"wrong type":    c.wrongType,
+				"deep nesting":  c.deepNesting,
+			}
+			if c.indefinite != nil {
+				bad["indefinite length"] = c.indefinite
+			}
+			for name, b := range bad {
+				var dst RiftToken
+				if err := c.unmarshal(&dst, b); err == nil {
+					t.Errorf("%s: token decoded", name)
+				}
+				var m MatchResult
+				if err := c.unmarshalM(&m, b); err == nil {
+					t.Errorf("%s: match decoded", name)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,72 @@
+package rift
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+// TestUnmarshalIntoSealedToken decodes into a token already sealed under
+// integrity mode: the decoded value is a governed write, not tampering
+func TestUnmarshalIntoSealedToken(t *testing.T) {
+	SetIntegrityMode(true)
+	t.Cleanup(func() { SetIntegrityMode(false) })
+
+	src := NewRiftToken(TokenGoString, NewRiftMemorySpan(SpanFixed, 64))
+	if err := src.SetValue(RiftTokenValue{StringVal: "decoded"}); err != nil {
+		t.Fatal(err)
+	}
+	jsonData, err := json.Marshal(src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	binData, err := src.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	msgpackData, err := src.MarshalMsgpack()
+	if err != nil {
+		t.Fatal(err)
+	}
+	cborData, err := src.MarshalCBOR()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	codecs := []struct {
+		name   string
+		decode func(*RiftToken) error
+	}{
+		{"json", func(tok *RiftToken) error { return json.Unmarshal(jsonData, tok) }},
+		{"binary", func(tok *RiftToken) error { return tok.UnmarshalBinary(binData) }},
+		{"msgpack", func(tok *RiftToken) error { return tok.UnmarshalMsgpack(msgpackData) }},
+		{"cbor", func(tok *RiftToken) error { return tok.UnmarshalCBOR(cborData) }},
+	}
+	for _, c := range codecs {
+		t.Run(c.name, func(t *testing.T) {
+			tok := NewRiftToken(TokenGoInt, NewRiftMemorySpan(SpanFixed, 64))
+			if err := tok.SetValue(RiftTokenValue{IntVal: 1}); err != nil {
+				t.Fatal(err)
+			}
+			if err := tok.ValidateErr(); err != nil {
+				t.Fatal(err)
+			}
+			if !tok.Sealed() {
+				t.Fatal("token not sealed after validation")
+			}
+			before := tok.Version()
+
+			if err := c.decode(tok); err != nil {
+				t.Fatal(err)
+			}
+			if err := tok.ValidateErr(); err != nil {
+				t.Errorf("decoded token fails validation: %v", err)
+			}
+			if tok.Value.StringVal != "decoded" || tok.Type != TokenGoString {
+				t.Errorf("decoded %+v of type %d", tok.Value, tok.Type)
+			}
+			if tok.Version() <= before {
+				t.Errorf("version %d not bumped from %d", tok.Version(), before)
+			}
+		})
+	}
+}
@@ -0,0 +1,124 @@
+// go/target/collapse_partial.go
+// Partial Collapse and Amplitude Pruning - Go Implementation
+
+package rift
+
+import (
+	"fmt"
+	"math/cmplx"
+	"slices"
+)
+
+// ============================================================================
+// Partial Collapse
+// ============================================================================
+
+// CollapsePartial removes the states at indices from the superposition and
+// renormalizes the amplitudes of the rest, keeping their relative
+// magnitudes and phases: the token is as if measured and found not to be
+// in any of the removed states. Indices may repeat and come in any order.
+// A superposition left with a single state collapses to it, as Collapse
+// does; removing every state, or leaving only states of zero amplitude,
+// fails with the token unchanged. The entropy gate applies as it does to
+// Collapse, and the removal is audited as AuditCollapse.
+func (t *RiftToken) CollapsePartial(indices []uint32) error {
+	if !t.HasBit(TokenSuperposed) {
+		return govErr(CodeNotSuperposed, "collapse", "token not in superposition")
+	}
+	n := len(t.SuperposedStates)
+	remove := make([]bool, n)
+	removed := 0
+	for _, i := range indices {
+		if int(i) >= n {
+			return govErr(CodeIndexOutOfRange, "collapse", "index %d out of %d states", i, n)
+		}
+		if !remove[i] {
+			remove[i] = true
+			removed++
+		}
+	}
+	if removed == 0 {
+		return nil
+	}
+	if removed == n {
+		return govErr(CodeNoStates, "collapse", "removing all %d states", n)
+	}
+	if err := t.checkEntropyGate("collapse"); err != nil {
+		return err
+	}
+	return t.keepStates("collapse", remove, fmt.Sprintf("partial: removed %d of %d states", removed, n))
+}
+
+// Prune drops the states whose amplitude magnitude is below threshold and
+// renormalizes the rest as CollapsePartial does, returning how many it
+// dropped. Simulations exploring many branches call it to discard those
+// too improbable to matter; the probability dropped is at most
+// threshold² per state. If every state is below threshold Prune fails
+// with CodeZeroProbability, leaving the token unchanged.
+func (t *RiftToken) Prune(threshold float64) (int, error) {
+	if !t.HasBit(TokenSuperposed) {
+		return 0, govErr(CodeNotSuperposed, "prune", "token not in superposition")
+	}
+	amps := t.stateVector()
+	remove := make([]bool, len(amps))
+	removed := 0
+	for i, a := range amps {
+		if cmplx.Abs(a) < threshold {
+			remove[i] = true
+			removed++
+		}
+	}
+	if removed == 0 {
+		return 0, nil
+	}
+	if removed == len(amps) {
+		return 0, govErr(CodeZeroProbability, "prune", "all %d states have amplitude below %g", removed, threshold)
+	}
+	if err := t.checkEntropyGate("prune"); err != nil {
+		return 0, err
+	}
+	detail := fmt.Sprintf("pruned %d of %d states below amplitude %g", removed, len(amps), threshold)
+	if err := t.keepStates("prune", remove, detail); err != nil {
+		return 0, err
+	}
+	return removed, nil
+}
+
+// keepStates narrows the superposition to the states not marked in
+// remove, at least one, collapsing to a lone survivor
+func (t *RiftToken) keepStates(op string, remove []bool, detail string) error {
+	amps := t.stateVector()
+	var (
+		states []*RiftToken
+		kept   []complex128
+		last   int
+	)
+	for i, state := range t.SuperposedStates {
+		if !remove[i] {
+			states = append(states, state)
+			kept = append(kept, amps[i])
+			last = i
+		}
+	}
+	if complexNormSq(kept) <= 0 {
+		return govErr(CodeZeroProbability, op, "remaining %d states have zero amplitude", len(kept))
+	}
+
+	if len(states) == 1 {
+		if t.joint != nil {
+			return t.collapseJoint(uint32(last))
+		}
+		t.collapse(uint32(last), detail)
+		return nil
+	}
+	// A joint register's qubits have two states, so one always survives
+	// alone; other tokens narrow in place
+	if err := t.setStateVector(op, kept); err != nil {
+		return err
+	}
+	t.SuperposedStates = slices.Clip(states)
+	t.SuperpositionCount = uint32(len(states))
+	t.reseal()
+	auditEmit(AuditCollapse, t, detail)
+	return nil
+}
@@ -0,0 +1,341 @@
+// go/target/collections.go
+// Governed Slices and Maps (RiftSlice[T], RiftMap[K,V]) - Go Implementation
+
+package rift
+
+import "reflect"
+
+// ============================================================================
+// Collection Governance
+// ============================================================================
+
+// sizeOf returns the in-memory size of T, at least 1
+func sizeOf[T any]() uint64 {
+	size := uint64(reflect.TypeOf((*T)(nil)).Elem().Size())
+	if size == 0 {
+		size = 1
+	}
+	return size
+}
+
+// newCollectionToken creates a governed token of tokenType holding data in
+// a row span sized for capacity elements
+func newCollectionToken(tokenType int, elemSize uint64, capacity int, data interface{}) *RiftToken {
+	if capacity < 0 {
+		capacity = 0
+	}
+	memory := NewRiftMemorySpan(SpanRow, elemSize*uint64(capacity))
+	token := NewRiftToken(tokenType, memory)
+	token.Value.PtrVal = data
+	token.SetBit(TokenInitialized)
+	token.Validate()
+	return token
+}
+
+// acquire checks that the collection token is governed and its access mask
+// grants bits, then takes its write or read lock. The returned func
+// releases the lock.
+func acquire(t *RiftToken, op string, bits uint32, write bool) (func(), error) {
+	if !t.HasBit(TokenGoverned) {
+		return nil, t.violation(govErr(CodeNotGoverned, op, "collection token not governed"))
+	}
+	if err := t.checkAccess(op, bits); err != nil {
+		return nil, err
+	}
+	if write {
+		if !t.Lock() {
+			return nil, govErr(CodeDeadlock, op, "lock would complete a wait cycle")
+		}
+		return func() { t.Unlock() }, nil
+	}
+	if !t.RLock() {
+		return nil, govErr(CodeDeadlock, op, "lock would complete a wait cycle")
+	}
+	return func() { t.RUnlock() }, nil
+}
+
+// spanCapacity returns how many elements of elemSize the token's span
+// holds; it follows Resize
+func spanCapacity(t *RiftToken, elemSize uint64) int {
+	if t.Memory == nil {
+		return 0
+	}
+	return int(t.Memory.Bytes / elemSize)
+}
+
+// ============================================================================
+// RiftSlice
+// ============================================================================
+
+// RiftSlice is a slice owned by a TokenGoSlice token. Reading elements
+// requires AccessRead, replacing them AccessUpdate, appending AccessCreate
+// and removing AccessDelete on the token's memory span, and every access
+// holds the token's lock. The span's Bytes bound the length: appends past
+// Bytes / sizeof(T) elements fail with CodeSpanBounds.
+type RiftSlice[T any] struct {
+	token *RiftToken
+}
+
+// NewRiftSlice creates an empty governed slice with room for capacity
+// elements
+func NewRiftSlice[T any](capacity int) *RiftSlice[T] {
+	return &RiftSlice[T]{token: newCollectionToken(TokenGoSlice, sizeOf[T](), capacity, []T(nil))}
+}
+
+// Token returns the governance token of the slice
+func (s *RiftSlice[T]) Token() *RiftToken {
+	return s.token
+}
+
+// items returns the backing slice; token lock held
+func (s *RiftSlice[T]) items() []T {
+	items, _ := s.token.Value.PtrVal.([]T)
+	return items
+}
+
+// store replaces the backing slice and reseals the token; write lock held
+func (s *RiftSlice[T]) store(items []T) {
+	s.token.Value.PtrVal = items
+	s.token.reseal()
+}
+
+// Len returns the number of elements
+func (s *RiftSlice[T]) Len() int {
+	s.token.RLock()
+	defer s.token.RUnlock()
+	return len(s.items())
+}
+
+// Cap returns the number of elements the memory span allows
+func (s *RiftSlice[T]) Cap() int {
+	return spanCapacity(s.token, sizeOf[T]())
+}
+
+// Get returns the element at i
+func (s *RiftSlice[T]) Get(i int) (T, error) {
+	var zero T
+	release, err := acquire(s.token, "get", AccessRead, false)
+	if err != nil {
+		return zero, err
+	}
+	defer release()
+
+	items := s.items()
+	if i < 0 || i >= len(items) {
+		return zero, govErr(CodeIndexOutOfRange, "get", "index %d out of %d elements", i, len(items))
+	}
+	return items[i], nil
+}
+
+// Set replaces the element at i
+func (s *RiftSlice[T]) Set(i int, v T) error {
+	release, err := acquire(s.token, "set", AccessUpdate, true)
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	items := s.items()
+	if i < 0 || i >= len(items) {
+		return govErr(CodeIndexOutOfRange, "set", "index %d out of %d elements", i, len(items))
+	}
+	items[i] = v
+	s.store(items)
+	auditEmit(AuditSetValue, s.token, "")
+	return nil
+}
+
+// Append adds elements to the end of the slice. Nothing is appended if
+// the result would exceed the span's capacity.
+func (s *RiftSlice[T]) Append(vs ...T) error {
+	release, err := acquire(s.token, "append", AccessCreate, true)
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	items := s.items()
+	if limit := s.Cap(); len(items)+len(vs) > limit {
+		return govErr(CodeSpanBounds, "append", "%d elements exceed span capacity %d", len(items)+len(vs), limit)
+	}
+	s.store(append(items, vs...))
+	auditEmit(AuditSetValue, s.token, "")
+	return nil
+}
+
+// Delete removes the element at i, shifting later elements down
+func (s *RiftSlice[T]) Delete(i int) error {
+	release, err := acquire(s.token, "delete", AccessDelete, true)
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	items := s.items()
+	if i < 0 || i >= len(items) {
+		return govErr(CodeIndexOutOfRange, "delete", "index %d out of %d elements", i, len(items))
+	}
+	copy(items[i:], items[i+1:])
+	var zero T
+	items[len(items)-1] = zero
+	s.store(items[:len(items)-1])
+	auditEmit(AuditDelete, s.token, "")
+	return nil
+}
+
+// Values returns a copy of the elements
+func (s *RiftSlice[T]) Values() ([]T, error) {
+	release, err := acquire(s.token, "get", AccessRead, false)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+	return append([]T(nil), s.items()...), nil
+}
+
+// Range calls fn for each element in order until fn returns false. The
+// read lock is held throughout, so fn must not modify the slice.
+func (s *RiftSlice[T]) Range(fn func(i int, v T) bool) error {
+	release, err := acquire(s.token, "get", AccessRead, false)
+	if err != nil {
+		return err
+	}
+	defer release()
+	for i, v := range s.items() {
+		if !fn(i, v) {
+			break
+		}
+	}
+	return nil
+}
+
+// ============================================================================
+// RiftMap
+// ============================================================================
+
+// RiftMap is a map owned by a TokenGoMap token. Reading requires
+// AccessRead, adding a key AccessCreate, replacing a value AccessUpdate
+// and removing a key AccessDelete on the token's memory span, and every
+// access holds the token's lock. The span's Bytes bound the entry count:
+// adding keys past Bytes / (sizeof(K) + sizeof(V)) fails with
+// CodeSpanBounds.
+type RiftMap[K comparable, V any] struct {
+	token *RiftToken
+}
+
+// NewRiftMap creates an empty governed map with room for capacity entries
+func NewRiftMap[K comparable, V any](capacity int) *RiftMap[K, V] {
+	return &RiftMap[K, V]{token: newCollectionToken(TokenGoMap, sizeOf[K]()+sizeOf[V](), capacity, make(map[K]V))}
+}
+
+// Token returns the governance token of the map
+func (m *RiftMap[K, V]) Token() *RiftToken {
+	return m.token
+}
+
+// entries returns the backing map; token lock held
+func (m *RiftMap[K, V]) entries() map[K]V {
+	entries, _ := m.token.Value.PtrVal.(map[K]V)
+	return entries
+}
+
+// Len returns the number of entries
+func (m *RiftMap[K, V]) Len() int {
+	m.token.RLock()
+	defer m.token.RUnlock()
+	return len(m.entries())
+}
+
+// Cap returns the number of entries the memory span allows
+func (m *RiftMap[K, V]) Cap() int {
+	return spanCapacity(m.token, sizeOf[K]()+sizeOf[V]())
+}
+
+// Get returns the value stored under k
+func (m *RiftMap[K, V]) Get(k K) (V, bool, error) {
+	var zero V
+	release, err := acquire(m.token, "get", AccessRead, false)
+	if err != nil {
+		return zero, false, err
+	}
+	defer release()
+	v, ok := m.entries()[k]
+	return v, ok, nil
+}
+
+// Set stores v under k. A new key requires AccessCreate and room in the
+// span; replacing a value requires AccessUpdate.
+func (m *RiftMap[K, V]) Set(k K, v V) error {
+	// Which access is needed depends on the key, so check it under the lock
+	release, err := acquire(m.token, "set", 0, true)
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	entries := m.entries()
+	if _, ok := entries[k]; ok {
+		if err := m.token.checkAccess("set", AccessUpdate); err != nil {
+			return err
+		}
+	} else {
+		if err := m.token.checkAccess("set", AccessCreate); err != nil {
+			return err
+		}
+		if limit := m.Cap(); len(entries)+1 > limit {
+			return govErr(CodeSpanBounds, "set", "%d entries exceed span capacity %d", len(entries)+1, limit)
+		}
+	}
+	entries[k] = v
+	m.token.reseal()
+	auditEmit(AuditSetValue, m.token, "")
+	return nil
+}
+
+// Delete removes k, reporting whether it was present
+func (m *RiftMap[K, V]) Delete(k K) (bool, error) {
+	release, err := acquire(m.token, "delete", AccessDelete, true)
+	if err != nil {
+		return false, err
+	}
+	defer release()
+
+	entries := m.entries()
+	if _, ok := entries[k]; !ok {
+		return false, nil
+	}
+	delete(entries, k)
+	m.token.reseal()
+	auditEmit(AuditDelete, m.token, "")
+	return true, nil
+}
+
+// Keys returns the map's keys in unspecified order
+func (m *RiftMap[K, V]) Keys() ([]K, error) {
+	release, err := acquire(m.token, "get", AccessRead, false)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+	keys := make([]K, 0, len(m.entries()))
+	for k := range m.entries() {
+		keys = append(keys, k)
+	}
+	return keys, nil
+}
+
+// Range calls fn for each entry until fn returns false. The read lock is
+// held throughout, so fn must not modify the map.
+func (m *RiftMap[K, V]) Range(fn func(k K, v V) bool) error {
+	release, err := acquire(m.token, "get", AccessRead, false)
+	if err != nil {
+		return err
+	}
+	defer release()
+	for k, v := range m.entries() {
+		if !fn(k, v) {
+			break
+		}
+	}
+	return nil
+}
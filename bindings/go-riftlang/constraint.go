@@ -0,0 +1,98 @@
+// go/target/constraint.go
+// Token Value Constraints - Go Implementation
+
+package rift
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"regexp"
+)
+
+// ============================================================================
+// Constraints
+// ============================================================================
+
+// Constraint checks a token value, returning why the value is not allowed
+// or nil
+type Constraint func(RiftTokenValue) error
+
+// AddConstraint attaches c to the token. SetValue and CompareAndSetValue
+// refuse a value failing any constraint, leaving the token unchanged, and
+// validation fails while the current value fails one. Either way the
+// error has CodeConstraint, names the constraint by its position among
+// the token's constraints and wraps the constraint's own error, and is
+// reported to the violation handler. Constraints run in the order added,
+// outside the token's locks. The returned func removes the constraint.
+func (t *RiftToken) AddConstraint(c Constraint) (remove func()) {
+	h := t.hookSet()
+	return addHook(h, &h.check, c)
+}
+
+// constraintError returns the first constraint val fails, or nil
+func (t *RiftToken) constraintError(op string, val RiftTokenValue) *GovernanceError {
+	h := t.hooks.Load()
+	if h == nil {
+		return nil
+	}
+	checks := hookFuncs(h, &h.check)
+	for i, c := range checks {
+		if err := c(val); err != nil {
+			return &GovernanceError{Code: CodeConstraint, Op: op,
+				Detail: fmt.Sprintf("constraint %d of %d", i+1, len(checks)), Err: err}
+		}
+	}
+	return nil
+}
+
+// ============================================================================
+// Common Constraints
+// ============================================================================
+
+// IntRange allows IntVal values in [min, max]
+func IntRange(min, max int64) Constraint {
+	return func(v RiftTokenValue) error {
+		if v.IntVal < min || v.IntVal > max {
+			return fmt.Errorf("%d is outside [%d, %d]", v.IntVal, min, max)
+		}
+		return nil
+	}
+}
+
+// FloatRange allows FloatVal values in [min, max]; NaN is refused
+func FloatRange(min, max float64) Constraint {
+	return func(v RiftTokenValue) error {
+		if math.IsNaN(v.FloatVal) || v.FloatVal < min || v.FloatVal > max {
+			return fmt.Errorf("%g is outside [%g, %g]", v.FloatVal, min, max)
+		}
+		return nil
+	}
+}
+
+// StringMatches allows StringVal values that re matches
+func StringMatches(re *regexp.Regexp) Constraint {
+	return func(v RiftTokenValue) error {
+		if !re.MatchString(v.StringVal) {
+			return fmt.Errorf("%.64q does not match %s", v.StringVal, re)
+		}
+		return nil
+	}
+}
+
+// NotNil allows PtrVal values that are neither nil nor a nil pointer, map,
+// slice, channel or func
+func NotNil() Constraint {
+	return func(v RiftTokenValue) error {
+		if v.PtrVal == nil {
+			return fmt.Errorf("value is nil")
+		}
+		switch rv := reflect.ValueOf(v.PtrVal); rv.Kind() {
+		case reflect.Pointer, reflect.Map, reflect.Slice, reflect.Chan, reflect.Func, reflect.Interface:
+			if rv.IsNil() {
+				return fmt.Errorf("value is a nil %s", rv.Type())
+			}
+		}
+		return nil
+	}
+}
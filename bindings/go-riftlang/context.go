@@ -0,0 +1,50 @@
+// go/target/context.go
+// Context-Aware Governance - Go Implementation
+
+package rift
+
+import (
+	"context"
+	"errors"
+)
+
+// tokenContextKey keys the governed token carried by a context
+type tokenContextKey struct{}
+
+// ============================================================================
+// Context Propagation
+// ============================================================================
+
+// ContextWithToken returns a copy of ctx carrying token
+func ContextWithToken(ctx context.Context, token *RiftToken) context.Context {
+	return context.WithValue(ctx, tokenContextKey{}, token)
+}
+
+// TokenFromContext returns the token carried by ctx, if any
+func TokenFromContext(ctx context.Context) (*RiftToken, bool) {
+	token, ok := ctx.Value(tokenContextKey{}).(*RiftToken)
+	return token, ok && token != nil
+}
+
+// WithTokenContext is WithToken honoring ctx: it gives up waiting for the
+// token lock when ctx is done, and passes fn a context carrying the token
+func WithTokenContext(ctx context.Context, token *RiftToken, fn func(context.Context, *RiftToken) error) error {
+	if token == nil {
+		return govErr(CodeNilToken, "with token", "token is nil")
+	}
+	if err := token.LockContext(ctx); err != nil {
+		return err
+	}
+	defer token.Unlock()
+	return fn(ContextWithToken(ctx, token), token)
+}
+
+// contextError converts a context error into a GovernanceError wrapping it,
+// using onDeadline as the code when the deadline expired
+func contextError(op string, onDeadline ErrorCode, err error) error {
+	code := CodeCanceled
+	if errors.Is(err, context.DeadlineExceeded) {
+		code = onDeadline
+	}
+	return &GovernanceError{Code: code, Op: op, Err: err}
+}
@@ -0,0 +1,163 @@
+// go/target/convert.go
+// Governed Token Type Conversion - Go Implementation
+
+package rift
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+)
+
+// maxExactFloatInt is the largest magnitude below which every integer is
+// exactly representable as a float64
+const maxExactFloatInt = 1 << 53
+
+// ============================================================================
+// Conversion
+// ============================================================================
+
+// As converts the token's value to another token type (GoInt, GoFloat or
+// GoString) without losing range or precision; see AsWith
+func (t *RiftToken) As(targetType int) (*RiftToken, error) {
+	return t.AsWith(targetType, nil)
+}
+
+// AsWith converts the token's value to targetType and returns it as a new
+// token validated against p (structurally when p is nil). The conversion
+// needs AccessRead on the token and an initialized, collapsed value.
+//
+// Out-of-range values always fail with CodeConversion. Conversions that
+// lose precision (fractional floats to GoInt, integers beyond 2^53 to
+// GoFloat) also fail unless p's rule for targetType sets LossyConversion,
+// in which case floats truncate toward zero. Strings are parsed as base-10
+// integers or as floats. Each successful conversion is audited as
+// AuditConvert on the new token.
+func (t *RiftToken) AsWith(targetType int, p *GovernancePolicy) (*RiftToken, error) {
+	if err := t.checkAccess("convert", AccessRead); err != nil {
+		return nil, err
+	}
+	src := t.readSource()
+	if !src.HasBit(TokenInitialized) {
+		return nil, govErr(CodeNotInitialized, "convert", "token value not initialized")
+	}
+	if src.HasBit(TokenSuperposed) {
+		return nil, govErr(CodeConversion, "convert", "superposed token must collapse before conversion")
+	}
+
+	lossy := p != nil && p.RuleFor(targetType).LossyConversion
+	val, err := convertValue(src.Type, src.Value, targetType, lossy)
+	if err != nil {
+		return nil, err
+	}
+
+	memory := NewRiftMemorySpan(SpanFixed, 64)
+	if t.Memory != nil {
+		memory.AccessMask = t.Memory.Mask()
+	}
+	out := NewRiftToken(targetType, memory)
+	out.Value = val
+	out.SourceFile, out.SourceLine, out.SourceColumn = t.SourceFile, t.SourceLine, t.SourceColumn
+	out.SetBit(TokenInitialized)
+	auditEmit(AuditConvert, out, fmt.Sprintf("%s -> %s", TokenTypeName(src.Type), TokenTypeName(targetType)))
+
+	if err := out.ValidateAgainst(p); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// convertValue converts v from one token type to another
+func convertValue(from int, v RiftTokenValue, to int, lossy bool) (RiftTokenValue, error) {
+	conv := func(format string, args ...interface{}) error {
+		return govErr(CodeConversion, "convert", "%s to %s: %s",
+			TokenTypeName(from), TokenTypeName(to), fmt.Sprintf(format, args...))
+	}
+
+	switch from {
+	case TokenGoInt, TokenGoFloat, TokenGoString:
+	default:
+		return RiftTokenValue{}, conv("unsupported source type")
+	}
+
+	var out RiftTokenValue
+	switch to {
+	case TokenGoInt:
+		switch from {
+		case TokenGoInt:
+			out.IntVal = v.IntVal
+		case TokenGoFloat:
+			n, err := floatToInt(v.FloatVal, lossy)
+			if err != nil {
+				return out, conv("%v", err)
+			}
+			out.IntVal = n
+		case TokenGoString:
+			n, err := strconv.ParseInt(v.StringVal, 10, 64)
+			switch {
+			case errors.Is(err, strconv.ErrRange):
+				return out, conv("%q is out of int64 range", v.StringVal)
+			case err != nil:
+				// not an integer literal; try it as a float
+				f, ferr := strconv.ParseFloat(v.StringVal, 64)
+				if errors.Is(ferr, strconv.ErrRange) {
+					return out, conv("%q is out of int64 range", v.StringVal)
+				}
+				if ferr != nil {
+					return out, conv("%q is not a number", v.StringVal)
+				}
+				if n, err = floatToInt(f, lossy); err != nil {
+					return out, conv("%v", err)
+				}
+			}
+			out.IntVal = n
+		}
+
+	case TokenGoFloat:
+		switch from {
+		case TokenGoInt:
+			if !lossy && (v.IntVal > maxExactFloatInt || v.IntVal < -maxExactFloatInt) {
+				return out, conv("%d is not exactly representable as a float64", v.IntVal)
+			}
+			out.FloatVal = float64(v.IntVal)
+		case TokenGoFloat:
+			out.FloatVal = v.FloatVal
+		case TokenGoString:
+			f, err := strconv.ParseFloat(v.StringVal, 64)
+			if err != nil {
+				if errors.Is(err, strconv.ErrRange) {
+					return out, conv("%q is out of float64 range", v.StringVal)
+				}
+				return out, conv("%q is not a number", v.StringVal)
+			}
+			out.FloatVal = f
+		}
+
+	case TokenGoString:
+		switch from {
+		case TokenGoInt:
+			out.StringVal = strconv.FormatInt(v.IntVal, 10)
+		case TokenGoFloat:
+			out.StringVal = strconv.FormatFloat(v.FloatVal, 'g', -1, 64)
+		case TokenGoString:
+			out.StringVal = v.StringVal
+		}
+
+	default:
+		return out, conv("unsupported target type")
+	}
+	return out, nil
+}
+
+// floatToInt converts f to an int64, refusing values out of range and,
+// unless lossy, values with a fractional part
+func floatToInt(f float64, lossy bool) (int64, error) {
+	if math.IsNaN(f) || math.IsInf(f, 0) || f >= math.MaxInt64 || f < math.MinInt64 {
+		return 0, fmt.Errorf("%v is out of int64 range", f)
+	}
+	if !lossy && f != math.Trunc(f) {
+		return 0, fmt.Errorf("%v has a fractional part", f)
+	}
+	return int64(f), nil
+}
@@ -0,0 +1,205 @@
+// go/target/dashboard.go
+// Governance Dashboard HTTP Handler - Go Implementation
+
+package rift
+
+import (
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"net/http"
+	"path"
+	"strings"
+	"time"
+)
+
+// ============================================================================
+// DashboardState
+// ============================================================================
+
+// DashboardState is what the dashboard shows: the tokens in DefaultRegistry
+// and every token entangled with them, and the engines registered with
+// RegisterEngine. Tokens are read without taking their locks.
+type DashboardState struct {
+	Taken         time.Time         `json:"taken"`
+	LockOwnership bool              `json:"lockOwnership"` // holders are tracked (see SetLockOwnership)
+	Tokens        []DashboardToken  `json:"tokens"`
+	Engines       []DashboardEngine `json:"engines"`
+}
+
+// DashboardToken is one token of a DashboardState. Partners index Tokens,
+// as the entanglement graph of the registry numbers them.
+type DashboardToken struct {
+	Index          int      `json:"index"`
+	Name           string   `json:"name,omitempty"` // DefaultRegistry name; none for tokens reached by entanglement
+	Type           string   `json:"type"`
+	Bits           []string `json:"bits"`
+	Governed       bool     `json:"governed"`
+	Source         string   `json:"source,omitempty"` // file:line of creation
+	EntanglementID uint32   `json:"entanglementId,omitempty"`
+	Partners       []int    `json:"partners,omitempty"`
+	WriteLocked    bool     `json:"writeLocked,omitempty"`
+	ReadLocks      int      `json:"readLocks,omitempty"`
+	Writer         uint64   `json:"writer,omitempty"`  // goroutine holding the write lock, when tracked
+	Readers        []uint64 `json:"readers,omitempty"` // goroutines holding read locks, when tracked
+	Contention     uint64   `json:"contention,omitempty"`
+}
+
+// DashboardEngine is a registered pattern engine and its statistics
+type DashboardEngine struct {
+	Name  string      `json:"name"`
+	Mode  string      `json:"mode"`
+	Stats EngineStats `json:"stats"`
+}
+
+// dashboardBits names the validation bits in display order
+var dashboardBits = []struct {
+	bit  uint32
+	name string
+}{
+	{TokenAllocated, "allocated"},
+	{TokenInitialized, "initialized"},
+	{TokenLocked, "locked"},
+	{TokenGoverned, "governed"},
+	{TokenSuperposed, "superposed"},
+	{TokenEntangled, "entangled"},
+	{TokenPersistent, "persistent"},
+	{TokenShadow, "shadow"},
+}
+
+// ReadDashboard captures the current DashboardState. Lock holders are
+// known only while lock ownership tracking or deadlock detection is on.
+func ReadDashboard() *DashboardState {
+	state := &DashboardState{Taken: clockNow(), LockOwnership: LockOwnership() || DeadlockDetection() != DeadlockOff}
+
+	g := EntanglementGraphOf(DefaultRegistry)
+	state.Tokens = make([]DashboardToken, len(g.tokens))
+	for i, t := range g.tokens {
+		bits := t.ValidationBits.Load()
+		dt := DashboardToken{
+			Index:          i,
+			Name:           g.labels[i],
+			Type:           TokenTypeName(t.Type),
+			Bits:           []string{},
+			Governed:       bits&TokenGoverned != 0,
+			EntanglementID: t.EntanglementID,
+			Partners:       g.adj[i],
+			Contention:     t.LockContention(),
+		}
+		for _, b := range dashboardBits {
+			if bits&b.bit != 0 {
+				dt.Bits = append(dt.Bits, b.name)
+			}
+		}
+		if t.SourceFile != "" {
+			dt.Source = fmt.Sprintf("%s:%d", t.SourceFile, t.SourceLine)
+		}
+		dt.WriteLocked, dt.ReadLocks = t.lock.state()
+		dt.Writer, dt.Readers = lockHolders(t)
+		state.Tokens[i] = dt
+	}
+
+	state.Engines = []DashboardEngine{}
+	for _, name := range EngineNames() {
+		if e, ok := LookupEngine(name); ok {
+			state.Engines = append(state.Engines, DashboardEngine{Name: name, Mode: e.Mode().String(), Stats: e.Stats()})
+		}
+	}
+	return state
+}
+
+// ============================================================================
+// Handler
+// ============================================================================
+
+// DashboardHandler returns a handler serving a read-only governance
+// dashboard: live tokens with their validation bits and lock holders, the
+// entanglement graph and pattern engine statistics. Mounted at a prefix
+// (with http.StripPrefix), it serves
+//
+//	GET /            the dashboard page, refreshing every few seconds
+//	GET /state.json  the ReadDashboard state as JSON
+//	GET /graph.dot   the entanglement graph in Graphviz DOT format
+//
+// It exposes token names and source locations, so mount it only where
+// those may be seen, such as a staging or debug listener.
+func DashboardHandler() http.Handler {
+	return http.HandlerFunc(serveDashboard)
+}
+
+// serveDashboard serves the DashboardHandler routes
+func serveDashboard(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	switch path.Base(r.URL.Path) {
+	case "state.json":
+		w.Header().Set("Content-Type", "application/json")
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		enc.Encode(ReadDashboard())
+	case "graph.dot":
+		w.Header().Set("Content-Type", "text/vnd.graphviz; charset=utf-8")
+		EntanglementGraphOf(DefaultRegistry).WriteDOT(w)
+	default:
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		if err := dashboardPage.Execute(w, ReadDashboard()); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+	}
+}
+
+// dashboardPage renders a DashboardState. Links are relative so the page
+// works under any prefix.
+var dashboardPage = template.Must(template.New("dashboard").Funcs(template.FuncMap{
+	"join": strings.Join,
+}).Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<meta http-equiv="refresh" content="5">
+<title>Rift governance</title>
+<style>
+body { font: 13px/1.4 system-ui, sans-serif; margin: 1.5em; color: #222; }
+table { border-collapse: collapse; margin-bottom: 1.5em; }
+th, td { border: 1px solid #ccc; padding: 3px 8px; text-align: left; vertical-align: top; }
+th { background: #f3f3f3; }
+.bad { color: #b00; }
+.muted { color: #888; }
+</style>
+</head>
+<body>
+<h1>Rift governance</h1>
+<p class="muted">{{.Taken.Format "2006-01-02 15:04:05.000 MST"}} &middot;
+<a href="state.json">state.json</a> &middot; <a href="graph.dot">graph.dot</a>
+{{if not .LockOwnership}}&middot; lock holders not tracked (see SetLockOwnership and SetDeadlockDetection){{end}}</p>
+
+<h2>Tokens ({{len .Tokens}})</h2>
+<table>
+<tr><th>#</th><th>Name</th><th>Type</th><th>Bits</th><th>Lock</th><th>Holders</th><th>Contention</th><th>Entangled with</th><th>Source</th></tr>
+{{range .Tokens}}<tr>
+<td>{{.Index}}</td>
+<td>{{if .Name}}{{.Name}}{{else}}<span class="muted">&mdash;</span>{{end}}</td>
+<td>{{.Type}}</td>
+<td{{if not .Governed}} class="bad"{{end}}>{{join .Bits " "}}</td>
+<td>{{if .WriteLocked}}write{{else if .ReadLocks}}read &times;{{.ReadLocks}}{{end}}</td>
+<td>{{if .Writer}}g{{.Writer}}{{end}}{{range .Readers}} g{{.}}{{end}}</td>
+<td>{{if .Contention}}{{.Contention}}{{end}}</td>
+<td>{{if .EntanglementID}}id {{.EntanglementID}}: {{end}}{{range .Partners}}#{{.}} {{end}}</td>
+<td class="muted">{{.Source}}</td>
+</tr>
+{{end}}</table>
+
+<h2>Pattern engines ({{len .Engines}})</h2>
+<table>
+<tr><th>Name</th><th>Mode</th><th>Pairs</th><th>Matches</th><th>Failures</th><th>Avg ms</th><th>Cache hits</th><th>Cache misses</th></tr>
+{{range .Engines}}<tr>
+<td>{{.Name}}</td><td>{{.Mode}}</td><td>{{.Stats.PairCount}}</td><td>{{.Stats.TotalMatches}}</td><td>{{.Stats.TotalFailures}}</td>
+<td>{{printf "%.3f" .Stats.AverageMatchTimeMs}}</td><td>{{.Stats.CacheHits}}</td><td>{{.Stats.CacheMisses}}</td>
+</tr>
+{{end}}</table>
+</body>
+</html>
+`))
@@ -0,0 +1,255 @@
+// go/target/deadlock.go
+// Token Lock Deadlock Detector - Go Implementation
+
+package rift
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// DeadlockMode selects how a detected wait cycle is reported
+type DeadlockMode int32
+
+const (
+	DeadlockOff   DeadlockMode = iota // no tracking (default)
+	DeadlockPanic                     // panic with a *GovernanceError
+	DeadlockError                     // fail the lock call with a *GovernanceError
+)
+
+// ============================================================================
+// DeadlockCycle
+// ============================================================================
+
+// DeadlockWait is one edge of a wait cycle: a goroutine waiting for a token
+// held (or, for readers, about to be written) by other goroutines
+type DeadlockWait struct {
+	Goroutine uint64
+	Token     *RiftToken
+	Write     bool
+	HeldBy    []uint64
+}
+
+// DeadlockCycle reports the wait cycle a lock call would have completed
+type DeadlockCycle struct {
+	Cycle []DeadlockWait
+}
+
+// Error implements error
+func (e *DeadlockCycle) Error() string {
+	parts := make([]string, len(e.Cycle))
+	for i, w := range e.Cycle {
+		mode := "read"
+		if w.Write {
+			mode = "write"
+		}
+		parts[i] = fmt.Sprintf("goroutine %d waits to %s %s held by goroutine(s) %v",
+			w.Goroutine, mode, describeToken(w.Token), w.HeldBy)
+	}
+	return "wait cycle: " + strings.Join(parts, "; ")
+}
+
+// describeToken names a token by address, type and source location
+func describeToken(t *RiftToken) string {
+	desc := fmt.Sprintf("token %p (%s)", t, TokenTypeName(t.Type))
+	if t.SourceFile != "" {
+		desc += fmt.Sprintf(" at %s:%d", t.SourceFile, t.SourceLine)
+	}
+	return desc
+}
+
+// ============================================================================
+// Wait-For Graph
+// ============================================================================
+
+// lockWait records the token a goroutine is blocked on
+type lockWait struct {
+	token *RiftToken
+	write bool
+}
+
+// deadlocks tracks token lock holders and waiters while detection is on
+var deadlocks struct {
+	mode    atomic.Int32
+	lock    sync.Mutex
+	writers map[*RiftToken]uint64         // token -> write-lock holder
+	readers map[*RiftToken]map[uint64]int // token -> goroutine -> read holds
+	waiting map[uint64]lockWait
+}
+
+// SetDeadlockDetection turns the detector on or off. Tracking starts from
+// the next lock call, so locks already held are not seen.
+func SetDeadlockDetection(mode DeadlockMode) {
+	deadlocks.lock.Lock()
+	defer deadlocks.lock.Unlock()
+	deadlocks.writers = make(map[*RiftToken]uint64)
+	deadlocks.readers = make(map[*RiftToken]map[uint64]int)
+	deadlocks.waiting = make(map[uint64]lockWait)
+	deadlocks.mode.Store(int32(mode))
+}
+
+// DeadlockDetection returns the current detector mode
+func DeadlockDetection() DeadlockMode {
+	return DeadlockMode(deadlocks.mode.Load())
+}
+
+// deadlockWait registers the calling goroutine as waiting for t and checks
+// whether that closes a wait cycle. It returns the goroutine id to pass to
+// deadlockAcquired or deadlockAbandon (0 when detection is off), or the
+// cycle error in DeadlockError mode.
+func deadlockWait(t *RiftToken, write bool) (uint64, error) {
+	mode := DeadlockMode(deadlocks.mode.Load())
+	if mode == DeadlockOff {
+		return 0, nil
+	}
+	gid := goroutineID()
+
+	deadlocks.lock.Lock()
+	deadlocks.waiting[gid] = lockWait{token: t, write: write}
+	cycle := findWaitCycle(gid)
+	if cycle != nil {
+		delete(deadlocks.waiting, gid)
+	}
+	deadlocks.lock.Unlock()
+
+	if cycle == nil {
+		return gid, nil
+	}
+	err := &GovernanceError{Code: CodeDeadlock, Op: "lock", Err: cycle}
+	if mode == DeadlockPanic {
+		panic(err)
+	}
+	return 0, err
+}
+
+// deadlockAcquired moves gid from waiting on t to holding t. A zero gid
+// (no prior wait, as for TryLock) uses the calling goroutine.
+func deadlockAcquired(t *RiftToken, gid uint64, write bool) {
+	if gid == 0 {
+		if deadlocks.mode.Load() == int32(DeadlockOff) {
+			return
+		}
+		gid = goroutineID()
+	}
+
+	deadlocks.lock.Lock()
+	defer deadlocks.lock.Unlock()
+	if deadlocks.waiting == nil {
+		return
+	}
+	delete(deadlocks.waiting, gid)
+	if write {
+		deadlocks.writers[t] = gid
+		return
+	}
+	held := deadlocks.readers[t]
+	if held == nil {
+		held = make(map[uint64]int)
+		deadlocks.readers[t] = held
+	}
+	held[gid]++
+}
+
+// deadlockAbandon clears gid's wait after a cancelled acquisition
+func deadlockAbandon(gid uint64) {
+	if gid == 0 {
+		return
+	}
+	deadlocks.lock.Lock()
+	defer deadlocks.lock.Unlock()
+	delete(deadlocks.waiting, gid)
+}
+
+// deadlockReleased drops one hold on t. Read holds prefer the calling
+// goroutine's, since Go locks may be released by a different goroutine.
+func deadlockReleased(t *RiftToken, write bool) {
+	if deadlocks.mode.Load() == int32(DeadlockOff) {
+		return
+	}
+
+	deadlocks.lock.Lock()
+	defer deadlocks.lock.Unlock()
+	if write {
+		delete(deadlocks.writers, t)
+		return
+	}
+	held := deadlocks.readers[t]
+	if len(held) == 0 {
+		return
+	}
+	gid := goroutineID()
+	if _, ok := held[gid]; !ok {
+		for g := range held {
+			gid = g
+			break
+		}
+	}
+	if held[gid]--; held[gid] <= 0 {
+		delete(held, gid)
+	}
+	if len(held) == 0 {
+		delete(deadlocks.readers, t)
+	}
+}
+
+// blockers returns the goroutines a waiter on w is blocked behind: the
+// write holder, plus read holders for writers, or pending writers for
+// readers (pending writers hold off new readers). deadlocks.lock held.
+func blockers(w lockWait, self uint64) []uint64 {
+	var out []uint64
+	if g, ok := deadlocks.writers[w.token]; ok {
+		out = append(out, g)
+	}
+	if w.write {
+		for g := range deadlocks.readers[w.token] {
+			out = append(out, g)
+		}
+	} else {
+		for g, other := range deadlocks.waiting {
+			if g != self && other.token == w.token && other.write {
+				out = append(out, g)
+			}
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i] < out[j] })
+	return out
+}
+
+// findWaitCycle searches the wait-for graph for a path from start back to
+// itself. deadlocks.lock held.
+func findWaitCycle(start uint64) *DeadlockCycle {
+	visited := make(map[uint64]bool)
+	var path []DeadlockWait
+
+	var visit func(g uint64) bool
+	visit = func(g uint64) bool {
+		w, ok := deadlocks.waiting[g]
+		if !ok {
+			return false
+		}
+		next := blockers(w, g)
+		path = append(path, DeadlockWait{Goroutine: g, Token: w.token, Write: w.write, HeldBy: next})
+		for _, h := range next {
+			if h == start {
+				return true
+			}
+			if !visited[h] {
+				visited[h] = true
+				if visit(h) {
+					return true
+				}
+			}
+		}
+		path = path[:len(path)-1]
+		return false
+	}
+
+	visited[start] = true
+	if visit(start) {
+		return &DeadlockCycle{Cycle: path}
+	}
+	return nil
+}
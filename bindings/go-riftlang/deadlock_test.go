@@ -0,0 +1,98 @@
+package rift
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// waitForWaiters polls until n goroutines wait on a token lock
+func waitForWaiters(t *testing.T, n int) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		deadlocks.lock.Lock()
+		waiting := len(deadlocks.waiting)
+		deadlocks.lock.Unlock()
+		if waiting >= n {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("%d goroutines waiting, want %d", waiting, n)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+// TestDeadlockCycle locks a then b on one goroutine and b then a on
+// another: the lock call closing the cycle fails and names both waits
+func TestDeadlockCycle(t *testing.T) {
+	SetDeadlockDetection(DeadlockError)
+	t.Cleanup(func() { SetDeadlockDetection(DeadlockOff) })
+
+	a, b := newLockToken(), newLockToken()
+	a.Lock()
+	done := make(chan struct{})
+	bLocked := make(chan struct{})
+	go func() {
+		defer close(done)
+		b.Lock()
+		close(bLocked)
+		if !a.Lock() { // blocks until the main goroutine unlocks a
+			t.Error("second goroutine's lock of a refused")
+			return
+		}
+		a.Unlock()
+		b.Unlock()
+	}()
+	<-bLocked
+	waitForWaiters(t, 1)
+
+	err := b.LockContext(context.Background())
+	var cycle *DeadlockCycle
+	if ErrorCodeOf(err) != CodeDeadlock || !errors.As(err, &cycle) {
+		t.Fatalf("closing lock = %v, want a %v wait cycle", err, CodeDeadlock)
+	}
+	if len(cycle.Cycle) != 2 || cycle.Cycle[0].Token != b || cycle.Cycle[1].Token != a {
+		t.Errorf("cycle %v, want waits on b then a", cycle)
+	}
+	a.Unlock()
+	<-done
+}
+
+func TestDeadlockSelf(t *testing.T) {
+	SetDeadlockDetection(DeadlockError)
+	t.Cleanup(func() { SetDeadlockDetection(DeadlockOff) })
+
+	tok := newLockToken()
+	tok.RLock()
+	if tok.Lock() {
+		t.Fatal("upgrading a read lock to a write lock was allowed")
+	}
+	tok.RUnlock()
+	if !tok.Lock() {
+		t.Fatal("lock of a free token refused")
+	}
+	if tok.RLock() {
+		t.Fatal("read-locking a token the goroutine write-locks was allowed")
+	}
+	tok.Unlock()
+}
+
+func TestDeadlockPanic(t *testing.T) {
+	SetDeadlockDetection(DeadlockPanic)
+	t.Cleanup(func() { SetDeadlockDetection(DeadlockOff) })
+
+	tok := newLockToken()
+	tok.Lock()
+	defer tok.Unlock()
+	defer func() {
+		err, _ := recover().(error)
+		if ErrorCodeOf(err) != CodeDeadlock {
+			t.Errorf("recovered %v, want %v", err, CodeDeadlock)
+		}
+	}()
+	tok.Lock()
+	t.Fatal("relock did not panic")
+}
@@ -0,0 +1,215 @@
+// go/target/decoherence.go
+// Decoherence Simulation - Go Implementation
+
+package rift
+
+import (
+	"fmt"
+	"math"
+	"math/cmplx"
+	"math/rand"
+	"sync"
+	"time"
+)
+
+// DecoherenceClock simulates decoherence over time. A superposed token
+// registered with the clock loses its phases gradually: each state's phase
+// decays by e^(-elapsed/CoherenceTime), so the amplitudes approach the
+// classical distribution with the same probabilities and interference
+// fades. Once CoherenceTime has elapsed since registration the token is
+// measured and collapses. Decay steps are audited as AuditDecohere and the
+// collapse as AuditCollapse.
+//
+// Time only moves when Advance or Tick is called, or in the background
+// after Start, so simulations can drive the clock with Now.
+type DecoherenceClock struct {
+	CoherenceTime time.Duration
+	Now           func() time.Time // nil = the package clock (see SetClock)
+	Rand          *rand.Rand       // nil = package RNG; see SetRandSource
+
+	lock    sync.Mutex
+	tokens  map[*RiftToken]*coherence
+	advance sync.Mutex // serializes Advance, guarding Rand
+	stop    chan struct{}
+	done    chan struct{}
+}
+
+// coherence tracks a registered token's time steps
+type coherence struct {
+	start time.Time // registration
+	last  time.Time // last decay step
+}
+
+// NewDecoherenceClock creates a clock collapsing tokens coherenceTime after
+// they are registered
+func NewDecoherenceClock(coherenceTime time.Duration) *DecoherenceClock {
+	return &DecoherenceClock{CoherenceTime: coherenceTime, tokens: make(map[*RiftToken]*coherence)}
+}
+
+// now returns the clock's current time
+func (c *DecoherenceClock) now() time.Time {
+	if c.Now != nil {
+		return c.Now()
+	}
+	return clockNow()
+}
+
+// Register starts t's coherence time. The token must be superposed;
+// registering it again restarts its coherence time.
+func (c *DecoherenceClock) Register(t *RiftToken) error {
+	if !t.HasBit(TokenSuperposed) {
+		return govErr(CodeNotSuperposed, "decohere", "token not in superposition")
+	}
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	now := c.now()
+	c.tokens[t] = &coherence{start: now, last: now}
+	return nil
+}
+
+// Unregister stops simulating decoherence for t
+func (c *DecoherenceClock) Unregister(t *RiftToken) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	delete(c.tokens, t)
+}
+
+// Len returns the number of registered tokens
+func (c *DecoherenceClock) Len() int {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return len(c.tokens)
+}
+
+// ============================================================================
+// Time Steps
+// ============================================================================
+
+// Tick advances the clock to its current time; see Advance
+func (c *DecoherenceClock) Tick() int {
+	return c.Advance(c.now())
+}
+
+// Advance decays every registered token to its coherence at now, collapsing
+// and unregistering those whose coherence time has elapsed, and returns how
+// many collapsed. Tokens no longer superposed are unregistered. A token
+// whose lock would complete a wait cycle is left for the next call.
+func (c *DecoherenceClock) Advance(now time.Time) int {
+	c.advance.Lock()
+	defer c.advance.Unlock()
+
+	c.lock.Lock()
+	tokens := make(map[*RiftToken]*coherence, len(c.tokens))
+	for t, co := range c.tokens {
+		tokens[t] = co
+	}
+	c.lock.Unlock()
+
+	collapsed := 0
+	for t, co := range tokens {
+		if !t.Lock() {
+			continue
+		}
+		done, didCollapse := c.step(t, co, now)
+		t.Unlock()
+		if didCollapse {
+			collapsed++
+		}
+		if done {
+			c.lock.Lock()
+			// Registered again meanwhile: keep the new coherence time
+			if c.tokens[t] == co {
+				delete(c.tokens, t)
+			}
+			c.lock.Unlock()
+		}
+	}
+	return collapsed
+}
+
+// step decays or collapses t at now, reporting whether t is finished with
+// and whether it collapsed; t's lock held
+func (c *DecoherenceClock) step(t *RiftToken, co *coherence, now time.Time) (done, collapsed bool) {
+	if !t.HasBit(TokenSuperposed) || len(t.SuperposedStates) == 0 {
+		return true, false
+	}
+	if now.Sub(co.start) >= c.CoherenceTime {
+		var err error
+		if t.joint != nil {
+			_, err = t.measureJoint(c.Rand)
+		} else {
+			_, err = t.measure(c.Rand, "coherence time elapsed")
+		}
+		return true, err == nil
+	}
+	// An entangled register's phases belong to all its qubits, so only its
+	// collapse is simulated
+	if now.After(co.last) && t.joint == nil {
+		decay := math.Exp(-float64(now.Sub(co.last)) / float64(c.CoherenceTime))
+		remaining := math.Exp(-float64(now.Sub(co.start)) / float64(c.CoherenceTime))
+		t.dephase(decay, remaining)
+		co.last = now
+	}
+	return false, false
+}
+
+// dephase scales each state's phase by decay, leaving the probabilities
+// unchanged; remaining is the coherence reported in the audit event
+func (t *RiftToken) dephase(decay, remaining float64) {
+	amps := t.stateVector()
+	changed := false
+	for i, a := range amps {
+		if phase := cmplx.Phase(a); phase != 0 {
+			amps[i] = cmplx.Rect(cmplx.Abs(a), phase*decay)
+			changed = true
+		}
+	}
+	if !changed {
+		return
+	}
+	// The norm is unchanged, so this cannot fail
+	t.setStateVector("decohere", amps)
+	auditEmit(AuditDecohere, t, fmt.Sprintf("coherence %.4f", remaining))
+}
+
+// ============================================================================
+// Background
+// ============================================================================
+
+// Start calls Tick every interval of the package clock (see SetClock)
+// until Stop. It does nothing if the clock is already running.
+func (c *DecoherenceClock) Start(interval time.Duration) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if c.stop != nil {
+		return
+	}
+	stop, done := make(chan struct{}), make(chan struct{})
+	c.stop, c.done = stop, done
+	ticker := CurrentClock().NewTicker(interval)
+	go func() {
+		defer close(done)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C():
+				c.Tick()
+			case <-stop:
+				return
+			}
+		}
+	}()
+}
+
+// Stop halts the background clock and waits for a tick in progress to
+// finish
+func (c *DecoherenceClock) Stop() {
+	c.lock.Lock()
+	stop, done := c.stop, c.done
+	c.stop, c.done = nil, nil
+	c.lock.Unlock()
+	if stop != nil {
+		close(stop)
+		<-done
+	}
+}
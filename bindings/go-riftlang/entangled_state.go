@@ -0,0 +1,217 @@
+// go/target/entangled_state.go
+// Correlated Multi-Qubit States (Bell, GHZ) - Go Implementation
+
+package rift
+
+import (
+	"math"
+	"math/rand"
+	"sync"
+)
+
+// maxRegisterQubits bounds a joint register to the bits of a basis index
+const maxRegisterQubits = 64
+
+// ============================================================================
+// Joint Register
+// ============================================================================
+
+// jointRegister is the shared state vector of entangled qubit tokens. Each
+// member is a two-state superposed token (states 0 and 1) holding one
+// qubit; bit i of a basis index is the value of members[i]. Only non-zero
+// amplitudes are stored, so GHZ states stay small for any width.
+type jointRegister struct {
+	lock    sync.Mutex
+	amps    map[uint64]complex128
+	members []*RiftToken
+}
+
+// BellPair returns two qubit tokens in the Bell state (|00> + |11>)/√2.
+// Measuring either yields 0 or 1 with equal probability and collapses the
+// other to the same value.
+func BellPair() (*RiftToken, *RiftToken) {
+	q := GHZ(2)
+	return q[0], q[1]
+}
+
+// GHZ returns n qubit tokens in the GHZ state (|0...0> + |1...1>)/√2, or nil
+// unless 2 <= n <= 64. Measuring any one collapses all n to the same value.
+func GHZ(n int) []*RiftToken {
+	if n < 2 || n > maxRegisterQubits {
+		return nil
+	}
+	ones := uint64(1)<<n - 1 // all n bits set (the shift yields 0 for n = 64)
+	amp := complex(math.Sqrt2/2, 0)
+	reg := &jointRegister{amps: map[uint64]complex128{0: amp, ones: amp}}
+
+	id := NewEntanglementID()
+	for i := 0; i < n; i++ {
+		reg.members = append(reg.members, newQubitToken(reg, i))
+	}
+	for i, a := range reg.members {
+		for _, b := range reg.members[i+1:] {
+			a.EntangleWith(b, id)
+			b.EntangleWith(a, id)
+		}
+	}
+	reg.refresh(false, "")
+	return reg.members
+}
+
+// newQubitToken creates the superposed token holding qubit i of reg
+func newQubitToken(reg *jointRegister, i int) *RiftToken {
+	memory := NewRiftMemorySpan(SpanSuperposed, 64)
+	memory.Alignment = QuantumAlignment
+	token := NewRiftToken(TokenQGoInt, memory)
+
+	states := make([]*RiftToken, 2)
+	for v := range states {
+		states[v] = NewRiftToken(TokenGoInt, NewRiftMemorySpan(SpanFixed, 64))
+		states[v].Value.IntVal = int64(v)
+		states[v].SetBit(TokenInitialized)
+	}
+	token.SuperposedStates = states
+	token.SuperpositionCount = 2
+	token.joint = reg
+	token.jointQubit = i
+	token.SetBit(TokenSuperposed)
+	auditEmit(AuditSuperpose, token, "")
+	return token
+}
+
+// probability returns the probability that qubit q reads 1; reg.lock held
+func (reg *jointRegister) probability(q int) (p1, total float64) {
+	bit := uint64(1) << q
+	for k, a := range reg.amps {
+		p := real(a)*real(a) + imag(a)*imag(a)
+		total += p
+		if k&bit != 0 {
+			p1 += p
+		}
+	}
+	return p1, total
+}
+
+// project keeps the basis states where qubit q reads outcome and
+// renormalizes; reg.lock held
+func (reg *jointRegister) project(q int, outcome uint32) {
+	bit := uint64(1) << q
+	norm := 0.0
+	for k, a := range reg.amps {
+		if (k&bit != 0) != (outcome == 1) {
+			delete(reg.amps, k)
+			continue
+		}
+		norm += real(a)*real(a) + imag(a)*imag(a)
+	}
+	scale := complex(1/math.Sqrt(norm), 0)
+	for k := range reg.amps {
+		reg.amps[k] *= scale
+	}
+}
+
+// refresh updates each still-superposed member's marginal amplitudes. With
+// settle set, members whose value is now certain collapse to it, recording
+// detail in their audit events.
+func (reg *jointRegister) refresh(settle bool, detail string) {
+	reg.lock.Lock()
+	type settled struct {
+		token   *RiftToken
+		outcome uint32
+	}
+	var collapse []settled
+	for i, m := range reg.members {
+		if !m.HasBit(TokenSuperposed) || m.joint != reg {
+			continue
+		}
+		p1, total := reg.probability(i)
+		p1 /= total
+		switch {
+		case settle && p1 < gateEpsilon:
+			collapse = append(collapse, settled{m, 0})
+		case settle && 1-p1 < gateEpsilon:
+			collapse = append(collapse, settled{m, 1})
+		default:
+			m.Amplitudes = []float64{math.Sqrt(1 - p1), math.Sqrt(p1)}
+			m.Phases = nil
+		}
+	}
+	reg.lock.Unlock()
+
+	for _, s := range collapse {
+		s.token.collapse(s.outcome, detail)
+	}
+}
+
+// ============================================================================
+// Member Operations
+// ============================================================================
+
+// measureJoint samples t's qubit from the joint distribution and collapses
+// t and every member the outcome determines
+func (t *RiftToken) measureJoint(r *rand.Rand) (*RiftToken, error) {
+	reg := t.joint
+	reg.lock.Lock()
+	p1, total := reg.probability(t.jointQubit)
+	if total <= 0 {
+		reg.lock.Unlock()
+		return nil, govErr(CodeZeroProbability, "measure", "entangled register has zero total probability")
+	}
+	var outcome uint32
+	if randFloat64(r)*total < p1 {
+		outcome = 1
+	}
+	reg.project(t.jointQubit, outcome)
+	reg.lock.Unlock()
+
+	state := t.SuperposedStates[outcome]
+	reg.refresh(true, "entangled")
+	return state, nil
+}
+
+// collapseJoint forces t's qubit to outcome and collapses every member the
+// outcome determines
+func (t *RiftToken) collapseJoint(outcome uint32) error {
+	reg := t.joint
+	reg.lock.Lock()
+	p1, total := reg.probability(t.jointQubit)
+	p := p1
+	if outcome == 0 {
+		p = total - p1
+	}
+	if p <= gateEpsilon*total {
+		reg.lock.Unlock()
+		return govErr(CodeZeroProbability, "collapse", "entangled state %d has zero probability", outcome)
+	}
+	reg.project(t.jointQubit, outcome)
+	reg.lock.Unlock()
+
+	reg.refresh(true, "entangled")
+	return nil
+}
+
+// applyJoint applies g to t's qubit of the joint register
+func (t *RiftToken) applyJoint(g Gate) {
+	reg := t.joint
+	bit := uint64(1) << t.jointQubit
+
+	reg.lock.Lock()
+	next := make(map[uint64]complex128, len(reg.amps))
+	for k, a := range reg.amps {
+		b := 0
+		if k&bit != 0 {
+			b = 1
+		}
+		next[k&^bit] += g[0][b] * a
+		next[k|bit] += g[1][b] * a
+	}
+	for k, a := range next {
+		if real(a)*real(a)+imag(a)*imag(a) < gateEpsilon*gateEpsilon {
+			delete(next, k)
+		}
+	}
+	reg.amps = next
+	reg.lock.Unlock()
+
+	reg.refresh(false, "")
+}
@@ -0,0 +1,258 @@
+// go/target/entanglement_graph.go
+// Entanglement Graph Introspection - Go Implementation
+
+package rift
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+)
+
+// ============================================================================
+// EntanglementGraph
+// ============================================================================
+
+// EntanglementGraph is an undirected view of the EntangledWith links among
+// a set of tokens. It is built once from the links present at the time;
+// later entanglements are not reflected.
+type EntanglementGraph struct {
+	tokens []*RiftToken
+	labels []string
+	index  map[*RiftToken]int
+	adj    [][]int // sorted, without duplicates
+}
+
+// NewEntanglementGraph builds the graph of roots and every token reachable
+// from them through EntangledWith. A link in either direction joins two
+// tokens.
+func NewEntanglementGraph(roots ...*RiftToken) *EntanglementGraph {
+	g := &EntanglementGraph{index: make(map[*RiftToken]int)}
+	for _, t := range roots {
+		g.add(t, "")
+	}
+	g.link()
+	return g
+}
+
+// EntanglementGraphOf builds the graph of the tokens in r, labelling them
+// with their registered names in DOT output
+func EntanglementGraphOf(r *TokenRegistry) *EntanglementGraph {
+	g := &EntanglementGraph{index: make(map[*RiftToken]int)}
+	r.Range(func(name string, t *RiftToken) bool {
+		g.add(t, name)
+		return true
+	})
+	g.link()
+	return g
+}
+
+// add adds t if new, labelling it name unless it already has a label
+func (g *EntanglementGraph) add(t *RiftToken, name string) int {
+	if t == nil {
+		return -1
+	}
+	if i, ok := g.index[t]; ok {
+		if g.labels[i] == "" {
+			g.labels[i] = name
+		}
+		return i
+	}
+	i := len(g.tokens)
+	g.index[t] = i
+	g.tokens = append(g.tokens, t)
+	g.labels = append(g.labels, name)
+	return i
+}
+
+// link adds the tokens reachable from the current ones and their edges
+func (g *EntanglementGraph) link() {
+	var edges [][2]int
+	for i := 0; i < len(g.tokens); i++ {
+		for _, p := range g.tokens[i].EntangledWith {
+			if j := g.add(p, ""); j >= 0 && j != i {
+				edges = append(edges, [2]int{i, j})
+			}
+		}
+	}
+
+	g.adj = make([][]int, len(g.tokens))
+	for _, e := range edges {
+		g.adj[e[0]] = append(g.adj[e[0]], e[1])
+		g.adj[e[1]] = append(g.adj[e[1]], e[0])
+	}
+	for i, ns := range g.adj {
+		sort.Ints(ns)
+		out := ns[:0]
+		for k, n := range ns {
+			if k == 0 || n != ns[k-1] {
+				out = append(out, n)
+			}
+		}
+		g.adj[i] = out
+	}
+}
+
+// Tokens returns every token in the graph
+func (g *EntanglementGraph) Tokens() []*RiftToken {
+	return append([]*RiftToken(nil), g.tokens...)
+}
+
+// Len returns the number of tokens in the graph
+func (g *EntanglementGraph) Len() int {
+	return len(g.tokens)
+}
+
+// Partners returns the tokens directly entangled with t, or nil if t is not
+// in the graph
+func (g *EntanglementGraph) Partners(t *RiftToken) []*RiftToken {
+	i, ok := g.index[t]
+	if !ok {
+		return nil
+	}
+	return g.tokensAt(g.adj[i])
+}
+
+// tokensAt maps indices to tokens
+func (g *EntanglementGraph) tokensAt(idx []int) []*RiftToken {
+	out := make([]*RiftToken, len(idx))
+	for k, i := range idx {
+		out[k] = g.tokens[i]
+	}
+	return out
+}
+
+// ============================================================================
+// Components and Cycles
+// ============================================================================
+
+// ConnectedComponent returns every token connected to t by a chain of
+// entanglements, t included, or nil if t is not in the graph
+func (g *EntanglementGraph) ConnectedComponent(t *RiftToken) []*RiftToken {
+	i, ok := g.index[t]
+	if !ok {
+		return nil
+	}
+	return g.tokensAt(g.component(i, make([]bool, len(g.tokens))))
+}
+
+// Components returns the graph's connected components, isolated tokens
+// included
+func (g *EntanglementGraph) Components() [][]*RiftToken {
+	seen := make([]bool, len(g.tokens))
+	var out [][]*RiftToken
+	for i := range g.tokens {
+		if !seen[i] {
+			out = append(out, g.tokensAt(g.component(i, seen)))
+		}
+	}
+	return out
+}
+
+// component returns the sorted indices connected to start, marking them
+// in seen
+func (g *EntanglementGraph) component(start int, seen []bool) []int {
+	seen[start] = true
+	comp := []int{start}
+	for k := 0; k < len(comp); k++ {
+		for _, n := range g.adj[comp[k]] {
+			if !seen[n] {
+				seen[n] = true
+				comp = append(comp, n)
+			}
+		}
+	}
+	sort.Ints(comp)
+	return comp
+}
+
+// FindCycle returns the tokens of a cycle of three or more entanglements,
+// in order, or nil if the graph is a forest. Mutual links between two
+// tokens are a single entanglement, not a cycle.
+func (g *EntanglementGraph) FindCycle() []*RiftToken {
+	parent := make([]int, len(g.tokens))
+	depth := make([]int, len(g.tokens))
+	for i := range parent {
+		parent[i] = -1
+		depth[i] = -1
+	}
+
+	var cycle []int
+	var visit func(i int) bool
+	visit = func(i int) bool {
+		for _, n := range g.adj[i] {
+			if n == parent[i] {
+				continue
+			}
+			if depth[n] >= 0 {
+				if depth[n] < depth[i] {
+					// back edge: walk up from i to n
+					for v := i; v != n; v = parent[v] {
+						cycle = append(cycle, v)
+					}
+					cycle = append(cycle, n)
+					return true
+				}
+				continue
+			}
+			parent[n] = i
+			depth[n] = depth[i] + 1
+			if visit(n) {
+				return true
+			}
+		}
+		return false
+	}
+
+	for i := range g.tokens {
+		if depth[i] < 0 {
+			depth[i] = 0
+			if visit(i) {
+				return g.tokensAt(cycle)
+			}
+		}
+	}
+	return nil
+}
+
+// HasCycle reports whether any entanglements form a cycle
+func (g *EntanglementGraph) HasCycle() bool {
+	return g.FindCycle() != nil
+}
+
+// ============================================================================
+// DOT Export
+// ============================================================================
+
+// WriteDOT writes the graph in Graphviz DOT format. Nodes are labelled with
+// their registered name (or token type) and entanglement ID; superposed
+// tokens are drawn as ellipses, collapsed ones as boxes.
+func (g *EntanglementGraph) WriteDOT(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	bw.WriteString("graph entanglement {\n")
+	for i, t := range g.tokens {
+		label := g.labels[i]
+		if label == "" {
+			label = TokenTypeName(t.Type)
+		}
+		if t.EntanglementID != 0 {
+			label += fmt.Sprintf("\nid %d", t.EntanglementID)
+		}
+		shape := "box"
+		if t.IsSuperposed() {
+			shape = "ellipse"
+		}
+		fmt.Fprintf(bw, "  t%d [label=%s, shape=%s];\n", i, strconv.Quote(label), shape)
+	}
+	for i, ns := range g.adj {
+		for _, n := range ns {
+			if i < n {
+				fmt.Fprintf(bw, "  t%d -- t%d;\n", i, n)
+			}
+		}
+	}
+	bw.WriteString("}\n")
+	return bw.Flush()
+}
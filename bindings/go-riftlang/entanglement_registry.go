@@ -0,0 +1,169 @@
+// go/target/entanglement_registry.go
+// Entanglement ID Allocation and Registry - Go Implementation
+
+package rift
+
+import (
+	"runtime"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"weak"
+)
+
+// ============================================================================
+// ID Allocation
+// ============================================================================
+
+// lastEntanglementID is the last ID NewEntanglementID handed out
+var lastEntanglementID atomic.Uint32
+
+// NewEntanglementID allocates an entanglement ID no live token in this
+// process holds. IDs count up from 1; 0 means "not entangled" and is never
+// returned. After 2^32 allocations the counter wraps and skips IDs still in
+// use. Shared span tokens take the FNV-1a hash of their slot name as their
+// ID, fixed across processes, so they may share an ID with a group
+// allocated here; EntanglementMembers then returns both.
+func NewEntanglementID() uint32 {
+	for {
+		id := lastEntanglementID.Add(1)
+		if id != 0 && !entanglementInUse(id) {
+			return id
+		}
+	}
+}
+
+// ============================================================================
+// Registry
+// ============================================================================
+
+// entanglements maps entanglement IDs to the tokens holding them. Tokens
+// are held weakly, so an entangled token that is dropped without Release is
+// still collected; a cleanup then removes it.
+var entanglements struct {
+	lock   sync.Mutex
+	seq    uint64
+	groups map[uint32]map[weak.Pointer[RiftToken]]uint64 // id -> member -> join order
+	of     map[weak.Pointer[RiftToken]]uint32            // member -> id
+}
+
+// registerEntanglement records t as a member of group id, moving it out of
+// any group it was in before
+func registerEntanglement(t *RiftToken, id uint32) {
+	if id == 0 {
+		unregisterEntanglement(t)
+		return
+	}
+	wp := weak.Make(t)
+	entanglements.lock.Lock()
+	defer entanglements.lock.Unlock()
+	if entanglements.groups == nil {
+		entanglements.groups = make(map[uint32]map[weak.Pointer[RiftToken]]uint64)
+		entanglements.of = make(map[weak.Pointer[RiftToken]]uint32)
+	}
+	old, member := entanglements.of[wp]
+	if member && old == id {
+		return
+	}
+	if member {
+		leaveGroup(wp, old)
+	} else if !t.entCleanup {
+		t.entCleanup = true
+		runtime.AddCleanup(t, forgetEntangled, wp)
+	}
+	group := entanglements.groups[id]
+	if group == nil {
+		group = make(map[weak.Pointer[RiftToken]]uint64)
+		entanglements.groups[id] = group
+	}
+	entanglements.seq++
+	group[wp] = entanglements.seq
+	entanglements.of[wp] = id
+}
+
+// unregisterEntanglement removes t from its group, if any
+func unregisterEntanglement(t *RiftToken) {
+	forgetEntangled(weak.Make(t))
+}
+
+// forgetEntangled removes the token wp points to, live or collected, from
+// its group
+func forgetEntangled(wp weak.Pointer[RiftToken]) {
+	entanglements.lock.Lock()
+	defer entanglements.lock.Unlock()
+	if id, ok := entanglements.of[wp]; ok {
+		leaveGroup(wp, id)
+		delete(entanglements.of, wp)
+	}
+}
+
+// leaveGroup removes wp from group id, dropping the group once empty;
+// entanglements.lock held
+func leaveGroup(wp weak.Pointer[RiftToken], id uint32) {
+	group := entanglements.groups[id]
+	delete(group, wp)
+	if len(group) == 0 {
+		delete(entanglements.groups, id)
+	}
+}
+
+// entanglementInUse reports whether a live token holds id
+func entanglementInUse(id uint32) bool {
+	entanglements.lock.Lock()
+	defer entanglements.lock.Unlock()
+	for wp := range entanglements.groups[id] {
+		if wp.Value() != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// EntanglementMembers returns the live tokens of this process holding
+// entanglement ID id, in the order they joined the group, or nil if there
+// are none. A token joins when EntangleWith or Entangle gives it the ID or
+// a shared span hands it out, and leaves when entangled under another ID
+// or released. Tokens decoded from a snapshot or message keep their ID but
+// do not join.
+func EntanglementMembers(id uint32) []*RiftToken {
+	entanglements.lock.Lock()
+	type member struct {
+		t   *RiftToken
+		seq uint64
+	}
+	var members []member
+	for wp, seq := range entanglements.groups[id] {
+		if t := wp.Value(); t != nil {
+			members = append(members, member{t, seq})
+		}
+	}
+	entanglements.lock.Unlock()
+
+	if len(members) == 0 {
+		return nil
+	}
+	sort.Slice(members, func(i, j int) bool { return members[i].seq < members[j].seq })
+	out := make([]*RiftToken, len(members))
+	for i, m := range members {
+		out[i] = m.t
+	}
+	return out
+}
+
+// EntanglementIDs returns the entanglement IDs held by live tokens of this
+// process, in ascending order
+func EntanglementIDs() []uint32 {
+	entanglements.lock.Lock()
+	defer entanglements.lock.Unlock()
+	ids := make([]uint32, 0, len(entanglements.groups))
+	for id, group := range entanglements.groups {
+		for wp := range group {
+			if wp.Value() != nil {
+				ids = append(ids, id)
+				break
+			}
+		}
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	return ids
+}
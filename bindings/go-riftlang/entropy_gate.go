@@ -0,0 +1,98 @@
+// go/target/entropy_gate.go
+// Entropy-Gated Collapse - Go Implementation
+
+package rift
+
+import "sync"
+
+// ============================================================================
+// EntropyGate
+// ============================================================================
+
+// EntropyGate governs when a superposed token may collapse, based on the
+// Shannon entropy of its amplitudes (see CalculateEntropy). Collapse and
+// Measure are refused while entropy is at or above Threshold. At or above
+// Decoherence the superposition is too mixed to hold: it collapses by
+// measurement as soon as Superpose or a gate produces it, and explicit
+// collapses are always allowed.
+type EntropyGate struct {
+	Threshold   float64 // collapse allowed below this entropy; 0 = no gate
+	Decoherence float64 // forced collapse at or above this entropy; 0 = never
+}
+
+// entropyGates holds the gate for each engine mode
+var entropyGates struct {
+	lock  sync.RWMutex
+	modes map[string]EntropyGate
+}
+
+// SetEntropyGate installs the gate for tokens of an engine mode
+// ("classical" or "quantum"). A zero gate removes it.
+func SetEntropyGate(mode string, gate EntropyGate) {
+	entropyGates.lock.Lock()
+	defer entropyGates.lock.Unlock()
+	if gate == (EntropyGate{}) {
+		delete(entropyGates.modes, mode)
+		return
+	}
+	if entropyGates.modes == nil {
+		entropyGates.modes = make(map[string]EntropyGate)
+	}
+	entropyGates.modes[mode] = gate
+}
+
+// EntropyGateFor returns the gate installed for an engine mode
+func EntropyGateFor(mode string) (EntropyGate, bool) {
+	entropyGates.lock.RLock()
+	defer entropyGates.lock.RUnlock()
+	gate, ok := entropyGates.modes[mode]
+	return gate, ok
+}
+
+// EntropyGate returns the gate described by the policy's entropy and
+// decoherence thresholds
+func (p *Policy) EntropyGate() EntropyGate {
+	p = p.Current()
+	return EntropyGate{Threshold: p.EntropyThreshold, Decoherence: p.DecoherenceThreshold}
+}
+
+// tokenMode returns the engine mode governing a token: quantum token types
+// are "quantum", the rest "classical"
+func tokenMode(t *RiftToken) string {
+	if t.Type == TokenQGoInt || t.Type == TokenQGoChan {
+		return "quantum"
+	}
+	return "classical"
+}
+
+// ============================================================================
+// Gate Checks
+// ============================================================================
+
+// checkEntropyGate refuses a collapse while the token's entropy is between
+// its gate's thresholds
+func (t *RiftToken) checkEntropyGate(op string) error {
+	gate, ok := EntropyGateFor(tokenMode(t))
+	if !ok || gate.Threshold <= 0 {
+		return nil
+	}
+	entropy := CalculateEntropy(t)
+	if entropy < gate.Threshold || gate.Decoherence > 0 && entropy >= gate.Decoherence {
+		return nil
+	}
+	return govErr(CodeEntropyGate, op, "entropy %.4f not below threshold %.4f", entropy, gate.Threshold)
+}
+
+// decohere collapses a superposition whose entropy has reached its gate's
+// decoherence threshold. Entangled registers only collapse by measurement.
+func (t *RiftToken) decohere() {
+	gate, ok := EntropyGateFor(tokenMode(t))
+	if !ok || gate.Decoherence <= 0 || len(t.SuperposedStates) == 0 || t.joint != nil {
+		return
+	}
+	if CalculateEntropy(t) >= gate.Decoherence {
+		// A zero-probability superposition has no entropy, so measure
+		// cannot fail here
+		t.measure(nil, "decoherence")
+	}
+}
@@ -0,0 +1,182 @@
+// go/target/equal.go
+// Governed Token Equality - Go Implementation
+
+package rift
+
+import (
+	"fmt"
+	"math/cmplx"
+	"reflect"
+	"sync/atomic"
+)
+
+// EqualityMode selects how Equal treats superposed tokens
+type EqualityMode int
+
+const (
+	// EqualRequireCollapsed refuses to compare a superposed token: Equal
+	// fails with CodeSuperposed until it is measured or collapsed. This is
+	// the default.
+	EqualRequireCollapsed EqualityMode = iota
+
+	// EqualAllStates compares superpositions state by state. Two superposed
+	// tokens are equal when they hold equal states in the same order with
+	// the same amplitudes and phases, to within AmplitudeEpsilon. A
+	// superposed token never equals a classical one.
+	EqualAllStates
+)
+
+// String returns the mode name
+func (m EqualityMode) String() string {
+	switch m {
+	case EqualRequireCollapsed:
+		return "require-collapsed"
+	case EqualAllStates:
+		return "all-states"
+	}
+	return fmt.Sprintf("EqualityMode(%d)", int(m))
+}
+
+var equalityMode atomic.Int32
+
+// SetSuperposedEquality sets how Equal treats superposed tokens
+func SetSuperposedEquality(mode EqualityMode) {
+	equalityMode.Store(int32(mode))
+}
+
+// SuperposedEquality returns the current equality mode
+func SuperposedEquality() EqualityMode {
+	return EqualityMode(equalityMode.Load())
+}
+
+// ============================================================================
+// Equal
+// ============================================================================
+
+// Equal reports whether a and b hold equal values. Both tokens must be
+// governed (see Validate) and grant AccessRead; Equal holds their read
+// locks, taken in a fixed order, while it compares. Tokens of different
+// types are never equal, nor is an initialized token equal to one that is
+// not. PtrVal values compare with reflect.DeepEqual, and the tokens of
+// ArrVal compare element-wise with Equal, so they must be governed too.
+// Superposed tokens are refused or compared state by state as
+// SetSuperposedEquality says; qubits of an entangled register (BellPair,
+// GHZ) compare by their marginal amplitudes.
+func Equal(a, b *RiftToken) (bool, error) {
+	if a == nil || b == nil {
+		return false, govErr(CodeNilToken, "equal", "nil token")
+	}
+	for _, t := range []*RiftToken{a, b} {
+		if !t.HasBit(TokenGoverned) {
+			return false, t.violation(t.located(govErr(CodeNotGoverned, "equal", "token not governed")))
+		}
+		if err := t.checkAccess("equal", AccessRead); err != nil {
+			return false, err
+		}
+		t.Refresh()
+	}
+
+	unlock, err := rlockPair("equal", a, b)
+	if err != nil {
+		return false, err
+	}
+	defer unlock()
+
+	mode := SuperposedEquality()
+	if mode == EqualRequireCollapsed {
+		for _, t := range []*RiftToken{a, b} {
+			if t.HasBit(TokenSuperposed) {
+				return false, t.located(govErr(CodeSuperposed, "equal", "token superposed; measure or collapse it first"))
+			}
+		}
+	}
+	if a == b {
+		return true, nil
+	}
+	if a.HasBit(TokenSuperposed) || b.HasBit(TokenSuperposed) {
+		return superpositionsEqual(a, b)
+	}
+	return valuesEqual(a.readSource(), b.readSource(), true)
+}
+
+// valuesEqual compares the type, initialization and value of x and y.
+// With locked set, x and y are governed tokens whose value is read under
+// valueLock; superposed states are read directly.
+func valuesEqual(x, y *RiftToken, locked bool) (bool, error) {
+	if x.Type != y.Type || x.HasBit(TokenInitialized) != y.HasBit(TokenInitialized) {
+		return false, nil
+	}
+	xv, yv := x.Value, y.Value
+	if locked {
+		xv, yv = x.snapshotValue(), y.snapshotValue()
+	}
+	if xv.IntVal != yv.IntVal || xv.FloatVal != yv.FloatVal || xv.StringVal != yv.StringVal {
+		return false, nil
+	}
+	if !reflect.DeepEqual(xv.PtrVal, yv.PtrVal) || len(xv.ArrVal) != len(yv.ArrVal) {
+		return false, nil
+	}
+	for i, xe := range xv.ArrVal {
+		ye := yv.ArrVal[i]
+		if xe == nil || ye == nil {
+			if xe != ye {
+				return false, nil
+			}
+			continue
+		}
+		if eq, err := Equal(xe, ye); !eq || err != nil {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+// superpositionsEqual compares the states and amplitudes of a and b, both
+// read-locked
+func superpositionsEqual(a, b *RiftToken) (bool, error) {
+	if a.HasBit(TokenSuperposed) != b.HasBit(TokenSuperposed) || a.Type != b.Type ||
+		len(a.SuperposedStates) != len(b.SuperposedStates) {
+		return false, nil
+	}
+	for i, x := range a.SuperposedStates {
+		if eq, err := valuesEqual(x, b.SuperposedStates[i], false); !eq || err != nil {
+			return false, err
+		}
+	}
+	bAmps := b.stateVector()
+	for i, amp := range a.stateVector() {
+		if cmplx.Abs(amp-bAmps[i]) > AmplitudeEpsilon {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// rlockPair read-locks a and b, which may be the same token, in lockOrder
+// and returns the function releasing them
+func rlockPair(op string, a, b *RiftToken) (func(), error) {
+	first, second := a, b
+	if second.lockOrder() < first.lockOrder() {
+		first, second = second, first
+	}
+	if !first.RLock() {
+		return nil, first.located(govErr(CodeDeadlock, op, "lock would complete a wait cycle"))
+	}
+	if second != first && !second.RLock() {
+		first.RUnlock()
+		return nil, second.located(govErr(CodeDeadlock, op, "lock would complete a wait cycle"))
+	}
+	return func() {
+		if second != first {
+			second.RUnlock()
+		}
+		first.RUnlock()
+	}, nil
+}
+
+// snapshotValue copies the token's value under valueLock
+func (t *RiftToken) snapshotValue() RiftTokenValue {
+	t.valueLock.Lock()
+	defer t.valueLock.Unlock()
+	return t.Value
+}
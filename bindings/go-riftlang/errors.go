@@ -0,0 +1,235 @@
+// go/target/errors.go
+// Typed Governance Errors - Go Implementation
+
+package rift
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ============================================================================
+// Error Codes
+// ============================================================================
+
+// ErrorCode identifies why a governance operation failed. Its values are
+// the Go binding's own; RiftError gives the riftlang.h RIFT_ERROR_* value.
+type ErrorCode int
+
+const (
+	CodeOK ErrorCode = iota
+	CodeNilToken
+	CodeNotAllocated
+	CodeNotInitialized
+	CodeNoMemory
+	CodeBadAlignment
+	CodeNotSuperposed
+	CodeNoStates
+	CodeIndexOutOfRange
+	CodeAmplitudeMismatch
+	CodeZeroProbability
+	CodeSelfEntangle
+	CodeRegexCompile
+	CodeLockTimeout
+	CodePermissionDenied
+	CodeNotGoverned
+	CodeNotShadow
+	CodeCanceled
+	CodeNotQubit
+	CodeBelowThreshold
+	CodeDeadlock
+	CodeTampered
+	CodeEntropyGate
+	CodeSpanBounds
+	CodeConversion
+	CodeExpired
+	CodePanic
+	CodePoolClosed
+	CodePairNotFound
+	CodeNotClonable
+	CodeTxDone
+	CodeConflict
+	CodeNotNormalized
+	CodeSuperposed
+	CodeNoMatch
+	CodeNotLockHolder
+	CodeQuotaExceeded
+	CodeConstraint
+)
+
+var errorCodeNames = map[ErrorCode]string{
+	CodeOK:                "E_OK",
+	CodeNilToken:          "E_NIL_TOKEN",
+	CodeNotAllocated:      "E_NOT_ALLOCATED",
+	CodeNotInitialized:    "E_NOT_INITIALIZED",
+	CodeNoMemory:          "E_NO_MEMORY",
+	CodeBadAlignment:      "E_BAD_ALIGNMENT",
+	CodeNotSuperposed:     "E_NOT_SUPERPOSED",
+	CodeNoStates:          "E_NO_STATES",
+	CodeIndexOutOfRange:   "E_INDEX_OUT_OF_RANGE",
+	CodeAmplitudeMismatch: "E_AMPLITUDE_MISMATCH",
+	CodeZeroProbability:   "E_ZERO_PROBABILITY",
+	CodeSelfEntangle:      "E_SELF_ENTANGLE",
+	CodeRegexCompile:      "E_REGEX_COMPILE",
+	CodeLockTimeout:       "E_LOCK_TIMEOUT",
+	CodePermissionDenied:  "E_PERMISSION_DENIED",
+	CodeNotGoverned:       "E_NOT_GOVERNED",
+	CodeNotShadow:         "E_NOT_SHADOW",
+	CodeCanceled:          "E_CANCELED",
+	CodeNotQubit:          "E_NOT_QUBIT",
+	CodeBelowThreshold:    "E_BELOW_THRESHOLD",
+	CodeDeadlock:          "E_DEADLOCK",
+	CodeTampered:          "E_TAMPERED",
+	CodeEntropyGate:       "E_ENTROPY_GATE",
+	CodeSpanBounds:        "E_SPAN_BOUNDS",
+	CodeConversion:        "E_CONVERSION",
+	CodeExpired:           "E_EXPIRED",
+	CodePanic:             "E_PANIC",
+	CodePoolClosed:        "E_POOL_CLOSED",
+	CodePairNotFound:      "E_PAIR_NOT_FOUND",
+	CodeNotClonable:       "E_NOT_CLONABLE",
+	CodeTxDone:            "E_TX_DONE",
+	CodeConflict:          "E_CONFLICT",
+	CodeNotNormalized:     "E_NOT_NORMALIZED",
+	CodeSuperposed:        "E_SUPERPOSED",
+	CodeNoMatch:           "E_NO_MATCH",
+	CodeNotLockHolder:     "E_NOT_LOCK_HOLDER",
+	CodeQuotaExceeded:     "E_QUOTA_EXCEEDED",
+	CodeConstraint:        "E_CONSTRAINT",
+}
+
+// String returns the riftlang.h-style code name (e.g. E_NOT_ALLOCATED)
+func (c ErrorCode) String() string {
+	if name, ok := errorCodeNames[c]; ok {
+		return name
+	}
+	return fmt.Sprintf("E_UNKNOWN(%d)", int(c))
+}
+
+// ============================================================================
+// riftlang.h Error Codes
+// ============================================================================
+
+// Error codes as riftlang.h defines them (RIFT_OK, RIFT_ERROR_*). The Go
+// binding reports failures with finer-grained ErrorCodes; RiftError maps
+// each onto one of these for C callers and logs shared with them.
+const (
+	RiftOK                   = 0
+	RiftErrorNoMem           = -1
+	RiftErrorInvalidToken    = -2
+	RiftErrorLockFailed      = -3
+	RiftErrorPolicyViolation = -4
+	RiftErrorInvalidPattern  = -5
+	RiftErrorMatchFailed     = -6
+	RiftErrorSerialization   = -7
+	RiftErrorDeserialization = -8
+	RiftErrorQuantumInvalid  = -9
+	RiftErrorEntanglement    = -10
+	RiftErrorCollapse        = -11
+)
+
+var riftErrors = map[ErrorCode]int{
+	CodeOK:                RiftOK,
+	CodeNoMemory:          RiftErrorNoMem,
+	CodeSpanBounds:        RiftErrorNoMem,
+	CodePoolClosed:        RiftErrorNoMem,
+	CodeNilToken:          RiftErrorInvalidToken,
+	CodeNotAllocated:      RiftErrorInvalidToken,
+	CodeNotInitialized:    RiftErrorInvalidToken,
+	CodeBadAlignment:      RiftErrorInvalidToken,
+	CodeNotShadow:         RiftErrorInvalidToken,
+	CodeTampered:          RiftErrorInvalidToken,
+	CodeConversion:        RiftErrorInvalidToken,
+	CodeExpired:           RiftErrorInvalidToken,
+	CodeNotClonable:       RiftErrorInvalidToken,
+	CodeLockTimeout:       RiftErrorLockFailed,
+	CodeCanceled:          RiftErrorLockFailed,
+	CodeDeadlock:          RiftErrorLockFailed,
+	CodeTxDone:            RiftErrorLockFailed,
+	CodeConflict:          RiftErrorLockFailed,
+	CodeNotLockHolder:     RiftErrorLockFailed,
+	CodePermissionDenied:  RiftErrorPolicyViolation,
+	CodeNotGoverned:       RiftErrorPolicyViolation,
+	CodeBelowThreshold:    RiftErrorPolicyViolation,
+	CodeEntropyGate:       RiftErrorPolicyViolation,
+	CodePanic:             RiftErrorPolicyViolation,
+	CodeQuotaExceeded:     RiftErrorPolicyViolation,
+	CodeConstraint:        RiftErrorPolicyViolation,
+	CodeRegexCompile:      RiftErrorInvalidPattern,
+	CodePairNotFound:      RiftErrorMatchFailed,
+	CodeNoMatch:           RiftErrorMatchFailed,
+	CodeNotSuperposed:     RiftErrorQuantumInvalid,
+	CodeNoStates:          RiftErrorQuantumInvalid,
+	CodeIndexOutOfRange:   RiftErrorQuantumInvalid,
+	CodeAmplitudeMismatch: RiftErrorQuantumInvalid,
+	CodeNotQubit:          RiftErrorQuantumInvalid,
+	CodeNotNormalized:     RiftErrorQuantumInvalid,
+	CodeSuperposed:        RiftErrorQuantumInvalid,
+	CodeSelfEntangle:      RiftErrorEntanglement,
+	CodeZeroProbability:   RiftErrorCollapse,
+}
+
+// RiftError returns the riftlang.h error code for c: RIFT_OK for CodeOK,
+// otherwise the RIFT_ERROR_* value of the failure's category. Codes the
+// header has no category for, and unknown codes, map to
+// RIFT_ERROR_POLICY_VIOLATION.
+func (c ErrorCode) RiftError() int {
+	if code, ok := riftErrors[c]; ok {
+		return code
+	}
+	return RiftErrorPolicyViolation
+}
+
+// ============================================================================
+// GovernanceError
+// ============================================================================
+
+// GovernanceError reports a failed governance operation
+type GovernanceError struct {
+	Code   ErrorCode
+	Op     string // operation, e.g. "superpose"
+	Detail string
+	Err    error  // underlying cause, if any
+	Source string // file:line that created the token, if known
+}
+
+// Error implements error
+func (e *GovernanceError) Error() string {
+	msg := fmt.Sprintf("rift: %s: %s", e.Op, e.Code)
+	if e.Detail != "" {
+		msg += ": " + e.Detail
+	}
+	if e.Err != nil {
+		msg += ": " + e.Err.Error()
+	}
+	if e.Source != "" {
+		msg += " (token created at " + e.Source + ")"
+	}
+	return msg
+}
+
+// Unwrap returns the underlying cause
+func (e *GovernanceError) Unwrap() error {
+	return e.Err
+}
+
+// Is matches another *GovernanceError with the same code, so callers can use
+// errors.Is(err, &GovernanceError{Code: CodeNotSuperposed})
+func (e *GovernanceError) Is(target error) bool {
+	t, ok := target.(*GovernanceError)
+	return ok && t.Code == e.Code
+}
+
+// govErr builds a GovernanceError
+func govErr(code ErrorCode, op, format string, args ...interface{}) *GovernanceError {
+	return &GovernanceError{Code: code, Op: op, Detail: fmt.Sprintf(format, args...)}
+}
+
+// ErrorCodeOf returns the code of a GovernanceError in err's chain, or CodeOK
+func ErrorCodeOf(err error) ErrorCode {
+	var ge *GovernanceError
+	if errors.As(err, &ge) {
+		return ge.Code
+	}
+	return CodeOK
+}
@@ -0,0 +1,87 @@
+package rift
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+// TestRiftErrorMirrorsHeader pins the riftlang.h values and checks every
+// named code maps onto one of them
+func TestRiftErrorMirrorsHeader(t *testing.T) {
+	header := map[string]int{
+		"RIFT_OK":                     0,
+		"RIFT_ERROR_NOMEM":            -1,
+		"RIFT_ERROR_INVALID_TOKEN":    -2,
+		"RIFT_ERROR_LOCK_FAILED":      -3,
+		"RIFT_ERROR_POLICY_VIOLATION": -4,
+		"RIFT_ERROR_INVALID_PATTERN":  -5,
+		"RIFT_ERROR_MATCH_FAILED":     -6,
+		"RIFT_ERROR_SERIALIZATION":    -7,
+		"RIFT_ERROR_DESERIALIZATION":  -8,
+		"RIFT_ERROR_QUANTUM_INVALID":  -9,
+		"RIFT_ERROR_ENTANGLEMENT":     -10,
+		"RIFT_ERROR_COLLAPSE":         -11,
+	}
+	got := map[string]int{
+		"RIFT_OK":                     RiftOK,
+		"RIFT_ERROR_NOMEM":            RiftErrorNoMem,
+		"RIFT_ERROR_INVALID_TOKEN":    RiftErrorInvalidToken,
+		"RIFT_ERROR_LOCK_FAILED":      RiftErrorLockFailed,
+		"RIFT_ERROR_POLICY_VIOLATION": RiftErrorPolicyViolation,
+		"RIFT_ERROR_INVALID_PATTERN":  RiftErrorInvalidPattern,
+		"RIFT_ERROR_MATCH_FAILED":     RiftErrorMatchFailed,
+		"RIFT_ERROR_SERIALIZATION":    RiftErrorSerialization,
+		"RIFT_ERROR_DESERIALIZATION":  RiftErrorDeserialization,
+		"RIFT_ERROR_QUANTUM_INVALID":  RiftErrorQuantumInvalid,
+		"RIFT_ERROR_ENTANGLEMENT":     RiftErrorEntanglement,
+		"RIFT_ERROR_COLLAPSE":         RiftErrorCollapse,
+	}
+	for name, want := range header {
+		if got[name] != want {
+			t.Errorf("%s = %d, want %d", name, got[name], want)
+		}
+	}
+
+	for code, name := range errorCodeNames {
+		if _, ok := riftErrors[code]; !ok {
+			t.Errorf("%s has no riftlang.h code", name)
+		}
+		if c := code.RiftError(); c > 0 || c < -11 || (c == 0) != (code == CodeOK) {
+			t.Errorf("%s.RiftError() = %d", name, c)
+		}
+	}
+}
+
+func TestRiftErrorCategories(t *testing.T) {
+	tests := []struct {
+		code ErrorCode
+		want int
+	}{
+		{CodeOK, RiftOK},
+		{CodeNoMemory, RiftErrorNoMem},
+		{CodeNotAllocated, RiftErrorInvalidToken},
+		{CodeLockTimeout, RiftErrorLockFailed},
+		{CodeDeadlock, RiftErrorLockFailed},
+		{CodePermissionDenied, RiftErrorPolicyViolation},
+		{CodeRegexCompile, RiftErrorInvalidPattern},
+		{CodeNoMatch, RiftErrorMatchFailed},
+		{CodeNotSuperposed, RiftErrorQuantumInvalid},
+		{CodeSelfEntangle, RiftErrorEntanglement},
+		{CodeZeroProbability, RiftErrorCollapse},
+		{ErrorCode(999), RiftErrorPolicyViolation},
+	}
+	for _, tt := range tests {
+		if got := tt.code.RiftError(); got != tt.want {
+			t.Errorf("%v.RiftError() = %d, want %d", tt.code, got, tt.want)
+		}
+	}
+
+	err := fmt.Errorf("wrapped: %w", govErr(CodeDeadlock, "lock", "cycle"))
+	if got := ErrorCodeOf(err).RiftError(); got != RiftErrorLockFailed {
+		t.Errorf("wrapped deadlock maps to %d, want %d", got, RiftErrorLockFailed)
+	}
+	if !errors.Is(err, &GovernanceError{Code: CodeDeadlock}) {
+		t.Error("errors.Is does not match the wrapped code")
+	}
+}
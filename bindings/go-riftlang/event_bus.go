@@ -0,0 +1,187 @@
+// go/target/event_bus.go
+// Token Event Bus - Go Implementation
+
+package rift
+
+import (
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// ============================================================================
+// Events
+// ============================================================================
+
+// TokenEventKind is a kind of governance event; kinds are bits, so a set
+// of kinds is their OR
+type TokenEventKind uint32
+
+const (
+	EventCreated    TokenEventKind = 1 << iota // NewRiftToken or a TokenArena made the token
+	EventRegistered                            // the token was registered in DefaultRegistry
+	EventValidated                             // the token passed validation and is governed
+	EventLocked                                // the token's write lock was acquired
+	EventCollapsed                             // the token collapsed out of superposition
+	EventReleased                              // Release ended the token's lifetime
+)
+
+// String returns the kind's name, or the names of a set's kinds joined
+// with |
+func (k TokenEventKind) String() string {
+	names := []string{"created", "registered", "validated", "locked", "collapsed", "released"}
+	var set []string
+	for i, name := range names {
+		if k&(1<<i) != 0 {
+			set = append(set, name)
+		}
+	}
+	if len(set) == 0 {
+		return "none"
+	}
+	return strings.Join(set, "|")
+}
+
+// TokenEvent is a governance event published on the event bus. Bits are
+// the token's validation bits when the event was published; Name is the
+// token's DefaultRegistry name (see NameOf), "" before it is registered.
+type TokenEvent struct {
+	Kind  TokenEventKind
+	Token *RiftToken
+	Name  string
+	Type  int
+	Bits  uint32
+	Time  time.Time
+}
+
+// EventFilter selects the events a subscriber receives. Every set field
+// must match; the zero filter matches every event.
+type EventFilter struct {
+	Kinds     TokenEventKind // any of these kinds; 0 is all
+	Types     []int          // any of these token types; empty is all
+	Namespace string         // DefaultRegistry names in this namespace ("pkg" or "pkg.sub"); "" is all
+	Bits      uint32         // tokens with all these validation bits
+}
+
+// match reports whether ev passes the filter
+func (f *EventFilter) match(ev *TokenEvent) bool {
+	if f.Kinds != 0 && f.Kinds&ev.Kind == 0 {
+		return false
+	}
+	if len(f.Types) > 0 && !slices.Contains(f.Types, ev.Type) {
+		return false
+	}
+	if f.Bits != 0 && ev.Bits&f.Bits != f.Bits {
+		return false
+	}
+	if f.Namespace != "" && !strings.HasPrefix(ev.Name, f.Namespace+NamespaceSeparator) {
+		return false
+	}
+	return true
+}
+
+// ============================================================================
+// Subscriptions
+// ============================================================================
+
+// subscriber is a filter and the func receiving its events
+type subscriber struct {
+	filter EventFilter
+	fn     func(TokenEvent)
+}
+
+// bus holds the subscribers, replaced whole on each change so publishing
+// reads them without locking
+var bus struct {
+	lock sync.Mutex
+	subs atomic.Pointer[[]*subscriber]
+}
+
+// Subscribe calls fn with each event passing filter, on the goroutine
+// that caused it and before the operation returns, until the returned
+// func is called. fn must not block; hand events to another goroutine,
+// or use SubscribeChan, for slow work. The filter's Types slice is
+// copied.
+func Subscribe(filter EventFilter, fn func(TokenEvent)) (unsubscribe func()) {
+	filter.Types = slices.Clone(filter.Types)
+	sub := &subscriber{filter: filter, fn: fn}
+
+	bus.lock.Lock()
+	defer bus.lock.Unlock()
+	var subs []*subscriber
+	if p := bus.subs.Load(); p != nil {
+		subs = *p
+	}
+	subs = append(slices.Clip(subs), sub)
+	bus.subs.Store(&subs)
+
+	return func() {
+		bus.lock.Lock()
+		defer bus.lock.Unlock()
+		p := bus.subs.Load()
+		if p == nil {
+			return
+		}
+		i := slices.Index(*p, sub)
+		if i < 0 {
+			return
+		}
+		rest := slices.Delete(slices.Clone(*p), i, i+1)
+		bus.subs.Store(&rest)
+	}
+}
+
+// SubscribeChan delivers the events passing filter on a channel buffering
+// size of them, for consumers running on their own goroutine. Events
+// arriving while the buffer is full are dropped rather than slowing
+// governance. The returned func ends the subscription and closes the
+// channel.
+func SubscribeChan(filter EventFilter, size int) (<-chan TokenEvent, func()) {
+	ch := make(chan TokenEvent, size)
+	var lock sync.Mutex
+	closed := false
+	unsubscribe := Subscribe(filter, func(ev TokenEvent) {
+		lock.Lock()
+		defer lock.Unlock()
+		if closed {
+			return
+		}
+		select {
+		case ch <- ev:
+		default:
+		}
+	})
+	return ch, func() {
+		unsubscribe()
+		lock.Lock()
+		defer lock.Unlock()
+		if !closed {
+			closed = true
+			close(ch)
+		}
+	}
+}
+
+// ============================================================================
+// Publishing
+// ============================================================================
+
+// publishEvent delivers an event for t to the matching subscribers. name
+// is the token's registry name if the caller knows it, "" to look it up.
+func publishEvent(kind TokenEventKind, t *RiftToken, name string) {
+	p := bus.subs.Load()
+	if p == nil || len(*p) == 0 {
+		return
+	}
+	if name == "" {
+		name, _ = DefaultRegistry.NameOf(t)
+	}
+	ev := TokenEvent{Kind: kind, Token: t, Name: name, Type: t.Type, Bits: t.ValidationBits.Load(), Time: clockNow()}
+	for _, sub := range *p {
+		if sub.filter.match(&ev) {
+			sub.fn(ev)
+		}
+	}
+}
@@ -0,0 +1,161 @@
+// go/target/governance_policy.go
+// Per-Type Governance Policy - Go Implementation
+
+package rift
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ============================================================================
+// TypeRule
+// ============================================================================
+
+// TypeRule holds the governance constraints for one token type. Each
+// required bit, the alignment, the superposition bound and the memory span
+// count as one check; a token passes when the fraction of checks it meets
+// reaches Threshold.
+type TypeRule struct {
+	Threshold        float64 // minimum fraction of passing checks (0..1)
+	RequiredBits     uint32  // validation bits that must be set
+	MaxSuperposition uint32  // maximum superposed states; 0 = unlimited
+	Alignment        uint32  // span alignment must be a multiple; 0 = any
+	LossyConversion  bool    // AsWith may lose precision converting to this type
+}
+
+// DefaultTypeRule is the rule applied to token types without their own rule
+func DefaultTypeRule() TypeRule {
+	return TypeRule{
+		Threshold:    DefaultThreshold,
+		RequiredBits: TokenAllocated | TokenInitialized,
+	}
+}
+
+// ============================================================================
+// GovernancePolicy
+// ============================================================================
+
+// GovernancePolicy maps token types to validation rules
+type GovernancePolicy struct {
+	Default TypeRule
+	Types   map[int]TypeRule
+}
+
+// NewGovernancePolicy creates a policy applying DefaultTypeRule to all types
+func NewGovernancePolicy() *GovernancePolicy {
+	return &GovernancePolicy{
+		Default: DefaultTypeRule(),
+		Types:   make(map[int]TypeRule),
+	}
+}
+
+// SetRule sets the rule for a token type
+func (p *GovernancePolicy) SetRule(tokenType int, rule TypeRule) {
+	if p.Types == nil {
+		p.Types = make(map[int]TypeRule)
+	}
+	p.Types[tokenType] = rule
+}
+
+// RuleFor returns the rule for a token type
+func (p *GovernancePolicy) RuleFor(tokenType int) TypeRule {
+	if rule, ok := p.Types[tokenType]; ok {
+		return rule
+	}
+	return p.Default
+}
+
+// Score returns the fraction of rule checks the token meets and a
+// description of each failed check
+func (p *GovernancePolicy) Score(t *RiftToken) (float64, []string) {
+	rule := p.RuleFor(t.Type)
+	var failed []string
+	checks := 0
+
+	for bit := uint32(1); bit != 0 && bit <= rule.RequiredBits; bit <<= 1 {
+		if rule.RequiredBits&bit == 0 {
+			continue
+		}
+		checks++
+		if !t.HasBit(bit) {
+			failed = append(failed, fmt.Sprintf("missing bit 0x%02x", bit))
+		}
+	}
+
+	checks++
+	if t.Memory == nil || !t.Memory.ValidateAlignment() {
+		failed = append(failed, "memory span missing or unaligned")
+	} else if rule.Alignment > 0 {
+		checks++
+		if t.Memory.Alignment%rule.Alignment != 0 {
+			failed = append(failed, fmt.Sprintf("alignment %d not a multiple of %d", t.Memory.Alignment, rule.Alignment))
+		}
+	}
+
+	if rule.MaxSuperposition > 0 {
+		checks++
+		if t.SuperpositionCount > rule.MaxSuperposition {
+			failed = append(failed, fmt.Sprintf("%d superposed states exceed %d", t.SuperpositionCount, rule.MaxSuperposition))
+		}
+	}
+
+	return float64(checks-len(failed)) / float64(checks), failed
+}
+
+// ValidateAgainst validates the token structurally and then against the
+// policy rule for its type, marking it governed on success
+func (t *RiftToken) ValidateAgainst(p *GovernancePolicy) error {
+	if p == nil {
+		return t.ValidateErr()
+	}
+	if err := t.validationError(); err != nil {
+		t.located(err)
+		auditEmit(AuditValidateFail, t, err.Error())
+		t.fireValidate(err)
+		return t.violation(err)
+	}
+
+	rule := p.RuleFor(t.Type)
+	if score, failed := p.Score(t); score < rule.Threshold {
+		err := t.located(govErr(CodeBelowThreshold, "validate", "score %.2f below %s threshold %.2f: %s",
+			score, TokenTypeName(t.Type), rule.Threshold, strings.Join(failed, "; ")))
+		auditEmit(AuditValidateFail, t, err.Error())
+		t.fireValidate(err)
+		return t.violation(err)
+	}
+
+	t.SetBit(TokenGoverned)
+	t.fireValidate(nil)
+	publishEvent(EventValidated, t, "")
+	return nil
+}
+
+// GovernancePolicy derives per-type rules from a .rift policy: the policy
+// threshold applies to every type, `memory: aligned(n)` type fields set
+// the type's alignment, and `conversion: lossy` allows lossy conversions
+// to the type
+func (p *Policy) GovernancePolicy() *GovernancePolicy {
+	p = p.Current()
+	gp := NewGovernancePolicy()
+	if p.ValidationThreshold > 0 {
+		gp.Default.Threshold = p.ValidationThreshold
+	}
+	for tokenType := TokenGoInt; tokenType <= TokenGoPtr; tokenType++ {
+		fields, ok := p.Types[TokenTypeName(tokenType)]
+		if !ok {
+			continue
+		}
+		rule := gp.Default
+		if mem := fields["memory"]; mem != nil {
+			if kind, n, err := parseCall(mem.Scalar); err == nil && kind == "aligned" && n > 0 {
+				rule.Alignment = uint32(n)
+			}
+		}
+		if conv := fields["conversion"]; conv != nil {
+			rule.LossyConversion = conv.Scalar == "lossy"
+		}
+		gp.SetRule(tokenType, rule)
+	}
+	return gp
+}
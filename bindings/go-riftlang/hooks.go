@@ -0,0 +1,134 @@
+// go/target/hooks.go
+// Token Lifecycle Hooks - Go Implementation
+
+package rift
+
+import (
+	"sync"
+)
+
+// ============================================================================
+// Hook Types
+// ============================================================================
+
+// ChangeHook observes a token value change
+type ChangeHook func(old, new RiftTokenValue)
+
+// ValidateHook observes a validation; err is nil when the token passed
+type ValidateHook func(err error)
+
+// CollapseHook observes a collapse to the state at index
+type CollapseHook func(state *RiftToken, index uint32)
+
+// hookEntry is a registered hook with the id used to remove it
+type hookEntry[F any] struct {
+	id uint64
+	fn F
+}
+
+// tokenHooks holds a token's registered observers in registration order
+type tokenHooks struct {
+	lock     sync.Mutex
+	nextID   uint64
+	change   []hookEntry[ChangeHook]
+	validate []hookEntry[ValidateHook]
+	collapse []hookEntry[CollapseHook]
+	release  []hookEntry[ReleaseHook] // see OnRelease
+	check    []hookEntry[Constraint]  // see AddConstraint
+
+	violation ViolationHandler // see SetViolationHandler
+}
+
+// hookSet returns the token's hooks, allocating them on first use
+func (t *RiftToken) hookSet() *tokenHooks {
+	if h := t.hooks.Load(); h != nil {
+		return h
+	}
+	t.hooks.CompareAndSwap(nil, &tokenHooks{})
+	return t.hooks.Load()
+}
+
+// addHook appends fn to list and returns a func removing it
+func addHook[F any](h *tokenHooks, list *[]hookEntry[F], fn F) func() {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	h.nextID++
+	id := h.nextID
+	*list = append(*list, hookEntry[F]{id: id, fn: fn})
+
+	return func() {
+		h.lock.Lock()
+		defer h.lock.Unlock()
+		for i, e := range *list {
+			if e.id == id {
+				*list = append((*list)[:i:i], (*list)[i+1:]...)
+				return
+			}
+		}
+	}
+}
+
+// hookFuncs snapshots list so hooks run outside the lock and may register
+// or remove hooks themselves
+func hookFuncs[F any](h *tokenHooks, list *[]hookEntry[F]) []F {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	fns := make([]F, len(*list))
+	for i, e := range *list {
+		fns[i] = e.fn
+	}
+	return fns
+}
+
+// ============================================================================
+// Registration
+// ============================================================================
+
+// OnChange registers fn to run after SetValue, Delete or Collapse changes the
+// token value. The returned func removes the hook.
+func (t *RiftToken) OnChange(fn ChangeHook) (remove func()) {
+	h := t.hookSet()
+	return addHook(h, &h.change, fn)
+}
+
+// OnValidate registers fn to run after every validation of the token. The
+// returned func removes the hook.
+func (t *RiftToken) OnValidate(fn ValidateHook) (remove func()) {
+	h := t.hookSet()
+	return addHook(h, &h.validate, fn)
+}
+
+// OnCollapse registers fn to run after the token collapses out of
+// superposition. The returned func removes the hook.
+func (t *RiftToken) OnCollapse(fn CollapseHook) (remove func()) {
+	h := t.hookSet()
+	return addHook(h, &h.collapse, fn)
+}
+
+// ============================================================================
+// Dispatch
+// ============================================================================
+
+func (t *RiftToken) fireChange(old, new RiftTokenValue) {
+	if h := t.hooks.Load(); h != nil {
+		for _, fn := range hookFuncs(h, &h.change) {
+			fn(old, new)
+		}
+	}
+}
+
+func (t *RiftToken) fireValidate(err error) {
+	if h := t.hooks.Load(); h != nil {
+		for _, fn := range hookFuncs(h, &h.validate) {
+			fn(err)
+		}
+	}
+}
+
+func (t *RiftToken) fireCollapse(state *RiftToken, index uint32) {
+	if h := t.hooks.Load(); h != nil {
+		for _, fn := range hookFuncs(h, &h.collapse) {
+			fn(state, index)
+		}
+	}
+}
@@ -0,0 +1,149 @@
+// go/target/integrity.go
+// Token Checksums and Tamper Detection - Go Implementation
+
+package rift
+
+import (
+	"encoding/binary"
+	"fmt"
+	"hash/crc64"
+	"math"
+	"sync/atomic"
+)
+
+// integrityBits are the validation bits covered by the checksum; the rest
+// (locked, governed, superposed, ...) change too often to seal
+const integrityBits = TokenAllocated | TokenInitialized
+
+var (
+	integrityMode  atomic.Bool
+	integrityTable = crc64.MakeTable(crc64.ECMA)
+)
+
+// SetIntegrityMode turns token checksums on or off. While on, a token is
+// sealed with a CRC-64 of its type, value and memory metadata the first time
+// it validates, resealed by SetValue, Delete and Collapse, and fails
+// validation with a TamperError if those fields change any other way.
+// Access masks are not covered, since Grant and Revoke change them by design.
+func SetIntegrityMode(on bool) {
+	integrityMode.Store(on)
+}
+
+// IntegrityMode reports whether token checksums are enabled
+func IntegrityMode() bool {
+	return integrityMode.Load()
+}
+
+// ============================================================================
+// TamperError
+// ============================================================================
+
+// TamperError reports a token whose fields no longer match its checksum
+type TamperError struct {
+	Expected uint64
+	Actual   uint64
+}
+
+// Error implements error
+func (e *TamperError) Error() string {
+	return fmt.Sprintf("token checksum %016x does not match sealed %016x", e.Actual, e.Expected)
+}
+
+// ============================================================================
+// Checksums
+// ============================================================================
+
+// Checksum returns the CRC-64 of the token's type, value, memory metadata
+// and allocation bits
+func (t *RiftToken) Checksum() uint64 {
+	t.valueLock.Lock()
+	defer t.valueLock.Unlock()
+	return t.sum()
+}
+
+// sum implements Checksum; valueLock held
+func (t *RiftToken) sum() uint64 {
+	var buf []byte
+	buf = binary.AppendVarint(buf, int64(t.Type))
+	buf = binary.AppendUvarint(buf, uint64(t.ValidationBits.Load()&integrityBits))
+
+	buf = binary.AppendVarint(buf, t.Value.IntVal)
+	buf = binary.LittleEndian.AppendUint64(buf, math.Float64bits(t.Value.FloatVal))
+	buf = binary.AppendUvarint(buf, uint64(len(t.Value.StringVal)))
+	buf = append(buf, t.Value.StringVal...)
+	if t.Value.PtrVal != nil {
+		buf = fmt.Appendf(buf, "%T|%v", t.Value.PtrVal, t.Value.PtrVal)
+	}
+	buf = binary.AppendUvarint(buf, uint64(len(t.Value.ArrVal)))
+	for _, elem := range t.Value.ArrVal {
+		buf = fmt.Appendf(buf, "%p", elem)
+	}
+
+	if t.Memory != nil {
+		buf = binary.AppendUvarint(buf, uint64(t.Memory.Type))
+		buf = binary.AppendUvarint(buf, t.Memory.Bytes)
+		buf = binary.AppendUvarint(buf, uint64(t.Memory.Alignment))
+		var flags byte = 4
+		if t.Memory.Open {
+			flags |= 1
+		}
+		if t.Memory.Direction {
+			flags |= 2
+		}
+		buf = append(buf, flags)
+	}
+	return crc64.Checksum(buf, integrityTable)
+}
+
+// Sealed reports whether the token carries a checksum
+func (t *RiftToken) Sealed() bool {
+	t.valueLock.Lock()
+	defer t.valueLock.Unlock()
+	return t.sealed
+}
+
+// reseal refreshes the checksum of a sealed token after a governed change
+func (t *RiftToken) reseal() {
+	t.valueLock.Lock()
+	t.resealLocked()
+	t.valueLock.Unlock()
+}
+
+// resealLocked implements reseal; valueLock held
+func (t *RiftToken) resealLocked() {
+	if t.sealed && integrityMode.Load() {
+		t.seal(t.sum())
+	}
+}
+
+// seal records sum as the token's checksum, along with the span resize
+// count it covers; valueLock held
+func (t *RiftToken) seal(sum uint64) {
+	t.checksum = sum
+	t.sealed = true
+	if t.Memory != nil {
+		t.sealedResizes = t.Memory.resizes.Load()
+	}
+}
+
+// checkIntegrity verifies a sealed token's checksum, sealing tokens seen
+// for the first time. It is a no-op while integrity mode is off. The sum is
+// computed and compared under valueLock, so a concurrent write cannot seal
+// a value the sum does not cover.
+func (t *RiftToken) checkIntegrity() *GovernanceError {
+	if !integrityMode.Load() {
+		return nil
+	}
+	t.valueLock.Lock()
+	defer t.valueLock.Unlock()
+	sum := t.sum()
+	if !t.sealed || t.Memory != nil && t.Memory.resizes.Load() != t.sealedResizes {
+		// First validation, or the span was resized through Resize
+		t.seal(sum)
+		return nil
+	}
+	if sum != t.checksum {
+		return &GovernanceError{Code: CodeTampered, Op: "validate", Err: &TamperError{Expected: t.checksum, Actual: sum}}
+	}
+	return nil
+}
@@ -0,0 +1,49 @@
+package rift
+
+import (
+	"sync"
+	"testing"
+)
+
+// TestIntegrityConcurrentWrites validates a sealed token while other
+// goroutines write it: governed writes must never read as tampering, and
+// sealing must not race with them (run with -race)
+func TestIntegrityConcurrentWrites(t *testing.T) {
+	SetIntegrityMode(true)
+	t.Cleanup(func() { SetIntegrityMode(false) })
+
+	tok := NewRiftToken(TokenGoString, NewRiftMemorySpan(SpanFixed, 64))
+	if err := tok.SetValue(RiftTokenValue{StringVal: "a"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := tok.ValidateErr(); err != nil || !tok.Sealed() {
+		t.Fatalf("first validation: %v, sealed %v", err, tok.Sealed())
+	}
+
+	const n = 200
+	var wg sync.WaitGroup
+	for range 2 {
+		wg.Go(func() {
+			for i := range n {
+				if err := tok.SetValue(RiftTokenValue{StringVal: string(rune('a' + i%26))}); err != nil {
+					t.Error(err)
+					return
+				}
+			}
+		})
+		wg.Go(func() {
+			for range n {
+				if err := tok.ValidateErr(); err != nil {
+					t.Error(err)
+					return
+				}
+			}
+		})
+	}
+	wg.Wait()
+
+	tok.Value.StringVal = "tampered"
+	if code := ErrorCodeOf(tok.ValidateErr()); code != CodeTampered {
+		t.Fatalf("Validate code %v after a direct write, want %v", code, CodeTampered)
+	}
+}
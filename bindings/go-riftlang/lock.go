@@ -0,0 +1,224 @@
+// go/target/lock.go
+// Cancellable Token Lock - Go Implementation
+
+package rift
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// ErrLockTimeout is returned when a token lock cannot be acquired in time
+var ErrLockTimeout = errors.New("token lock acquisition timed out")
+
+// ============================================================================
+// rwLock
+// ============================================================================
+
+// rwLock is a reader/writer lock whose acquisition can be abandoned. Waiters
+// park on a channel that is closed whenever the lock state changes. Pending
+// writers hold off new readers so writers are not starved.
+type rwLock struct {
+	mu             sync.Mutex
+	writer         bool
+	readers        int
+	writersWaiting int
+	changed        chan struct{}
+	contention     atomic.Uint64 // acquisitions that had to wait
+}
+
+// waitChan returns the channel closed on the next state change; l.mu held
+func (l *rwLock) waitChan() chan struct{} {
+	if l.changed == nil {
+		l.changed = make(chan struct{})
+	}
+	return l.changed
+}
+
+// broadcast wakes all waiters; l.mu held
+func (l *rwLock) broadcast() {
+	if l.changed != nil {
+		close(l.changed)
+		l.changed = nil
+	}
+}
+
+// tryLock acquires the write lock without waiting
+func (l *rwLock) tryLock() bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.writer || l.readers > 0 {
+		return false
+	}
+	l.writer = true
+	return true
+}
+
+// lock acquires the write lock, giving up when done fires (nil never fires)
+func (l *rwLock) lock(done <-chan struct{}) bool {
+	l.mu.Lock()
+	if !l.writer && l.readers == 0 {
+		l.writer = true
+		l.mu.Unlock()
+		return true
+	}
+	l.contention.Add(1)
+	lockContention.Add(1)
+	l.writersWaiting++
+	for l.writer || l.readers > 0 {
+		ch := l.waitChan()
+		l.mu.Unlock()
+		select {
+		case <-ch:
+		case <-done:
+			l.mu.Lock()
+			l.writersWaiting--
+			l.broadcast() // readers may have been held off by us
+			l.mu.Unlock()
+			return false
+		}
+		l.mu.Lock()
+	}
+	l.writersWaiting--
+	l.writer = true
+	l.mu.Unlock()
+	return true
+}
+
+// unlock releases the write lock
+func (l *rwLock) unlock() {
+	l.mu.Lock()
+	if !l.writer {
+		l.mu.Unlock()
+		panic("rift: unlock of unlocked token lock")
+	}
+	l.writer = false
+	l.broadcast()
+	l.mu.Unlock()
+}
+
+// rlock acquires a read lock, giving up when done fires (nil never fires)
+func (l *rwLock) rlock(done <-chan struct{}) bool {
+	l.mu.Lock()
+	waited := false
+	for l.writer || l.writersWaiting > 0 {
+		if !waited {
+			waited = true
+			l.contention.Add(1)
+			lockContention.Add(1)
+		}
+		ch := l.waitChan()
+		l.mu.Unlock()
+		select {
+		case <-ch:
+		case <-done:
+			return false
+		}
+		l.mu.Lock()
+	}
+	l.readers++
+	l.mu.Unlock()
+	return true
+}
+
+// readCount returns the number of read locks held
+func (l *rwLock) readCount() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.readers
+}
+
+// state reports whether the write lock is held and how many read locks are
+func (l *rwLock) state() (bool, int) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.writer, l.readers
+}
+
+// runlock releases a read lock, reporting false if none is held. The check
+// and the release are one step, so of two racing callers for the last read
+// lock only one succeeds.
+func (l *rwLock) runlock() bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.readers == 0 {
+		return false
+	}
+	l.readers--
+	if l.readers == 0 {
+		l.broadcast()
+	}
+	return true
+}
+
+// ============================================================================
+// Token Lock Variants
+// ============================================================================
+
+// TryLock acquires the token lock only if it is free
+func (t *RiftToken) TryLock() bool {
+	if !t.lock.tryLock() {
+		return false
+	}
+	deadlockAcquired(t, 0, true)
+	t.locked()
+	return true
+}
+
+// LockTimeout acquires the token lock, failing with ErrLockTimeout after d
+func (t *RiftToken) LockTimeout(d time.Duration) error {
+	ctx, cancel := context.WithTimeout(context.Background(), d)
+	defer cancel()
+
+	gid, err := deadlockWait(t, true)
+	if err != nil {
+		return err
+	}
+	if !t.lock.lock(ctx.Done()) {
+		deadlockAbandon(gid)
+		return &GovernanceError{Code: CodeLockTimeout, Op: "lock", Detail: fmt.Sprintf("after %v", d), Err: ErrLockTimeout}
+	}
+	deadlockAcquired(t, gid, true)
+	t.locked()
+	return nil
+}
+
+// LockContext acquires the token lock, giving up when ctx is done. The
+// returned error wraps ctx.Err(): CodeLockTimeout for an expired deadline,
+// CodeCanceled otherwise.
+func (t *RiftToken) LockContext(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return contextError("lock", CodeLockTimeout, err)
+	}
+	gid, err := deadlockWait(t, true)
+	if err != nil {
+		return err
+	}
+	if !t.lock.lock(ctx.Done()) {
+		deadlockAbandon(gid)
+		return contextError("lock", CodeLockTimeout, ctx.Err())
+	}
+	deadlockAcquired(t, gid, true)
+	t.bindSpan(ctx)
+	t.locked()
+	return nil
+}
+
+// LockContention returns how many lock acquisitions on the token had to wait
+func (t *RiftToken) LockContention() uint64 {
+	return t.lock.contention.Load()
+}
+
+// locked records a successful write-lock acquisition
+func (t *RiftToken) locked() {
+	t.lockCount++
+	t.SetBit(TokenLocked)
+	ownerAcquired(t, true)
+	auditEmit(AuditLock, t, "")
+	t.traceEvent(traceEventLock)
+	publishEvent(EventLocked, t, "")
+}
@@ -0,0 +1,134 @@
+// go/target/lock_owner.go
+// Token Lock Ownership Tracking - Go Implementation
+
+package rift
+
+import (
+	"sort"
+	"sync"
+	"sync/atomic"
+)
+
+// ============================================================================
+// Configuration
+// ============================================================================
+
+// lockOwners records which goroutines hold token locks while ownership
+// tracking is on
+var lockOwners struct {
+	on      atomic.Bool
+	lock    sync.Mutex
+	writers map[*RiftToken]uint64         // token -> write-lock holder
+	readers map[*RiftToken]map[uint64]int // token -> goroutine -> read holds
+}
+
+// SetLockOwnership turns lock ownership tracking on or off. While it is on,
+// every token lock records the goroutine taking it, and Unlock or RUnlock
+// from a goroutine not holding the lock is a violation (CodeNotLockHolder)
+// and leaves the lock held. Tracking costs a stack read per lock call and
+// forbids handing a lock to another goroutine to release, so it is off by
+// default. It starts from the next lock call: locks already held are not
+// checked.
+//
+// Unlocking a token that is not locked at all is a violation whether or
+// not tracking is on.
+func SetLockOwnership(on bool) {
+	lockOwners.lock.Lock()
+	defer lockOwners.lock.Unlock()
+	lockOwners.writers = make(map[*RiftToken]uint64)
+	lockOwners.readers = make(map[*RiftToken]map[uint64]int)
+	lockOwners.on.Store(on)
+}
+
+// LockOwnership reports whether lock ownership tracking is on
+func LockOwnership() bool {
+	return lockOwners.on.Load()
+}
+
+// ============================================================================
+// Tracking
+// ============================================================================
+
+// ownerAcquired records the calling goroutine as holding t
+func ownerAcquired(t *RiftToken, write bool) {
+	if !lockOwners.on.Load() {
+		return
+	}
+	gid := goroutineID()
+	lockOwners.lock.Lock()
+	defer lockOwners.lock.Unlock()
+	if write {
+		lockOwners.writers[t] = gid
+		return
+	}
+	held := lockOwners.readers[t]
+	if held == nil {
+		held = make(map[uint64]int)
+		lockOwners.readers[t] = held
+	}
+	held[gid]++
+}
+
+// ownerReleasing checks that the calling goroutine holds t and drops its
+// hold, or returns why it may not release t
+func ownerReleasing(t *RiftToken, write bool) *GovernanceError {
+	if !lockOwners.on.Load() {
+		return nil
+	}
+	gid := goroutineID()
+	lockOwners.lock.Lock()
+	defer lockOwners.lock.Unlock()
+	if write {
+		owner, ok := lockOwners.writers[t]
+		if !ok {
+			return nil // locked before tracking started
+		}
+		if owner != gid {
+			return govErr(CodeNotLockHolder, "unlock", "locked by goroutine %d, unlocked by goroutine %d", owner, gid)
+		}
+		delete(lockOwners.writers, t)
+		return nil
+	}
+
+	held := lockOwners.readers[t]
+	if len(held) == 0 {
+		return nil
+	}
+	if held[gid] == 0 {
+		holders := make([]uint64, 0, len(held))
+		for g := range held {
+			holders = append(holders, g)
+		}
+		sort.Slice(holders, func(i, j int) bool { return holders[i] < holders[j] })
+		return govErr(CodeNotLockHolder, "runlock", "goroutine %d holds no read lock (held by goroutines %v)", gid, holders)
+	}
+	if held[gid]--; held[gid] == 0 {
+		delete(held, gid)
+	}
+	if len(held) == 0 {
+		delete(lockOwners.readers, t)
+	}
+	return nil
+}
+
+// lockHolders returns the goroutines holding t as lock ownership tracking
+// or, when it is off, the deadlock detector records them; none when both
+// are off
+func lockHolders(t *RiftToken) (writer uint64, readers []uint64) {
+	var held map[uint64]int
+	switch {
+	case lockOwners.on.Load():
+		lockOwners.lock.Lock()
+		defer lockOwners.lock.Unlock()
+		writer, held = lockOwners.writers[t], lockOwners.readers[t]
+	case DeadlockDetection() != DeadlockOff:
+		deadlocks.lock.Lock()
+		defer deadlocks.lock.Unlock()
+		writer, held = deadlocks.writers[t], deadlocks.readers[t]
+	}
+	for g := range held {
+		readers = append(readers, g)
+	}
+	sort.Slice(readers, func(i, j int) bool { return readers[i] < readers[j] })
+	return writer, readers
+}
@@ -0,0 +1,113 @@
+package rift
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+func newLockToken() *RiftToken {
+	return NewRiftToken(TokenGoInt, NewRiftMemorySpan(SpanFixed, 64))
+}
+
+func TestTryLock(t *testing.T) {
+	tok := newLockToken()
+	if !tok.TryLock() {
+		t.Fatal("TryLock of a free token failed")
+	}
+	if tok.TryLock() {
+		t.Fatal("TryLock of a locked token succeeded")
+	}
+	tok.Unlock()
+	tok.RLock()
+	if tok.TryLock() {
+		t.Fatal("TryLock of a read-locked token succeeded")
+	}
+	tok.RUnlock()
+}
+
+func TestLockTimeout(t *testing.T) {
+	tok := newLockToken()
+	tok.Lock()
+	err := tok.LockTimeout(10 * time.Millisecond)
+	if ErrorCodeOf(err) != CodeLockTimeout || !errors.Is(err, ErrLockTimeout) {
+		t.Fatalf("LockTimeout on a held lock = %v, want %v", err, ErrLockTimeout)
+	}
+	tok.Unlock()
+	if err := tok.LockTimeout(time.Second); err != nil {
+		t.Fatalf("LockTimeout on a free lock = %v", err)
+	}
+	tok.Unlock()
+}
+
+func TestLockContext(t *testing.T) {
+	tok := newLockToken()
+	tok.RLock()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(10*time.Millisecond, cancel)
+	err := tok.LockContext(ctx)
+	if ErrorCodeOf(err) != CodeCanceled || !errors.Is(err, context.Canceled) {
+		t.Fatalf("LockContext canceled = %v, want %v", err, CodeCanceled)
+	}
+	if err := tok.LockContext(ctx); ErrorCodeOf(err) != CodeCanceled {
+		t.Fatalf("LockContext with a done context = %v, want %v", err, CodeCanceled)
+	}
+
+	ctx, cancel = context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := tok.LockContext(ctx); ErrorCodeOf(err) != CodeLockTimeout {
+		t.Fatalf("LockContext past deadline = %v, want %v", err, CodeLockTimeout)
+	}
+
+	// The abandoned writers no longer hold off readers
+	acquired := make(chan bool)
+	go func() {
+		acquired <- tok.RLock()
+		tok.RUnlock()
+	}()
+	select {
+	case <-acquired:
+	case <-time.After(time.Second):
+		t.Fatal("RLock blocked behind an abandoned writer")
+	}
+	tok.RUnlock()
+
+	if err := tok.LockContext(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	tok.Unlock()
+}
+
+// TestRUnlockRace has two goroutines release the only read lock at once:
+// exactly one succeeds and the other gets CodeNotLockHolder, not a panic
+func TestRUnlockRace(t *testing.T) {
+	for range 200 {
+		tok := newLockToken()
+		tok.RLock()
+		var wg sync.WaitGroup
+		errs := make([]error, 2)
+		for i := range errs {
+			wg.Go(func() { errs[i] = tok.RUnlockErr() })
+		}
+		wg.Wait()
+
+		failed := 0
+		for _, err := range errs {
+			if err != nil {
+				if ErrorCodeOf(err) != CodeNotLockHolder {
+					t.Fatalf("RUnlockErr = %v, want %v", err, CodeNotLockHolder)
+				}
+				failed++
+			}
+		}
+		if failed != 1 {
+			t.Fatalf("%d of 2 racing RUnlocks failed, want 1", failed)
+		}
+		if w, r := tok.lock.state(); w || r != 0 {
+			t.Fatalf("lock state writer %v, readers %d after release", w, r)
+		}
+	}
+}
@@ -0,0 +1,340 @@
+// go/target/mutex.go
+// Governed Mutex and RWMutex - Go Implementation
+
+package rift
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// ============================================================================
+// Governance State
+// ============================================================================
+
+// MutexStats is a snapshot of a governed mutex's acquisition and hold-time
+// counters. Hold times cover write locks and read locks released by the
+// goroutine that acquired them.
+type MutexStats struct {
+	Acquisitions    uint64
+	Contended       uint64 // acquisitions that had to wait
+	TotalHold       time.Duration
+	MaxHold         time.Duration // longest single hold
+	HoldOverruns    uint64        // holds longer than the max hold duration
+	OrderViolations uint64        // acquisitions inverting an earlier lock order
+}
+
+// mutexGovernance is the state shared by Mutex and RWMutex
+type mutexGovernance struct {
+	id           atomic.Uint64 // assigned on first acquisition
+	owner        atomic.Uint64 // write-lock holder goroutine
+	since        atomic.Int64  // write-lock acquisition time, unix nanos
+	acquisitions atomic.Uint64
+	contended    atomic.Uint64
+	holdNanos    atomic.Int64
+	maxHoldNanos atomic.Int64
+	overruns     atomic.Uint64
+	violations   atomic.Uint64
+}
+
+// nextMutexID numbers governed mutexes for lock-order tracking
+var nextMutexID atomic.Uint64
+
+// heldMutex is one lock held by a goroutine
+type heldMutex struct {
+	g     *mutexGovernance
+	name  string
+	read  bool
+	since time.Time
+}
+
+// mutexOrder tracks the locks each goroutine holds and the acquisition
+// orders observed between mutexes
+var mutexOrder struct {
+	lock   sync.Mutex
+	held   map[uint64][]heldMutex // goroutine -> held locks in acquisition order
+	before map[[2]uint64]bool     // {a, b}: b was acquired while holding a
+}
+
+// maxLockHold is the default max hold duration in nanoseconds; 0 = none
+var maxLockHold atomic.Int64
+
+// SetMaxLockHold sets the hold duration past which governed mutexes emit an
+// AuditLockHold event on release, for mutexes without their own MaxHold
+// (see Policy.MaxLockHold). Zero disables the check.
+func SetMaxLockHold(d time.Duration) {
+	maxLockHold.Store(int64(d))
+}
+
+// MaxLockHold returns the default max hold duration
+func MaxLockHold() time.Duration {
+	return time.Duration(maxLockHold.Load())
+}
+
+// mutexID returns g's id, assigning one on first use
+func (g *mutexGovernance) mutexID() uint64 {
+	if id := g.id.Load(); id != 0 {
+		return id
+	}
+	g.id.CompareAndSwap(0, nextMutexID.Add(1))
+	return g.id.Load()
+}
+
+// label names the mutex in audit details
+func (g *mutexGovernance) label(name string) string {
+	if name != "" {
+		return name
+	}
+	return fmt.Sprintf("mutex#%d", g.mutexID())
+}
+
+// acquired records an acquisition by the calling goroutine, checking it
+// against the lock order of the locks that goroutine already holds
+func (g *mutexGovernance) acquired(name string, read, waited bool) {
+	now := clockNow()
+	gid := goroutineID()
+	id := g.mutexID()
+
+	g.acquisitions.Add(1)
+	if waited {
+		g.contended.Add(1)
+	}
+	if !read {
+		g.owner.Store(gid)
+		g.since.Store(now.UnixNano())
+	}
+
+	var inversions []string
+	mutexOrder.lock.Lock()
+	if mutexOrder.held == nil {
+		mutexOrder.held = make(map[uint64][]heldMutex)
+		mutexOrder.before = make(map[[2]uint64]bool)
+	}
+	for _, h := range mutexOrder.held[gid] {
+		hid := h.g.mutexID()
+		if hid == id {
+			continue
+		}
+		if mutexOrder.before[[2]uint64{id, hid}] {
+			inversions = append(inversions, h.g.label(h.name))
+		}
+		mutexOrder.before[[2]uint64{hid, id}] = true
+	}
+	mutexOrder.held[gid] = append(mutexOrder.held[gid], heldMutex{g: g, name: name, read: read, since: now})
+	mutexOrder.lock.Unlock()
+
+	for _, other := range inversions {
+		g.violations.Add(1)
+		auditEmit(AuditLockOrder, nil, fmt.Sprintf("%s acquired while holding %s, inverting an earlier %s -> %s order",
+			g.label(name), other, g.label(name), other))
+	}
+}
+
+// released records a release and its hold time. Read holds are matched to
+// the calling goroutine's; Go locks may be released by another goroutine,
+// in which case a read hold's duration is unknown and not counted.
+func (g *mutexGovernance) released(name string, read bool, maxHold time.Duration) {
+	now := clockNow()
+	gid := goroutineID()
+
+	mutexOrder.lock.Lock()
+	since, found := dropHeld(gid, g, read)
+	if !found && !read {
+		// unlocked by a goroutine other than the owner
+		since, found = dropHeld(g.owner.Load(), g, read)
+	}
+	mutexOrder.lock.Unlock()
+
+	if !read {
+		since = time.Unix(0, g.since.Load())
+		found = true
+		g.owner.Store(0)
+	}
+	if !found {
+		return
+	}
+
+	held := now.Sub(since)
+	g.holdNanos.Add(int64(held))
+	for {
+		max := g.maxHoldNanos.Load()
+		if int64(held) <= max || g.maxHoldNanos.CompareAndSwap(max, int64(held)) {
+			break
+		}
+	}
+	if maxHold <= 0 {
+		maxHold = MaxLockHold()
+	}
+	if maxHold > 0 && held > maxHold {
+		g.overruns.Add(1)
+		auditEmit(AuditLockHold, nil, fmt.Sprintf("%s held %v, max %v", g.label(name), held, maxHold))
+	}
+}
+
+// dropHeld removes the most recent hold of g by goroutine gid and returns
+// its acquisition time; mutexOrder.lock held
+func dropHeld(gid uint64, g *mutexGovernance, read bool) (time.Time, bool) {
+	held := mutexOrder.held[gid]
+	for i := len(held) - 1; i >= 0; i-- {
+		if held[i].g == g && held[i].read == read {
+			since := held[i].since
+			held = append(held[:i], held[i+1:]...)
+			if len(held) == 0 {
+				delete(mutexOrder.held, gid)
+			} else {
+				mutexOrder.held[gid] = held
+			}
+			return since, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// stats snapshots the counters
+func (g *mutexGovernance) stats() MutexStats {
+	return MutexStats{
+		Acquisitions:    g.acquisitions.Load(),
+		Contended:       g.contended.Load(),
+		TotalHold:       time.Duration(g.holdNanos.Load()),
+		MaxHold:         time.Duration(g.maxHoldNanos.Load()),
+		HoldOverruns:    g.overruns.Load(),
+		OrderViolations: g.violations.Load(),
+	}
+}
+
+// ============================================================================
+// Mutex
+// ============================================================================
+
+// Mutex is a sync.Mutex under token governance. It records the owner
+// goroutine, acquisition counts and hold times, emits AuditLockHold when a
+// hold exceeds MaxHold (or the SetMaxLockHold default), and AuditLockOrder
+// when two governed mutexes are acquired in opposite orders. The zero
+// value is an unlocked mutex. A Mutex must not be copied after first use.
+type Mutex struct {
+	Name    string        // label for audit events; default "mutex#<id>"
+	MaxHold time.Duration // 0 = the SetMaxLockHold default
+
+	mu  sync.Mutex
+	gov mutexGovernance
+}
+
+// Lock acquires the mutex
+func (m *Mutex) Lock() {
+	waited := !m.mu.TryLock()
+	if waited {
+		m.mu.Lock()
+	}
+	m.gov.acquired(m.Name, false, waited)
+}
+
+// TryLock acquires the mutex only if it is free
+func (m *Mutex) TryLock() bool {
+	if !m.mu.TryLock() {
+		return false
+	}
+	m.gov.acquired(m.Name, false, false)
+	return true
+}
+
+// Unlock releases the mutex
+func (m *Mutex) Unlock() {
+	m.gov.released(m.Name, false, m.MaxHold)
+	m.mu.Unlock()
+}
+
+// Owner returns the goroutine holding the mutex, or 0
+func (m *Mutex) Owner() uint64 {
+	return m.gov.owner.Load()
+}
+
+// Stats returns the mutex's acquisition and hold-time counters
+func (m *Mutex) Stats() MutexStats {
+	return m.gov.stats()
+}
+
+// ============================================================================
+// RWMutex
+// ============================================================================
+
+// RWMutex is a sync.RWMutex under the same governance as Mutex. Owner
+// reports the write-lock holder; read locks take part in lock-order
+// tracking and hold-time checks.
+type RWMutex struct {
+	Name    string
+	MaxHold time.Duration
+
+	mu  sync.RWMutex
+	gov mutexGovernance
+}
+
+// Lock acquires the write lock
+func (m *RWMutex) Lock() {
+	waited := !m.mu.TryLock()
+	if waited {
+		m.mu.Lock()
+	}
+	m.gov.acquired(m.Name, false, waited)
+}
+
+// TryLock acquires the write lock only if it is free
+func (m *RWMutex) TryLock() bool {
+	if !m.mu.TryLock() {
+		return false
+	}
+	m.gov.acquired(m.Name, false, false)
+	return true
+}
+
+// Unlock releases the write lock
+func (m *RWMutex) Unlock() {
+	m.gov.released(m.Name, false, m.MaxHold)
+	m.mu.Unlock()
+}
+
+// RLock acquires a read lock
+func (m *RWMutex) RLock() {
+	waited := !m.mu.TryRLock()
+	if waited {
+		m.mu.RLock()
+	}
+	m.gov.acquired(m.Name, true, waited)
+}
+
+// TryRLock acquires a read lock only if no writer holds or awaits the lock
+func (m *RWMutex) TryRLock() bool {
+	if !m.mu.TryRLock() {
+		return false
+	}
+	m.gov.acquired(m.Name, true, false)
+	return true
+}
+
+// RUnlock releases a read lock
+func (m *RWMutex) RUnlock() {
+	m.gov.released(m.Name, true, m.MaxHold)
+	m.mu.RUnlock()
+}
+
+// RLocker returns a sync.Locker whose Lock and Unlock call RLock and RUnlock
+func (m *RWMutex) RLocker() sync.Locker {
+	return (*rlocker)(m)
+}
+
+// rlocker adapts an RWMutex's read lock to sync.Locker
+type rlocker RWMutex
+
+func (r *rlocker) Lock()   { (*RWMutex)(r).RLock() }
+func (r *rlocker) Unlock() { (*RWMutex)(r).RUnlock() }
+
+// Owner returns the goroutine holding the write lock, or 0
+func (m *RWMutex) Owner() uint64 {
+	return m.gov.owner.Load()
+}
+
+// Stats returns the mutex's acquisition and hold-time counters
+func (m *RWMutex) Stats() MutexStats {
+	return m.gov.stats()
+}
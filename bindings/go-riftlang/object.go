@@ -0,0 +1,231 @@
+// go/target/object.go
+// Struct Field Governance - Go Implementation
+
+package rift
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// objectField is a governed field of the struct embedding a RiftObject
+type objectField struct {
+	name  string
+	index []int
+	token *RiftToken
+}
+
+// ============================================================================
+// Field Tokens
+// ============================================================================
+
+// Govern puts the fields of outer, a pointer to the struct embedding o,
+// under governance. Every exported field except o itself and func fields
+// gets a token holding the field's current value, on a span sized and
+// aligned like the field. A `rift` struct tag lists the field's access
+// rights like a policy's memory access list, e.g. `rift:"read"` for a
+// read-only field or `rift:"read,update"`; untagged fields allow CRUD and
+// `rift:"-"` leaves a field ungoverned. Calling Govern again regenerates
+// the tokens from the current field values.
+//
+//	type Account struct {
+//		rift.RiftObject
+//		Owner   string `rift:"read"`
+//		Balance int64
+//	}
+//	acct := &Account{Owner: "ada"}
+//	err := acct.Govern(acct)
+func (o *RiftObject) Govern(outer interface{}) error {
+	target := reflect.ValueOf(outer)
+	if target.Kind() != reflect.Pointer || target.IsNil() || target.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("govern: %T is not a pointer to a struct", outer)
+	}
+	target = target.Elem()
+	typ := target.Type()
+
+	embedded := false
+	var fields []objectField
+	for i := 0; i < typ.NumField(); i++ {
+		sf := typ.Field(i)
+		if sf.Anonymous && sf.Type == reflect.TypeOf(RiftObject{}) && target.Field(i).Addr().Interface() == o {
+			embedded = true
+			continue
+		}
+		tag := sf.Tag.Get("rift")
+		if !sf.IsExported() || tag == "-" || sf.Type.Kind() == reflect.Func {
+			continue
+		}
+		mask := AccessCRUD
+		if tag != "" {
+			var err error
+			if mask, err = parseAccessList(strings.Split(tag, ",")); err != nil {
+				return fmt.Errorf("govern: field %s: %w", sf.Name, err)
+			}
+		}
+
+		memory := NewRiftMemorySpan(SpanFixed, uint64(sf.Type.Size()))
+		memory.Alignment = uint32(sf.Type.Align())
+		token := NewRiftToken(tokenTypeOf(sf.Type), memory)
+		token.SetValue(fieldValue(target.Field(i)))
+		memory.Revoke(AccessCRUD &^ mask)
+		token.Validate()
+		fields = append(fields, objectField{name: sf.Name, index: sf.Index, token: token})
+	}
+	if !embedded {
+		return fmt.Errorf("govern: %T does not embed this RiftObject", outer)
+	}
+
+	if o.token == nil {
+		o.memory = NewRiftMemorySpan(SpanFixed, 4096)
+		o.token = NewRiftToken(TokenGoSlice, o.memory)
+		o.token.Validate()
+	}
+	if err := o.memory.Resize(uint64(typ.Size())); err != nil {
+		return err
+	}
+	o.target = target
+	o.fields = fields
+	return nil
+}
+
+// Fields returns the names of the governed fields in declaration order
+func (o *RiftObject) Fields() []string {
+	names := make([]string, len(o.fields))
+	for i, f := range o.fields {
+		names[i] = f.name
+	}
+	return names
+}
+
+// Field returns the token of a governed field, or nil
+func (o *RiftObject) Field(name string) *RiftToken {
+	if f := o.field(name); f != nil {
+		return f.token
+	}
+	return nil
+}
+
+// field looks up a governed field
+func (o *RiftObject) field(name string) *objectField {
+	for i := range o.fields {
+		if o.fields[i].name == name {
+			return &o.fields[i]
+		}
+	}
+	return nil
+}
+
+// ============================================================================
+// Field Access
+// ============================================================================
+
+// Get returns a governed field's value. Both the object's and the field's
+// access masks must grant AccessRead.
+func (o *RiftObject) Get(name string) (interface{}, error) {
+	f, err := o.access("get", name, AccessRead)
+	if err != nil {
+		return nil, err
+	}
+	return o.target.FieldByIndex(f.index).Interface(), nil
+}
+
+// Set writes a governed field through its token, so the write is checked
+// against the object's and the field's access masks, audited and seen by
+// the token's hooks. value must be assignable to the field, or a number
+// converting to a numeric field; nil sets the zero value.
+func (o *RiftObject) Set(name string, value interface{}) error {
+	f, err := o.access("set", name, AccessUpdate)
+	if err != nil {
+		return err
+	}
+	dst := o.target.FieldByIndex(f.index)
+	v := reflect.ValueOf(value)
+	switch {
+	case !v.IsValid():
+		v = reflect.Zero(dst.Type())
+	case v.Type().AssignableTo(dst.Type()):
+	case isNumeric(v.Kind()) && isNumeric(dst.Kind()):
+		v = v.Convert(dst.Type())
+	default:
+		return govErr(CodeConversion, "set", "field %s is %s, not %T", name, dst.Type(), value)
+	}
+
+	if err := f.token.SetValue(fieldValue(v)); err != nil {
+		return err
+	}
+	dst.Set(v)
+	return nil
+}
+
+// access looks up a governed field and checks the object's and the field's
+// access masks grant bits
+func (o *RiftObject) access(op, name string, bits uint32) (*objectField, error) {
+	f := o.field(name)
+	if f == nil {
+		return nil, govErr(CodeNotGoverned, op, "no governed field %q", name)
+	}
+	if err := o.token.checkAccess(op, bits); err != nil {
+		return nil, err
+	}
+	if err := f.token.checkAccess(op, bits); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+// ============================================================================
+// Validation
+// ============================================================================
+
+// ValidateAll validates the object's token and every field token, and
+// checks each field still holds its token's value: a field written
+// directly rather than through Set fails with CodeTampered. It returns
+// every failure joined, or nil.
+func (o *RiftObject) ValidateAll() error {
+	if o.token == nil {
+		return govErr(CodeNotGoverned, "validate", "object not governed")
+	}
+	var errs []error
+	if err := o.token.ValidateErr(); err != nil {
+		errs = append(errs, err)
+	}
+	for _, f := range o.fields {
+		if err := f.token.ValidateErr(); err != nil {
+			errs = append(errs, fmt.Errorf("field %s: %w", f.name, err))
+			continue
+		}
+		if !reflect.DeepEqual(fieldValue(o.target.FieldByIndex(f.index)), f.token.Value) {
+			err := f.token.located(govErr(CodeTampered, "validate", "field %s changed outside Set", f.name))
+			auditEmit(AuditValidateFail, f.token, err.Error())
+			errs = append(errs, f.token.violation(err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// fieldValue converts a field value to a token value
+func fieldValue(v reflect.Value) RiftTokenValue {
+	var val RiftTokenValue
+	switch {
+	case v.CanInt():
+		val.IntVal = v.Int()
+	case v.CanUint():
+		val.IntVal = int64(v.Uint())
+	case v.CanFloat():
+		val.FloatVal = v.Float()
+	case v.Kind() == reflect.String:
+		val.StringVal = v.String()
+	case v.Type() == reflect.TypeOf([]*RiftToken(nil)):
+		val.ArrVal = v.Interface().([]*RiftToken)
+	default:
+		val.PtrVal = v.Interface()
+	}
+	return val
+}
+
+// isNumeric reports whether k is an integer or float kind
+func isNumeric(k reflect.Kind) bool {
+	return k >= reflect.Int && k <= reflect.Float64
+}
@@ -0,0 +1,178 @@
+// go/target/pattern_adaptive.go
+// Adaptive Pair Evaluation Order - Go Implementation
+
+package rift
+
+import (
+	"context"
+	"slices"
+	"sort"
+	"sync"
+	"sync/atomic"
+)
+
+const (
+	// adaptiveWindow is the number of wins between reorders; each reorder
+	// halves every pair's win score, so older wins weigh less
+	adaptiveWindow = 256
+
+	// adaptiveMaxPromoted bounds the pairs tried ahead of rank order
+	adaptiveMaxPromoted = 4
+
+	// adaptiveMinShare is the share of a window's wins (1/adaptiveMinShare)
+	// a pair needs to be promoted
+	adaptiveMinShare = 16
+)
+
+// ============================================================================
+// Configuration
+// ============================================================================
+
+// SetAdaptiveOrder turns adaptive evaluation order on or off. While it is
+// on, the engine counts the wins of each pair under SelectPriority, with
+// older wins decaying, and tries the few pairs that win most often before
+// the others. A promoted pair that matches is still checked against the
+// pairs ranked ahead of it, so the result is always the one rank order
+// gives: priorities and tie-breaks keep their meaning, and only the order
+// in which pairs are tried changes. Those checks only ask whether a pair
+// matches, which is cheaper than locating the match and its captures as
+// a rank-order scan does, so skewed workloads where a few low-ranked pairs
+// win most inputs match faster; with no pairs ranked ahead of the winner
+// there is nothing to gain. Adding, removing or re-ranking pairs drops
+// the promotions until the next reorder, and parallel scans (see
+// SetWorkers) keep rank order.
+func (e *PatternEngine) SetAdaptiveOrder(on bool) {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	switch {
+	case on && e.adaptive == nil:
+		e.adaptive = &adaptiveOrder{}
+	case !on:
+		e.adaptive = nil
+	}
+}
+
+// AdaptiveOrder reports whether adaptive evaluation order is on
+func (e *PatternEngine) AdaptiveOrder() bool {
+	e.lock.RLock()
+	defer e.lock.RUnlock()
+	return e.adaptive != nil
+}
+
+// PromotedPairs returns the TransformIDs of the pairs adaptive order tries
+// first, in the order it tries them; none when adaptive order is off or
+// no pair wins often enough
+func (e *PatternEngine) PromotedPairs() []uint32 {
+	e.lock.RLock()
+	defer e.lock.RUnlock()
+	if e.adaptive == nil {
+		return nil
+	}
+	hot := e.adaptive.hot.Load()
+	if hot == nil || hot.index != e.index || hot.gen != e.index.gen {
+		return nil
+	}
+	ids := make([]uint32, len(hot.pairs))
+	for i, ip := range hot.pairs {
+		ids[i] = ip.pair.TransformID
+	}
+	return ids
+}
+
+// ============================================================================
+// adaptiveOrder
+// ============================================================================
+
+// adaptiveOrder holds the promoted pairs of an engine
+type adaptiveOrder struct {
+	hot     atomic.Pointer[promotedPairs]
+	pending atomic.Uint64 // wins since the last reorder
+	lock    sync.Mutex    // serializes reorders
+}
+
+// promotedPairs are the pairs tried first, most wins first, as of one
+// generation of one index; pair-set and rank changes make them stale
+type promotedPairs struct {
+	index *pairIndex
+	gen   uint64
+	pairs []*indexedPair
+}
+
+// won counts a win for ip, reordering after every adaptiveWindow wins;
+// engine lock held
+func (a *adaptiveOrder) won(x *pairIndex, ip *indexedPair) {
+	ip.wins.Add(1)
+	if a.pending.Add(1)%adaptiveWindow != 0 {
+		return
+	}
+	if !a.lock.TryLock() {
+		return // another match is reordering
+	}
+	defer a.lock.Unlock()
+
+	type scored struct {
+		ip   *indexedPair
+		wins uint64
+	}
+	var top []scored
+	for _, p := range x.all() {
+		w := p.wins.Load()
+		if w*adaptiveMinShare >= adaptiveWindow {
+			top = append(top, scored{p, w})
+		}
+		p.wins.Add(^(w/2 - 1)) // subtract w/2, keeping concurrent wins
+	}
+	sort.SliceStable(top, func(i, j int) bool { return top[i].wins > top[j].wins })
+
+	hot := &promotedPairs{index: x, gen: x.gen}
+	for _, s := range top[:min(len(top), adaptiveMaxPromoted)] {
+		hot.pairs = append(hot.pairs, s.ip)
+	}
+	a.hot.Store(hot)
+}
+
+// scan returns what scanCandidates would, trying the promoted pairs first.
+// A promoted pair that matches wins unless a candidate ranked ahead of it
+// matches too, which is tested without locating the match; candidates
+// ranked behind it are not tried at all.
+func (a *adaptiveOrder) scan(done <-chan struct{}, x *pairIndex, candidates []*indexedPair, input string) (int, []int, error) {
+	hot := a.hot.Load()
+	if hot == nil || hot.index != x || hot.gen != x.gen {
+		return scanCandidates(done, candidates, input)
+	}
+
+	var failed []int // candidate positions of promoted pairs that did not match
+	for _, h := range hot.pairs {
+		pos := x.position(candidates, h)
+		if pos < 0 {
+			continue
+		}
+		loc := h.match(input)
+		if loc == nil {
+			failed = append(failed, pos)
+			continue
+		}
+		for i, ip := range candidates[:pos] {
+			if done != nil && i%matchCheckInterval == 0 {
+				select {
+				case <-done:
+					return -1, nil, context.Canceled
+				default:
+				}
+			}
+			if slices.Contains(failed, i) || !ip.matches(input) {
+				continue
+			}
+			return i, ip.match(input), nil
+		}
+		return pos, loc, nil
+	}
+	return scanCandidates(done, candidates, input)
+}
+
+// matches reports whether the pair matches input, without locating the
+// match
+func (ip *indexedPair) matches(input string) bool {
+	re := ip.pair.Left.CompiledRegex
+	return re != nil && ip.mayMatch(input) && re.MatchString(input)
+}
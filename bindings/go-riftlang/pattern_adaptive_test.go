@@ -0,0 +1,90 @@
+package rift
+
+import (
+	"fmt"
+	"testing"
+)
+
+// floatingEngine returns an engine of n floating pairs `w<i>$` of equal
+// priority, so every pair is a candidate for every input
+func floatingEngine(tb testing.TB, n int, adaptive bool) *PatternEngine {
+	tb.Helper()
+	e := NewPatternEngine("classical")
+	for i := range n {
+		if err := e.AddPairErr(fmt.Sprintf(`w%d$`, i), fmt.Sprintf("out%d", i), 1, true); err != nil {
+			tb.Fatal(err)
+		}
+	}
+	e.SetAdaptiveOrder(adaptive)
+	return e
+}
+
+// TestAdaptiveOrderKeepsRank warms an adaptive engine on a skewed workload
+// until it promotes pairs, then checks every input matches as rank order
+// does
+func TestAdaptiveOrderKeepsRank(t *testing.T) {
+	const n = 300
+	adaptive := floatingEngine(t, n, true)
+	ranked := floatingEngine(t, n, false)
+	for range 2 * adaptiveWindow {
+		adaptive.Match("x w3")
+	}
+	if ids := adaptive.PromotedPairs(); len(ids) == 0 {
+		t.Fatal("no pairs promoted")
+	}
+
+	for _, input := range []string{"x w3", "w3 w7", "x w150", "x w299", "x w3 w0", "nothing"} {
+		got, want := adaptive.Match(input), ranked.Match(input)
+		if got.Matched != want.Matched || got.Output != want.Output {
+			t.Errorf("Match(%q) = %q (matched %v), rank order %q (matched %v)",
+				input, got.Output, got.Matched, want.Output, want.Matched)
+		}
+	}
+}
+
+// TestPairIndexPosition checks position finds every candidate where it is
+// under each tie-break mode, and reports pairs that are not candidates
+func TestPairIndexPosition(t *testing.T) {
+	for _, tb := range []TieBreak{TieLastRegistered, TieFirstRegistered, TieLongestMatch, TieMostSpecific} {
+		e := statementEngine(t, 200)
+		e.SetTieBreak(tb)
+		all := e.index.all()
+		for _, input := range statementInputs(200) {
+			candidates := e.index.candidates(input)
+			in := make(map[*indexedPair]bool)
+			for i, ip := range candidates {
+				in[ip] = true
+				if pos := e.index.position(candidates, ip); pos != i {
+					t.Fatalf("%v, input %q: position of candidate %d = %d", tb, input, i, pos)
+				}
+			}
+			for _, ip := range all {
+				if !in[ip] && e.index.position(candidates, ip) != -1 {
+					t.Fatalf("%v, input %q: non-candidate %d found", tb, input, ip.pair.TransformID)
+				}
+			}
+		}
+	}
+}
+
+// BenchmarkAdaptiveScan matches with the last-ranked of n candidate pairs
+// promoted, the workload adaptive order is for
+func BenchmarkAdaptiveScan(b *testing.B) {
+	for _, n := range []int{100, 1000, 10000} {
+		for _, adaptive := range []bool{false, true} {
+			e := floatingEngine(b, n, adaptive)
+			input := "x w0" // registered first, so ranked last
+			for range 2 * adaptiveWindow {
+				e.Match(input)
+			}
+			if adaptive && len(e.PromotedPairs()) == 0 {
+				b.Fatal("no pairs promoted")
+			}
+			b.Run(fmt.Sprintf("adaptive=%v/pairs=%d", adaptive, n), func(b *testing.B) {
+				for b.Loop() {
+					e.Match(input)
+				}
+			})
+		}
+	}
+}
@@ -0,0 +1,465 @@
+// go/target/pattern_analyze.go
+// Pattern Conflict Analysis - Go Implementation
+
+package rift
+
+import (
+	"encoding/binary"
+	"fmt"
+	"regexp/syntax"
+	"sort"
+	"unicode"
+)
+
+// ============================================================================
+// Conflicts
+// ============================================================================
+
+// ConflictKind classifies a finding of AnalyzePairs
+type ConflictKind int
+
+const (
+	// ConflictUnmatchable marks a pair whose left pattern matches no input
+	// at all, such as `a^b`
+	ConflictUnmatchable ConflictKind = iota
+
+	// ConflictShadowed marks a pair that can never be Match's result:
+	// every input it matches is also matched by a pair ranked ahead of it
+	ConflictShadowed
+
+	// ConflictSubsumed marks a pair whose left pattern matches in full
+	// only texts the other pair's also matches in full, such as `foo`
+	// and `\w+`
+	ConflictSubsumed
+
+	// ConflictOverlap marks pairs of equal priority whose left patterns
+	// both match some text in full, so the tie-break mode decides between
+	// them
+	ConflictOverlap
+)
+
+// String returns the kind name
+func (k ConflictKind) String() string {
+	switch k {
+	case ConflictUnmatchable:
+		return "unmatchable"
+	case ConflictShadowed:
+		return "shadowed"
+	case ConflictSubsumed:
+		return "subsumed"
+	case ConflictOverlap:
+		return "overlap"
+	}
+	return fmt.Sprintf("ConflictKind(%d)", int(k))
+}
+
+// Conflict is a finding of AnalyzePairs about one pair
+type Conflict struct {
+	Kind    ConflictKind
+	Pair    uint32   // TransformID of the pair the finding is about
+	Others  []uint32 // TransformIDs of the pairs it conflicts with
+	Example string   // for ConflictOverlap, a text both left patterns match in full
+	Detail  string
+}
+
+// analysisStates bounds the automaton states one check explores; a check
+// that needs more is inconclusive and reports nothing
+const analysisStates = 4096
+
+// ============================================================================
+// AnalyzePairs
+// ============================================================================
+
+// AnalyzePairs statically checks the enabled pairs' left patterns against
+// each other, without matching any input, and returns its findings in rank
+// order: unmatchable pairs, pairs shadowed by pairs ranked ahead of them,
+// pairs subsumed by another pair, and equal-priority pairs that overlap.
+// Shadowing follows the engine's ranking and is only checked under
+// SelectPriority; under TieLongestMatch only a strictly higher priority
+// shadows. Checks are exact for Go regexp syntax, but one too large to
+// decide within a bounded number of automaton states is skipped, so no
+// finding is not a proof.
+func (e *PatternEngine) AnalyzePairs() []Conflict {
+	e.lock.RLock()
+	all := e.index.all()
+	tie, selection := e.index.tie, e.selection
+	e.lock.RUnlock()
+
+	type analyzed struct {
+		pair         *BipartitePair
+		full, search *syntax.Prog
+		dead         bool
+	}
+	pairs := make([]*analyzed, 0, len(all))
+	rank := make(map[uint32]int, len(all))
+	var out []Conflict
+	for _, ip := range all {
+		a := &analyzed{pair: ip.pair}
+		var err error
+		if a.full, err = compileLanguage(ip.pair.Left.PatternStr, false); err != nil {
+			continue
+		}
+		if a.search, err = compileLanguage(ip.pair.Left.PatternStr, true); err != nil {
+			continue
+		}
+		rank[ip.pair.TransformID] = len(pairs)
+		pairs = append(pairs, a)
+		if _, found, ok := findInput(a.full, nil, acceptA, false); ok && !found {
+			a.dead = true
+			out = append(out, Conflict{Kind: ConflictUnmatchable, Pair: ip.pair.TransformID,
+				Detail: fmt.Sprintf("%s matches no input", ip.pair.Left.PatternStr)})
+		}
+	}
+
+	// Shadowing: the pair's inputs are all matched by pairs ranked ahead
+	if selection == SelectPriority {
+		for k, p := range pairs {
+			if p.dead {
+				continue
+			}
+			var ahead []*analyzed
+			for _, q := range pairs[:k] {
+				if !q.dead && (q.pair.Left.Priority < p.pair.Left.Priority || tie != TieLongestMatch) {
+					ahead = append(ahead, q)
+				}
+			}
+			var by []*analyzed
+			for _, q := range ahead {
+				if included(p.search, q.search) {
+					by = []*analyzed{q}
+					break
+				}
+			}
+			if by == nil && len(ahead) > 1 {
+				progs := make([]*syntax.Prog, len(ahead))
+				for i, q := range ahead {
+					progs[i] = q.search
+				}
+				if included(p.search, progs...) {
+					by = ahead
+				}
+			}
+			if by != nil {
+				c := Conflict{Kind: ConflictShadowed, Pair: p.pair.TransformID,
+					Detail: "every input it matches is matched by a pair ranked ahead"}
+				for _, q := range by {
+					c.Others = append(c.Others, q.pair.TransformID)
+				}
+				out = append(out, c)
+			}
+		}
+	}
+
+	// Subsumption and overlap between each two pairs
+	for i, p := range pairs {
+		for _, q := range pairs[i+1:] {
+			if p.dead || q.dead {
+				continue
+			}
+			pq, qp := included(p.full, q.full), included(q.full, p.full)
+			pid, qid := p.pair.TransformID, q.pair.TransformID
+			switch {
+			case pq && qp:
+				out = append(out, Conflict{Kind: ConflictSubsumed, Pair: qid, Others: []uint32{pid},
+					Detail: fmt.Sprintf("matches exactly the texts pair %d matches", pid)})
+			case pq:
+				out = append(out, Conflict{Kind: ConflictSubsumed, Pair: pid, Others: []uint32{qid},
+					Detail: fmt.Sprintf("every text it matches in full is matched by pair %d", qid)})
+			case qp:
+				out = append(out, Conflict{Kind: ConflictSubsumed, Pair: qid, Others: []uint32{pid},
+					Detail: fmt.Sprintf("every text it matches in full is matched by pair %d", pid)})
+			}
+			if p.pair.Left.Priority != q.pair.Left.Priority {
+				continue
+			}
+			if example, found, _ := findInput(p.full, []*syntax.Prog{q.full}, acceptBoth, true); found {
+				out = append(out, Conflict{Kind: ConflictOverlap, Pair: qid, Others: []uint32{pid}, Example: example,
+					Detail: fmt.Sprintf("pair %d matches the same text at priority %d; %s decides", pid, p.pair.Left.Priority, tie)})
+			}
+		}
+	}
+
+	sort.SliceStable(out, func(i, j int) bool {
+		if ri, rj := rank[out[i].Pair], rank[out[j].Pair]; ri != rj {
+			return ri < rj
+		}
+		return out[i].Kind < out[j].Kind
+	})
+	return out
+}
+
+// included reports whether every input a accepts is accepted by one of bs;
+// an inconclusive check reports false
+func included(a *syntax.Prog, bs ...*syntax.Prog) bool {
+	_, found, ok := findInput(a, bs, acceptOnlyA, false)
+	return ok && !found
+}
+
+// compileLanguage compiles pattern for the automaton checks, which run a
+// program over a whole input. With search the pattern may match anywhere
+// in the input, as Match searches, rather than the whole of it.
+func compileLanguage(pattern string, search bool) (*syntax.Prog, error) {
+	re, err := syntax.Parse(pattern, syntax.Perl)
+	if err != nil {
+		return nil, err
+	}
+	if search {
+		anything := &syntax.Regexp{Op: syntax.OpStar, Sub: []*syntax.Regexp{{Op: syntax.OpAnyChar}}}
+		re = &syntax.Regexp{Op: syntax.OpConcat, Sub: []*syntax.Regexp{anything, re, anything}}
+	}
+	return syntax.Compile(re.Simplify())
+}
+
+// ============================================================================
+// Automaton Search
+// ============================================================================
+
+// Acceptance goals for findInput
+var (
+	acceptA     = func(a, b bool) bool { return a }
+	acceptBoth  = func(a, b bool) bool { return a && b }
+	acceptOnlyA = func(a, b bool) bool { return a && !b }
+)
+
+// automaton runs a program a and programs bs side by side, tracking the
+// subset of instructions each side can be at; a state in bs is its
+// program's index and pc packed into a uint64
+type automaton struct {
+	progs []*syntax.Prog // a, then bs
+}
+
+// findInput searches breadth-first for the shortest input for which goal
+// holds of whether a and any of bs accept it. needB prunes inputs once no
+// b can accept them. ok is false when the search gave up.
+func findInput(a *syntax.Prog, bs []*syntax.Prog, goal func(a, b bool) bool, needB bool) (input string, found, ok bool) {
+	m := &automaton{progs: append([]*syntax.Prog{a}, bs...)}
+	alphabet := m.alphabet()
+
+	type node struct {
+		a, b   []uint64
+		prev   rune
+		parent int
+		r      rune
+	}
+	start := node{a: []uint64{uint64(a.Start)}, prev: -1, parent: -1}
+	for i, p := range bs {
+		start.b = append(start.b, uint64(i+1)<<32|uint64(p.Start))
+	}
+	nodes := []node{start}
+	seen := map[string]bool{stateKey(start.a, start.b, -1): true}
+	for n := 0; n < len(nodes); n++ {
+		cur := nodes[n]
+		end := syntax.EmptyOpContext(cur.prev, -1)
+		if goal(m.accepts(m.closure(cur.a, end)), m.accepts(m.closure(cur.b, end))) {
+			var rs []rune
+			for i := n; nodes[i].parent >= 0; i = nodes[i].parent {
+				rs = append(rs, nodes[i].r)
+			}
+			for i, j := 0, len(rs)-1; i < j; i, j = i+1, j-1 {
+				rs[i], rs[j] = rs[j], rs[i]
+			}
+			return string(rs), true, true
+		}
+		for _, r := range alphabet {
+			ctx := syntax.EmptyOpContext(cur.prev, r)
+			na := m.step(m.closure(cur.a, ctx), r)
+			if len(na) == 0 {
+				continue
+			}
+			nb := m.step(m.closure(cur.b, ctx), r)
+			if needB && len(nb) == 0 {
+				continue
+			}
+			prev := wordClass(r)
+			key := stateKey(na, nb, prev)
+			if seen[key] {
+				continue
+			}
+			if len(nodes) >= analysisStates {
+				return "", false, false
+			}
+			seen[key] = true
+			nodes = append(nodes, node{a: na, b: nb, prev: prev, parent: n, r: r})
+		}
+	}
+	return "", false, true
+}
+
+// inst returns the instruction a packed state refers to
+func (m *automaton) inst(s uint64) *syntax.Inst {
+	return &m.progs[s>>32].Inst[uint32(s)]
+}
+
+// closure adds the states reachable without consuming a rune, with the
+// empty-width assertions that hold in ctx
+func (m *automaton) closure(states []uint64, ctx syntax.EmptyOp) []uint64 {
+	seen := make(map[uint64]bool, len(states))
+	var out []uint64
+	var visit func(s uint64)
+	visit = func(s uint64) {
+		if seen[s] {
+			return
+		}
+		seen[s] = true
+		prog := s &^ 0xFFFFFFFF
+		in := m.inst(s)
+		switch in.Op {
+		case syntax.InstAlt, syntax.InstAltMatch:
+			visit(prog | uint64(in.Out))
+			visit(prog | uint64(in.Arg))
+		case syntax.InstCapture, syntax.InstNop:
+			visit(prog | uint64(in.Out))
+		case syntax.InstEmptyWidth:
+			if syntax.EmptyOp(in.Arg)&^ctx == 0 {
+				visit(prog | uint64(in.Out))
+			}
+		case syntax.InstFail:
+		default:
+			out = append(out, s)
+		}
+	}
+	for _, s := range states {
+		visit(s)
+	}
+	return out
+}
+
+// step returns the states after consuming r, sorted and deduplicated
+func (m *automaton) step(states []uint64, r rune) []uint64 {
+	var out []uint64
+	for _, s := range states {
+		in := m.inst(s)
+		var ok bool
+		switch in.Op {
+		case syntax.InstRune:
+			ok = in.MatchRune(r)
+		case syntax.InstRune1:
+			ok = r == in.Rune[0]
+		case syntax.InstRuneAny:
+			ok = true
+		case syntax.InstRuneAnyNotNL:
+			ok = r != '\n'
+		}
+		if ok {
+			out = append(out, s&^0xFFFFFFFF|uint64(in.Out))
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i] < out[j] })
+	n := 0
+	for i, s := range out {
+		if i == 0 || s != out[n-1] {
+			out[n] = s
+			n++
+		}
+	}
+	return out[:n]
+}
+
+// accepts reports whether a closed state set includes a match
+func (m *automaton) accepts(states []uint64) bool {
+	for _, s := range states {
+		if m.inst(s).Op == syntax.InstMatch {
+			return true
+		}
+	}
+	return false
+}
+
+// alphabet splits the runes into intervals every instruction and
+// assertion treats alike and returns one rune of each, printable where the
+// interval allows
+func (m *automaton) alphabet() []rune {
+	bounds := map[rune]bool{0: true, 0xD800: true, 0xE000: true}
+	add := func(lo, hi rune) {
+		bounds[lo] = true
+		if hi < unicode.MaxRune {
+			bounds[hi+1] = true
+		}
+	}
+	add('\n', '\n')
+	add('0', '9')
+	add('A', 'Z')
+	add('_', '_')
+	add('a', 'z')
+	for _, p := range m.progs {
+		for _, in := range p.Inst {
+			switch in.Op {
+			case syntax.InstRune1:
+				add(in.Rune[0], in.Rune[0])
+			case syntax.InstRune:
+				if len(in.Rune) == 1 {
+					r := in.Rune[0]
+					add(r, r)
+					if syntax.Flags(in.Arg)&syntax.FoldCase != 0 {
+						for f := unicode.SimpleFold(r); f != r; f = unicode.SimpleFold(f) {
+							add(f, f)
+						}
+					}
+					continue
+				}
+				for i := 0; i+1 < len(in.Rune); i += 2 {
+					add(in.Rune[i], in.Rune[i+1])
+				}
+			}
+		}
+	}
+
+	starts := make([]rune, 0, len(bounds))
+	for r := range bounds {
+		starts = append(starts, r)
+	}
+	sort.Slice(starts, func(i, j int) bool { return starts[i] < starts[j] })
+	reps := make([]rune, 0, len(starts))
+	for i, lo := range starts {
+		if lo == 0xD800 {
+			continue // surrogates do not occur in strings
+		}
+		hi := rune(unicode.MaxRune)
+		if i+1 < len(starts) {
+			hi = starts[i+1] - 1
+		}
+		reps = append(reps, printableIn(lo, hi))
+	}
+	return reps
+}
+
+// printableIn returns a rune of [lo, hi], preferring readable ones
+func printableIn(lo, hi rune) rune {
+	for _, r := range []rune{'a', 'A', '0', '_', ' '} {
+		if lo <= r && r <= hi {
+			return r
+		}
+	}
+	if lo < '!' && hi >= '!' {
+		return '!'
+	}
+	return lo
+}
+
+// wordClass reduces r to the rune standing for every rune that
+// EmptyOpContext treats alike as the previous rune
+func wordClass(r rune) rune {
+	switch {
+	case r < 0:
+		return -1
+	case r == '\n':
+		return '\n'
+	case syntax.IsWordChar(r):
+		return 'a'
+	}
+	return ' '
+}
+
+// stateKey encodes an automaton state for the visited set
+func stateKey(a, b []uint64, prev rune) string {
+	buf := make([]byte, 0, 8*(len(a)+len(b))+12)
+	buf = binary.LittleEndian.AppendUint32(buf, uint32(prev))
+	buf = binary.LittleEndian.AppendUint32(buf, uint32(len(a)))
+	for _, s := range a {
+		buf = binary.LittleEndian.AppendUint64(buf, s)
+	}
+	for _, s := range b {
+		buf = binary.LittleEndian.AppendUint64(buf, s)
+	}
+	return string(buf)
+}
@@ -0,0 +1,141 @@
+// go/target/pattern_cache.go
+// Memoized Match Cache - Go Implementation
+
+package rift
+
+import (
+	"container/list"
+	"maps"
+	"slices"
+	"sync"
+)
+
+// ============================================================================
+// Cache Configuration
+// ============================================================================
+
+// SetMatchCache gives Match and MatchContext an LRU cache of the last size
+// results, keyed on input and pair-set version, for workloads matching the
+// same inputs repeatedly. Any pair-set change (see Version), and
+// SetSelection, SetTieBreak or SetMode, invalidates the cache. Cached results count
+// as matches or failures in the metrics like computed ones. size <= 0
+// disables the cache, which is the default.
+func (e *PatternEngine) SetMatchCache(size int) {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	if size <= 0 {
+		e.cache = nil
+		return
+	}
+	e.cache = newMatchCache(size)
+}
+
+// MatchCacheSize returns the match cache capacity, 0 when disabled
+func (e *PatternEngine) MatchCacheSize() int {
+	e.lock.RLock()
+	defer e.lock.RUnlock()
+	if e.cache == nil {
+		return 0
+	}
+	return e.cache.size
+}
+
+// ============================================================================
+// matchCache
+// ============================================================================
+
+// matchCache is an LRU cache of match results for one pair-set version
+type matchCache struct {
+	lock    sync.Mutex
+	size    int
+	version uint64 // pair-set version of the cached results
+	entries map[string]*list.Element
+	order   *list.List // of *matchEntry, most recently used first
+	hits    uint64
+	misses  uint64
+}
+
+// matchEntry is a cached result
+type matchEntry struct {
+	input  string
+	result MatchResult
+}
+
+func newMatchCache(size int) *matchCache {
+	return &matchCache{size: size, entries: make(map[string]*list.Element), order: list.New()}
+}
+
+// get returns the cached result for input at version, counting a hit or
+// miss. A new version drops every cached result.
+func (c *matchCache) get(input string, version uint64) (MatchResult, bool) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if version != c.version {
+		c.reset(version)
+	}
+	el, ok := c.entries[input]
+	if !ok {
+		c.misses++
+		return MatchResult{}, false
+	}
+	c.hits++
+	c.order.MoveToFront(el)
+	return copyResult(el.Value.(*matchEntry).result), true
+}
+
+// put caches result for input at version, evicting the least recently used
+// result when full
+func (c *matchCache) put(input string, version uint64, result MatchResult) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if version != c.version {
+		c.reset(version)
+	}
+	if el, ok := c.entries[input]; ok {
+		el.Value.(*matchEntry).result = copyResult(result)
+		c.order.MoveToFront(el)
+		return
+	}
+	c.entries[input] = c.order.PushFront(&matchEntry{input: input, result: copyResult(result)})
+	if c.order.Len() > c.size {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*matchEntry).input)
+	}
+}
+
+// invalidate drops every cached result
+func (c *matchCache) invalidate() {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.reset(c.version)
+}
+
+// reset empties the cache for version; c.lock held
+func (c *matchCache) reset(version uint64) {
+	c.version = version
+	clear(c.entries)
+	c.order.Init()
+}
+
+// counts returns the hit and miss counts
+func (c *matchCache) counts() (hits, misses uint64) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return c.hits, c.misses
+}
+
+// copyResult copies r so callers cannot change a cached result's groups
+// or states
+func copyResult(r MatchResult) MatchResult {
+	r.Groups = maps.Clone(r.Groups)
+	if r.States != nil {
+		states := make([]MatchResult, len(r.States))
+		for i, s := range r.States {
+			states[i] = copyResult(s)
+		}
+		r.States = states
+	}
+	r.Amplitudes = slices.Clone(r.Amplitudes)
+	return r
+}
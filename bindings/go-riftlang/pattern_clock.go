@@ -0,0 +1,45 @@
+// go/target/pattern_clock.go
+// Pattern Engine Clock - Go Implementation
+
+package rift
+
+import (
+	"time"
+)
+
+// ============================================================================
+// Engine Clock
+// ============================================================================
+
+// SetClock makes c the clock timing the engine's matches, for the latency
+// in its metrics, match observers and the runtime metrics; nil returns the
+// engine to the package clock (see SetClock). With a ManualClock set to
+// auto-advance, every match takes exactly its step.
+func (e *PatternEngine) SetClock(c Clock) {
+	if c == nil {
+		e.clock.Store(nil)
+		return
+	}
+	e.clock.Store(&clockHolder{clock: c})
+}
+
+// Clock returns the clock timing the engine's matches
+func (e *PatternEngine) Clock() Clock {
+	if h := e.clock.Load(); h != nil {
+		return h.clock
+	}
+	return CurrentClock()
+}
+
+// now returns the engine clock's time
+func (e *PatternEngine) now() time.Time {
+	if h := e.clock.Load(); h != nil {
+		return h.clock.Now()
+	}
+	return clockNow()
+}
+
+// since returns the engine clock's time elapsed since t
+func (e *PatternEngine) since(t time.Time) time.Duration {
+	return e.now().Sub(t)
+}
@@ -4,9 +4,12 @@
 package rift
 
 import (
+	"context"
 	"fmt"
 	"regexp"
+	"sort"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
@@ -24,12 +27,12 @@ const (
 
 // RiftPattern represents a compiled pattern with metadata
 type RiftPattern struct {
-	PatternStr     string
-	CompiledRegex  *regexp.Regexp
-	Polarity       PatternPolarity
-	Priority       uint32
-	Anchored       bool
-	IsLiteral      bool
+	PatternStr    string
+	CompiledRegex *regexp.Regexp // shared by patterns with the same PatternStr; do not modify
+	Polarity      PatternPolarity
+	Priority      uint32
+	Anchored      bool
+	IsLiteral     bool
 }
 
 // ============================================================================
@@ -38,11 +41,37 @@ type RiftPattern struct {
 
 // BipartitePair links input pattern (left) to output pattern (right)
 type BipartitePair struct {
-	Left        *RiftPattern
-	Right       *RiftPattern
-	TransformFn func(string) string
-	IsGoverned  bool
-	TransformID uint32
+	Left         *RiftPattern
+	Right        *RiftPattern
+	TransformFn  func(string) string
+	IsGoverned   bool
+	TransformID  uint32
+	Plan         *SubstitutionPlan // nil when Substitution is SubstituteNone
+	Substitution Substitution      // placeholders Plan expands (see SetPairSubstitution)
+	Group        string            // named group, "" for ungrouped (see AddGroupPair)
+
+	// NeedsCaptures is set by AddPair when the output or the result's
+	// Groups use capture groups; otherwise Match only tests the left regex
+	NeedsCaptures bool
+}
+
+// capturesNeeded reports whether building the pair's result reads any
+// submatches: a placeholder in the plan or a named group in the left
+// pattern
+func (pair *BipartitePair) capturesNeeded() bool {
+	if pair.Plan != nil {
+		for _, seg := range pair.Plan.Segments {
+			if seg.Group >= 0 {
+				return true
+			}
+		}
+	}
+	for _, name := range pair.Left.CompiledRegex.SubexpNames() {
+		if name != "" {
+			return true
+		}
+	}
+	return false
 }
 
 // ============================================================================
@@ -55,7 +84,12 @@ type MatchResult struct {
 	Output      string
 	Priority    uint32
 	TransformID uint32
-	Groups      map[string]string
+	Groups      map[string]string // nil when the left pattern has no named groups
+	Start, End  int               // byte range of the match in the input (see Positions for lines and columns)
+	Text        string            // the matched text, input[Start:End]
+	States      []MatchResult     // ModeQuantum: a result per matching pair, nil otherwise (see Collapse)
+	Amplitudes  []float64         // amplitude of each of States
+	Version     uint64            // pair-set version matched against (see PatternEngine.Version)
 }
 
 // ============================================================================
@@ -64,29 +98,86 @@ type MatchResult struct {
 
 // PatternEngine manages all pattern pairs and compilation cache
 type PatternEngine struct {
-	pairs               []*BipartitePair
-	mode                string
-	lock                sync.RWMutex
-	totalMatches        uint64
-	totalFailures       uint64
-	averageMatchTimeMs  float64
+	pairs              []*BipartitePair // in TransformID order
+	nextID             uint32           // last TransformID allocated
+	version            uint64           // bumped by every pair-set change
+	index              *pairIndex
+	workers            int
+	selection          Selection
+	cache              *matchCache     // see SetMatchCache
+	disabled           map[string]bool // groups left out of matching (see DisableGroup)
+	mode               EngineMode
+	adaptive           *adaptiveOrder              // see SetAdaptiveOrder
+	clock              atomic.Pointer[clockHolder] // see SetClock
+	lock               sync.RWMutex
+	metricsLock        sync.Mutex
+	totalMatches       uint64
+	totalFailures      uint64
+	averageMatchTimeMs float64
+	observers          []MatchObserver
+}
+
+// MatchObserver is called after every Match with its latency and outcome
+type MatchObserver func(elapsed time.Duration, matched bool)
+
+// EngineStats is a typed snapshot of engine metrics
+type EngineStats struct {
+	TotalMatches       uint64
+	TotalFailures      uint64
+	AverageMatchTimeMs float64
+	PairCount          int
+	CacheHits          uint64 // see SetMatchCache
+	CacheMisses        uint64
 }
 
-// NewPatternEngine creates a new pattern engine
+// NewPatternEngine creates a new pattern engine in the named mode (see
+// EngineModeNamed). An unknown name gives ModeClassical; use
+// EngineModeNamed and SetMode to reject it instead.
 func NewPatternEngine(mode string) *PatternEngine {
-	if mode == "" {
-		mode = "classical"
-	}
+	m, _ := EngineModeNamed(mode)
 	return &PatternEngine{
 		pairs: make([]*BipartitePair, 0),
-		mode:  mode,
+		index: newPairIndex(),
+		mode:  m,
 	}
 }
 
 // AddPair adds a bipartite pattern pair
 func (e *PatternEngine) AddPair(leftPattern, rightPattern string, priority uint32, rightIsLiteral bool) bool {
-	e.lock.Lock()
-	defer e.lock.Unlock()
+	return e.AddPairErr(leftPattern, rightPattern, priority, rightIsLiteral) == nil
+}
+
+// AddPairErr is AddPair returning a GovernanceError on failure
+func (e *PatternEngine) AddPairErr(leftPattern, rightPattern string, priority uint32, rightIsLiteral bool) error {
+	_, err := e.AddPairID(leftPattern, rightPattern, priority, rightIsLiteral)
+	return err
+}
+
+// AddPairID is AddPairErr returning the new pair's TransformID. IDs are
+// allocated in increasing order and never reused, so they stay valid
+// across RemovePair and UpdatePair.
+func (e *PatternEngine) AddPairID(leftPattern, rightPattern string, priority uint32, rightIsLiteral bool) (uint32, error) {
+	return e.AddGroupPair("", leftPattern, rightPattern, priority, rightIsLiteral)
+}
+
+// addPair registers a compiled pair under the next TransformID; e.lock held
+func (e *PatternEngine) addPair(pair *BipartitePair) uint32 {
+	e.nextID++
+	pair.TransformID = e.nextID
+	e.pairs = append(e.pairs, pair)
+	e.index.add(pair)
+	if e.disabled[pair.Group] {
+		e.index.park(pair)
+	}
+	e.version++
+	return pair.TransformID
+}
+
+// newPair compiles a pair without registering it or assigning its ID
+func newPair(leftPattern, rightPattern string, priority uint32, mode Substitution) (*BipartitePair, error) {
+	if mode < SubstituteNone || mode > SubstituteBoth {
+		return nil, fmt.Errorf("add pair: unknown substitution mode %v", mode)
+	}
 
 	// Create left pattern (input matcher)
 	left := &RiftPattern{
@@ -97,10 +188,10 @@ func (e *PatternEngine) AddPair(leftPattern, rightPattern string, priority uint3
 		IsLiteral:  false,
 	}
 
-	// Compile left regex
-	compiled, err := regexp.Compile(leftPattern)
+	// Compile left regex, shared with other pairs using it
+	compiled, err := compileShared(left, leftPattern)
 	if err != nil {
-		return false
+		return nil, &GovernanceError{Code: CodeRegexCompile, Op: "add pair", Detail: leftPattern, Err: err}
 	}
 	left.CompiledRegex = compiled
 
@@ -110,134 +201,302 @@ func (e *PatternEngine) AddPair(leftPattern, rightPattern string, priority uint3
 		Polarity:   PatternRight,
 		Priority:   priority,
 		Anchored:   false,
-		IsLiteral:  rightIsLiteral,
+		IsLiteral:  mode == SubstituteNone,
 	}
 
-	// Compile right if it's not a literal
-	if !rightIsLiteral {
-		compiled, err := regexp.Compile(rightPattern)
-		if err != nil {
-			right.IsLiteral = true
-		} else {
+	// Compile right if it's not a literal. Templates need not be valid
+	// regexps, so a right that fails to compile still substitutes.
+	if !right.IsLiteral {
+		if compiled, err := compileShared(right, rightPattern); err == nil {
 			right.CompiledRegex = compiled
 		}
 	}
 
 	// Create pair
 	pair := &BipartitePair{
-		Left:        left,
-		Right:       right,
-		TransformFn: nil,
-		IsGoverned:  false,
-		TransformID: uint32(len(e.pairs) + 1),
+		Left:         left,
+		Right:        right,
+		TransformFn:  nil,
+		IsGoverned:   false,
+		Substitution: mode,
 	}
+	if !right.IsLiteral {
+		pair.Plan = compilePlan(rightPattern, left.CompiledRegex, mode)
+	}
+	pair.NeedsCaptures = pair.capturesNeeded()
+	return pair, nil
+}
 
-	e.pairs = append(e.pairs, pair)
-	return true
+// ============================================================================
+// Pair Set Changes
+// ============================================================================
+
+// RemovePair removes the pair with the given TransformID
+func (e *PatternEngine) RemovePair(id uint32) error {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	i, ok := e.pairPos(id)
+	if !ok {
+		return govErr(CodePairNotFound, "remove pair", "no pair %d", id)
+	}
+	e.index.remove(e.pairs[i])
+	e.pairs = append(e.pairs[:i], e.pairs[i+1:]...)
+	e.version++
+	return nil
 }
 
-// Match matches input against all left patterns, returns best match
-func (e *PatternEngine) Match(input string) *MatchResult {
-	startTime := time.Now()
+// UpdatePair replaces the patterns and priority of the pair with the given
+// TransformID. The pair keeps its ID, its governance flag, TransformFn and
+// group, and its registration order for tie-breaking. On error the pair is left
+// unchanged.
+func (e *PatternEngine) UpdatePair(id uint32, leftPattern, rightPattern string, priority uint32, rightIsLiteral bool) error {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	i, ok := e.pairPos(id)
+	if !ok {
+		return govErr(CodePairNotFound, "update pair", "no pair %d", id)
+	}
+	pair, err := newPair(leftPattern, rightPattern, priority, literalSubstitution(rightIsLiteral))
+	if err != nil {
+		return err
+	}
+	old := e.pairs[i]
+	pair.TransformID = id
+	pair.IsGoverned = old.IsGoverned
+	pair.TransformFn = old.TransformFn
+	pair.Group = old.Group
+	e.index.replace(old, pair)
+	e.pairs[i] = pair
+	e.version++
+	return nil
+}
 
+// Version returns the pair-set version, which every AddPair, RemovePair
+// and UpdatePair increments. MatchResult.Version reports the version a
+// match ran against.
+func (e *PatternEngine) Version() uint64 {
 	e.lock.RLock()
 	defer e.lock.RUnlock()
+	return e.version
+}
+
+// pairPos returns the position in e.pairs of the pair with the given ID;
+// e.lock held
+func (e *PatternEngine) pairPos(id uint32) (int, bool) {
+	i := sort.Search(len(e.pairs), func(i int) bool { return e.pairs[i].TransformID >= id })
+	return i, i < len(e.pairs) && e.pairs[i].TransformID == id
+}
 
-	var bestPair *BipartitePair
-	var bestPriority uint32 = ^uint32(0) // Max uint32
-	var bestMatch []string
-	var bestGroups map[string]string
+// Match matches input against all left patterns, returns best match. The
+// engine's mode decides what the result holds (see EngineMode).
+func (e *PatternEngine) Match(input string) *MatchResult {
+	result, _ := e.match(context.Background(), input, true)
+	return result
+}
 
-	// Search for matching pattern (respecting priority)
-	for _, pair := range e.pairs {
-		if pair.Left.CompiledRegex == nil {
-			continue
+// MatchContext is Match honoring ctx: it stops scanning candidates once ctx
+// is done and returns a GovernanceError wrapping ctx.Err(). In ModeStrict
+// it fails with CodeNoMatch when no pair matches.
+func (e *PatternEngine) MatchContext(ctx context.Context, input string) (*MatchResult, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, contextError("match", CodeCanceled, err)
+	}
+	result, err := e.match(ctx, input, true)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, contextError("match", CodeCanceled, ctx.Err())
 		}
+		return nil, err
+	}
+	return result, nil
+}
 
-		// Check priority - lower number = higher priority
-		if pair.Left.Priority > bestPriority {
-			continue
+// matchCheckInterval is how many candidates match scans between checks of
+// its done channel
+const matchCheckInterval = 16
+
+// match implements Match, giving up when ctx is done. parallel allows
+// fanning the candidate scan across the engine's workers. In ModeStrict an
+// unmatched result comes with its error.
+func (e *PatternEngine) match(ctx context.Context, input string, parallel bool) (result *MatchResult, err error) {
+	startTime := e.now()
+	if span := e.startMatchSpan(ctx); span != nil {
+		defer func() { endMatchSpan(span, result, err, e.since(startTime)) }()
+	}
+
+	e.lock.RLock()
+	defer e.lock.RUnlock()
+
+	if e.cache != nil {
+		if res, ok := e.cache.get(input, e.version); ok {
+			e.updateMetrics(e.since(startTime), res.Matched)
+			return &res, e.modeError(input, &res)
 		}
+	}
+	if result, err = e.matchLocked(ctx.Done(), input, parallel); err != nil {
+		return nil, err
+	}
+	e.applyMode(input, result)
+	if e.cache != nil {
+		e.cache.put(input, e.version, *result)
+	}
+	e.updateMetrics(e.since(startTime), result.Matched)
+	return result, e.modeError(input, result)
+}
 
-		// Try to match input against left pattern
-		matches := pair.Left.CompiledRegex.FindStringSubmatch(input)
-		if matches != nil {
-			bestPair = pair
-			bestPriority = pair.Left.Priority
-			bestMatch = matches
-			bestGroups = make(map[string]string)
-
-			// Extract named groups
-			for i, name := range pair.Left.CompiledRegex.SubexpNames() {
-				if i > 0 && i < len(matches) && name != "" {
-					bestGroups[name] = matches[i]
-				}
-			}
+// matchLocked finds the match the engine's selection strategy chooses;
+// e.lock held
+func (e *PatternEngine) matchLocked(done <-chan struct{}, input string, parallel bool) (*MatchResult, error) {
+	if e.selection != SelectPriority {
+		results, err := e.selectLocked(done, input)
+		if err != nil {
+			return nil, err
+		}
+		if len(results) == 0 {
+			return &MatchResult{Matched: false, Version: e.version}, nil
 		}
+		return &results[0], nil
 	}
 
-	// Generate output
-	if bestPair != nil {
-		template := bestPair.Right.PatternStr
-		output := template
+	// Candidates arrive in rank order (lower number = higher priority), so
+	// the first match is the best match
+	candidates := e.index.candidates(input)
+	var best int
+	var bestLoc []int
+	var err error
+	if parallel && e.workers > 1 && len(candidates) >= parallelMinCandidates {
+		best, bestLoc, err = scanParallel(done, candidates, input, e.workers)
+	} else if e.adaptive != nil {
+		best, bestLoc, err = e.adaptive.scan(done, e.index, candidates, input)
+	} else {
+		best, bestLoc, err = scanCandidates(done, candidates, input)
+	}
+	if err != nil {
+		return nil, err
+	}
 
-		if !bestPair.Right.IsLiteral {
-			// Substitute capture groups
-			for i, match := range bestMatch {
-				if i > 0 {
-					placeholder := fmt.Sprintf("$%d", i)
-					output = regexp.MustCompile(regexp.QuoteMeta(placeholder)).
-						ReplaceAllString(output, match)
-				}
-			}
-			// Substitute named groups
-			for name, value := range bestGroups {
-				placeholder := fmt.Sprintf("{%s}", name)
-				output = regexp.MustCompile(regexp.QuoteMeta(placeholder)).
-					ReplaceAllString(output, value)
-			}
+	if best >= 0 {
+		if e.index.tie == TieLongestMatch {
+			best, bestLoc = longestAmongTies(candidates, best, input)
 		}
-
-		// Update metrics
-		elapsed := float64(time.Since(startTime).Nanoseconds()) / 1000000.0
-		e.totalMatches++
-		e.updateMetrics(elapsed)
-
-		return &MatchResult{
-			Matched:     true,
-			Output:      output,
-			Priority:    bestPriority,
-			TransformID: bestPair.TransformID,
-			Groups:      bestGroups,
+		if e.adaptive != nil {
+			e.adaptive.won(e.index, candidates[best])
 		}
+		res := locResult(candidates[best], input, bestLoc)
+		res.Version = e.version
+		return &res, nil
 	}
 
 	// No match found
-	elapsed := float64(time.Since(startTime).Nanoseconds()) / 1000000.0
-	e.totalFailures++
-	e.updateMetrics(elapsed)
+	return &MatchResult{Matched: false, Version: e.version}, nil
+}
 
-	return &MatchResult{Matched: false}
+// result builds the MatchResult for a pair's submatches
+func (pair *BipartitePair) result(submatches []string) MatchResult {
+	var groups map[string]string
+	for i, name := range pair.Left.CompiledRegex.SubexpNames() {
+		if i > 0 && i < len(submatches) && name != "" {
+			if groups == nil {
+				groups = make(map[string]string)
+			}
+			groups[name] = submatches[i]
+		}
+	}
+
+	output := pair.Right.PatternStr
+	if pair.Plan != nil {
+		output = pair.Plan.Expand(submatches)
+	}
+	return MatchResult{
+		Matched:     true,
+		Output:      output,
+		Priority:    pair.Left.Priority,
+		TransformID: pair.TransformID,
+		Groups:      groups,
+	}
+}
+
+// scanCandidates returns the index of the first candidate matching input
+// and its submatch index pairs, or -1
+func scanCandidates(done <-chan struct{}, candidates []*indexedPair, input string) (int, []int, error) {
+	for i, ip := range candidates {
+		if done != nil && i%matchCheckInterval == 0 {
+			select {
+			case <-done:
+				return -1, nil, context.Canceled
+			default:
+			}
+		}
+		if loc := ip.match(input); loc != nil {
+			return i, loc, nil
+		}
+	}
+	return -1, nil, nil
 }
 
-// updateMetrics updates running average match time
-func (e *PatternEngine) updateMetrics(elapsedMs float64) {
+// updateMetrics updates counters and running average match time, then
+// notifies observers
+func (e *PatternEngine) updateMetrics(elapsed time.Duration, matched bool) {
+	elapsedMs := float64(elapsed.Nanoseconds()) / 1000000.0
+
+	e.metricsLock.Lock()
+	if matched {
+		e.totalMatches++
+	} else {
+		e.totalFailures++
+	}
 	total := e.totalMatches + e.totalFailures
-	if total > 0 {
-		e.averageMatchTimeMs = ((e.averageMatchTimeMs * float64(total-1)) + elapsedMs) / float64(total)
+	e.averageMatchTimeMs = ((e.averageMatchTimeMs * float64(total-1)) + elapsedMs) / float64(total)
+	observers := e.observers
+	e.metricsLock.Unlock()
+	recordMatch(elapsed, matched)
+
+	for _, fn := range observers {
+		fn(elapsed, matched)
 	}
 }
 
+// AddMatchObserver registers fn to be called after every Match
+func (e *PatternEngine) AddMatchObserver(fn MatchObserver) {
+	e.metricsLock.Lock()
+	defer e.metricsLock.Unlock()
+	e.observers = append(e.observers[:len(e.observers):len(e.observers)], fn)
+}
+
 // GetMetrics returns engine metrics
 func (e *PatternEngine) GetMetrics() map[string]interface{} {
-	e.lock.RLock()
-	defer e.lock.RUnlock()
-
+	stats := e.Stats()
 	return map[string]interface{}{
-		"totalMatches":       e.totalMatches,
-		"totalFailures":      e.totalFailures,
-		"averageMatchTimeMs": e.averageMatchTimeMs,
-		"pairCount":          len(e.pairs),
+		"totalMatches":       stats.TotalMatches,
+		"totalFailures":      stats.TotalFailures,
+		"averageMatchTimeMs": stats.AverageMatchTimeMs,
+		"pairCount":          stats.PairCount,
+		"cacheHits":          stats.CacheHits,
+		"cacheMisses":        stats.CacheMisses,
+	}
+}
+
+// Stats returns a typed snapshot of engine metrics
+func (e *PatternEngine) Stats() EngineStats {
+	e.lock.RLock()
+	pairCount := len(e.pairs)
+	var hits, misses uint64
+	if e.cache != nil {
+		hits, misses = e.cache.counts()
+	}
+	e.lock.RUnlock()
+
+	e.metricsLock.Lock()
+	defer e.metricsLock.Unlock()
+	return EngineStats{
+		TotalMatches:       e.totalMatches,
+		TotalFailures:      e.totalFailures,
+		AverageMatchTimeMs: e.averageMatchTimeMs,
+		PairCount:          pairCount,
+		CacheHits:          hits,
+		CacheMisses:        misses,
 	}
 }
 
@@ -254,30 +513,30 @@ func (e *PatternEngine) GetPairCount() int {
 
 // DefaultGoPatterns contains default Go code transformation patterns
 var DefaultGoPatterns = []struct {
-	Left           string
-	Right          string
-	Priority       uint32
-	RightIsLiteral bool
+	Left         string
+	Right        string
+	Priority     uint32
+	Substitution Substitution
 }{
 	// Variable declaration transformation
-	{`var\s+(\w+)\s+(\w+)`, `riftVar$2("$1")`, 100, true},
+	{`var\s+(\w+)\s+(\w+)`, `riftVar$2("$1")`, 100, SubstituteIndexed},
 	// Function declaration
-	{`func\s+(\w+)\s*\(\s*\)`, `riftFunc("$1", func()`, 90, true},
+	{`func\s+(\w+)\s*\(\s*\)`, `riftFunc("$1", func()`, 90, SubstituteIndexed},
 	// Goroutine
-	{`go\s+(\w+)\s*\(\)`, `riftGo(riftFunc("$1", func()`, 85, true},
+	{`go\s+(\w+)\s*\(\)`, `riftGo(riftFunc("$1", func()`, 85, SubstituteIndexed},
 	// Const declaration
-	{`const\s+(\w+)\s*=\s*(.+)`, `riftConst("$1", $2)`, 80, true},
+	{`const\s+(\w+)\s*=\s*(.+)`, `riftConst("$1", $2)`, 80, SubstituteIndexed},
 	// Type declaration
-	{`type\s+(\w+)\s+struct`, `riftStruct("$1", struct`, 70, true},
+	{`type\s+(\w+)\s+struct`, `riftStruct("$1", struct`, 70, SubstituteIndexed},
 	// Quantum decorator
-	{`@quantum`, `@riftQuantumDecorator`, 200, true},
+	{`@quantum`, `@riftQuantumDecorator`, 200, SubstituteNone},
 }
 
 // CreateDefaultEngine creates a pattern engine with default Go transformations
 func CreateDefaultEngine() *PatternEngine {
 	engine := NewPatternEngine("classical")
 	for _, pattern := range DefaultGoPatterns {
-		engine.AddPair(pattern.Left, pattern.Right, pattern.Priority, pattern.RightIsLiteral)
+		engine.AddPairSubstitution(pattern.Left, pattern.Right, pattern.Priority, pattern.Substitution)
 	}
 	return engine
 }
@@ -0,0 +1,117 @@
+// go/target/pattern_explain.go
+// Pattern Match Explanation - Go Implementation
+
+package rift
+
+import "fmt"
+
+// ============================================================================
+// PairTrace
+// ============================================================================
+
+// PairTrace reports how one pair fared against an input
+type PairTrace struct {
+	TransformID uint32
+	Left        string
+	Right       string
+	Priority    uint32
+	Rank        int  // evaluation order; 0 is tried first
+	Matched     bool // the left pattern matches the input
+	Winner      bool // the pair Match would return
+	Reason      string
+	Submatches  []string          // whole match, then each group; nil unless Matched
+	Groups      map[string]string // named groups; nil unless Matched
+	Output      string            // what the pair would produce; "" unless Matched
+}
+
+// ============================================================================
+// Explain
+// ============================================================================
+
+// Explain traces every pair against input in rank order, as Match would
+// try them, but without stopping at the first match: each trace says
+// whether the pair matched, what it captured and would output, and, for
+// matching pairs that lost, which pair beat them and why. Explain does not
+// update the engine's metrics.
+func (e *PatternEngine) Explain(input string) []PairTrace {
+	e.lock.RLock()
+	defer e.lock.RUnlock()
+
+	all := e.index.all()
+	traces := make([]PairTrace, len(all))
+	locs := make([][]int, len(all))
+	winner := -1
+	for i, ip := range all {
+		pair := ip.pair
+		tr := &traces[i]
+		*tr = PairTrace{
+			TransformID: pair.TransformID,
+			Left:        pair.Left.PatternStr,
+			Right:       pair.Right.PatternStr,
+			Priority:    pair.Left.Priority,
+			Rank:        i,
+		}
+
+		if pair.Left.CompiledRegex != nil {
+			locs[i] = pair.Left.CompiledRegex.FindStringSubmatchIndex(input)
+		}
+		if locs[i] == nil {
+			tr.Reason = "no match"
+			continue
+		}
+
+		submatches := submatchStrings(input, locs[i])
+		res := pair.result(submatches)
+		tr.Matched = true
+		tr.Submatches = submatches
+		tr.Groups = res.Groups
+		tr.Output = res.Output
+		if winner < 0 {
+			winner = i
+		}
+	}
+	if winner < 0 {
+		return traces
+	}
+
+	reason := "first match in rank order"
+	if e.selection != SelectPriority {
+		winner = selectBest(e.selection, locs)
+		if winner < 0 {
+			return traces
+		}
+		reason = selectWinReason(e.selection, locs[winner])
+	} else if e.index.tie == TieLongestMatch {
+		first := winner
+		for i := first + 1; i < len(all) && all[i].pair.Left.Priority == all[first].pair.Left.Priority; i++ {
+			if locs[i] != nil && matchLen(locs[i]) > matchLen(locs[winner]) {
+				winner = i
+			}
+		}
+		reason = fmt.Sprintf("longest match among priority %d", all[winner].pair.Left.Priority)
+	}
+	traces[winner].Winner = true
+	traces[winner].Reason = reason
+
+	w := all[winner]
+	for i, ip := range all {
+		if locs[i] == nil || i == winner {
+			continue
+		}
+		if r := selectReason(e.selection, locs[winner], locs[i]); r != "" {
+			traces[i].Reason = fmt.Sprintf("lost to pair %d: %s", w.pair.TransformID, r)
+		} else if w.pair.Left.Priority < ip.pair.Left.Priority {
+			traces[i].Reason = fmt.Sprintf("lost to pair %d: priority %d ranks ahead of %d",
+				w.pair.TransformID, w.pair.Left.Priority, ip.pair.Left.Priority)
+		} else {
+			traces[i].Reason = fmt.Sprintf("lost to pair %d: %s", w.pair.TransformID,
+				e.index.tieReason(w, ip, matchLen(locs[winner]), matchLen(locs[i])))
+		}
+	}
+	return traces
+}
+
+// matchLen returns the byte length of the match at loc
+func matchLen(loc []int) int {
+	return loc[1] - loc[0]
+}
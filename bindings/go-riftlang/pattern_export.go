@@ -0,0 +1,98 @@
+// go/target/pattern_export.go
+// Pattern Engine Export and Import - Go Implementation
+
+package rift
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// EngineExportFormat and EngineExportVersion identify the documents
+// written by Export
+const (
+	EngineExportFormat  = "rift-engine"
+	EngineExportVersion = 1
+)
+
+// ============================================================================
+// EngineExport
+// ============================================================================
+
+// EngineExport is a pattern engine as Export writes it: its configuration,
+// in the form Snapshot gives registered engines, and its match counters.
+// It holds no timestamps and its pairs are in TransformID order, so
+// exporting the same engine twice gives the same bytes and changes to an
+// engine show as small diffs.
+type EngineExport struct {
+	Format  string        `json:"format"`
+	Version int           `json:"version"`
+	Engine  EngineDump    `json:"engine"`
+	Metrics EngineMetrics `json:"metrics"`
+}
+
+// EngineMetrics is the part of EngineStats an import carries over. Cache
+// counters and the pair count are left out: the first starts afresh and
+// the second follows from the pairs.
+type EngineMetrics struct {
+	TotalMatches       uint64  `json:"totalMatches"`
+	TotalFailures      uint64  `json:"totalFailures"`
+	AverageMatchTimeMs float64 `json:"averageMatchTimeMs"`
+}
+
+// Export writes the engine to w as an indented JSON EngineExport, for
+// ImportEngine to load. It fails, writing nothing, if a pair has a
+// TransformFn, since functions cannot be exported.
+func (e *PatternEngine) Export(w io.Writer) error {
+	ed, err := e.dump("")
+	if err != nil {
+		return fmt.Errorf("export engine: %w", err)
+	}
+	stats := e.Stats()
+	data, err := json.MarshalIndent(EngineExport{
+		Format:  EngineExportFormat,
+		Version: EngineExportVersion,
+		Engine:  ed,
+		Metrics: EngineMetrics{
+			TotalMatches:       stats.TotalMatches,
+			TotalFailures:      stats.TotalFailures,
+			AverageMatchTimeMs: stats.AverageMatchTimeMs,
+		},
+	}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("export engine: %w", err)
+	}
+	_, err = w.Write(append(data, '\n'))
+	return err
+}
+
+// ImportEngine reads an engine written by Export and returns a new engine
+// with the same pairs, TransformIDs, groups, mode, selection, tie-break,
+// workers, match cache size and match counters. The engine is not
+// registered; pass it to RegisterEngine to make Snapshot capture it.
+// Documents of another format or a newer version, and unknown modes, are
+// rejected.
+func ImportEngine(r io.Reader) (*PatternEngine, error) {
+	var doc EngineExport
+	if err := json.NewDecoder(r).Decode(&doc); err != nil {
+		return nil, fmt.Errorf("import engine: %w", err)
+	}
+	if doc.Format != EngineExportFormat {
+		return nil, fmt.Errorf("import engine: format %q is not %q", doc.Format, EngineExportFormat)
+	}
+	if doc.Version < 1 || doc.Version > EngineExportVersion {
+		return nil, fmt.Errorf("import engine: unsupported version %d", doc.Version)
+	}
+	if _, err := EngineModeNamed(doc.Engine.Mode); err != nil {
+		return nil, fmt.Errorf("import engine: %w", err)
+	}
+	e, err := doc.Engine.engine()
+	if err != nil {
+		return nil, fmt.Errorf("import engine: %w", err)
+	}
+	e.totalMatches = doc.Metrics.TotalMatches
+	e.totalFailures = doc.Metrics.TotalFailures
+	e.averageMatchTimeMs = doc.Metrics.AverageMatchTimeMs
+	return e, nil
+}
@@ -0,0 +1,247 @@
+// go/target/pattern_group.go
+// Pattern Groups and Engine Merging - Go Implementation
+
+package rift
+
+import (
+	"fmt"
+	"sort"
+)
+
+// ============================================================================
+// Groups
+// ============================================================================
+
+// AddGroupPair is AddPairID adding the pair to a named group, which can be
+// disabled and enabled as a whole. AddPairID adds to the group "". A pair
+// added to a disabled group is registered but does not match until the
+// group is enabled.
+func (e *PatternEngine) AddGroupPair(group, leftPattern, rightPattern string, priority uint32, rightIsLiteral bool) (uint32, error) {
+	return e.addGroupPair(group, leftPattern, rightPattern, priority, literalSubstitution(rightIsLiteral))
+}
+
+func (e *PatternEngine) addGroupPair(group, leftPattern, rightPattern string, priority uint32, mode Substitution) (uint32, error) {
+	pair, err := newPair(leftPattern, rightPattern, priority, mode)
+	if err != nil {
+		return 0, err
+	}
+	pair.Group = group
+
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	return e.addPair(pair), nil
+}
+
+// SetPairGroup moves the pair with the given TransformID to group. The pair
+// keeps its registration order and matches only if group is enabled.
+func (e *PatternEngine) SetPairGroup(id uint32, group string) error {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	i, ok := e.pairPos(id)
+	if !ok {
+		return govErr(CodePairNotFound, "set pair group", "no pair %d", id)
+	}
+	pair := e.pairs[i]
+	if pair.Group == group {
+		return nil
+	}
+	pair.Group = group
+	if e.disabled[group] {
+		e.index.park(pair)
+	} else {
+		e.index.unpark(pair)
+	}
+	e.version++
+	return nil
+}
+
+// DisableGroup takes every pair of a group out of matching, for Match,
+// Select, Transform and Explain alike, and bumps the pair-set version. The
+// pairs stay registered, keep their IDs and registration order, and can be
+// updated or removed. Groups need not have pairs to be disabled; pairs
+// added to the group later start disabled.
+func (e *PatternEngine) DisableGroup(group string) {
+	e.setGroupEnabled(group, false)
+}
+
+// EnableGroup returns a disabled group's pairs to matching
+func (e *PatternEngine) EnableGroup(group string) {
+	e.setGroupEnabled(group, true)
+}
+
+// GroupEnabled reports whether a group's pairs take part in matching.
+// Groups are enabled until disabled.
+func (e *PatternEngine) GroupEnabled(group string) bool {
+	e.lock.RLock()
+	defer e.lock.RUnlock()
+	return !e.disabled[group]
+}
+
+// Groups returns, sorted, the names of the groups that have pairs or are
+// disabled
+func (e *PatternEngine) Groups() []string {
+	e.lock.RLock()
+	defer e.lock.RUnlock()
+	seen := make(map[string]bool)
+	for _, p := range e.pairs {
+		seen[p.Group] = true
+	}
+	for g := range e.disabled {
+		seen[g] = true
+	}
+	names := make([]string, 0, len(seen))
+	for g := range seen {
+		names = append(names, g)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func (e *PatternEngine) setGroupEnabled(group string, enabled bool) {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	e.setGroupLocked(group, enabled)
+}
+
+// setGroupLocked enables or disables a group; e.lock held
+func (e *PatternEngine) setGroupLocked(group string, enabled bool) {
+	if !e.disabled[group] == enabled {
+		return
+	}
+	if enabled {
+		delete(e.disabled, group)
+	} else {
+		if e.disabled == nil {
+			e.disabled = make(map[string]bool)
+		}
+		e.disabled[group] = true
+	}
+	for _, p := range e.pairs {
+		if p.Group != group {
+			continue
+		}
+		if enabled {
+			e.index.unpark(p)
+		} else {
+			e.index.park(p)
+		}
+	}
+	e.version++
+}
+
+// disabledGroups returns the disabled group names, sorted; e.lock held
+func (e *PatternEngine) disabledGroups() []string {
+	var names []string
+	for g := range e.disabled {
+		names = append(names, g)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ============================================================================
+// Merging
+// ============================================================================
+
+// MergeConflict selects what Merge does with a pair whose left pattern an
+// existing pair already has
+type MergeConflict int
+
+const (
+	// MergeOverride updates the existing pair to the merged pair's right
+	// pattern, priority, governance flag, TransformFn and group. The pair
+	// keeps its TransformID and registration order. This is the default,
+	// so a project engine merged into an organization base overrides its
+	// rules.
+	MergeOverride MergeConflict = iota
+
+	// MergeKeepExisting skips the merged pair, so the base engine wins
+	MergeKeepExisting
+
+	// MergeKeepBoth adds the merged pair alongside the existing one.
+	// Priority decides between them and, on equal priority, the tie-break
+	// mode, the merged pair counting as registered later.
+	MergeKeepBoth
+)
+
+// String returns the mode name
+func (m MergeConflict) String() string {
+	switch m {
+	case MergeOverride:
+		return "override"
+	case MergeKeepExisting:
+		return "keep-existing"
+	case MergeKeepBoth:
+		return "keep-both"
+	}
+	return fmt.Sprintf("MergeConflict(%d)", int(m))
+}
+
+// Merge adds other's pairs to e, resolving pairs with an existing left
+// pattern by MergeOverride (see MergeWith)
+func (e *PatternEngine) Merge(other *PatternEngine) error {
+	return e.MergeWith(other, MergeOverride)
+}
+
+// MergeWith adds other's pairs to e in other's TransformID order, under
+// new TransformIDs and after e's pairs in registration order, so the
+// result is the same every time. Pairs keep their group, governance flag
+// and TransformFn, and groups disabled in other are disabled in e. A pair
+// whose left pattern an existing pair already has is resolved by conflict;
+// pairs merged in the same call never conflict with each other. other is
+// not changed.
+func (e *PatternEngine) MergeWith(other *PatternEngine, conflict MergeConflict) error {
+	if other == nil {
+		return fmt.Errorf("merge: nil engine")
+	}
+	if other == e {
+		return fmt.Errorf("merge: engine merged into itself")
+	}
+	if conflict < MergeOverride || conflict > MergeKeepBoth {
+		return fmt.Errorf("merge: unknown conflict mode %v", conflict)
+	}
+
+	// Copy other's pairs before taking e's lock, so concurrent merges in
+	// both directions cannot deadlock
+	other.lock.RLock()
+	merged := make([]BipartitePair, len(other.pairs))
+	for i, p := range other.pairs {
+		merged[i] = *p
+	}
+	disabled := other.disabledGroups()
+	other.lock.RUnlock()
+
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	for _, g := range disabled {
+		e.setGroupLocked(g, false)
+	}
+
+	existing := make(map[string]int, len(e.pairs))
+	for i, p := range e.pairs {
+		if _, ok := existing[p.Left.PatternStr]; !ok {
+			existing[p.Left.PatternStr] = i
+		}
+	}
+	for i := range merged {
+		pair := &merged[i]
+		j, clash := existing[pair.Left.PatternStr]
+		switch {
+		case !clash || conflict == MergeKeepBoth:
+			e.addPair(pair)
+		case conflict == MergeOverride:
+			old := e.pairs[j]
+			pair.TransformID = old.TransformID
+			e.index.replace(old, pair)
+			if e.disabled[pair.Group] {
+				e.index.park(pair)
+			} else {
+				e.index.unpark(pair)
+			}
+			e.pairs[j] = pair
+			e.version++
+		}
+	}
+	return nil
+}
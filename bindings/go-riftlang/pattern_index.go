@@ -0,0 +1,320 @@
+// go/target/pattern_index.go
+// Priority-Indexed Pair Lookup - Go Implementation
+
+package rift
+
+import (
+	"regexp/syntax"
+	"sort"
+	"strings"
+	"sync/atomic"
+)
+
+// ============================================================================
+// indexedPair
+// ============================================================================
+
+// indexedPair is a pair plus the data the index needs to rank and prefilter it
+type indexedPair struct {
+	pair        *BipartitePair
+	seq         uint64 // registration order
+	prefix      string // literal every match must contain (anchored: start with)
+	anchored    bool
+	specificity int           // literal characters and anchors, for TieMostSpecific
+	wins        atomic.Uint64 // decaying win count, for SetAdaptiveOrder
+}
+
+// ============================================================================
+// prefixTrie
+// ============================================================================
+
+// prefixTrie indexes anchored pairs by their literal prefix so that only
+// pairs whose prefix begins the input are considered
+type prefixTrie struct {
+	children map[byte]*prefixTrie
+	pairs    []*indexedPair
+}
+
+func newPrefixTrie() *prefixTrie {
+	return &prefixTrie{children: make(map[byte]*prefixTrie)}
+}
+
+func (t *prefixTrie) insert(ip *indexedPair) {
+	node := t
+	for i := 0; i < len(ip.prefix); i++ {
+		c := ip.prefix[i]
+		child, ok := node.children[c]
+		if !ok {
+			child = newPrefixTrie()
+			node.children[c] = child
+		}
+		node = child
+	}
+	node.pairs = append(node.pairs, ip)
+}
+
+// remove deletes pair from the node for prefix, returning its entry or nil.
+// Emptied nodes are left in place; they cost only a map lookup.
+func (t *prefixTrie) remove(prefix string, pair *BipartitePair) *indexedPair {
+	node := t
+	for i := 0; i < len(prefix) && node != nil; i++ {
+		node = node.children[prefix[i]]
+	}
+	if node == nil {
+		return nil
+	}
+	for i, ip := range node.pairs {
+		if ip.pair == pair {
+			node.pairs = append(node.pairs[:i:i], node.pairs[i+1:]...)
+			return ip
+		}
+	}
+	return nil
+}
+
+// collect appends every pair whose prefix is a prefix of input
+func (t *prefixTrie) collect(input string, out []*indexedPair) []*indexedPair {
+	node := t
+	out = append(out, node.pairs...)
+	for i := 0; i < len(input); i++ {
+		child, ok := node.children[input[i]]
+		if !ok {
+			break
+		}
+		node = child
+		out = append(out, node.pairs...)
+	}
+	return out
+}
+
+// ============================================================================
+// pairIndex
+// ============================================================================
+
+// pairIndex keeps pairs ordered by rank so Match can stop at the first hit
+type pairIndex struct {
+	seq      uint64
+	gen      uint64 // bumped by every change to the indexed pairs or their rank
+	tie      TieBreak
+	anchored *prefixTrie
+	floating []*indexedPair            // sorted by rank
+	parked   map[*BipartitePair]uint64 // pairs of disabled groups, by registration order
+}
+
+func newPairIndex() *pairIndex {
+	return &pairIndex{anchored: newPrefixTrie(), parked: make(map[*BipartitePair]uint64)}
+}
+
+// before reports whether a ranks ahead of b: lower priority number first
+// and, on equal priority, as the tie-break mode orders them. The default
+// puts the most recently registered pair first (the pair that the original
+// linear scan would have kept); TieLongestMatch ranks by registration and
+// re-ranks at match time.
+func (x *pairIndex) before(a, b *indexedPair) bool {
+	pa, pb := a.pair.Left.Priority, b.pair.Left.Priority
+	if pa != pb {
+		return pa < pb
+	}
+	switch x.tie {
+	case TieMostSpecific:
+		if a.specificity != b.specificity {
+			return a.specificity > b.specificity
+		}
+		return a.seq < b.seq
+	case TieFirstRegistered, TieLongestMatch:
+		return a.seq < b.seq
+	}
+	return a.seq > b.seq
+}
+
+// setTieBreak changes the tie-break mode and re-ranks; callers must hold
+// the engine write lock
+func (x *pairIndex) setTieBreak(tb TieBreak) {
+	x.tie = tb
+	x.gen++
+	sort.Slice(x.floating, func(i, j int) bool { return x.before(x.floating[i], x.floating[j]) })
+}
+
+// add registers a pair; callers must hold the engine write lock
+func (x *pairIndex) add(pair *BipartitePair) {
+	x.seq++
+	x.insert(pair, x.seq)
+}
+
+// replace swaps old for pair, keeping old's registration order; callers
+// must hold the engine write lock
+func (x *pairIndex) replace(old, pair *BipartitePair) {
+	if seq, ok := x.parked[old]; ok {
+		delete(x.parked, old)
+		x.parked[pair] = seq
+		return
+	}
+	if ip := x.remove(old); ip != nil {
+		x.insert(pair, ip.seq)
+	}
+}
+
+// remove unregisters a pair, returning its entry or nil; callers must hold
+// the engine write lock
+func (x *pairIndex) remove(pair *BipartitePair) *indexedPair {
+	x.gen++
+	if seq, ok := x.parked[pair]; ok {
+		delete(x.parked, pair)
+		return &indexedPair{pair: pair, seq: seq}
+	}
+	prefix, anchored := literalPrefix(pair.Left.PatternStr)
+	if anchored && prefix != "" {
+		return x.anchored.remove(prefix, pair)
+	}
+	for i, ip := range x.floating {
+		if ip.pair == pair {
+			x.floating = append(x.floating[:i], x.floating[i+1:]...)
+			return ip
+		}
+	}
+	return nil
+}
+
+// park takes a pair out of matching, keeping its registration order for
+// unpark; callers must hold the engine write lock
+func (x *pairIndex) park(pair *BipartitePair) {
+	if _, ok := x.parked[pair]; ok {
+		return
+	}
+	if ip := x.remove(pair); ip != nil {
+		x.parked[pair] = ip.seq
+	}
+}
+
+// unpark returns a parked pair to matching; callers must hold the engine
+// write lock
+func (x *pairIndex) unpark(pair *BipartitePair) {
+	if seq, ok := x.parked[pair]; ok {
+		delete(x.parked, pair)
+		x.insert(pair, seq)
+	}
+}
+
+// insert indexes pair with the given registration order
+func (x *pairIndex) insert(pair *BipartitePair, seq uint64) {
+	x.gen++
+	ip := &indexedPair{pair: pair, seq: seq}
+	ip.prefix, ip.anchored = literalPrefix(pair.Left.PatternStr)
+	ip.specificity = specificity(pair.Left.PatternStr)
+
+	if ip.anchored && ip.prefix != "" {
+		x.anchored.insert(ip)
+		return
+	}
+
+	pos := sort.Search(len(x.floating), func(i int) bool {
+		return x.before(ip, x.floating[i])
+	})
+	x.floating = append(x.floating, nil)
+	copy(x.floating[pos+1:], x.floating[pos:])
+	x.floating[pos] = ip
+}
+
+// candidates returns the pairs that can match input, in rank order
+func (x *pairIndex) candidates(input string) []*indexedPair {
+	anchored := x.anchored.collect(input, nil)
+	if len(anchored) == 0 {
+		return x.floating
+	}
+	sort.Slice(anchored, func(i, j int) bool { return x.before(anchored[i], anchored[j]) })
+
+	// Merge the two rank-ordered lists
+	merged := make([]*indexedPair, 0, len(anchored)+len(x.floating))
+	i, j := 0, 0
+	for i < len(anchored) && j < len(x.floating) {
+		if x.before(anchored[i], x.floating[j]) {
+			merged = append(merged, anchored[i])
+			i++
+		} else {
+			merged = append(merged, x.floating[j])
+			j++
+		}
+	}
+	merged = append(merged, anchored[i:]...)
+	return append(merged, x.floating[j:]...)
+}
+
+// position returns the position of ip in candidates, which are in rank
+// order as candidates returns them, or -1. Rank is a total order, so a
+// binary search finds it.
+func (x *pairIndex) position(candidates []*indexedPair, ip *indexedPair) int {
+	i := sort.Search(len(candidates), func(i int) bool { return !x.before(candidates[i], ip) })
+	if i < len(candidates) && candidates[i] == ip {
+		return i
+	}
+	return -1
+}
+
+// all returns every indexed pair in rank order
+func (x *pairIndex) all() []*indexedPair {
+	var out []*indexedPair
+	var walk func(t *prefixTrie)
+	walk = func(t *prefixTrie) {
+		out = append(out, t.pairs...)
+		for _, child := range t.children {
+			walk(child)
+		}
+	}
+	walk(x.anchored)
+	out = append(out, x.floating...)
+	sort.Slice(out, func(i, j int) bool { return x.before(out[i], out[j]) })
+	return out
+}
+
+// mayMatch is a cheap prefilter run before the regex
+func (ip *indexedPair) mayMatch(input string) bool {
+	if ip.prefix == "" || ip.anchored {
+		return true
+	}
+	return strings.Contains(input, ip.prefix)
+}
+
+// match runs the prefilter and then the left regex, returning the
+// submatch index pairs of the leftmost match or nil. Pairs that do not
+// need captures take the cheaper FindStringIndex path, which locates only
+// the match itself.
+func (ip *indexedPair) match(input string) []int {
+	re := ip.pair.Left.CompiledRegex
+	if re == nil || !ip.mayMatch(input) {
+		return nil
+	}
+	if !ip.pair.NeedsCaptures {
+		return re.FindStringIndex(input)
+	}
+	return re.FindStringSubmatchIndex(input)
+}
+
+// literalPrefix returns the literal text every match of pattern must begin
+// with, and whether the pattern is anchored to the start of the input
+func literalPrefix(pattern string) (string, bool) {
+	re, err := syntax.Parse(pattern, syntax.Perl)
+	if err != nil {
+		return "", false
+	}
+	re = re.Simplify()
+
+	subs := []*syntax.Regexp{re}
+	if re.Op == syntax.OpConcat {
+		subs = re.Sub
+	}
+
+	anchored := false
+	if len(subs) > 0 && subs[0].Op == syntax.OpBeginText {
+		anchored = true
+		subs = subs[1:]
+	}
+
+	var sb strings.Builder
+	for _, sub := range subs {
+		if sub.Op != syntax.OpLiteral || sub.Flags&syntax.FoldCase != 0 {
+			break
+		}
+		sb.WriteString(string(sub.Rune))
+	}
+	return sb.String(), anchored
+}
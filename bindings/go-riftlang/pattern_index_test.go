@@ -0,0 +1,137 @@
+package rift
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+)
+
+// linearScan is the matcher the index replaced: every pair is tried and
+// the lowest priority number wins, the last registered among equals
+func linearScan(pairs []*BipartitePair, input string) *BipartitePair {
+	var best *BipartitePair
+	for _, p := range pairs {
+		if p.Left.CompiledRegex.MatchString(input) && (best == nil || p.Left.Priority <= best.Left.Priority) {
+			best = p
+		}
+	}
+	return best
+}
+
+// indexedScan is Match's priority path without metrics or result building
+func indexedScan(x *pairIndex, input string) *BipartitePair {
+	candidates := x.candidates(input)
+	best, _, _ := scanCandidates(nil, candidates, input)
+	if best < 0 {
+		return nil
+	}
+	return candidates[best].pair
+}
+
+// statementEngine returns an engine of n pairs: mostly anchored keyword
+// rules sharing a few priorities, with every tenth a floating one
+func statementEngine(tb testing.TB, n int) *PatternEngine {
+	tb.Helper()
+	e := NewPatternEngine("classical")
+	for i := 0; i < n; i++ {
+		left := fmt.Sprintf(`^stmt%d\b`, i)
+		if i%10 == 0 {
+			left = fmt.Sprintf(`tok%d$`, i)
+		}
+		if !e.AddPair(left, fmt.Sprintf("out%d", i), uint32(i%7), true) {
+			tb.Fatalf("AddPair(%q) failed", left)
+		}
+	}
+	return e
+}
+
+func statementInputs(n int) []string {
+	return []string{
+		fmt.Sprintf("stmt%d x := 1", n/2+1),
+		fmt.Sprintf("call tok%d", n/2),
+		"no rule matches this line",
+	}
+}
+
+func TestPairIndexMatchesLinearScan(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	e := NewPatternEngine("classical")
+	lefts := []string{`^a`, `^ab`, `^abc`, `b`, `bc`, `^b`, `c$`, `[a-c]+`, `^ab?c`, `x`}
+	for i := 0; i < 200; i++ {
+		left := lefts[rng.Intn(len(lefts))]
+		e.AddPair(left, fmt.Sprintf("r%d", i), uint32(rng.Intn(4)), true)
+	}
+	for _, input := range []string{"abc", "ab", "a", "bc", "c", "xabc", "x", "", "zzz"} {
+		want, got := linearScan(e.pairs, input), indexedScan(e.index, input)
+		if want != got {
+			t.Errorf("input %q: index chose %v, linear scan %v", input, pairID(got), pairID(want))
+		}
+	}
+}
+
+func pairID(p *BipartitePair) interface{} {
+	if p == nil {
+		return "no pair"
+	}
+	return p.TransformID
+}
+
+// TestPairIndexTies pins the rank the trie and floating lists must merge
+// to: lower priority numbers first, then the last registered pair
+func TestPairIndexTies(t *testing.T) {
+	type pair struct {
+		left     string
+		priority uint32
+	}
+	tests := []struct {
+		name  string
+		pairs []pair
+		input string
+		want  string // Output, the winning pair's right side
+	}{
+		{"lower priority wins over later", []pair{{"a", 1}, {"^ab", 2}}, "abc", "r0"},
+		{"lower priority wins over earlier", []pair{{"^ab", 2}, {"a", 1}}, "abc", "r1"},
+		{"later anchored beats floating", []pair{{"b", 3}, {"^ab", 3}}, "abc", "r1"},
+		{"later floating beats anchored", []pair{{"^ab", 3}, {"b", 3}}, "abc", "r1"},
+		{"later of two anchored", []pair{{"^a", 3}, {"^abc", 3}}, "abc", "r1"},
+		{"later of two anchored, shorter prefix", []pair{{"^abc", 3}, {"^a", 3}}, "abc", "r1"},
+		{"later of two floating", []pair{{"c", 3}, {"b", 3}}, "abc", "r1"},
+		{"last of three", []pair{{"b", 3}, {"^ab", 3}, {"c", 3}}, "abc", "r2"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := NewPatternEngine("classical")
+			for i, p := range tt.pairs {
+				e.AddPair(p.left, fmt.Sprintf("r%d", i), p.priority, true)
+			}
+			res := e.Match(tt.input)
+			if !res.Matched || res.Output != tt.want {
+				t.Errorf("Match(%q) = %q (matched %v), want %q", tt.input, res.Output, res.Matched, tt.want)
+			}
+		})
+	}
+}
+
+// BenchmarkPairLookup compares the index with the linear scan it replaced
+// on engines of growing size
+func BenchmarkPairLookup(b *testing.B) {
+	for _, n := range []int{100, 1000, 10000} {
+		e := statementEngine(b, n)
+		inputs := statementInputs(n)
+		for _, input := range inputs {
+			if linearScan(e.pairs, input) != indexedScan(e.index, input) {
+				b.Fatalf("n=%d input %q: index and linear scan disagree", n, input)
+			}
+		}
+		b.Run(fmt.Sprintf("linear/pairs=%d", n), func(b *testing.B) {
+			for i := 0; b.Loop(); i++ {
+				linearScan(e.pairs, inputs[i%len(inputs)])
+			}
+		})
+		b.Run(fmt.Sprintf("indexed/pairs=%d", n), func(b *testing.B) {
+			for i := 0; b.Loop(); i++ {
+				indexedScan(e.index, inputs[i%len(inputs)])
+			}
+		})
+	}
+}
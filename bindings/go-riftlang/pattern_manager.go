@@ -0,0 +1,243 @@
+// go/target/pattern_manager.go
+// Per-Tenant Pattern Engine Manager - Go Implementation
+
+package rift
+
+import (
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// ============================================================================
+// EngineManager
+// ============================================================================
+
+// EngineManagerOptions configures an EngineManager
+type EngineManagerOptions struct {
+	// Mode is the mode of every tenant's engine
+	Mode EngineMode
+
+	// Init prepares a tenant's new engine, typically by loading the
+	// tenant's pattern set; an error is returned by ForTenant and the
+	// engine is discarded, so the next ForTenant tries again. Nil leaves
+	// new engines empty.
+	Init func(tenant string, e *PatternEngine) error
+
+	// IdleTimeout evicts a tenant's engine once ForTenant has not
+	// returned it for this long. Zero never evicts.
+	IdleTimeout time.Duration
+
+	// OnEvict, if set, is called with each evicted engine, outside the
+	// manager's lock
+	OnEvict func(tenant string, e *PatternEngine)
+
+	// Clock times idleness and eviction, and is set on each tenant's
+	// engine (see PatternEngine.SetClock). Nil is the package clock.
+	Clock Clock
+}
+
+// EngineManager keeps an isolated pattern engine per tenant, created on
+// first use. Engines share compiled regexps (see ReadRegexCache), so
+// tenants loading the same patterns compile them once. An engine evicted
+// while a caller still holds it keeps working; the tenant's next
+// ForTenant builds a new one.
+type EngineManager struct {
+	opts    EngineManagerOptions
+	lock    sync.Mutex
+	tenants map[string]*tenantEngine
+
+	stop chan struct{}
+	done chan struct{}
+	once sync.Once
+}
+
+// tenantEngine is one tenant's engine and when it was last handed out
+type tenantEngine struct {
+	ready  chan struct{} // closed once Init has run
+	engine *PatternEngine
+	err    error
+	used   atomic.Int64 // UnixNano of the last ForTenant
+}
+
+// TenantStats is a tenant's engine statistics
+type TenantStats struct {
+	Tenant   string
+	LastUsed time.Time
+	Stats    EngineStats
+}
+
+// NewEngineManager returns a manager configured by opts. With an
+// IdleTimeout it evicts idle engines in the background until Stop.
+func NewEngineManager(opts EngineManagerOptions) *EngineManager {
+	m := &EngineManager{
+		opts:    opts,
+		tenants: make(map[string]*tenantEngine),
+		stop:    make(chan struct{}),
+		done:    make(chan struct{}),
+	}
+	if opts.IdleTimeout <= 0 {
+		close(m.done)
+		return m
+	}
+	ticker := m.clock().NewTicker(max(opts.IdleTimeout/2, time.Millisecond))
+	go func() {
+		defer close(m.done)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C():
+				m.EvictIdle()
+			case <-m.stop:
+				return
+			}
+		}
+	}()
+	return m
+}
+
+// ForTenant returns the tenant's engine, creating and initializing it on
+// first use. Concurrent first calls for a tenant share one Init.
+func (m *EngineManager) ForTenant(id string) (*PatternEngine, error) {
+	m.lock.Lock()
+	te, ok := m.tenants[id]
+	if !ok {
+		te = &tenantEngine{ready: make(chan struct{})}
+		m.tenants[id] = te
+	}
+	te.used.Store(m.clock().Now().UnixNano())
+	m.lock.Unlock()
+
+	if !ok {
+		m.initTenant(id, te)
+	}
+	<-te.ready
+	return te.engine, te.err
+}
+
+// initTenant builds a tenant's engine, forgetting the tenant on failure
+func (m *EngineManager) initTenant(id string, te *tenantEngine) {
+	defer close(te.ready)
+	e := NewPatternEngine(m.opts.Mode.String())
+	if m.opts.Clock != nil {
+		e.SetClock(m.opts.Clock)
+	}
+	if m.opts.Init != nil {
+		if err := m.opts.Init(id, e); err != nil {
+			te.err = err
+			m.lock.Lock()
+			if m.tenants[id] == te {
+				delete(m.tenants, id)
+			}
+			m.lock.Unlock()
+			return
+		}
+	}
+	te.engine = e
+}
+
+// clock returns the manager's clock
+func (m *EngineManager) clock() Clock {
+	if m.opts.Clock != nil {
+		return m.opts.Clock
+	}
+	return CurrentClock()
+}
+
+// Tenants returns the tenants with an engine, in sorted order
+func (m *EngineManager) Tenants() []string {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	ids := make([]string, 0, len(m.tenants))
+	for id := range m.tenants {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// Evict drops the tenant's engine, reporting whether it had one
+func (m *EngineManager) Evict(id string) bool {
+	m.lock.Lock()
+	te, ok := m.tenants[id]
+	delete(m.tenants, id)
+	m.lock.Unlock()
+	if ok {
+		m.evicted(id, te)
+	}
+	return ok
+}
+
+// EvictIdle drops the engines idle for longer than IdleTimeout, returning
+// how many it dropped; with no IdleTimeout it drops none
+func (m *EngineManager) EvictIdle() int {
+	if m.opts.IdleTimeout <= 0 {
+		return 0
+	}
+	cutoff := m.clock().Now().Add(-m.opts.IdleTimeout).UnixNano()
+	evicted := make(map[string]*tenantEngine)
+	m.lock.Lock()
+	for id, te := range m.tenants {
+		if te.used.Load() < cutoff {
+			evicted[id] = te
+			delete(m.tenants, id)
+		}
+	}
+	m.lock.Unlock()
+	for id, te := range evicted {
+		m.evicted(id, te)
+	}
+	return len(evicted)
+}
+
+// evicted reports an evicted engine to OnEvict once it is initialized
+func (m *EngineManager) evicted(id string, te *tenantEngine) {
+	if m.opts.OnEvict == nil {
+		return
+	}
+	<-te.ready
+	if te.engine != nil {
+		m.opts.OnEvict(id, te.engine)
+	}
+}
+
+// Stats returns the statistics of every tenant's engine, by tenant
+func (m *EngineManager) Stats() []TenantStats {
+	m.lock.Lock()
+	type entry struct {
+		id string
+		te *tenantEngine
+	}
+	entries := make([]entry, 0, len(m.tenants))
+	for id, te := range m.tenants {
+		entries = append(entries, entry{id, te})
+	}
+	m.lock.Unlock()
+
+	sort.Slice(entries, func(i, j int) bool { return entries[i].id < entries[j].id })
+	stats := make([]TenantStats, 0, len(entries))
+	for _, en := range entries {
+		select {
+		case <-en.te.ready:
+		default:
+			continue // still initializing
+		}
+		if en.te.engine == nil {
+			continue
+		}
+		stats = append(stats, TenantStats{
+			Tenant:   en.id,
+			LastUsed: time.Unix(0, en.te.used.Load()),
+			Stats:    en.te.engine.Stats(),
+		})
+	}
+	return stats
+}
+
+// Stop halts background eviction, waiting for a pass in progress to
+// finish. The manager's engines stay usable.
+func (m *EngineManager) Stop() {
+	m.once.Do(func() { close(m.stop) })
+	<-m.done
+}
@@ -0,0 +1,107 @@
+// go/target/pattern_mode.go
+// Pattern Engine Modes - Go Implementation
+
+package rift
+
+import "fmt"
+
+// EngineMode selects how Match, MatchContext and MatchAll report a match
+// and the lack of one. Select, Transform and Explain behave the same in
+// every mode.
+type EngineMode int
+
+const (
+	// ModeClassical returns the best match, or a result with Matched false
+	// when no pair matches. This is the default.
+	ModeClassical EngineMode = iota
+
+	// ModeQuantum is ModeClassical with every matching pair contributing:
+	// the result is superposed over the results of all the pairs matching
+	// the input, held in its States with their Amplitudes, for the caller
+	// to Collapse or Measure (see MatchResult.States)
+	ModeQuantum
+
+	// ModeStrict treats unmatched input as an error: MatchContext fails
+	// with CodeNoMatch, and Match and MatchAll return the unmatched result
+	// after counting the failure
+	ModeStrict
+
+	// ModePermissive passes unmatched input through: the result has
+	// Matched false and Output set to the input, so rewriting with Output
+	// leaves it unchanged
+	ModePermissive
+)
+
+// ErrNoMatch matches, via errors.Is, the error MatchContext returns for
+// unmatched input in ModeStrict
+var ErrNoMatch error = &GovernanceError{Code: CodeNoMatch, Op: "match", Detail: "no pair matches"}
+
+// String returns the mode name NewPatternEngine takes
+func (m EngineMode) String() string {
+	switch m {
+	case ModeClassical:
+		return "classical"
+	case ModeQuantum:
+		return "quantum"
+	case ModeStrict:
+		return "strict"
+	case ModePermissive:
+		return "permissive"
+	}
+	return fmt.Sprintf("EngineMode(%d)", int(m))
+}
+
+// EngineModeNamed returns the mode for a name: "classical" (or "" or
+// "classic", the policy default), "quantum", "strict" or "permissive"
+func EngineModeNamed(name string) (EngineMode, error) {
+	switch name {
+	case "", "classical", "classic":
+		return ModeClassical, nil
+	case "quantum":
+		return ModeQuantum, nil
+	case "strict":
+		return ModeStrict, nil
+	case "permissive":
+		return ModePermissive, nil
+	}
+	return 0, fmt.Errorf("unknown engine mode %q", name)
+}
+
+// SetMode sets the engine's mode, invalidating the match cache
+func (e *PatternEngine) SetMode(m EngineMode) {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	e.mode = m
+	if e.cache != nil {
+		e.cache.invalidate()
+	}
+}
+
+// Mode returns the engine's mode
+func (e *PatternEngine) Mode() EngineMode {
+	e.lock.RLock()
+	defer e.lock.RUnlock()
+	return e.mode
+}
+
+// ============================================================================
+// Mode Semantics
+// ============================================================================
+
+// applyMode adjusts a match result for the engine's mode; e.lock held
+func (e *PatternEngine) applyMode(input string, res *MatchResult) {
+	switch {
+	case e.mode == ModeQuantum && res.Matched:
+		res.States, res.Amplitudes = quantumStates(e.index.candidates(input), input, *res)
+	case e.mode == ModePermissive && !res.Matched:
+		res.Output = input
+	}
+}
+
+// modeError returns the error the engine's mode makes of res; e.lock held
+func (e *PatternEngine) modeError(input string, res *MatchResult) error {
+	if e.mode != ModeStrict || res.Matched {
+		return nil
+	}
+	return govErr(CodeNoMatch, "match", "no pair matches %.64q", input)
+}
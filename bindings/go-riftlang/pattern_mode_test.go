@@ -0,0 +1,132 @@
+package rift
+
+import (
+	"context"
+	"errors"
+	"math"
+	"testing"
+)
+
+// modeEngine returns an engine in mode with two pairs matching "let x"
+func modeEngine(t *testing.T, mode string) *PatternEngine {
+	t.Helper()
+	e := NewPatternEngine(mode)
+	if err := e.AddPairErr(`^let (\w+)`, "var $1", 1, false); err != nil {
+		t.Fatal(err)
+	}
+	if err := e.AddPairErr(`\w+`, "word", 2, true); err != nil {
+		t.Fatal(err)
+	}
+	return e
+}
+
+// TestEngineModes matches input some pair takes and input none does in
+// each mode, with and without the match cache
+func TestEngineModes(t *testing.T) {
+	tests := []struct {
+		mode       EngineMode
+		states     int    // len(States) of the match
+		missOutput string // Output of the miss
+		missErr    bool   // MatchContext fails on the miss
+	}{
+		{ModeClassical, 0, "", false},
+		{ModeQuantum, 2, "", false},
+		{ModeStrict, 0, "", true},
+		{ModePermissive, 0, "-- ", false},
+	}
+	for _, tt := range tests {
+		for _, cache := range []int{0, 16} {
+			name := tt.mode.String()
+			if cache > 0 {
+				name += "/cached"
+			}
+			t.Run(name, func(t *testing.T) {
+				e := modeEngine(t, tt.mode.String())
+				if e.Mode() != tt.mode {
+					t.Fatalf("Mode() = %v, want %v", e.Mode(), tt.mode)
+				}
+				if cache > 0 {
+					e.SetMatchCache(cache)
+				}
+
+				for range 2 { // the second round hits the cache
+					hit, err := e.MatchContext(context.Background(), "let x")
+					if err != nil {
+						t.Fatal(err)
+					}
+					if !hit.Matched || hit.Output != "var x" {
+						t.Errorf("match = %q (matched %v), want %q", hit.Output, hit.Matched, "var x")
+					}
+					if len(hit.States) != tt.states || len(hit.Amplitudes) != tt.states {
+						t.Errorf("match has %d states, %d amplitudes; want %d", len(hit.States), len(hit.Amplitudes), tt.states)
+					}
+
+					miss, err := e.MatchContext(context.Background(), "-- ")
+					switch {
+					case tt.missErr:
+						if !errors.Is(err, ErrNoMatch) || ErrorCodeOf(err) != CodeNoMatch || miss != nil {
+							t.Errorf("miss = %+v, %v; want %v", miss, err, ErrNoMatch)
+						}
+					case err != nil:
+						t.Errorf("miss error %v, want none", err)
+					case miss.Matched || miss.Output != tt.missOutput:
+						t.Errorf("miss = %+v, want unmatched with Output %q", miss, tt.missOutput)
+					}
+
+					// Match reports the miss only through the result
+					if res := e.Match("-- "); res == nil || res.Matched || res.Output != tt.missOutput {
+						t.Errorf("Match miss = %+v, want unmatched with Output %q", res, tt.missOutput)
+					}
+				}
+			})
+		}
+	}
+}
+
+// TestEngineModeQuantumStates checks a superposed match: the chosen result
+// comes first, every matching pair contributes and the amplitudes are
+// normalized
+func TestEngineModeQuantumStates(t *testing.T) {
+	e := modeEngine(t, "quantum")
+	res := e.Match("let x")
+	if !res.Superposed() {
+		t.Fatal("quantum match not superposed")
+	}
+	if res.States[0].Output != res.Output || res.States[1].Output != "word" {
+		t.Errorf("states %q, %q; want %q, %q", res.States[0].Output, res.States[1].Output, res.Output, "word")
+	}
+	sum := 0.0
+	for _, a := range res.Amplitudes {
+		sum += a * a
+	}
+	if math.Abs(sum-1) > 1e-9 {
+		t.Errorf("squared amplitudes sum to %v, want 1", sum)
+	}
+	got, err := res.Collapse(1)
+	if err != nil || got.Output != "word" {
+		t.Errorf("Collapse(1) = %q, %v; want %q", got.Output, err, "word")
+	}
+
+	e.SetMode(ModeClassical)
+	if res := e.Match("let x"); res.Superposed() {
+		t.Error("classical match superposed after SetMode")
+	}
+}
+
+func TestEngineModeNamed(t *testing.T) {
+	for name, want := range map[string]EngineMode{
+		"":           ModeClassical,
+		"classic":    ModeClassical,
+		"classical":  ModeClassical,
+		"quantum":    ModeQuantum,
+		"strict":     ModeStrict,
+		"permissive": ModePermissive,
+	} {
+		if got, err := EngineModeNamed(name); err != nil || got != want {
+			t.Errorf("EngineModeNamed(%q) = %v, %v; want %v", name, got, err, want)
+		}
+	}
+	if _, err := EngineModeNamed("fuzzy"); err == nil {
+		t.Error(`EngineModeNamed("fuzzy") succeeded`)
+	}
+}
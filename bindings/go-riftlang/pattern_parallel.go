@@ -0,0 +1,134 @@
+// go/target/pattern_parallel.go
+// Parallel Pattern Matching - Go Implementation
+
+package rift
+
+import (
+	"context"
+	"runtime"
+	"sync"
+	"sync/atomic"
+)
+
+// parallelMinCandidates is the fewest candidates worth fanning out; below
+// this the goroutine handoff costs more than the regex work
+const parallelMinCandidates = 64
+
+// ============================================================================
+// Worker Configuration
+// ============================================================================
+
+// SetWorkers sets how many goroutines Match uses to evaluate candidate pairs.
+// n <= 1 keeps matching sequential. The winning pair is the same either way.
+func (e *PatternEngine) SetWorkers(n int) {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	if n < 1 {
+		n = 1
+	}
+	e.workers = n
+}
+
+// Workers returns the configured worker count
+func (e *PatternEngine) Workers() int {
+	e.lock.RLock()
+	defer e.lock.RUnlock()
+	if e.workers < 1 {
+		return 1
+	}
+	return e.workers
+}
+
+// ============================================================================
+// Parallel Scan
+// ============================================================================
+
+// scanParallel is scanCandidates fanned across workers. Candidate indices
+// are handed out in rank order and a worker stops once its next index ranks
+// below the best match so far, so every candidate ranked above the final
+// winner has been tried and the result matches the sequential scan.
+func scanParallel(done <-chan struct{}, candidates []*indexedPair, input string, workers int) (int, []int, error) {
+	var next atomic.Int64
+	var best atomic.Int64
+	var canceled atomic.Bool
+	best.Store(int64(len(candidates)))
+	results := make([][]int, len(candidates))
+
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for n := 0; ; n++ {
+				i := next.Add(1) - 1
+				if i >= best.Load() || canceled.Load() {
+					return
+				}
+				if done != nil && n%matchCheckInterval == 0 {
+					select {
+					case <-done:
+						canceled.Store(true)
+						return
+					default:
+					}
+				}
+				if loc := candidates[i].match(input); loc != nil {
+					results[i] = loc
+					for {
+						cur := best.Load()
+						if i >= cur || best.CompareAndSwap(cur, i) {
+							break
+						}
+					}
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	if canceled.Load() {
+		return -1, nil, context.Canceled
+	}
+	if i := best.Load(); i < int64(len(candidates)) {
+		return int(i), results[i], nil
+	}
+	return -1, nil, nil
+}
+
+// ============================================================================
+// Batch Matching
+// ============================================================================
+
+// MatchAll matches every input, spreading inputs across the engine's workers
+// (GOMAXPROCS when unset). Results are in input order and equal to calling
+// Match on each input.
+func (e *PatternEngine) MatchAll(inputs []string) []*MatchResult {
+	workers := e.Workers()
+	if workers <= 1 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	if workers > len(inputs) {
+		workers = len(inputs)
+	}
+
+	results := make([]*MatchResult, len(inputs))
+	var next atomic.Int64
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				i := next.Add(1) - 1
+				if i >= int64(len(inputs)) {
+					return
+				}
+				// Inputs are already spread across workers, so each match
+				// scans its candidates sequentially
+				results[i], _ = e.match(context.Background(), inputs[i], false)
+			}
+		}()
+	}
+	wg.Wait()
+	return results
+}
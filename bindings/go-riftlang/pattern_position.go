@@ -0,0 +1,87 @@
+// go/target/pattern_position.go
+// Match Positions - Go Implementation
+
+package rift
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// ============================================================================
+// Positions
+// ============================================================================
+
+// Position is a location in a multi-line input
+type Position struct {
+	Offset int // byte offset
+	Line   int // 1-based
+	Column int // 1-based, counted in runes from the start of the line
+}
+
+// String formats the position as line:column
+func (p Position) String() string {
+	return fmt.Sprintf("%d:%d", p.Line, p.Column)
+}
+
+// PositionAt returns the position of byte offset off in input. Lines end at
+// '\n', so a "\r\n" line ending counts its '\r' as the last column of the
+// line. Offsets outside input are clamped to it.
+func PositionAt(input string, off int) Position {
+	off = max(0, min(off, len(input)))
+	before := input[:off]
+	lineStart := strings.LastIndexByte(before, '\n') + 1
+	return Position{
+		Offset: off,
+		Line:   strings.Count(before, "\n") + 1,
+		Column: utf8.RuneCountInString(before[lineStart:]) + 1,
+	}
+}
+
+// Positions returns the line and column positions of the match's Start and
+// End in input, the string that was matched. End is the position just past
+// the match. For several results against one long input, a LineIndex
+// avoids rescanning it for each.
+func (r MatchResult) Positions(input string) (start, end Position) {
+	return PositionAt(input, r.Start), PositionAt(input, r.End)
+}
+
+// ============================================================================
+// Line Index
+// ============================================================================
+
+// LineIndex maps byte offsets of one input to positions in O(log lines)
+type LineIndex struct {
+	input  string
+	starts []int // byte offset of each line's first byte
+}
+
+// NewLineIndex indexes the line starts of input
+func NewLineIndex(input string) *LineIndex {
+	starts := []int{0}
+	for i := 0; i < len(input); i++ {
+		if input[i] == '\n' {
+			starts = append(starts, i+1)
+		}
+	}
+	return &LineIndex{input: input, starts: starts}
+}
+
+// Position returns the position of byte offset off, as PositionAt does
+func (x *LineIndex) Position(off int) Position {
+	off = max(0, min(off, len(x.input)))
+	// The line is the last one starting at or before off
+	line := sort.Search(len(x.starts), func(i int) bool { return x.starts[i] > off }) - 1
+	return Position{
+		Offset: off,
+		Line:   line + 1,
+		Column: utf8.RuneCountInString(x.input[x.starts[line]:off]) + 1,
+	}
+}
+
+// Positions returns the positions of r's Start and End
+func (x *LineIndex) Positions(r MatchResult) (start, end Position) {
+	return x.Position(r.Start), x.Position(r.End)
+}
@@ -0,0 +1,123 @@
+// go/target/pattern_quantum.go
+// Superposed Match Results - Go Implementation
+
+package rift
+
+import (
+	"math"
+	"math/rand"
+)
+
+// ============================================================================
+// Superposed Results
+// ============================================================================
+
+// quantumStates returns the result of every candidate matching input and
+// their amplitudes. chosen, the result the engine's selection picked,
+// comes first and the rest follow in rank order. A pair's probability is
+// proportional to its weight (see matchWeight).
+func quantumStates(candidates []*indexedPair, input string, chosen MatchResult) ([]MatchResult, []float64) {
+	states := []MatchResult{chosen}
+	weights := []float64{0}
+	for _, ip := range candidates {
+		if ip.pair.TransformID == chosen.TransformID {
+			weights[0] = matchWeight(ip)
+			continue
+		}
+		loc := ip.matchLoc(input)
+		if loc == nil {
+			continue
+		}
+		res := locResult(ip, input, loc)
+		res.Version = chosen.Version
+		states = append(states, res)
+		weights = append(weights, matchWeight(ip))
+	}
+
+	total := 0.0
+	for _, w := range weights {
+		total += w
+	}
+	amps := make([]float64, len(weights))
+	for i, w := range weights {
+		amps[i] = math.Sqrt(w / total)
+	}
+	return states, amps
+}
+
+// matchWeight is the unnormalized probability of a matching pair in a
+// superposed result: (1 + specificity) / (1 + priority), so higher ranked
+// (lower priority number) and more literal patterns weigh more
+func matchWeight(ip *indexedPair) float64 {
+	return float64(1+ip.specificity) / (1 + float64(ip.pair.Left.Priority))
+}
+
+// Superposed reports whether the result holds superposed states to
+// Collapse or Measure
+func (r MatchResult) Superposed() bool {
+	return len(r.States) > 0
+}
+
+// Collapse selects state index of a superposed result, returning that
+// pair's result. State 0 is the result Match returns outside ModeQuantum.
+func (r MatchResult) Collapse(index uint32) (MatchResult, error) {
+	if !r.Superposed() {
+		return MatchResult{}, govErr(CodeNotSuperposed, "collapse match", "match result not superposed")
+	}
+	if int(index) >= len(r.States) {
+		return MatchResult{}, govErr(CodeIndexOutOfRange, "collapse match", "state %d of %d", index, len(r.States))
+	}
+	return r.States[index], nil
+}
+
+// Measure collapses a superposed result to a state chosen at random with
+// probability |amplitude|², using the package RNG (see SetRandSource)
+func (r MatchResult) Measure() (MatchResult, error) {
+	return r.MeasureWith(nil)
+}
+
+// MeasureWith is Measure drawing from rng, or the package RNG when rng is
+// nil
+func (r MatchResult) MeasureWith(rng *rand.Rand) (MatchResult, error) {
+	if !r.Superposed() {
+		return MatchResult{}, govErr(CodeNotSuperposed, "measure match", "match result not superposed")
+	}
+	x := randFloat64(rng) * AmplitudeNorm(r.Amplitudes)
+	selected := len(r.States) - 1
+	for i, a := range r.Amplitudes {
+		if x < a*a {
+			selected = i
+			break
+		}
+		x -= a * a
+	}
+	return r.States[selected], nil
+}
+
+// OutputToken returns the result's output as a token: for a superposed
+// result, a superposed token with a string state holding each state's
+// Output under its amplitude, so the token can be measured in its place;
+// otherwise a governed string token holding Output. It fails for a result
+// that did not match.
+func (r MatchResult) OutputToken() (*RiftToken, error) {
+	if !r.Matched {
+		return nil, govErr(CodeNotInitialized, "output token", "match result did not match")
+	}
+	if !r.Superposed() {
+		return Var("output", r.Output), nil
+	}
+	states := make([]*RiftToken, len(r.States))
+	for i, s := range r.States {
+		states[i] = newStateToken(s.Output)
+	}
+	memory := NewRiftMemorySpan(SpanSuperposed, 64)
+	memory.Alignment = QuantumAlignment
+	token := NewRiftToken(TokenQGoInt, memory)
+	if err := token.SuperposeErr(states, r.Amplitudes); err != nil {
+		return nil, err
+	}
+	if err := token.ValidateErr(); err != nil {
+		return nil, err
+	}
+	return token, nil
+}
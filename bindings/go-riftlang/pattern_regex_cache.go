@@ -0,0 +1,105 @@
+// go/target/pattern_regex_cache.go
+// Shared Compiled Pattern Cache - Go Implementation
+
+package rift
+
+import (
+	"regexp"
+	"runtime"
+	"sync"
+	"sync/atomic"
+)
+
+// ============================================================================
+// Regex Cache
+// ============================================================================
+
+// regexCache shares compiled regexps between every pattern, in every
+// engine, compiled from the same string. An entry lives while patterns
+// using it do: each RiftPattern holds a reference, dropped when the
+// pattern is garbage collected.
+var regexCache = struct {
+	lock    sync.Mutex
+	entries map[string]*regexEntry
+	hits    atomic.Uint64
+	misses  atomic.Uint64
+}{entries: make(map[string]*regexEntry)}
+
+// regexEntry is a compiled regexp and the number of patterns using it
+type regexEntry struct {
+	re   *regexp.Regexp
+	refs int
+}
+
+// RegexCacheStats describes the shared regexp cache
+type RegexCacheStats struct {
+	Patterns int    // distinct pattern strings compiled and in use
+	Refs     int    // patterns using them, across all engines
+	Hits     uint64 // compilations the cache saved
+	Misses   uint64 // compilations it ran
+}
+
+// ReadRegexCache returns the state of the cache that shares compiled
+// regexps between pairs with the same left or right pattern, within an
+// engine or across engines. A regexp is compiled once however many
+// engines load the pattern, and dropped once the last pattern using it is
+// garbage collected.
+func ReadRegexCache() RegexCacheStats {
+	regexCache.lock.Lock()
+	defer regexCache.lock.Unlock()
+	st := RegexCacheStats{
+		Patterns: len(regexCache.entries),
+		Hits:     regexCache.hits.Load(),
+		Misses:   regexCache.misses.Load(),
+	}
+	for _, e := range regexCache.entries {
+		st.Refs += e.refs
+	}
+	return st
+}
+
+// compileShared returns the compiled regexp for expr, shared with every
+// other pattern compiled from it, and counts a reference held by owner
+// until owner is collected. Failures are not cached.
+func compileShared(owner *RiftPattern, expr string) (*regexp.Regexp, error) {
+	regexCache.lock.Lock()
+	if e, ok := regexCache.entries[expr]; ok {
+		e.refs++
+		regexCache.lock.Unlock()
+		regexCache.hits.Add(1)
+		runtime.AddCleanup(owner, releaseShared, expr)
+		return e.re, nil
+	}
+	regexCache.lock.Unlock()
+
+	// Compile unlocked so engines loading different patterns do not wait
+	// on each other; a pattern compiled twice concurrently keeps the
+	// first result
+	re, err := regexp.Compile(expr)
+	if err != nil {
+		return nil, err
+	}
+	regexCache.misses.Add(1)
+
+	regexCache.lock.Lock()
+	e, ok := regexCache.entries[expr]
+	if !ok {
+		e = &regexEntry{re: re}
+		regexCache.entries[expr] = e
+	}
+	e.refs++
+	regexCache.lock.Unlock()
+	runtime.AddCleanup(owner, releaseShared, expr)
+	return e.re, nil
+}
+
+// releaseShared drops a collected pattern's reference to expr's regexp
+func releaseShared(expr string) {
+	regexCache.lock.Lock()
+	defer regexCache.lock.Unlock()
+	if e, ok := regexCache.entries[expr]; ok {
+		if e.refs--; e.refs <= 0 {
+			delete(regexCache.entries, expr)
+		}
+	}
+}
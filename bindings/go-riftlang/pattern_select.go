@@ -0,0 +1,294 @@
+// go/target/pattern_select.go
+// Match Selection Strategies - Go Implementation
+
+package rift
+
+import (
+	"context"
+	"fmt"
+	"sort"
+)
+
+// Selection chooses which match Match returns when several pairs match
+type Selection int
+
+const (
+	// SelectPriority returns the highest-ranked matching pair: lowest
+	// priority number, then the engine's TieBreak. This is the default.
+	SelectPriority Selection = iota
+
+	// SelectLongest returns the pair whose leftmost match is longest,
+	// falling back to rank among equally long matches
+	SelectLongest
+
+	// SelectLeftmost returns the pair whose match starts earliest in the
+	// input, falling back to rank among matches at the same position
+	SelectLeftmost
+
+	// SelectAllNonOverlapping splits the input the way a lexer does: from
+	// the start of the input, the earliest match wins, the longest among
+	// those starting at the same position, then rank; scanning resumes
+	// after it. Select returns every match chosen; Match returns the first.
+	SelectAllNonOverlapping
+)
+
+// String returns the strategy name
+func (s Selection) String() string {
+	switch s {
+	case SelectPriority:
+		return "priority"
+	case SelectLongest:
+		return "longest"
+	case SelectLeftmost:
+		return "leftmost"
+	case SelectAllNonOverlapping:
+		return "all-non-overlapping"
+	}
+	return fmt.Sprintf("Selection(%d)", int(s))
+}
+
+// SetSelection sets the engine's match selection strategy
+func (e *PatternEngine) SetSelection(s Selection) {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	e.selection = s
+	if e.cache != nil {
+		e.cache.invalidate()
+	}
+}
+
+// Selection returns the engine's match selection strategy
+func (e *PatternEngine) Selection() Selection {
+	e.lock.RLock()
+	defer e.lock.RUnlock()
+	return e.selection
+}
+
+// ============================================================================
+// Select
+// ============================================================================
+
+// Select returns the matches the engine's strategy chooses for input, in
+// input order, with Start and End set: one match under SelectPriority,
+// SelectLongest and SelectLeftmost, every token under
+// SelectAllNonOverlapping, and none if nothing matches. Zero-width matches
+// are skipped under SelectAllNonOverlapping.
+func (e *PatternEngine) Select(input string) []MatchResult {
+	results, _ := e.selectMatches(context.Background(), input)
+	return results
+}
+
+// SelectContext is Select honoring ctx, like MatchContext
+func (e *PatternEngine) SelectContext(ctx context.Context, input string) ([]MatchResult, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, contextError("select", CodeCanceled, err)
+	}
+	results, err := e.selectMatches(ctx, input)
+	if err != nil {
+		return nil, contextError("select", CodeCanceled, ctx.Err())
+	}
+	return results, nil
+}
+
+// selectMatches implements Select
+func (e *PatternEngine) selectMatches(ctx context.Context, input string) ([]MatchResult, error) {
+	startTime := e.now()
+	e.lock.RLock()
+	defer e.lock.RUnlock()
+
+	results, err := e.selectLocked(ctx.Done(), input)
+	if err != nil {
+		return nil, err
+	}
+	e.updateMetrics(e.since(startTime), len(results) > 0)
+	return results, nil
+}
+
+// selectLocked returns the matches the engine's strategy chooses; e.lock
+// held
+func (e *PatternEngine) selectLocked(done <-chan struct{}, input string) ([]MatchResult, error) {
+	candidates := e.index.candidates(input)
+	if e.selection == SelectAllNonOverlapping {
+		results, err := lexMatches(done, candidates, input)
+		for i := range results {
+			results[i].Version = e.version
+		}
+		return results, err
+	}
+
+	// Under SelectPriority rank decides, so only TieLongestMatch needs to
+	// look past the first match, and longestAmongTies handles it
+	locs, err := candidateLocs(done, candidates, input, e.selection == SelectPriority)
+	if err != nil {
+		return nil, err
+	}
+	best := -1
+	if e.selection == SelectPriority {
+		for i, loc := range locs {
+			if loc != nil {
+				best = i
+				break
+			}
+		}
+		if best >= 0 && e.index.tie == TieLongestMatch {
+			var loc []int
+			best, loc = longestAmongTies(candidates, best, input)
+			locs[best] = loc
+		}
+	} else {
+		best = selectBest(e.selection, locs)
+	}
+	if best < 0 {
+		return nil, nil
+	}
+	res := locResult(candidates[best], input, locs[best])
+	res.Version = e.version
+	return []MatchResult{res}, nil
+}
+
+// ============================================================================
+// Strategies
+// ============================================================================
+
+// candidateLocs returns the leftmost match of each candidate, nil where it
+// does not match. With first set it stops after the first match.
+func candidateLocs(done <-chan struct{}, candidates []*indexedPair, input string, first bool) ([][]int, error) {
+	locs := make([][]int, len(candidates))
+	for i, ip := range candidates {
+		if done != nil && i%matchCheckInterval == 0 {
+			select {
+			case <-done:
+				return nil, context.Canceled
+			default:
+			}
+		}
+		if locs[i] = ip.matchLoc(input); locs[i] != nil && first {
+			break
+		}
+	}
+	return locs, nil
+}
+
+// selectBest returns the index of the match strategy prefers among locs
+// (indexed in rank order), or -1 if none matched. For
+// SelectAllNonOverlapping that is the pair of the first token.
+func selectBest(strategy Selection, locs [][]int) int {
+	best := -1
+	for i, loc := range locs {
+		if loc == nil || strategy == SelectAllNonOverlapping && matchLen(loc) == 0 {
+			continue
+		}
+		if best < 0 || selectsOver(strategy, loc, locs[best]) {
+			best = i
+		}
+	}
+	return best
+}
+
+// selectsOver reports whether a match at loc beats one at other that ranks
+// ahead of it
+func selectsOver(strategy Selection, loc, other []int) bool {
+	switch strategy {
+	case SelectLongest:
+		return matchLen(loc) > matchLen(other)
+	case SelectLeftmost:
+		return loc[0] < other[0]
+	case SelectAllNonOverlapping:
+		return loc[0] < other[0] || loc[0] == other[0] && matchLen(loc) > matchLen(other)
+	}
+	return false
+}
+
+// lexMatches splits input into non-overlapping matches, earliest first,
+// longest among those starting together, then by rank
+func lexMatches(done <-chan struct{}, candidates []*indexedPair, input string) ([]MatchResult, error) {
+	type found struct {
+		rank int
+		loc  []int
+	}
+	var all []found
+	for i, ip := range candidates {
+		if done != nil && i%matchCheckInterval == 0 {
+			select {
+			case <-done:
+				return nil, context.Canceled
+			default:
+			}
+		}
+		re := ip.pair.Left.CompiledRegex
+		if re == nil || !ip.mayMatch(input) {
+			continue
+		}
+		for _, loc := range re.FindAllStringSubmatchIndex(input, -1) {
+			if loc[0] != loc[1] {
+				all = append(all, found{i, loc})
+			}
+		}
+	}
+	sort.SliceStable(all, func(i, j int) bool {
+		a, b := all[i], all[j]
+		if a.loc[0] != b.loc[0] {
+			return a.loc[0] < b.loc[0]
+		}
+		if matchLen(a.loc) != matchLen(b.loc) {
+			return matchLen(a.loc) > matchLen(b.loc)
+		}
+		return a.rank < b.rank
+	})
+
+	var results []MatchResult
+	end := 0
+	for _, f := range all {
+		if f.loc[0] < end {
+			continue
+		}
+		results = append(results, locResult(candidates[f.rank], input, f.loc))
+		end = f.loc[1]
+	}
+	return results, nil
+}
+
+// locResult builds the MatchResult of a match at loc
+func locResult(ip *indexedPair, input string, loc []int) MatchResult {
+	res := ip.pair.result(submatchStrings(input, loc))
+	res.Start, res.End = loc[0], loc[1]
+	res.Text = input[loc[0]:loc[1]]
+	return res
+}
+
+// selectReason says why winner beat a loser under a strategy other than
+// SelectPriority, or "" if the strategy did not decide between them
+func selectReason(strategy Selection, winnerLoc, loserLoc []int) string {
+	longer := fmt.Sprintf("longer match (%d bytes vs %d)", matchLen(winnerLoc), matchLen(loserLoc))
+	earlier := fmt.Sprintf("earlier match (byte %d vs %d)", winnerLoc[0], loserLoc[0])
+	switch strategy {
+	case SelectLongest:
+		if matchLen(winnerLoc) > matchLen(loserLoc) {
+			return longer
+		}
+	case SelectLeftmost:
+		if winnerLoc[0] < loserLoc[0] {
+			return earlier
+		}
+	case SelectAllNonOverlapping:
+		if winnerLoc[0] < loserLoc[0] {
+			return earlier
+		}
+		if winnerLoc[0] == loserLoc[0] && matchLen(winnerLoc) > matchLen(loserLoc) {
+			return longer
+		}
+	}
+	return ""
+}
+
+// selectWinReason describes the winner of a strategy other than
+// SelectPriority
+func selectWinReason(strategy Selection, loc []int) string {
+	switch strategy {
+	case SelectLongest:
+		return fmt.Sprintf("longest match (%d bytes)", matchLen(loc))
+	case SelectLeftmost:
+		return fmt.Sprintf("leftmost match (byte %d)", loc[0])
+	}
+	return fmt.Sprintf("first token (bytes %d-%d)", loc[0], loc[1])
+}
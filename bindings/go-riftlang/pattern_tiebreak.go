@@ -0,0 +1,152 @@
+// go/target/pattern_tiebreak.go
+// Equal-Priority Tie-Breaking - Go Implementation
+
+package rift
+
+import (
+	"fmt"
+	"regexp/syntax"
+)
+
+// TieBreak selects which of several matching pairs with the same priority
+// wins. Priority always decides first; a TieBreak only orders pairs of
+// equal priority.
+type TieBreak int
+
+const (
+	// TieLastRegistered prefers the most recently registered pair, so a
+	// later AddPair overrides an earlier one of equal priority. This is the
+	// default, and the behaviour of engines before tie-break modes.
+	TieLastRegistered TieBreak = iota
+
+	// TieFirstRegistered prefers the earliest registered pair, so later
+	// pairs of equal priority act only as fallbacks
+	TieFirstRegistered
+
+	// TieLongestMatch prefers the pair whose leftmost match in the input is
+	// longest, then the earliest registered. Every matching pair of the
+	// winning priority is evaluated, so it costs more than the static modes.
+	TieLongestMatch
+
+	// TieMostSpecific prefers the pair whose left pattern has the most
+	// literal characters (anchors count as one each), then the earliest
+	// registered, so `^func main\(` beats `^func \w+\(`
+	TieMostSpecific
+)
+
+// String returns the mode name
+func (tb TieBreak) String() string {
+	switch tb {
+	case TieLastRegistered:
+		return "last-registered"
+	case TieFirstRegistered:
+		return "first-registered"
+	case TieLongestMatch:
+		return "longest-match"
+	case TieMostSpecific:
+		return "most-specific"
+	}
+	return fmt.Sprintf("TieBreak(%d)", int(tb))
+}
+
+// SetTieBreak sets how the engine orders matching pairs of equal priority,
+// for Match, MatchAll, Transform and Explain alike
+func (e *PatternEngine) SetTieBreak(tb TieBreak) {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	e.index.setTieBreak(tb)
+	if e.cache != nil {
+		e.cache.invalidate()
+	}
+}
+
+// TieBreak returns the engine's tie-break mode
+func (e *PatternEngine) TieBreak() TieBreak {
+	e.lock.RLock()
+	defer e.lock.RUnlock()
+	return e.index.tie
+}
+
+// ============================================================================
+// Longest Match
+// ============================================================================
+
+// longestAmongTies returns, of candidates[best] and the candidates after it
+// with the same priority, the one with the longest match in input along
+// with its submatch index pairs. Earlier candidates win ties.
+func longestAmongTies(candidates []*indexedPair, best int, input string) (int, []int) {
+	priority := candidates[best].pair.Left.Priority
+	bestLoc := candidates[best].matchLoc(input)
+	for i := best + 1; i < len(candidates) && candidates[i].pair.Left.Priority == priority; i++ {
+		if loc := candidates[i].matchLoc(input); loc != nil && matchLen(loc) > matchLen(bestLoc) {
+			best, bestLoc = i, loc
+		}
+	}
+	return best, bestLoc
+}
+
+// matchLoc runs the prefilter and then the left regex, returning the
+// submatch index pairs of the leftmost match or nil
+func (ip *indexedPair) matchLoc(input string) []int {
+	re := ip.pair.Left.CompiledRegex
+	if re == nil || !ip.mayMatch(input) {
+		return nil
+	}
+	return re.FindStringSubmatchIndex(input)
+}
+
+// ============================================================================
+// Specificity
+// ============================================================================
+
+// specificity counts the literal characters and anchors of pattern
+func specificity(pattern string) int {
+	re, err := syntax.Parse(pattern, syntax.Perl)
+	if err != nil {
+		return 0
+	}
+	var count func(re *syntax.Regexp) int
+	count = func(re *syntax.Regexp) int {
+		switch re.Op {
+		case syntax.OpLiteral:
+			return len(re.Rune)
+		case syntax.OpBeginLine, syntax.OpEndLine, syntax.OpBeginText, syntax.OpEndText:
+			return 1
+		case syntax.OpConcat, syntax.OpCapture:
+			n := 0
+			for _, sub := range re.Sub {
+				n += count(sub)
+			}
+			return n
+		}
+		// Alternations and repetitions guarantee no particular literal
+		return 0
+	}
+	return count(re)
+}
+
+// ============================================================================
+// Explanation
+// ============================================================================
+
+// tieReason says why winner beat loser, both matching with equal priority;
+// lengths are the byte lengths of their matches
+func (x *pairIndex) tieReason(winner, loser *indexedPair, winnerLen, loserLen int) string {
+	priority := loser.pair.Left.Priority
+	switch x.tie {
+	case TieFirstRegistered:
+		return fmt.Sprintf("same priority %d, registered earlier", priority)
+	case TieLongestMatch:
+		if winnerLen > loserLen {
+			return fmt.Sprintf("same priority %d, longer match (%d bytes vs %d)", priority, winnerLen, loserLen)
+		}
+		return fmt.Sprintf("same priority %d, equally long match registered earlier", priority)
+	case TieMostSpecific:
+		if winner.specificity > loser.specificity {
+			return fmt.Sprintf("same priority %d, more specific (%d literal characters vs %d)",
+				priority, winner.specificity, loser.specificity)
+		}
+		return fmt.Sprintf("same priority %d, equally specific and registered earlier", priority)
+	}
+	return fmt.Sprintf("same priority %d, registered more recently", priority)
+}
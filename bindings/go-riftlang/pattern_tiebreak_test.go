@@ -0,0 +1,76 @@
+package rift
+
+import (
+	"fmt"
+	"testing"
+)
+
+// TestTieBreakModes registers pairs of equal priority and checks which one
+// each tie-break mode picks; priority still decides first in every mode
+func TestTieBreakModes(t *testing.T) {
+	type pair struct {
+		left     string
+		priority uint32
+	}
+	tests := []struct {
+		name  string
+		tie   TieBreak
+		pairs []pair
+		input string
+		want  string // Output, the winning pair's right side
+	}{
+		{"default prefers last", TieLastRegistered, []pair{{"a", 3}, {"^ab", 3}, {"abc", 3}}, "abc", "r2"},
+		{"default, last is floating", TieLastRegistered, []pair{{"^ab", 3}, {"c", 3}}, "abc", "r1"},
+		{"default, priority first", TieLastRegistered, []pair{{"a", 1}, {"abc", 3}}, "abc", "r0"},
+
+		{"first-registered prefers first", TieFirstRegistered, []pair{{"a", 3}, {"^ab", 3}, {"abc", 3}}, "abc", "r0"},
+		{"first-registered, first is floating", TieFirstRegistered, []pair{{"c", 3}, {"^ab", 3}}, "abc", "r0"},
+		{"first-registered, priority first", TieFirstRegistered, []pair{{"a", 3}, {"abc", 1}}, "abc", "r1"},
+
+		{"longest-match prefers longest", TieLongestMatch, []pair{{"^a", 3}, {"bc", 3}, {"abc", 3}, {"^ab", 3}}, "abcd", "r2"},
+		{"longest-match, equal length first", TieLongestMatch, []pair{{"b", 3}, {"c", 3}}, "abc", "r0"},
+		{"longest-match, priority first", TieLongestMatch, []pair{{"abcd", 3}, {"a", 1}}, "abcd", "r1"},
+
+		{"most-specific prefers literals", TieMostSpecific, []pair{{`^func main\(`, 3}, {`^func \w+\(`, 3}}, "func main(", "r0"},
+		{"most-specific, registered either way", TieMostSpecific, []pair{{`^func \w+\(`, 3}, {`^func main\(`, 3}}, "func main(", "r1"},
+		{"most-specific, equal first", TieMostSpecific, []pair{{"b", 3}, {"c", 3}}, "abc", "r0"},
+		{"most-specific, priority first", TieMostSpecific, []pair{{`^func main\(`, 3}, {`\w+`, 1}}, "func main(", "r1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := NewPatternEngine("classical")
+			if tt.tie != TieLastRegistered {
+				e.SetTieBreak(tt.tie)
+			}
+			if e.TieBreak() != tt.tie {
+				t.Fatalf("TieBreak() = %v, want %v", e.TieBreak(), tt.tie)
+			}
+			for i, p := range tt.pairs {
+				if err := e.AddPairErr(p.left, fmt.Sprintf("r%d", i), p.priority, true); err != nil {
+					t.Fatal(err)
+				}
+			}
+			res := e.Match(tt.input)
+			if !res.Matched || res.Output != tt.want {
+				t.Errorf("Match(%q) = %q (matched %v), want %q", tt.input, res.Output, res.Matched, tt.want)
+			}
+			if all := e.MatchAll([]string{tt.input}); all[0].Output != res.Output {
+				t.Errorf("MatchAll(%q) = %q, Match %q", tt.input, all[0].Output, res.Output)
+			}
+		})
+	}
+}
+
+func TestTieBreakString(t *testing.T) {
+	for tb, want := range map[TieBreak]string{
+		TieLastRegistered:  "last-registered",
+		TieFirstRegistered: "first-registered",
+		TieLongestMatch:    "longest-match",
+		TieMostSpecific:    "most-specific",
+		TieBreak(9):        "TieBreak(9)",
+	} {
+		if got := tb.String(); got != want {
+			t.Errorf("TieBreak(%d).String() = %q, want %q", int(tb), got, want)
+		}
+	}
+}
@@ -0,0 +1,93 @@
+// go/target/pattern_transform.go
+// Replace-All Transformation - Go Implementation
+
+package rift
+
+import (
+	"sort"
+	"strings"
+)
+
+// ============================================================================
+// Transform
+// ============================================================================
+
+// Transform rewrites every match in input, not just the best one. All pairs
+// are matched across the whole input; matches are then taken in rank order
+// (the order Match uses), skipping any that overlap a match already taken,
+// and each is replaced by its pair's output. Under TieLongestMatch, longer
+// matches are taken before shorter ones of equal priority. The results
+// describe the replacements in input order. Zero-width matches are ignored.
+func (e *PatternEngine) Transform(input string) (string, []MatchResult) {
+	startTime := e.now()
+
+	e.lock.RLock()
+	defer e.lock.RUnlock()
+
+	// found holds every non-empty match in rank order
+	type found struct {
+		ip  *indexedPair
+		loc []int
+	}
+	var all []found
+	for _, ip := range e.index.candidates(input) {
+		re := ip.pair.Left.CompiledRegex
+		if re == nil || !ip.mayMatch(input) {
+			continue
+		}
+		for _, loc := range re.FindAllStringSubmatchIndex(input, -1) {
+			if loc[0] != loc[1] {
+				all = append(all, found{ip, loc})
+			}
+		}
+	}
+	if e.index.tie == TieLongestMatch {
+		sort.SliceStable(all, func(i, j int) bool {
+			pi, pj := all[i].ip.pair.Left.Priority, all[j].ip.pair.Left.Priority
+			if pi != pj {
+				return pi < pj
+			}
+			return all[i].loc[1]-all[i].loc[0] > all[j].loc[1]-all[j].loc[0]
+		})
+	}
+
+	// taken holds accepted matches sorted by start
+	var taken []MatchResult
+	for _, f := range all {
+		start, end := f.loc[0], f.loc[1]
+		pos := sort.Search(len(taken), func(i int) bool { return taken[i].Start >= start })
+		if pos > 0 && taken[pos-1].End > start || pos < len(taken) && taken[pos].Start < end {
+			continue // overlaps a higher-ranked match
+		}
+
+		res := locResult(f.ip, input, f.loc)
+		res.Version = e.version
+		taken = append(taken, MatchResult{})
+		copy(taken[pos+1:], taken[pos:])
+		taken[pos] = res
+	}
+
+	var out strings.Builder
+	last := 0
+	for _, res := range taken {
+		out.WriteString(input[last:res.Start])
+		out.WriteString(res.Output)
+		last = res.End
+	}
+	out.WriteString(input[last:])
+
+	e.updateMetrics(e.since(startTime), len(taken) > 0)
+	return out.String(), taken
+}
+
+// submatchStrings converts FindStringSubmatchIndex output to the strings
+// FindStringSubmatch would return
+func submatchStrings(input string, loc []int) []string {
+	s := make([]string, len(loc)/2)
+	for i := range s {
+		if loc[2*i] >= 0 {
+			s[i] = input[loc[2*i]:loc[2*i+1]]
+		}
+	}
+	return s
+}
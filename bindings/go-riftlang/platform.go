@@ -0,0 +1,152 @@
+// go/target/platform.go
+// Platform Topology and Cache-Line Alignment - Go Implementation
+
+package rift
+
+import (
+	"runtime"
+	"sync"
+)
+
+// CPUTopology is the cache-line size and NUMA layout of the machine,
+// detected once at first use
+type CPUTopology struct {
+	CacheLine uint32     // bytes per cache line
+	Nodes     []NUMANode // one node holding every CPU where NUMA is unknown
+}
+
+// NUMANode is a NUMA node and the CPUs it holds
+type NUMANode struct {
+	ID   int
+	CPUs []int
+}
+
+// SpanAffinity is the CPU, and the NUMA node holding it, that a span is
+// meant to be used from
+type SpanAffinity struct {
+	CPU  int `json:"cpu"`
+	Node int `json:"node"` // -1 if the CPU is not in the topology
+}
+
+// topology is detected on first use
+var topology = sync.OnceValue(func() CPUTopology {
+	topo := detectTopology()
+	if topo.CacheLine == 0 || topo.CacheLine&(topo.CacheLine-1) != 0 {
+		topo.CacheLine = archCacheLine()
+	}
+	if len(topo.Nodes) == 0 {
+		cpus := make([]int, runtime.NumCPU())
+		for i := range cpus {
+			cpus[i] = i
+		}
+		topo.Nodes = []NUMANode{{ID: 0, CPUs: cpus}}
+	}
+	return topo
+})
+
+// ============================================================================
+// Detection
+// ============================================================================
+
+// Topology returns the machine's CPU topology. Callers must not modify it.
+func Topology() CPUTopology {
+	return topology()
+}
+
+// CacheLineSize returns the cache-line size in bytes, the default
+// alignment of SpanDistributed spans
+func CacheLineSize() uint32 {
+	return topology().CacheLine
+}
+
+// NodeOfCPU returns the NUMA node holding cpu, or -1 if there is none
+func NodeOfCPU(cpu int) int {
+	for _, n := range topology().Nodes {
+		for _, c := range n.CPUs {
+			if c == cpu {
+				return n.ID
+			}
+		}
+	}
+	return -1
+}
+
+// archCacheLine is the usual cache-line size of the target architecture,
+// for platforms that do not report it
+func archCacheLine() uint32 {
+	switch runtime.GOARCH {
+	case "arm64":
+		if runtime.GOOS == "darwin" || runtime.GOOS == "ios" {
+			return 128
+		}
+	case "ppc64", "ppc64le":
+		return 128
+	case "s390x":
+		return 256
+	}
+	return 64
+}
+
+// ============================================================================
+// CPU-Local Spans
+// ============================================================================
+
+// NewRiftMemorySpanForCPU creates a span like NewRiftMemorySpan with an
+// affinity hint for cpu and its NUMA node. Distributed spans are aligned to
+// the detected cache-line size either way. The hint is advisory: Go does
+// not pin goroutines or memory.
+func NewRiftMemorySpanForCPU(cpu int, spanType int, bytes uint64) *RiftMemorySpan {
+	span := NewRiftMemorySpan(spanType, bytes)
+	span.affinity = &SpanAffinity{CPU: cpu, Node: NodeOfCPU(cpu)}
+	return span
+}
+
+// Affinity returns the span's CPU affinity hint, and false if it has none
+func (s *RiftMemorySpan) Affinity() (SpanAffinity, bool) {
+	if s.affinity == nil {
+		return SpanAffinity{}, false
+	}
+	return *s.affinity, true
+}
+
+// ============================================================================
+// Cache-Line Validation
+// ============================================================================
+
+// cacheLineError reports a distributed span that could share a cache line
+// with another span: it must be aligned to at least a cache line, and a span
+// carved from parent must start on a cache-line boundary and end on one or
+// at its parent's end. Other span types pass. spanTree held.
+func cacheLineError(op string, spanType int, alignment uint32, parent *RiftMemorySpan, offset, bytes uint64) *GovernanceError {
+	if spanType != SpanDistributed {
+		return nil
+	}
+	line := uint64(CacheLineSize())
+	if uint64(alignment)%line != 0 {
+		return govErr(CodeBadAlignment, op, "distributed alignment %d is not a multiple of the %d-byte cache line", alignment, line)
+	}
+	if parent == nil {
+		return nil
+	}
+	end := offset + bytes
+	if offset%line != 0 || end%line != 0 && end != parent.Bytes {
+		return govErr(CodeBadAlignment, op, "distributed span [%d, %d) straddles a %d-byte cache line", offset, end, line)
+	}
+	return nil
+}
+
+// ValidateCacheLines checks that a distributed span cannot share a cache
+// line with another span; other span types pass
+func (s *RiftMemorySpan) ValidateCacheLines() error {
+	if err := s.cacheLineError("validate"); err != nil {
+		return err
+	}
+	return nil
+}
+
+// cacheLineError is ValidateCacheLines returning a GovernanceError
+func (s *RiftMemorySpan) cacheLineError(op string) *GovernanceError {
+	spanTree.Lock()
+	defer spanTree.Unlock()
+	return cacheLineError(op, s.Type, s.Alignment, s.parent, s.offset, s.Bytes)
+}
@@ -0,0 +1,67 @@
+// go/target/platform_linux.go
+// Platform Topology Detection (Linux) - Go Implementation
+
+package rift
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// detectTopology reads the cache-line size and NUMA nodes from sysfs,
+// leaving fields it cannot read zero
+func detectTopology() CPUTopology {
+	var topo CPUTopology
+	if data, err := os.ReadFile("/sys/devices/system/cpu/cpu0/cache/index0/coherency_line_size"); err == nil {
+		if n, err := strconv.ParseUint(strings.TrimSpace(string(data)), 10, 32); err == nil {
+			topo.CacheLine = uint32(n)
+		}
+	}
+
+	dirs, _ := filepath.Glob("/sys/devices/system/node/node[0-9]*")
+	for _, dir := range dirs {
+		id, err := strconv.Atoi(strings.TrimPrefix(filepath.Base(dir), "node"))
+		if err != nil {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(dir, "cpulist"))
+		if err != nil {
+			continue
+		}
+		cpus, err := parseCPUList(strings.TrimSpace(string(data)))
+		if err != nil {
+			continue
+		}
+		topo.Nodes = append(topo.Nodes, NUMANode{ID: id, CPUs: cpus})
+	}
+	sort.Slice(topo.Nodes, func(i, j int) bool { return topo.Nodes[i].ID < topo.Nodes[j].ID })
+	return topo
+}
+
+// parseCPUList parses a sysfs CPU list such as "0-3,8,10-11"
+func parseCPUList(s string) ([]int, error) {
+	var cpus []int
+	if s == "" {
+		return cpus, nil
+	}
+	for _, part := range strings.Split(s, ",") {
+		lo, hi, isRange := strings.Cut(part, "-")
+		first, err := strconv.Atoi(lo)
+		if err != nil {
+			return nil, err
+		}
+		last := first
+		if isRange {
+			if last, err = strconv.Atoi(hi); err != nil {
+				return nil, err
+			}
+		}
+		for c := first; c <= last; c++ {
+			cpus = append(cpus, c)
+		}
+	}
+	return cpus, nil
+}
@@ -0,0 +1,12 @@
+// go/target/platform_other.go
+// Platform Topology Detection (Fallback) - Go Implementation
+
+//go:build !linux
+
+package rift
+
+// detectTopology reports nothing, leaving the architecture's usual
+// cache-line size and a single NUMA node
+func detectTopology() CPUTopology {
+	return CPUTopology{}
+}
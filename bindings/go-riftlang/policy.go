@@ -0,0 +1,706 @@
+// go/target/policy.go
+// .rift Governance Policy Loader - Go Implementation
+
+package rift
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ============================================================================
+// Policy Structures
+// ============================================================================
+
+// PolicyValue is a parsed value from a .rift block body: a scalar, a list
+// or a nested map
+type PolicyValue struct {
+	Scalar string
+	List   []string
+	Map    map[string]*PolicyValue
+}
+
+// PolicyBlock is a single top-level .rift declaration
+// (e.g. `!govern classic { ... }`, `align span<fixed> { ... }`)
+type PolicyBlock struct {
+	Kind   string
+	Args   []string
+	Fields map[string]*PolicyValue
+	Line   int
+}
+
+// SpanDefault holds the defaults declared by an `align span<name>` block
+type SpanDefault struct {
+	Name      string
+	Type      int
+	Bytes     uint64
+	Direction bool // true = right->left
+	Open      bool
+}
+
+// PolicyPattern is a `pattern "left" -> "right"` declaration
+type PolicyPattern struct {
+	Left     string
+	Right    string
+	Priority uint32
+	Governed bool
+}
+
+// Policy is a loaded .rift governance policy that spans and tokens can be
+// validated against
+type Policy struct {
+	Source string
+	Mode   string
+
+	// token_memory rules
+	AlignmentKind string // "fixed" or "dynamic"
+	Alignment     uint32
+	AccessMask    uint32
+
+	// Thresholds
+	ValidationThreshold  float64
+	EntropyThreshold     float64
+	DecoherenceThreshold float64 // 0 = never force collapse
+
+	// MaxLockHold is the longest a governed Mutex or RWMutex should be held
+	// (see SetMaxLockHold); 0 = no limit
+	MaxLockHold time.Duration
+
+	// MaxConcurrency bounds how many tasks a Pool built from the policy runs
+	// at once; 0 = no policy limit
+	MaxConcurrency int
+
+	// OnViolation names the handler for governance violations: "error",
+	// "log" or "panic" (see ViolationHandlerNamed); "" = not set
+	OnViolation string
+
+	// Amplitudes names the amplitude mode for superpositions: "accept",
+	// "strict" or "normalize" (see AmplitudeModeNamed); "" = not set
+	Amplitudes string
+
+	Spans    map[string]*SpanDefault
+	Types    map[string]map[string]*PolicyValue
+	Roles    map[string]uint32
+	Patterns []PolicyPattern
+	Blocks   []*PolicyBlock
+
+	lock *sync.RWMutex // set by Watch
+}
+
+// ============================================================================
+// Loading
+// ============================================================================
+
+// LoadPolicy reads and parses a .rift governance file
+func LoadPolicy(path string) (*Policy, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read policy: %w", err)
+	}
+	policy, err := ParsePolicy(string(data))
+	if err != nil {
+		return nil, fmt.Errorf("policy %s: %w", path, err)
+	}
+	policy.Source = path
+	return policy, nil
+}
+
+// ParsePolicy parses .rift governance source text
+func ParsePolicy(src string) (*Policy, error) {
+	blocks, err := ParsePolicyBlocks(src)
+	if err != nil {
+		return nil, err
+	}
+
+	policy := &Policy{
+		Mode:                "classic",
+		AlignmentKind:       "fixed",
+		Alignment:           ClassicalAlignment,
+		AccessMask:          AccessCRUD,
+		ValidationThreshold: DefaultThreshold,
+		EntropyThreshold:    DefaultEntropy,
+		Spans:               make(map[string]*SpanDefault),
+		Types:               make(map[string]map[string]*PolicyValue),
+		Roles:               make(map[string]uint32),
+		Blocks:              blocks,
+	}
+
+	for _, block := range blocks {
+		if err := policy.apply(block); err != nil {
+			return nil, fmt.Errorf("line %d: %w", block.Line, err)
+		}
+	}
+	return policy, nil
+}
+
+// ParsePolicyBlocks parses .rift source into its blocks without applying
+// them, for tools inspecting policies that ParsePolicy would reject. Only
+// syntax errors fail.
+func ParsePolicyBlocks(src string) ([]*PolicyBlock, error) {
+	p := &policyParser{src: src, line: 1}
+	return p.parseBlocks()
+}
+
+// apply folds a parsed block into the policy
+func (p *Policy) apply(b *PolicyBlock) error {
+	switch b.Kind {
+	case "govern":
+		if len(b.Args) > 0 {
+			p.Mode = b.Args[0]
+		}
+		if mem := b.Fields["token_memory"]; mem != nil && mem.Map != nil {
+			if align := mem.Map["alignment"]; align != nil {
+				kind, n, err := parseAlignment(align.Scalar)
+				if err != nil {
+					return err
+				}
+				p.AlignmentKind = kind
+				p.Alignment = n
+			}
+			if access := mem.Map["access"]; access != nil {
+				mask, err := parseAccessList(access.List)
+				if err != nil {
+					return err
+				}
+				p.AccessMask = mask
+			}
+		}
+		if val := b.Fields["token_value"]; val != nil && val.Map != nil {
+			if v := val.Map["validation"]; v != nil && strings.HasPrefix(v.Scalar, "entropy_threshold") {
+				_, f, err := parseCall(v.Scalar)
+				if err != nil {
+					return err
+				}
+				p.EntropyThreshold = f
+			}
+		}
+		if v := b.Fields["threshold"]; v != nil {
+			f, err := strconv.ParseFloat(v.Scalar, 64)
+			if err != nil {
+				return fmt.Errorf("invalid threshold %q", v.Scalar)
+			}
+			p.ValidationThreshold = f
+		}
+		if v := b.Fields["max_lock_hold"]; v != nil {
+			d, err := time.ParseDuration(v.Scalar)
+			if err != nil || d < 0 {
+				return fmt.Errorf("invalid max_lock_hold %q", v.Scalar)
+			}
+			p.MaxLockHold = d
+		}
+		if v := b.Fields["max_concurrency"]; v != nil {
+			n, err := strconv.Atoi(v.Scalar)
+			if err != nil || n < 0 {
+				return fmt.Errorf("invalid max_concurrency %q", v.Scalar)
+			}
+			p.MaxConcurrency = n
+		}
+		if v := b.Fields["on_violation"]; v != nil {
+			if _, err := ViolationHandlerNamed(v.Scalar); err != nil {
+				return err
+			}
+			p.OnViolation = v.Scalar
+		}
+		if v := b.Fields["amplitudes"]; v != nil {
+			if _, err := AmplitudeModeNamed(v.Scalar); err != nil {
+				return err
+			}
+			p.Amplitudes = v.Scalar
+		}
+
+	case "align":
+		if len(b.Args) == 0 || !strings.HasPrefix(b.Args[0], "span<") {
+			return fmt.Errorf("align block requires span<name>")
+		}
+		name := strings.TrimSuffix(strings.TrimPrefix(b.Args[0], "span<"), ">")
+		span := &SpanDefault{Name: name, Type: spanTypeByName(name), Direction: true, Open: true}
+		if v := b.Fields["type"]; v != nil {
+			span.Type = spanTypeByName(v.Scalar)
+			if span.Type < 0 {
+				return fmt.Errorf("unknown span type %q", v.Scalar)
+			}
+		}
+		if span.Type < 0 {
+			span.Type = SpanFixed
+		}
+		if v := b.Fields["bytes"]; v != nil {
+			n, err := strconv.ParseUint(v.Scalar, 10, 64)
+			if err != nil {
+				return fmt.Errorf("invalid span bytes %q", v.Scalar)
+			}
+			span.Bytes = n
+		}
+		if v := b.Fields["direction"]; v != nil {
+			span.Direction = strings.ReplaceAll(v.Scalar, " ", "") != "left->right"
+		}
+		if v := b.Fields["open"]; v != nil {
+			span.Open = v.Scalar == "true"
+		}
+		p.Spans[name] = span
+
+	case "role":
+		if len(b.Args) == 0 {
+			return fmt.Errorf("role block requires a name")
+		}
+		var mask uint32
+		if v := b.Fields["permissions"]; v != nil {
+			m, err := parseAccessList(v.List)
+			if err != nil {
+				return err
+			}
+			mask = m
+		}
+		p.Roles[b.Args[0]] = mask
+
+	case "type":
+		if len(b.Args) == 0 {
+			return fmt.Errorf("type block requires a name")
+		}
+		p.Types[b.Args[0]] = b.Fields
+
+	case "pattern":
+		if len(b.Args) < 3 || b.Args[1] != "->" {
+			return fmt.Errorf("pattern block requires \"left\" -> \"right\"")
+		}
+		pat := PolicyPattern{Left: b.Args[0], Right: b.Args[2]}
+		if v := b.Fields["priority"]; v != nil {
+			n, err := strconv.ParseUint(v.Scalar, 10, 32)
+			if err != nil {
+				return fmt.Errorf("invalid pattern priority %q", v.Scalar)
+			}
+			pat.Priority = uint32(n)
+		}
+		if v := b.Fields["governed"]; v != nil {
+			pat.Governed = v.Scalar == "true"
+		}
+		p.Patterns = append(p.Patterns, pat)
+
+	case "entanglement_registry":
+		if v := b.Fields["entropy_threshold"]; v != nil {
+			f, err := strconv.ParseFloat(v.Scalar, 64)
+			if err != nil {
+				return fmt.Errorf("invalid entropy_threshold %q", v.Scalar)
+			}
+			p.EntropyThreshold = f
+		}
+		if v := b.Fields["decoherence_threshold"]; v != nil {
+			f, err := strconv.ParseFloat(v.Scalar, 64)
+			if err != nil {
+				return fmt.Errorf("invalid decoherence_threshold %q", v.Scalar)
+			}
+			p.DecoherenceThreshold = f
+		}
+	}
+	// Unknown block kinds (policy_fn, collapse_trigger, ...) are kept in
+	// Blocks for callers that need them
+	return nil
+}
+
+// ============================================================================
+// Validation
+// ============================================================================
+
+// ValidateSpan checks a memory span against the policy's alignment and
+// access rules
+func (p *Policy) ValidateSpan(s *RiftMemorySpan) error {
+	p = p.Current()
+	if s == nil {
+		return fmt.Errorf("memory span is nil")
+	}
+	if !s.ValidateAlignment() {
+		return fmt.Errorf("span alignment %d is not a power of 2", s.Alignment)
+	}
+	switch p.AlignmentKind {
+	case "fixed":
+		if s.Alignment != p.Alignment {
+			return fmt.Errorf("span alignment %d violates fixed(%d)", s.Alignment, p.Alignment)
+		}
+	case "dynamic":
+		if s.Alignment < p.Alignment {
+			return fmt.Errorf("span alignment %d below dynamic(%d)", s.Alignment, p.Alignment)
+		}
+	}
+	if mask := s.Mask(); mask&^p.AccessMask != 0 {
+		return fmt.Errorf("span access mask 0x%02x exceeds policy mask 0x%02x", mask, p.AccessMask)
+	}
+	return nil
+}
+
+// ValidateToken checks a token and its memory span against the policy
+func (p *Policy) ValidateToken(t *RiftToken) error {
+	p = p.Current()
+	if !t.HasBit(TokenAllocated) {
+		return fmt.Errorf("token not allocated")
+	}
+	if err := p.ValidateSpan(t.Memory); err != nil {
+		return err
+	}
+	if fields, ok := p.Types[TokenTypeName(t.Type)]; ok {
+		if mem := fields["memory"]; mem != nil {
+			kind, n, err := parseCall(mem.Scalar)
+			if err == nil && kind == "aligned" && n > 0 && t.Memory.Alignment%uint32(n) != 0 {
+				return fmt.Errorf("token alignment %d violates aligned(%d)", t.Memory.Alignment, uint32(n))
+			}
+		}
+	}
+	return nil
+}
+
+// SpanFor creates a memory span from a declared `align span<name>` default
+func (p *Policy) SpanFor(name string) (*RiftMemorySpan, error) {
+	p = p.Current()
+	def, ok := p.Spans[name]
+	if !ok {
+		return nil, fmt.Errorf("policy declares no span<%s>", name)
+	}
+	span := NewRiftMemorySpan(def.Type, def.Bytes)
+	span.Direction = def.Direction
+	span.Open = def.Open
+	span.AccessMask &= p.AccessMask
+	return span, nil
+}
+
+// ============================================================================
+// Helpers
+// ============================================================================
+
+// TokenTypeName returns the .rift type name for a token type constant
+func TokenTypeName(tokenType int) string {
+	switch tokenType {
+	case TokenGoInt:
+		return "GoInt"
+	case TokenGoFloat:
+		return "GoFloat"
+	case TokenGoString:
+		return "GoString"
+	case TokenGoSlice:
+		return "GoSlice"
+	case TokenGoMap:
+		return "GoMap"
+	case TokenGoChan:
+		return "GoChan"
+	case TokenQGoInt:
+		return "QGoInt"
+	case TokenQGoChan:
+		return "QGoChan"
+	case TokenGoPtr:
+		return "GoPtr"
+	}
+	return "Unknown"
+}
+
+// spanTypeByName maps a .rift span name to a span type, or -1
+func spanTypeByName(name string) int {
+	switch name {
+	case "fixed":
+		return SpanFixed
+	case "row", "expandable":
+		return SpanRow
+	case "continuous":
+		return SpanContinuous
+	case "superposed":
+		return SpanSuperposed
+	case "entangled":
+		return SpanEntangled
+	case "distributed":
+		return SpanDistributed
+	}
+	return -1
+}
+
+// parseAccessList converts [CREATE, READ, ...] into an access mask
+func parseAccessList(items []string) (uint32, error) {
+	var mask uint32
+	for _, item := range items {
+		switch strings.ToUpper(item) {
+		case "CREATE":
+			mask |= AccessCreate
+		case "READ":
+			mask |= AccessRead
+		case "UPDATE", "WRITE":
+			mask |= AccessUpdate
+		case "DELETE":
+			mask |= AccessDelete
+		case "SUPERPOSE":
+			mask |= AccessSuperpose
+		case "ENTANGLE":
+			mask |= AccessEntangle
+		default:
+			return 0, fmt.Errorf("unknown access right %q", item)
+		}
+	}
+	return mask, nil
+}
+
+// parseCall splits a value like fixed(4096) into its name and argument
+func parseCall(s string) (string, float64, error) {
+	open := strings.IndexByte(s, '(')
+	if open < 0 || !strings.HasSuffix(s, ")") {
+		return "", 0, fmt.Errorf("expected name(value), got %q", s)
+	}
+	n, err := strconv.ParseFloat(strings.TrimSpace(s[open+1:len(s)-1]), 64)
+	if err != nil {
+		return "", 0, fmt.Errorf("invalid argument in %q", s)
+	}
+	return strings.TrimSpace(s[:open]), n, nil
+}
+
+// parseAlignment parses an alignment kind(n), where n must be a power of
+// two no larger than a span alignment can hold
+func parseAlignment(s string) (string, uint32, error) {
+	kind, n, err := parseCall(s)
+	if err != nil {
+		return "", 0, err
+	}
+	if n != math.Trunc(n) || n < 1 || n > 1<<31 {
+		return "", 0, fmt.Errorf("alignment %q is not a whole number of bytes from 1 to %d", s, 1<<31)
+	}
+	if a := uint32(n); a&(a-1) != 0 {
+		return "", 0, fmt.Errorf("alignment %q is not a power of 2", s)
+	}
+	return kind, uint32(n), nil
+}
+
+// ============================================================================
+// Parser
+// ============================================================================
+
+// policyParser is a small recursive-descent parser for .rift block syntax
+type policyParser struct {
+	src  string
+	pos  int
+	line int
+}
+
+func (p *policyParser) errorf(format string, args ...interface{}) error {
+	return fmt.Errorf("line %d: %s", p.line, fmt.Sprintf(format, args...))
+}
+
+func (p *policyParser) peek() byte {
+	if p.pos < len(p.src) {
+		return p.src[p.pos]
+	}
+	return 0
+}
+
+func (p *policyParser) next() byte {
+	c := p.src[p.pos]
+	p.pos++
+	if c == '\n' {
+		p.line++
+	}
+	return c
+}
+
+// skipSpace skips whitespace, // line comments and /* block comments */
+func (p *policyParser) skipSpace() {
+	for p.pos < len(p.src) {
+		c := p.src[p.pos]
+		switch {
+		case c == ' ' || c == '\t' || c == '\r' || c == '\n':
+			p.next()
+		case strings.HasPrefix(p.src[p.pos:], "//"):
+			for p.pos < len(p.src) && p.src[p.pos] != '\n' {
+				p.pos++
+			}
+		case strings.HasPrefix(p.src[p.pos:], "/*"):
+			p.pos += 2
+			for p.pos < len(p.src) && !strings.HasPrefix(p.src[p.pos:], "*/") {
+				p.next()
+			}
+			p.pos += 2
+			if p.pos > len(p.src) {
+				p.pos = len(p.src)
+			}
+		default:
+			return
+		}
+	}
+}
+
+func (p *policyParser) parseBlocks() ([]*PolicyBlock, error) {
+	var blocks []*PolicyBlock
+	for {
+		p.skipSpace()
+		if p.pos >= len(p.src) {
+			return blocks, nil
+		}
+		block, err := p.parseBlock()
+		if err != nil {
+			return nil, err
+		}
+		blocks = append(blocks, block)
+	}
+}
+
+// parseBlock parses `kind args... { fields }`
+func (p *policyParser) parseBlock() (*PolicyBlock, error) {
+	block := &PolicyBlock{Line: p.line}
+	var words []string
+	header := func() {
+		block.Kind = strings.TrimPrefix(words[0], "!")
+		for _, w := range words[1:] {
+			if w != "=" && w != "on" {
+				block.Args = append(block.Args, w)
+			}
+		}
+	}
+	for {
+		p.skipSpace()
+		c := p.peek()
+
+		// Bodyless directives such as `!govern classic` end at the line
+		if len(words) > 0 && len(words[0]) > 0 && words[0][0] == '!' && c != '{' && (c == 0 || p.line > block.Line) {
+			header()
+			return block, nil
+		}
+
+		switch c {
+		case 0:
+			return nil, p.errorf("unexpected end of input in block header")
+		case '{':
+			if len(words) == 0 {
+				return nil, p.errorf("block without a header")
+			}
+			header()
+			fields, err := p.parseMap()
+			if err != nil {
+				return nil, err
+			}
+			block.Fields = fields
+			return block, nil
+		case '"':
+			s, err := p.parseString()
+			if err != nil {
+				return nil, err
+			}
+			if len(words) == 0 && s == "" {
+				return nil, p.errorf("empty block kind")
+			}
+			words = append(words, s)
+		default:
+			start := p.pos
+			for p.pos < len(p.src) && !strings.ContainsRune(" \t\r\n{\"", rune(p.src[p.pos])) {
+				p.pos++
+			}
+			words = append(words, p.src[start:p.pos])
+		}
+	}
+}
+
+// parseString parses a double-quoted string, unescaping \" and \\
+func (p *policyParser) parseString() (string, error) {
+	p.next() // opening quote
+	var sb strings.Builder
+	for p.pos < len(p.src) {
+		c := p.next()
+		switch c {
+		case '"':
+			return sb.String(), nil
+		case '\\':
+			if p.pos < len(p.src) {
+				n := p.next()
+				if n != '"' && n != '\\' {
+					sb.WriteByte('\\')
+				}
+				sb.WriteByte(n)
+			}
+		default:
+			sb.WriteByte(c)
+		}
+	}
+	return "", p.errorf("unterminated string")
+}
+
+// parseMap parses `{ key: value, ... }`
+func (p *policyParser) parseMap() (map[string]*PolicyValue, error) {
+	p.next() // '{'
+	fields := make(map[string]*PolicyValue)
+	for {
+		p.skipSpace()
+		switch p.peek() {
+		case 0:
+			return nil, p.errorf("unexpected end of input in block body")
+		case '}':
+			p.next()
+			return fields, nil
+		case ',':
+			p.next()
+			continue
+		}
+
+		start := p.pos
+		for p.pos < len(p.src) && p.src[p.pos] != ':' && p.src[p.pos] != '\n' && p.src[p.pos] != '}' {
+			p.pos++
+		}
+		if p.peek() != ':' {
+			return nil, p.errorf("expected ':' after field %q", strings.TrimSpace(p.src[start:p.pos]))
+		}
+		key := strings.TrimSpace(p.src[start:p.pos])
+		p.next()
+
+		value, err := p.parseValue()
+		if err != nil {
+			return nil, err
+		}
+		fields[key] = value
+	}
+}
+
+// parseValue parses a nested map, a [list] or a raw scalar
+func (p *policyParser) parseValue() (*PolicyValue, error) {
+	p.skipSpace()
+	switch p.peek() {
+	case '{':
+		m, err := p.parseMap()
+		if err != nil {
+			return nil, err
+		}
+		return &PolicyValue{Map: m}, nil
+	case '[':
+		p.next()
+		start := p.pos
+		for p.pos < len(p.src) && p.src[p.pos] != ']' {
+			p.next()
+		}
+		if p.pos >= len(p.src) {
+			return nil, p.errorf("unterminated list")
+		}
+		raw := p.src[start:p.pos]
+		p.next()
+		var items []string
+		for _, item := range strings.Split(raw, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				items = append(items, item)
+			}
+		}
+		return &PolicyValue{List: items}, nil
+	case '"':
+		s, err := p.parseString()
+		if err != nil {
+			return nil, err
+		}
+		return &PolicyValue{Scalar: s}, nil
+	}
+
+	// Raw scalar runs to the next top-level ',', newline or '}'
+	start := p.pos
+	depth := 0
+	for p.pos < len(p.src) {
+		c := p.src[p.pos]
+		if c == '(' {
+			depth++
+		} else if c == ')' {
+			depth--
+		} else if depth == 0 && (c == ',' || c == '\n' || c == '}') {
+			break
+		}
+		p.pos++
+	}
+	return &PolicyValue{Scalar: strings.TrimSpace(p.src[start:p.pos])}, nil
+}
@@ -0,0 +1,86 @@
+package rift
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParsePolicyMalformedHeaders(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		want string
+	}{
+		{"empty quoted kind", `"" x {}`, "line 1: empty block kind"},
+		{"empty quoted kind alone", `""`, "line 1: empty block kind"},
+		{"empty kind on later line", "align span<fixed> {}\n\"\" {}", "line 2: empty block kind"},
+		{"body without header", `{ a: 1 }`, "line 1: block without a header"},
+		{"header without body", `role Writer`, "line 1: unexpected end of input in block header"},
+		{"unterminated kind", `"govern`, "line 1: unterminated string"},
+		{"unterminated body", "role R {\n  permissions: [READ]\n", "line 3: unexpected end of input in block body"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParsePolicy(tt.src)
+			if err == nil {
+				t.Fatalf("ParsePolicy(%q) succeeded, want error %q", tt.src, tt.want)
+			}
+			if !strings.HasPrefix(err.Error(), tt.want) {
+				t.Errorf("ParsePolicy(%q) = %q, want %q", tt.src, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestParsePolicyDirectives(t *testing.T) {
+	p, err := ParsePolicy("!govern quantum\nalign span<fixed> { bytes: 64 }\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.Mode != "quantum" {
+		t.Errorf("Mode = %q, want quantum", p.Mode)
+	}
+	if s := p.Spans["fixed"]; s == nil || s.Bytes != 64 {
+		t.Errorf("Spans[fixed] = %+v, want 64 bytes", s)
+	}
+}
+
+func TestParsePolicyAlignment(t *testing.T) {
+	policy := func(align string) string {
+		return "govern classic {\n  token_memory: {\n    alignment: " + align + "\n  }\n}\n"
+	}
+	tests := []struct {
+		align string
+		want  string // error prefix, "" for success
+	}{
+		{"fixed(4096)", ""},
+		{"dynamic(8)", ""},
+		{"fixed(1)", ""},
+		{"fixed(2147483648)", ""},
+		{"fixed(-8)", `line 1: alignment "fixed(-8)" is not a whole number`},
+		{"fixed(0)", `line 1: alignment "fixed(0)" is not a whole number`},
+		{"fixed(8.5)", `line 1: alignment "fixed(8.5)" is not a whole number`},
+		{"fixed(4294967296)", `line 1: alignment "fixed(4294967296)" is not a whole number`},
+		{"fixed(1e300)", `line 1: alignment "fixed(1e300)" is not a whole number`},
+		{"fixed(NaN)", `line 1: alignment "fixed(NaN)" is not a whole number`},
+		{"fixed(24)", `line 1: alignment "fixed(24)" is not a power of 2`},
+		{"dynamic(12)", `line 1: alignment "dynamic(12)" is not a power of 2`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.align, func(t *testing.T) {
+			p, err := ParsePolicy(policy(tt.align))
+			switch {
+			case tt.want == "" && err != nil:
+				t.Fatal(err)
+			case tt.want == "":
+				if _, n, _ := parseCall(tt.align); p.Alignment != uint32(n) {
+					t.Errorf("Alignment = %d, want %g", p.Alignment, n)
+				}
+			case err == nil:
+				t.Errorf("ParsePolicy succeeded with Alignment %d, want error %q", p.Alignment, tt.want)
+			case !strings.HasPrefix(err.Error(), tt.want):
+				t.Errorf("ParsePolicy = %q, want %q", err, tt.want)
+			}
+		})
+	}
+}
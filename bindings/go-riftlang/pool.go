@@ -0,0 +1,272 @@
+// go/target/pool.go
+// Governed Worker Pool - Go Implementation
+
+package rift
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// ErrPoolClosed matches, via errors.Is, the error Submit returns after Close
+var ErrPoolClosed error = &GovernanceError{Code: CodePoolClosed, Op: "submit", Detail: "pool closed"}
+
+// PoolOptions configures a Pool
+type PoolOptions struct {
+	// Workers bounds how many tasks run at once. The default is the
+	// policy's MaxConcurrency, or GOMAXPROCS without one.
+	Workers int
+
+	// QueueSize is how many submitted tasks may wait for a worker before
+	// Submit blocks; 0 = Workers
+	QueueSize int
+
+	// Policy supplies Workers when it is unset
+	Policy *Policy
+
+	// OnPanic is called with each recovered task panic. The default prints
+	// it like Go does.
+	OnPanic func(r interface{})
+}
+
+// TaskObserver is called after each task with how long it waited for a
+// worker, how long it ran, and its error
+type TaskObserver func(wait, run time.Duration, err error)
+
+// PoolStats is a typed snapshot of pool metrics
+type PoolStats struct {
+	Workers          int
+	QueueDepth       int // tasks waiting for a worker
+	Running          int
+	Completed        uint64 // tasks that returned nil
+	Failed           uint64 // tasks that returned an error, panics included
+	Panicked         uint64
+	AverageWaitMs    float64
+	AverageLatencyMs float64 // run time, excluding the wait
+}
+
+// ============================================================================
+// Tasks
+// ============================================================================
+
+// PoolTask is a submitted task. Its token is governed, and locked by the
+// task, while the task runs.
+type PoolTask struct {
+	ctx    context.Context
+	fn     func(context.Context) error
+	token  *RiftToken
+	queued time.Time
+	done   chan struct{}
+	err    error
+}
+
+// Token returns the task's token, which the task's context carries (see
+// TokenFromContext)
+func (t *PoolTask) Token() *RiftToken {
+	return t.token
+}
+
+// Done is closed when the task has finished
+func (t *PoolTask) Done() <-chan struct{} {
+	return t.done
+}
+
+// Wait blocks until the task has finished and returns its error. A panic
+// is reported as a CodePanic GovernanceError.
+func (t *PoolTask) Wait() error {
+	<-t.done
+	return t.err
+}
+
+// ============================================================================
+// Pool
+// ============================================================================
+
+// Pool runs submitted tasks on a bounded set of worker goroutines, each
+// task inside its own governed token context
+type Pool struct {
+	opts  PoolOptions
+	tasks chan *PoolTask
+	wg    sync.WaitGroup
+
+	lock   sync.RWMutex // guards closed against sends on tasks
+	closed bool
+
+	running   atomic.Int64
+	completed atomic.Uint64
+	failed    atomic.Uint64
+	panicked  atomic.Uint64
+
+	metricsLock sync.Mutex
+	recorded    uint64
+	avgWaitMs   float64
+	avgRunMs    float64
+	observers   []TaskObserver
+}
+
+// NewPool starts a pool's workers
+func NewPool(opts PoolOptions) *Pool {
+	if opts.Workers <= 0 && opts.Policy != nil {
+		opts.Workers = opts.Policy.MaxConcurrency
+	}
+	if opts.Workers <= 0 {
+		opts.Workers = runtime.GOMAXPROCS(0)
+	}
+	if opts.QueueSize <= 0 {
+		opts.QueueSize = opts.Workers
+	}
+	p := &Pool{opts: opts, tasks: make(chan *PoolTask, opts.QueueSize)}
+	p.wg.Add(opts.Workers)
+	for i := 0; i < opts.Workers; i++ {
+		go p.work()
+	}
+	return p
+}
+
+// Submit queues fn to run with a context derived from ctx that carries the
+// task's token, blocking while the queue is full. It fails with ctx's
+// error if ctx is done first, and with ErrPoolClosed after Close.
+func (p *Pool) Submit(ctx context.Context, fn func(ctx context.Context) error) (*PoolTask, error) {
+	token := NewRiftToken(TokenGoChan, NewRiftMemorySpan(SpanFixed, 4096))
+	token.SetBit(TokenInitialized)
+	token.Validate()
+	task := &PoolTask{ctx: ctx, fn: fn, token: token, queued: clockNow(), done: make(chan struct{})}
+
+	p.lock.RLock()
+	defer p.lock.RUnlock()
+	if p.closed {
+		return nil, ErrPoolClosed
+	}
+	select {
+	case p.tasks <- task:
+		return task, nil
+	case <-ctx.Done():
+		return nil, contextError("submit", CodeCanceled, ctx.Err())
+	}
+}
+
+// Close stops accepting tasks and waits for the queued and running ones
+// to finish
+func (p *Pool) Close() {
+	p.lock.Lock()
+	if !p.closed {
+		p.closed = true
+		close(p.tasks)
+	}
+	p.lock.Unlock()
+	p.wg.Wait()
+}
+
+// work runs tasks until the pool closes
+func (p *Pool) work() {
+	defer p.wg.Done()
+	for task := range p.tasks {
+		p.run(task)
+	}
+}
+
+// run executes a task under its token's lock, recovering a panic as a
+// governance violation
+func (p *Pool) run(task *PoolTask) {
+	start := clockNow()
+	p.running.Add(1)
+	var panicked bool
+	panicked, task.err = p.invoke(task)
+	p.running.Add(-1)
+	task.token.ClearBit(TokenGoverned)
+	p.record(start.Sub(task.queued), clockSince(start), task.err, panicked)
+	close(task.done)
+}
+
+// invoke calls the task's function in its token context
+func (p *Pool) invoke(task *PoolTask) (panicked bool, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			panicked = true
+			if p.opts.OnPanic != nil {
+				p.opts.OnPanic(r)
+			} else {
+				fmt.Printf("Rift-governed pool task panicked: %v\n", r)
+			}
+			auditEmit(AuditPanic, task.token, fmt.Sprint(r))
+			err = govErr(CodePanic, "pool task", "%v", r)
+		}
+	}()
+	err = WithTokenContext(task.ctx, task.token, func(ctx context.Context, _ *RiftToken) error {
+		return task.fn(ctx)
+	})
+	return false, err
+}
+
+// ============================================================================
+// Metrics
+// ============================================================================
+
+// record folds a finished task into the pool metrics
+func (p *Pool) record(wait, run time.Duration, err error, panicked bool) {
+	if err != nil {
+		p.failed.Add(1)
+	} else {
+		p.completed.Add(1)
+	}
+	// After failed, so Stats never sees more panics than failures
+	if panicked {
+		p.panicked.Add(1)
+	}
+
+	p.metricsLock.Lock()
+	p.recorded++
+	waitMs := float64(wait.Nanoseconds()) / 1e6
+	runMs := float64(run.Nanoseconds()) / 1e6
+	p.avgWaitMs += (waitMs - p.avgWaitMs) / float64(p.recorded)
+	p.avgRunMs += (runMs - p.avgRunMs) / float64(p.recorded)
+	observers := p.observers
+	p.metricsLock.Unlock()
+
+	for _, fn := range observers {
+		fn(wait, run, err)
+	}
+}
+
+// AddTaskObserver registers fn to be called after every task
+func (p *Pool) AddTaskObserver(fn TaskObserver) {
+	p.metricsLock.Lock()
+	defer p.metricsLock.Unlock()
+	p.observers = append(p.observers[:len(p.observers):len(p.observers)], fn)
+}
+
+// Stats returns a typed snapshot of pool metrics
+func (p *Pool) Stats() PoolStats {
+	panicked := p.panicked.Load()
+	p.metricsLock.Lock()
+	defer p.metricsLock.Unlock()
+	return PoolStats{
+		Workers:          p.opts.Workers,
+		QueueDepth:       len(p.tasks),
+		Running:          int(p.running.Load()),
+		Completed:        p.completed.Load(),
+		Failed:           p.failed.Load(),
+		Panicked:         panicked,
+		AverageWaitMs:    p.avgWaitMs,
+		AverageLatencyMs: p.avgRunMs,
+	}
+}
+
+// GetMetrics returns pool metrics
+func (p *Pool) GetMetrics() map[string]interface{} {
+	stats := p.Stats()
+	return map[string]interface{}{
+		"workers":          stats.Workers,
+		"queueDepth":       stats.QueueDepth,
+		"running":          stats.Running,
+		"completed":        stats.Completed,
+		"failed":           stats.Failed,
+		"panicked":         stats.Panicked,
+		"averageWaitMs":    stats.AverageWaitMs,
+		"averageLatencyMs": stats.AverageLatencyMs,
+	}
+}
@@ -0,0 +1,164 @@
+// go/target/quantum_gates.go
+// Quantum Gate Operations - Go Implementation
+
+package rift
+
+import (
+	"math"
+	"math/cmplx"
+)
+
+// gateEpsilon is the magnitude below which an imaginary part is dropped
+const gateEpsilon = 1e-12
+
+// ============================================================================
+// Gates
+// ============================================================================
+
+// Gate is a single-qubit unitary acting on basis states |0> and |1>
+type Gate [2][2]complex128
+
+// Standard single-qubit gates
+var (
+	GateHadamard = Gate{
+		{complex(math.Sqrt2/2, 0), complex(math.Sqrt2/2, 0)},
+		{complex(math.Sqrt2/2, 0), complex(-math.Sqrt2/2, 0)},
+	}
+	GatePauliX = Gate{{0, 1}, {1, 0}}
+	GatePauliY = Gate{{0, -1i}, {1i, 0}}
+	GatePauliZ = Gate{{1, 0}, {0, -1}}
+)
+
+// GatePhaseShift returns the gate diag(1, e^{iθ})
+func GatePhaseShift(theta float64) Gate {
+	return Gate{{1, 0}, {0, cmplx.Exp(complex(0, theta))}}
+}
+
+// ============================================================================
+// Token Gate Operations
+// ============================================================================
+
+// Hadamard applies the Hadamard gate to qubit q of a superposed token
+func (t *RiftToken) Hadamard(q int) error {
+	return t.ApplyGate(q, GateHadamard)
+}
+
+// PauliX applies the Pauli-X (NOT) gate to qubit q of a superposed token
+func (t *RiftToken) PauliX(q int) error {
+	return t.ApplyGate(q, GatePauliX)
+}
+
+// PauliY applies the Pauli-Y gate to qubit q of a superposed token
+func (t *RiftToken) PauliY(q int) error {
+	return t.ApplyGate(q, GatePauliY)
+}
+
+// PauliZ applies the Pauli-Z gate to qubit q of a superposed token
+func (t *RiftToken) PauliZ(q int) error {
+	return t.ApplyGate(q, GatePauliZ)
+}
+
+// PhaseShift applies diag(1, e^{iθ}) to qubit q of a superposed token
+func (t *RiftToken) PhaseShift(q int, theta float64) error {
+	return t.ApplyGate(q, GatePhaseShift(theta))
+}
+
+// ApplyGate applies g to qubit q and renormalizes the amplitudes. The
+// superposed states are read as a register of log2(n) qubits, with qubit 0
+// the least significant bit of the state index, so the state count must be
+// a power of two.
+func (t *RiftToken) ApplyGate(q int, g Gate) error {
+	if !t.HasBit(TokenSuperposed) {
+		return govErr(CodeNotSuperposed, "gate", "token not in superposition")
+	}
+	n := len(t.SuperposedStates)
+	if n < 2 || n&(n-1) != 0 {
+		return govErr(CodeNotQubit, "gate", "%d states is not a qubit register", n)
+	}
+	if q < 0 || q >= qubitCount(n) {
+		return govErr(CodeIndexOutOfRange, "gate", "qubit %d out of %d", q, qubitCount(n))
+	}
+
+	if t.joint != nil {
+		t.applyJoint(g)
+		auditEmit(AuditGate, t, "")
+		return nil
+	}
+
+	amps := t.stateVector()
+	applyGateBlocks(amps, 1<<q, g)
+	if err := t.setStateVector("gate", amps); err != nil {
+		return err
+	}
+	auditEmit(AuditGate, t, "")
+	t.decohere()
+	return nil
+}
+
+// stateVector returns the complex amplitudes of the superposed states.
+// Missing or mismatched amplitudes are read as a uniform superposition.
+func (t *RiftToken) stateVector() []complex128 {
+	n := len(t.SuperposedStates)
+	amps := make([]complex128, n)
+	if len(t.Amplitudes) == n && len(t.Phases) != n {
+		for i, a := range t.Amplitudes[:n] {
+			amps[i] = complex(a, 0)
+		}
+		return amps
+	}
+	for i := range amps {
+		mag := 1 / math.Sqrt(float64(n))
+		if len(t.Amplitudes) == n {
+			mag = t.Amplitudes[i]
+		}
+		phase := 0.0
+		if len(t.Phases) == n {
+			phase = t.Phases[i]
+		}
+		amps[i] = complex(mag, 0) * cmplx.Exp(complex(0, phase))
+	}
+	return amps
+}
+
+// setStateVector normalizes amps and stores them back on the token. Purely
+// real vectors keep signed Amplitudes with no Phases; otherwise Amplitudes
+// hold magnitudes and Phases the per-state arguments.
+func (t *RiftToken) setStateVector(op string, amps []complex128) error {
+	norm := complexNormSq(amps)
+	isReal := true
+	for _, a := range amps {
+		if math.Abs(imag(a)) > gateEpsilon {
+			isReal = false
+			break
+		}
+	}
+	if norm <= 0 {
+		return govErr(CodeZeroProbability, op, "state vector has zero norm")
+	}
+	scale := 1 / math.Sqrt(norm)
+
+	t.Amplitudes = make([]float64, len(amps))
+	t.Phases = nil
+	if isReal {
+		for i, a := range amps {
+			t.Amplitudes[i] = real(a) * scale
+		}
+		return nil
+	}
+	t.Phases = make([]float64, len(amps))
+	for i, a := range amps {
+		t.Amplitudes[i] = cmplx.Abs(a) * scale
+		t.Phases[i] = cmplx.Phase(a)
+	}
+	return nil
+}
+
+// qubitCount returns log2(n) for a power-of-two state count
+func qubitCount(n int) int {
+	q := 0
+	for n > 1 {
+		n >>= 1
+		q++
+	}
+	return q
+}
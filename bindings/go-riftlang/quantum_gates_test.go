@@ -0,0 +1,25 @@
+package rift
+
+import (
+	"testing"
+)
+
+// TestApplyGateQubitRange applies gates to qubits outside a two-qubit
+// register, directly and through a circuit. Qubits 63 and 64 once
+// overflowed the range check.
+func TestApplyGateQubitRange(t *testing.T) {
+	for _, q := range []int{0, 1} {
+		if err := Superpose(0, 1, 2, 3).Hadamard(q); err != nil {
+			t.Errorf("Hadamard(%d): %v", q, err)
+		}
+	}
+	for _, q := range []int{-1, 2, 3, 31, 62, 63, 64, 65, 1 << 20} {
+		if err := Superpose(0, 1, 2, 3).Hadamard(q); ErrorCodeOf(err) != CodeIndexOutOfRange {
+			t.Errorf("Hadamard(%d) error %v, want %v", q, err, CodeIndexOutOfRange)
+		}
+		err := NewCircuit().H(Superpose(0, 1, 2, 3), q).Run()
+		if ErrorCodeOf(err) != CodeIndexOutOfRange {
+			t.Errorf("circuit H(%d) error %v, want %v", q, err, CodeIndexOutOfRange)
+		}
+	}
+}
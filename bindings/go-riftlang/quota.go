@@ -0,0 +1,195 @@
+// go/target/quota.go
+// Span Quotas and Rate Limits - Go Implementation
+
+package rift
+
+import (
+	"math"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// ErrQuotaExceeded matches, via errors.Is, every error a QuotaPolicy
+// limit causes
+var ErrQuotaExceeded error = &GovernanceError{Code: CodeQuotaExceeded, Op: "quota", Detail: "quota exceeded"}
+
+// ============================================================================
+// QuotaPolicy
+// ============================================================================
+
+// QuotaPolicy limits the resources governed tokens may take. Zero and
+// missing entries are unlimited; each limit fails with CodeQuotaExceeded
+// and is reported to the violation handler.
+type QuotaPolicy struct {
+	// NamespaceBytes bounds, per namespace name ("pkg" or "pkg.sub"), the
+	// span bytes of the distinct tokens registered under it in any
+	// TokenRegistry, nested namespaces included. Register refuses a token
+	// that would exceed it.
+	NamespaceBytes map[string]uint64
+
+	// SpanTokens bounds the governed tokens per span type (SpanFixed and
+	// so on). A token counts from its first successful validation until
+	// Release; ValidateErr refuses a token that would exceed it.
+	SpanTokens map[int]int
+
+	// OpsPerSecond bounds the SetValue and GetValue calls on the tokens of
+	// each span, allowing bursts of up to Burst calls (at least 1; 0 means
+	// one second's worth). Calls over the limit fail rather than wait.
+	OpsPerSecond float64
+	Burst        int
+}
+
+// quotas holds the policy in force; nil when no limits are set
+var quotas atomic.Pointer[QuotaPolicy]
+
+// spanTokens counts governed tokens per span type, whether or not a
+// policy is set, so a policy applies to the tokens already governed
+var spanTokens [SpanDistributed + 1]atomic.Int64
+
+// SetQuota puts p in force, replacing the previous policy; nil removes
+// every limit. The policy must not be modified afterwards. Rate limits
+// start afresh with each policy.
+func SetQuota(p *QuotaPolicy) {
+	quotas.Store(p)
+}
+
+// Quota returns the policy in force, or nil
+func Quota() *QuotaPolicy {
+	return quotas.Load()
+}
+
+// SpanTokenCount returns the number of governed tokens counted against
+// QuotaPolicy.SpanTokens for spanType
+func SpanTokenCount(spanType int) int {
+	if spanType < 0 || spanType >= len(spanTokens) {
+		return 0
+	}
+	return int(spanTokens[spanType].Load())
+}
+
+// ============================================================================
+// Span Token Counts
+// ============================================================================
+
+// admitQuota counts a token being governed for the first time against its
+// span type's limit, or returns why it may not be governed
+func (t *RiftToken) admitQuota() *GovernanceError {
+	if t.quotaSpan.Load() != 0 || t.Memory == nil {
+		return nil
+	}
+	st := t.Memory.Type
+	if st < 0 || st >= len(spanTokens) {
+		return nil
+	}
+	if !t.quotaSpan.CompareAndSwap(0, int32(st)+1) {
+		return nil // counted by a concurrent validation
+	}
+	n := spanTokens[st].Add(1)
+	if p := quotas.Load(); p != nil {
+		if limit := p.SpanTokens[st]; limit > 0 && n > int64(limit) {
+			spanTokens[st].Add(-1)
+			t.quotaSpan.Store(0)
+			return govErr(CodeQuotaExceeded, "validate", "span type %d holds its quota of %d governed tokens", st, limit)
+		}
+	}
+	return nil
+}
+
+// releaseQuota uncounts a released token
+func (t *RiftToken) releaseQuota() {
+	if st := t.quotaSpan.Swap(0); st != 0 {
+		spanTokens[st-1].Add(-1)
+	}
+}
+
+// ============================================================================
+// Namespace Bytes
+// ============================================================================
+
+// namespaceQuotaError returns why registering t under name would exceed a
+// namespace's byte quota, or nil; r.lock held
+func (r *TokenRegistry) namespaceQuotaError(name string, t *RiftToken) *GovernanceError {
+	p := quotas.Load()
+	if p == nil || len(p.NamespaceBytes) == 0 || t == nil {
+		return nil
+	}
+	for ns, limit := range p.NamespaceBytes {
+		prefix := ns + NamespaceSeparator
+		if limit == 0 || !strings.HasPrefix(name, prefix) {
+			continue
+		}
+		counted := map[*RiftToken]bool{t: true}
+		used := spanBytes(t)
+		for n, other := range r.tokens {
+			if n == name || counted[other] || !strings.HasPrefix(n, prefix) {
+				continue
+			}
+			counted[other] = true
+			used += spanBytes(other)
+		}
+		if used > limit {
+			return govErr(CodeQuotaExceeded, "register", "namespace %q would hold %d span bytes, over its quota of %d", ns, used, limit)
+		}
+	}
+	return nil
+}
+
+// spanBytes returns the bytes of t's span, 0 without one
+func spanBytes(t *RiftToken) uint64 {
+	if t == nil || t.Memory == nil {
+		return 0
+	}
+	return t.Memory.Bytes
+}
+
+// ============================================================================
+// Rate Limits
+// ============================================================================
+
+// rateBucket is a span's token bucket for QuotaPolicy.OpsPerSecond
+type rateBucket struct {
+	policy *QuotaPolicy // the policy the bucket was filled for
+	lock   sync.Mutex
+	tokens float64
+	last   time.Time
+}
+
+// checkRate takes one operation from the token's span's rate limit, or
+// reports that none is left
+func (t *RiftToken) checkRate(op string) error {
+	p := quotas.Load()
+	if p == nil || p.OpsPerSecond <= 0 || t.Memory == nil {
+		return nil
+	}
+	burst := float64(p.Burst)
+	if burst <= 0 {
+		burst = math.Max(1, p.OpsPerSecond)
+	}
+
+	b := t.Memory.rate.Load()
+	if b == nil || b.policy != p {
+		fresh := &rateBucket{policy: p, tokens: burst, last: clockNow()}
+		if t.Memory.rate.CompareAndSwap(b, fresh) {
+			b = fresh
+		} else {
+			b = t.Memory.rate.Load()
+		}
+	}
+
+	b.lock.Lock()
+	now := clockNow()
+	b.tokens = math.Min(burst, b.tokens+now.Sub(b.last).Seconds()*p.OpsPerSecond)
+	b.last = now
+	ok := b.tokens >= 1
+	if ok {
+		b.tokens--
+	}
+	b.lock.Unlock()
+
+	if ok {
+		return nil
+	}
+	return t.violation(t.located(govErr(CodeQuotaExceeded, op, "span over its rate of %g operations per second", p.OpsPerSecond)))
+}
@@ -0,0 +1,291 @@
+// go/target/registry.go
+// Named Token Registry - Go Implementation
+
+package rift
+
+import (
+	"sort"
+	"strings"
+	"sync"
+)
+
+// NamespaceSeparator joins a namespace and a token name ("pkg.x")
+const NamespaceSeparator = "."
+
+// ============================================================================
+// TokenRegistry
+// ============================================================================
+
+// TokenRegistry maps names to live tokens. Names are flat strings; a
+// Namespace view prefixes them so packages can register without colliding.
+type TokenRegistry struct {
+	lock    sync.RWMutex
+	tokens  map[string]*RiftToken
+	byToken map[*RiftToken]string // name each token was last registered under (see NameOf)
+}
+
+// DefaultRegistry receives the tokens created by Var and Func
+var DefaultRegistry = NewTokenRegistry()
+
+// NewTokenRegistry creates an empty registry
+func NewTokenRegistry() *TokenRegistry {
+	return &TokenRegistry{tokens: make(map[string]*RiftToken), byToken: make(map[*RiftToken]string)}
+}
+
+// Register binds name to t, replacing any token already registered under
+// that name, and returns the replaced token or nil. A token that would
+// take a namespace over its QuotaPolicy.NamespaceBytes is reported to its
+// violation handler and left unregistered; RegisterErr returns the error.
+func (r *TokenRegistry) Register(name string, t *RiftToken) *RiftToken {
+	prev, _ := r.RegisterErr(name, t)
+	return prev
+}
+
+// RegisterErr is Register returning a CodeQuotaExceeded error when the
+// token would exceed a namespace quota
+func (r *TokenRegistry) RegisterErr(name string, t *RiftToken) (*RiftToken, error) {
+	r.lock.Lock()
+	if err := r.namespaceQuotaError(name, t); err != nil {
+		r.lock.Unlock()
+		return nil, t.violation(t.located(err))
+	}
+	prev := r.tokens[name]
+	r.bind(name, t)
+	r.lock.Unlock()
+	if r == DefaultRegistry {
+		publishEvent(EventRegistered, t, name)
+	}
+	return prev, nil
+}
+
+// Lookup returns the token registered under name
+func (r *TokenRegistry) Lookup(name string) (*RiftToken, bool) {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+	t, ok := r.tokens[name]
+	return t, ok
+}
+
+// Unregister removes name, reporting whether it was registered
+func (r *TokenRegistry) Unregister(name string) bool {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	_, ok := r.tokens[name]
+	r.unbind(name)
+	return ok
+}
+
+// NameOf returns the name t was last registered under, while that name
+// is still bound to it
+func (r *TokenRegistry) NameOf(t *RiftToken) (string, bool) {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+	name, ok := r.byToken[t]
+	return name, ok
+}
+
+// Names returns every registered name in sorted order
+func (r *TokenRegistry) Names() []string {
+	return r.names("")
+}
+
+// Len returns the number of registered tokens
+func (r *TokenRegistry) Len() int {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+	return len(r.tokens)
+}
+
+// Range calls fn for each registered token in name order until fn returns
+// false. It works on a snapshot, so fn may modify the registry.
+func (r *TokenRegistry) Range(fn func(name string, t *RiftToken) bool) {
+	r.rangePrefix("", fn)
+}
+
+// Clear unregisters every token
+func (r *TokenRegistry) Clear() {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.reset(make(map[string]*RiftToken))
+}
+
+// bind binds name to t; r.lock held
+func (r *TokenRegistry) bind(name string, t *RiftToken) {
+	if prev, ok := r.tokens[name]; ok && r.byToken[prev] == name {
+		delete(r.byToken, prev)
+	}
+	r.tokens[name] = t
+	r.byToken[t] = name
+}
+
+// unbind removes name; r.lock held
+func (r *TokenRegistry) unbind(name string) {
+	if t, ok := r.tokens[name]; ok && r.byToken[t] == name {
+		delete(r.byToken, t)
+	}
+	delete(r.tokens, name)
+}
+
+// reset replaces every binding with tokens; r.lock held
+func (r *TokenRegistry) reset(tokens map[string]*RiftToken) {
+	r.tokens = tokens
+	r.byToken = make(map[*RiftToken]string, len(tokens))
+	for name, t := range tokens {
+		r.byToken[t] = name
+	}
+}
+
+// Namespace returns a view of the registry whose names are prefixed with
+// ns and NamespaceSeparator
+func (r *TokenRegistry) Namespace(ns string) Namespace {
+	return Namespace{registry: r, prefix: ns + NamespaceSeparator}
+}
+
+// names returns the sorted names starting with prefix
+func (r *TokenRegistry) names(prefix string) []string {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+	var names []string
+	for name := range r.tokens {
+		if strings.HasPrefix(name, prefix) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// rangePrefix calls fn for each token whose name starts with prefix
+func (r *TokenRegistry) rangePrefix(prefix string, fn func(name string, t *RiftToken) bool) {
+	for _, name := range r.names(prefix) {
+		t, ok := r.Lookup(name)
+		if !ok {
+			continue // unregistered since the snapshot
+		}
+		if !fn(name, t) {
+			return
+		}
+	}
+}
+
+// ============================================================================
+// Namespace
+// ============================================================================
+
+// Namespace is a prefixed view of a TokenRegistry. Names passed to and
+// returned from a Namespace are relative to it.
+type Namespace struct {
+	registry *TokenRegistry
+	prefix   string
+}
+
+// Name returns the namespace's name
+func (n Namespace) Name() string {
+	return strings.TrimSuffix(n.prefix, NamespaceSeparator)
+}
+
+// Namespace returns a nested namespace
+func (n Namespace) Namespace(ns string) Namespace {
+	return Namespace{registry: n.registry, prefix: n.prefix + ns + NamespaceSeparator}
+}
+
+// Register binds name within the namespace; see TokenRegistry.Register
+func (n Namespace) Register(name string, t *RiftToken) *RiftToken {
+	return n.registry.Register(n.prefix+name, t)
+}
+
+// RegisterErr binds name within the namespace; see
+// TokenRegistry.RegisterErr
+func (n Namespace) RegisterErr(name string, t *RiftToken) (*RiftToken, error) {
+	return n.registry.RegisterErr(n.prefix+name, t)
+}
+
+// Lookup returns the token registered under name within the namespace
+func (n Namespace) Lookup(name string) (*RiftToken, bool) {
+	return n.registry.Lookup(n.prefix + name)
+}
+
+// Unregister removes name from the namespace
+func (n Namespace) Unregister(name string) bool {
+	return n.registry.Unregister(n.prefix + name)
+}
+
+// Names returns the sorted names in the namespace, nested ones included
+func (n Namespace) Names() []string {
+	names := n.registry.names(n.prefix)
+	for i, name := range names {
+		names[i] = strings.TrimPrefix(name, n.prefix)
+	}
+	return names
+}
+
+// Range calls fn for each token in the namespace until fn returns false
+func (n Namespace) Range(fn func(name string, t *RiftToken) bool) {
+	n.registry.rangePrefix(n.prefix, func(name string, t *RiftToken) bool {
+		return fn(strings.TrimPrefix(name, n.prefix), t)
+	})
+}
+
+// Clear unregisters every token in the namespace
+func (n Namespace) Clear() {
+	n.registry.lock.Lock()
+	defer n.registry.lock.Unlock()
+	for name := range n.registry.tokens {
+		if strings.HasPrefix(name, n.prefix) {
+			n.registry.unbind(name)
+		}
+	}
+}
+
+// ============================================================================
+// Engine Registry
+// ============================================================================
+
+// engines maps names to pattern engines so Snapshot can find them
+var engines struct {
+	lock   sync.RWMutex
+	byName map[string]*PatternEngine
+}
+
+// RegisterEngine binds name to e, replacing and returning any engine
+// already registered under that name. Registered engines are captured by
+// Snapshot.
+func RegisterEngine(name string, e *PatternEngine) *PatternEngine {
+	engines.lock.Lock()
+	defer engines.lock.Unlock()
+	if engines.byName == nil {
+		engines.byName = make(map[string]*PatternEngine)
+	}
+	prev := engines.byName[name]
+	engines.byName[name] = e
+	return prev
+}
+
+// LookupEngine returns the engine registered under name
+func LookupEngine(name string) (*PatternEngine, bool) {
+	engines.lock.RLock()
+	defer engines.lock.RUnlock()
+	e, ok := engines.byName[name]
+	return e, ok
+}
+
+// UnregisterEngine removes name, reporting whether it was registered
+func UnregisterEngine(name string) bool {
+	engines.lock.Lock()
+	defer engines.lock.Unlock()
+	_, ok := engines.byName[name]
+	delete(engines.byName, name)
+	return ok
+}
+
+// EngineNames returns every registered engine name in sorted order
+func EngineNames() []string {
+	engines.lock.RLock()
+	defer engines.lock.RUnlock()
+	names := make([]string, 0, len(engines.byName))
+	for name := range engines.byName {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
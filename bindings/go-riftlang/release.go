@@ -0,0 +1,224 @@
+// go/target/release.go
+// Token Release and Leak Detection - Go Implementation
+
+package rift
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// ============================================================================
+// Release
+// ============================================================================
+
+// ReleaseHook observes a token about to be released. It runs before the
+// token is torn down, so it can still read the token's value.
+type ReleaseHook func(t *RiftToken)
+
+// OnRelease registers fn to run when the token is released, as a finalizer
+// for resources tied to its lifetime. The returned func removes the hook.
+func (t *RiftToken) OnRelease(fn ReleaseHook) (remove func()) {
+	h := t.hookSet()
+	return addHook(h, &h.release, fn)
+}
+
+// Release ends the token's lifetime, requiring AccessDelete. It runs the
+// release hooks, then detaches the token from its entanglement partners,
+// shared span slot and TTL, zeroes its value and quantum state, wipes and
+// releases its memory span along with the spans carved from it, and clears
+// every validation bit. A released token fails validation with
+// E_NOT_ALLOCATED and cannot be released again. States and array elements
+// the token refers to are tokens of their own and are not released. The
+// token must not be locked or in use by other goroutines.
+func (t *RiftToken) Release() error {
+	if !t.HasBit(TokenAllocated) {
+		return t.located(govErr(CodeNotAllocated, "release", "token not allocated"))
+	}
+	if err := t.checkAccess("release", AccessDelete); err != nil {
+		return err
+	}
+
+	if h := t.hooks.Load(); h != nil {
+		for _, fn := range hookFuncs(h, &h.release) {
+			fn(t)
+		}
+	}
+
+	t.disentangle()
+	t.shared.Store(nil)
+	t.SetTTL(0)
+
+	t.valueLock.Lock()
+	t.shadowOf = nil
+	t.shadowDirty = false
+	wipeValue(&t.Value)
+	t.version.Add(1)
+	t.valueLock.Unlock()
+	t.SuperposedStates = nil
+	t.SuperpositionCount = 0
+	t.Amplitudes = nil
+	t.Phases = nil
+	t.Phase = 0
+
+	if t.Memory != nil {
+		t.Memory.Release()
+	}
+	t.ValidationBits.Store(0)
+	t.reseal()
+	t.hooks.Store(nil)
+	untrackLeak(t)
+	t.countReleased()
+	t.releaseQuota()
+	auditEmit(AuditRelease, t, "")
+	publishEvent(EventReleased, t, "")
+	return nil
+}
+
+// Released reports whether the token has been released, or was retired as
+// a shadow
+func (t *RiftToken) Released() bool {
+	return !t.HasBit(TokenAllocated)
+}
+
+// disentangle removes the token from its partners' EntangledWith links,
+// clearing TokenEntangled on partners left with none, and leaves its joint
+// register
+func (t *RiftToken) disentangle() {
+	for _, p := range t.EntangledWith {
+		links := p.EntangledWith[:0]
+		for _, q := range p.EntangledWith {
+			if q != t {
+				links = append(links, q)
+			}
+		}
+		for i := len(links); i < len(p.EntangledWith); i++ {
+			p.EntangledWith[i] = nil
+		}
+		p.EntangledWith = links
+		p.EntanglementCount = uint32(len(links))
+		if len(links) == 0 && p.joint == nil {
+			p.ClearBit(TokenEntangled)
+		}
+	}
+	t.EntangledWith = nil
+	t.EntanglementCount = 0
+	t.EntanglementID = 0
+	unregisterEntanglement(t)
+	t.joint = nil
+	t.jointQubit = 0
+}
+
+// wipeValue zeroes v. Byte slices held in PtrVal are overwritten in place;
+// strings are immutable and can only be dropped.
+func wipeValue(v *RiftTokenValue) {
+	if b, ok := v.PtrVal.([]byte); ok {
+		for i := range b {
+			b[i] = 0
+		}
+	}
+	*v = RiftTokenValue{}
+}
+
+// ============================================================================
+// Leak Detection
+// ============================================================================
+
+// LeakReport describes a token created while leak detection was on and not
+// yet released
+type LeakReport struct {
+	Token   *RiftToken
+	Type    int
+	Source  string // file:line of the creating code, if captured
+	Created time.Time
+}
+
+// String formats the report for logs
+func (r LeakReport) String() string {
+	src := r.Source
+	if src == "" {
+		src = "unknown source"
+	}
+	return fmt.Sprintf("%s token %p created at %s, %v ago", TokenTypeName(r.Type), r.Token, src, clockSince(r.Created).Round(time.Millisecond))
+}
+
+// leaks tracks live tokens while leak detection is on
+var leaks struct {
+	on   atomic.Bool
+	lock sync.Mutex
+	seq  uint64
+	live map[*RiftToken]leakEntry
+}
+
+type leakEntry struct {
+	seq     uint64
+	created time.Time
+}
+
+// SetLeakDetection turns leak detection on or off. While on, every token
+// NewRiftToken creates is tracked until it is released, and Leaks lists
+// the ones still live. Tracked tokens are kept reachable, so this is a
+// debugging aid for tests and development. Turning detection on or off
+// forgets the tokens tracked so far.
+func SetLeakDetection(on bool) {
+	leaks.lock.Lock()
+	defer leaks.lock.Unlock()
+	leaks.live = nil
+	if on {
+		leaks.live = make(map[*RiftToken]leakEntry)
+	}
+	leaks.on.Store(on)
+}
+
+// LeakDetection reports whether leak detection is on
+func LeakDetection() bool {
+	return leaks.on.Load()
+}
+
+// Leaks returns the tracked tokens not yet released, oldest first
+func Leaks() []LeakReport {
+	leaks.lock.Lock()
+	type tracked struct {
+		token *RiftToken
+		leakEntry
+	}
+	live := make([]tracked, 0, len(leaks.live))
+	for t, e := range leaks.live {
+		live = append(live, tracked{t, e})
+	}
+	leaks.lock.Unlock()
+
+	sort.Slice(live, func(i, j int) bool { return live[i].seq < live[j].seq })
+	reports := make([]LeakReport, len(live))
+	for i, l := range live {
+		reports[i] = LeakReport{Token: l.token, Type: l.token.Type, Source: l.token.Source(), Created: l.created}
+	}
+	return reports
+}
+
+// trackLeak records a new token while leak detection is on
+func trackLeak(t *RiftToken) {
+	if !leaks.on.Load() {
+		return
+	}
+	leaks.lock.Lock()
+	defer leaks.lock.Unlock()
+	if leaks.live == nil {
+		return
+	}
+	leaks.seq++
+	leaks.live[t] = leakEntry{seq: leaks.seq, created: clockNow()}
+}
+
+// untrackLeak forgets a released token
+func untrackLeak(t *RiftToken) {
+	if !leaks.on.Load() {
+		return
+	}
+	leaks.lock.Lock()
+	defer leaks.lock.Unlock()
+	delete(leaks.live, t)
+}
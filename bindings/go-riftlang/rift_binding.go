@@ -8,7 +8,9 @@ import (
 	"fmt"
 	"math"
 	"math/rand"
+	"reflect"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
@@ -28,11 +30,26 @@ const (
 	TokenShadow      uint32 = 0x80
 )
 
+// Access mask bits. The CRUD bits are the permissions riftlang.h documents
+// for access_mask; AccessSuperpose and AccessEntangle exist only in the Go
+// binding and are never set by C-side policies.
+const (
+	AccessCreate    uint32 = 0x01
+	AccessRead      uint32 = 0x02
+	AccessUpdate    uint32 = 0x04
+	AccessDelete    uint32 = 0x08
+	AccessSuperpose uint32 = 0x10
+	AccessEntangle  uint32 = 0x20
+
+	AccessCRUD = AccessCreate | AccessRead | AccessUpdate | AccessDelete
+)
+
 // Memory alignment constants
 const (
 	ClassicalAlignment = 4096
 	QuantumAlignment   = 8
 	DefaultThreshold   = 0.85
+	DefaultEntropy     = 0.25
 )
 
 // Token types
@@ -45,6 +62,7 @@ const (
 	TokenGoChan
 	TokenQGoInt
 	TokenQGoChan
+	TokenGoPtr // bools, structs, pointers and other values held in PtrVal
 )
 
 // Span types
@@ -63,12 +81,24 @@ const (
 
 // RiftMemorySpan - declared BEFORE type or value per Rift spec
 type RiftMemorySpan struct {
-	Type        int
-	Bytes       uint64
-	Alignment   uint32
-	Open        bool
-	Direction   bool // true = right->left
-	AccessMask  uint32
+	Type       int
+	Bytes      uint64
+	Alignment  uint32
+	Open       bool
+	Direction  bool   // true = right->left
+	AccessMask uint32 // read with Mask once the span is shared
+
+	// Sub-span tree (see Carve), guarded by spanTree
+	parent   *RiftMemorySpan
+	offset   uint64
+	children []*RiftMemorySpan
+	released atomic.Bool
+	resizes  atomic.Uint64              // Resize count, so integrity checks accept new sizes
+	affinity *SpanAffinity              // see NewRiftMemorySpanForCPU
+	data     []byte                     // root span contents, see Writer; guarded by spanTree
+	aligned  bool                       // data is an aligned buffer (see Buffer); guarded by spanTree
+	mapping  *spanMapping               // file holding data (see OpenMappedSpan); guarded by spanTree
+	rate     atomic.Pointer[rateBucket] // see QuotaPolicy.OpsPerSecond
 }
 
 // NewRiftMemorySpan creates a new memory span
@@ -78,7 +108,7 @@ func NewRiftMemorySpan(spanType int, bytes uint64) *RiftMemorySpan {
 		Bytes:      bytes,
 		Open:       true,
 		Direction:  true,
-		AccessMask: 0x0F, // CREATE|READ|UPDATE|DELETE
+		AccessMask: AccessCRUD,
 	}
 
 	// Set default alignment based on span type
@@ -88,7 +118,7 @@ func NewRiftMemorySpan(spanType int, bytes uint64) *RiftMemorySpan {
 	case SpanSuperposed, SpanEntangled:
 		span.Alignment = QuantumAlignment
 	case SpanDistributed:
-		span.Alignment = 64 // Cache-line alignment
+		span.Alignment = CacheLineSize()
 	default:
 		span.Alignment = ClassicalAlignment
 	}
@@ -96,6 +126,44 @@ func NewRiftMemorySpan(spanType int, bytes uint64) *RiftMemorySpan {
 	return span
 }
 
+// Mask returns the access mask. Grant and Revoke may change it while
+// tokens on the span are in use, so it is loaded atomically.
+func (s *RiftMemorySpan) Mask() uint32 {
+	return atomic.LoadUint32(&s.AccessMask)
+}
+
+// Allows reports whether the access mask grants all of bits
+func (s *RiftMemorySpan) Allows(bits uint32) bool {
+	return s.Mask()&bits == bits
+}
+
+// Grant adds bits to the access mask. A carved span cannot be granted
+// bits its parent lacks.
+func (s *RiftMemorySpan) Grant(bits uint32) {
+	spanTree.Lock()
+	defer spanTree.Unlock()
+	if s.parent != nil {
+		bits &= s.parent.Mask()
+	}
+	atomic.OrUint32(&s.AccessMask, bits)
+}
+
+// Revoke removes bits from the access mask and from every span carved
+// from it
+func (s *RiftMemorySpan) Revoke(bits uint32) {
+	spanTree.Lock()
+	defer spanTree.Unlock()
+	s.revoke(bits)
+}
+
+// revoke implements Revoke; spanTree held
+func (s *RiftMemorySpan) revoke(bits uint32) {
+	atomic.AndUint32(&s.AccessMask, ^bits)
+	for _, c := range s.children {
+		c.revoke(bits)
+	}
+}
+
 // ValidateAlignment checks if alignment is power of 2
 func (s *RiftMemorySpan) ValidateAlignment() bool {
 	align := s.Alignment
@@ -118,27 +186,60 @@ type RiftTokenValue struct {
 // RiftToken - The Token Triplet: (type, value, memory) with governance
 type RiftToken struct {
 	// Core triplet - memory declared first per Rift spec
-	Type           int
-	Value          RiftTokenValue
-	Memory         *RiftMemorySpan
+	Type   int
+	Value  RiftTokenValue
+	Memory *RiftMemorySpan
 
 	// Governance fields
-	ValidationBits uint32
-	lock           sync.RWMutex
+	ValidationBits atomic.Uint32 // see SetBit, ClearBit and HasBit
+	lock           rwLock
 	lockCount      uint32
+	orderID        atomic.Uint64 // see lockOrder
+	countedLive    bool          // counted in rift/tokens/live (see ReadMetrics)
+	quotaSpan      atomic.Int32  // span type + 1 counted in QuotaPolicy.SpanTokens, 0 = none
+
+	// Value version (see Version), bumped under valueLock with every change
+	valueLock sync.Mutex
+	version   atomic.Uint64
+
+	// Shadow fields (valid when TokenShadow set)
+	shadowOf    *RiftToken
+	shadowDirty bool
+
+	// Shared span slot (see SharedSpan.Token)
+	shared atomic.Pointer[sharedSlot]
+
+	// Lifecycle observers, allocated on first registration
+	hooks atomic.Pointer[tokenHooks]
+
+	// Trace span bound by LockContext (see EnableTracing)
+	span atomic.Pointer[boundSpan]
+
+	// Expiry (see SetTTL): unix nanoseconds, 0 = no TTL
+	expiresAt     atomic.Int64
+	expiryAudited atomic.Bool
+
+	// Integrity checksum (see SetIntegrityMode), guarded by valueLock
+	checksum      uint64
+	sealed        bool
+	sealedResizes uint64 // Memory.resizes when sealed
 
 	// Quantum fields (valid when TokenSuperposed set)
-	SuperposedStates []*RiftToken
+	SuperposedStates   []*RiftToken
 	SuperpositionCount uint32
-	Amplitudes       []float64
-	Phase            float64
+	Amplitudes         []float64
+	Phases             []float64 // per-state relative phase; nil when all real
+	Phase              float64
 
 	// Entanglement fields (valid when TokenEntangled set)
-	EntangledWith    []*RiftToken
+	EntangledWith     []*RiftToken
 	EntanglementCount uint32
-	EntanglementID   uint32
+	EntanglementID    uint32
+	joint             *jointRegister // shared state of BellPair/GHZ qubits
+	jointQubit        int
+	entCleanup        bool // the entanglement registry has a cleanup on the token
 
-	// Source location
+	// Source location (see SetSourceCapture)
 	SourceLine   uint32
 	SourceColumn uint32
 	SourceFile   string
@@ -147,108 +248,276 @@ type RiftToken struct {
 // NewRiftToken creates a new Rift token
 func NewRiftToken(tokenType int, memory *RiftMemorySpan) *RiftToken {
 	token := &RiftToken{
-		Type:           tokenType,
-		Memory:         memory,
-		ValidationBits: TokenAllocated,
-		Phase:          0.0,
+		Type:   tokenType,
+		Memory: memory,
+		Phase:  0.0,
 	}
+	token.ValidationBits.Store(TokenAllocated)
+	token.captureSource()
+	token.countCreated()
+	trackLeak(token)
+	publishEvent(EventCreated, token, "")
 
 	return token
 }
 
 // GetValue gets the token value with validation check
 func (t *RiftToken) GetValue() (RiftTokenValue, error) {
-	if t.ValidationBits&TokenInitialized == 0 {
-		return RiftTokenValue{}, fmt.Errorf("token value not initialized")
+	if err := t.checkAccess("get", AccessRead); err != nil {
+		return RiftTokenValue{}, err
+	}
+	if err := t.checkRate("get"); err != nil {
+		return RiftTokenValue{}, err
 	}
-	return t.Value, nil
+	t.Refresh()
+	src := t.readSource()
+	if !src.HasBit(TokenInitialized) {
+		return RiftTokenValue{}, govErr(CodeNotInitialized, "get", "token value not initialized")
+	}
+	src.valueLock.Lock()
+	defer src.valueLock.Unlock()
+	return src.Value, nil
 }
 
-// SetValue sets the token value with immediate binding (classic mode)
-func (t *RiftToken) SetValue(val RiftTokenValue) {
-	t.Value = val
-	t.ValidationBits |= TokenInitialized
+// SetValue sets the token value with immediate binding (classic mode). The
+// first write requires AccessCreate, later writes require AccessUpdate.
+func (t *RiftToken) SetValue(val RiftTokenValue) error {
+	need := AccessUpdate
+	if !t.readSource().HasBit(TokenInitialized) {
+		need = AccessCreate
+	}
+	if err := t.checkAccess("set", need); err != nil {
+		return err
+	}
+	if err := t.checkRate("set"); err != nil {
+		return err
+	}
+	return t.writeValue(val, nil)
 }
 
-// Lock acquires the token lock for thread safety
+// Delete clears the token value, requiring AccessDelete. The token stays
+// allocated but is no longer initialized or governed.
+func (t *RiftToken) Delete() error {
+	if err := t.checkAccess("delete", AccessDelete); err != nil {
+		return err
+	}
+
+	t.valueLock.Lock()
+	old := t.previousValue()
+	t.storeLocked(RiftTokenValue{})
+	t.shadowDirty = true
+	t.valueLock.Unlock()
+	t.ClearBit(TokenInitialized | TokenGoverned)
+	t.reseal()
+	auditEmit(AuditDelete, t, "")
+	t.fireChange(old, RiftTokenValue{})
+	return nil
+}
+
+// checkAccess verifies the memory span's access mask grants bits. Tokens
+// without a memory span carry no mask and are not restricted.
+func (t *RiftToken) checkAccess(op string, bits uint32) error {
+	if t.Memory == nil || t.Memory.Allows(bits) {
+		return nil
+	}
+	err := govErr(CodePermissionDenied, op, "access mask 0x%02x lacks 0x%02x", t.Memory.Mask(), bits)
+	auditEmit(AuditAccessDenied, t, err.Error())
+	return t.violation(err)
+}
+
+// Lock acquires the token lock for thread safety. It returns false only
+// when deadlock detection is in DeadlockError mode and the wait would
+// complete a cycle.
 func (t *RiftToken) Lock() bool {
-	t.lock.Lock()
-	t.lockCount++
-	t.ValidationBits |= TokenLocked
+	gid, err := deadlockWait(t, true)
+	if err != nil {
+		return false
+	}
+	t.lock.lock(nil)
+	deadlockAcquired(t, gid, true)
+	t.locked()
 	return true
 }
 
-// Unlock releases the token lock
+// Unlock releases the token lock. It returns false, leaving the lock as
+// it is, on a violation (see UnlockErr).
 func (t *RiftToken) Unlock() bool {
-	if t.lockCount > 0 {
-		t.lockCount--
-		if t.lockCount == 0 {
-			t.ValidationBits &^= TokenLocked
-		}
-		t.lock.Unlock()
-		return true
+	return t.UnlockErr() == nil
+}
+
+// UnlockErr is Unlock returning a GovernanceError on failure: a
+// CodeNotLockHolder violation when the token is not locked, or, with
+// SetLockOwnership on, when another goroutine locked it. The error goes
+// to the violation handler first, so ViolationPanic makes misuse panic.
+func (t *RiftToken) UnlockErr() error {
+	if t.lockCount == 0 {
+		return t.violation(t.located(govErr(CodeNotLockHolder, "unlock", "token not locked")))
+	}
+	if err := ownerReleasing(t, true); err != nil {
+		return t.violation(t.located(err))
 	}
-	return false
+	t.lockCount--
+	if t.lockCount == 0 {
+		t.ClearBit(TokenLocked)
+		t.unbindSpan()
+	}
+	deadlockReleased(t, true)
+	t.lock.unlock()
+	auditEmit(AuditUnlock, t, "")
+	return nil
 }
 
-// RLock acquires a read lock
+// RLock acquires a read lock. Like Lock, it returns false only when
+// deadlock detection refuses a wait that would complete a cycle.
 func (t *RiftToken) RLock() bool {
-	t.lock.RLock()
+	gid, err := deadlockWait(t, false)
+	if err != nil {
+		return false
+	}
+	t.lock.rlock(nil)
+	deadlockAcquired(t, gid, false)
+	ownerAcquired(t, false)
 	return true
 }
 
-// RUnlock releases a read lock
+// RUnlock releases a read lock. It returns false, leaving the lock as it
+// is, on a violation (see RUnlockErr).
 func (t *RiftToken) RUnlock() bool {
-	t.lock.RUnlock()
-	return true
+	return t.RUnlockErr() == nil
+}
+
+// RUnlockErr is RUnlock returning a GovernanceError on failure: a
+// CodeNotLockHolder violation when the token is not read-locked, or, with
+// SetLockOwnership on, when the calling goroutine holds none of its read
+// locks. Like UnlockErr, it reports to the violation handler first.
+func (t *RiftToken) RUnlockErr() error {
+	if t.lock.readCount() == 0 {
+		return t.violation(t.located(govErr(CodeNotLockHolder, "runlock", "token not read-locked")))
+	}
+	if err := ownerReleasing(t, false); err != nil {
+		return t.violation(t.located(err))
+	}
+	if !t.lock.runlock() {
+		// Another RUnlock released the last read lock since the check
+		return t.violation(t.located(govErr(CodeNotLockHolder, "runlock", "token not read-locked")))
+	}
+	deadlockReleased(t, false)
+	return nil
 }
 
 // Validate validates the token against governance policy
 func (t *RiftToken) Validate() bool {
+	return t.ValidateErr() == nil
+}
+
+// ValidateErr validates the token, returning a GovernanceError on failure
+func (t *RiftToken) ValidateErr() error {
+	err := t.validationError()
+	if err == nil {
+		err = t.admitQuota()
+	}
+	if err != nil {
+		t.located(err)
+		auditEmit(AuditValidateFail, t, err.Error())
+		t.traceValidate(err)
+		t.fireValidate(err)
+		return t.violation(err)
+	}
+
+	// Mark as governed
+	t.SetBit(TokenGoverned)
+	t.traceValidate(nil)
+	t.fireValidate(nil)
+	publishEvent(EventValidated, t, "")
+	return nil
+}
+
+// validationError returns why the token fails governance, or nil
+func (t *RiftToken) validationError() *GovernanceError {
 	// Check ALLOCATED bit
-	if t.ValidationBits&TokenAllocated == 0 {
-		return false
+	if !t.HasBit(TokenAllocated) {
+		return govErr(CodeNotAllocated, "validate", "token not allocated")
+	}
+	if err := t.expiryError(); err != nil {
+		return err
 	}
 
 	// Memory span must exist and be valid
 	if t.Memory == nil || t.Memory.Alignment == 0 {
-		return false
+		return govErr(CodeNoMemory, "validate", "memory span missing or unaligned")
+	}
+	if t.Memory.Released() {
+		return govErr(CodeNoMemory, "validate", "memory span released")
 	}
 
 	// Validate alignment
 	if !t.Memory.ValidateAlignment() {
-		return false
+		return govErr(CodeBadAlignment, "validate", "alignment %d is not a power of 2", t.Memory.Alignment)
+	}
+	if err := t.Memory.cacheLineError("validate"); err != nil {
+		return err
 	}
 
 	// Type-specific validation
 	switch t.Type {
 	case TokenGoInt, TokenGoFloat:
 		// Numeric types must have initialized value
-		if t.ValidationBits&TokenInitialized == 0 {
-			return false
+		if !t.HasBit(TokenInitialized) {
+			return govErr(CodeNotInitialized, "validate", "numeric token not initialized")
 		}
 	case TokenQGoInt:
 		// Quantum tokens need states if superposed
-		if t.ValidationBits&TokenSuperposed != 0 {
+		if t.HasBit(TokenSuperposed) {
 			if len(t.SuperposedStates) == 0 {
-				return false
+				return govErr(CodeNoStates, "validate", "superposed token has no states")
 			}
 		}
 	}
 
-	// Mark as governed
-	t.ValidationBits |= TokenGoverned
-	return true
+	// Superposed amplitudes must be a normalized vector
+	if t.HasBit(TokenSuperposed) {
+		if err := amplitudeError("validate", t.Amplitudes, len(t.SuperposedStates)); err != nil {
+			return err
+		}
+	}
+
+	// The value must satisfy the token's constraints
+	if t.HasBit(TokenInitialized) {
+		if err := t.constraintError("validate", t.readSource().snapshotValue()); err != nil {
+			return err
+		}
+	}
+	return t.checkIntegrity()
 }
 
-// Superpose puts the token into quantum superposition
+// Superpose puts the token into quantum superposition. Amplitudes that are
+// not normalized are stored, refused or normalized according to
+// SetAmplitudeNormalization. A superposition
+// whose entropy reaches the entropy gate's decoherence threshold collapses
+// immediately (see SetEntropyGate).
 func (t *RiftToken) Superpose(states []*RiftToken, amplitudes []float64) bool {
+	return t.SuperposeErr(states, amplitudes) == nil
+}
+
+// SuperposeErr is Superpose returning a GovernanceError on failure
+func (t *RiftToken) SuperposeErr(states []*RiftToken, amplitudes []float64) error {
 	if len(states) == 0 {
-		return false
+		return govErr(CodeNoStates, "superpose", "no states given")
+	}
+	if len(amplitudes) > 0 && len(amplitudes) != len(states) {
+		return govErr(CodeAmplitudeMismatch, "superpose", "%d amplitudes for %d states", len(amplitudes), len(states))
+	}
+	if len(amplitudes) > 0 {
+		var err error
+		if amplitudes, err = superposeAmplitudes(amplitudes); err != nil {
+			return err
+		}
 	}
 
 	t.SuperposedStates = states
 	t.SuperpositionCount = uint32(len(states))
+	t.Phases = nil
+	t.joint = nil
 
 	if len(amplitudes) > 0 {
 		t.Amplitudes = amplitudes
@@ -261,58 +530,179 @@ func (t *RiftToken) Superpose(states []*RiftToken, amplitudes []float64) bool {
 		}
 	}
 
-	t.ValidationBits |= TokenSuperposed
-	return true
+	t.SetBit(TokenSuperposed)
+	auditEmit(AuditSuperpose, t, "")
+	t.decohere()
+	return nil
 }
 
 // EntangleWith creates entanglement with another token
 func (t *RiftToken) EntangleWith(other *RiftToken, entanglementID uint32) bool {
+	return t.EntangleWithErr(other, entanglementID) == nil
+}
+
+// EntangleWithErr is EntangleWith returning a GovernanceError on failure
+func (t *RiftToken) EntangleWithErr(other *RiftToken, entanglementID uint32) error {
+	if other == nil {
+		return govErr(CodeNilToken, "entangle", "partner token is nil")
+	}
+	if other == t {
+		return govErr(CodeSelfEntangle, "entangle", "token cannot entangle with itself")
+	}
+
 	t.EntangledWith = append(t.EntangledWith, other)
 	t.EntanglementCount++
 	t.EntanglementID = entanglementID
-	t.ValidationBits |= TokenEntangled
-	other.ValidationBits |= TokenEntangled
-	return true
+	registerEntanglement(t, entanglementID)
+	t.SetBit(TokenEntangled)
+	other.SetBit(TokenEntangled)
+	auditEmit(AuditEntangle, t, "")
+	return nil
 }
 
 // Collapse collapses superposition to single state
 func (t *RiftToken) Collapse(selectedIndex uint32) bool {
-	if t.ValidationBits&TokenSuperposed == 0 {
-		return false
+	return t.CollapseErr(selectedIndex) == nil
+}
+
+// CollapseErr is Collapse returning a GovernanceError on failure
+func (t *RiftToken) CollapseErr(selectedIndex uint32) error {
+	if !t.HasBit(TokenSuperposed) {
+		return govErr(CodeNotSuperposed, "collapse", "token not in superposition")
+	}
+	if int(selectedIndex) >= len(t.SuperposedStates) {
+		return govErr(CodeIndexOutOfRange, "collapse", "index %d out of %d states", selectedIndex, len(t.SuperposedStates))
+	}
+	if err := t.checkEntropyGate("collapse"); err != nil {
+		return err
+	}
+	if t.joint != nil {
+		return t.collapseJoint(selectedIndex)
 	}
+	t.collapse(selectedIndex, "")
+	return nil
+}
+
+// collapse replaces the superposition with the selected state, recording
+// detail in the audit event
+func (t *RiftToken) collapse(selectedIndex uint32, detail string) {
+	collapsed := t.SuperposedStates[selectedIndex]
+	t.traceCollapse(selectedIndex, len(t.SuperposedStates), detail)
+	old := t.Value
+	t.storeValue(collapsed.Value)
+	t.Type = collapsed.Type
+	t.SuperposedStates = nil
+	t.Amplitudes = nil
+	t.Phases = nil
+	t.SuperpositionCount = 0
+	t.joint = nil
+	t.ClearBit(TokenSuperposed)
+	// The token now holds the state's value, so it is as initialized as
+	// the state was
+	if collapsed.HasBit(TokenInitialized) {
+		t.SetBit(TokenInitialized)
+	}
+	t.reseal()
+	auditEmit(AuditCollapse, t, detail)
+	t.fireCollapse(collapsed, selectedIndex)
+	publishEvent(EventCollapsed, t, "")
+	t.fireChange(old, t.Value)
+}
+
+// Measure collapses superposition by sampling a state with probability
+// |amplitude|², returning the observed state
+func (t *RiftToken) Measure() (*RiftToken, error) {
+	return t.MeasureWith(nil)
+}
+
+// MeasureWith is Measure using the given RNG (nil uses the package RNG; see
+// SetRandSource), for reproducible measurements
+func (t *RiftToken) MeasureWith(r *rand.Rand) (*RiftToken, error) {
+	if !t.HasBit(TokenSuperposed) {
+		return nil, govErr(CodeNotSuperposed, "measure", "token not in superposition")
+	}
+	if len(t.SuperposedStates) == 0 {
+		return nil, govErr(CodeNoStates, "measure", "superposed token has no states")
+	}
+	if err := t.checkEntropyGate("measure"); err != nil {
+		return nil, err
+	}
+	if t.joint != nil {
+		return t.measureJoint(r)
+	}
+	return t.measure(r, "")
+}
+
+// measure samples a state and collapses to it, skipping the entropy gate
+func (t *RiftToken) measure(r *rand.Rand, detail string) (*RiftToken, error) {
+	weights := make([]float64, len(t.SuperposedStates))
+	total := 0.0
+	for i := range weights {
+		if len(t.Amplitudes) == len(t.SuperposedStates) {
+			weights[i] = t.Amplitudes[i] * t.Amplitudes[i]
+		} else {
+			weights[i] = 1.0
+		}
+		total += weights[i]
+	}
+	if total <= 0 {
+		return nil, govErr(CodeZeroProbability, "measure", "superposed token has zero total probability")
+	}
+
+	x := randFloat64(r) * total
+	selected := len(weights) - 1
+	for i, w := range weights {
+		if x < w {
+			selected = i
+			break
+		}
+		x -= w
+	}
+
+	state := t.SuperposedStates[selected]
+	t.collapse(uint32(selected), detail)
+	return state, nil
+}
 
-	if int(selectedIndex) < len(t.SuperposedStates) {
-		collapsed := t.SuperposedStates[selectedIndex]
-		t.Value = collapsed.Value
-		t.Type = collapsed.Type
-		t.SuperposedStates = nil
-		t.Amplitudes = nil
-		t.SuperpositionCount = 0
-		t.ValidationBits &^= TokenSuperposed
-		return true
+// SetBit atomically sets the given validation bits
+func (t *RiftToken) SetBit(bits uint32) {
+	t.ValidationBits.Or(bits)
+	if sh := t.shared.Load(); sh != nil {
+		sh.storeBits(t)
 	}
-	return false
+}
+
+// ClearBit atomically clears the given validation bits
+func (t *RiftToken) ClearBit(bits uint32) {
+	t.ValidationBits.And(^bits)
+	if sh := t.shared.Load(); sh != nil {
+		sh.storeBits(t)
+	}
+}
+
+// HasBit reports whether all of the given validation bits are set
+func (t *RiftToken) HasBit(bits uint32) bool {
+	return t.ValidationBits.Load()&bits == bits
 }
 
 // IsValid checks if token is valid and governed
 func (t *RiftToken) IsValid() bool {
-	return t.ValidationBits&TokenInitialized != 0 &&
-		t.ValidationBits&TokenGoverned != 0
+	return t.HasBit(TokenInitialized | TokenGoverned)
 }
 
 // IsLocked checks if token is locked
 func (t *RiftToken) IsLocked() bool {
-	return t.ValidationBits&TokenLocked != 0
+	return t.HasBit(TokenLocked)
 }
 
 // IsSuperposed checks if token is in superposition
 func (t *RiftToken) IsSuperposed() bool {
-	return t.ValidationBits&TokenSuperposed != 0
+	return t.HasBit(TokenSuperposed)
 }
 
 // IsEntangled checks if token is entangled
 func (t *RiftToken) IsEntangled() bool {
-	return t.ValidationBits&TokenEntangled != 0
+	return t.HasBit(TokenEntangled)
 }
 
 // String returns string representation
@@ -324,10 +714,15 @@ func (t *RiftToken) String() string {
 // Rift Object Base
 // ============================================================================
 
-// RiftObject is the base class for Go objects governed by Rift policies
+// RiftObject is the base class for Go objects governed by Rift policies.
+// Embed it in a struct and call Govern to give the struct's fields tokens.
 type RiftObject struct {
 	memory *RiftMemorySpan
 	token  *RiftToken
+
+	// Field governance (see Govern)
+	target reflect.Value
+	fields []objectField
 }
 
 // NewRiftObject creates a new Rift object
@@ -364,33 +759,43 @@ func Superpose(states ...interface{}) *RiftToken {
 	// Create child tokens for each state
 	stateTokens := make([]*RiftToken, len(states))
 	for i, state := range states {
-		stateMemory := NewRiftMemorySpan(SpanFixed, 64)
-		stateToken := NewRiftToken(TokenGoInt, stateMemory)
-
-		switch v := state.(type) {
-		case int:
-			stateToken.Value.IntVal = int64(v)
-		case int64:
-			stateToken.Value.IntVal = v
-		case float64:
-			stateToken.Value.FloatVal = v
-		case string:
-			stateToken.Value.StringVal = v
-		default:
-			stateToken.Value.PtrVal = state
-		}
-
-		stateToken.ValidationBits |= TokenInitialized
-		stateTokens[i] = stateToken
+		stateTokens[i] = newStateToken(state)
 	}
 
 	token.Superpose(stateTokens, nil)
 	return token
 }
 
-// Entangle entangles two tokens
+// newStateToken creates the token of one superposed state: ints are
+// GoInt, float64 GoFloat and strings GoString states, and other values are
+// kept in PtrVal
+func newStateToken(state interface{}) *RiftToken {
+	stateMemory := NewRiftMemorySpan(SpanFixed, 64)
+	stateToken := NewRiftToken(TokenGoInt, stateMemory)
+
+	switch v := state.(type) {
+	case int:
+		stateToken.Value.IntVal = int64(v)
+	case int64:
+		stateToken.Value.IntVal = v
+	case float64:
+		stateToken.Type = TokenGoFloat
+		stateToken.Value.FloatVal = v
+	case string:
+		stateToken.Type = TokenGoString
+		stateToken.Value.StringVal = v
+	default:
+		stateToken.Value.PtrVal = state
+	}
+
+	stateToken.SetBit(TokenInitialized)
+	return stateToken
+}
+
+// Entangle entangles two tokens under a newly allocated ID (see
+// NewEntanglementID), returning it
 func Entangle(a, b *RiftToken) uint32 {
-	entanglementID := uint32(rand.Intn(1000000))
+	entanglementID := NewEntanglementID()
 	a.EntangleWith(b, entanglementID)
 	b.EntangleWith(a, entanglementID)
 	return entanglementID
@@ -401,21 +806,17 @@ func Collapse(token *RiftToken, selectedIndex uint32) bool {
 	return token.Collapse(selectedIndex)
 }
 
+// Measure collapses a superposed token by amplitude-weighted sampling
+func Measure(token *RiftToken) (*RiftToken, error) {
+	return token.Measure()
+}
+
 // CalculateEntropy calculates Shannon entropy from amplitudes
 func CalculateEntropy(token *RiftToken) float64 {
 	if len(token.Amplitudes) == 0 {
 		return 0.0
 	}
-
-	entropy := 0.0
-	for _, amp := range token.Amplitudes {
-		p := amp * amp
-		if p > 0 {
-			entropy -= p * math.Log2(p)
-		}
-	}
-
-	return entropy
+	return entropyBits(token.Amplitudes)
 }
 
 // ============================================================================
@@ -431,7 +832,9 @@ func WithToken(token *RiftToken, fn func(*RiftToken) error) error {
 	return fmt.Errorf("failed to acquire token lock")
 }
 
-// Var creates a Rift-governed variable
+// Var creates a Rift-governed variable registered in DefaultRegistry. Its
+// token is a TokenGoInt whatever the value; Int, Float, Str, Slice and Map
+// create tokens of the value's type.
 func Var(name string, value interface{}) *RiftToken {
 	memory := NewRiftMemorySpan(SpanFixed, 64)
 	token := NewRiftToken(TokenGoInt, memory)
@@ -449,27 +852,47 @@ func Var(name string, value interface{}) *RiftToken {
 		token.Value.PtrVal = value
 	}
 
-	token.ValidationBits |= TokenInitialized
+	token.SetBit(TokenInitialized)
 	token.Validate()
+	DefaultRegistry.Register(name, token)
 	return token
 }
 
-// Func creates a Rift-governed function
+// Func creates a Rift-governed function registered in DefaultRegistry
 func Func(name string, fn interface{}) *RiftToken {
 	memory := NewRiftMemorySpan(SpanRow, 4096)
 	token := NewRiftToken(TokenGoChan, memory)
 	token.Value.PtrVal = fn
-	token.ValidationBits |= TokenInitialized
+	token.SetBit(TokenInitialized)
 	token.Validate()
+	DefaultRegistry.Register(name, token)
 	return token
 }
 
-// Go creates a Rift-governed goroutine
+// Go creates a Rift-governed goroutine. It runs in a Scope whose Token is
+// the goroutine's own token and whose Parent, Policy and Audit sink come
+// from the calling goroutine's scope (see Current).
 func Go(fn func()) {
+	// The goroutine's own stack starts in this package, so take the
+	// source location from Go's caller
+	var file string
+	var line int
+	if SourceCapture() {
+		file, line = callerOutsidePackage()
+	}
+	scope := Scope{}
+	if parent := Current(); parent != nil {
+		scope = Scope{Parent: parent.Token, Policy: parent.Policy, Audit: parent.Audit}
+	}
 	go func() {
 		// Wrap goroutine with Rift governance
 		memory := NewRiftMemorySpan(SpanFixed, 4096)
 		token := NewRiftToken(TokenGoChan, memory)
+		token.SourceFile, token.SourceLine = file, uint32(line)
+		scope.Token = token
+		gid := goroutineID()
+		setScope(gid, &scope)
+		defer setScope(gid, nil)
 		token.Validate()
 
 		defer func() {
@@ -484,9 +907,42 @@ func Go(fn func()) {
 }
 
 // ============================================================================
-// Initialization
+// Randomness
 // ============================================================================
 
-func init() {
-	rand.Seed(time.Now().UnixNano())
+// packageRand is the RNG behind Measure when none is injected. It is
+// separate from the global math/rand source.
+var (
+	packageRand     = rand.New(rand.NewSource(time.Now().UnixNano()))
+	packageRandLock sync.Mutex
+)
+
+// SetRandSource makes src the source of the package's randomness:
+// measurement outcomes (Measure, decoherence and entangled registers). nil
+// restores a time-seeded source. src need not be
+// safe for concurrent use.
+func SetRandSource(src rand.Source) {
+	if src == nil {
+		src = rand.NewSource(time.Now().UnixNano())
+	}
+	packageRandLock.Lock()
+	defer packageRandLock.Unlock()
+	packageRand = rand.New(src)
+}
+
+// SetDeterministic seeds the package's randomness with seed, so that a
+// program making the same calls in the same order sees the same
+// measurements on every run
+func SetDeterministic(seed int64) {
+	SetRandSource(rand.NewSource(seed))
+}
+
+// randFloat64 draws from r, or from the package RNG when r is nil
+func randFloat64(r *rand.Rand) float64 {
+	if r != nil {
+		return r.Float64()
+	}
+	packageRandLock.Lock()
+	defer packageRandLock.Unlock()
+	return packageRand.Float64()
 }
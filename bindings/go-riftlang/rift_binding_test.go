@@ -0,0 +1,158 @@
+package rift
+
+import (
+	"math/rand"
+	"slices"
+	"sync"
+	"testing"
+)
+
+// measureRun measures n fresh superpositions of four weighted states with
+// measure, returning the observed values
+func measureRun(t *testing.T, n int, measure func(*RiftToken) (*RiftToken, error)) []int64 {
+	t.Helper()
+	out := make([]int64, n)
+	for i := range out {
+		tok := Superpose(0, 1, 2, 3)
+		if err := tok.SuperposeErr(tok.SuperposedStates, []float64{0.1, 0.3, 0.5, 0.8}); err != nil {
+			t.Fatal(err)
+		}
+		state, err := measure(tok)
+		if err != nil {
+			t.Fatal(err)
+		}
+		out[i] = state.Value.IntVal
+	}
+	return out
+}
+
+func TestMeasureWithSeedRepeats(t *testing.T) {
+	run := func(seed int64) []int64 {
+		r := rand.New(rand.NewSource(seed))
+		return measureRun(t, 64, func(tok *RiftToken) (*RiftToken, error) { return tok.MeasureWith(r) })
+	}
+	first, second := run(42), run(42)
+	if !slices.Equal(first, second) {
+		t.Errorf("seed 42 measured\n%v\nthen\n%v", first, second)
+	}
+	if other := run(7); slices.Equal(first, other) {
+		t.Errorf("seeds 42 and 7 measured the same %v", first)
+	}
+}
+
+func TestSetDeterministicRepeats(t *testing.T) {
+	t.Cleanup(func() { SetRandSource(nil) })
+	run := func() []int64 {
+		SetDeterministic(42)
+		return measureRun(t, 64, Measure)
+	}
+	first, second := run(), run()
+	if !slices.Equal(first, second) {
+		t.Errorf("SetDeterministic(42) measured\n%v\nthen\n%v", first, second)
+	}
+
+	// The injected RNG and the package one draw the same sequence
+	r := rand.New(rand.NewSource(42))
+	injected := measureRun(t, 64, func(tok *RiftToken) (*RiftToken, error) { return tok.MeasureWith(r) })
+	if !slices.Equal(first, injected) {
+		t.Errorf("package RNG measured\n%v\ninjected RNG\n%v", first, injected)
+	}
+}
+
+// TestValidationBitsConcurrent drives one token from many goroutines at
+// once: writes, locking, validation and bit flips must not race (run with
+// -race) or lose bits
+func TestValidationBitsConcurrent(t *testing.T) {
+	tok := NewRiftToken(TokenGoInt, NewRiftMemorySpan(SpanFixed, 64))
+	if err := tok.SetValue(RiftTokenValue{IntVal: 0}); err != nil {
+		t.Fatal(err)
+	}
+
+	const n = 200
+	var wg sync.WaitGroup
+	for g := range 4 {
+		wg.Go(func() {
+			for i := range n {
+				if err := tok.SetValue(RiftTokenValue{IntVal: int64(g*n + i)}); err != nil {
+					t.Error(err)
+					return
+				}
+			}
+		})
+		wg.Go(func() {
+			for range n {
+				if !tok.Lock() {
+					t.Error("Lock refused")
+					return
+				}
+				if !tok.HasBit(TokenLocked) {
+					t.Error("TokenLocked clear while locked")
+				}
+				tok.Unlock()
+			}
+		})
+		wg.Go(func() {
+			for range n {
+				if !tok.Validate() {
+					t.Error(tok.ValidateErr())
+					return
+				}
+			}
+		})
+		wg.Go(func() {
+			for range n {
+				tok.SetBit(TokenPersistent)
+				tok.HasBit(TokenAllocated | TokenInitialized)
+				tok.ClearBit(TokenPersistent)
+			}
+		})
+	}
+	wg.Wait()
+
+	if want := TokenAllocated | TokenInitialized | TokenGoverned; !tok.HasBit(want) {
+		t.Errorf("bits 0x%02x lost some of 0x%02x", tok.ValidationBits.Load(), want)
+	}
+	if tok.HasBit(TokenLocked) || tok.HasBit(TokenPersistent) {
+		t.Errorf("bits 0x%02x kept a transient bit", tok.ValidationBits.Load())
+	}
+}
+
+// TestAccessMaskConcurrent grants and revokes update access while other
+// goroutines use the token: checks must not race with the mask changes (run
+// with -race), and a denied write must be a permission error
+func TestAccessMaskConcurrent(t *testing.T) {
+	span := NewRiftMemorySpan(SpanFixed, 64)
+	tok := NewRiftToken(TokenGoInt, span)
+	if err := tok.SetValue(RiftTokenValue{IntVal: 1}); err != nil {
+		t.Fatal(err)
+	}
+
+	const n = 500
+	var wg sync.WaitGroup
+	wg.Go(func() {
+		for range n {
+			span.Revoke(AccessUpdate)
+			span.Grant(AccessUpdate)
+		}
+	})
+	for g := range 4 {
+		wg.Go(func() {
+			for i := range n {
+				if _, err := tok.GetValue(); err != nil {
+					t.Error(err)
+					return
+				}
+				err := tok.SetValue(RiftTokenValue{IntVal: int64(g*n + i)})
+				if err != nil && ErrorCodeOf(err) != CodePermissionDenied {
+					t.Error(err)
+					return
+				}
+			}
+		})
+	}
+	wg.Wait()
+
+	if !span.Allows(AccessCRUD) {
+		t.Errorf("mask 0x%02x, want 0x%02x granted", span.Mask(), AccessCRUD)
+	}
+}
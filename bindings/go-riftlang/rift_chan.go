@@ -0,0 +1,222 @@
+// go/target/rift_chan.go
+// Governed Channels (RiftChan[T]) - Go Implementation
+
+package rift
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// ============================================================================
+// Channel Metrics
+// ============================================================================
+
+// ChanMetrics is a snapshot of a governed channel's traffic
+type ChanMetrics struct {
+	Sends        uint64
+	Receives     uint64
+	Depth        int
+	MaxDepth     int
+	SendBlocked  time.Duration
+	RecvBlocked  time.Duration
+	Capacity     int
+	Closed       bool
+	AccessDenied uint64
+}
+
+// chanState is shared by a channel and its restricted views
+type chanState struct {
+	lock        sync.Mutex
+	sends       uint64
+	receives    uint64
+	maxDepth    int
+	sendBlocked time.Duration
+	recvBlocked time.Duration
+	denied      uint64
+	closed      atomic.Bool
+}
+
+// ============================================================================
+// RiftChan
+// ============================================================================
+
+// RiftChan wraps a Go channel with Rift governance. Sending requires
+// AccessUpdate, receiving requires AccessRead and closing requires
+// AccessDelete on the channel token's memory span.
+type RiftChan[T any] struct {
+	ch    chan T
+	token *RiftToken
+	state *chanState
+}
+
+// NewRiftChan creates a governed channel with the given buffer capacity
+func NewRiftChan[T any](capacity int) *RiftChan[T] {
+	var zero T
+	elem := uint64(reflect.TypeOf(&zero).Elem().Size())
+	if elem == 0 {
+		elem = 1
+	}
+
+	memory := NewRiftMemorySpan(SpanRow, elem*uint64(capacity+1))
+	token := NewRiftToken(TokenGoChan, memory)
+	token.Value.PtrVal = make(chan T, capacity)
+	token.SetBit(TokenInitialized)
+	token.Validate()
+
+	return &RiftChan[T]{
+		ch:    token.Value.PtrVal.(chan T),
+		token: token,
+		state: &chanState{},
+	}
+}
+
+// Token returns the governance token of the channel
+func (c *RiftChan[T]) Token() *RiftToken {
+	return c.token
+}
+
+// SendOnly returns a view of the channel that cannot receive or close
+func (c *RiftChan[T]) SendOnly() *RiftChan[T] {
+	return c.restricted(AccessRead | AccessDelete)
+}
+
+// RecvOnly returns a view of the channel that cannot send or close
+func (c *RiftChan[T]) RecvOnly() *RiftChan[T] {
+	return c.restricted(AccessUpdate | AccessDelete)
+}
+
+// restricted returns a view sharing the channel with bits removed from the mask
+func (c *RiftChan[T]) restricted(revoke uint32) *RiftChan[T] {
+	m := c.token.Memory
+	span := &RiftMemorySpan{
+		Type:       m.Type,
+		Bytes:      m.Bytes,
+		Alignment:  m.Alignment,
+		Open:       m.Open,
+		Direction:  m.Direction,
+		AccessMask: m.Mask() &^ revoke,
+	}
+
+	token := NewRiftToken(c.token.Type, span)
+	token.Value = c.token.Value
+	token.SetBit(TokenInitialized)
+	token.Validate()
+
+	return &RiftChan[T]{ch: c.ch, token: token, state: c.state}
+}
+
+// check verifies the channel token is governed and allows the operation
+func (c *RiftChan[T]) check(op string, bit uint32) error {
+	if !c.token.HasBit(TokenGoverned) {
+		return fmt.Errorf("channel token not governed")
+	}
+	if c.token.Memory == nil || !c.token.Memory.Allows(bit) {
+		c.state.lock.Lock()
+		c.state.denied++
+		c.state.lock.Unlock()
+		return fmt.Errorf("channel access mask forbids %s", op)
+	}
+	return nil
+}
+
+// Send sends a value, blocking while the buffer is full
+func (c *RiftChan[T]) Send(v T) (err error) {
+	if err := c.check("send", AccessUpdate); err != nil {
+		return err
+	}
+	if c.state.closed.Load() {
+		return fmt.Errorf("send on closed channel")
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("send on closed channel")
+		}
+	}()
+
+	var blocked time.Duration
+	select {
+	case c.ch <- v:
+	default:
+		start := clockNow()
+		c.ch <- v
+		blocked = clockSince(start)
+	}
+
+	depth := len(c.ch)
+	c.state.lock.Lock()
+	c.state.sends++
+	c.state.sendBlocked += blocked
+	if depth > c.state.maxDepth {
+		c.state.maxDepth = depth
+	}
+	c.state.lock.Unlock()
+	return nil
+}
+
+// Recv receives a value; ok is false once the channel is closed and drained
+func (c *RiftChan[T]) Recv() (v T, ok bool, err error) {
+	if err := c.check("receive", AccessRead); err != nil {
+		return v, false, err
+	}
+
+	var blocked time.Duration
+	select {
+	case v, ok = <-c.ch:
+	default:
+		start := clockNow()
+		v, ok = <-c.ch
+		blocked = clockSince(start)
+	}
+
+	c.state.lock.Lock()
+	if ok {
+		c.state.receives++
+	}
+	c.state.recvBlocked += blocked
+	c.state.lock.Unlock()
+	return v, ok, nil
+}
+
+// Close closes the channel
+func (c *RiftChan[T]) Close() error {
+	if err := c.check("close", AccessDelete); err != nil {
+		return err
+	}
+	if !c.state.closed.CompareAndSwap(false, true) {
+		return fmt.Errorf("channel already closed")
+	}
+	close(c.ch)
+	return nil
+}
+
+// Len returns the current queue depth
+func (c *RiftChan[T]) Len() int {
+	return len(c.ch)
+}
+
+// Cap returns the buffer capacity
+func (c *RiftChan[T]) Cap() int {
+	return cap(c.ch)
+}
+
+// Metrics returns a snapshot of channel metrics
+func (c *RiftChan[T]) Metrics() ChanMetrics {
+	c.state.lock.Lock()
+	defer c.state.lock.Unlock()
+
+	return ChanMetrics{
+		Sends:        c.state.sends,
+		Receives:     c.state.receives,
+		Depth:        len(c.ch),
+		MaxDepth:     c.state.maxDepth,
+		SendBlocked:  c.state.sendBlocked,
+		RecvBlocked:  c.state.recvBlocked,
+		Capacity:     cap(c.ch),
+		Closed:       c.state.closed.Load(),
+		AccessDenied: c.state.denied,
+	}
+}
@@ -0,0 +1,330 @@
+// go/target/riftbench/riftbench.go
+// Benchmark and Profiling Harness for Rift Governance - Go Implementation
+
+// Package riftbench holds the standard benchmarks for Rift governance
+// overhead: token allocation, lock contention, validation, superposition
+// and collapse, amplitude math over superpositions of 2^17 and 2^20
+// states, and pattern matching at 10, 100 and 10k pairs.
+//
+// Each benchmark body runs under runtime/pprof labels rift_bench (the
+// benchmark name) and rift_op (the governance operation), so CPU profiles
+// can be filtered with `go tool pprof -tagfocus rift_op=lock`. Run the
+// suite from a test file:
+//
+//	func BenchmarkRift(b *testing.B) { riftbench.RunSub(b, nil) }
+//
+// or standalone, in `go test -bench` output format for benchstat, with
+// Run or the riftbench command.
+package riftbench
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"math/bits"
+	"regexp"
+	"runtime"
+	"runtime/pprof"
+	"testing"
+
+	rift "github.com/obinexus/riftlang/bindings/go-riftlang"
+)
+
+// Governance operations, the values of the rift_op profile label
+const (
+	OpAlloc     = "alloc"
+	OpLock      = "lock"
+	OpValidate  = "validate"
+	OpQuantum   = "quantum"
+	OpAmplitude = "amplitude"
+	OpMatch     = "match"
+)
+
+// StateCounts are the superposition sizes the amplitude benchmarks cover
+var StateCounts = []int{1 << 17, 1 << 20}
+
+// PairCounts are the pattern engine sizes the match benchmarks cover
+var PairCounts = []int{10, 100, 10000}
+
+// Benchmark is a named governance benchmark
+type Benchmark struct {
+	Name string // without the "Benchmark" prefix
+	Op   string // rift_op profile label
+	F    func(b *testing.B)
+}
+
+// Result is the outcome of one benchmark run by Run
+type Result struct {
+	Benchmark Benchmark
+	testing.BenchmarkResult
+}
+
+// ============================================================================
+// Suite
+// ============================================================================
+
+// Benchmarks returns the standard suite in a stable order
+func Benchmarks() []Benchmark {
+	bs := []Benchmark{
+		{"TokenAlloc", OpAlloc, benchTokenAlloc},
+		{"TokenSetValue", OpAlloc, benchTokenSetValue},
+		{"LockUncontended", OpLock, benchLockUncontended},
+		{"LockContended", OpLock, benchLockContended},
+		{"RLockContended", OpLock, benchRLockContended},
+		{"Validate", OpValidate, benchValidate},
+		{"ValidateAgainst", OpValidate, benchValidateAgainst},
+		{"SuperposeCollapse", OpQuantum, benchSuperposeCollapse},
+		{"SuperposeMeasure", OpQuantum, benchSuperposeMeasure},
+	}
+	for _, n := range StateCounts {
+		bs = append(bs,
+			Benchmark{fmt.Sprintf("Entropy/states=%d", n), OpAmplitude, benchEntropy(n)},
+			Benchmark{fmt.Sprintf("AmplitudeNorm/states=%d", n), OpAmplitude, benchAmplitudeNorm(n)},
+			Benchmark{fmt.Sprintf("Normalize/states=%d", n), OpAmplitude, benchNormalize(n)},
+			Benchmark{fmt.Sprintf("Hadamard/states=%d", n), OpAmplitude, benchHadamard(n)},
+		)
+	}
+	for _, n := range PairCounts {
+		bs = append(bs,
+			Benchmark{fmt.Sprintf("Match/pairs=%d/hit", n), OpMatch, benchMatch(n, true)},
+			Benchmark{fmt.Sprintf("Match/pairs=%d/miss", n), OpMatch, benchMatch(n, false)},
+		)
+	}
+	return bs
+}
+
+// Labeled wraps bm.F so its body runs under bm's pprof labels
+func Labeled(bm Benchmark) func(b *testing.B) {
+	return func(b *testing.B) {
+		labels := pprof.Labels("rift_bench", bm.Name, "rift_op", bm.Op)
+		pprof.Do(context.Background(), labels, func(context.Context) {
+			bm.F(b)
+		})
+	}
+}
+
+// RunSub runs the benchmarks whose names match filter (all if nil) as
+// sub-benchmarks of b
+func RunSub(b *testing.B, filter *regexp.Regexp) {
+	for _, bm := range Benchmarks() {
+		if filter == nil || filter.MatchString(bm.Name) {
+			b.Run(bm.Name, Labeled(bm))
+		}
+	}
+}
+
+// Run runs the benchmarks whose names match filter (all if nil) outside
+// `go test`, writing a line per benchmark to w in benchmark output format
+func Run(w io.Writer, filter *regexp.Regexp) []Result {
+	var results []Result
+	procs := runtime.GOMAXPROCS(0)
+	for _, bm := range Benchmarks() {
+		if filter != nil && !filter.MatchString(bm.Name) {
+			continue
+		}
+		r := testing.Benchmark(Labeled(bm))
+		results = append(results, Result{Benchmark: bm, BenchmarkResult: r})
+		if w != nil {
+			fmt.Fprintf(w, "Benchmark%s-%d\t%s\t%s\n", bm.Name, procs, r.String(), r.MemString())
+		}
+	}
+	return results
+}
+
+// ============================================================================
+// Benchmarks
+// ============================================================================
+
+// newIntToken returns an initialized GoInt token
+func newIntToken(v int64) *rift.RiftToken {
+	t := rift.NewRiftToken(rift.TokenGoInt, rift.NewRiftMemorySpan(rift.SpanFixed, 8))
+	t.SetValue(rift.RiftTokenValue{IntVal: v})
+	return t
+}
+
+func benchTokenAlloc(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		rift.NewRiftToken(rift.TokenGoInt, rift.NewRiftMemorySpan(rift.SpanFixed, 8))
+	}
+}
+
+func benchTokenSetValue(b *testing.B) {
+	b.ReportAllocs()
+	t := newIntToken(0)
+	for i := 0; i < b.N; i++ {
+		if err := t.SetValue(rift.RiftTokenValue{IntVal: int64(i)}); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func benchLockUncontended(b *testing.B) {
+	b.ReportAllocs()
+	t := newIntToken(0)
+	for i := 0; i < b.N; i++ {
+		t.Lock()
+		t.Unlock()
+	}
+}
+
+func benchLockContended(b *testing.B) {
+	b.ReportAllocs()
+	t := newIntToken(0)
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			t.Lock()
+			t.Unlock()
+		}
+	})
+}
+
+func benchRLockContended(b *testing.B) {
+	b.ReportAllocs()
+	t := newIntToken(0)
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			t.RLock()
+			t.RUnlock()
+		}
+	})
+}
+
+func benchValidate(b *testing.B) {
+	b.ReportAllocs()
+	t := newIntToken(42)
+	for i := 0; i < b.N; i++ {
+		if err := t.ValidateErr(); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func benchValidateAgainst(b *testing.B) {
+	b.ReportAllocs()
+	t := newIntToken(42)
+	p := rift.NewGovernancePolicy()
+	for i := 0; i < b.N; i++ {
+		if err := t.ValidateAgainst(p); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func benchSuperposeCollapse(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		t := rift.Superpose(1, 2, 3, 4)
+		if err := t.CollapseErr(uint32(i % 4)); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func benchSuperposeMeasure(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		t := rift.Superpose(1, 2, 3, 4)
+		if _, err := t.Measure(); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// newWideToken returns a token superposed over n states with unequal
+// amplitudes. The states share one value token, which the amplitude math
+// never reads.
+func newWideToken(b *testing.B, n int) *rift.RiftToken {
+	state := newIntToken(1)
+	states := make([]*rift.RiftToken, n)
+	amps := make([]float64, n)
+	for i := range states {
+		states[i] = state
+		amps[i] = float64(i%7 + 1)
+	}
+	amps, err := rift.NormalizeAmplitudes(amps)
+	if err != nil {
+		b.Fatal(err)
+	}
+	t := rift.NewRiftToken(rift.TokenQGoInt, rift.NewRiftMemorySpan(rift.SpanSuperposed, 64))
+	if err := t.SuperposeErr(states, amps); err != nil {
+		b.Fatal(err)
+	}
+	return t
+}
+
+func benchEntropy(n int) func(b *testing.B) {
+	return func(b *testing.B) {
+		t := newWideToken(b, n)
+		b.SetBytes(int64(n) * 8)
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			rift.CalculateEntropy(t)
+		}
+	}
+}
+
+func benchAmplitudeNorm(n int) func(b *testing.B) {
+	return func(b *testing.B) {
+		t := newWideToken(b, n)
+		b.SetBytes(int64(n) * 8)
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			rift.AmplitudeNorm(t.Amplitudes)
+		}
+	}
+}
+
+func benchNormalize(n int) func(b *testing.B) {
+	return func(b *testing.B) {
+		t := newWideToken(b, n)
+		b.SetBytes(int64(n) * 8)
+		b.ReportAllocs()
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			if _, err := rift.NormalizeAmplitudes(t.Amplitudes); err != nil {
+				b.Fatal(err)
+			}
+		}
+	}
+}
+
+// benchHadamard applies H to each qubit of an n-state register in turn
+func benchHadamard(n int) func(b *testing.B) {
+	return func(b *testing.B) {
+		t := newWideToken(b, n)
+		qubits := bits.Len(uint(n)) - 1
+		b.SetBytes(int64(n) * 16)
+		b.ReportAllocs()
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			if err := t.Hadamard(i % qubits); err != nil {
+				b.Fatal(err)
+			}
+		}
+	}
+}
+
+// benchMatch matches against an engine of n pairs; hit inputs match the
+// lowest-ranked pair, miss inputs match none
+func benchMatch(n int, hit bool) func(b *testing.B) {
+	return func(b *testing.B) {
+		engine := rift.NewPatternEngine("classical")
+		for i := 0; i < n; i++ {
+			if err := engine.AddPairErr(fmt.Sprintf(`^key%d=(\d+)$`, i), fmt.Sprintf("v%d:$1", i), uint32(i), false); err != nil {
+				b.Fatal(err)
+			}
+		}
+		input := fmt.Sprintf("key%d=12345", n-1)
+		if !hit {
+			input = "nokey=12345"
+		}
+		b.ReportAllocs()
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			if r := engine.Match(input); r.Matched != hit {
+				b.Fatalf("match %q: matched=%t, want %t", input, r.Matched, hit)
+			}
+		}
+	}
+}
@@ -0,0 +1,301 @@
+// go/target/riftcluster/riftcluster.go
+// Distributed Span Replication - Go Implementation
+
+// Package riftcluster replicates value changes of tokens on distributed
+// memory spans to peer nodes over gRPC. A token's EntanglementID is its
+// replication group key: tokens sharing an ID on different nodes are
+// replicas of one another.
+package riftcluster
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+
+	rift "github.com/obinexus/riftlang/bindings/go-riftlang"
+)
+
+// Consistency selects how many peers must acknowledge an update
+type Consistency int
+
+const (
+	Async  Consistency = iota // return after the local write
+	Quorum                    // wait for a majority of nodes, self included
+)
+
+// DefaultTimeout bounds replication of a single update
+const DefaultTimeout = 2 * time.Second
+
+// Options configures a Node
+type Options struct {
+	Consistency Consistency
+	Timeout     time.Duration
+
+	// OnError is called for replication failures that Set does not report,
+	// i.e. every failure in Async mode and late failures in Quorum mode
+	OnError func(peer string, err error)
+}
+
+// ============================================================================
+// Node
+// ============================================================================
+
+// group tracks the local replica of one replication group. Writes are
+// ordered by (seq, node) so every replica settles on the same last writer.
+type group struct {
+	token *rift.RiftToken
+	seq   uint64
+	node  string
+}
+
+// Node hosts replicas and exchanges value changes with its peers
+type Node struct {
+	id   string
+	opts Options
+
+	lock   sync.RWMutex
+	peers  map[string]*grpc.ClientConn
+	groups map[uint32]*group
+	server *grpc.Server
+}
+
+// NewNode creates a node with the given unique id
+func NewNode(id string, opts Options) *Node {
+	if opts.Timeout <= 0 {
+		opts.Timeout = DefaultTimeout
+	}
+	return &Node{
+		id:     id,
+		opts:   opts,
+		peers:  make(map[string]*grpc.ClientConn),
+		groups: make(map[uint32]*group),
+	}
+}
+
+// ID returns the node id
+func (n *Node) ID() string {
+	return n.id
+}
+
+// AddPeer connects to the node listening at addr
+func (n *Node) AddPeer(addr string) error {
+	conn, err := grpc.NewClient(addr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		return fmt.Errorf("connect peer %s: %w", addr, err)
+	}
+
+	n.lock.Lock()
+	defer n.lock.Unlock()
+	if old, ok := n.peers[addr]; ok {
+		old.Close()
+	}
+	n.peers[addr] = conn
+	return nil
+}
+
+// Join registers token as the local replica of its entanglement group. The
+// token must live on a SpanDistributed span and carry an EntanglementID.
+func (n *Node) Join(token *rift.RiftToken) error {
+	if token == nil {
+		return fmt.Errorf("token is nil")
+	}
+	if token.Memory == nil || token.Memory.Type != rift.SpanDistributed {
+		return fmt.Errorf("token is not on a distributed span")
+	}
+	if token.EntanglementID == 0 {
+		return fmt.Errorf("token has no entanglement id to use as group key")
+	}
+
+	n.lock.Lock()
+	defer n.lock.Unlock()
+	if g, ok := n.groups[token.EntanglementID]; ok && g.token != token {
+		return fmt.Errorf("group %d already has a local replica", token.EntanglementID)
+	}
+	n.groups[token.EntanglementID] = &group{token: token}
+	return nil
+}
+
+// Leave stops replicating token
+func (n *Node) Leave(token *rift.RiftToken) {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+	if g, ok := n.groups[token.EntanglementID]; ok && g.token == token {
+		delete(n.groups, token.EntanglementID)
+	}
+}
+
+// Set writes val to a joined token and replicates it to all peers according
+// to the node's consistency. In Quorum mode a failed quorum is reported but
+// the local write is kept; replicas converge on the next successful write.
+func (n *Node) Set(ctx context.Context, token *rift.RiftToken, val rift.RiftTokenValue) error {
+	if err := token.LockContext(ctx); err != nil {
+		return err
+	}
+
+	n.lock.Lock()
+	g, ok := n.groups[token.EntanglementID]
+	if !ok || g.token != token {
+		n.lock.Unlock()
+		token.Unlock()
+		return fmt.Errorf("token has not joined group %d", token.EntanglementID)
+	}
+	if err := token.SetValue(val); err != nil {
+		n.lock.Unlock()
+		token.Unlock()
+		return err
+	}
+	g.seq++
+	g.node = n.id
+	req := &ReplicateRequest{Group: token.EntanglementID, Seq: g.seq, Node: n.id, Value: val}
+	peers := make(map[string]*grpc.ClientConn, len(n.peers))
+	for addr, conn := range n.peers {
+		peers[addr] = conn
+	}
+	n.lock.Unlock()
+	token.Unlock()
+
+	return n.replicate(ctx, peers, req)
+}
+
+// replicate sends req to peers, waiting for a quorum when configured
+func (n *Node) replicate(ctx context.Context, peers map[string]*grpc.ClientConn, req *ReplicateRequest) error {
+	if len(peers) == 0 {
+		return nil
+	}
+
+	acks := make(chan peerAck, len(peers))
+	for addr, conn := range peers {
+		go func(addr string, conn *grpc.ClientConn) {
+			callCtx, cancel := context.WithTimeout(context.Background(), n.opts.Timeout)
+			defer cancel()
+			_, err := callReplicate(callCtx, conn, req)
+			acks <- peerAck{peer: addr, err: err}
+		}(addr, conn)
+	}
+
+	if n.opts.Consistency == Async {
+		go n.drain(acks, len(peers))
+		return nil
+	}
+
+	need := (len(peers) + 1) / 2 // majority of peers+1 nodes, minus self
+	got, failed := 0, 0
+	for i := 0; i < len(peers); i++ {
+		if got >= need {
+			go n.drain(acks, len(peers)-i)
+			return nil
+		}
+		select {
+		case ack := <-acks:
+			if ack.err != nil {
+				failed++
+				n.report(ack)
+			} else {
+				got++
+			}
+		case <-ctx.Done():
+			go n.drain(acks, len(peers)-i)
+			return fmt.Errorf("quorum wait: %w", ctx.Err())
+		}
+	}
+	if got < need {
+		return fmt.Errorf("quorum not reached: %d of %d peer acks (%d failed)", got, need, failed)
+	}
+	return nil
+}
+
+// peerAck is the outcome of replicating to one peer
+type peerAck struct {
+	peer string
+	err  error
+}
+
+// drain reports the remaining count replication results
+func (n *Node) drain(acks <-chan peerAck, count int) {
+	for i := 0; i < count; i++ {
+		if ack := <-acks; ack.err != nil {
+			n.report(ack)
+		}
+	}
+}
+
+// report passes a failed ack to the OnError callback
+func (n *Node) report(ack peerAck) {
+	if n.opts.OnError != nil {
+		n.opts.OnError(ack.peer, ack.err)
+	}
+}
+
+// replicateRemote applies a change received from a peer when it is newer
+// than the local replica
+func (n *Node) replicateRemote(req *ReplicateRequest) (*ReplicateReply, error) {
+	n.lock.RLock()
+	g, ok := n.groups[req.Group]
+	n.lock.RUnlock()
+	if !ok {
+		return &ReplicateReply{Applied: false}, nil
+	}
+
+	g.token.Lock()
+	defer g.token.Unlock()
+	n.lock.Lock()
+	defer n.lock.Unlock()
+
+	if req.Seq < g.seq || (req.Seq == g.seq && req.Node <= g.node) {
+		return &ReplicateReply{Applied: false, Seq: g.seq}, nil
+	}
+	if err := g.token.SetValue(req.Value); err != nil {
+		return nil, err
+	}
+	g.seq = req.Seq
+	g.node = req.Node
+	return &ReplicateReply{Applied: true, Seq: g.seq}, nil
+}
+
+// ============================================================================
+// Server
+// ============================================================================
+
+// Serve accepts replication requests from peers on lis until Close
+func (n *Node) Serve(lis net.Listener) error {
+	n.lock.Lock()
+	if n.server == nil {
+		n.server = grpc.NewServer()
+		n.server.RegisterService(&serviceDesc, nodeService{n})
+	}
+	server := n.server
+	n.lock.Unlock()
+	return server.Serve(lis)
+}
+
+// Close stops the server and disconnects from peers
+func (n *Node) Close() error {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+	if n.server != nil {
+		n.server.Stop()
+		n.server = nil
+	}
+	var first error
+	for addr, conn := range n.peers {
+		if err := conn.Close(); err != nil && first == nil {
+			first = err
+		}
+		delete(n.peers, addr)
+	}
+	return first
+}
+
+// nodeService adapts Node to the replicator service interface
+type nodeService struct {
+	node *Node
+}
+
+func (s nodeService) replicate(ctx context.Context, req *ReplicateRequest) (*ReplicateReply, error) {
+	return s.node.replicateRemote(req)
+}
@@ -0,0 +1,188 @@
+package riftcluster
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	rift "github.com/obinexus/riftlang/bindings/go-riftlang"
+)
+
+// replica returns a token on a distributed span in group id
+func replica(id uint32) *rift.RiftToken {
+	t := rift.NewRiftToken(rift.TokenGoInt, rift.NewRiftMemorySpan(rift.SpanDistributed, 8))
+	t.EntanglementID = id
+	return t
+}
+
+// intValue reads t's value under its lock
+func intValue(t *rift.RiftToken) int64 {
+	t.RLock()
+	defer t.RUnlock()
+	return t.Value.IntVal
+}
+
+// startNode serves a node on a loopback port, returning its address
+func startNode(t *testing.T, id string, opts Options) (*Node, string) {
+	t.Helper()
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Skipf("loopback listen unavailable: %v", err)
+	}
+	n := NewNode(id, opts)
+	go n.Serve(lis)
+	t.Cleanup(func() { n.Close() })
+	return n, lis.Addr().String()
+}
+
+func TestJoin(t *testing.T) {
+	n := NewNode("a", Options{})
+
+	fixed := rift.NewRiftToken(rift.TokenGoInt, rift.NewRiftMemorySpan(rift.SpanFixed, 8))
+	fixed.EntanglementID = 1
+	if err := n.Join(fixed); err == nil {
+		t.Fatal("Join accepted a token on a fixed span")
+	}
+	if err := n.Join(replica(0)); err == nil {
+		t.Fatal("Join accepted a token without an entanglement id")
+	}
+
+	tok := replica(1)
+	if err := n.Join(tok); err != nil {
+		t.Fatalf("Join: %v", err)
+	}
+	if err := n.Join(tok); err != nil {
+		t.Fatalf("Join twice: %v", err)
+	}
+	if err := n.Join(replica(1)); err == nil {
+		t.Fatal("Join accepted a second replica of one group")
+	}
+
+	n.Leave(tok)
+	if err := n.Set(context.Background(), tok, rift.RiftTokenValue{IntVal: 1}); err == nil {
+		t.Fatal("Set succeeded after Leave")
+	}
+}
+
+func TestReplicateRemoteOrder(t *testing.T) {
+	n := NewNode("b", Options{})
+	tok := replica(1)
+	if err := n.Join(tok); err != nil {
+		t.Fatalf("Join: %v", err)
+	}
+
+	tests := []struct {
+		seq     uint64
+		node    string
+		value   int64
+		applied bool
+	}{
+		{2, "a", 20, true},
+		{1, "c", 10, false}, // older sequence
+		{2, "a", 21, false}, // same writer again
+		{2, "c", 22, true},  // tie broken by node id
+		{3, "a", 30, true},
+	}
+	for _, tt := range tests {
+		reply, err := n.replicateRemote(&ReplicateRequest{Group: 1, Seq: tt.seq, Node: tt.node, Value: rift.RiftTokenValue{IntVal: tt.value}})
+		if err != nil {
+			t.Fatalf("replicate (%d, %s): %v", tt.seq, tt.node, err)
+		}
+		if reply.Applied != tt.applied {
+			t.Fatalf("replicate (%d, %s) applied = %v, want %v", tt.seq, tt.node, reply.Applied, tt.applied)
+		}
+	}
+	if got := intValue(tok); got != 30 {
+		t.Fatalf("value = %d, want 30", got)
+	}
+
+	reply, err := n.replicateRemote(&ReplicateRequest{Group: 2, Seq: 1, Node: "a"})
+	if err != nil || reply.Applied {
+		t.Fatalf("replicate to unknown group = %+v, %v", reply, err)
+	}
+}
+
+func TestSetReplicates(t *testing.T) {
+	for _, consistency := range []Consistency{Async, Quorum} {
+		a, _ := startNode(t, "a", Options{Consistency: consistency})
+		b, addrB := startNode(t, "b", Options{})
+		c, addrC := startNode(t, "c", Options{})
+
+		tokA, tokB, tokC := replica(7), replica(7), replica(7)
+		for _, j := range []struct {
+			n   *Node
+			tok *rift.RiftToken
+		}{{a, tokA}, {b, tokB}, {c, tokC}} {
+			if err := j.n.Join(j.tok); err != nil {
+				t.Fatalf("Join %s: %v", j.n.ID(), err)
+			}
+		}
+		for _, addr := range []string{addrB, addrC} {
+			if err := a.AddPeer(addr); err != nil {
+				t.Fatalf("AddPeer: %v", err)
+			}
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		if err := a.Set(ctx, tokA, rift.RiftTokenValue{IntVal: 99}); err != nil {
+			t.Fatalf("Set (consistency %d): %v", consistency, err)
+		}
+		cancel()
+		if got := intValue(tokA); got != 99 {
+			t.Fatalf("local value = %d, want 99", got)
+		}
+
+		deadline := time.Now().Add(10 * time.Second)
+		for intValue(tokB) != 99 || intValue(tokC) != 99 {
+			if time.Now().After(deadline) {
+				t.Fatalf("consistency %d: replicas = %d, %d, want 99", consistency, intValue(tokB), intValue(tokC))
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+	}
+}
+
+func TestQuorumUnreachable(t *testing.T) {
+	errs := make(chan string, 2)
+	a := NewNode("a", Options{
+		Consistency: Quorum,
+		Timeout:     200 * time.Millisecond,
+		OnError:     func(peer string, err error) { errs <- peer },
+	})
+	defer a.Close()
+
+	// Listeners closed at once, so both peers refuse connections
+	var addrs []string
+	for range 2 {
+		lis, err := net.Listen("tcp", "127.0.0.1:0")
+		if err != nil {
+			t.Skipf("loopback listen unavailable: %v", err)
+		}
+		addrs = append(addrs, lis.Addr().String())
+		lis.Close()
+	}
+	for _, addr := range addrs {
+		if err := a.AddPeer(addr); err != nil {
+			t.Fatalf("AddPeer: %v", err)
+		}
+	}
+
+	tok := replica(3)
+	if err := a.Join(tok); err != nil {
+		t.Fatalf("Join: %v", err)
+	}
+	if err := a.Set(context.Background(), tok, rift.RiftTokenValue{IntVal: 5}); err == nil {
+		t.Fatal("Set reached a quorum with no live peers")
+	}
+	if got := intValue(tok); got != 5 {
+		t.Fatalf("local value = %d, want 5 kept after a failed quorum", got)
+	}
+	for range 2 {
+		select {
+		case <-errs:
+		case <-time.After(30 * time.Second):
+			t.Fatal("OnError not called for every unreachable peer")
+		}
+	}
+}
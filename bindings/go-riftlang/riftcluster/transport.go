@@ -0,0 +1,98 @@
+// go/target/riftcluster/transport.go
+// Span Replication Transport (gRPC) - Go Implementation
+
+package riftcluster
+
+import (
+	"context"
+	"encoding/json"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/encoding"
+
+	rift "github.com/obinexus/riftlang/bindings/go-riftlang"
+)
+
+// codecName is the gRPC content subtype used for replication messages
+const codecName = "riftjson"
+
+// serviceName is the fully qualified gRPC service name
+const serviceName = "riftcluster.Replicator"
+
+// ============================================================================
+// Messages
+// ============================================================================
+
+// ReplicateRequest carries one value change for a replication group
+type ReplicateRequest struct {
+	Group uint32              `json:"group"`
+	Seq   uint64              `json:"seq"`
+	Node  string              `json:"node"`
+	Value rift.RiftTokenValue `json:"value"`
+}
+
+// ReplicateReply acknowledges a ReplicateRequest. Applied is false when the
+// peer already holds a newer value or does not host the group.
+type ReplicateReply struct {
+	Applied bool   `json:"applied"`
+	Seq     uint64 `json:"seq"`
+}
+
+// ============================================================================
+// Codec
+// ============================================================================
+
+// jsonCodec encodes replication messages as JSON so the service needs no
+// generated protobuf code
+type jsonCodec struct{}
+
+func (jsonCodec) Marshal(v interface{}) ([]byte, error)      { return json.Marshal(v) }
+func (jsonCodec) Unmarshal(data []byte, v interface{}) error { return json.Unmarshal(data, v) }
+func (jsonCodec) Name() string                               { return codecName }
+
+func init() {
+	encoding.RegisterCodec(jsonCodec{})
+}
+
+// ============================================================================
+// Service
+// ============================================================================
+
+// replicator is implemented by Node to receive replicated changes
+type replicator interface {
+	replicate(ctx context.Context, req *ReplicateRequest) (*ReplicateReply, error)
+}
+
+func replicateHandler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	req := new(ReplicateRequest)
+	if err := dec(req); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(replicator).replicate(ctx, req)
+	}
+	info := &grpc.UnaryServerInfo{Server: srv, FullMethod: "/" + serviceName + "/Replicate"}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(replicator).replicate(ctx, req.(*ReplicateRequest))
+	}
+	return interceptor(ctx, req, info, handler)
+}
+
+var serviceDesc = grpc.ServiceDesc{
+	ServiceName: serviceName,
+	HandlerType: (*replicator)(nil),
+	Methods: []grpc.MethodDesc{
+		{MethodName: "Replicate", Handler: replicateHandler},
+	},
+	Streams: []grpc.StreamDesc{},
+}
+
+// callReplicate sends req to a peer connection
+func callReplicate(ctx context.Context, conn *grpc.ClientConn, req *ReplicateRequest) (*ReplicateReply, error) {
+	reply := new(ReplicateReply)
+	err := conn.Invoke(ctx, "/"+serviceName+"/Replicate", req, reply, grpc.CallContentSubtype(codecName))
+	if err != nil {
+		return nil, err
+	}
+	return reply, nil
+}
@@ -0,0 +1,77 @@
+// go/target/riftexpvar/riftexpvar.go
+// Expvar Exporter for Rift Governance - Go Implementation
+
+// Package riftexpvar publishes Rift governance metrics through expvar, so
+// they appear at /debug/vars alongside memstats with no dependencies
+// beyond the standard library. Importing the package is enough:
+//
+//	import _ "github.com/obinexus/riftlang/bindings/go-riftlang/riftexpvar"
+//
+// It publishes one variable, "rift", holding every metric of
+// rift.AllMetrics under its name (a histogram as its buckets and counts)
+// and, under "engines", the statistics of each engine registered with
+// rift.RegisterEngine.
+package riftexpvar
+
+import (
+	"expvar"
+	"math"
+
+	rift "github.com/obinexus/riftlang/bindings/go-riftlang"
+)
+
+// VarName is the expvar variable the package publishes
+const VarName = "rift"
+
+func init() {
+	expvar.Publish(VarName, expvar.Func(func() interface{} { return Snapshot() }))
+}
+
+// Histogram is the JSON form of a rift.Float64Histogram. JSON has no
+// infinities, so infinite bucket boundaries are given as nil.
+type Histogram struct {
+	Buckets []*float64 `json:"buckets"`
+	Counts  []uint64   `json:"counts"`
+}
+
+// Snapshot returns the value published as VarName: each metric's value
+// keyed by its name, plus "engines" mapping registered engine names to
+// their rift.EngineStats
+func Snapshot() map[string]interface{} {
+	descs := rift.AllMetrics()
+	samples := make([]rift.MetricSample, len(descs))
+	for i, d := range descs {
+		samples[i].Name = d.Name
+	}
+	rift.ReadMetrics(samples)
+
+	out := make(map[string]interface{}, len(samples)+1)
+	for _, s := range samples {
+		switch s.Value.Kind() {
+		case rift.MetricKindUint64:
+			out[s.Name] = s.Value.Uint64()
+		case rift.MetricKindFloat64Histogram:
+			out[s.Name] = histogram(s.Value.Float64Histogram())
+		}
+	}
+
+	engines := make(map[string]rift.EngineStats)
+	for _, name := range rift.EngineNames() {
+		if e, ok := rift.LookupEngine(name); ok {
+			engines[name] = e.Stats()
+		}
+	}
+	out["engines"] = engines
+	return out
+}
+
+// histogram converts h to its JSON form
+func histogram(h *rift.Float64Histogram) Histogram {
+	out := Histogram{Buckets: make([]*float64, len(h.Buckets)), Counts: h.Counts}
+	for i, b := range h.Buckets {
+		if !math.IsInf(b, 0) {
+			out.Buckets[i] = &b
+		}
+	}
+	return out
+}
@@ -0,0 +1,218 @@
+// go/target/rifthttp/rifthttp.go
+// REST/HTTP API for the Pattern Engine - Go Implementation
+
+// Package rifthttp serves a rift.PatternEngine over HTTP as JSON, so teams
+// can run a shared transformation service:
+//
+//	POST /pairs    add a pattern pair               (PairRequest -> PairResponse)
+//	POST /match    match or transform input         (MatchRequest -> MatchResponse)
+//	GET  /metrics  engine statistics                (rift.EngineStats)
+//	GET  /schema   JSON Schemas of the bodies above
+//
+// Errors are reported as an ErrorResponse with a 4xx or 5xx status. The
+// Handler is an http.Handler, so it can be mounted under a prefix with
+// http.StripPrefix.
+package rifthttp
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+
+	rift "github.com/obinexus/riftlang/bindings/go-riftlang"
+)
+
+// DefaultMaxBodyBytes bounds request bodies unless Handler.MaxBodyBytes is set
+const DefaultMaxBodyBytes = 1 << 20
+
+// ============================================================================
+// Messages
+// ============================================================================
+
+// PairRequest adds a pair; see rift.PatternEngine.AddPair
+type PairRequest struct {
+	Left           string `json:"left"`
+	Right          string `json:"right"`
+	Priority       uint32 `json:"priority"`
+	RightIsLiteral bool   `json:"rightIsLiteral,omitempty"`
+	Substitution   string `json:"substitution,omitempty"` // overrides RightIsLiteral; see rift.Substitution
+}
+
+// PairResponse reports the transform ID of an added pair and the engine's
+// new pair count
+type PairResponse struct {
+	TransformID uint32 `json:"transformId"`
+	Pairs       int    `json:"pairs"`
+}
+
+// MatchRequest matches Input, or each of Inputs. With All set the input is
+// rewritten at every match (rift.PatternEngine.Transform) instead of
+// matched once.
+type MatchRequest struct {
+	Input  string   `json:"input,omitempty"`
+	Inputs []string `json:"inputs,omitempty"`
+	All    bool     `json:"all,omitempty"`
+}
+
+// MatchResponse holds the best match for Input or the matches for Inputs
+// in order; with All, Output is the rewritten input and Results the
+// replacements made
+type MatchResponse struct {
+	Output  string              `json:"output,omitempty"`
+	Result  *rift.MatchResult   `json:"result,omitempty"`
+	Results []*rift.MatchResult `json:"results,omitempty"`
+}
+
+// ErrorResponse is the body of every failed request. Code is the
+// governance error code, when there is one.
+type ErrorResponse struct {
+	Error string `json:"error"`
+	Code  string `json:"code,omitempty"`
+}
+
+// ============================================================================
+// Handler
+// ============================================================================
+
+// Handler serves one pattern engine
+type Handler struct {
+	Engine       *rift.PatternEngine
+	MaxBodyBytes int64 // 0 = DefaultMaxBodyBytes
+
+	mux  *http.ServeMux
+	once sync.Once
+}
+
+// NewHandler returns a handler serving engine
+func NewHandler(engine *rift.PatternEngine) *Handler {
+	return &Handler{Engine: engine}
+}
+
+// ServeHTTP implements http.Handler
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.once.Do(func() {
+		h.mux = http.NewServeMux()
+		h.mux.HandleFunc("/pairs", h.method(http.MethodPost, h.addPair))
+		h.mux.HandleFunc("/match", h.method(http.MethodPost, h.match))
+		h.mux.HandleFunc("/metrics", h.method(http.MethodGet, h.metrics))
+		h.mux.HandleFunc("/schema", h.method(http.MethodGet, schema))
+	})
+	h.mux.ServeHTTP(w, r)
+}
+
+// method restricts fn to one HTTP method
+func (h *Handler) method(m string, fn http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != m {
+			w.Header().Set("Allow", m)
+			writeError(w, http.StatusMethodNotAllowed, fmt.Errorf("%s requires %s", r.URL.Path, m))
+			return
+		}
+		fn(w, r)
+	}
+}
+
+func (h *Handler) addPair(w http.ResponseWriter, r *http.Request) {
+	var req PairRequest
+	if !h.decode(w, r, &req) {
+		return
+	}
+	if req.Left == "" {
+		writeError(w, http.StatusBadRequest, errors.New("left pattern is empty"))
+		return
+	}
+
+	var id uint32
+	var err error
+	if req.Substitution != "" {
+		var mode rift.Substitution
+		if mode, err = rift.SubstitutionNamed(req.Substitution); err == nil {
+			id, err = h.Engine.AddPairSubstitution(req.Left, req.Right, req.Priority, mode)
+		}
+	} else {
+		id, err = h.Engine.AddPairID(req.Left, req.Right, req.Priority, req.RightIsLiteral)
+	}
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, PairResponse{TransformID: id, Pairs: h.Engine.GetPairCount()})
+}
+
+func (h *Handler) match(w http.ResponseWriter, r *http.Request) {
+	var req MatchRequest
+	if !h.decode(w, r, &req) {
+		return
+	}
+
+	var resp MatchResponse
+	switch {
+	case req.All && req.Inputs != nil:
+		writeError(w, http.StatusBadRequest, errors.New("all applies to input, not inputs"))
+		return
+	case req.All:
+		output, results := h.Engine.Transform(req.Input)
+		resp.Output = output
+		resp.Results = make([]*rift.MatchResult, len(results))
+		for i := range results {
+			resp.Results[i] = &results[i]
+		}
+	case req.Inputs != nil:
+		resp.Results = h.Engine.MatchAll(req.Inputs)
+	default:
+		result, err := h.Engine.MatchContext(r.Context(), req.Input)
+		if err != nil {
+			writeError(w, http.StatusServiceUnavailable, err)
+			return
+		}
+		resp.Result = result
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+func (h *Handler) metrics(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, h.Engine.Stats())
+}
+
+// ============================================================================
+// Encoding
+// ============================================================================
+
+// decode reads a JSON request body into v, writing the error response and
+// returning false on failure
+func (h *Handler) decode(w http.ResponseWriter, r *http.Request, v interface{}) bool {
+	limit := h.MaxBodyBytes
+	if limit <= 0 {
+		limit = DefaultMaxBodyBytes
+	}
+	dec := json.NewDecoder(http.MaxBytesReader(w, r.Body, limit))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(v); err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			writeError(w, http.StatusRequestEntityTooLarge, err)
+		} else {
+			writeError(w, http.StatusBadRequest, fmt.Errorf("decode request: %w", err))
+		}
+		return false
+	}
+	return true
+}
+
+// writeJSON writes v with the given status
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+// writeError writes err as an ErrorResponse
+func writeError(w http.ResponseWriter, status int, err error) {
+	resp := ErrorResponse{Error: err.Error()}
+	if code := rift.ErrorCodeOf(err); code != rift.CodeOK {
+		resp.Code = code.String()
+	}
+	writeJSON(w, status, resp)
+}
@@ -0,0 +1,115 @@
+package rifthttp
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	rift "github.com/obinexus/riftlang/bindings/go-riftlang"
+)
+
+// serve sends a request to h and decodes the response body into out
+func serve(t *testing.T, h http.Handler, method, path, body string, out interface{}) int {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(method, path, strings.NewReader(body)))
+	if out != nil {
+		if err := json.Unmarshal(rec.Body.Bytes(), out); err != nil {
+			t.Fatalf("%s %s: decode %q: %v", method, path, rec.Body.String(), err)
+		}
+	}
+	return rec.Code
+}
+
+func TestAddPairAndMatch(t *testing.T) {
+	h := NewHandler(rift.NewPatternEngine("classical"))
+
+	var pair PairResponse
+	if code := serve(t, h, http.MethodPost, "/pairs", `{"left":"(?P<name>\\w+)=(\\d+)","right":"{name}:$2","priority":1,"substitution":"both"}`, &pair); code != http.StatusCreated {
+		t.Fatalf("POST /pairs = %d", code)
+	}
+	if pair.Pairs != 1 {
+		t.Fatalf("pairs = %d, want 1", pair.Pairs)
+	}
+
+	var one MatchResponse
+	if code := serve(t, h, http.MethodPost, "/match", `{"input":"x=1"}`, &one); code != http.StatusOK {
+		t.Fatalf("POST /match = %d", code)
+	}
+	if one.Result == nil || !one.Result.Matched || one.Result.Output != "x:1" {
+		t.Fatalf("match result = %+v, want output x:1", one.Result)
+	}
+
+	var many MatchResponse
+	serve(t, h, http.MethodPost, "/match", `{"inputs":["a=2","none"]}`, &many)
+	if len(many.Results) != 2 || !many.Results[0].Matched || many.Results[1].Matched {
+		t.Fatalf("match inputs = %+v, want first only matched", many.Results)
+	}
+
+	var all MatchResponse
+	serve(t, h, http.MethodPost, "/match", `{"input":"a=1 b=2","all":true}`, &all)
+	if all.Output != "a:1 b:2" || len(all.Results) != 2 {
+		t.Fatalf("match all = %q with %d results, want \"a:1 b:2\" with 2", all.Output, len(all.Results))
+	}
+
+	var stats rift.EngineStats
+	if code := serve(t, h, http.MethodGet, "/metrics", "", &stats); code != http.StatusOK {
+		t.Fatalf("GET /metrics = %d", code)
+	}
+	if stats.PairCount != 1 || stats.TotalMatches == 0 {
+		t.Fatalf("metrics = %+v", stats)
+	}
+}
+
+func TestErrors(t *testing.T) {
+	h := NewHandler(rift.NewPatternEngine("classical"))
+	h.MaxBodyBytes = 64
+
+	tests := []struct {
+		name         string
+		method, path string
+		body         string
+		want         int
+		code         string
+	}{
+		{"wrong method", http.MethodGet, "/pairs", "", http.StatusMethodNotAllowed, ""},
+		{"empty left", http.MethodPost, "/pairs", `{"left":"","right":"x","priority":1}`, http.StatusBadRequest, ""},
+		{"bad regexp", http.MethodPost, "/pairs", `{"left":"(","right":"x","priority":1}`, http.StatusBadRequest, "E_REGEX_COMPILE"},
+		{"bad substitution", http.MethodPost, "/pairs", `{"left":"a","right":"x","priority":1,"substitution":"some"}`, http.StatusBadRequest, ""},
+		{"unknown field", http.MethodPost, "/match", `{"input":"a","extra":1}`, http.StatusBadRequest, ""},
+		{"malformed", http.MethodPost, "/match", `{"input":`, http.StatusBadRequest, ""},
+		{"all with inputs", http.MethodPost, "/match", `{"inputs":["a"],"all":true}`, http.StatusBadRequest, ""},
+		{"too large", http.MethodPost, "/match", `{"input":"` + strings.Repeat("a", 100) + `"}`, http.StatusRequestEntityTooLarge, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var resp ErrorResponse
+			if code := serve(t, h, tt.method, tt.path, tt.body, &resp); code != tt.want {
+				t.Fatalf("status = %d, want %d (%s)", code, tt.want, resp.Error)
+			}
+			if resp.Error == "" || resp.Code != tt.code {
+				t.Fatalf("error = %q code %q, want code %q", resp.Error, resp.Code, tt.code)
+			}
+		})
+	}
+}
+
+func TestSchema(t *testing.T) {
+	h := NewHandler(rift.NewPatternEngine("classical"))
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/schema", nil))
+	if rec.Code != http.StatusOK || rec.Header().Get("Content-Type") != "application/schema+json" {
+		t.Fatalf("GET /schema = %d %s", rec.Code, rec.Header().Get("Content-Type"))
+	}
+	var schemas map[string]json.RawMessage
+	if err := json.Unmarshal(rec.Body.Bytes(), &schemas); err != nil {
+		t.Fatalf("schema is not JSON: %v", err)
+	}
+	for _, name := range []string{"PairRequest", "PairResponse", "MatchRequest", "MatchResponse", "EngineStats", "ErrorResponse"} {
+		if _, ok := schemas[name]; !ok {
+			t.Errorf("schema missing %s", name)
+		}
+	}
+}
@@ -0,0 +1,98 @@
+// go/target/rifthttp/schema.go
+// JSON Schemas for the Pattern Engine API - Go Implementation
+
+package rifthttp
+
+import "net/http"
+
+// Schemas holds the JSON Schema (draft 2020-12) of each request and
+// response body, keyed by message name, as served by GET /schema
+const Schemas = `{
+  "PairRequest": {
+    "$schema": "https://json-schema.org/draft/2020-12/schema",
+    "type": "object",
+    "required": ["left", "right", "priority"],
+    "properties": {
+      "left": {"type": "string", "minLength": 1, "description": "Go regexp matched against input"},
+      "right": {"type": "string", "description": "output template with $N and {name} placeholders"},
+      "priority": {"type": "integer", "minimum": 0, "maximum": 4294967295, "description": "lower ranks first"},
+      "rightIsLiteral": {"type": "boolean", "description": "emit right verbatim, without placeholders"},
+      "substitution": {"enum": ["none", "indexed", "named", "both"], "description": "placeholders right expands; overrides rightIsLiteral"}
+    },
+    "additionalProperties": false
+  },
+  "PairResponse": {
+    "$schema": "https://json-schema.org/draft/2020-12/schema",
+    "type": "object",
+    "required": ["transformId", "pairs"],
+    "properties": {
+      "transformId": {"type": "integer"},
+      "pairs": {"type": "integer"}
+    }
+  },
+  "MatchRequest": {
+    "$schema": "https://json-schema.org/draft/2020-12/schema",
+    "type": "object",
+    "properties": {
+      "input": {"type": "string"},
+      "inputs": {"type": "array", "items": {"type": "string"}},
+      "all": {"type": "boolean", "description": "rewrite every match in input"}
+    },
+    "additionalProperties": false
+  },
+  "MatchResponse": {
+    "$schema": "https://json-schema.org/draft/2020-12/schema",
+    "type": "object",
+    "properties": {
+      "output": {"type": "string"},
+      "result": {"$ref": "#/$defs/MatchResult"},
+      "results": {"type": "array", "items": {"$ref": "#/$defs/MatchResult"}}
+    },
+    "$defs": {
+      "MatchResult": {
+        "type": "object",
+        "properties": {
+          "Matched": {"type": "boolean"},
+          "Output": {"type": "string"},
+          "Priority": {"type": "integer"},
+          "TransformID": {"type": "integer"},
+          "Groups": {"type": ["object", "null"], "additionalProperties": {"type": "string"}},
+          "Start": {"type": "integer"},
+          "End": {"type": "integer"},
+          "Text": {"type": "string"},
+          "States": {"type": ["array", "null"], "items": {"$ref": "#/$defs/MatchResult"}, "description": "a result per matching pair in quantum mode"},
+          "Amplitudes": {"type": ["array", "null"], "items": {"type": "number"}},
+          "Version": {"type": "integer", "description": "pair-set version matched against"}
+        }
+      }
+    }
+  },
+  "EngineStats": {
+    "$schema": "https://json-schema.org/draft/2020-12/schema",
+    "type": "object",
+    "properties": {
+      "TotalMatches": {"type": "integer"},
+      "TotalFailures": {"type": "integer"},
+      "AverageMatchTimeMs": {"type": "number"},
+      "PairCount": {"type": "integer"},
+      "CacheHits": {"type": "integer"},
+      "CacheMisses": {"type": "integer"}
+    }
+  },
+  "ErrorResponse": {
+    "$schema": "https://json-schema.org/draft/2020-12/schema",
+    "type": "object",
+    "required": ["error"],
+    "properties": {
+      "error": {"type": "string"},
+      "code": {"type": "string", "description": "governance error code, e.g. E_REGEX_COMPILE"}
+    }
+  }
+}
+`
+
+// schema serves Schemas
+func schema(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/schema+json")
+	w.Write([]byte(Schemas))
+}
@@ -0,0 +1,259 @@
+// go/target/riftlint/engine.go
+// riftlint Engine Export Checks - Go Implementation
+
+package riftlint
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+
+	rift "github.com/obinexus/riftlang/bindings/go-riftlang"
+)
+
+// ============================================================================
+// Engine Export Linting
+// ============================================================================
+
+// exportLint holds an export's source and the line of every key in it, by
+// dotted path (engine.pairs.3.left)
+type exportLint struct {
+	*linter
+	data  []byte
+	lines map[string]int
+}
+
+// LintEngineExport checks an engine export, as PatternEngine.Export
+// writes it, read from name
+func LintEngineExport(name string, data []byte) []Diagnostic {
+	l := &linter{file: name}
+	el := &exportLint{linter: l, data: data, lines: make(map[string]int)}
+
+	// Walk the document against EngineExport first: the decoder drops
+	// unknown fields silently, and only the walk knows their lines
+	dec := json.NewDecoder(bytes.NewReader(data))
+	if err := el.walk(dec, reflect.TypeOf(rift.EngineExport{}), ""); err != nil {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		el.errorf(el.lineAt(dec.InputOffset()), CheckSyntax, "%v", err)
+		return l.sorted()
+	}
+	var doc rift.EngineExport
+	if err := json.Unmarshal(data, &doc); err != nil {
+		line := 0
+		var typeErr *json.UnmarshalTypeError
+		if errors.As(err, &typeErr) {
+			line = el.lineAt(typeErr.Offset)
+		}
+		el.errorf(line, CheckValue, "%v", err)
+		return l.sorted()
+	}
+
+	if doc.Format != rift.EngineExportFormat {
+		el.errorf(el.lines["format"], CheckValue, "format %q is not %q", doc.Format, rift.EngineExportFormat)
+	}
+	if doc.Version < 1 || doc.Version > rift.EngineExportVersion {
+		el.errorf(el.lines["version"], CheckValue, "unsupported version %d", doc.Version)
+	}
+	if _, err := rift.EngineModeNamed(doc.Engine.Mode); err != nil {
+		el.errorf(el.lines["engine.mode"], CheckValue, "%v", err)
+	}
+	el.pairs(doc.Engine)
+
+	// The importer has the last word on what it rejects
+	if !el.anyError() {
+		if _, err := rift.ImportEngine(bytes.NewReader(data)); err != nil {
+			el.errorf(0, CheckValue, "%v", err)
+		}
+	}
+	return l.sorted()
+}
+
+// pairs checks the engine's pairs and disabled groups
+func (el *exportLint) pairs(ed rift.EngineDump) {
+	type groupLeft struct{ group, left string }
+	var (
+		last   uint32
+		ids    = make(map[uint32]int)
+		lefts  = make(map[groupLeft]int)
+		groups = make(map[string]bool)
+	)
+	for i, pd := range ed.Pairs {
+		path := "engine.pairs." + strconv.Itoa(i)
+		line := el.lines[path]
+		groups[pd.Group] = true
+
+		if err := compilePattern(pd.Left); err != nil {
+			el.errorf(el.lineOf(path+".left", line), CheckPattern, "left pattern %q does not compile: %v", pd.Left, err)
+		}
+		if pd.Substitution != "" {
+			if _, err := rift.SubstitutionNamed(pd.Substitution); err != nil {
+				el.errorf(el.lineOf(path+".substitution", line), CheckValue, "%v", err)
+			}
+		}
+
+		id := pd.ID
+		if id == 0 {
+			id = last + 1
+		}
+		switch prev, dup := ids[id]; {
+		case dup:
+			el.errorf(el.lineOf(path+".id", line), CheckDuplicate, "pair id %d repeats the pair at line %d", id, prev)
+		case id <= last:
+			el.errorf(el.lineOf(path+".id", line), CheckValue, "pair id %d out of order after %d", id, last)
+		}
+		ids[id] = line
+		last = max(last, id)
+
+		key := groupLeft{pd.Group, pd.Left}
+		if prev, ok := lefts[key]; ok {
+			el.warnf(el.lineOf(path+".left", line), CheckDuplicate, "left pattern %q repeats the pair at line %d in the same group; under priority selection only one of them matches", pd.Left, prev)
+		} else {
+			lefts[key] = line
+		}
+	}
+	for i, g := range ed.Disabled {
+		if !groups[g] {
+			el.warnf(el.lines["engine.disabledGroups."+strconv.Itoa(i)], CheckUnusedRule, "disabled group %q has no pairs", g)
+		}
+	}
+}
+
+// anyError reports whether an error has been reported
+func (el *exportLint) anyError() bool {
+	for _, d := range el.diags {
+		if d.Severity == SeverityError {
+			return true
+		}
+	}
+	return false
+}
+
+// ============================================================================
+// Walking
+// ============================================================================
+
+// walk reads one value from dec, checking object keys against the json
+// fields of t and recording the line of each key and array element
+func (el *exportLint) walk(dec *json.Decoder, t reflect.Type, path string) error {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	tok, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	delim, ok := tok.(json.Delim)
+	if !ok {
+		return nil
+	}
+	switch delim {
+	case '{':
+		fields := jsonFields(t)
+		for dec.More() {
+			tok, err := dec.Token()
+			if err != nil {
+				return err
+			}
+			key, _ := tok.(string)
+			sub := join(path, key)
+			el.lines[sub] = el.lineAt(dec.InputOffset())
+
+			ft, known := fields[key]
+			switch {
+			case t.Kind() == reflect.Map:
+				ft, known = t.Elem(), true
+			case t.Kind() != reflect.Struct:
+				ft, known = t, true
+			case !known:
+				el.warnf(el.lines[sub], CheckUnknownField, "%s has no field %q; it is ignored", describe(path), key)
+				ft = reflect.TypeOf((*any)(nil)).Elem()
+			}
+			if err := el.walk(dec, ft, sub); err != nil {
+				return err
+			}
+		}
+	case '[':
+		elem := t
+		if t.Kind() == reflect.Slice || t.Kind() == reflect.Array {
+			elem = t.Elem()
+		}
+		for i := 0; dec.More(); i++ {
+			sub := join(path, strconv.Itoa(i))
+			// The line of an element is where its value starts, past the
+			// separating comma InputOffset stops before
+			el.lines[sub] = el.lineAt(el.skipSpace(dec.InputOffset()))
+			if err := el.walk(dec, elem, sub); err != nil {
+				return err
+			}
+		}
+	}
+	_, err = dec.Token() // the closing delimiter
+	return err
+}
+
+// jsonFields maps the json names of struct t's fields to their types
+func jsonFields(t reflect.Type) map[string]reflect.Type {
+	fields := make(map[string]reflect.Type)
+	if t.Kind() != reflect.Struct {
+		return fields
+	}
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+		name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		fields[name] = f.Type
+	}
+	return fields
+}
+
+// lineAt returns the line holding byte offset off of the export
+func (el *exportLint) lineAt(off int64) int {
+	off = min(max(off, 0), int64(len(el.data)))
+	return bytes.Count(el.data[:off], []byte{'\n'}) + 1
+}
+
+// lineOf returns the line of the key at path, or fallback
+func (el *exportLint) lineOf(path string, fallback int) int {
+	if line, ok := el.lines[path]; ok {
+		return line
+	}
+	return fallback
+}
+
+// skipSpace returns the offset of the first byte at or after off that is
+// neither white space nor a comma
+func (el *exportLint) skipSpace(off int64) int64 {
+	for off < int64(len(el.data)) && strings.IndexByte(" \t\r\n,", el.data[off]) >= 0 {
+		off++
+	}
+	return off
+}
+
+// describe names the object at path for messages
+func describe(path string) string {
+	if path == "" {
+		return "export"
+	}
+	return path
+}
+
+func join(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
@@ -0,0 +1,454 @@
+// go/target/riftlint/policy.go
+// riftlint .rift Policy Checks - Go Implementation
+
+package riftlint
+
+import (
+	"math"
+	"math/bits"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	rift "github.com/obinexus/riftlang/bindings/go-riftlang"
+)
+
+// ============================================================================
+// Schema
+// ============================================================================
+
+// blockFields lists the fields of each block kind the loader reads, or
+// that the policy language defines for other bindings; a nil entry is a
+// scalar or list, a non-nil one a map with those keys. Kinds missing here
+// but listed in freeBlocks take any fields.
+var blockFields = map[string]map[string][]string{
+	"govern": {
+		"token_memory":       {"alignment", "access", "phase"},
+		"token_type":         {"inference", "checking", "casting"},
+		"token_value":        {"binding", "resolution", "validation"},
+		"policy_enforcement": {"timing", "violation", "recovery"},
+		"threshold":          nil,
+		"max_lock_hold":      nil,
+		"max_concurrency":    nil,
+		"on_violation":       nil,
+		"amplitudes":         nil,
+	},
+	"align":                 {"direction": nil, "bytes": nil, "type": nil, "open": nil},
+	"role":                  {"permissions": nil, "scope": nil, "trace": nil},
+	"pattern":               {"priority": nil, "governed": nil},
+	"entanglement_registry": {"entropy_threshold": nil, "decoherence_threshold": nil, "max_pairs": nil, "auto_collapse": nil},
+}
+
+// freeBlocks are the block kinds whose fields are the author's own
+var freeBlocks = map[string]bool{"type": true, "policy_fn": true, "collapse_trigger": true}
+
+// spanTypes are the span names an align block's type may take
+var spanTypes = map[string]bool{
+	"fixed": true, "row": true, "expandable": true, "continuous": true,
+	"superposed": true, "entangled": true, "distributed": true,
+}
+
+// accessRights maps the rights of access lists to their mask bits
+var accessRights = map[string]uint32{
+	"CREATE":    rift.AccessCreate,
+	"READ":      rift.AccessRead,
+	"UPDATE":    rift.AccessUpdate,
+	"WRITE":     rift.AccessUpdate,
+	"DELETE":    rift.AccessDelete,
+	"SUPERPOSE": rift.AccessSuperpose,
+	"ENTANGLE":  rift.AccessEntangle,
+}
+
+// ============================================================================
+// Policy Linting
+// ============================================================================
+
+// policyLint holds what a policy's govern blocks set, which the other
+// blocks are checked against, and the declarations seen so far
+type policyLint struct {
+	*linter
+	access    uint32  // token_memory access mask
+	alignKind string  // token_memory alignment kind
+	align     float64 // token_memory alignment
+
+	governLine int
+	spans      map[string]int // span name -> line of its align block
+	roles      map[string]int
+	patterns   map[string]int // left pattern -> line
+	types      map[string]bool
+}
+
+// LintPolicy checks .rift source read from name
+func LintPolicy(name, src string) []Diagnostic {
+	l := &linter{file: name}
+	blocks, err := rift.ParsePolicyBlocks(src)
+	if err != nil {
+		line, msg := splitLine(err)
+		l.errorf(line, CheckSyntax, "%s", msg)
+		return l.sorted()
+	}
+
+	pl := &policyLint{
+		linter:    l,
+		access:    rift.AccessCRUD,
+		alignKind: "fixed",
+		align:     rift.ClassicalAlignment,
+		spans:     make(map[string]int),
+		roles:     make(map[string]int),
+		patterns:  make(map[string]int),
+		types:     make(map[string]bool),
+	}
+	for t := rift.TokenGoInt; t <= rift.TokenGoPtr; t++ {
+		pl.types[rift.TokenTypeName(t)] = true
+	}
+
+	// The govern blocks set the mask and alignment every other block is
+	// checked against, wherever they appear
+	for _, b := range blocks {
+		if b.Kind == "govern" {
+			pl.govern(b)
+		}
+	}
+	for _, b := range blocks {
+		if b.Kind != "govern" {
+			pl.block(b)
+		}
+	}
+
+	// The loader has the last word on what it rejects
+	policy, err := rift.ParsePolicy(src)
+	if err != nil {
+		if line, msg := splitLine(err); !l.hasError(line) {
+			l.errorf(line, CheckValue, "%s", msg)
+		}
+		return l.sorted()
+	}
+	pl.spanDefaults(policy)
+	return l.sorted()
+}
+
+// block checks one block other than govern
+func (pl *policyLint) block(b *rift.PolicyBlock) {
+	if _, ok := blockFields[b.Kind]; ok {
+		pl.unknownFields(b)
+	} else if !freeBlocks[b.Kind] {
+		pl.warnf(b.Line, CheckUnknownBlock, "unknown block kind %q is ignored", b.Kind)
+		return
+	}
+	switch b.Kind {
+	case "align":
+		pl.alignBlock(b)
+	case "role":
+		pl.role(b)
+	case "type":
+		pl.typeRule(b)
+	case "pattern":
+		pl.pattern(b)
+	case "entanglement_registry":
+		for _, key := range []string{"entropy_threshold", "decoherence_threshold"} {
+			if v := b.Fields[key]; v != nil {
+				pl.float(b.Line, key, v.Scalar, 0, math.Inf(1))
+			}
+		}
+	case "policy_fn":
+		if v := b.Fields["default_access"]; v != nil {
+			pl.rights(b.Line, "policy_fn "+strings.Join(b.Args, " "), v.List, false)
+		}
+	}
+}
+
+// unknownFields reports the fields, and fields of nested maps, the
+// schema does not list
+func (pl *policyLint) unknownFields(b *rift.PolicyBlock) {
+	schema := blockFields[b.Kind]
+	for _, key := range sortedKeys(b.Fields) {
+		sub, ok := schema[key]
+		if !ok {
+			pl.warnf(b.Line, CheckUnknownField, "%s block has no field %q; it is ignored", b.Kind, key)
+			continue
+		}
+		v := b.Fields[key]
+		if sub == nil {
+			continue
+		}
+		if v.Map == nil {
+			pl.warnf(b.Line, CheckValue, "%s should be a { ... } map; it is ignored", key)
+			continue
+		}
+		for _, inner := range sortedKeys(v.Map) {
+			if !contains(sub, inner) {
+				pl.warnf(b.Line, CheckUnknownField, "%s has no field %q; it is ignored", key, inner)
+			}
+		}
+	}
+}
+
+// ============================================================================
+// Block Checks
+// ============================================================================
+
+// govern checks a govern block, recording its mask and alignment
+func (pl *policyLint) govern(b *rift.PolicyBlock) {
+	if pl.governLine != 0 {
+		pl.warnf(b.Line, CheckDuplicate, "second govern block overrides the settings of line %d", pl.governLine)
+	}
+	pl.governLine = b.Line
+	pl.unknownFields(b)
+
+	if mem := b.Fields["token_memory"]; mem != nil && mem.Map != nil {
+		if v := mem.Map["alignment"]; v != nil {
+			pl.alignment(b.Line, v.Scalar)
+		}
+		if v := mem.Map["access"]; v != nil {
+			if mask, ok := pl.rights(b.Line, "token_memory access", v.List, true); ok {
+				pl.access = mask
+				if mask == 0 {
+					pl.warnf(b.Line, CheckAccess, "token_memory access grants no rights; every span access is denied")
+				}
+			}
+		}
+	}
+	if val := b.Fields["token_value"]; val != nil && val.Map != nil {
+		if v := val.Map["validation"]; v != nil && strings.HasPrefix(v.Scalar, "entropy_threshold") {
+			if _, _, ok := parseCall(v.Scalar); !ok {
+				pl.errorf(b.Line, CheckValue, "validation %q is not entropy_threshold(x)", v.Scalar)
+			}
+		}
+	}
+	if v := b.Fields["threshold"]; v != nil {
+		if f, ok := pl.float(b.Line, "threshold", v.Scalar, math.Inf(-1), math.Inf(1)); ok && (f < 0 || f > 1) {
+			pl.warnf(b.Line, CheckValue, "threshold %g is outside [0, 1], the range of governance scores", f)
+		}
+	}
+	if v := b.Fields["max_lock_hold"]; v != nil {
+		if d, err := time.ParseDuration(v.Scalar); err != nil || d < 0 {
+			pl.errorf(b.Line, CheckValue, "max_lock_hold %q is not a duration", v.Scalar)
+		}
+	}
+	if v := b.Fields["max_concurrency"]; v != nil {
+		if n, err := strconv.Atoi(v.Scalar); err != nil || n < 0 {
+			pl.errorf(b.Line, CheckValue, "max_concurrency %q is not a count", v.Scalar)
+		}
+	}
+	if v := b.Fields["on_violation"]; v != nil {
+		if _, err := rift.ViolationHandlerNamed(v.Scalar); err != nil {
+			pl.errorf(b.Line, CheckValue, "on_violation: %v", err)
+		}
+	}
+	if v := b.Fields["amplitudes"]; v != nil {
+		if _, err := rift.AmplitudeModeNamed(v.Scalar); err != nil {
+			pl.errorf(b.Line, CheckValue, "amplitudes: %v", err)
+		}
+	}
+}
+
+// alignment checks token_memory's alignment, recording it
+func (pl *policyLint) alignment(line int, s string) {
+	kind, n, ok := parseCall(s)
+	if !ok {
+		pl.errorf(line, CheckValue, "alignment %q is not fixed(n) or dynamic(n)", s)
+		return
+	}
+	pl.alignKind, pl.align = kind, n
+	switch kind {
+	case "fixed":
+		if !powerOfTwo(n) {
+			pl.errorf(line, CheckAlignment, "alignment fixed(%g) is not a power of two; no span can have it", n)
+		}
+	case "dynamic":
+		switch {
+		case n > 1<<31:
+			pl.errorf(line, CheckAlignment, "alignment dynamic(%g) is above every span alignment", n)
+		case n > 0 && !powerOfTwo(n):
+			least := uint64(1) << bits.Len64(uint64(math.Ceil(n))-1)
+			pl.errorf(line, CheckAlignment, "alignment dynamic(%g) is not a power of two; the least span alignment meeting it is %d", n, least)
+		}
+	default:
+		pl.warnf(line, CheckAlignment, "alignment kind %q is neither fixed nor dynamic; span alignments go unchecked", kind)
+	}
+}
+
+// alignBlock checks an `align span<name>` block
+func (pl *policyLint) alignBlock(b *rift.PolicyBlock) {
+	if len(b.Args) == 0 || !strings.HasPrefix(b.Args[0], "span<") {
+		pl.errorf(b.Line, CheckValue, "align block requires span<name>")
+		return
+	}
+	name := strings.TrimSuffix(strings.TrimPrefix(b.Args[0], "span<"), ">")
+	if prev, ok := pl.spans[name]; ok {
+		pl.warnf(b.Line, CheckDuplicate, "span<%s> redeclares line %d's, replacing it", name, prev)
+	}
+	pl.spans[name] = b.Line
+
+	if v := b.Fields["type"]; v != nil && !spanTypes[v.Scalar] {
+		pl.errorf(b.Line, CheckValue, "unknown span type %q", v.Scalar)
+	}
+	if v := b.Fields["bytes"]; v != nil {
+		if _, err := strconv.ParseUint(v.Scalar, 10, 64); err != nil {
+			pl.errorf(b.Line, CheckValue, "span bytes %q is not a size", v.Scalar)
+		}
+	}
+	if v := b.Fields["direction"]; v != nil {
+		if d := strings.ReplaceAll(v.Scalar, " ", ""); d != "left->right" && d != "right->left" {
+			pl.warnf(b.Line, CheckValue, "direction %q is neither left -> right nor right -> left; it is read as right -> left", v.Scalar)
+		}
+	}
+	if v := b.Fields["open"]; v != nil && v.Scalar != "true" && v.Scalar != "false" {
+		pl.warnf(b.Line, CheckValue, "open %q is not true or false; it is read as false", v.Scalar)
+	}
+}
+
+// role checks a role's permissions against token_memory access
+func (pl *policyLint) role(b *rift.PolicyBlock) {
+	if len(b.Args) == 0 {
+		pl.errorf(b.Line, CheckValue, "role block requires a name")
+		return
+	}
+	name := b.Args[0]
+	if prev, ok := pl.roles[name]; ok {
+		pl.warnf(b.Line, CheckDuplicate, "role %s redeclares line %d's, replacing it", name, prev)
+	}
+	pl.roles[name] = b.Line
+	if v := b.Fields["permissions"]; v != nil {
+		pl.rights(b.Line, "role "+name, v.List, true)
+	}
+}
+
+// typeRule checks a type rule's name and memory alignment
+func (pl *policyLint) typeRule(b *rift.PolicyBlock) {
+	if len(b.Args) == 0 {
+		pl.errorf(b.Line, CheckValue, "type block requires a name")
+		return
+	}
+	name := b.Args[0]
+	if !pl.types[name] {
+		pl.warnf(b.Line, CheckUnusedRule, "type rule %s names no token type; validation never applies it", name)
+	}
+	v := b.Fields["memory"]
+	if v == nil {
+		return
+	}
+	kind, n, ok := parseCall(v.Scalar)
+	if !ok || kind != "aligned" {
+		pl.warnf(b.Line, CheckValue, "memory %q is not aligned(n); it is ignored", v.Scalar)
+		return
+	}
+	switch {
+	case !powerOfTwo(n):
+		pl.errorf(b.Line, CheckAlignment, "type %s memory aligned(%g) is not a power of two; no span alignment is a multiple of it", name, n)
+	case pl.alignKind == "fixed" && powerOfTwo(pl.align) && math.Mod(pl.align, n) != 0:
+		pl.errorf(b.Line, CheckAlignment, "type %s memory aligned(%g) conflicts with alignment fixed(%g); no %s token can pass validation", name, n, pl.align, name)
+	}
+}
+
+// pattern checks a pattern declaration
+func (pl *policyLint) pattern(b *rift.PolicyBlock) {
+	if len(b.Args) < 3 || b.Args[1] != "->" {
+		pl.errorf(b.Line, CheckValue, "pattern block requires \"left\" -> \"right\"")
+		return
+	}
+	left := b.Args[0]
+	if err := compilePattern(left); err != nil {
+		pl.errorf(b.Line, CheckPattern, "left pattern %q does not compile: %v", left, err)
+	}
+	if prev, ok := pl.patterns[left]; ok {
+		pl.warnf(b.Line, CheckDuplicate, "left pattern %q repeats line %d's; under priority selection only one of the pairs matches", left, prev)
+	} else {
+		pl.patterns[left] = b.Line
+	}
+	if v := b.Fields["priority"]; v != nil {
+		if _, err := strconv.ParseUint(v.Scalar, 10, 32); err != nil {
+			pl.errorf(b.Line, CheckValue, "invalid pattern priority %q", v.Scalar)
+		}
+	}
+	if v := b.Fields["governed"]; v != nil && v.Scalar != "true" && v.Scalar != "false" {
+		pl.warnf(b.Line, CheckValue, "governed %q is not true or false; it is read as false", v.Scalar)
+	}
+}
+
+// spanDefaults reports declared spans that the policy rejects as SpanFor
+// makes them
+func (pl *policyLint) spanDefaults(policy *rift.Policy) {
+	for _, name := range sortedKeys(policy.Spans) {
+		s, err := policy.SpanFor(name)
+		if err != nil {
+			continue
+		}
+		if err := policy.ValidateSpan(s); err != nil {
+			pl.errorf(pl.spans[name], CheckAlignment, "span<%s> fails the policy as SpanFor makes it: %v", name, err)
+		}
+	}
+}
+
+// ============================================================================
+// Values
+// ============================================================================
+
+// rights parses an access list for what, reporting unknown rights (as
+// errors where the loader rejects them) and rights token_memory withholds
+func (pl *policyLint) rights(line int, what string, items []string, strict bool) (uint32, bool) {
+	var mask uint32
+	ok := true
+	for _, item := range items {
+		bit, known := accessRights[strings.ToUpper(item)]
+		if !known {
+			if strict {
+				pl.errorf(line, CheckAccess, "%s: unknown access right %q", what, item)
+			} else {
+				pl.warnf(line, CheckAccess, "%s: unknown access right %q", what, item)
+			}
+			ok = false
+			continue
+		}
+		mask |= bit
+		if what != "token_memory access" && bit&^pl.access != 0 {
+			pl.warnf(line, CheckAccess, "%s grants %s, which token_memory access withholds; spans never carry it", what, strings.ToUpper(item))
+		}
+	}
+	return mask, ok
+}
+
+// float parses a number for key, which should lie in [min, max]
+func (pl *policyLint) float(line int, key, s string, min, max float64) (float64, bool) {
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		pl.errorf(line, CheckValue, "invalid %s %q", key, s)
+		return 0, false
+	}
+	if f < min || f > max {
+		pl.warnf(line, CheckValue, "%s %g is out of range", key, f)
+	}
+	return f, true
+}
+
+// parseCall splits a value like fixed(4096) into its name and argument
+func parseCall(s string) (string, float64, bool) {
+	open := strings.IndexByte(s, '(')
+	if open < 0 || !strings.HasSuffix(s, ")") {
+		return "", 0, false
+	}
+	n, err := strconv.ParseFloat(strings.TrimSpace(s[open+1:len(s)-1]), 64)
+	if err != nil {
+		return "", 0, false
+	}
+	return strings.TrimSpace(s[:open]), n, true
+}
+
+func contains(list []string, s string) bool {
+	for _, x := range list {
+		if x == s {
+			return true
+		}
+	}
+	return false
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,216 @@
+// go/target/riftlint/riftlint.go
+// riftlint Policy and Pattern Linter - Go Implementation
+
+// Package riftlint statically checks .rift governance policies and pattern
+// files, and the pattern engine exports written by PatternEngine.Export,
+// for mistakes that load without complaint or only fail at run time:
+//
+//   - unknown block kinds and fields, which the loaders ignore
+//   - alignments no span can meet: fixed alignments that are not a power
+//     of two, type rules whose aligned(n) the policy's fixed alignment is
+//     not a multiple of, and span defaults the policy itself rejects
+//   - conflicting access masks: roles and policy functions granting rights
+//     that token_memory withholds, and rights that do not exist
+//   - rule groups that are never used: type rules naming no token type and
+//     disabled pattern groups without pairs
+//   - patterns that do not compile or repeat another pair's left pattern
+//   - values the loaders reject
+//
+// cmd/riftlint runs it over files and directories for CI, printing text
+// or JSON:
+//
+//	riftlint -json ./policies
+package riftlint
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	rift "github.com/obinexus/riftlang/bindings/go-riftlang"
+)
+
+// ============================================================================
+// Diagnostics
+// ============================================================================
+
+// Severity is how serious a finding is
+type Severity string
+
+const (
+	// SeverityError marks a file that fails to load, or a rule that no
+	// token or span can satisfy
+	SeverityError Severity = "error"
+
+	// SeverityWarning marks a file that loads but most likely does not do
+	// what its author meant
+	SeverityWarning Severity = "warning"
+)
+
+// Checks, the Check of each Diagnostic
+const (
+	CheckSyntax       = "syntax"        // the file does not parse
+	CheckValue        = "value"         // a field's value is malformed or out of range
+	CheckUnknownBlock = "unknown-block" // a block kind the policy loader ignores
+	CheckUnknownField = "unknown-field" // a field the loaders ignore
+	CheckAlignment    = "alignment"     // an alignment no span can meet
+	CheckAccess       = "access"        // access rights that do not exist or conflict
+	CheckUnusedRule   = "unused-rule"   // a type rule or group that nothing uses
+	CheckPattern      = "pattern"       // a pattern that does not compile
+	CheckDuplicate    = "duplicate"     // a declaration repeating or overriding another
+)
+
+// Diagnostic is one finding in a file
+type Diagnostic struct {
+	File     string   `json:"file"`
+	Line     int      `json:"line,omitempty"` // 0 when there is no line to point to
+	Severity Severity `json:"severity"`
+	Check    string   `json:"check"`
+	Message  string   `json:"message"`
+}
+
+// String formats the diagnostic as file:line: severity: message (check)
+func (d Diagnostic) String() string {
+	pos := d.File
+	if d.Line > 0 {
+		pos += ":" + strconv.Itoa(d.Line)
+	}
+	return fmt.Sprintf("%s: %s: %s (%s)", pos, d.Severity, d.Message, d.Check)
+}
+
+// linter collects the diagnostics of one file
+type linter struct {
+	file  string
+	diags []Diagnostic
+}
+
+func (l *linter) report(line int, sev Severity, check, format string, args ...interface{}) {
+	l.diags = append(l.diags, Diagnostic{File: l.file, Line: line, Severity: sev, Check: check, Message: fmt.Sprintf(format, args...)})
+}
+
+func (l *linter) errorf(line int, check, format string, args ...interface{}) {
+	l.report(line, SeverityError, check, format, args...)
+}
+
+func (l *linter) warnf(line int, check, format string, args ...interface{}) {
+	l.report(line, SeverityWarning, check, format, args...)
+}
+
+// hasError reports whether an error was reported at line
+func (l *linter) hasError(line int) bool {
+	for _, d := range l.diags {
+		if d.Line == line && d.Severity == SeverityError {
+			return true
+		}
+	}
+	return false
+}
+
+// sorted returns the diagnostics in line order
+func (l *linter) sorted() []Diagnostic {
+	sort.SliceStable(l.diags, func(i, j int) bool { return l.diags[i].Line < l.diags[j].Line })
+	return l.diags
+}
+
+// ============================================================================
+// Files
+// ============================================================================
+
+// LintFile lints the file at path: as an engine export if it holds a JSON
+// object, as .rift source otherwise. Only failing to read the file is an
+// error; problems with its contents are diagnostics.
+func LintFile(path string) ([]Diagnostic, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if isJSON(data) {
+		return LintEngineExport(path, data), nil
+	}
+	return LintPolicy(path, string(data)), nil
+}
+
+// Files returns the files under root that LintFile checks, sorted: .rift
+// files, and .json files holding engine exports. Like the go tool, it
+// skips testdata, vendor and directories starting with "." or "_".
+func Files(root string) ([]string, error) {
+	var files []string
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		name := d.Name()
+		if d.IsDir() {
+			if path != root && (name == "testdata" || name == "vendor" ||
+				strings.HasPrefix(name, ".") || strings.HasPrefix(name, "_")) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		switch filepath.Ext(name) {
+		case ".rift":
+			files = append(files, path)
+		case ".json":
+			if data, err := os.ReadFile(path); err == nil && IsEngineExport(data) {
+				files = append(files, path)
+			}
+		}
+		return nil
+	})
+	sort.Strings(files)
+	return files, err
+}
+
+// IsEngineExport reports whether data is a JSON object in the format
+// PatternEngine.Export writes
+func IsEngineExport(data []byte) bool {
+	var head struct {
+		Format string `json:"format"`
+	}
+	return isJSON(data) && json.Unmarshal(data, &head) == nil && head.Format == rift.EngineExportFormat
+}
+
+// isJSON reports whether data starts like a JSON object
+func isJSON(data []byte) bool {
+	data = bytes.TrimSpace(data)
+	return len(data) > 0 && data[0] == '{'
+}
+
+// ============================================================================
+// Shared Checks
+// ============================================================================
+
+// leadingLine splits the "line N: " prefix off a policy error
+var leadingLine = regexp.MustCompile(`^line (\d+): `)
+
+// splitLine returns the line a policy error names, if any, and the rest
+// of its message
+func splitLine(err error) (int, string) {
+	msg := err.Error()
+	m := leadingLine.FindStringSubmatch(msg)
+	if m == nil {
+		return 0, msg
+	}
+	n, _ := strconv.Atoi(m[1])
+	return n, msg[len(m[0]):]
+}
+
+// compilePattern reports why a left pattern does not compile, or nil
+func compilePattern(left string) error {
+	_, err := regexp.Compile(left)
+	return err
+}
+
+// powerOfTwo reports whether f is a positive integral power of two that
+// fits an alignment
+func powerOfTwo(f float64) bool {
+	n := uint64(f)
+	return float64(n) == f && n > 0 && n <= 1<<31 && n&(n-1) == 0
+}
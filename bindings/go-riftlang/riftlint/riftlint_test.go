@@ -0,0 +1,102 @@
+package riftlint
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestLintPolicyMalformedHeaders lints headers the policy parser once
+// panicked on: each must come back as a syntax error on its line
+func TestLintPolicyMalformedHeaders(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		line int
+		want string
+	}{
+		{"empty quoted kind", `"" x {}`, 1, "empty block kind"},
+		{"empty quoted kind alone", `""`, 1, "empty block kind"},
+		{"empty kind on later line", "align span<fixed> {}\n\"\" {}", 2, "empty block kind"},
+		{"body without header", `{ a: 1 }`, 1, "block without a header"},
+		{"unterminated body", "role R {\n  permissions: [READ]\n", 3, "unexpected end of input"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diags := LintPolicy("x.rift", tt.src)
+			if len(diags) != 1 {
+				t.Fatalf("LintPolicy(%q) = %v, want one syntax error", tt.src, diags)
+			}
+			d := diags[0]
+			if d.Severity != SeverityError || d.Check != CheckSyntax || d.Line != tt.line || !strings.Contains(d.Message, tt.want) {
+				t.Errorf("LintPolicy(%q) = %v, want a syntax error at line %d containing %q", tt.src, d, tt.line, tt.want)
+			}
+		})
+	}
+}
+
+func TestLintPolicyChecks(t *testing.T) {
+	src := `align span<fixed> {
+  bytes: 64
+}
+
+widget w {
+  size: 1
+}
+
+pattern "(" -> "x" {
+  priority: 1
+}
+`
+	want := map[string]int{CheckUnknownBlock: 5, CheckPattern: 9}
+	for _, d := range LintPolicy("x.rift", src) {
+		line, ok := want[d.Check]
+		if !ok {
+			t.Errorf("unexpected finding %v", d)
+			continue
+		}
+		if d.Line != line {
+			t.Errorf("%s finding at line %d, want %d", d.Check, d.Line, line)
+		}
+		delete(want, d.Check)
+	}
+	for check, line := range want {
+		t.Errorf("missing %s finding at line %d", check, line)
+	}
+}
+
+// TestLintFile lints a policy and an engine export from disk, telling them
+// apart by content
+func TestLintFile(t *testing.T) {
+	dir := t.TempDir()
+	policy := filepath.Join(dir, "bad.rift")
+	export := filepath.Join(dir, "engine.json")
+	if err := os.WriteFile(policy, []byte(`"" x {}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(export, []byte("{\n  \"format\": "), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, path := range []string{policy, export} {
+		diags, err := LintFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(diags) != 1 || diags[0].Check != CheckSyntax || diags[0].File != path {
+			t.Errorf("LintFile(%s) = %v, want one syntax error", path, diags)
+		}
+	}
+	if _, err := LintFile(filepath.Join(dir, "missing.rift")); err == nil {
+		t.Error("LintFile of a missing file succeeded")
+	}
+
+	files, err := Files(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != 1 || files[0] != policy {
+		t.Errorf("Files = %v, want only %s: the truncated export is not recognized", files, policy)
+	}
+}
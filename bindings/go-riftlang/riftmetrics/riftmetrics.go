@@ -0,0 +1,286 @@
+// go/target/riftmetrics/riftmetrics.go
+// Prometheus Exporter for Rift Governance - Go Implementation
+
+// Package riftmetrics exposes Rift token governance, pattern engine and pool
+// metrics as prometheus.Collector implementations.
+package riftmetrics
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	rift "github.com/obinexus/riftlang/bindings/go-riftlang"
+)
+
+// TokenSource returns the tokens a TokenCollector reports on
+type TokenSource func() []*rift.RiftToken
+
+// validationBitNames labels the tokens_by_bit gauge
+var validationBitNames = []struct {
+	bit  uint32
+	name string
+}{
+	{rift.TokenAllocated, "allocated"},
+	{rift.TokenInitialized, "initialized"},
+	{rift.TokenLocked, "locked"},
+	{rift.TokenGoverned, "governed"},
+	{rift.TokenSuperposed, "superposed"},
+	{rift.TokenEntangled, "entangled"},
+	{rift.TokenPersistent, "persistent"},
+	{rift.TokenShadow, "shadow"},
+}
+
+// ============================================================================
+// TokenCollector
+// ============================================================================
+
+// TokenCollector reports token counts by validation bit, lock contention and
+// superposition sizes for the tokens returned by its source
+type TokenCollector struct {
+	source TokenSource
+
+	tokens      *prometheus.Desc
+	tokensByBit *prometheus.Desc
+	contention  *prometheus.Desc
+	superposed  *prometheus.Desc
+	states      *prometheus.Desc
+}
+
+// NewTokenCollector creates a collector over source
+func NewTokenCollector(namespace string, source TokenSource) *TokenCollector {
+	return &TokenCollector{
+		source: source,
+		tokens: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "tokens", "live"),
+			"Number of live governed tokens.", nil, nil),
+		tokensByBit: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "tokens", "by_bit"),
+			"Number of live tokens with each validation bit set.", []string{"bit"}, nil),
+		contention: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "tokens", "lock_contention"),
+			"Lock acquisitions on live tokens that had to wait.", nil, nil),
+		superposed: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "tokens", "superposed"),
+			"Number of tokens in superposition.", nil, nil),
+		states: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "tokens", "superposition_states"),
+			"Total superposed states across live tokens.", nil, nil),
+	}
+}
+
+// Describe implements prometheus.Collector
+func (c *TokenCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.tokens
+	ch <- c.tokensByBit
+	ch <- c.contention
+	ch <- c.superposed
+	ch <- c.states
+}
+
+// Collect implements prometheus.Collector
+func (c *TokenCollector) Collect(ch chan<- prometheus.Metric) {
+	tokens := c.source()
+
+	counts := make([]int, len(validationBitNames))
+	var contention uint64
+	var superposed, states int
+	for _, t := range tokens {
+		for i, b := range validationBitNames {
+			if t.HasBit(b.bit) {
+				counts[i]++
+			}
+		}
+		contention += t.LockContention()
+		if t.IsSuperposed() {
+			superposed++
+			states += int(t.SuperpositionCount)
+		}
+	}
+
+	ch <- prometheus.MustNewConstMetric(c.tokens, prometheus.GaugeValue, float64(len(tokens)))
+	for i, b := range validationBitNames {
+		ch <- prometheus.MustNewConstMetric(c.tokensByBit, prometheus.GaugeValue, float64(counts[i]), b.name)
+	}
+	ch <- prometheus.MustNewConstMetric(c.contention, prometheus.GaugeValue, float64(contention))
+	ch <- prometheus.MustNewConstMetric(c.superposed, prometheus.GaugeValue, float64(superposed))
+	ch <- prometheus.MustNewConstMetric(c.states, prometheus.GaugeValue, float64(states))
+}
+
+// ============================================================================
+// PatternCollector
+// ============================================================================
+
+// PatternCollector reports match latency, match/failure counts, failure
+// rate, pair count and match cache hits/misses for a pattern engine
+type PatternCollector struct {
+	engine  *rift.PatternEngine
+	latency *prometheus.HistogramVec
+
+	matches  *prometheus.Desc
+	failures *prometheus.Desc
+	rate     *prometheus.Desc
+	pairs    *prometheus.Desc
+	hits     *prometheus.Desc
+	misses   *prometheus.Desc
+}
+
+// NewPatternCollector creates a collector for engine, labelled with name.
+// It registers a match observer on the engine to feed the latency histogram.
+func NewPatternCollector(namespace, name string, engine *rift.PatternEngine) *PatternCollector {
+	labels := prometheus.Labels{"engine": name}
+	c := &PatternCollector{
+		engine: engine,
+		latency: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace:   namespace,
+			Subsystem:   "pattern",
+			Name:        "match_duration_seconds",
+			Help:        "Pattern match latency.",
+			Buckets:     prometheus.ExponentialBuckets(1e-6, 4, 10),
+			ConstLabels: labels,
+		}, []string{"result"}),
+		matches: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "pattern", "matches_total"),
+			"Inputs that matched a pattern pair.", nil, labels),
+		failures: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "pattern", "failures_total"),
+			"Inputs that matched no pattern pair.", nil, labels),
+		rate: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "pattern", "failure_ratio"),
+			"Fraction of inputs that matched no pattern pair.", nil, labels),
+		pairs: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "pattern", "pairs"),
+			"Number of registered pattern pairs.", nil, labels),
+		hits: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "pattern", "cache_hits_total"),
+			"Matches answered from the match cache.", nil, labels),
+		misses: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "pattern", "cache_misses_total"),
+			"Matches the match cache could not answer.", nil, labels),
+	}
+
+	engine.AddMatchObserver(func(elapsed time.Duration, matched bool) {
+		result := "miss"
+		if matched {
+			result = "hit"
+		}
+		c.latency.WithLabelValues(result).Observe(elapsed.Seconds())
+	})
+	return c
+}
+
+// Describe implements prometheus.Collector
+func (c *PatternCollector) Describe(ch chan<- *prometheus.Desc) {
+	c.latency.Describe(ch)
+	ch <- c.matches
+	ch <- c.failures
+	ch <- c.rate
+	ch <- c.pairs
+	ch <- c.hits
+	ch <- c.misses
+}
+
+// Collect implements prometheus.Collector
+func (c *PatternCollector) Collect(ch chan<- prometheus.Metric) {
+	c.latency.Collect(ch)
+
+	stats := c.engine.Stats()
+	rate := 0.0
+	if total := stats.TotalMatches + stats.TotalFailures; total > 0 {
+		rate = float64(stats.TotalFailures) / float64(total)
+	}
+	ch <- prometheus.MustNewConstMetric(c.matches, prometheus.CounterValue, float64(stats.TotalMatches))
+	ch <- prometheus.MustNewConstMetric(c.failures, prometheus.CounterValue, float64(stats.TotalFailures))
+	ch <- prometheus.MustNewConstMetric(c.rate, prometheus.GaugeValue, rate)
+	ch <- prometheus.MustNewConstMetric(c.pairs, prometheus.GaugeValue, float64(stats.PairCount))
+	ch <- prometheus.MustNewConstMetric(c.hits, prometheus.CounterValue, float64(stats.CacheHits))
+	ch <- prometheus.MustNewConstMetric(c.misses, prometheus.CounterValue, float64(stats.CacheMisses))
+}
+
+// ============================================================================
+// PoolCollector
+// ============================================================================
+
+// PoolCollector reports task latency, queue depth, running tasks and task
+// outcomes for a worker pool
+type PoolCollector struct {
+	pool    *rift.Pool
+	latency *prometheus.HistogramVec
+	wait    prometheus.Histogram
+
+	workers *prometheus.Desc
+	queue   *prometheus.Desc
+	running *prometheus.Desc
+	tasks   *prometheus.Desc
+}
+
+// NewPoolCollector creates a collector for pool, labelled with name. It
+// registers a task observer on the pool to feed the latency histograms.
+func NewPoolCollector(namespace, name string, pool *rift.Pool) *PoolCollector {
+	labels := prometheus.Labels{"pool": name}
+	c := &PoolCollector{
+		pool: pool,
+		latency: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace:   namespace,
+			Subsystem:   "pool",
+			Name:        "task_duration_seconds",
+			Help:        "Task run time, excluding the wait for a worker.",
+			Buckets:     prometheus.ExponentialBuckets(1e-5, 4, 12),
+			ConstLabels: labels,
+		}, []string{"result"}),
+		wait: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Namespace:   namespace,
+			Subsystem:   "pool",
+			Name:        "task_wait_seconds",
+			Help:        "Time tasks spent queued for a worker.",
+			Buckets:     prometheus.ExponentialBuckets(1e-5, 4, 12),
+			ConstLabels: labels,
+		}),
+		workers: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "pool", "workers"),
+			"Number of pool workers.", nil, labels),
+		queue: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "pool", "queue_depth"),
+			"Tasks waiting for a worker.", nil, labels),
+		running: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "pool", "running"),
+			"Tasks running.", nil, labels),
+		tasks: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "pool", "tasks_total"),
+			"Finished tasks by result.", []string{"result"}, labels),
+	}
+
+	pool.AddTaskObserver(func(wait, run time.Duration, err error) {
+		result := "ok"
+		if err != nil {
+			result = "error"
+		}
+		c.latency.WithLabelValues(result).Observe(run.Seconds())
+		c.wait.Observe(wait.Seconds())
+	})
+	return c
+}
+
+// Describe implements prometheus.Collector
+func (c *PoolCollector) Describe(ch chan<- *prometheus.Desc) {
+	c.latency.Describe(ch)
+	c.wait.Describe(ch)
+	ch <- c.workers
+	ch <- c.queue
+	ch <- c.running
+	ch <- c.tasks
+}
+
+// Collect implements prometheus.Collector
+func (c *PoolCollector) Collect(ch chan<- prometheus.Metric) {
+	c.latency.Collect(ch)
+	c.wait.Collect(ch)
+
+	stats := c.pool.Stats()
+	ch <- prometheus.MustNewConstMetric(c.workers, prometheus.GaugeValue, float64(stats.Workers))
+	ch <- prometheus.MustNewConstMetric(c.queue, prometheus.GaugeValue, float64(stats.QueueDepth))
+	ch <- prometheus.MustNewConstMetric(c.running, prometheus.GaugeValue, float64(stats.Running))
+	ch <- prometheus.MustNewConstMetric(c.tasks, prometheus.CounterValue, float64(stats.Completed), "ok")
+	ch <- prometheus.MustNewConstMetric(c.tasks, prometheus.CounterValue, float64(stats.Failed-stats.Panicked), "error")
+	ch <- prometheus.MustNewConstMetric(c.tasks, prometheus.CounterValue, float64(stats.Panicked), "panic")
+}
@@ -0,0 +1,794 @@
+// go/target/riftpb/rift.proto
+// Rift Token Schema - Protocol Buffers
+//
+// The wire form of tokens, memory spans, match results and audit events for
+// services and other language bindings. It carries what the JSON and binary
+// token codecs carry: entanglement partners are pointers and cannot cross a
+// process boundary, so only the entanglement ID identifies the group.
+
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        (unknown)
+// source: rift.proto
+
+package riftpb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// Token is a RiftToken: the (type, value, memory) triplet with its
+// governance state
+type Token struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Type           int32                  `protobuf:"varint,1,opt,name=type,proto3" json:"type,omitempty"`
+	Value          *Value                 `protobuf:"bytes,2,opt,name=value,proto3" json:"value,omitempty"`
+	Memory         *MemorySpan            `protobuf:"bytes,3,opt,name=memory,proto3" json:"memory,omitempty"`
+	ValidationBits uint32                 `protobuf:"varint,4,opt,name=validation_bits,json=validationBits,proto3" json:"validation_bits,omitempty"`
+	// Quantum fields, valid when the superposed bit is set
+	SuperposedStates []*Token  `protobuf:"bytes,5,rep,name=superposed_states,json=superposedStates,proto3" json:"superposed_states,omitempty"`
+	Amplitudes       []float64 `protobuf:"fixed64,6,rep,packed,name=amplitudes,proto3" json:"amplitudes,omitempty"`
+	Phases           []float64 `protobuf:"fixed64,7,rep,packed,name=phases,proto3" json:"phases,omitempty"`
+	Phase            float64   `protobuf:"fixed64,8,opt,name=phase,proto3" json:"phase,omitempty"`
+	// Entanglement fields, valid when the entangled bit is set
+	EntanglementCount uint32 `protobuf:"varint,9,opt,name=entanglement_count,json=entanglementCount,proto3" json:"entanglement_count,omitempty"`
+	EntanglementId    uint32 `protobuf:"varint,10,opt,name=entanglement_id,json=entanglementId,proto3" json:"entanglement_id,omitempty"`
+	// Source location
+	SourceLine    uint32 `protobuf:"varint,11,opt,name=source_line,json=sourceLine,proto3" json:"source_line,omitempty"`
+	SourceColumn  uint32 `protobuf:"varint,12,opt,name=source_column,json=sourceColumn,proto3" json:"source_column,omitempty"`
+	SourceFile    string `protobuf:"bytes,13,opt,name=source_file,json=sourceFile,proto3" json:"source_file,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Token) Reset() {
+	*x = Token{}
+	mi := &file_rift_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Token) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Token) ProtoMessage() {}
+
+func (x *Token) ProtoReflect() protoreflect.Message {
+	mi := &file_rift_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Token.ProtoReflect.Descriptor instead.
+func (*Token) Descriptor() ([]byte, []int) {
+	return file_rift_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Token) GetType() int32 {
+	if x != nil {
+		return x.Type
+	}
+	return 0
+}
+
+func (x *Token) GetValue() *Value {
+	if x != nil {
+		return x.Value
+	}
+	return nil
+}
+
+func (x *Token) GetMemory() *MemorySpan {
+	if x != nil {
+		return x.Memory
+	}
+	return nil
+}
+
+func (x *Token) GetValidationBits() uint32 {
+	if x != nil {
+		return x.ValidationBits
+	}
+	return 0
+}
+
+func (x *Token) GetSuperposedStates() []*Token {
+	if x != nil {
+		return x.SuperposedStates
+	}
+	return nil
+}
+
+func (x *Token) GetAmplitudes() []float64 {
+	if x != nil {
+		return x.Amplitudes
+	}
+	return nil
+}
+
+func (x *Token) GetPhases() []float64 {
+	if x != nil {
+		return x.Phases
+	}
+	return nil
+}
+
+func (x *Token) GetPhase() float64 {
+	if x != nil {
+		return x.Phase
+	}
+	return 0
+}
+
+func (x *Token) GetEntanglementCount() uint32 {
+	if x != nil {
+		return x.EntanglementCount
+	}
+	return 0
+}
+
+func (x *Token) GetEntanglementId() uint32 {
+	if x != nil {
+		return x.EntanglementId
+	}
+	return 0
+}
+
+func (x *Token) GetSourceLine() uint32 {
+	if x != nil {
+		return x.SourceLine
+	}
+	return 0
+}
+
+func (x *Token) GetSourceColumn() uint32 {
+	if x != nil {
+		return x.SourceColumn
+	}
+	return 0
+}
+
+func (x *Token) GetSourceFile() string {
+	if x != nil {
+		return x.SourceFile
+	}
+	return ""
+}
+
+// Value is a RiftTokenValue
+type Value struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	IntVal    int64                  `protobuf:"zigzag64,1,opt,name=int_val,json=intVal,proto3" json:"int_val,omitempty"`
+	FloatVal  float64                `protobuf:"fixed64,2,opt,name=float_val,json=floatVal,proto3" json:"float_val,omitempty"`
+	StringVal string                 `protobuf:"bytes,3,opt,name=string_val,json=stringVal,proto3" json:"string_val,omitempty"`
+	// PtrVal encoded as JSON; empty when nil
+	PtrJson       []byte   `protobuf:"bytes,4,opt,name=ptr_json,json=ptrJson,proto3" json:"ptr_json,omitempty"`
+	ArrVal        []*Token `protobuf:"bytes,5,rep,name=arr_val,json=arrVal,proto3" json:"arr_val,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Value) Reset() {
+	*x = Value{}
+	mi := &file_rift_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Value) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Value) ProtoMessage() {}
+
+func (x *Value) ProtoReflect() protoreflect.Message {
+	mi := &file_rift_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Value.ProtoReflect.Descriptor instead.
+func (*Value) Descriptor() ([]byte, []int) {
+	return file_rift_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *Value) GetIntVal() int64 {
+	if x != nil {
+		return x.IntVal
+	}
+	return 0
+}
+
+func (x *Value) GetFloatVal() float64 {
+	if x != nil {
+		return x.FloatVal
+	}
+	return 0
+}
+
+func (x *Value) GetStringVal() string {
+	if x != nil {
+		return x.StringVal
+	}
+	return ""
+}
+
+func (x *Value) GetPtrJson() []byte {
+	if x != nil {
+		return x.PtrJson
+	}
+	return nil
+}
+
+func (x *Value) GetArrVal() []*Token {
+	if x != nil {
+		return x.ArrVal
+	}
+	return nil
+}
+
+// MemorySpan is a RiftMemorySpan's metadata. Sub-span trees are not
+// carried.
+type MemorySpan struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Type          int32                  `protobuf:"varint,1,opt,name=type,proto3" json:"type,omitempty"`
+	Bytes         uint64                 `protobuf:"varint,2,opt,name=bytes,proto3" json:"bytes,omitempty"`
+	Alignment     uint32                 `protobuf:"varint,3,opt,name=alignment,proto3" json:"alignment,omitempty"`
+	Open          bool                   `protobuf:"varint,4,opt,name=open,proto3" json:"open,omitempty"`
+	Direction     bool                   `protobuf:"varint,5,opt,name=direction,proto3" json:"direction,omitempty"`
+	AccessMask    uint32                 `protobuf:"varint,6,opt,name=access_mask,json=accessMask,proto3" json:"access_mask,omitempty"`
+	Affinity      *SpanAffinity          `protobuf:"bytes,7,opt,name=affinity,proto3" json:"affinity,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MemorySpan) Reset() {
+	*x = MemorySpan{}
+	mi := &file_rift_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MemorySpan) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MemorySpan) ProtoMessage() {}
+
+func (x *MemorySpan) ProtoReflect() protoreflect.Message {
+	mi := &file_rift_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MemorySpan.ProtoReflect.Descriptor instead.
+func (*MemorySpan) Descriptor() ([]byte, []int) {
+	return file_rift_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *MemorySpan) GetType() int32 {
+	if x != nil {
+		return x.Type
+	}
+	return 0
+}
+
+func (x *MemorySpan) GetBytes() uint64 {
+	if x != nil {
+		return x.Bytes
+	}
+	return 0
+}
+
+func (x *MemorySpan) GetAlignment() uint32 {
+	if x != nil {
+		return x.Alignment
+	}
+	return 0
+}
+
+func (x *MemorySpan) GetOpen() bool {
+	if x != nil {
+		return x.Open
+	}
+	return false
+}
+
+func (x *MemorySpan) GetDirection() bool {
+	if x != nil {
+		return x.Direction
+	}
+	return false
+}
+
+func (x *MemorySpan) GetAccessMask() uint32 {
+	if x != nil {
+		return x.AccessMask
+	}
+	return 0
+}
+
+func (x *MemorySpan) GetAffinity() *SpanAffinity {
+	if x != nil {
+		return x.Affinity
+	}
+	return nil
+}
+
+// SpanAffinity is a span's CPU affinity hint
+type SpanAffinity struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Cpu           int32                  `protobuf:"varint,1,opt,name=cpu,proto3" json:"cpu,omitempty"`
+	Node          int32                  `protobuf:"varint,2,opt,name=node,proto3" json:"node,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SpanAffinity) Reset() {
+	*x = SpanAffinity{}
+	mi := &file_rift_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SpanAffinity) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SpanAffinity) ProtoMessage() {}
+
+func (x *SpanAffinity) ProtoReflect() protoreflect.Message {
+	mi := &file_rift_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SpanAffinity.ProtoReflect.Descriptor instead.
+func (*SpanAffinity) Descriptor() ([]byte, []int) {
+	return file_rift_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *SpanAffinity) GetCpu() int32 {
+	if x != nil {
+		return x.Cpu
+	}
+	return 0
+}
+
+func (x *SpanAffinity) GetNode() int32 {
+	if x != nil {
+		return x.Node
+	}
+	return 0
+}
+
+// MatchResult is a pattern engine match
+type MatchResult struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Matched       bool                   `protobuf:"varint,1,opt,name=matched,proto3" json:"matched,omitempty"`
+	Output        string                 `protobuf:"bytes,2,opt,name=output,proto3" json:"output,omitempty"`
+	Priority      uint32                 `protobuf:"varint,3,opt,name=priority,proto3" json:"priority,omitempty"`
+	TransformId   uint32                 `protobuf:"varint,4,opt,name=transform_id,json=transformId,proto3" json:"transform_id,omitempty"`
+	Groups        map[string]string      `protobuf:"bytes,5,rep,name=groups,proto3" json:"groups,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	Start         int64                  `protobuf:"varint,6,opt,name=start,proto3" json:"start,omitempty"`
+	End           int64                  `protobuf:"varint,7,opt,name=end,proto3" json:"end,omitempty"`
+	Version       uint64                 `protobuf:"varint,8,opt,name=version,proto3" json:"version,omitempty"`
+	Text          string                 `protobuf:"bytes,9,opt,name=text,proto3" json:"text,omitempty"`
+	States        []*MatchResult         `protobuf:"bytes,10,rep,name=states,proto3" json:"states,omitempty"`
+	Amplitudes    []float64              `protobuf:"fixed64,11,rep,packed,name=amplitudes,proto3" json:"amplitudes,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MatchResult) Reset() {
+	*x = MatchResult{}
+	mi := &file_rift_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MatchResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MatchResult) ProtoMessage() {}
+
+func (x *MatchResult) ProtoReflect() protoreflect.Message {
+	mi := &file_rift_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MatchResult.ProtoReflect.Descriptor instead.
+func (*MatchResult) Descriptor() ([]byte, []int) {
+	return file_rift_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *MatchResult) GetMatched() bool {
+	if x != nil {
+		return x.Matched
+	}
+	return false
+}
+
+func (x *MatchResult) GetOutput() string {
+	if x != nil {
+		return x.Output
+	}
+	return ""
+}
+
+func (x *MatchResult) GetPriority() uint32 {
+	if x != nil {
+		return x.Priority
+	}
+	return 0
+}
+
+func (x *MatchResult) GetTransformId() uint32 {
+	if x != nil {
+		return x.TransformId
+	}
+	return 0
+}
+
+func (x *MatchResult) GetGroups() map[string]string {
+	if x != nil {
+		return x.Groups
+	}
+	return nil
+}
+
+func (x *MatchResult) GetStart() int64 {
+	if x != nil {
+		return x.Start
+	}
+	return 0
+}
+
+func (x *MatchResult) GetEnd() int64 {
+	if x != nil {
+		return x.End
+	}
+	return 0
+}
+
+func (x *MatchResult) GetVersion() uint64 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+func (x *MatchResult) GetText() string {
+	if x != nil {
+		return x.Text
+	}
+	return ""
+}
+
+func (x *MatchResult) GetStates() []*MatchResult {
+	if x != nil {
+		return x.States
+	}
+	return nil
+}
+
+func (x *MatchResult) GetAmplitudes() []float64 {
+	if x != nil {
+		return x.Amplitudes
+	}
+	return nil
+}
+
+// AuditEvent is a governance audit record
+type AuditEvent struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Seq            uint64                 `protobuf:"varint,1,opt,name=seq,proto3" json:"seq,omitempty"`
+	Time           *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=time,proto3" json:"time,omitempty"`
+	Kind           string                 `protobuf:"bytes,3,opt,name=kind,proto3" json:"kind,omitempty"`
+	Goroutine      uint64                 `protobuf:"varint,4,opt,name=goroutine,proto3" json:"goroutine,omitempty"`
+	File           string                 `protobuf:"bytes,5,opt,name=file,proto3" json:"file,omitempty"`
+	Line           int64                  `protobuf:"varint,6,opt,name=line,proto3" json:"line,omitempty"`
+	TokenType      int32                  `protobuf:"varint,7,opt,name=token_type,json=tokenType,proto3" json:"token_type,omitempty"`
+	TokenBits      uint32                 `protobuf:"varint,8,opt,name=token_bits,json=tokenBits,proto3" json:"token_bits,omitempty"`
+	EntanglementId uint32                 `protobuf:"varint,9,opt,name=entanglement_id,json=entanglementId,proto3" json:"entanglement_id,omitempty"`
+	TokenSource    string                 `protobuf:"bytes,10,opt,name=token_source,json=tokenSource,proto3" json:"token_source,omitempty"`
+	Detail         string                 `protobuf:"bytes,11,opt,name=detail,proto3" json:"detail,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *AuditEvent) Reset() {
+	*x = AuditEvent{}
+	mi := &file_rift_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AuditEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AuditEvent) ProtoMessage() {}
+
+func (x *AuditEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_rift_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AuditEvent.ProtoReflect.Descriptor instead.
+func (*AuditEvent) Descriptor() ([]byte, []int) {
+	return file_rift_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *AuditEvent) GetSeq() uint64 {
+	if x != nil {
+		return x.Seq
+	}
+	return 0
+}
+
+func (x *AuditEvent) GetTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Time
+	}
+	return nil
+}
+
+func (x *AuditEvent) GetKind() string {
+	if x != nil {
+		return x.Kind
+	}
+	return ""
+}
+
+func (x *AuditEvent) GetGoroutine() uint64 {
+	if x != nil {
+		return x.Goroutine
+	}
+	return 0
+}
+
+func (x *AuditEvent) GetFile() string {
+	if x != nil {
+		return x.File
+	}
+	return ""
+}
+
+func (x *AuditEvent) GetLine() int64 {
+	if x != nil {
+		return x.Line
+	}
+	return 0
+}
+
+func (x *AuditEvent) GetTokenType() int32 {
+	if x != nil {
+		return x.TokenType
+	}
+	return 0
+}
+
+func (x *AuditEvent) GetTokenBits() uint32 {
+	if x != nil {
+		return x.TokenBits
+	}
+	return 0
+}
+
+func (x *AuditEvent) GetEntanglementId() uint32 {
+	if x != nil {
+		return x.EntanglementId
+	}
+	return 0
+}
+
+func (x *AuditEvent) GetTokenSource() string {
+	if x != nil {
+		return x.TokenSource
+	}
+	return ""
+}
+
+func (x *AuditEvent) GetDetail() string {
+	if x != nil {
+		return x.Detail
+	}
+	return ""
+}
+
+var File_rift_proto protoreflect.FileDescriptor
+
+const file_rift_proto_rawDesc = "" +
+	"\n" +
+	"\n" +
+	"rift.proto\x12\x04rift\x1a\x1fgoogle/protobuf/timestamp.proto\"\xd8\x03\n" +
+	"\x05Token\x12\x12\n" +
+	"\x04type\x18\x01 \x01(\x05R\x04type\x12!\n" +
+	"\x05value\x18\x02 \x01(\v2\v.rift.ValueR\x05value\x12(\n" +
+	"\x06memory\x18\x03 \x01(\v2\x10.rift.MemorySpanR\x06memory\x12'\n" +
+	"\x0fvalidation_bits\x18\x04 \x01(\rR\x0evalidationBits\x128\n" +
+	"\x11superposed_states\x18\x05 \x03(\v2\v.rift.TokenR\x10superposedStates\x12\x1e\n" +
+	"\n" +
+	"amplitudes\x18\x06 \x03(\x01R\n" +
+	"amplitudes\x12\x16\n" +
+	"\x06phases\x18\a \x03(\x01R\x06phases\x12\x14\n" +
+	"\x05phase\x18\b \x01(\x01R\x05phase\x12-\n" +
+	"\x12entanglement_count\x18\t \x01(\rR\x11entanglementCount\x12'\n" +
+	"\x0fentanglement_id\x18\n" +
+	" \x01(\rR\x0eentanglementId\x12\x1f\n" +
+	"\vsource_line\x18\v \x01(\rR\n" +
+	"sourceLine\x12#\n" +
+	"\rsource_column\x18\f \x01(\rR\fsourceColumn\x12\x1f\n" +
+	"\vsource_file\x18\r \x01(\tR\n" +
+	"sourceFile\"\x9d\x01\n" +
+	"\x05Value\x12\x17\n" +
+	"\aint_val\x18\x01 \x01(\x12R\x06intVal\x12\x1b\n" +
+	"\tfloat_val\x18\x02 \x01(\x01R\bfloatVal\x12\x1d\n" +
+	"\n" +
+	"string_val\x18\x03 \x01(\tR\tstringVal\x12\x19\n" +
+	"\bptr_json\x18\x04 \x01(\fR\aptrJson\x12$\n" +
+	"\aarr_val\x18\x05 \x03(\v2\v.rift.TokenR\x06arrVal\"\xd7\x01\n" +
+	"\n" +
+	"MemorySpan\x12\x12\n" +
+	"\x04type\x18\x01 \x01(\x05R\x04type\x12\x14\n" +
+	"\x05bytes\x18\x02 \x01(\x04R\x05bytes\x12\x1c\n" +
+	"\talignment\x18\x03 \x01(\rR\talignment\x12\x12\n" +
+	"\x04open\x18\x04 \x01(\bR\x04open\x12\x1c\n" +
+	"\tdirection\x18\x05 \x01(\bR\tdirection\x12\x1f\n" +
+	"\vaccess_mask\x18\x06 \x01(\rR\n" +
+	"accessMask\x12.\n" +
+	"\baffinity\x18\a \x01(\v2\x12.rift.SpanAffinityR\baffinity\"4\n" +
+	"\fSpanAffinity\x12\x10\n" +
+	"\x03cpu\x18\x01 \x01(\x05R\x03cpu\x12\x12\n" +
+	"\x04node\x18\x02 \x01(\x05R\x04node\"\x91\x03\n" +
+	"\vMatchResult\x12\x18\n" +
+	"\amatched\x18\x01 \x01(\bR\amatched\x12\x16\n" +
+	"\x06output\x18\x02 \x01(\tR\x06output\x12\x1a\n" +
+	"\bpriority\x18\x03 \x01(\rR\bpriority\x12!\n" +
+	"\ftransform_id\x18\x04 \x01(\rR\vtransformId\x125\n" +
+	"\x06groups\x18\x05 \x03(\v2\x1d.rift.MatchResult.GroupsEntryR\x06groups\x12\x14\n" +
+	"\x05start\x18\x06 \x01(\x03R\x05start\x12\x10\n" +
+	"\x03end\x18\a \x01(\x03R\x03end\x12\x18\n" +
+	"\aversion\x18\b \x01(\x04R\aversion\x12\x12\n" +
+	"\x04text\x18\t \x01(\tR\x04text\x12)\n" +
+	"\x06states\x18\n" +
+	" \x03(\v2\x11.rift.MatchResultR\x06states\x12\x1e\n" +
+	"\n" +
+	"amplitudes\x18\v \x03(\x01R\n" +
+	"amplitudes\x1a9\n" +
+	"\vGroupsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\xca\x02\n" +
+	"\n" +
+	"AuditEvent\x12\x10\n" +
+	"\x03seq\x18\x01 \x01(\x04R\x03seq\x12.\n" +
+	"\x04time\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\x04time\x12\x12\n" +
+	"\x04kind\x18\x03 \x01(\tR\x04kind\x12\x1c\n" +
+	"\tgoroutine\x18\x04 \x01(\x04R\tgoroutine\x12\x12\n" +
+	"\x04file\x18\x05 \x01(\tR\x04file\x12\x12\n" +
+	"\x04line\x18\x06 \x01(\x03R\x04line\x12\x1d\n" +
+	"\n" +
+	"token_type\x18\a \x01(\x05R\ttokenType\x12\x1d\n" +
+	"\n" +
+	"token_bits\x18\b \x01(\rR\ttokenBits\x12'\n" +
+	"\x0fentanglement_id\x18\t \x01(\rR\x0eentanglementId\x12!\n" +
+	"\ftoken_source\x18\n" +
+	" \x01(\tR\vtokenSource\x12\x16\n" +
+	"\x06detail\x18\v \x01(\tR\x06detailB:Z8github.com/obinexus/riftlang/bindings/go-riftlang/riftpbb\x06proto3"
+
+var (
+	file_rift_proto_rawDescOnce sync.Once
+	file_rift_proto_rawDescData []byte
+)
+
+func file_rift_proto_rawDescGZIP() []byte {
+	file_rift_proto_rawDescOnce.Do(func() {
+		file_rift_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_rift_proto_rawDesc), len(file_rift_proto_rawDesc)))
+	})
+	return file_rift_proto_rawDescData
+}
+
+var file_rift_proto_msgTypes = make([]protoimpl.MessageInfo, 7)
+var file_rift_proto_goTypes = []any{
+	(*Token)(nil),                 // 0: rift.Token
+	(*Value)(nil),                 // 1: rift.Value
+	(*MemorySpan)(nil),            // 2: rift.MemorySpan
+	(*SpanAffinity)(nil),          // 3: rift.SpanAffinity
+	(*MatchResult)(nil),           // 4: rift.MatchResult
+	(*AuditEvent)(nil),            // 5: rift.AuditEvent
+	nil,                           // 6: rift.MatchResult.GroupsEntry
+	(*timestamppb.Timestamp)(nil), // 7: google.protobuf.Timestamp
+}
+var file_rift_proto_depIdxs = []int32{
+	1, // 0: rift.Token.value:type_name -> rift.Value
+	2, // 1: rift.Token.memory:type_name -> rift.MemorySpan
+	0, // 2: rift.Token.superposed_states:type_name -> rift.Token
+	0, // 3: rift.Value.arr_val:type_name -> rift.Token
+	3, // 4: rift.MemorySpan.affinity:type_name -> rift.SpanAffinity
+	6, // 5: rift.MatchResult.groups:type_name -> rift.MatchResult.GroupsEntry
+	4, // 6: rift.MatchResult.states:type_name -> rift.MatchResult
+	7, // 7: rift.AuditEvent.time:type_name -> google.protobuf.Timestamp
+	8, // [8:8] is the sub-list for method output_type
+	8, // [8:8] is the sub-list for method input_type
+	8, // [8:8] is the sub-list for extension type_name
+	8, // [8:8] is the sub-list for extension extendee
+	0, // [0:8] is the sub-list for field type_name
+}
+
+func init() { file_rift_proto_init() }
+func file_rift_proto_init() {
+	if File_rift_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_rift_proto_rawDesc), len(file_rift_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   7,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_rift_proto_goTypes,
+		DependencyIndexes: file_rift_proto_depIdxs,
+		MessageInfos:      file_rift_proto_msgTypes,
+	}.Build()
+	File_rift_proto = out.File
+	file_rift_proto_goTypes = nil
+	file_rift_proto_depIdxs = nil
+}
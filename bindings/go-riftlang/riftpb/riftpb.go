@@ -0,0 +1,288 @@
+// go/target/riftpb/riftpb.go
+// Protocol Buffers Token Codec - Go Implementation
+
+// Package riftpb is the Protocol Buffers form of Rift tokens, memory spans,
+// match results and audit events, for services and bindings in other
+// languages. rift.proto is the schema and rift.pb.go its generated code;
+// this file converts between the generated messages and the rift types.
+package riftpb
+
+//go:generate protoc --go_out=. --go_opt=paths=source_relative rift.proto
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	rift "github.com/obinexus/riftlang/bindings/go-riftlang"
+)
+
+// Marshal encodes t in the Protocol Buffers wire format
+func Marshal(t *rift.RiftToken) ([]byte, error) {
+	m, err := FromToken(t)
+	if err != nil {
+		return nil, err
+	}
+	return proto.Marshal(m)
+}
+
+// Unmarshal decodes a token from the Protocol Buffers wire format
+func Unmarshal(data []byte) (*rift.RiftToken, error) {
+	var m Token
+	if err := proto.Unmarshal(data, &m); err != nil {
+		return nil, err
+	}
+	return ToToken(&m)
+}
+
+// ============================================================================
+// Tokens
+// ============================================================================
+
+// FromToken converts t to its message. PtrVal is encoded as JSON, as the
+// binary codec does.
+func FromToken(t *rift.RiftToken) (*Token, error) {
+	if t == nil {
+		return nil, nil
+	}
+	m := &Token{
+		Type:              int32(t.Type),
+		Memory:            FromSpan(t.Memory),
+		ValidationBits:    t.ValidationBits.Load(),
+		Amplitudes:        t.Amplitudes,
+		Phases:            t.Phases,
+		Phase:             t.Phase,
+		EntanglementCount: t.EntanglementCount,
+		EntanglementId:    t.EntanglementID,
+		SourceLine:        t.SourceLine,
+		SourceColumn:      t.SourceColumn,
+		SourceFile:        t.SourceFile,
+	}
+	var err error
+	if m.Value, err = fromValue(t.Value); err != nil {
+		return nil, err
+	}
+	if m.SuperposedStates, err = fromTokens(t.SuperposedStates); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// ToToken restores a token from its message. The Locked bit is cleared
+// since no lock is held by the restored token, and it has no entanglement
+// partners.
+func ToToken(m *Token) (*rift.RiftToken, error) {
+	if m == nil {
+		return nil, nil
+	}
+	t := rift.NewRiftToken(int(m.Type), ToSpan(m.Memory))
+	var err error
+	if t.Value, err = toValue(m.Value); err != nil {
+		return nil, err
+	}
+	t.ValidationBits.Store(m.ValidationBits &^ rift.TokenLocked)
+	if t.SuperposedStates, err = toTokens(m.SuperposedStates); err != nil {
+		return nil, err
+	}
+	t.SuperpositionCount = uint32(len(t.SuperposedStates))
+	t.Amplitudes = m.Amplitudes
+	t.Phases = m.Phases
+	t.Phase = m.Phase
+	t.EntanglementCount = m.EntanglementCount
+	t.EntanglementID = m.EntanglementId
+	t.SourceLine = m.SourceLine
+	t.SourceColumn = m.SourceColumn
+	t.SourceFile = m.SourceFile
+	return t, nil
+}
+
+func fromValue(v rift.RiftTokenValue) (*Value, error) {
+	m := &Value{IntVal: v.IntVal, FloatVal: v.FloatVal, StringVal: v.StringVal}
+	if v.PtrVal != nil {
+		ptr, err := json.Marshal(v.PtrVal)
+		if err != nil {
+			return nil, fmt.Errorf("encode pointer value: %w", err)
+		}
+		m.PtrJson = ptr
+	}
+	var err error
+	if m.ArrVal, err = fromTokens(v.ArrVal); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func toValue(m *Value) (rift.RiftTokenValue, error) {
+	v := rift.RiftTokenValue{IntVal: m.GetIntVal(), FloatVal: m.GetFloatVal(), StringVal: m.GetStringVal()}
+	if ptr := m.GetPtrJson(); len(ptr) > 0 {
+		if err := json.Unmarshal(ptr, &v.PtrVal); err != nil {
+			return v, fmt.Errorf("decode pointer value: %w", err)
+		}
+	}
+	var err error
+	v.ArrVal, err = toTokens(m.GetArrVal())
+	return v, err
+}
+
+func fromTokens(tokens []*rift.RiftToken) ([]*Token, error) {
+	if tokens == nil {
+		return nil, nil
+	}
+	out := make([]*Token, len(tokens))
+	for i, t := range tokens {
+		m, err := FromToken(t)
+		if err != nil {
+			return nil, err
+		}
+		if m == nil {
+			m = &Token{}
+		}
+		out[i] = m
+	}
+	return out, nil
+}
+
+func toTokens(ms []*Token) ([]*rift.RiftToken, error) {
+	if ms == nil {
+		return nil, nil
+	}
+	out := make([]*rift.RiftToken, len(ms))
+	for i, m := range ms {
+		t, err := ToToken(m)
+		if err != nil {
+			return nil, err
+		}
+		out[i] = t
+	}
+	return out, nil
+}
+
+// ============================================================================
+// Memory Spans
+// ============================================================================
+
+// FromSpan converts a span's metadata to its message
+func FromSpan(s *rift.RiftMemorySpan) *MemorySpan {
+	if s == nil {
+		return nil
+	}
+	m := &MemorySpan{
+		Type:       int32(s.Type),
+		Bytes:      s.Bytes,
+		Alignment:  s.Alignment,
+		Open:       s.Open,
+		Direction:  s.Direction,
+		AccessMask: s.Mask(),
+	}
+	if aff, ok := s.Affinity(); ok {
+		m.Affinity = &SpanAffinity{Cpu: int32(aff.CPU), Node: int32(aff.Node)}
+	}
+	return m
+}
+
+// ToSpan restores a root span from its message. An affinity's node is
+// looked up again for its CPU, since the topology may differ from the
+// sender's.
+func ToSpan(m *MemorySpan) *rift.RiftMemorySpan {
+	if m == nil {
+		return nil
+	}
+	var s *rift.RiftMemorySpan
+	if m.Affinity != nil {
+		s = rift.NewRiftMemorySpanForCPU(int(m.Affinity.Cpu), int(m.Type), m.Bytes)
+	} else {
+		s = rift.NewRiftMemorySpan(int(m.Type), m.Bytes)
+	}
+	s.Alignment = m.Alignment
+	s.Open = m.Open
+	s.Direction = m.Direction
+	s.AccessMask = m.AccessMask
+	return s
+}
+
+// ============================================================================
+// Match Results and Audit Events
+// ============================================================================
+
+// FromMatchResult converts a match result, with its superposed states, to
+// its message
+func FromMatchResult(r rift.MatchResult) *MatchResult {
+	m := &MatchResult{
+		Matched:     r.Matched,
+		Output:      r.Output,
+		Priority:    r.Priority,
+		TransformId: r.TransformID,
+		Groups:      r.Groups,
+		Start:       int64(r.Start),
+		End:         int64(r.End),
+		Version:     r.Version,
+		Text:        r.Text,
+		Amplitudes:  r.Amplitudes,
+	}
+	for _, s := range r.States {
+		m.States = append(m.States, FromMatchResult(s))
+	}
+	return m
+}
+
+// ToMatchResult restores a match result from its message. Groups is nil
+// when the message has none, as for a pattern without named groups.
+func ToMatchResult(m *MatchResult) rift.MatchResult {
+	r := rift.MatchResult{
+		Matched:     m.GetMatched(),
+		Output:      m.GetOutput(),
+		Priority:    m.GetPriority(),
+		TransformID: m.GetTransformId(),
+		Start:       int(m.GetStart()),
+		End:         int(m.GetEnd()),
+		Version:     m.GetVersion(),
+		Text:        m.GetText(),
+		Amplitudes:  m.GetAmplitudes(),
+	}
+	if len(m.GetGroups()) > 0 {
+		r.Groups = m.GetGroups()
+	}
+	for _, s := range m.GetStates() {
+		r.States = append(r.States, ToMatchResult(s))
+	}
+	return r
+}
+
+// FromAuditEvent converts an audit event to its message
+func FromAuditEvent(e rift.AuditEvent) *AuditEvent {
+	return &AuditEvent{
+		Seq:            e.Seq,
+		Time:           timestamppb.New(e.Time),
+		Kind:           string(e.Kind),
+		Goroutine:      e.Goroutine,
+		File:           e.File,
+		Line:           int64(e.Line),
+		TokenType:      int32(e.TokenType),
+		TokenBits:      e.TokenBits,
+		EntanglementId: e.EntanglementID,
+		TokenSource:    e.TokenSource,
+		Detail:         e.Detail,
+	}
+}
+
+// ToAuditEvent restores an audit event from its message
+func ToAuditEvent(m *AuditEvent) rift.AuditEvent {
+	e := rift.AuditEvent{
+		Seq:            m.GetSeq(),
+		Kind:           rift.AuditEventKind(m.GetKind()),
+		Goroutine:      m.GetGoroutine(),
+		File:           m.GetFile(),
+		Line:           int(m.GetLine()),
+		TokenType:      int(m.GetTokenType()),
+		TokenBits:      m.GetTokenBits(),
+		EntanglementID: m.GetEntanglementId(),
+		TokenSource:    m.GetTokenSource(),
+		Detail:         m.GetDetail(),
+	}
+	if m.GetTime() != nil {
+		e.Time = m.GetTime().AsTime()
+	}
+	return e
+}
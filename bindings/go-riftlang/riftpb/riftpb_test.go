@@ -0,0 +1,158 @@
+package riftpb
+
+import (
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+
+	"google.golang.org/protobuf/proto"
+
+	rift "github.com/obinexus/riftlang/bindings/go-riftlang"
+)
+
+func TestTokenRoundTrip(t *testing.T) {
+	long := strings.Repeat("héllo ", 12000)
+	tests := []struct {
+		name      string
+		tokenType int
+		value     rift.RiftTokenValue
+	}{
+		{"negative int", rift.TokenGoInt, rift.RiftTokenValue{IntVal: -1}},
+		{"int64 min", rift.TokenGoInt, rift.RiftTokenValue{IntVal: math.MinInt64}},
+		{"int64 max", rift.TokenGoInt, rift.RiftTokenValue{IntVal: math.MaxInt64}},
+		{"negative float", rift.TokenGoFloat, rift.RiftTokenValue{FloatVal: -2.5e-300}},
+		{"long string", rift.TokenGoString, rift.RiftTokenValue{StringVal: long}},
+		{"pointer", rift.TokenGoPtr, rift.RiftTokenValue{PtrVal: []interface{}{"a", -1.5, true}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			span := rift.NewRiftMemorySpan(rift.SpanRow, 128)
+			span.Revoke(rift.AccessDelete)
+			src := rift.NewRiftToken(tt.tokenType, span)
+			if err := src.SetValue(tt.value); err != nil {
+				t.Fatal(err)
+			}
+			src.Lock()
+			data, err := Marshal(src)
+			src.Unlock()
+			if err != nil {
+				t.Fatal(err)
+			}
+			dst, err := Unmarshal(data)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if dst.Type != src.Type || !reflect.DeepEqual(dst.Value, src.Value) {
+				t.Errorf("decoded %s %+v, want %s %+v", rift.TokenTypeName(dst.Type), dst.Value, rift.TokenTypeName(src.Type), src.Value)
+			}
+			if dst.HasBit(rift.TokenLocked) {
+				t.Error("decoded token is locked")
+			}
+			if !dst.HasBit(rift.TokenInitialized) {
+				t.Error("decoded token not initialized")
+			}
+			m := dst.Memory
+			if m.Type != rift.SpanRow || m.Bytes != 128 || m.Mask() != rift.AccessCRUD&^rift.AccessDelete {
+				t.Errorf("decoded span %+v", m)
+			}
+		})
+	}
+
+	t.Run("superposed", func(t *testing.T) {
+		src := rift.Superpose(int64(-5), "two", 3.5)
+		data, err := Marshal(src)
+		if err != nil {
+			t.Fatal(err)
+		}
+		dst, err := Unmarshal(data)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(dst.SuperposedStates) != 3 || !reflect.DeepEqual(dst.Amplitudes, src.Amplitudes) {
+			t.Fatalf("decoded %d states with amplitudes %v", len(dst.SuperposedStates), dst.Amplitudes)
+		}
+		for i, s := range dst.SuperposedStates {
+			if want := src.SuperposedStates[i]; s.Type != want.Type || !reflect.DeepEqual(s.Value, want.Value) {
+				t.Errorf("state %d = %+v, want %+v", i, s.Value, want.Value)
+			}
+		}
+	})
+}
+
+func TestMatchResultRoundTrip(t *testing.T) {
+	src := rift.MatchResult{
+		Matched:     true,
+		Output:      strings.Repeat("out", 100),
+		Priority:    7,
+		TransformID: 3,
+		Groups:      map[string]string{"name": "x", "value": ""},
+		Start:       -1,
+		End:         40,
+		Text:        "matched",
+		Amplitudes:  []float64{0.6, -0.8},
+		States: []rift.MatchResult{
+			{Matched: true, Output: "a", Priority: 7, TransformID: 3, End: 1, Text: "a"},
+			{Matched: true, Output: "b", Priority: 1, TransformID: 4, Groups: map[string]string{"k": "v"}},
+		},
+		Version: 1 << 40,
+	}
+	data, err := proto.Marshal(FromMatchResult(src))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var m MatchResult
+	if err := proto.Unmarshal(data, &m); err != nil {
+		t.Fatal(err)
+	}
+	if got := ToMatchResult(&m); !reflect.DeepEqual(got, src) {
+		t.Errorf("decoded\n%+v\nwant\n%+v", got, src)
+	}
+}
+
+// TestUnmarshalMalformed decodes broken input, which must fail, not panic
+func TestUnmarshalMalformed(t *testing.T) {
+	src := rift.NewRiftToken(rift.TokenGoString, rift.NewRiftMemorySpan(rift.SpanFixed, 64))
+	if err := src.SetValue(rift.RiftTokenValue{StringVal: strings.Repeat("s", 1000)}); err != nil {
+		t.Fatal(err)
+	}
+	data, err := Marshal(src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	badJSON, err := proto.Marshal(&Token{Value: &Value{PtrJson: []byte("{")}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	nestedBadJSON, err := proto.Marshal(&Token{SuperposedStates: []*Token{{Value: &Value{PtrJson: []byte("[1,")}}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{"truncated string", data[:len(data)/2]},
+		{"length past the end", []byte{0x12, 0x05, 0x18}},
+		{"truncated varint", []byte{0x08, 0x80}},
+		{"invalid wire type", []byte{0x0e}},
+		{"field number zero", []byte{0x00, 0x01}},
+		{"bad pointer JSON", badJSON},
+		{"bad pointer JSON in a state", nestedBadJSON},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tok, err := Unmarshal(tt.data); err == nil {
+				t.Errorf("decoded %+v", tok)
+			}
+		})
+	}
+
+	// Proto3 strings must be UTF-8
+	bad := rift.NewRiftToken(rift.TokenGoString, rift.NewRiftMemorySpan(rift.SpanFixed, 64))
+	bad.Value.StringVal = "\xff"
+	if _, err := Marshal(bad); err == nil {
+		t.Error("token with invalid UTF-8 encoded")
+	}
+}
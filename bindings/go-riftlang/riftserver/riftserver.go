@@ -0,0 +1,305 @@
+// go/target/riftserver/riftserver.go
+// Governance Sidecar Service - Go Implementation
+
+// Package riftserver exposes the Go governance engine over gRPC so non-Go
+// processes and remote clients can use it as a sidecar. Tokens live on the
+// server and are referred to by handles, released with Release. Every RPC
+// is recorded in the rift audit log as an AuditRPC event.
+package riftserver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"net"
+	"sync"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	rift "github.com/obinexus/riftlang/bindings/go-riftlang"
+)
+
+// Options configures a Server
+type Options struct {
+	// Engine serves Match requests that name no registered engine. The
+	// default is rift.CreateDefaultEngine().
+	Engine *rift.PatternEngine
+
+	// Policy validates tokens; nil validates structurally
+	Policy *rift.GovernancePolicy
+}
+
+// Server implements the riftserver.Governance service
+type Server struct {
+	opts Options
+
+	lock   sync.Mutex
+	next   uint64
+	tokens map[uint64]*rift.RiftToken
+	server *grpc.Server
+}
+
+// NewServer creates a server with no tokens
+func NewServer(opts Options) *Server {
+	if opts.Engine == nil {
+		opts.Engine = rift.CreateDefaultEngine()
+	}
+	return &Server{opts: opts, tokens: make(map[uint64]*rift.RiftToken)}
+}
+
+// Register adds the service to a gRPC server owned by the caller
+func (s *Server) Register(gs *grpc.Server) {
+	gs.RegisterService(&serviceDesc, s)
+}
+
+// Serve accepts requests on lis until Close
+func (s *Server) Serve(lis net.Listener) error {
+	s.lock.Lock()
+	if s.server == nil {
+		s.server = grpc.NewServer()
+		s.Register(s.server)
+	}
+	server := s.server
+	s.lock.Unlock()
+	return server.Serve(lis)
+}
+
+// Close stops the server started by Serve
+func (s *Server) Close() {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if s.server != nil {
+		s.server.Stop()
+		s.server = nil
+	}
+}
+
+// ============================================================================
+// Handles
+// ============================================================================
+
+// put stores t under a new handle
+func (s *Server) put(t *rift.RiftToken) uint64 {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.next++
+	s.tokens[s.next] = t
+	return s.next
+}
+
+// get returns the token under handle
+func (s *Server) get(handle uint64) (*rift.RiftToken, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	t, ok := s.tokens[handle]
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "unknown token handle %d", handle)
+	}
+	return t, nil
+}
+
+// ============================================================================
+// RPCs
+// ============================================================================
+
+// CreateToken allocates a token, initializing it when req.Value is set
+func (s *Server) CreateToken(ctx context.Context, req *CreateTokenRequest) (reply *TokenReply, err error) {
+	var t *rift.RiftToken
+	defer func() { s.audit("CreateToken", t, err) }()
+	t = rift.NewRiftToken(req.Type, rift.NewRiftMemorySpan(req.SpanType, req.Bytes))
+	if req.Value != nil {
+		if err := t.SetValue(*req.Value); err != nil {
+			return nil, statusError(err)
+		}
+	}
+	if reply, err = s.reply(0, t); err != nil {
+		return nil, err
+	}
+	reply.Handle = s.put(t)
+	return reply, nil
+}
+
+// GetToken returns a token's current state
+func (s *Server) GetToken(ctx context.Context, req *HandleRequest) (reply *TokenReply, err error) {
+	t, err := s.get(req.Handle)
+	defer func() { s.audit("GetToken", t, err) }()
+	if err != nil {
+		return nil, err
+	}
+	t.RLock()
+	defer t.RUnlock()
+	return s.reply(req.Handle, t)
+}
+
+// SetValue writes a token's value under its lock
+func (s *Server) SetValue(ctx context.Context, req *SetValueRequest) (reply *TokenReply, err error) {
+	t, err := s.get(req.Handle)
+	defer func() { s.audit("SetValue", t, err) }()
+	if err != nil {
+		return nil, err
+	}
+	if err := t.LockContext(ctx); err != nil {
+		return nil, statusError(err)
+	}
+	defer t.Unlock()
+	if err := t.SetValue(req.Value); err != nil {
+		return nil, statusError(err)
+	}
+	return s.reply(req.Handle, t)
+}
+
+// Validate validates a token against the server's policy
+func (s *Server) Validate(ctx context.Context, req *HandleRequest) (reply *ValidateReply, err error) {
+	t, err := s.get(req.Handle)
+	defer func() { s.audit("Validate", t, err) }()
+	if err != nil {
+		return nil, err
+	}
+	t.RLock()
+	defer t.RUnlock()
+	if verr := t.ValidateAgainst(s.opts.Policy); verr != nil {
+		reply := &ValidateReply{Code: rift.ErrorCodeOf(verr).String(), Detail: verr.Error()}
+		var ge *rift.GovernanceError
+		if errors.As(verr, &ge) {
+			reply.Detail = ge.Detail
+		}
+		return reply, nil
+	}
+	return &ValidateReply{Valid: true}, nil
+}
+
+// Superpose creates a superposed token over req.States
+func (s *Server) Superpose(ctx context.Context, req *SuperposeRequest) (reply *TokenReply, err error) {
+	var t *rift.RiftToken
+	defer func() { s.audit("Superpose", t, err) }()
+	if len(req.States) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "superpose needs at least one state")
+	}
+	states := make([]interface{}, len(req.States))
+	for i, v := range req.States {
+		states[i] = stateValue(v)
+	}
+	t = rift.Superpose(states...)
+	if reply, err = s.reply(0, t); err != nil {
+		return nil, err
+	}
+	reply.Handle = s.put(t)
+	return reply, nil
+}
+
+// Collapse collapses a superposed token to the state at req.Index
+func (s *Server) Collapse(ctx context.Context, req *CollapseRequest) (reply *TokenReply, err error) {
+	t, err := s.get(req.Handle)
+	defer func() { s.audit("Collapse", t, err) }()
+	if err != nil {
+		return nil, err
+	}
+	if err := t.LockContext(ctx); err != nil {
+		return nil, statusError(err)
+	}
+	defer t.Unlock()
+	if err := t.CollapseErr(req.Index); err != nil {
+		return nil, statusError(err)
+	}
+	return s.reply(req.Handle, t)
+}
+
+// Match matches req.Input against the named engine or the server's engine
+func (s *Server) Match(ctx context.Context, req *MatchRequest) (reply *rift.MatchResult, err error) {
+	defer func() { s.audit("Match", nil, err) }()
+	engine := s.opts.Engine
+	if req.Engine != "" {
+		var ok bool
+		if engine, ok = rift.LookupEngine(req.Engine); !ok {
+			return nil, status.Errorf(codes.NotFound, "no engine registered as %q", req.Engine)
+		}
+	}
+	result, err := engine.MatchContext(ctx, req.Input)
+	if err != nil {
+		return nil, statusError(err)
+	}
+	return result, nil
+}
+
+// Release drops a token handle
+func (s *Server) Release(ctx context.Context, req *HandleRequest) (reply *Empty, err error) {
+	t, err := s.get(req.Handle)
+	defer func() { s.audit("Release", t, err) }()
+	if err != nil {
+		return nil, err
+	}
+	s.lock.Lock()
+	delete(s.tokens, req.Handle)
+	s.lock.Unlock()
+	return &Empty{}, nil
+}
+
+// ============================================================================
+// Helpers
+// ============================================================================
+
+// reply builds a TokenReply holding a copy of t, so the reply can be
+// encoded after t's lock is released; t's lock held or t not yet stored
+func (s *Server) reply(handle uint64, t *rift.RiftToken) (*TokenReply, error) {
+	data, err := t.MarshalJSON()
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	out := new(rift.RiftToken)
+	if err := out.UnmarshalJSON(data); err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	return &TokenReply{Handle: handle, Token: out}, nil
+}
+
+// audit records an RPC and its outcome
+func (s *Server) audit(method string, t *rift.RiftToken, err error) {
+	detail := method + ": ok"
+	if err != nil {
+		detail = fmt.Sprintf("%s: %s", method, status.Convert(err).Message())
+	}
+	rift.EmitAudit(rift.AuditRPC, t, detail)
+}
+
+// stateValue converts a JSON-decoded state into a rift.Superpose argument
+func stateValue(v interface{}) interface{} {
+	if f, ok := v.(float64); ok && f == math.Trunc(f) && math.Abs(f) < 1<<53 {
+		return int64(f)
+	}
+	return v
+}
+
+// statusCodes maps governance error codes to gRPC status codes; unlisted
+// codes map to FailedPrecondition
+var statusCodes = map[rift.ErrorCode]codes.Code{
+	rift.CodePermissionDenied: codes.PermissionDenied,
+	rift.CodeIndexOutOfRange:  codes.OutOfRange,
+	rift.CodeSpanBounds:       codes.OutOfRange,
+	rift.CodeRegexCompile:     codes.InvalidArgument,
+	rift.CodeConversion:       codes.InvalidArgument,
+	rift.CodeLockTimeout:      codes.DeadlineExceeded,
+	rift.CodeCanceled:         codes.Canceled,
+	rift.CodeDeadlock:         codes.Aborted,
+}
+
+// statusError converts a governance error into a gRPC status error
+func statusError(err error) error {
+	code := rift.ErrorCodeOf(err)
+	if code == rift.CodeOK {
+		if errors.Is(err, rift.ErrLockTimeout) || errors.Is(err, context.DeadlineExceeded) {
+			return status.Error(codes.DeadlineExceeded, err.Error())
+		}
+		if errors.Is(err, context.Canceled) {
+			return status.Error(codes.Canceled, err.Error())
+		}
+		return status.Error(codes.Internal, err.Error())
+	}
+	c, ok := statusCodes[code]
+	if !ok {
+		c = codes.FailedPrecondition
+	}
+	return status.Error(c, err.Error())
+}
@@ -0,0 +1,147 @@
+package riftserver
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	rift "github.com/obinexus/riftlang/bindings/go-riftlang"
+)
+
+// newTestClient serves a fresh Server over an in-memory listener
+func newTestClient(t *testing.T) *Client {
+	t.Helper()
+	lis := bufconn.Listen(1 << 20)
+	s := NewServer(Options{})
+	go s.Serve(lis)
+	t.Cleanup(s.Close)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return NewClient(conn)
+}
+
+// wantCode fails unless err is a status error with code want
+func wantCode(t *testing.T, err error, want codes.Code) {
+	t.Helper()
+	if got := status.Code(err); got != want {
+		t.Fatalf("code = %v, want %v (err %v)", got, want, err)
+	}
+}
+
+func TestTokenLifecycle(t *testing.T) {
+	c := newTestClient(t)
+	ctx := context.Background()
+
+	created, err := c.CreateToken(ctx, &CreateTokenRequest{
+		Type:     rift.TokenGoInt,
+		SpanType: rift.SpanFixed,
+		Bytes:    8,
+		Value:    &rift.RiftTokenValue{IntVal: 7},
+	})
+	if err != nil {
+		t.Fatalf("CreateToken: %v", err)
+	}
+	if created.Handle == 0 || created.Token.Value.IntVal != 7 {
+		t.Fatalf("CreateToken = handle %d value %d", created.Handle, created.Token.Value.IntVal)
+	}
+
+	if _, err := c.SetValue(ctx, created.Handle, rift.RiftTokenValue{IntVal: 42}); err != nil {
+		t.Fatalf("SetValue: %v", err)
+	}
+	got, err := c.GetToken(ctx, created.Handle)
+	if err != nil {
+		t.Fatalf("GetToken: %v", err)
+	}
+	if got.Handle != created.Handle || got.Token.Value.IntVal != 42 {
+		t.Fatalf("GetToken = handle %d value %d, want %d 42", got.Handle, got.Token.Value.IntVal, created.Handle)
+	}
+
+	valid, err := c.Validate(ctx, created.Handle)
+	if err != nil {
+		t.Fatalf("Validate: %v", err)
+	}
+	if !valid.Valid {
+		t.Fatalf("Validate = %s %s, want valid", valid.Code, valid.Detail)
+	}
+
+	if err := c.Release(ctx, created.Handle); err != nil {
+		t.Fatalf("Release: %v", err)
+	}
+	_, err = c.GetToken(ctx, created.Handle)
+	wantCode(t, err, codes.NotFound)
+	wantCode(t, c.Release(ctx, created.Handle), codes.NotFound)
+}
+
+func TestSuperposeCollapse(t *testing.T) {
+	c := newTestClient(t)
+	ctx := context.Background()
+
+	sup, err := c.Superpose(ctx, 1, 2.5, "three")
+	if err != nil {
+		t.Fatalf("Superpose: %v", err)
+	}
+	if n := len(sup.Token.SuperposedStates); n != 3 {
+		t.Fatalf("Superpose states = %d, want 3", n)
+	}
+
+	_, err = c.Collapse(ctx, sup.Handle, 3)
+	wantCode(t, err, codes.OutOfRange)
+
+	got, err := c.Collapse(ctx, sup.Handle, 2)
+	if err != nil {
+		t.Fatalf("Collapse: %v", err)
+	}
+	if got.Token.Type != rift.TokenGoString || got.Token.Value.StringVal != "three" {
+		t.Fatalf("Collapse = type %d %q, want GoString \"three\"", got.Token.Type, got.Token.Value.StringVal)
+	}
+
+	_, err = c.Collapse(ctx, sup.Handle, 0)
+	wantCode(t, err, codes.FailedPrecondition)
+
+	_, err = c.Superpose(ctx)
+	wantCode(t, err, codes.InvalidArgument)
+}
+
+func TestMatch(t *testing.T) {
+	c := newTestClient(t)
+	ctx := context.Background()
+
+	result, err := c.Match(ctx, "", "var x int")
+	if err != nil {
+		t.Fatalf("Match: %v", err)
+	}
+	if !result.Matched || result.Output != `riftVarint("x")` {
+		t.Fatalf("Match = %v %q, want riftVarint(\"x\")", result.Matched, result.Output)
+	}
+
+	_, err = c.Match(ctx, "no-such-engine", "var x int")
+	wantCode(t, err, codes.NotFound)
+}
+
+func TestUnknownHandle(t *testing.T) {
+	c := newTestClient(t)
+	ctx := context.Background()
+
+	_, err := c.GetToken(ctx, 99)
+	wantCode(t, err, codes.NotFound)
+	_, err = c.SetValue(ctx, 99, rift.RiftTokenValue{})
+	wantCode(t, err, codes.NotFound)
+	_, err = c.Validate(ctx, 99)
+	wantCode(t, err, codes.NotFound)
+	_, err = c.Collapse(ctx, 99, 0)
+	wantCode(t, err, codes.NotFound)
+}
@@ -0,0 +1,214 @@
+// go/target/riftserver/transport.go
+// Governance Service Transport (gRPC) - Go Implementation
+
+package riftserver
+
+import (
+	"context"
+	"encoding/json"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/encoding"
+
+	rift "github.com/obinexus/riftlang/bindings/go-riftlang"
+)
+
+// codecName is the gRPC content subtype used for service messages. Clients
+// in other languages send JSON bodies with content-type
+// application/grpc+riftjson.
+const codecName = "riftjson"
+
+// serviceName is the fully qualified gRPC service name
+const serviceName = "riftserver.Governance"
+
+// ============================================================================
+// Messages
+// ============================================================================
+
+// CreateTokenRequest allocates a token on a new memory span. Value, when
+// set, initializes the token.
+type CreateTokenRequest struct {
+	Type     int                  `json:"type"`
+	SpanType int                  `json:"spanType"`
+	Bytes    uint64               `json:"bytes"`
+	Value    *rift.RiftTokenValue `json:"value,omitempty"`
+}
+
+// HandleRequest names a token created on the server
+type HandleRequest struct {
+	Handle uint64 `json:"handle"`
+}
+
+// SetValueRequest writes a token's value
+type SetValueRequest struct {
+	Handle uint64              `json:"handle"`
+	Value  rift.RiftTokenValue `json:"value"`
+}
+
+// SuperposeRequest creates a superposed token over States. Whole numbers
+// become GoInt states, other numbers GoFloat and strings GoString.
+type SuperposeRequest struct {
+	States []interface{} `json:"states"`
+}
+
+// CollapseRequest collapses a superposed token to the state at Index
+type CollapseRequest struct {
+	Handle uint64 `json:"handle"`
+	Index  uint32 `json:"index"`
+}
+
+// MatchRequest matches Input against the engine registered under Engine,
+// or the server's engine when Engine is empty
+type MatchRequest struct {
+	Engine string `json:"engine,omitempty"`
+	Input  string `json:"input"`
+}
+
+// TokenReply returns a token handle and a copy of the token, sent in its
+// JSON codec form
+type TokenReply struct {
+	Handle uint64          `json:"handle"`
+	Token  *rift.RiftToken `json:"token"`
+}
+
+// ValidateReply reports a validation outcome. A token failing governance is
+// a normal reply, not an RPC error.
+type ValidateReply struct {
+	Valid  bool   `json:"valid"`
+	Code   string `json:"code,omitempty"`
+	Detail string `json:"detail,omitempty"`
+}
+
+// Empty is the reply of RPCs without a result
+type Empty struct{}
+
+// ============================================================================
+// Codec
+// ============================================================================
+
+// jsonCodec encodes service messages as JSON so the service needs no
+// generated protobuf code
+type jsonCodec struct{}
+
+func (jsonCodec) Marshal(v interface{}) ([]byte, error)      { return json.Marshal(v) }
+func (jsonCodec) Unmarshal(data []byte, v interface{}) error { return json.Unmarshal(data, v) }
+func (jsonCodec) Name() string                               { return codecName }
+
+func init() {
+	encoding.RegisterCodec(jsonCodec{})
+}
+
+// ============================================================================
+// Service
+// ============================================================================
+
+// governance is implemented by Server
+type governance interface {
+	CreateToken(ctx context.Context, req *CreateTokenRequest) (*TokenReply, error)
+	GetToken(ctx context.Context, req *HandleRequest) (*TokenReply, error)
+	SetValue(ctx context.Context, req *SetValueRequest) (*TokenReply, error)
+	Validate(ctx context.Context, req *HandleRequest) (*ValidateReply, error)
+	Superpose(ctx context.Context, req *SuperposeRequest) (*TokenReply, error)
+	Collapse(ctx context.Context, req *CollapseRequest) (*TokenReply, error)
+	Match(ctx context.Context, req *MatchRequest) (*rift.MatchResult, error)
+	Release(ctx context.Context, req *HandleRequest) (*Empty, error)
+}
+
+// unaryHandler builds the grpc.MethodDesc handler for one method
+func unaryHandler[Req any, Reply any](method string, call func(governance, context.Context, *Req) (*Reply, error)) grpc.MethodHandler {
+	return func(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+		req := new(Req)
+		if err := dec(req); err != nil {
+			return nil, err
+		}
+		if interceptor == nil {
+			return call(srv.(governance), ctx, req)
+		}
+		info := &grpc.UnaryServerInfo{Server: srv, FullMethod: "/" + serviceName + "/" + method}
+		handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+			return call(srv.(governance), ctx, req.(*Req))
+		}
+		return interceptor(ctx, req, info, handler)
+	}
+}
+
+var serviceDesc = grpc.ServiceDesc{
+	ServiceName: serviceName,
+	HandlerType: (*governance)(nil),
+	Methods: []grpc.MethodDesc{
+		{MethodName: "CreateToken", Handler: unaryHandler("CreateToken", governance.CreateToken)},
+		{MethodName: "GetToken", Handler: unaryHandler("GetToken", governance.GetToken)},
+		{MethodName: "SetValue", Handler: unaryHandler("SetValue", governance.SetValue)},
+		{MethodName: "Validate", Handler: unaryHandler("Validate", governance.Validate)},
+		{MethodName: "Superpose", Handler: unaryHandler("Superpose", governance.Superpose)},
+		{MethodName: "Collapse", Handler: unaryHandler("Collapse", governance.Collapse)},
+		{MethodName: "Match", Handler: unaryHandler("Match", governance.Match)},
+		{MethodName: "Release", Handler: unaryHandler("Release", governance.Release)},
+	},
+	Streams: []grpc.StreamDesc{},
+}
+
+// ============================================================================
+// Client
+// ============================================================================
+
+// Client calls a remote governance Server
+type Client struct {
+	conn *grpc.ClientConn
+}
+
+// NewClient returns a client using conn
+func NewClient(conn *grpc.ClientConn) *Client {
+	return &Client{conn: conn}
+}
+
+// invoke calls method with the service codec
+func invoke[Reply any](ctx context.Context, c *Client, method string, req interface{}) (*Reply, error) {
+	reply := new(Reply)
+	if err := c.conn.Invoke(ctx, "/"+serviceName+"/"+method, req, reply, grpc.CallContentSubtype(codecName)); err != nil {
+		return nil, err
+	}
+	return reply, nil
+}
+
+// CreateToken allocates a token on the server
+func (c *Client) CreateToken(ctx context.Context, req *CreateTokenRequest) (*TokenReply, error) {
+	return invoke[TokenReply](ctx, c, "CreateToken", req)
+}
+
+// GetToken fetches the current state of a token
+func (c *Client) GetToken(ctx context.Context, handle uint64) (*TokenReply, error) {
+	return invoke[TokenReply](ctx, c, "GetToken", &HandleRequest{Handle: handle})
+}
+
+// SetValue writes a token's value
+func (c *Client) SetValue(ctx context.Context, handle uint64, val rift.RiftTokenValue) (*TokenReply, error) {
+	return invoke[TokenReply](ctx, c, "SetValue", &SetValueRequest{Handle: handle, Value: val})
+}
+
+// Validate validates a token against the server's policy
+func (c *Client) Validate(ctx context.Context, handle uint64) (*ValidateReply, error) {
+	return invoke[ValidateReply](ctx, c, "Validate", &HandleRequest{Handle: handle})
+}
+
+// Superpose creates a superposed token over states
+func (c *Client) Superpose(ctx context.Context, states ...interface{}) (*TokenReply, error) {
+	return invoke[TokenReply](ctx, c, "Superpose", &SuperposeRequest{States: states})
+}
+
+// Collapse collapses a superposed token to the state at index
+func (c *Client) Collapse(ctx context.Context, handle uint64, index uint32) (*TokenReply, error) {
+	return invoke[TokenReply](ctx, c, "Collapse", &CollapseRequest{Handle: handle, Index: index})
+}
+
+// Match matches input against a server-side engine; "" selects the
+// server's engine
+func (c *Client) Match(ctx context.Context, engine, input string) (*rift.MatchResult, error) {
+	return invoke[rift.MatchResult](ctx, c, "Match", &MatchRequest{Engine: engine, Input: input})
+}
+
+// Release drops a token handle on the server
+func (c *Client) Release(ctx context.Context, handle uint64) error {
+	_, err := invoke[Empty](ctx, c, "Release", &HandleRequest{Handle: handle})
+	return err
+}
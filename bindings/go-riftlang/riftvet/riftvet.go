@@ -0,0 +1,364 @@
+// go/target/riftvet/riftvet.go
+// rift-vet Governance Analyzer - Go Implementation
+
+// Package riftvet is a go/analysis analyzer flagging Go code that bypasses
+// Rift governance:
+//
+//   - reading or writing RiftToken.Value directly instead of through
+//     GetValue and SetValue, which check the access mask and audit
+//   - Unlock or RUnlock of a local token the function never locked
+//   - Collapse or Measure of a token made by NewRiftToken that the
+//     function never superposes, which fails with E_NOT_SUPERPOSED
+//   - go statements handing tokens to goroutines not launched by rift.Go
+//
+// cmd/riftvet runs it under go vet:
+//
+//	go vet -vettool=$(which riftvet) ./...
+package riftvet
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+	"golang.org/x/tools/go/ast/inspector"
+)
+
+// riftPath is the import path of the rift package
+const riftPath = "github.com/obinexus/riftlang/bindings/go-riftlang"
+
+// Analyzer reports Rift governance violations
+var Analyzer = &analysis.Analyzer{
+	Name:     "riftvet",
+	Doc:      "report Go code that bypasses Rift token governance",
+	Requires: []*analysis.Analyzer{inspect.Analyzer},
+	Run:      run,
+}
+
+func run(pass *analysis.Pass) (interface{}, error) {
+	if pass.Pkg.Path() == riftPath || !importsRift(pass.Pkg) {
+		return nil, nil
+	}
+	ins := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
+	filter := []ast.Node{(*ast.SelectorExpr)(nil), (*ast.FuncDecl)(nil), (*ast.GoStmt)(nil)}
+	ins.Preorder(filter, func(n ast.Node) {
+		switch n := n.(type) {
+		case *ast.SelectorExpr:
+			checkValueField(pass, n)
+		case *ast.FuncDecl:
+			if n.Body != nil {
+				checkUnlocks(pass, n.Body)
+				checkCollapses(pass, n.Body)
+			}
+		case *ast.GoStmt:
+			checkGoStmt(pass, n)
+		}
+	})
+	return nil, nil
+}
+
+// importsRift reports whether pkg imports the rift package
+func importsRift(pkg *types.Package) bool {
+	for _, imp := range pkg.Imports() {
+		if imp.Path() == riftPath {
+			return true
+		}
+	}
+	return false
+}
+
+// isToken reports whether t is RiftToken or a pointer to it
+func isToken(t types.Type) bool {
+	if ptr, ok := t.Underlying().(*types.Pointer); ok {
+		t = ptr.Elem()
+	}
+	named, ok := t.(*types.Named)
+	if !ok {
+		return false
+	}
+	obj := named.Obj()
+	return obj.Pkg() != nil && obj.Pkg().Path() == riftPath && obj.Name() == "RiftToken"
+}
+
+// tokenMethod returns the name of the RiftToken method call calls and its
+// receiver expression, or "" if call is not one
+func tokenMethod(pass *analysis.Pass, call *ast.CallExpr) (string, ast.Expr) {
+	sel, ok := ast.Unparen(call.Fun).(*ast.SelectorExpr)
+	if !ok {
+		return "", nil
+	}
+	s := pass.TypesInfo.Selections[sel]
+	if s == nil || s.Kind() != types.MethodVal || !isToken(s.Recv()) {
+		return "", nil
+	}
+	return sel.Sel.Name, ast.Unparen(sel.X)
+}
+
+// localVar returns the variable at the root of x if it is declared inside
+// body, or nil
+func localVar(pass *analysis.Pass, x ast.Expr, body *ast.BlockStmt) *types.Var {
+	for {
+		switch e := x.(type) {
+		case *ast.SelectorExpr:
+			x = e.X
+			continue
+		case *ast.ParenExpr:
+			x = e.X
+			continue
+		case *ast.StarExpr:
+			x = e.X
+			continue
+		case *ast.Ident:
+			v, ok := pass.TypesInfo.Uses[e].(*types.Var)
+			if ok && v.Pos() >= body.Pos() && v.Pos() < body.End() {
+				return v
+			}
+		}
+		return nil
+	}
+}
+
+// ============================================================================
+// Value Field
+// ============================================================================
+
+// checkValueField flags a selection of RiftToken.Value
+func checkValueField(pass *analysis.Pass, sel *ast.SelectorExpr) {
+	s := pass.TypesInfo.Selections[sel]
+	if s == nil || s.Kind() != types.FieldVal || sel.Sel.Name != "Value" || !isToken(s.Recv()) {
+		return
+	}
+	pass.Reportf(sel.Sel.Pos(), "direct access to RiftToken.Value bypasses governance; use GetValue or SetValue")
+}
+
+// ============================================================================
+// Unlock Without Lock
+// ============================================================================
+
+// Lock methods by the unlock method they pair with
+var lockMethods = map[string][]string{
+	"Unlock":  {"Lock", "TryLock", "LockTimeout", "LockContext"},
+	"RUnlock": {"RLock"},
+}
+
+// checkUnlocks flags an Unlock or RUnlock of a local token with no lock of
+// the same token earlier in body. Tokens reached through parameters,
+// receivers or globals may be locked by the caller and are not checked.
+func checkUnlocks(pass *analysis.Pass, body *ast.BlockStmt) {
+	locked := make(map[string][]token.Pos) // by lock method and receiver
+	type unlock struct {
+		call   *ast.CallExpr
+		method string
+		recv   string
+	}
+	var unlocks []unlock
+	ast.Inspect(body, func(n ast.Node) bool {
+		call, ok := n.(*ast.CallExpr)
+		if !ok {
+			return true
+		}
+		method, recv := tokenMethod(pass, call)
+		if method == "" || localVar(pass, recv, body) == nil {
+			return true
+		}
+		key := types.ExprString(recv)
+		if _, ok := lockMethods[method]; ok {
+			unlocks = append(unlocks, unlock{call, method, key})
+		} else {
+			locked[method+" "+key] = append(locked[method+" "+key], call.Pos())
+		}
+		return true
+	})
+
+	for _, u := range unlocks {
+		found := false
+		for _, lock := range lockMethods[u.method] {
+			for _, pos := range locked[lock+" "+u.recv] {
+				found = found || pos < u.call.Pos()
+			}
+		}
+		if !found {
+			pass.Reportf(u.call.Pos(), "%s.%s without a preceding %s.%s in this function", u.recv, u.method, u.recv, lockMethods[u.method][0])
+		}
+	}
+}
+
+// ============================================================================
+// Collapse Without Superposition
+// ============================================================================
+
+// Methods that collapse a superposition, and that may put a token in one
+var (
+	collapseMethods  = map[string]bool{"Collapse": true, "CollapseErr": true, "Measure": true, "MeasureWith": true}
+	superposeMethods = map[string]bool{"Superpose": true, "SuperposeErr": true, "SetBit": true}
+)
+
+// checkCollapses flags collapsing a token that body makes with
+// NewRiftToken and never superposes. A token used in any other way than
+// calling its methods or reading its fields, such as being passed to a
+// function, may be superposed elsewhere and is not checked.
+func checkCollapses(pass *analysis.Pass, body *ast.BlockStmt) {
+	fresh := make(map[*types.Var]bool) // made by NewRiftToken; false once it may be superposed
+	ast.Inspect(body, func(n ast.Node) bool {
+		assign, ok := n.(*ast.AssignStmt)
+		if !ok || assign.Tok != token.DEFINE || len(assign.Lhs) != len(assign.Rhs) {
+			return true
+		}
+		for i, rhs := range assign.Rhs {
+			id, ok := assign.Lhs[i].(*ast.Ident)
+			if !ok || !isNewToken(pass, rhs) {
+				continue
+			}
+			if v, ok := pass.TypesInfo.Defs[id].(*types.Var); ok {
+				fresh[v] = true
+			}
+		}
+		return true
+	})
+	if len(fresh) == 0 {
+		return
+	}
+
+	type collapse struct {
+		call   *ast.CallExpr
+		name   string
+		method string
+	}
+	var collapses []collapse
+	var collapsed []*types.Var
+	var stack []ast.Node
+	ast.Inspect(body, func(n ast.Node) bool {
+		if n == nil {
+			stack = stack[:len(stack)-1]
+			return true
+		}
+		stack = append(stack, n)
+		id, ok := n.(*ast.Ident)
+		if !ok {
+			return true
+		}
+		v, ok := pass.TypesInfo.Uses[id].(*types.Var)
+		if !ok || !fresh[v] {
+			return true
+		}
+		var parent, grand ast.Node
+		if len(stack) >= 2 {
+			parent = stack[len(stack)-2]
+		}
+		if len(stack) >= 3 {
+			grand = stack[len(stack)-3]
+		}
+		sel, ok := parent.(*ast.SelectorExpr)
+		if !ok || sel.X != id {
+			fresh[v] = false
+			return true
+		}
+		if call, ok := grand.(*ast.CallExpr); ok && call.Fun == sel {
+			switch {
+			case superposeMethods[sel.Sel.Name]:
+				fresh[v] = false
+			case collapseMethods[sel.Sel.Name]:
+				collapses = append(collapses, collapse{call, id.Name, sel.Sel.Name})
+				collapsed = append(collapsed, v)
+			}
+			return true
+		}
+		if s := pass.TypesInfo.Selections[sel]; s == nil || s.Kind() != types.FieldVal || isWritten(stack[:len(stack)-1]) {
+			fresh[v] = false
+		}
+		return true
+	})
+
+	for i, c := range collapses {
+		if fresh[collapsed[i]] {
+			pass.Reportf(c.call.Pos(), "%s.%s on a token that is never superposed; it fails with E_NOT_SUPERPOSED", c.name, c.method)
+		}
+	}
+}
+
+// isNewToken reports whether x calls rift.NewRiftToken
+func isNewToken(pass *analysis.Pass, x ast.Expr) bool {
+	call, ok := ast.Unparen(x).(*ast.CallExpr)
+	return ok && isRiftFunc(pass, call, "NewRiftToken")
+}
+
+// isRiftFunc reports whether call calls the rift function name
+func isRiftFunc(pass *analysis.Pass, call *ast.CallExpr, name string) bool {
+	var id *ast.Ident
+	switch fun := ast.Unparen(call.Fun).(type) {
+	case *ast.Ident:
+		id = fun
+	case *ast.SelectorExpr:
+		id = fun.Sel
+	default:
+		return false
+	}
+	fn, ok := pass.TypesInfo.Uses[id].(*types.Func)
+	return ok && fn.Pkg() != nil && fn.Pkg().Path() == riftPath && fn.Name() == name
+}
+
+// isWritten reports whether the innermost node of stack, a field
+// selection, is assigned, incremented or has its address taken, directly or
+// through further selections and indexing
+func isWritten(stack []ast.Node) bool {
+	x := stack[len(stack)-1]
+	for i := len(stack) - 2; i >= 0; i-- {
+		switch p := stack[i].(type) {
+		case *ast.SelectorExpr:
+			if p.X != x {
+				return false
+			}
+		case *ast.IndexExpr:
+			if p.X != x {
+				return false
+			}
+		case *ast.ParenExpr:
+		case *ast.CallExpr:
+			// A method call on the field, such as ValidationBits.Store
+			return p.Fun == x
+		case *ast.AssignStmt:
+			for _, lhs := range p.Lhs {
+				if lhs == x {
+					return true
+				}
+			}
+			return false
+		case *ast.IncDecStmt:
+			return true
+		case *ast.UnaryExpr:
+			return p.Op == token.AND
+		default:
+			return false
+		}
+		x = stack[i]
+	}
+	return false
+}
+
+// ============================================================================
+// Goroutines
+// ============================================================================
+
+// checkGoStmt flags a go statement whose call uses a token, unless it
+// launches rift.Go itself
+func checkGoStmt(pass *analysis.Pass, stmt *ast.GoStmt) {
+	if isRiftFunc(pass, stmt.Call, "Go") {
+		return
+	}
+	var name string
+	ast.Inspect(stmt.Call, func(n ast.Node) bool {
+		id, ok := n.(*ast.Ident)
+		if !ok || name != "" {
+			return name == ""
+		}
+		if v, ok := pass.TypesInfo.Uses[id].(*types.Var); ok && isToken(v.Type()) {
+			name = id.Name
+		}
+		return true
+	})
+	if name != "" {
+		pass.Reportf(stmt.Go, "goroutine uses token %s; launch it with rift.Go so it runs governed", name)
+	}
+}
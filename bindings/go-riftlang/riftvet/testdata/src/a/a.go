@@ -0,0 +1,73 @@
+package a
+
+import rift "github.com/obinexus/riftlang/bindings/go-riftlang"
+
+func valueField(t *rift.RiftToken) int64 {
+	t.Value.IntVal = 1 // want `direct access to RiftToken.Value bypasses governance`
+	v, _ := t.GetValue()
+	return v.IntVal
+}
+
+func unlockWithoutLock() {
+	t := rift.NewRiftToken(rift.TokenGoInt, rift.NewRiftMemorySpan(rift.SpanFixed, 8))
+	t.Unlock()  // want `t.Unlock without a preceding t.Lock in this function`
+	t.RUnlock() // want `t.RUnlock without a preceding t.RLock in this function`
+}
+
+func lockedBefore() {
+	t := rift.NewRiftToken(rift.TokenGoInt, rift.NewRiftMemorySpan(rift.SpanFixed, 8))
+	t.Lock()
+	defer t.Unlock()
+	if t.TryLock() {
+		t.Unlock()
+	}
+}
+
+func unlockBeforeLock() {
+	t := rift.NewRiftToken(rift.TokenGoInt, rift.NewRiftMemorySpan(rift.SpanFixed, 8))
+	t.RUnlock() // want `t.RUnlock without a preceding t.RLock`
+	t.RLock()
+}
+
+// Tokens from parameters may be locked by the caller
+func unlockParam(t *rift.RiftToken) {
+	t.Unlock()
+}
+
+func collapseFresh() {
+	t := rift.NewRiftToken(rift.TokenGoInt, rift.NewRiftMemorySpan(rift.SpanFixed, 8))
+	_ = t.Type
+	t.Collapse(0)        // want `t.Collapse on a token that is never superposed`
+	_ = t.CollapseErr(0) // want `t.CollapseErr on a token that is never superposed`
+}
+
+func collapseSuperposed() {
+	t := rift.NewRiftToken(rift.TokenGoInt, rift.NewRiftMemorySpan(rift.SpanFixed, 8))
+	t.Superpose(nil, nil)
+	t.Collapse(0)
+}
+
+func collapseEscaped() {
+	t := rift.NewRiftToken(rift.TokenGoInt, rift.NewRiftMemorySpan(rift.SpanFixed, 8))
+	superpose(t)
+	t.Collapse(0)
+}
+
+func collapseFieldWritten() {
+	t := rift.NewRiftToken(rift.TokenGoInt, rift.NewRiftMemorySpan(rift.SpanFixed, 8))
+	t.EntanglementID = 1
+	t.Collapse(0)
+}
+
+func superpose(t *rift.RiftToken) {
+	t.Superpose(nil, nil)
+}
+
+func goroutines(t *rift.RiftToken) {
+	go superpose(t) // want `goroutine uses token t; launch it with rift.Go so it runs governed`
+	go func() {     // want `goroutine uses token t`
+		t.SetValue(rift.RiftTokenValue{})
+	}()
+	go rift.Go(func() { superpose(t) })
+	rift.Go(func() { superpose(t) })
+}
@@ -0,0 +1,35 @@
+// Package rift is a stub of the rift API used by the riftvet fixtures
+package rift
+
+const TokenGoInt = 0
+
+const SpanFixed = 0
+
+type RiftMemorySpan struct{}
+
+func NewRiftMemorySpan(spanType int, bytes uint64) *RiftMemorySpan { return &RiftMemorySpan{} }
+
+type RiftTokenValue struct {
+	IntVal int64
+}
+
+type RiftToken struct {
+	Type           int
+	Value          RiftTokenValue
+	EntanglementID uint32
+}
+
+func NewRiftToken(tokenType int, memory *RiftMemorySpan) *RiftToken { return &RiftToken{} }
+
+func (t *RiftToken) Lock()                                      {}
+func (t *RiftToken) Unlock()                                    {}
+func (t *RiftToken) TryLock() bool                              { return true }
+func (t *RiftToken) RLock()                                     {}
+func (t *RiftToken) RUnlock()                                   {}
+func (t *RiftToken) GetValue() (RiftTokenValue, error)          { return RiftTokenValue{}, nil }
+func (t *RiftToken) SetValue(val RiftTokenValue) error          { return nil }
+func (t *RiftToken) Superpose(states []*RiftToken, a []float64) {}
+func (t *RiftToken) Collapse(index uint32)                      {}
+func (t *RiftToken) CollapseErr(index uint32) error             { return nil }
+
+func Go(fn func()) {}
@@ -0,0 +1,238 @@
+//go:build js && wasm
+
+// go/target/riftwasm/main.go
+// WebAssembly Entry Point (syscall/js) - Go Implementation
+//
+// Build with:
+//
+//	GOOS=js GOARCH=wasm go build -o rift.wasm ./riftwasm
+//
+// and load alongside $(go env GOROOT)/lib/wasm/wasm_exec.js. The binding is
+// installed as globalThis.rift. Go values cannot cross into JavaScript, so
+// tokens and engines are referred to by integer handles that must be passed
+// to rift.release when no longer needed.
+package main
+
+import (
+	"encoding/json"
+	"sync"
+	"syscall/js"
+
+	rift "github.com/obinexus/riftlang/bindings/go-riftlang"
+)
+
+// ============================================================================
+// Handles
+// ============================================================================
+
+var (
+	handleLock sync.Mutex
+	nextHandle int
+	tokens     = make(map[int]*rift.RiftToken)
+	engines    = make(map[int]*rift.PatternEngine)
+)
+
+// handleError is panicked by the handle lookups and turned into a returned
+// Error by guard, since a Go panic would otherwise stop the wasm runtime
+type handleError string
+
+// guard wraps fn so bad handles return an Error instead of exiting
+func guard(fn func(js.Value, []js.Value) interface{}) func(js.Value, []js.Value) interface{} {
+	return func(this js.Value, args []js.Value) (result interface{}) {
+		defer func() {
+			if r := recover(); r != nil {
+				msg, ok := r.(handleError)
+				if !ok {
+					panic(r)
+				}
+				result = js.Global().Get("Error").New(string(msg))
+			}
+		}()
+		return fn(this, args)
+	}
+}
+
+func putToken(t *rift.RiftToken) int {
+	handleLock.Lock()
+	defer handleLock.Unlock()
+	nextHandle++
+	tokens[nextHandle] = t
+	return nextHandle
+}
+
+func getToken(v js.Value) *rift.RiftToken {
+	handleLock.Lock()
+	defer handleLock.Unlock()
+	t, ok := tokens[v.Int()]
+	if !ok {
+		panic(handleError("rift: unknown token handle"))
+	}
+	return t
+}
+
+func putEngine(e *rift.PatternEngine) int {
+	handleLock.Lock()
+	defer handleLock.Unlock()
+	nextHandle++
+	engines[nextHandle] = e
+	return nextHandle
+}
+
+func getEngine(v js.Value) *rift.PatternEngine {
+	handleLock.Lock()
+	defer handleLock.Unlock()
+	e, ok := engines[v.Int()]
+	if !ok {
+		panic(handleError("rift: unknown engine handle"))
+	}
+	return e
+}
+
+// ============================================================================
+// Conversion
+// ============================================================================
+
+// fromJS converts a JavaScript primitive into a token state value
+func fromJS(v js.Value) interface{} {
+	switch v.Type() {
+	case js.TypeNumber:
+		f := v.Float()
+		if f == float64(int64(f)) {
+			return int64(f)
+		}
+		return f
+	case js.TypeString:
+		return v.String()
+	case js.TypeBoolean:
+		return v.Bool()
+	}
+	return js.Global().Get("JSON").Call("stringify", v).String()
+}
+
+// toJS converts a Go value into a JavaScript value through JSON
+func toJS(v interface{}) js.Value {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return js.Null()
+	}
+	return js.Global().Get("JSON").Call("parse", string(data))
+}
+
+// errorJS converts an error into a JavaScript Error, or null
+func errorJS(err error) js.Value {
+	if err == nil {
+		return js.Null()
+	}
+	return js.Global().Get("Error").New(err.Error())
+}
+
+// ============================================================================
+// Exported Functions
+// ============================================================================
+
+// superpose(...states) -> token handle
+func superpose(_ js.Value, args []js.Value) interface{} {
+	states := make([]interface{}, len(args))
+	for i, a := range args {
+		states[i] = fromJS(a)
+	}
+	return putToken(rift.Superpose(states...))
+}
+
+// entangle(a, b) -> entanglement id
+func entangle(_ js.Value, args []js.Value) interface{} {
+	return rift.Entangle(getToken(args[0]), getToken(args[1]))
+}
+
+// collapse(token, index = 0) -> Error | null
+func collapse(_ js.Value, args []js.Value) interface{} {
+	index := 0
+	if len(args) > 1 {
+		index = args[1].Int()
+	}
+	return errorJS(getToken(args[0]).CollapseErr(uint32(index)))
+}
+
+// measure(token) -> collapsed state as a token object
+func measure(_ js.Value, args []js.Value) interface{} {
+	state, err := getToken(args[0]).Measure()
+	if err != nil {
+		return errorJS(err)
+	}
+	return toJS(state)
+}
+
+// token(handle) -> token object (the JSON codec form)
+func token(_ js.Value, args []js.Value) interface{} {
+	return toJS(getToken(args[0]))
+}
+
+// entropy(token) -> Shannon entropy of the amplitudes
+func entropy(_ js.Value, args []js.Value) interface{} {
+	return rift.CalculateEntropy(getToken(args[0]))
+}
+
+// createEngine(mode = "classical") -> engine handle
+func createEngine(_ js.Value, args []js.Value) interface{} {
+	mode := ""
+	if len(args) > 0 {
+		mode = args[0].String()
+	}
+	return putEngine(rift.NewPatternEngine(mode))
+}
+
+// createDefaultEngine() -> engine handle
+func createDefaultEngine(_ js.Value, _ []js.Value) interface{} {
+	return putEngine(rift.CreateDefaultEngine())
+}
+
+// addPair(engine, left, right, priority, rightIsLiteral) -> Error | null
+func addPair(_ js.Value, args []js.Value) interface{} {
+	literal := len(args) > 4 && args[4].Truthy()
+	return errorJS(getEngine(args[0]).AddPairErr(args[1].String(), args[2].String(), uint32(args[3].Int()), literal))
+}
+
+// match(engine, input) -> {Matched, Output, Priority, TransformID, Groups}
+func match(_ js.Value, args []js.Value) interface{} {
+	return toJS(getEngine(args[0]).Match(args[1].String()))
+}
+
+// metrics(engine) -> engine metrics object
+func metrics(_ js.Value, args []js.Value) interface{} {
+	return toJS(getEngine(args[0]).GetMetrics())
+}
+
+// release(handle) frees a token or engine handle
+func release(_ js.Value, args []js.Value) interface{} {
+	handleLock.Lock()
+	defer handleLock.Unlock()
+	delete(tokens, args[0].Int())
+	delete(engines, args[0].Int())
+	return nil
+}
+
+func main() {
+	api := map[string]func(js.Value, []js.Value) interface{}{
+		"superpose":           superpose,
+		"entangle":            entangle,
+		"collapse":            collapse,
+		"measure":             measure,
+		"token":               token,
+		"entropy":             entropy,
+		"createEngine":        createEngine,
+		"createDefaultEngine": createDefaultEngine,
+		"addPair":             addPair,
+		"match":               match,
+		"metrics":             metrics,
+		"release":             release,
+	}
+
+	obj := js.Global().Get("Object").New()
+	for name, fn := range api {
+		obj.Set(name, js.FuncOf(guard(fn)))
+	}
+	js.Global().Set("rift", obj)
+
+	// Keep the runtime alive so the exported functions stay callable
+	select {}
+}
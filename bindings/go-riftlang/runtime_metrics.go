@@ -0,0 +1,268 @@
+// go/target/runtime_metrics.go
+// Named Governance Metrics - Go Implementation
+
+package rift
+
+import (
+	"math"
+	"sync/atomic"
+	"time"
+)
+
+// Metric names, in the style of runtime/metrics
+const (
+	MetricTokensCreated    = "rift/tokens/created"
+	MetricTokensReleased   = "rift/tokens/released"
+	MetricTokensLive       = "rift/tokens/live"
+	MetricLockContention   = "rift/locks/contention"
+	MetricPatternMatches   = "rift/patterns/matches"
+	MetricPatternFailures  = "rift/patterns/failures"
+	MetricMatchLatency     = "rift/patterns/match-latency"
+	MetricInternHits       = "rift/strings/intern-hits"
+	MetricInternSavedBytes = "rift/strings/intern-saved-bytes"
+	MetricAuditDropped     = "rift/audit/dropped"
+)
+
+// MetricKind is the type of a metric's value
+type MetricKind int
+
+const (
+	// MetricKindBad marks a sample whose name is not a known metric
+	MetricKindBad MetricKind = iota
+	MetricKindUint64
+	MetricKindFloat64Histogram
+)
+
+// MetricDescription describes a metric ReadMetrics can read
+type MetricDescription struct {
+	Name        string
+	Description string
+	Kind        MetricKind
+	Cumulative  bool // the value only grows over the life of the process
+}
+
+// Float64Histogram is a histogram of float64 values. Counts[i] counts the
+// values in [Buckets[i], Buckets[i+1]); the outermost boundaries may be
+// infinite. Buckets may be shared between histograms and must not be
+// modified.
+type Float64Histogram struct {
+	Counts  []uint64
+	Buckets []float64
+}
+
+// MetricValue is the value of a metric sample
+type MetricValue struct {
+	kind   MetricKind
+	scalar uint64
+	hist   *Float64Histogram
+}
+
+// Kind returns the value's kind, MetricKindBad for an unknown metric
+func (v MetricValue) Kind() MetricKind {
+	return v.kind
+}
+
+// Uint64 returns the value of a MetricKindUint64 metric. It panics for
+// other kinds.
+func (v MetricValue) Uint64() uint64 {
+	if v.kind != MetricKindUint64 {
+		panic("rift: called Uint64 on a metric value of kind " + v.kind.name())
+	}
+	return v.scalar
+}
+
+// Float64Histogram returns the value of a MetricKindFloat64Histogram
+// metric. It panics for other kinds.
+func (v MetricValue) Float64Histogram() *Float64Histogram {
+	if v.kind != MetricKindFloat64Histogram {
+		panic("rift: called Float64Histogram on a metric value of kind " + v.kind.name())
+	}
+	return v.hist
+}
+
+// name names the kind for panics
+func (k MetricKind) name() string {
+	switch k {
+	case MetricKindUint64:
+		return "Uint64"
+	case MetricKindFloat64Histogram:
+		return "Float64Histogram"
+	}
+	return "Bad"
+}
+
+// MetricSample names a metric for ReadMetrics, which fills in its Value
+type MetricSample struct {
+	Name  string
+	Value MetricValue
+}
+
+// ============================================================================
+// Catalogue
+// ============================================================================
+
+// metricCatalogue lists every metric with how to read it
+var metricCatalogue = []struct {
+	MetricDescription
+	read func() MetricValue
+}{
+	{
+		MetricDescription{Name: MetricTokensCreated, Kind: MetricKindUint64, Cumulative: true,
+			Description: "Tokens created by NewRiftToken, and so Var, Superpose and the like, or by a TokenArena."},
+		func() MetricValue { return uint64Value(tokensCreated.Load()) },
+	},
+	{
+		MetricDescription{Name: MetricTokensReleased, Kind: MetricKindUint64, Cumulative: true,
+			Description: "Tokens released with Release."},
+		func() MetricValue { return uint64Value(tokensReleased.Load()) },
+	},
+	{
+		MetricDescription{Name: MetricTokensLive, Kind: MetricKindUint64,
+			Description: "Created tokens not yet released or reset with their TokenArena. Tokens dropped without Release still count."},
+		func() MetricValue { return uint64Value(uint64(max(tokensLive.Load(), 0))) },
+	},
+	{
+		MetricDescription{Name: MetricLockContention, Kind: MetricKindUint64, Cumulative: true,
+			Description: "Token lock and read lock acquisitions that had to wait."},
+		func() MetricValue { return uint64Value(lockContention.Load()) },
+	},
+	{
+		MetricDescription{Name: MetricPatternMatches, Kind: MetricKindUint64, Cumulative: true,
+			Description: "Inputs that matched a pattern pair, across all engines."},
+		func() MetricValue { return uint64Value(patternMatches.Load()) },
+	},
+	{
+		MetricDescription{Name: MetricPatternFailures, Kind: MetricKindUint64, Cumulative: true,
+			Description: "Inputs that matched no pattern pair, across all engines."},
+		func() MetricValue { return uint64Value(patternFailures.Load()) },
+	},
+	{
+		MetricDescription{Name: MetricMatchLatency, Kind: MetricKindFloat64Histogram, Cumulative: true,
+			Description: "Distribution of match latencies across all engines, in seconds."},
+		readMatchLatency,
+	},
+	{
+		MetricDescription{Name: MetricInternHits, Kind: MetricKindUint64, Cumulative: true,
+			Description: "Strings a TokenArena intern pool answered with a copy it already held."},
+		func() MetricValue { return uint64Value(internHits.Load()) },
+	},
+	{
+		MetricDescription{Name: MetricInternSavedBytes, Kind: MetricKindUint64, Cumulative: true,
+			Description: "Bytes of string data TokenArena intern pools saved from being duplicated."},
+		func() MetricValue { return uint64Value(internSavedBytes.Load()) },
+	},
+	{
+		MetricDescription{Name: MetricAuditDropped, Kind: MetricKindUint64, Cumulative: true,
+			Description: "Audit events AsyncAuditSink pipelines discarded under AuditDrop or after Close."},
+		func() MetricValue { return uint64Value(auditDropped.Load()) },
+	},
+}
+
+// AllMetrics describes every metric ReadMetrics can read
+func AllMetrics() []MetricDescription {
+	out := make([]MetricDescription, len(metricCatalogue))
+	for i, m := range metricCatalogue {
+		out[i] = m.MetricDescription
+	}
+	return out
+}
+
+// ReadMetrics fills in the Value of each sample, as runtime/metrics.Read
+// does; samples naming an unknown metric get a MetricKindBad value. The
+// metrics are process-wide and cost a few atomic operations to keep, so
+// they are always on.
+func ReadMetrics(samples []MetricSample) {
+	for i := range samples {
+		samples[i].Value = MetricValue{}
+		for _, m := range metricCatalogue {
+			if m.Name == samples[i].Name {
+				samples[i].Value = m.read()
+				break
+			}
+		}
+	}
+}
+
+// uint64Value wraps a counter value
+func uint64Value(v uint64) MetricValue {
+	return MetricValue{kind: MetricKindUint64, scalar: v}
+}
+
+// ============================================================================
+// Counters
+// ============================================================================
+
+var (
+	tokensCreated   atomic.Uint64
+	tokensReleased  atomic.Uint64
+	tokensLive      atomic.Int64
+	lockContention  atomic.Uint64
+	patternMatches  atomic.Uint64
+	patternFailures atomic.Uint64
+)
+
+// countCreated counts a new token as created and live
+func (t *RiftToken) countCreated() {
+	tokensCreated.Add(1)
+	tokensLive.Add(1)
+	t.countedLive = true
+}
+
+// countReleased counts a released token, and drops it from the live count
+// if it was counted there
+func (t *RiftToken) countReleased() {
+	tokensReleased.Add(1)
+	t.dropLive()
+}
+
+// dropLive removes a counted token from the live count
+func (t *RiftToken) dropLive() {
+	if t.countedLive {
+		t.countedLive = false
+		tokensLive.Add(-1)
+	}
+}
+
+// ============================================================================
+// Match Latency
+// ============================================================================
+
+// matchLatencyBuckets bounds the match latency histogram: 0, 1µs growing
+// fourfold up to about 0.26s, then +Inf
+var matchLatencyBuckets = func() []float64 {
+	b := []float64{0}
+	for d := 1e-6; d < 1; d *= 4 {
+		b = append(b, d)
+	}
+	return append(b, math.Inf(1))
+}()
+
+// matchLatencyCounts counts latencies per bucket of matchLatencyBuckets
+var matchLatencyCounts = make([]atomic.Uint64, len(matchLatencyBuckets)-1)
+
+// recordMatch counts a match or failure and its latency
+func recordMatch(elapsed time.Duration, matched bool) {
+	if matched {
+		patternMatches.Add(1)
+	} else {
+		patternFailures.Add(1)
+	}
+	s := elapsed.Seconds()
+	i := 0
+	for i < len(matchLatencyCounts)-1 && s >= matchLatencyBuckets[i+1] {
+		i++
+	}
+	matchLatencyCounts[i].Add(1)
+}
+
+// readMatchLatency snapshots the match latency histogram
+func readMatchLatency() MetricValue {
+	h := &Float64Histogram{
+		Counts:  make([]uint64, len(matchLatencyCounts)),
+		Buckets: matchLatencyBuckets,
+	}
+	for i := range matchLatencyCounts {
+		h.Counts[i] = matchLatencyCounts[i].Load()
+	}
+	return MetricValue{kind: MetricKindFloat64Histogram, hist: h}
+}
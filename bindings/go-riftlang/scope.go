@@ -0,0 +1,121 @@
+// go/target/scope.go
+// Goroutine Governance Scopes - Go Implementation
+
+package rift
+
+import (
+	"context"
+	"io"
+	"sync"
+	"sync/atomic"
+)
+
+// ============================================================================
+// Scope
+// ============================================================================
+
+// Scope is the ambient governance of a goroutine: the token governing it,
+// the token of the goroutine that launched it, the policy its code should
+// validate against and the sink for its audit events. Goroutines launched
+// with Go inherit the launching goroutine's policy and audit sink, and its
+// token becomes their Parent.
+type Scope struct {
+	Token  *RiftToken
+	Parent *RiftToken
+	Policy *Policy
+	Audit  io.Writer // audit events raised on the goroutine; nil = the global sink
+}
+
+// scopes holds the scope of each goroutine that has one
+var scopes struct {
+	lock   sync.RWMutex
+	byG    map[uint64]*Scope
+	active atomic.Int32 // len(byG), read without the lock
+	audits atomic.Int32 // scopes with an Audit sink
+}
+
+// Current returns a copy of the calling goroutine's scope, or nil if it
+// has none. Goroutines launched with Go always have one.
+func Current() *Scope {
+	if scopes.active.Load() == 0 {
+		return nil
+	}
+	s := scopeOf(goroutineID())
+	if s == nil {
+		return nil
+	}
+	cp := *s
+	return &cp
+}
+
+// WithScope runs fn with s as the calling goroutine's scope, restoring the
+// previous scope, if any, when fn returns
+func WithScope(s Scope, fn func()) {
+	gid := goroutineID()
+	prev := setScope(gid, &s)
+	defer setScope(gid, prev)
+	fn()
+}
+
+// scopeOf returns the scope of goroutine gid, or nil
+func scopeOf(gid uint64) *Scope {
+	scopes.lock.RLock()
+	defer scopes.lock.RUnlock()
+	return scopes.byG[gid]
+}
+
+// setScope sets or, for nil, clears the scope of goroutine gid, returning
+// the scope it replaces
+func setScope(gid uint64, s *Scope) *Scope {
+	scopes.lock.Lock()
+	defer scopes.lock.Unlock()
+	prev := scopes.byG[gid]
+	if prev != nil && prev.Audit != nil {
+		scopes.audits.Add(-1)
+	}
+	if s == nil {
+		delete(scopes.byG, gid)
+	} else {
+		if scopes.byG == nil {
+			scopes.byG = make(map[uint64]*Scope)
+		}
+		scopes.byG[gid] = s
+		if s.Audit != nil {
+			scopes.audits.Add(1)
+		}
+	}
+	scopes.active.Store(int32(len(scopes.byG)))
+	return prev
+}
+
+// scopeAudit returns the audit sink of the calling goroutine's scope, or
+// nil
+func scopeAudit() io.Writer {
+	if scopes.audits.Load() == 0 {
+		return nil
+	}
+	if s := scopeOf(goroutineID()); s != nil {
+		return s.Audit
+	}
+	return nil
+}
+
+// ============================================================================
+// Context Propagation
+// ============================================================================
+
+// scopeContextKey keys the scope carried by a context
+type scopeContextKey struct{}
+
+// ContextWithScope returns a copy of ctx carrying s, for handing a scope
+// to goroutines not launched with Go; the receiver installs it with
+// WithScope
+func ContextWithScope(ctx context.Context, s Scope) context.Context {
+	return context.WithValue(ctx, scopeContextKey{}, s)
+}
+
+// ScopeFromContext returns the scope carried by ctx, if any
+func ScopeFromContext(ctx context.Context) (Scope, bool) {
+	s, ok := ctx.Value(scopeContextKey{}).(Scope)
+	return s, ok
+}
@@ -0,0 +1,147 @@
+// go/target/shadow.go
+// Shadow Tokens (Copy-on-Write Snapshots) - Go Implementation
+
+package rift
+
+// ============================================================================
+// Shadow Tokens
+// ============================================================================
+
+// Shadow creates a copy-on-write shadow of the token. The shadow shares the
+// origin's memory span, which it must treat as read-only, and reads through
+// to the origin's value until its first SetValue or Delete. Speculative
+// updates stay on the shadow until Commit writes them back or Discard drops
+// them. The origin's value is read under its value lock, so Shadow may
+// race with writers.
+func (t *RiftToken) Shadow() *RiftToken {
+	if t == nil {
+		return nil
+	}
+	t.valueLock.Lock()
+	value := t.Value
+	t.valueLock.Unlock()
+	shadow := &RiftToken{
+		Type:         t.Type,
+		Value:        value,
+		Memory:       t.Memory,
+		shadowOf:     t,
+		SourceLine:   t.SourceLine,
+		SourceColumn: t.SourceColumn,
+		SourceFile:   t.SourceFile,
+	}
+	shadow.ValidationBits.Store(t.ValidationBits.Load()&^(TokenLocked|TokenGoverned|TokenPersistent) | TokenShadow)
+	return shadow
+}
+
+// IsShadow checks if the token is a live shadow
+func (t *RiftToken) IsShadow() bool {
+	return t.HasBit(TokenShadow) && t.shadowOf != nil
+}
+
+// ShadowOf returns the origin of a live shadow, or nil
+func (t *RiftToken) ShadowOf() *RiftToken {
+	if !t.IsShadow() {
+		return nil
+	}
+	return t.shadowOf
+}
+
+// Commit writes the shadow's speculative value back to its origin under the
+// origin's lock and retires the shadow. A modified shadow must pass
+// validation; a deleted shadow deletes the origin's value. Commit fails
+// with CodeDeadlock, leaving the shadow live, if deadlock detection refuses
+// the origin's lock.
+func (t *RiftToken) Commit() error {
+	if !t.IsShadow() {
+		return govErr(CodeNotShadow, "commit", "token is not a live shadow")
+	}
+	origin := t.shadowOf
+
+	if t.isDirty() {
+		if t.HasBit(TokenInitialized) {
+			if err := t.ValidateErr(); err != nil {
+				return err
+			}
+		}
+
+		if !origin.Lock() {
+			return origin.located(govErr(CodeDeadlock, "commit", "lock would complete a wait cycle"))
+		}
+		var err error
+		if t.HasBit(TokenInitialized) {
+			err = origin.SetValue(t.Value)
+		} else {
+			err = origin.Delete()
+		}
+		origin.Unlock()
+		if err != nil {
+			return err
+		}
+	}
+
+	auditEmit(AuditShadowCommit, t, "")
+	t.retireShadow()
+	return nil
+}
+
+// Discard drops the shadow's speculative changes and retires the shadow,
+// leaving the origin untouched
+func (t *RiftToken) Discard() error {
+	if !t.IsShadow() {
+		return govErr(CodeNotShadow, "discard", "token is not a live shadow")
+	}
+	auditEmit(AuditShadowDiscard, t, "")
+	t.retireShadow()
+	return nil
+}
+
+// retireShadow detaches a shadow from its origin. A retired shadow is no
+// longer allocated and fails validation.
+func (t *RiftToken) retireShadow() {
+	t.valueLock.Lock()
+	t.shadowOf = nil
+	t.shadowDirty = false
+	t.Value = RiftTokenValue{}
+	t.valueLock.Unlock()
+	t.ClearBit(TokenShadow | TokenAllocated | TokenInitialized | TokenGoverned)
+	t.reseal()
+}
+
+// readSource returns the token whose value a read should observe: the
+// origin for an unmodified shadow, otherwise the token itself
+func (t *RiftToken) readSource() *RiftToken {
+	t.valueLock.Lock()
+	origin := t.cleanOrigin()
+	t.valueLock.Unlock()
+	if origin != nil {
+		return origin
+	}
+	return t
+}
+
+// isDirty reports whether a shadow has been written or deleted since it
+// was taken
+func (t *RiftToken) isDirty() bool {
+	t.valueLock.Lock()
+	defer t.valueLock.Unlock()
+	return t.shadowDirty
+}
+
+// cleanOrigin returns the origin of an unmodified shadow, or nil; valueLock
+// held
+func (t *RiftToken) cleanOrigin() *RiftToken {
+	if t.shadowDirty {
+		return nil
+	}
+	return t.shadowOf
+}
+
+// previousValue returns the value reads observed before a write: the
+// origin's, copied under its own lock, for an unmodified shadow; valueLock
+// held
+func (t *RiftToken) previousValue() RiftTokenValue {
+	if origin := t.cleanOrigin(); origin != nil {
+		return origin.snapshotValue()
+	}
+	return t.Value
+}
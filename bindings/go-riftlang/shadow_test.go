@@ -0,0 +1,123 @@
+package rift
+
+import (
+	"sync"
+	"testing"
+)
+
+// TestShadowCommitRefusedLock commits while the calling goroutine holds the
+// origin's lock, which deadlock detection refuses
+func TestShadowCommitRefusedLock(t *testing.T) {
+	SetDeadlockDetection(DeadlockError)
+	t.Cleanup(func() { SetDeadlockDetection(DeadlockOff) })
+
+	origin := NewRiftToken(TokenGoInt, NewRiftMemorySpan(SpanFixed, 64))
+	if err := origin.SetValue(RiftTokenValue{IntVal: 1}); err != nil {
+		t.Fatal(err)
+	}
+	shadow := origin.Shadow()
+	if err := shadow.SetValue(RiftTokenValue{IntVal: 2}); err != nil {
+		t.Fatal(err)
+	}
+
+	origin.Lock()
+	err := shadow.Commit()
+	origin.Unlock()
+	if code := ErrorCodeOf(err); code != CodeDeadlock {
+		t.Fatalf("Commit error %v, want %v", err, CodeDeadlock)
+	}
+	if !shadow.IsShadow() {
+		t.Fatal("shadow retired by a refused Commit")
+	}
+	if got, _ := origin.GetValue(); got.IntVal != 1 {
+		t.Fatalf("origin = %d after refused Commit, want 1", got.IntVal)
+	}
+
+	if err := shadow.Commit(); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := origin.GetValue(); got.IntVal != 2 {
+		t.Fatalf("origin = %d after Commit, want 2", got.IntVal)
+	}
+}
+
+// TestShadowConcurrentWrites shadows a token while another goroutine writes
+// it; run with -race
+func TestShadowConcurrentWrites(t *testing.T) {
+	origin := NewRiftToken(TokenGoString, NewRiftMemorySpan(SpanFixed, 64))
+	if err := origin.SetValue(RiftTokenValue{StringVal: "a"}); err != nil {
+		t.Fatal(err)
+	}
+
+	var wg sync.WaitGroup
+	wg.Go(func() {
+		for i := range 200 {
+			origin.SetValue(RiftTokenValue{StringVal: string(rune('a' + i%26))})
+		}
+	})
+	for range 200 {
+		shadow := origin.Shadow()
+		if err := shadow.Discard(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	wg.Wait()
+}
+
+// TestShadowConcurrentReadWrite reads and writes live shadows while another
+// goroutine writes their origin: the first write to a clean shadow reads the
+// origin, so this must not race (run with -race), and no write may reach
+// the origin before Commit
+func TestShadowConcurrentReadWrite(t *testing.T) {
+	origin := NewRiftToken(TokenGoInt, NewRiftMemorySpan(SpanFixed, 64))
+	if err := origin.SetValue(RiftTokenValue{IntVal: 0}); err != nil {
+		t.Fatal(err)
+	}
+
+	const n = 200
+	var wg sync.WaitGroup
+	wg.Go(func() {
+		for i := range n {
+			if err := origin.SetValue(RiftTokenValue{IntVal: int64(i)}); err != nil {
+				t.Error(err)
+				return
+			}
+		}
+	})
+	for g := range 4 {
+		wg.Go(func() {
+			for i := range n {
+				shadow := origin.Shadow()
+				var inner sync.WaitGroup
+				inner.Go(func() {
+					for range 4 {
+						shadow.GetValue()
+					}
+				})
+				var err error
+				if i%2 == 0 {
+					err = shadow.SetValue(RiftTokenValue{IntVal: -1 - int64(g)})
+				} else {
+					err = shadow.Delete()
+				}
+				inner.Wait()
+				if err != nil {
+					t.Error(err)
+					return
+				}
+				if !shadow.isDirty() {
+					t.Error("written shadow not dirty")
+				}
+				if err := shadow.Discard(); err != nil {
+					t.Error(err)
+					return
+				}
+			}
+		})
+	}
+	wg.Wait()
+
+	if got, err := origin.GetValue(); err != nil || got.IntVal != n-1 {
+		t.Fatalf("origin = %d, %v; want %d", got.IntVal, err, n-1)
+	}
+}
@@ -0,0 +1,424 @@
+// go/target/shared_span.go
+// Cross-Process Shared Spans - Go Implementation
+
+package rift
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"hash/fnv"
+	"math"
+	"os"
+	"strings"
+	"sync"
+)
+
+// ============================================================================
+// Binary Layout
+// ============================================================================
+
+// A shared span is a file mapped into every process using it: a header and
+// a fixed number of slots, each holding one logical token. All integers are
+// in host byte order, so processes on one machine agree, and the layout is
+// this C declaration:
+//
+//	struct rift_shm_header {        /* 64 bytes */
+//	    char     magic[8];          /* "RIFTSHM\0" */
+//	    uint32_t version;           /* 1 */
+//	    uint32_t slot_count;
+//	    uint32_t slot_size;         /* 256 */
+//	    uint8_t  reserved[44];
+//	};
+//	struct rift_shm_slot {          /* 256 bytes */
+//	    char     name[32];          /* NUL-padded; empty = free */
+//	    uint32_t type;              /* token type */
+//	    uint32_t validation_bits;   /* RIFT_TOKEN_LOCKED is never set */
+//	    uint32_t entanglement_id;   /* FNV-1a of name */
+//	    uint32_t string_len;
+//	    uint64_t seq;               /* bumped with every value change */
+//	    int64_t  int_val;
+//	    double   float_val;
+//	    char     string_val[184];
+//	};
+//
+// The advisory-lock protocol uses fcntl byte-range locks: a process holds
+// F_RDLCK on a slot's bytes to read it and F_WRLCK to write it, and F_WRLCK
+// on the header's bytes to allocate a slot or create the file. fcntl locks
+// belong to the process, so a process opens a shared span once and
+// serializes its own threads on top.
+const (
+	SharedSlotSize  = 256
+	SharedNameMax   = 32
+	SharedStringMax = 184
+
+	sharedMagic      = "RIFTSHM\x00"
+	sharedVersion    = 1
+	sharedHeaderSize = 64
+)
+
+// Slot field offsets
+const (
+	slotName     = 0
+	slotType     = 32
+	slotBits     = 36
+	slotEntID    = 40
+	slotStrLen   = 44
+	slotSeq      = 48
+	slotIntVal   = 56
+	slotFloatVal = 64
+	slotString   = 72
+)
+
+// ============================================================================
+// SharedSpan
+// ============================================================================
+
+// SharedSpan is a memory span backed by a file mapped into several
+// processes. Tokens placed on it with Token are entangled across the
+// processes: each is a slot that every process's token reads and writes.
+type SharedSpan struct {
+	path      string
+	file      *os.File
+	data      []byte
+	span      *RiftMemorySpan
+	lock      sync.RWMutex // write-held by Close
+	closed    bool
+	allocLock sync.Mutex     // this process's side of the header lock
+	slotLocks []sync.RWMutex // this process's side of the slot locks
+}
+
+// sharedSlot binds a token to its slot
+type sharedSlot struct {
+	span  *SharedSpan
+	index int
+	seen  uint64 // slot seq last loaded or stored; the token's valueLock held
+}
+
+// OpenSharedSpan maps the shared span in the file at path, creating it with
+// slots slots if it does not exist or is empty. An existing span keeps its
+// slot count, and slots may then be 0. Shared spans need a Unix system.
+func OpenSharedSpan(path string, slots int) (*SharedSpan, error) {
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0o600)
+	if err != nil {
+		return nil, err
+	}
+	s, err := openSharedSpan(f, slots)
+	if err != nil {
+		f.Close()
+		return nil, fmt.Errorf("shared span %s: %w", path, err)
+	}
+	s.path = path
+	return s, nil
+}
+
+func openSharedSpan(f *os.File, slots int) (*SharedSpan, error) {
+	if err := lockRange(f, 0, sharedHeaderSize, true); err != nil {
+		return nil, err
+	}
+	defer unlockRange(f, 0, sharedHeaderSize)
+
+	info, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+	header := make([]byte, sharedHeaderSize)
+	if info.Size() == 0 {
+		if slots <= 0 {
+			return nil, fmt.Errorf("new span needs a positive slot count, not %d", slots)
+		}
+		copy(header, sharedMagic)
+		binary.NativeEndian.PutUint32(header[8:], sharedVersion)
+		binary.NativeEndian.PutUint32(header[12:], uint32(slots))
+		binary.NativeEndian.PutUint32(header[16:], SharedSlotSize)
+		if err := f.Truncate(int64(sharedHeaderSize + slots*SharedSlotSize)); err != nil {
+			return nil, err
+		}
+		if _, err := f.WriteAt(header, 0); err != nil {
+			return nil, err
+		}
+	} else {
+		if _, err := f.ReadAt(header, 0); err != nil {
+			return nil, fmt.Errorf("read header: %w", err)
+		}
+		if string(header[:8]) != sharedMagic {
+			return nil, fmt.Errorf("not a shared span")
+		}
+		if v := binary.NativeEndian.Uint32(header[8:]); v != sharedVersion {
+			return nil, fmt.Errorf("unsupported layout version %d", v)
+		}
+		if size := binary.NativeEndian.Uint32(header[16:]); size != SharedSlotSize {
+			return nil, fmt.Errorf("slot size %d, want %d", size, SharedSlotSize)
+		}
+		slots = int(binary.NativeEndian.Uint32(header[12:]))
+		if info.Size() < int64(sharedHeaderSize+slots*SharedSlotSize) {
+			return nil, fmt.Errorf("file truncated to %d bytes", info.Size())
+		}
+	}
+
+	size := sharedHeaderSize + slots*SharedSlotSize
+	data, err := mapFile(f, size)
+	if err != nil {
+		return nil, err
+	}
+	return &SharedSpan{
+		file:      f,
+		data:      data,
+		span:      NewRiftMemorySpan(SpanEntangled, uint64(size)),
+		slotLocks: make([]sync.RWMutex, slots),
+	}, nil
+}
+
+// Close unmaps the span. Its tokens keep their last values but no longer
+// share them.
+func (s *SharedSpan) Close() error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if s.closed {
+		return nil
+	}
+	s.closed = true
+	err := unmapFile(s.data)
+	s.data = nil
+	if cerr := s.file.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
+
+// Span returns the governance span covering the mapped file
+func (s *SharedSpan) Span() *RiftMemorySpan {
+	return s.span
+}
+
+// Slots returns the span's slot count
+func (s *SharedSpan) Slots() int {
+	return len(s.slotLocks)
+}
+
+// ============================================================================
+// Shared Tokens
+// ============================================================================
+
+// Token returns a token entangled with every token of the same name on the
+// span, in this process or another: a value set on one is seen by the
+// others' GetValue, and validation bits are shared except TokenLocked,
+// which stays per process. The first process to name a slot allocates it
+// with tokenType; later callers must ask for the same type. The token's
+// EntanglementID is the slot's, the same in every process. Shared values
+// are scalars: PtrVal and ArrVal cannot be shared, and strings hold at most
+// SharedStringMax bytes. Version and CompareAndSetValue count this
+// process's view of the value.
+func (s *SharedSpan) Token(name string, tokenType int) (*RiftToken, error) {
+	if name == "" || len(name) > SharedNameMax || strings.IndexByte(name, 0) >= 0 {
+		return nil, fmt.Errorf("shared token name %q must be 1 to %d bytes without NUL", name, SharedNameMax)
+	}
+	s.lock.RLock()
+	if s.closed {
+		s.lock.RUnlock()
+		return nil, fmt.Errorf("shared span %s is closed", s.path)
+	}
+	index, entID, err := s.allocate(name, tokenType)
+	s.lock.RUnlock()
+	if err != nil {
+		return nil, err
+	}
+	memory := NewRiftMemorySpan(SpanEntangled, SharedSlotSize)
+	token := NewRiftToken(tokenType, memory)
+	token.EntanglementID = entID
+	registerEntanglement(token, entID)
+	token.SetBit(TokenEntangled)
+	sh := &sharedSlot{span: s, index: index}
+	token.shared.Store(sh)
+	sh.load(token, false)
+	auditEmit(AuditEntangle, token, "shared "+name)
+	return token, nil
+}
+
+// allocate finds or allocates the slot named name; s.lock read-held
+func (s *SharedSpan) allocate(name string, tokenType int) (int, uint32, error) {
+	s.allocLock.Lock()
+	defer s.allocLock.Unlock()
+	if err := lockRange(s.file, 0, sharedHeaderSize, true); err != nil {
+		return 0, 0, err
+	}
+	defer unlockRange(s.file, 0, sharedHeaderSize)
+
+	free := -1
+	for i := range s.slotLocks {
+		slot := s.slot(i)
+		slotName := slot[slotName : slotName+SharedNameMax]
+		if slotName[0] == 0 {
+			if free < 0 {
+				free = i
+			}
+			continue
+		}
+		if string(bytes.TrimRight(slotName, "\x00")) != name {
+			continue
+		}
+		if typ := int(binary.NativeEndian.Uint32(slot[slotType:])); typ != tokenType {
+			return 0, 0, govErr(CodeConversion, "shared token", "%q is %s, not %s", name, TokenTypeName(typ), TokenTypeName(tokenType))
+		}
+		return i, binary.NativeEndian.Uint32(slot[slotEntID:]), nil
+	}
+	if free < 0 {
+		return 0, 0, govErr(CodeNoMemory, "shared token", "no free slot for %q in %d slots", name, len(s.slotLocks))
+	}
+
+	h := fnv.New32a()
+	h.Write([]byte(name))
+	entID := h.Sum32()
+	if err := s.lockSlot(free, true); err != nil {
+		return 0, 0, err
+	}
+	slot := s.slot(free)
+	clear(slot)
+	copy(slot[slotName:], name)
+	binary.NativeEndian.PutUint32(slot[slotType:], uint32(tokenType))
+	binary.NativeEndian.PutUint32(slot[slotBits:], TokenAllocated|TokenEntangled)
+	binary.NativeEndian.PutUint32(slot[slotEntID:], entID)
+	s.unlockSlot(free, true)
+	return free, entID, nil
+}
+
+// slot returns slot i's bytes; s.lock read-held
+func (s *SharedSpan) slot(i int) []byte {
+	off := sharedHeaderSize + i*SharedSlotSize
+	return s.data[off : off+SharedSlotSize]
+}
+
+// lockSlot takes this process's and the file's lock on slot i
+func (s *SharedSpan) lockSlot(i int, write bool) error {
+	if write {
+		s.slotLocks[i].Lock()
+	} else {
+		s.slotLocks[i].RLock()
+	}
+	if err := lockRange(s.file, int64(sharedHeaderSize+i*SharedSlotSize), SharedSlotSize, write); err != nil {
+		s.releaseSlot(i, write)
+		return err
+	}
+	return nil
+}
+
+// unlockSlot releases the locks lockSlot took
+func (s *SharedSpan) unlockSlot(i int, write bool) {
+	unlockRange(s.file, int64(sharedHeaderSize+i*SharedSlotSize), SharedSlotSize)
+	s.releaseSlot(i, write)
+}
+
+func (s *SharedSpan) releaseSlot(i int, write bool) {
+	if write {
+		s.slotLocks[i].Unlock()
+	} else {
+		s.slotLocks[i].RUnlock()
+	}
+}
+
+// ============================================================================
+// Slot Transfer
+// ============================================================================
+
+// sharedValueError reports why val cannot be shared, or nil
+func sharedValueError(val RiftTokenValue) *GovernanceError {
+	switch {
+	case val.PtrVal != nil || val.ArrVal != nil:
+		return govErr(CodeConversion, "set", "shared tokens hold scalar values only")
+	case len(val.StringVal) > SharedStringMax:
+		return govErr(CodeSpanBounds, "set", "string of %d bytes exceeds shared slot capacity %d", len(val.StringVal), SharedStringMax)
+	}
+	return nil
+}
+
+// store writes t's value and bits to the slot as a new change; t's
+// valueLock held
+func (sh *sharedSlot) store(t *RiftToken) {
+	s := sh.span
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	if s.closed || s.lockSlot(sh.index, true) != nil {
+		return
+	}
+	slot := s.slot(sh.index)
+	seq := binary.NativeEndian.Uint64(slot[slotSeq:]) + 1
+	str := t.Value.StringVal
+	if len(str) > SharedStringMax {
+		str = str[:SharedStringMax]
+	}
+	binary.NativeEndian.PutUint32(slot[slotBits:], t.ValidationBits.Load()&^TokenLocked)
+	binary.NativeEndian.PutUint32(slot[slotStrLen:], uint32(len(str)))
+	binary.NativeEndian.PutUint64(slot[slotSeq:], seq)
+	binary.NativeEndian.PutUint64(slot[slotIntVal:], uint64(t.Value.IntVal))
+	binary.NativeEndian.PutUint64(slot[slotFloatVal:], math.Float64bits(t.Value.FloatVal))
+	copy(slot[slotString:slotString+SharedStringMax], str)
+	s.unlockSlot(sh.index, true)
+	sh.seen = seq
+}
+
+// storeBits writes t's validation bits to the slot
+func (sh *sharedSlot) storeBits(t *RiftToken) {
+	s := sh.span
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	if s.closed || s.lockSlot(sh.index, true) != nil {
+		return
+	}
+	binary.NativeEndian.PutUint32(s.slot(sh.index)[slotBits:], t.ValidationBits.Load()&^TokenLocked)
+	s.unlockSlot(sh.index, true)
+}
+
+// load reads the slot's bits into t and, when the slot has changed since
+// t last saw it, its value, firing t's change hooks if notify is set
+func (sh *sharedSlot) load(t *RiftToken, notify bool) {
+	s := sh.span
+	s.lock.RLock()
+	if s.closed || s.lockSlot(sh.index, false) != nil {
+		s.lock.RUnlock()
+		return
+	}
+	slot := s.slot(sh.index)
+	bits := binary.NativeEndian.Uint32(slot[slotBits:])
+	seq := binary.NativeEndian.Uint64(slot[slotSeq:])
+	strLen := min(int(binary.NativeEndian.Uint32(slot[slotStrLen:])), SharedStringMax)
+	val := RiftTokenValue{
+		IntVal:    int64(binary.NativeEndian.Uint64(slot[slotIntVal:])),
+		FloatVal:  math.Float64frombits(binary.NativeEndian.Uint64(slot[slotFloatVal:])),
+		StringVal: string(slot[slotString : slotString+strLen]),
+	}
+	s.unlockSlot(sh.index, false)
+	s.lock.RUnlock()
+
+	t.valueLock.Lock()
+	for {
+		cur := t.ValidationBits.Load()
+		if t.ValidationBits.CompareAndSwap(cur, bits&^TokenLocked|cur&TokenLocked) {
+			break
+		}
+	}
+	changed := seq > sh.seen
+	old := t.Value
+	if changed {
+		t.Value = val
+		t.version.Add(1)
+		t.resealLocked()
+		sh.seen = seq
+	}
+	t.valueLock.Unlock()
+	if changed && notify {
+		t.fireChange(old, val)
+	}
+}
+
+// Refresh loads a shared token's value and validation bits from its slot,
+// as GetValue does, and reports whether the value changed. It does nothing
+// for other tokens.
+func (t *RiftToken) Refresh() bool {
+	sh := t.shared.Load()
+	if sh == nil {
+		return false
+	}
+	before := t.version.Load()
+	sh.load(t, true)
+	return t.version.Load() != before
+}
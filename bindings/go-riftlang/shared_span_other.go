@@ -0,0 +1,30 @@
+// go/target/shared_span_other.go
+// Cross-Process Shared Spans (Fallback) - Go Implementation
+
+//go:build !unix
+
+package rift
+
+import (
+	"fmt"
+	"os"
+	"runtime"
+)
+
+var errSharedUnsupported = fmt.Errorf("shared spans are not supported on %s", runtime.GOOS)
+
+const mmapSupported = false
+
+func mapFile(f *os.File, size int) ([]byte, error) {
+	return nil, errSharedUnsupported
+}
+
+func unmapFile(data []byte) error {
+	return nil
+}
+
+func lockRange(f *os.File, off, n int64, write bool) error {
+	return errSharedUnsupported
+}
+
+func unlockRange(f *os.File, off, n int64) {}
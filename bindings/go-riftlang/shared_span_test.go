@@ -0,0 +1,104 @@
+package rift
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+// openShared opens the shared span at path, skipping where shared spans
+// are unsupported
+func openShared(t *testing.T, path string, slots int) *SharedSpan {
+	t.Helper()
+	if !mmapSupported {
+		t.Skip("shared spans need a Unix system")
+	}
+	s, err := OpenSharedSpan(path, slots)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+// TestSharedSpanRoundTrip maps one file twice, as two processes would, and
+// passes values both ways between the tokens of a slot
+func TestSharedSpanRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "shared.shm")
+	a := openShared(t, path, 4)
+	defer a.Close()
+	b := openShared(t, path, 0)
+	defer b.Close()
+	if b.Slots() != 4 {
+		t.Fatalf("second mapping has %d slots, want 4", b.Slots())
+	}
+
+	ta, err := a.Token("counter", TokenGoInt)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tb, err := b.Token("counter", TokenGoInt)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ta.EntanglementID != tb.EntanglementID {
+		t.Errorf("entanglement IDs %d and %d differ", ta.EntanglementID, tb.EntanglementID)
+	}
+
+	if err := ta.SetValue(RiftTokenValue{IntVal: 42}); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := tb.GetValue(); err != nil || got.IntVal != 42 {
+		t.Fatalf("second mapping read %d, %v; want 42", got.IntVal, err)
+	}
+	if err := tb.SetValue(RiftTokenValue{IntVal: -7}); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := ta.GetValue(); err != nil || got.IntVal != -7 {
+		t.Fatalf("first mapping read %d, %v; want -7", got.IntVal, err)
+	}
+
+	sa, err := a.Token("label", TokenGoString)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := sa.SetValue(RiftTokenValue{StringVal: "héllo"}); err != nil {
+		t.Fatal(err)
+	}
+	sb, err := b.Token("label", TokenGoString)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, err := sb.GetValue(); err != nil || got.StringVal != "héllo" {
+		t.Fatalf("second mapping read %q, %v; want %q", got.StringVal, err, "héllo")
+	}
+
+	if _, err := b.Token("counter", TokenGoString); ErrorCodeOf(err) != CodeConversion {
+		t.Errorf("Token with another type: %v, want %v", err, CodeConversion)
+	}
+}
+
+// TestSharedSpanReopen closes every mapping of a file and opens it again:
+// slots and values persist
+func TestSharedSpanReopen(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "shared.shm")
+	s := openShared(t, path, 2)
+	tok, err := s.Token("kept", TokenGoFloat)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := tok.SetValue(RiftTokenValue{FloatVal: 2.5}); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	s = openShared(t, path, 0)
+	defer s.Close()
+	tok, err = s.Token("kept", TokenGoFloat)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, err := tok.GetValue(); err != nil || got.FloatVal != 2.5 {
+		t.Fatalf("reopened span read %v, %v; want 2.5", got.FloatVal, err)
+	}
+}
@@ -0,0 +1,45 @@
+// go/target/shared_span_unix.go
+// Cross-Process Shared Spans (Unix) - Go Implementation
+
+//go:build unix
+
+package rift
+
+import (
+	"io"
+	"os"
+	"syscall"
+)
+
+// mmapSupported reports whether mapFile can map files
+const mmapSupported = true
+
+// mapFile maps size bytes of f shared and writable
+func mapFile(f *os.File, size int) ([]byte, error) {
+	return syscall.Mmap(int(f.Fd()), 0, size, syscall.PROT_READ|syscall.PROT_WRITE, syscall.MAP_SHARED)
+}
+
+// unmapFile unmaps a mapping made by mapFile
+func unmapFile(data []byte) error {
+	return syscall.Munmap(data)
+}
+
+// lockRange takes an fcntl lock on n bytes of f at off, waiting for it
+func lockRange(f *os.File, off, n int64, write bool) error {
+	lk := syscall.Flock_t{Type: syscall.F_RDLCK, Whence: io.SeekStart, Start: off, Len: n}
+	if write {
+		lk.Type = syscall.F_WRLCK
+	}
+	for {
+		err := syscall.FcntlFlock(f.Fd(), syscall.F_SETLKW, &lk)
+		if err != syscall.EINTR {
+			return err
+		}
+	}
+}
+
+// unlockRange releases a lock taken by lockRange
+func unlockRange(f *os.File, off, n int64) {
+	lk := syscall.Flock_t{Type: syscall.F_UNLCK, Whence: io.SeekStart, Start: off, Len: n}
+	syscall.FcntlFlock(f.Fd(), syscall.F_SETLK, &lk)
+}
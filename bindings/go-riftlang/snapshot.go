@@ -0,0 +1,426 @@
+// go/target/snapshot.go
+// Governance State Snapshot and Restore - Go Implementation
+
+package rift
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// StateDumpVersion is the StateDump format written by Snapshot
+const StateDumpVersion = 1
+
+// ============================================================================
+// StateDump
+// ============================================================================
+
+// StateDump is a self-contained copy of the package's governance state:
+// the tokens in DefaultRegistry and every token entangled with them, their
+// memory spans (whole carve trees), entanglement links and joint registers,
+// the engines registered with RegisterEngine, the entropy gates and the
+// max lock hold. Pointers become indices into Spans and Tokens, so a dump
+// encodes to JSON and can be inspected or restored in another process.
+type StateDump struct {
+	Version      int                    `json:"version"`
+	Taken        time.Time              `json:"taken"`
+	Spans        []SpanDump             `json:"spans,omitempty"`
+	Tokens       []TokenDump            `json:"tokens,omitempty"`
+	Registers    []RegisterDump         `json:"registers,omitempty"`
+	Engines      []EngineDump           `json:"engines,omitempty"`
+	EntropyGates map[string]EntropyGate `json:"entropyGates,omitempty"`
+	MaxLockHold  time.Duration          `json:"maxLockHold,omitempty"`
+}
+
+// SpanDump is a memory span and its place in its carve tree
+type SpanDump struct {
+	Type       int    `json:"type"`
+	Bytes      uint64 `json:"bytes"`
+	Alignment  uint32 `json:"alignment"`
+	Open       bool   `json:"open"`
+	Direction  bool   `json:"direction"`
+	AccessMask uint32 `json:"accessMask"`
+	Parent     int    `json:"parent"` // index in Spans; -1 for a root span
+	Offset     uint64 `json:"offset,omitempty"`
+	Released   bool   `json:"released,omitempty"`
+
+	Affinity *SpanAffinity `json:"affinity,omitempty"`
+}
+
+// TokenDump is a token in its JSON form (see RiftToken.MarshalJSON) plus
+// the links that form cannot hold
+type TokenDump struct {
+	Names         []string        `json:"names,omitempty"` // DefaultRegistry names; none for tokens reached by entanglement
+	Span          int             `json:"span"`            // index in Spans; -1 without memory
+	Token         json.RawMessage `json:"token"`
+	EntangledWith []int           `json:"entangledWith,omitempty"` // indices in Tokens
+}
+
+// RegisterDump is the shared state vector of BellPair/GHZ qubits
+type RegisterDump struct {
+	Members    []int            `json:"members"` // indices in Tokens, by qubit
+	Amplitudes []BasisAmplitude `json:"amplitudes"`
+}
+
+// BasisAmplitude is one non-zero amplitude of a joint register
+type BasisAmplitude struct {
+	Basis uint64  `json:"basis"`
+	Re    float64 `json:"re"`
+	Im    float64 `json:"im,omitempty"`
+}
+
+// EngineDump is a registered pattern engine's configuration. Match
+// counters are not part of it.
+type EngineDump struct {
+	Name        string     `json:"name,omitempty"` // RegisterEngine name; none in an EngineExport
+	Mode        string     `json:"mode"`
+	Workers     int        `json:"workers,omitempty"`
+	TieBreak    TieBreak   `json:"tieBreak,omitempty"`
+	Selection   Selection  `json:"selection,omitempty"`
+	MatchCache  int        `json:"matchCache,omitempty"`     // see SetMatchCache
+	Disabled    []string   `json:"disabledGroups,omitempty"` // see DisableGroup
+	Pairs       []PairDump `json:"pairs,omitempty"`
+	NextID      uint32     `json:"nextId,omitempty"`      // last TransformID allocated
+	PairVersion uint64     `json:"pairVersion,omitempty"` // see PatternEngine.Version
+	Adaptive    bool       `json:"adaptive,omitempty"`    // see SetAdaptiveOrder
+}
+
+// PairDump is a pattern pair in TransformID order. A zero ID, as in dumps
+// from before pair removal, is allocated on restore.
+type PairDump struct {
+	ID             uint32 `json:"id,omitempty"`
+	Left           string `json:"left"`
+	Right          string `json:"right"`
+	Priority       uint32 `json:"priority"`
+	RightIsLiteral bool   `json:"rightIsLiteral,omitempty"`
+	Substitution   string `json:"substitution,omitempty"` // when not implied by RightIsLiteral
+	Governed       bool   `json:"governed,omitempty"`
+	Group          string `json:"group,omitempty"`
+}
+
+// ============================================================================
+// Snapshot
+// ============================================================================
+
+// Snapshot captures the governance state in a StateDump. Tokens are read
+// without taking their locks, so a running simulation should be paused
+// for a consistent checkpoint. It fails if a registered engine has a pair
+// with a TransformFn, since functions cannot be captured.
+func Snapshot() (*StateDump, error) {
+	dump := &StateDump{Version: StateDumpVersion, Taken: clockNow(), MaxLockHold: MaxLockHold()}
+
+	// Tokens: registered ones in name order, then entanglement partners
+	// and register members breadth-first
+	index := make(map[*RiftToken]int)
+	var tokens []*RiftToken
+	add := func(t *RiftToken) int {
+		i, ok := index[t]
+		if !ok {
+			i = len(tokens)
+			index[t] = i
+			tokens = append(tokens, t)
+			dump.Tokens = append(dump.Tokens, TokenDump{Span: -1})
+		}
+		return i
+	}
+	DefaultRegistry.Range(func(name string, t *RiftToken) bool {
+		i := add(t)
+		dump.Tokens[i].Names = append(dump.Tokens[i].Names, name)
+		return true
+	})
+	registers := make(map[*jointRegister]int)
+	for i := 0; i < len(tokens); i++ {
+		t := tokens[i]
+		for _, p := range t.EntangledWith {
+			j := add(p)
+			dump.Tokens[i].EntangledWith = append(dump.Tokens[i].EntangledWith, j)
+		}
+		if reg := t.joint; reg != nil {
+			if _, ok := registers[reg]; !ok {
+				registers[reg] = len(dump.Registers)
+				dump.Registers = append(dump.Registers, reg.dump(add))
+			}
+		}
+	}
+
+	for i, t := range tokens {
+		data, err := t.MarshalJSON()
+		if err != nil {
+			return nil, fmt.Errorf("snapshot token %d: %w", i, err)
+		}
+		dump.Tokens[i].Token = data
+	}
+
+	// Spans: the whole carve tree of every token's span
+	spanTree.Lock()
+	spans := make(map[*RiftMemorySpan]int)
+	for i, t := range tokens {
+		if t.Memory == nil {
+			continue
+		}
+		if _, ok := spans[t.Memory]; !ok {
+			root := t.Memory
+			for root.parent != nil {
+				root = root.parent
+			}
+			dump.dumpSpanTree(root, -1, spans)
+		}
+		dump.Tokens[i].Span = spans[t.Memory]
+	}
+	spanTree.Unlock()
+
+	for _, name := range EngineNames() {
+		e, ok := LookupEngine(name)
+		if !ok {
+			continue
+		}
+		ed, err := e.dump(name)
+		if err != nil {
+			return nil, err
+		}
+		dump.Engines = append(dump.Engines, ed)
+	}
+
+	entropyGates.lock.RLock()
+	if len(entropyGates.modes) > 0 {
+		dump.EntropyGates = make(map[string]EntropyGate, len(entropyGates.modes))
+		for mode, gate := range entropyGates.modes {
+			dump.EntropyGates[mode] = gate
+		}
+	}
+	entropyGates.lock.RUnlock()
+
+	return dump, nil
+}
+
+// dumpSpanTree appends s and its descendants; spanTree held
+func (d *StateDump) dumpSpanTree(s *RiftMemorySpan, parent int, index map[*RiftMemorySpan]int) {
+	index[s] = len(d.Spans)
+	d.Spans = append(d.Spans, SpanDump{
+		Type:       s.Type,
+		Bytes:      s.Bytes,
+		Alignment:  s.Alignment,
+		Open:       s.Open,
+		Direction:  s.Direction,
+		AccessMask: s.Mask(),
+		Parent:     parent,
+		Offset:     s.offset,
+		Released:   s.released.Load(),
+		Affinity:   s.affinity,
+	})
+	self := index[s]
+	for _, c := range s.children {
+		d.dumpSpanTree(c, self, index)
+	}
+}
+
+// dump copies the register, numbering members with add
+func (reg *jointRegister) dump(add func(*RiftToken) int) RegisterDump {
+	reg.lock.Lock()
+	defer reg.lock.Unlock()
+	var rd RegisterDump
+	for _, m := range reg.members {
+		rd.Members = append(rd.Members, add(m))
+	}
+	for basis, a := range reg.amps {
+		rd.Amplitudes = append(rd.Amplitudes, BasisAmplitude{Basis: basis, Re: real(a), Im: imag(a)})
+	}
+	return rd
+}
+
+// dump captures the engine's configuration
+func (e *PatternEngine) dump(name string) (EngineDump, error) {
+	e.lock.RLock()
+	defer e.lock.RUnlock()
+	ed := EngineDump{Name: name, Mode: e.mode.String(), Workers: e.workers, TieBreak: e.index.tie, Selection: e.selection,
+		NextID: e.nextID, PairVersion: e.version}
+	if e.cache != nil {
+		ed.MatchCache = e.cache.size
+	}
+	ed.Adaptive = e.adaptive != nil
+	ed.Disabled = e.disabledGroups()
+	for _, p := range e.pairs {
+		if p.TransformFn != nil {
+			return EngineDump{}, fmt.Errorf("snapshot engine %q: pair %d has a TransformFn", name, p.TransformID)
+		}
+		pd := PairDump{
+			ID:             p.TransformID,
+			Left:           p.Left.PatternStr,
+			Right:          p.Right.PatternStr,
+			Priority:       p.Left.Priority,
+			RightIsLiteral: p.Right.IsLiteral,
+			Governed:       p.IsGoverned,
+			Group:          p.Group,
+		}
+		if p.Substitution != literalSubstitution(p.Right.IsLiteral) {
+			pd.Substitution = p.Substitution.String()
+		}
+		ed.Pairs = append(ed.Pairs, pd)
+	}
+	return ed, nil
+}
+
+// ============================================================================
+// Restore
+// ============================================================================
+
+// Restore replaces the governance state with a dump: DefaultRegistry and
+// the engine registry are reset to the dumped tokens and engines, and the
+// entropy gates and max lock hold are set. Restored tokens are new values;
+// tokens and engines held from before the restore are left untouched. On
+// error nothing is changed.
+func Restore(dump *StateDump) error {
+	if dump == nil {
+		return fmt.Errorf("restore: nil state dump")
+	}
+	if dump.Version != StateDumpVersion {
+		return fmt.Errorf("restore: unsupported state dump version %d", dump.Version)
+	}
+
+	spans := make([]*RiftMemorySpan, len(dump.Spans))
+	for i, sd := range dump.Spans {
+		s := &RiftMemorySpan{
+			Type:       sd.Type,
+			Bytes:      sd.Bytes,
+			Alignment:  sd.Alignment,
+			Open:       sd.Open,
+			Direction:  sd.Direction,
+			AccessMask: sd.AccessMask,
+			offset:     sd.Offset,
+			affinity:   sd.Affinity,
+		}
+		s.released.Store(sd.Released)
+		if sd.Parent >= 0 {
+			if sd.Parent >= i {
+				return fmt.Errorf("restore: span %d has parent %d out of order", i, sd.Parent)
+			}
+			s.parent = spans[sd.Parent]
+			s.parent.children = append(s.parent.children, s)
+		}
+		spans[i] = s
+	}
+
+	tokens := make([]*RiftToken, len(dump.Tokens))
+	for i := range tokens {
+		tokens[i] = &RiftToken{}
+	}
+	for i, td := range dump.Tokens {
+		t := tokens[i]
+		if err := t.UnmarshalJSON(td.Token); err != nil {
+			return fmt.Errorf("restore token %d: %w", i, err)
+		}
+		if td.Span >= 0 {
+			if td.Span >= len(spans) {
+				return fmt.Errorf("restore token %d: span %d out of range", i, td.Span)
+			}
+			t.Memory = spans[td.Span]
+		}
+		for _, j := range td.EntangledWith {
+			if j < 0 || j >= len(tokens) {
+				return fmt.Errorf("restore token %d: entangled token %d out of range", i, j)
+			}
+			t.EntangledWith = append(t.EntangledWith, tokens[j])
+		}
+	}
+
+	for r, rd := range dump.Registers {
+		if len(rd.Members) > maxRegisterQubits {
+			return fmt.Errorf("restore register %d: %d qubits exceed %d", r, len(rd.Members), maxRegisterQubits)
+		}
+		reg := &jointRegister{amps: make(map[uint64]complex128, len(rd.Amplitudes))}
+		for _, a := range rd.Amplitudes {
+			reg.amps[a.Basis] = complex(a.Re, a.Im)
+		}
+		for q, j := range rd.Members {
+			if j < 0 || j >= len(tokens) {
+				return fmt.Errorf("restore register %d: member %d out of range", r, j)
+			}
+			tokens[j].joint = reg
+			tokens[j].jointQubit = q
+			reg.members = append(reg.members, tokens[j])
+		}
+	}
+
+	restored := make(map[string]*PatternEngine, len(dump.Engines))
+	for _, ed := range dump.Engines {
+		e, err := ed.engine()
+		if err != nil {
+			return fmt.Errorf("restore engine %q: %w", ed.Name, err)
+		}
+		restored[ed.Name] = e
+	}
+
+	// Everything is built; swap it in
+	named := make(map[string]*RiftToken)
+	for i, td := range dump.Tokens {
+		for _, name := range td.Names {
+			named[name] = tokens[i]
+		}
+	}
+	DefaultRegistry.lock.Lock()
+	DefaultRegistry.reset(named)
+	DefaultRegistry.lock.Unlock()
+
+	engines.lock.Lock()
+	engines.byName = restored
+	engines.lock.Unlock()
+
+	entropyGates.lock.Lock()
+	entropyGates.modes = make(map[string]EntropyGate, len(dump.EntropyGates))
+	for mode, gate := range dump.EntropyGates {
+		entropyGates.modes[mode] = gate
+	}
+	entropyGates.lock.Unlock()
+
+	SetMaxLockHold(dump.MaxLockHold)
+	return nil
+}
+
+// engine builds a new pattern engine from the dump
+func (ed EngineDump) engine() (*PatternEngine, error) {
+	e := NewPatternEngine(ed.Mode)
+	e.workers = ed.Workers
+	e.index.tie = ed.TieBreak
+	e.selection = ed.Selection
+	if ed.MatchCache > 0 {
+		e.cache = newMatchCache(ed.MatchCache)
+	}
+	if ed.Adaptive {
+		e.adaptive = &adaptiveOrder{}
+	}
+	for _, g := range ed.Disabled {
+		e.setGroupLocked(g, false)
+	}
+	for _, pd := range ed.Pairs {
+		mode := literalSubstitution(pd.RightIsLiteral)
+		if pd.Substitution != "" {
+			var err error
+			if mode, err = SubstitutionNamed(pd.Substitution); err != nil {
+				return nil, err
+			}
+		}
+		pair, err := newPair(pd.Left, pd.Right, pd.Priority, mode)
+		if err != nil {
+			return nil, err
+		}
+		if pd.ID == 0 {
+			pd.ID = e.nextID + 1
+		}
+		if pd.ID <= e.nextID {
+			return nil, fmt.Errorf("pair %d out of order", pd.ID)
+		}
+		pair.TransformID = pd.ID
+		pair.IsGoverned = pd.Governed
+		pair.Group = pd.Group
+		e.pairs = append(e.pairs, pair)
+		e.index.add(pair)
+		if e.disabled[pair.Group] {
+			e.index.park(pair)
+		}
+		e.nextID = pd.ID
+	}
+	e.nextID = max(e.nextID, ed.NextID)
+	e.version = max(ed.PairVersion, uint64(len(ed.Pairs)))
+	return e, nil
+}
@@ -0,0 +1,52 @@
+// go/target/source.go
+// Token Source Locations - Go Implementation
+
+package rift
+
+import (
+	"fmt"
+	"sync/atomic"
+)
+
+// sourceCaptureOff disables source capture; capture is on by default
+var sourceCaptureOff atomic.Bool
+
+// SetSourceCapture turns source capture on or off. While on (the default),
+// NewRiftToken, and with it Var, Func, Go and Superpose, records the file
+// and line of the first caller outside this package in SourceFile and
+// SourceLine, so audit events and validation errors name the Go code that
+// created a token. Go reports no columns, so SourceColumn is left 0.
+// Turning capture off saves a stack walk per token.
+func SetSourceCapture(on bool) {
+	sourceCaptureOff.Store(!on)
+}
+
+// SourceCapture reports whether source capture is enabled
+func SourceCapture() bool {
+	return !sourceCaptureOff.Load()
+}
+
+// Source returns the token's source location as file:line, or "" if it is
+// not known
+func (t *RiftToken) Source() string {
+	if t.SourceFile == "" {
+		return ""
+	}
+	return fmt.Sprintf("%s:%d", t.SourceFile, t.SourceLine)
+}
+
+// captureSource records the caller outside this package as t's source
+// location if source capture is enabled
+func (t *RiftToken) captureSource() {
+	if sourceCaptureOff.Load() {
+		return
+	}
+	file, line := callerOutsidePackage()
+	t.SourceFile, t.SourceLine = file, uint32(line)
+}
+
+// located sets err's Source to t's source location and returns it
+func (t *RiftToken) located(err *GovernanceError) *GovernanceError {
+	err.Source = t.Source()
+	return err
+}
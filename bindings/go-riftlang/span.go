@@ -0,0 +1,205 @@
+// go/target/span.go
+// Memory Span Resizing and Sub-Span Carving - Go Implementation
+
+package rift
+
+import "sync"
+
+// spanTree guards the parent/child links of all spans.
+// Span sizes change rarely, so one lock keeps parent and child checks
+// consistent without a lock per span.
+var spanTree sync.Mutex
+
+// ============================================================================
+// Resizing
+// ============================================================================
+
+// Resize changes the span's size. The span must be open and not released,
+// its alignment must still be a power of two, a carved span must stay
+// within its parent without overlapping its siblings, and a parent must
+// still cover every child.
+func (s *RiftMemorySpan) Resize(bytes uint64) error {
+	spanTree.Lock()
+	defer spanTree.Unlock()
+
+	if s.released.Load() {
+		return govErr(CodeNoMemory, "resize", "span released")
+	}
+	if !s.Open {
+		return govErr(CodePermissionDenied, "resize", "span is closed")
+	}
+	if !s.ValidateAlignment() {
+		return govErr(CodeBadAlignment, "resize", "alignment %d is not a power of 2", s.Alignment)
+	}
+	if p := s.parent; p != nil {
+		if err := p.checkFit("resize", s, s.offset, bytes); err != nil {
+			return err
+		}
+		if err := cacheLineError("resize", s.Type, s.Alignment, p, s.offset, bytes); err != nil {
+			return err
+		}
+	}
+	if s.mapping != nil && bytes > uint64(len(s.data)) {
+		return govErr(CodeSpanBounds, "resize", "mapped span cannot grow past its file of %d bytes", len(s.data))
+	}
+	for _, c := range s.children {
+		if c.offset+c.Bytes > bytes {
+			return govErr(CodeSpanBounds, "resize", "%d bytes would cut off child span [%d, %d)", bytes, c.offset, c.offset+c.Bytes)
+		}
+	}
+	if bytes < s.Bytes {
+		s.truncate(bytes)
+	}
+	s.Bytes = bytes
+	if s.parent == nil && s.aligned {
+		s.back()
+	}
+	s.resizes.Add(1)
+	return nil
+}
+
+// truncate discards the span's contents from offset bytes on, so growing
+// the span again reads zeros there; spanTree held
+func (s *RiftMemorySpan) truncate(bytes uint64) {
+	if s.parent == nil {
+		if s.aligned {
+			clear(s.data[min(bytes, uint64(len(s.data))):]) // keep the aligned buffer
+		} else if uint64(len(s.data)) > bytes {
+			s.data = append([]byte(nil), s.data[:bytes]...)
+		}
+		return
+	}
+	r, base := s.root()
+	for i := base + bytes; i < base+s.Bytes && i < uint64(len(r.data)); i++ {
+		r.data[i] = 0
+	}
+}
+
+// ============================================================================
+// Carving
+// ============================================================================
+
+// Carve creates a sub-span covering [offset, offset+bytes) of the span. The
+// child inherits the parent's type, alignment and direction and its access
+// mask: Grant on the child cannot exceed the parent's mask, and Revoke on
+// the parent also revokes from its children. offset must be a multiple of
+// the alignment, and the child must fit inside the parent without
+// overlapping another child; a distributed child must not straddle a
+// cache line (see ValidateCacheLines). Releasing the parent releases the
+// child.
+func (s *RiftMemorySpan) Carve(offset, bytes uint64) (*RiftMemorySpan, error) {
+	spanTree.Lock()
+	defer spanTree.Unlock()
+
+	if s.released.Load() {
+		return nil, govErr(CodeNoMemory, "carve", "span released")
+	}
+	if !s.ValidateAlignment() {
+		return nil, govErr(CodeBadAlignment, "carve", "alignment %d is not a power of 2", s.Alignment)
+	}
+	if offset%uint64(s.Alignment) != 0 {
+		return nil, govErr(CodeBadAlignment, "carve", "offset %d is not a multiple of alignment %d", offset, s.Alignment)
+	}
+	if err := s.checkFit("carve", nil, offset, bytes); err != nil {
+		return nil, err
+	}
+	if err := cacheLineError("carve", s.Type, s.Alignment, s, offset, bytes); err != nil {
+		return nil, err
+	}
+
+	child := &RiftMemorySpan{
+		Type:       s.Type,
+		Bytes:      bytes,
+		Alignment:  s.Alignment,
+		Open:       s.Open,
+		Direction:  s.Direction,
+		AccessMask: s.Mask(),
+		parent:     s,
+		offset:     offset,
+		affinity:   s.affinity,
+	}
+	s.children = append(s.children, child)
+	return child, nil
+}
+
+// checkFit reports whether [offset, offset+bytes) fits inside s without
+// overlapping a child other than self; spanTree held
+func (s *RiftMemorySpan) checkFit(op string, self *RiftMemorySpan, offset, bytes uint64) error {
+	end := offset + bytes
+	if end < offset || end > s.Bytes {
+		return govErr(CodeSpanBounds, op, "%d bytes at offset %d exceed parent span of %d bytes", bytes, offset, s.Bytes)
+	}
+	for _, c := range s.children {
+		if c != self && offset < c.offset+c.Bytes && c.offset < end {
+			return govErr(CodeSpanBounds, op, "[%d, %d) overlaps child span [%d, %d)", offset, end, c.offset, c.offset+c.Bytes)
+		}
+	}
+	return nil
+}
+
+// Parent returns the span this span was carved from, or nil
+func (s *RiftMemorySpan) Parent() *RiftMemorySpan {
+	spanTree.Lock()
+	defer spanTree.Unlock()
+	return s.parent
+}
+
+// Offset returns the span's offset within its parent
+func (s *RiftMemorySpan) Offset() uint64 {
+	return s.offset
+}
+
+// Children returns the live spans carved from this span
+func (s *RiftMemorySpan) Children() []*RiftMemorySpan {
+	spanTree.Lock()
+	defer spanTree.Unlock()
+	return append([]*RiftMemorySpan(nil), s.children...)
+}
+
+// ============================================================================
+// Release
+// ============================================================================
+
+// Release ends the span's lifetime along with every span carved from it,
+// returning its range, zeroed, to the parent. Tokens on a released span
+// fail validation.
+func (s *RiftMemorySpan) Release() {
+	spanTree.Lock()
+	defer spanTree.Unlock()
+
+	if p := s.parent; p != nil {
+		for i, c := range p.children {
+			if c == s {
+				p.children = append(p.children[:i], p.children[i+1:]...)
+				break
+			}
+		}
+		s.truncate(0)
+	}
+	s.release()
+}
+
+// release marks s and its descendants released, wiping a root span's
+// contents; spanTree held
+func (s *RiftMemorySpan) release() {
+	s.released.Store(true)
+	for i := range s.data {
+		s.data[i] = 0
+	}
+	if s.mapping != nil {
+		s.mapping.sync(s.data) // zero the file too
+		s.mapping.close(s.data)
+		s.mapping = nil
+	}
+	s.data = nil
+	s.aligned = false
+	for _, c := range s.children {
+		c.release()
+	}
+	s.children = nil
+}
+
+// Released reports whether the span or an ancestor has been released
+func (s *RiftMemorySpan) Released() bool {
+	return s.released.Load()
+}
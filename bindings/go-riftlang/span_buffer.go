@@ -0,0 +1,155 @@
+// go/target/span_buffer.go
+// Aligned Span Buffers - Go Implementation
+
+package rift
+
+import (
+	"unsafe"
+)
+
+// ============================================================================
+// Buffer
+// ============================================================================
+
+// Buffer returns the span's memory: Bytes bytes whose first byte sits at
+// an address that is a multiple of the root span's Alignment, holding
+// what has been written to the span. A carved span's buffer is its range
+// of the root's, aligned too since carving offsets are multiples of the
+// alignment. The buffer is allocated on first use, over-allocated by
+// Alignment-1 bytes so an aligned start can be chosen within it, and is
+// the span's storage from then on: ReadAt, WriteAt and the span's readers
+// and writers see writes made through it and the reverse.
+//
+// Access through the buffer bypasses the access mask and is not
+// synchronized; use the checked helpers (ReadBytes, WriteBytes and so on)
+// or ReadAt and WriteAt for governed access. Resizing the root span past
+// its buffer's size moves the contents to a new buffer, leaving slices of
+// the old one stale, and Release zeroes the buffer. Buffer returns nil for
+// a released span or one whose alignment is not a power of two.
+func (s *RiftMemorySpan) Buffer() []byte {
+	spanTree.Lock()
+	defer spanTree.Unlock()
+	if s.released.Load() || !s.ValidateAlignment() {
+		return nil
+	}
+	r, base := s.root()
+	if !r.ValidateAlignment() {
+		return nil
+	}
+	r.back()
+	end := base + s.Bytes
+	return r.data[base:end:end]
+}
+
+// back gives a root span an aligned buffer of at least Bytes bytes,
+// keeping its contents; spanTree held
+func (s *RiftMemorySpan) back() {
+	if s.mapping != nil {
+		return // mapped spans keep their file's size (see Resize)
+	}
+	if s.aligned && uint64(len(s.data)) >= s.Bytes && alignedAt(s.data, s.Alignment) {
+		return
+	}
+	if s.mapTemp() {
+		return
+	}
+	buf := alignedBuffer(s.Bytes, s.Alignment)
+	copy(buf, s.data)
+	s.data = buf
+	s.aligned = true
+}
+
+// alignedBuffer allocates size zeroed bytes starting at a multiple of
+// align, a power of two. Go does not move heap objects, so the start stays
+// aligned for the buffer's life.
+func alignedBuffer(size uint64, align uint32) []byte {
+	raw := make([]byte, size+uint64(align)-1)
+	skip := uint64(0)
+	if rem := uint64(uintptr(unsafe.Pointer(unsafe.SliceData(raw)))) & uint64(align-1); rem != 0 {
+		skip = uint64(align) - rem
+	}
+	return raw[skip : skip+size : skip+size]
+}
+
+// alignedAt reports whether buf starts at a multiple of align; an empty
+// buffer has no start to misalign
+func alignedAt(buf []byte, align uint32) bool {
+	if len(buf) == 0 {
+		return true
+	}
+	return uintptr(unsafe.Pointer(unsafe.SliceData(buf)))&uintptr(align-1) == 0
+}
+
+// ============================================================================
+// Checked Access
+// ============================================================================
+
+// ReadBytes returns a copy of the n bytes at offset off. Unlike ReadAt it
+// reads all of them or none: a range that does not fit in the span fails
+// with E_SPAN_BOUNDS, and the span must grant AccessRead.
+func (s *RiftMemorySpan) ReadBytes(off, n uint64) ([]byte, error) {
+	spanTree.Lock()
+	defer spanTree.Unlock()
+	buf, err := s.checkedRange("read", AccessRead, off, n)
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(nil), buf...), nil
+}
+
+// WriteBytes copies p into the span at offset off. Unlike WriteAt it
+// writes all of p or nothing: a range that does not fit in the span fails
+// with E_SPAN_BOUNDS, and the span must grant AccessUpdate.
+func (s *RiftMemorySpan) WriteBytes(off uint64, p []byte) error {
+	spanTree.Lock()
+	defer spanTree.Unlock()
+	buf, err := s.checkedRange("write", AccessUpdate, off, uint64(len(p)))
+	if err != nil {
+		return err
+	}
+	copy(buf, p)
+	return nil
+}
+
+// Uint64At reads the 8 bytes at offset off as a uint64 in the span's
+// ByteOrder, with ReadBytes' checks
+func (s *RiftMemorySpan) Uint64At(off uint64) (uint64, error) {
+	spanTree.Lock()
+	defer spanTree.Unlock()
+	buf, err := s.checkedRange("read", AccessRead, off, 8)
+	if err != nil {
+		return 0, err
+	}
+	return s.ByteOrder().Uint64(buf), nil
+}
+
+// PutUint64At writes v as the 8 bytes at offset off in the span's
+// ByteOrder, with WriteBytes' checks
+func (s *RiftMemorySpan) PutUint64At(off, v uint64) error {
+	spanTree.Lock()
+	defer spanTree.Unlock()
+	buf, err := s.checkedRange("write", AccessUpdate, off, 8)
+	if err != nil {
+		return err
+	}
+	s.ByteOrder().PutUint64(buf, v)
+	return nil
+}
+
+// checkedRange returns the buffer bytes [off, off+n) of the span, backing
+// it first, or why the access is refused; spanTree held
+func (s *RiftMemorySpan) checkedRange(op string, access uint32, off, n uint64) ([]byte, error) {
+	if err := s.ioError(op, access, 0); err != nil {
+		return nil, err
+	}
+	if end := off + n; end < off || end > s.Bytes {
+		return nil, govErr(CodeSpanBounds, op, "%d bytes at offset %d exceed span of %d bytes", n, off, s.Bytes)
+	}
+	r, base := s.root()
+	if !r.ValidateAlignment() {
+		return nil, govErr(CodeBadAlignment, op, "alignment %d is not a power of 2", r.Alignment)
+	}
+	r.back()
+	start := base + off
+	return r.data[start : start+n : start+n], nil
+}
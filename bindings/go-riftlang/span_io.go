@@ -0,0 +1,287 @@
+// go/target/span_io.go
+// Direction-Aware Span Reads and Writes - Go Implementation
+
+package rift
+
+import (
+	"encoding/binary"
+	"io"
+)
+
+// ============================================================================
+// Span Contents
+// ============================================================================
+
+// A span's contents live in its root span's buffer, so a carved span reads
+// and writes its parent's bytes at its offset. The buffer grows as bytes
+// are written; bytes never written read as zero. Span contents are guarded
+// by spanTree along with the span tree.
+
+// ByteOrder returns the order in which the span's Writer lays out
+// multi-byte values: little-endian for a left->right span, big-endian for
+// a right->left one (see Writer)
+func (s *RiftMemorySpan) ByteOrder() binary.ByteOrder {
+	if s.Direction {
+		return binary.BigEndian
+	}
+	return binary.LittleEndian
+}
+
+// ReadAt reads span bytes at offset off in address order, whatever the
+// span's direction. It implements io.ReaderAt.
+func (s *RiftMemorySpan) ReadAt(p []byte, off int64) (int, error) {
+	spanTree.Lock()
+	defer spanTree.Unlock()
+	if err := s.ioError("read", AccessRead, off); err != nil {
+		return 0, err
+	}
+	n := s.readAt(p, uint64(off))
+	if n < len(p) {
+		return n, io.EOF
+	}
+	return n, nil
+}
+
+// WriteAt writes span bytes at offset off in address order, whatever the
+// span's direction. It implements io.WriterAt; bytes past the end of the
+// span are not written.
+func (s *RiftMemorySpan) WriteAt(p []byte, off int64) (int, error) {
+	spanTree.Lock()
+	defer spanTree.Unlock()
+	if err := s.ioError("write", AccessUpdate, off); err != nil {
+		return 0, err
+	}
+	n := s.writeAt(p, uint64(off))
+	if n < len(p) {
+		return n, govErr(CodeSpanBounds, "write", "%d bytes at offset %d exceed span of %d bytes", len(p), off, s.Bytes)
+	}
+	return n, nil
+}
+
+// ioError returns why the span refuses an access at off, or nil; spanTree
+// held
+func (s *RiftMemorySpan) ioError(op string, access uint32, off int64) error {
+	if s.released.Load() {
+		return govErr(CodeNoMemory, op, "span released")
+	}
+	if !s.Allows(access) {
+		return govErr(CodePermissionDenied, op, "span access mask 0x%x lacks 0x%x", s.Mask(), access)
+	}
+	if off < 0 {
+		return govErr(CodeSpanBounds, op, "negative offset %d", off)
+	}
+	return nil
+}
+
+// root returns the span's root and its offset within it; spanTree held
+func (s *RiftMemorySpan) root() (*RiftMemorySpan, uint64) {
+	off := uint64(0)
+	for s.parent != nil {
+		off += s.offset
+		s = s.parent
+	}
+	return s, off
+}
+
+// readAt copies span bytes from off into p, returning how many fit in the
+// span; spanTree held
+func (s *RiftMemorySpan) readAt(p []byte, off uint64) int {
+	if off >= s.Bytes {
+		return 0
+	}
+	if rest := s.Bytes - off; uint64(len(p)) > rest {
+		p = p[:rest]
+	}
+	r, base := s.root()
+	start := base + off
+	n := 0
+	if start < uint64(len(r.data)) {
+		n = copy(p, r.data[start:])
+	}
+	for i := n; i < len(p); i++ {
+		p[i] = 0
+	}
+	return len(p)
+}
+
+// writeAt copies p into the span at off, returning how many bytes fit in
+// the span; spanTree held
+func (s *RiftMemorySpan) writeAt(p []byte, off uint64) int {
+	if off >= s.Bytes {
+		return 0
+	}
+	if rest := s.Bytes - off; uint64(len(p)) > rest {
+		p = p[:rest]
+	}
+	r, base := s.root()
+	start := base + off
+	if end := start + uint64(len(p)); end > uint64(len(r.data)) {
+		if r.aligned || r.overMappedThreshold() {
+			r.back()
+		} else {
+			r.data = append(r.data, make([]byte, end-uint64(len(r.data)))...)
+		}
+	}
+	return copy(r.data[start:], p)
+}
+
+// contents returns a copy of the span's bytes written so far; spanTree
+// held
+func (s *RiftMemorySpan) contents() []byte {
+	r, base := s.root()
+	if base >= uint64(len(r.data)) {
+		return nil
+	}
+	end := base + s.Bytes
+	if end > uint64(len(r.data)) {
+		end = uint64(len(r.data))
+	}
+	return append([]byte(nil), r.data[base:end]...)
+}
+
+// ============================================================================
+// Directional Streams
+// ============================================================================
+
+// Writer returns a writer filling the span from the edge its direction
+// starts at: a left->right span from its first byte upward, a right->left
+// span from its last byte downward. The n-th byte written lands at offset
+// n or Bytes-1-n. Multi-byte values written least significant byte first
+// therefore read little-endian in address order on a left->right span and
+// big-endian on a right->left one (see ByteOrder). A Writer is not safe for
+// concurrent use.
+func (s *RiftMemorySpan) Writer() *SpanWriter {
+	return &SpanWriter{span: s}
+}
+
+// Reader returns a reader walking the span in its direction, so it reads
+// back in order what Writer wrote. A Reader is not safe for concurrent use.
+func (s *RiftMemorySpan) Reader() *SpanReader {
+	return &SpanReader{span: s}
+}
+
+// streamAt copies between p and the span for stream positions
+// [pos, pos+len(p)), mapping them to offsets by direction; spanTree held
+func (s *RiftMemorySpan) streamAt(p []byte, pos uint64, write bool) int {
+	if pos >= s.Bytes {
+		return 0
+	}
+	if rest := s.Bytes - pos; uint64(len(p)) > rest {
+		p = p[:rest]
+	}
+	if !s.Direction {
+		if write {
+			return s.writeAt(p, pos)
+		}
+		return s.readAt(p, pos)
+	}
+
+	// Right->left: stream position i is offset Bytes-1-i, so the range is
+	// stored reversed
+	off := s.Bytes - pos - uint64(len(p))
+	if write {
+		buf := make([]byte, len(p))
+		for i, b := range p {
+			buf[len(p)-1-i] = b
+		}
+		return s.writeAt(buf, off)
+	}
+	n := s.readAt(p, off)
+	for i, j := 0, n-1; i < j; i, j = i+1, j-1 {
+		p[i], p[j] = p[j], p[i]
+	}
+	return n
+}
+
+// SpanWriter writes a span in its direction (see RiftMemorySpan.Writer)
+type SpanWriter struct {
+	span *RiftMemorySpan
+	pos  uint64
+}
+
+// Write implements io.Writer. Writing past the end of the span writes what
+// fits and fails with E_SPAN_BOUNDS.
+func (w *SpanWriter) Write(p []byte) (int, error) {
+	spanTree.Lock()
+	defer spanTree.Unlock()
+	if err := w.span.ioError("write", AccessUpdate, 0); err != nil {
+		return 0, err
+	}
+	n := w.span.streamAt(p, w.pos, true)
+	w.pos += uint64(n)
+	if n < len(p) {
+		return n, govErr(CodeSpanBounds, "write", "span full after %d bytes", w.pos)
+	}
+	return n, nil
+}
+
+// WriteByte implements io.ByteWriter
+func (w *SpanWriter) WriteByte(c byte) error {
+	_, err := w.Write([]byte{c})
+	return err
+}
+
+// WriteUint64 writes v least significant byte first
+func (w *SpanWriter) WriteUint64(v uint64) error {
+	var buf [8]byte
+	binary.LittleEndian.PutUint64(buf[:], v)
+	_, err := w.Write(buf[:])
+	return err
+}
+
+// Len returns the number of bytes written
+func (w *SpanWriter) Len() uint64 {
+	return w.pos
+}
+
+// SpanReader reads a span in its direction (see RiftMemorySpan.Reader)
+type SpanReader struct {
+	span *RiftMemorySpan
+	pos  uint64
+}
+
+// Read implements io.Reader, returning io.EOF at the end of the span
+func (r *SpanReader) Read(p []byte) (int, error) {
+	spanTree.Lock()
+	defer spanTree.Unlock()
+	if err := r.span.ioError("read", AccessRead, 0); err != nil {
+		return 0, err
+	}
+	if len(p) == 0 {
+		return 0, nil
+	}
+	n := r.span.streamAt(p, r.pos, false)
+	r.pos += uint64(n)
+	if n == 0 {
+		return 0, io.EOF
+	}
+	return n, nil
+}
+
+// ReadByte implements io.ByteReader
+func (r *SpanReader) ReadByte() (byte, error) {
+	var buf [1]byte
+	if _, err := io.ReadFull(r, buf[:]); err != nil {
+		return 0, err
+	}
+	return buf[0], nil
+}
+
+// ReadUint64 reads a value written by SpanWriter.WriteUint64
+func (r *SpanReader) ReadUint64() (uint64, error) {
+	var buf [8]byte
+	if _, err := io.ReadFull(r, buf[:]); err != nil {
+		return 0, err
+	}
+	return binary.LittleEndian.Uint64(buf[:]), nil
+}
+
+// Len returns the number of bytes left to read
+func (r *SpanReader) Len() uint64 {
+	spanTree.Lock()
+	defer spanTree.Unlock()
+	if r.pos >= r.span.Bytes {
+		return 0
+	}
+	return r.span.Bytes - r.pos
+}
@@ -0,0 +1,110 @@
+package rift
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"testing"
+)
+
+// newDirectedSpan returns an empty span of n bytes; leftToRight clears
+// Direction
+func newDirectedSpan(n uint64, leftToRight bool) *RiftMemorySpan {
+	s := NewRiftMemorySpan(SpanFixed, n)
+	s.Direction = !leftToRight
+	return s
+}
+
+// TestSpanStreamDirection writes the same bytes to spans of either
+// direction: they land at opposite edges in address order, and each
+// Reader reads them back as written
+func TestSpanStreamDirection(t *testing.T) {
+	tests := []struct {
+		name        string
+		leftToRight bool
+		want        []byte // span bytes in address order
+	}{
+		{"left to right", true, []byte{'a', 'b', 'c', 0, 0, 0, 0, 0}},
+		{"right to left", false, []byte{0, 0, 0, 0, 0, 'c', 'b', 'a'}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newDirectedSpan(8, tt.leftToRight)
+			w := s.Writer()
+			if _, err := w.Write([]byte("ab")); err != nil {
+				t.Fatal(err)
+			}
+			if err := w.WriteByte('c'); err != nil {
+				t.Fatal(err)
+			}
+			if w.Len() != 3 {
+				t.Errorf("Writer.Len() = %d, want 3", w.Len())
+			}
+
+			got := make([]byte, 8)
+			if _, err := s.ReadAt(got, 0); err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(got, tt.want) {
+				t.Errorf("ReadAt = %q, want %q", got, tt.want)
+			}
+
+			r := s.Reader()
+			back := make([]byte, 3)
+			if _, err := io.ReadFull(r, back); err != nil {
+				t.Fatal(err)
+			}
+			if string(back) != "abc" {
+				t.Errorf("Reader read %q, want %q", back, "abc")
+			}
+			if r.Len() != 5 {
+				t.Errorf("Reader.Len() = %d, want 5", r.Len())
+			}
+		})
+	}
+}
+
+// TestSpanStreamByteOrder checks WriteUint64 reads back in the span's
+// ByteOrder in address order, and through ReadUint64 in stream order
+func TestSpanStreamByteOrder(t *testing.T) {
+	const v = 0x0102030405060708
+	for _, leftToRight := range []bool{true, false} {
+		s := newDirectedSpan(8, leftToRight)
+		if err := s.Writer().WriteUint64(v); err != nil {
+			t.Fatal(err)
+		}
+		raw := make([]byte, 8)
+		if _, err := s.ReadAt(raw, 0); err != nil {
+			t.Fatal(err)
+		}
+		want := binary.ByteOrder(binary.LittleEndian)
+		if !leftToRight {
+			want = binary.BigEndian
+		}
+		if s.ByteOrder() != want {
+			t.Errorf("left to right %v: ByteOrder() = %v, want %v", leftToRight, s.ByteOrder(), want)
+		}
+		if got := s.ByteOrder().Uint64(raw); got != v {
+			t.Errorf("left to right %v: ReadAt bytes % x decode to %#x, want %#x", leftToRight, raw, got, uint64(v))
+		}
+		if got, err := s.Reader().ReadUint64(); err != nil || got != v {
+			t.Errorf("left to right %v: ReadUint64 = %#x, %v; want %#x", leftToRight, got, err, uint64(v))
+		}
+	}
+}
+
+// TestSpanStreamBounds fills a span: the overflowing write keeps what fits
+// at the far edge and fails with E_SPAN_BOUNDS
+func TestSpanStreamBounds(t *testing.T) {
+	for _, leftToRight := range []bool{true, false} {
+		s := newDirectedSpan(4, leftToRight)
+		n, err := s.Writer().Write([]byte("abcdef"))
+		if n != 4 || ErrorCodeOf(err) != CodeSpanBounds {
+			t.Errorf("left to right %v: Write = %d, %v; want 4, %v", leftToRight, n, err, CodeSpanBounds)
+		}
+		back, err := io.ReadAll(s.Reader())
+		if err != nil || string(back) != "abcd" {
+			t.Errorf("left to right %v: Reader read %q, %v; want %q", leftToRight, back, err, "abcd")
+		}
+	}
+}
@@ -0,0 +1,213 @@
+// go/target/span_mmap.go
+// File-Mapped Spans - Go Implementation
+
+package rift
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sync/atomic"
+)
+
+// ============================================================================
+// Mapping Threshold
+// ============================================================================
+
+// mappedThreshold is the span size from which buffers are mapped; 0 never
+var mappedThreshold atomic.Uint64
+
+// SetMappedSpanThreshold makes root spans of at least bytes bytes keep
+// their contents in an unlinked temporary file mapped into memory rather
+// than on the Go heap, so very large spans neither grow the heap nor cost
+// the garbage collector. The mapping is made when the span's buffer is
+// first allocated (see Buffer); 0, the default, keeps every span on the
+// heap. Mapping needs a Unix system; elsewhere, or if a mapping fails,
+// spans stay on the heap. Use OpenMappedSpan for contents that must
+// survive a restart.
+func SetMappedSpanThreshold(bytes uint64) {
+	mappedThreshold.Store(bytes)
+}
+
+// MappedSpanThreshold returns the size set by SetMappedSpanThreshold
+func MappedSpanThreshold() uint64 {
+	return mappedThreshold.Load()
+}
+
+// ============================================================================
+// Persistent Mapped Spans
+// ============================================================================
+
+// spanMapping is the file behind a mapped root span's data
+type spanMapping struct {
+	file   *os.File
+	mapped bool // data is a mapping of file, not a heap copy of it
+}
+
+// OpenMappedSpan returns a root span of spanType whose contents are the
+// file at path, created if missing, so they persist across restarts. The
+// file is extended with zeros to bytes bytes if shorter; bytes 0 takes the
+// file's size. On Unix systems the file is mapped into memory and the
+// span's contents never live on the Go heap; elsewhere they are read into
+// a heap buffer and written back by Sync and Close.
+//
+// A mapped span cannot grow past its file, and a span's alignment must
+// not exceed the page size for its Buffer to be aligned. Call Close to
+// flush and unmap the span keeping the file, or Release to end its
+// lifetime, which zeroes the file as it does any span's contents.
+func OpenMappedSpan(path string, spanType int, bytes uint64) (*RiftMemorySpan, error) {
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0o600)
+	if err != nil {
+		return nil, err
+	}
+	s, err := openMappedSpan(f, spanType, bytes)
+	if err != nil {
+		f.Close()
+		return nil, fmt.Errorf("mapped span %s: %w", path, err)
+	}
+	return s, nil
+}
+
+func openMappedSpan(f *os.File, spanType int, bytes uint64) (*RiftMemorySpan, error) {
+	info, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+	if bytes == 0 {
+		bytes = uint64(info.Size())
+	}
+	if uint64(info.Size()) < bytes {
+		if err := f.Truncate(int64(bytes)); err != nil {
+			return nil, err
+		}
+	}
+
+	s := NewRiftMemorySpan(spanType, bytes)
+	spanTree.Lock()
+	defer spanTree.Unlock()
+	if err := s.attach(f); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Mapped reports whether the span's root keeps its contents in a file
+// (see OpenMappedSpan and SetMappedSpanThreshold)
+func (s *RiftMemorySpan) Mapped() bool {
+	spanTree.Lock()
+	defer spanTree.Unlock()
+	r, _ := s.root()
+	return r.mapping != nil
+}
+
+// Sync flushes the contents of the span's root to its file, for a span
+// that has one; on others it does nothing
+func (s *RiftMemorySpan) Sync() error {
+	spanTree.Lock()
+	defer spanTree.Unlock()
+	r, _ := s.root()
+	if r.mapping == nil {
+		return nil
+	}
+	return r.mapping.sync(r.data)
+}
+
+// Close flushes a mapped root span to its file, unmaps it and closes the
+// file, leaving its contents for the next OpenMappedSpan; the span and
+// those carved from it are released without being zeroed. For any other
+// span Close is Release.
+func (s *RiftMemorySpan) Close() error {
+	spanTree.Lock()
+	if s.parent != nil || s.mapping == nil {
+		spanTree.Unlock()
+		s.Release()
+		return nil
+	}
+	defer spanTree.Unlock()
+
+	err := s.mapping.sync(s.data)
+	if cerr := s.mapping.close(s.data); err == nil {
+		err = cerr
+	}
+	s.mapping = nil
+	s.data = nil
+	s.release()
+	return err
+}
+
+// ============================================================================
+// Mapping
+// ============================================================================
+
+// attach makes f, at least Bytes long, the root span's storage, keeping
+// the contents already written over the file's; spanTree held
+func (s *RiftMemorySpan) attach(f *os.File) error {
+	var data []byte
+	mapped := mmapSupported && s.Bytes > 0
+	if mapped {
+		var err error
+		if data, err = mapFile(f, int(s.Bytes)); err != nil {
+			return err
+		}
+	} else {
+		data = alignedBuffer(s.Bytes, s.Alignment)
+		if _, err := f.ReadAt(data, 0); err != nil && err != io.EOF {
+			return err
+		}
+	}
+	if len(s.data) > 0 {
+		copy(data, s.data)
+	}
+	s.data = data
+	s.aligned = true
+	s.mapping = &spanMapping{file: f, mapped: mapped}
+	return nil
+}
+
+// overMappedThreshold reports whether SetMappedSpanThreshold asks for the
+// span to be mapped
+func (s *RiftMemorySpan) overMappedThreshold() bool {
+	t := mappedThreshold.Load()
+	return t > 0 && s.Bytes >= t
+}
+
+// mapTemp backs a root span over the mapping threshold with a temporary
+// file, reporting whether it could; spanTree held
+func (s *RiftMemorySpan) mapTemp() bool {
+	if !s.overMappedThreshold() || !mmapSupported || s.Alignment > uint32(os.Getpagesize()) {
+		return false
+	}
+	f, err := os.CreateTemp("", "rift-span-*")
+	if err != nil {
+		return false
+	}
+	// The mapping outlives the file's name, so nothing is left behind
+	defer os.Remove(f.Name())
+	if err := f.Truncate(int64(s.Bytes)); err != nil || s.attach(f) != nil {
+		f.Close()
+		return false
+	}
+	return true
+}
+
+// sync writes data back to the file
+func (m *spanMapping) sync(data []byte) error {
+	if !m.mapped {
+		if _, err := m.file.WriteAt(data, 0); err != nil {
+			return err
+		}
+	}
+	return m.file.Sync()
+}
+
+// close unmaps data and closes the file
+func (m *spanMapping) close(data []byte) error {
+	var err error
+	if m.mapped {
+		err = unmapFile(data)
+	}
+	if cerr := m.file.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
@@ -0,0 +1,98 @@
+package rift
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestMappedSpanReopen writes a mapped span, directly and through a carved
+// span, closes it and opens the file again: the contents persist, and
+// Release zeroes them
+func TestMappedSpanReopen(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "span.map")
+	s, err := OpenMappedSpan(path, SpanFixed, 8192)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !s.Mapped() {
+		t.Fatal("span not mapped")
+	}
+	if _, err := s.WriteAt([]byte("head"), 0); err != nil {
+		t.Fatal(err)
+	}
+	sub, err := s.Carve(4096, 64)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := sub.WriteAt([]byte("carved"), 8); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if !s.Released() || !sub.Released() {
+		t.Error("closed span or its carved span not released")
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(data) != 8192 || !bytes.HasPrefix(data, []byte("head")) || !bytes.Equal(data[4104:4110], []byte("carved")) {
+		t.Fatalf("file of %d bytes does not hold what was written", len(data))
+	}
+
+	// Bytes 0 takes the file's size
+	s, err = OpenMappedSpan(path, SpanFixed, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.Bytes != 8192 {
+		t.Fatalf("reopened span of %d bytes, want 8192", s.Bytes)
+	}
+	got := make([]byte, 6)
+	if _, err := s.ReadAt(got[:4], 0); err != nil || string(got[:4]) != "head" {
+		t.Errorf("reopened span reads %q, %v at 0; want %q", got[:4], err, "head")
+	}
+	if _, err := s.ReadAt(got, 4104); err != nil || string(got) != "carved" {
+		t.Errorf("reopened span reads %q, %v at 4104; want %q", got, err, "carved")
+	}
+	s.Release()
+
+	data, err = os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(data, make([]byte, 8192)) {
+		t.Error("Release left the file's contents")
+	}
+}
+
+// TestMappedSpanExtend reopens a file asking for more bytes than it holds:
+// the file grows with zeros and keeps its contents
+func TestMappedSpanExtend(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "span.map")
+	if err := os.WriteFile(path, []byte("abc"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	s, err := OpenMappedSpan(path, SpanFixed, 64)
+	if err != nil {
+		t.Fatal(err)
+	}
+	buf := s.Buffer()
+	if len(buf) != 64 || string(buf[:3]) != "abc" || !bytes.Equal(buf[3:], make([]byte, 61)) {
+		t.Errorf("extended span holds %q", buf)
+	}
+	if err := s.Close(); err != nil {
+		t.Fatal(err)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Size() != 64 {
+		t.Fatalf("file of %d bytes, want 64", info.Size())
+	}
+}
@@ -0,0 +1,232 @@
+// go/target/substitution.go
+// Precompiled Output Substitution Plans - Go Implementation
+
+package rift
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// ============================================================================
+// Substitution Modes
+// ============================================================================
+
+// Substitution selects which placeholders of a pair's right pattern are
+// filled from the left pattern's capture groups
+type Substitution int
+
+const (
+	SubstituteNone    Substitution = iota // emit the right pattern verbatim
+	SubstituteIndexed                     // expand $N only
+	SubstituteNamed                       // expand {name} only
+	SubstituteBoth                        // expand $N and {name}
+)
+
+// String returns the mode name
+func (s Substitution) String() string {
+	switch s {
+	case SubstituteNone:
+		return "none"
+	case SubstituteIndexed:
+		return "indexed"
+	case SubstituteNamed:
+		return "named"
+	case SubstituteBoth:
+		return "both"
+	}
+	return fmt.Sprintf("Substitution(%d)", int(s))
+}
+
+// SubstitutionNamed returns the mode with the given name: "none",
+// "indexed", "named" or "both"
+func SubstitutionNamed(name string) (Substitution, error) {
+	for s := SubstituteNone; s <= SubstituteBoth; s++ {
+		if s.String() == name {
+			return s, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown substitution mode %q", name)
+}
+
+// literalSubstitution maps the rightIsLiteral flag of AddPair and
+// UpdatePair to a mode: literal rights expand nothing, others everything
+func literalSubstitution(rightIsLiteral bool) Substitution {
+	if rightIsLiteral {
+		return SubstituteNone
+	}
+	return SubstituteBoth
+}
+
+// ============================================================================
+// SubstitutionPlan
+// ============================================================================
+
+// PlanSegment is one piece of a substitution plan: literal text, or the
+// capture group whose text is copied into the output
+type PlanSegment struct {
+	Literal string
+	Group   int    // capture group index; -1 for literal text
+	Name    string // group name for {name} placeholders
+}
+
+// SubstitutionPlan is a right-hand template split at its $N and {name}
+// placeholders, built once by AddPair. Placeholders that name no group of
+// the left pattern, or that the pair's Substitution mode leaves out, stay
+// literal.
+type SubstitutionPlan struct {
+	Template string
+	Segments []PlanSegment
+}
+
+// compilePlan splits template at the placeholders mode expands that refer
+// to groups of left. $N takes the longest run of digits naming an existing
+// group, so with fewer than ten groups "$10" is group 1 followed by "0".
+func compilePlan(template string, left *regexp.Regexp, mode Substitution) *SubstitutionPlan {
+	indexed := mode == SubstituteIndexed || mode == SubstituteBoth
+	named := mode == SubstituteNamed || mode == SubstituteBoth
+	plan := &SubstitutionPlan{Template: template}
+	groups := left.NumSubexp()
+	var lit strings.Builder
+
+	flush := func() {
+		if lit.Len() > 0 {
+			plan.Segments = append(plan.Segments, PlanSegment{Literal: lit.String(), Group: -1})
+			lit.Reset()
+		}
+	}
+
+	for i := 0; i < len(template); {
+		switch template[i] {
+		case '$':
+			if !indexed {
+				break
+			}
+			end := i + 1
+			for end < len(template) && template[end] >= '0' && template[end] <= '9' {
+				end++
+			}
+			for ; end > i+1; end-- {
+				n, err := strconv.Atoi(template[i+1 : end])
+				if err == nil && n >= 1 && n <= groups {
+					flush()
+					plan.Segments = append(plan.Segments, PlanSegment{Group: n})
+					break
+				}
+			}
+			if end > i+1 {
+				i = end
+				continue
+			}
+
+		case '{':
+			if !named {
+				break
+			}
+			if end := strings.IndexByte(template[i:], '}'); end > 1 {
+				name := template[i+1 : i+end]
+				if n := left.SubexpIndex(name); n > 0 {
+					flush()
+					plan.Segments = append(plan.Segments, PlanSegment{Group: n, Name: name})
+					i += end + 1
+					continue
+				}
+			}
+		}
+		lit.WriteByte(template[i])
+		i++
+	}
+	flush()
+	return plan
+}
+
+// Expand builds the output for a match's submatches (as returned by
+// FindStringSubmatch)
+func (p *SubstitutionPlan) Expand(submatches []string) string {
+	var out strings.Builder
+	for _, seg := range p.Segments {
+		if seg.Group < 0 {
+			out.WriteString(seg.Literal)
+		} else if seg.Group < len(submatches) {
+			out.WriteString(submatches[seg.Group])
+		}
+	}
+	return out.String()
+}
+
+// String renders the plan for debugging, e.g. `"riftVar" + $2 + "(" + {name}`
+func (p *SubstitutionPlan) String() string {
+	parts := make([]string, len(p.Segments))
+	for i, seg := range p.Segments {
+		switch {
+		case seg.Group < 0:
+			parts[i] = strconv.Quote(seg.Literal)
+		case seg.Name != "":
+			parts[i] = fmt.Sprintf("{%s}", seg.Name)
+		default:
+			parts[i] = fmt.Sprintf("$%d", seg.Group)
+		}
+	}
+	if len(parts) == 0 {
+		return `""`
+	}
+	return strings.Join(parts, " + ")
+}
+
+// SubstitutionPlan returns the plan used to build the output of the pair
+// with the given TransformID. It is nil for pairs substituting nothing.
+func (e *PatternEngine) SubstitutionPlan(transformID uint32) (*SubstitutionPlan, bool) {
+	e.lock.RLock()
+	defer e.lock.RUnlock()
+	if i, ok := e.pairPos(transformID); ok {
+		return e.pairs[i].Plan, true
+	}
+	return nil, false
+}
+
+// AddPairSubstitution is AddPairID with an explicit substitution mode in
+// place of rightIsLiteral
+func (e *PatternEngine) AddPairSubstitution(leftPattern, rightPattern string, priority uint32, mode Substitution) (uint32, error) {
+	return e.addGroupPair("", leftPattern, rightPattern, priority, mode)
+}
+
+// SetPairSubstitution changes which placeholders the right pattern of the
+// pair with the given TransformID expands, rebuilding its plan. UpdatePair
+// sets the mode again from its rightIsLiteral flag.
+func (e *PatternEngine) SetPairSubstitution(id uint32, mode Substitution) error {
+	if mode < SubstituteNone || mode > SubstituteBoth {
+		return fmt.Errorf("set pair substitution: unknown mode %v", mode)
+	}
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	i, ok := e.pairPos(id)
+	if !ok {
+		return govErr(CodePairNotFound, "set pair substitution", "no pair %d", id)
+	}
+	old := e.pairs[i]
+	if old.Substitution == mode {
+		return nil
+	}
+	pair := *old
+	pair.setSubstitution(mode)
+	e.index.replace(old, &pair)
+	e.pairs[i] = &pair
+	e.version++
+	return nil
+}
+
+// setSubstitution sets the pair's mode and rebuilds its plan
+func (pair *BipartitePair) setSubstitution(mode Substitution) {
+	right := *pair.Right
+	right.IsLiteral = mode == SubstituteNone
+	pair.Right = &right
+	pair.Substitution = mode
+	pair.Plan = nil
+	if mode != SubstituteNone {
+		pair.Plan = compilePlan(right.PatternStr, pair.Left.CompiledRegex, mode)
+	}
+	pair.NeedsCaptures = pair.capturesNeeded()
+}
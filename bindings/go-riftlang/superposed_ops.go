@@ -0,0 +1,284 @@
+// go/target/superposed_ops.go
+// Superposition Arithmetic - Go Implementation
+
+package rift
+
+import (
+	"math"
+	"math/cmplx"
+	"reflect"
+	"sort"
+)
+
+// ============================================================================
+// Operations
+// ============================================================================
+
+// MapSuperposed applies fn to the value of every state of t and returns a
+// new superposed token over the results, each with its state's amplitude.
+// A classical t counts as a single state. States are passed to fn as
+// Superpose takes them: int64, float64 or string, or PtrVal. States
+// mapping to equal values merge (see CombineSuperposed). The operand must
+// grant AccessRead and is read-locked while fn runs; it is left as it is,
+// and the result is not entangled with it, so measuring one does not
+// collapse the other.
+func MapSuperposed(t *RiftToken, fn func(v interface{}) (interface{}, error)) (*RiftToken, error) {
+	if t == nil {
+		return nil, govErr(CodeNilToken, "map", "nil token")
+	}
+	if err := t.checkAccess("map", AccessRead); err != nil {
+		return nil, err
+	}
+	if !t.RLock() {
+		return nil, t.located(govErr(CodeDeadlock, "map", "lock would complete a wait cycle"))
+	}
+	defer t.RUnlock()
+	var outs []outcome
+	for _, b := range branches(t) {
+		v, err := fn(b.value)
+		if err != nil {
+			return nil, err
+		}
+		outs = append(outs, outcome{v, b.amp})
+	}
+	return superposeOutcomes("map", outs)
+}
+
+// CombineSuperposed applies fn to every pair of states of a and b and
+// returns a new superposed token over the results. Independent operands
+// pair every state of a with every state of b, with the product of their
+// amplitudes. Correlated operands pair only the states that can occur
+// together: a token combined with itself pairs each state with itself, and
+// qubits of one entangled register (BellPair, GHZ) pair as their joint
+// state allows. Pairs giving equal values merge into one state whose
+// probability is the sum of theirs; the merged state keeps a phase only if
+// all pairs had it. Both operands must grant AccessRead and are read-locked,
+// in the order Equal takes them, while fn runs; they are left as they are,
+// and the result is not entangled with them.
+func CombineSuperposed(a, b *RiftToken, fn func(x, y interface{}) (interface{}, error)) (*RiftToken, error) {
+	if a == nil || b == nil {
+		return nil, govErr(CodeNilToken, "combine", "nil token")
+	}
+	if err := a.checkAccess("combine", AccessRead); err != nil {
+		return nil, err
+	}
+	if err := b.checkAccess("combine", AccessRead); err != nil {
+		return nil, err
+	}
+	unlock, err := rlockPair("combine", a, b)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+	var outs []outcome
+	for _, p := range statePairs(a, b) {
+		v, err := fn(p.x, p.y)
+		if err != nil {
+			return nil, err
+		}
+		outs = append(outs, outcome{v, p.amp})
+	}
+	return superposeOutcomes("combine", outs)
+}
+
+// AddSuperposed adds the states of a and b (see CombineSuperposed).
+// Integers add to int64, other numbers to float64, and strings
+// concatenate.
+func AddSuperposed(a, b *RiftToken) (*RiftToken, error) {
+	return CombineSuperposed(a, b, func(x, y interface{}) (interface{}, error) {
+		if xs, ok := x.(string); ok {
+			if ys, ok := y.(string); ok {
+				return xs + ys, nil
+			}
+		}
+		return arith("add", x, y, func(x, y int64) int64 { return x + y }, func(x, y float64) float64 { return x + y })
+	})
+}
+
+// SubSuperposed subtracts the states of b from those of a (see
+// CombineSuperposed)
+func SubSuperposed(a, b *RiftToken) (*RiftToken, error) {
+	return CombineSuperposed(a, b, func(x, y interface{}) (interface{}, error) {
+		return arith("sub", x, y, func(x, y int64) int64 { return x - y }, func(x, y float64) float64 { return x - y })
+	})
+}
+
+// MulSuperposed multiplies the states of a and b (see CombineSuperposed)
+func MulSuperposed(a, b *RiftToken) (*RiftToken, error) {
+	return CombineSuperposed(a, b, func(x, y interface{}) (interface{}, error) {
+		return arith("mul", x, y, func(x, y int64) int64 { return x * y }, func(x, y float64) float64 { return x * y })
+	})
+}
+
+// arith applies an integer or float operation to two numeric state values
+func arith(op string, x, y interface{}, ints func(x, y int64) int64, floats func(x, y float64) float64) (interface{}, error) {
+	xv, yv := reflect.ValueOf(x), reflect.ValueOf(y)
+	if !isNumericValue(xv) || !isNumericValue(yv) {
+		return nil, govErr(CodeConversion, op, "cannot %s %T and %T", op, x, y)
+	}
+	if xv.CanFloat() || yv.CanFloat() {
+		return floats(toFloat(xv), toFloat(yv)), nil
+	}
+	return ints(toInt(xv), toInt(yv)), nil
+}
+
+func isNumericValue(v reflect.Value) bool {
+	return v.IsValid() && isNumeric(v.Kind())
+}
+
+func toFloat(v reflect.Value) float64 {
+	switch {
+	case v.CanFloat():
+		return v.Float()
+	case v.CanUint():
+		return float64(v.Uint())
+	}
+	return float64(v.Int())
+}
+
+func toInt(v reflect.Value) int64 {
+	if v.CanUint() {
+		return int64(v.Uint())
+	}
+	return v.Int()
+}
+
+// ============================================================================
+// State Enumeration
+// ============================================================================
+
+// branch is one state of an operand
+type branch struct {
+	value interface{}
+	amp   complex128
+}
+
+// statePair is a pair of operand states that can occur together
+type statePair struct {
+	x, y interface{}
+	amp  complex128
+}
+
+// outcome is one result of an operation before equal results merge
+type outcome struct {
+	value interface{}
+	amp   complex128
+}
+
+// branches returns t's states with their amplitudes; a classical token is
+// one state
+func branches(t *RiftToken) []branch {
+	if !t.HasBit(TokenSuperposed) || len(t.SuperposedStates) == 0 {
+		return []branch{{stateValue(t), 1}}
+	}
+	amps := t.stateVector()
+	out := make([]branch, len(amps))
+	for i, s := range t.SuperposedStates {
+		out[i] = branch{stateValue(s), amps[i]}
+	}
+	return out
+}
+
+// statePairs returns the state pairs of a and b that can occur together
+func statePairs(a, b *RiftToken) []statePair {
+	if a == b {
+		var out []statePair
+		for _, br := range branches(a) {
+			out = append(out, statePair{br.value, br.value, br.amp})
+		}
+		return out
+	}
+	if reg := a.joint; reg != nil && reg == b.joint && a.HasBit(TokenSuperposed) && b.HasBit(TokenSuperposed) {
+		reg.lock.Lock()
+		keys := make([]uint64, 0, len(reg.amps))
+		for k := range reg.amps {
+			keys = append(keys, k)
+		}
+		sort.Slice(keys, func(i, j int) bool { return keys[i] < keys[j] })
+		out := make([]statePair, len(keys))
+		for i, k := range keys {
+			x := a.SuperposedStates[k>>a.jointQubit&1]
+			y := b.SuperposedStates[k>>b.jointQubit&1]
+			out[i] = statePair{stateValue(x), stateValue(y), reg.amps[k]}
+		}
+		reg.lock.Unlock()
+		return out
+	}
+	var out []statePair
+	for _, x := range branches(a) {
+		for _, y := range branches(b) {
+			out = append(out, statePair{x.value, y.value, x.amp * y.amp})
+		}
+	}
+	return out
+}
+
+// stateValue returns a state token's value as Superpose takes it
+func stateValue(t *RiftToken) interface{} {
+	switch {
+	case t.Value.PtrVal != nil:
+		return t.Value.PtrVal
+	case t.Type == TokenGoFloat:
+		return t.Value.FloatVal
+	case t.Type == TokenGoString:
+		return t.Value.StringVal
+	}
+	return t.Value.IntVal
+}
+
+// ============================================================================
+// Result
+// ============================================================================
+
+// superposeOutcomes merges equal outcomes and returns a superposed token
+// over them
+func superposeOutcomes(op string, outs []outcome) (*RiftToken, error) {
+	type merged struct {
+		value  interface{}
+		prob   float64
+		amp    complex128 // of the first outcome
+		phased bool       // every outcome had amp's phase
+	}
+	var results []*merged
+	index := make(map[interface{}]*merged)
+	for _, o := range outs {
+		p := real(o.amp)*real(o.amp) + imag(o.amp)*imag(o.amp)
+		keyable := o.value == nil || reflect.ValueOf(o.value).Comparable()
+		if keyable {
+			if m := index[o.value]; m != nil {
+				m.prob += p
+				m.phased = m.phased && math.Abs(cmplx.Phase(o.amp)-cmplx.Phase(m.amp)) < gateEpsilon
+				continue
+			}
+		}
+		m := &merged{value: o.value, prob: p, amp: o.amp, phased: true}
+		results = append(results, m)
+		if keyable {
+			index[o.value] = m
+		}
+	}
+
+	states := make([]*RiftToken, len(results))
+	amps := make([]complex128, len(results))
+	for i, m := range results {
+		states[i] = newStateToken(m.value)
+		phase := 0.0
+		if m.phased {
+			phase = cmplx.Phase(m.amp)
+		}
+		amps[i] = cmplx.Rect(math.Sqrt(m.prob), phase)
+	}
+
+	memory := NewRiftMemorySpan(SpanSuperposed, 64)
+	memory.Alignment = QuantumAlignment
+	token := NewRiftToken(TokenQGoInt, memory)
+	token.SuperposedStates = states
+	token.SuperpositionCount = uint32(len(states))
+	if err := token.setStateVector(op, amps); err != nil {
+		return nil, err
+	}
+	token.SetBit(TokenSuperposed)
+	auditEmit(AuditSuperpose, token, op)
+	token.decohere()
+	return token, nil
+}
@@ -0,0 +1,72 @@
+package rift
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestSuperposedOpsNil(t *testing.T) {
+	tok := Superpose(1, 2)
+	identity := func(v interface{}) (interface{}, error) { return v, nil }
+	if _, err := MapSuperposed(nil, identity); ErrorCodeOf(err) != CodeNilToken {
+		t.Errorf("MapSuperposed(nil) error %v, want %v", err, CodeNilToken)
+	}
+	for _, pair := range [][2]*RiftToken{{nil, tok}, {tok, nil}, {nil, nil}} {
+		if _, err := AddSuperposed(pair[0], pair[1]); ErrorCodeOf(err) != CodeNilToken {
+			t.Errorf("AddSuperposed(%p, %p) error %v, want %v", pair[0], pair[1], err, CodeNilToken)
+		}
+	}
+}
+
+// TestCombineSuperposedConcurrent combines two tokens in both argument
+// orders while another goroutine write-locks them: the read locks are taken
+// in one order, so nothing deadlocks
+func TestCombineSuperposedConcurrent(t *testing.T) {
+	SetDeadlockDetection(DeadlockError)
+	t.Cleanup(func() { SetDeadlockDetection(DeadlockOff) })
+
+	a, b := Superpose(1, 2), Superpose(10, 20)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		var wg sync.WaitGroup
+		for g := range 4 {
+			wg.Go(func() {
+				for range 100 {
+					x, y := a, b
+					if g%2 == 1 {
+						x, y = b, a
+					}
+					sum, err := AddSuperposed(x, y)
+					if err != nil {
+						t.Error(err)
+						return
+					}
+					if len(sum.SuperposedStates) != 4 {
+						t.Errorf("%d states, want 4", len(sum.SuperposedStates))
+						return
+					}
+				}
+			})
+		}
+		wg.Go(func() {
+			for range 100 {
+				for _, tok := range []*RiftToken{a, b} {
+					if !tok.Lock() {
+						t.Error("Lock refused")
+						return
+					}
+					tok.Unlock()
+				}
+			}
+		})
+		wg.Wait()
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(30 * time.Second):
+		t.Fatal("combining in opposite orders did not complete")
+	}
+}
@@ -0,0 +1,273 @@
+// go/target/supervisor.go
+// Governed Goroutine Supervisor - Go Implementation
+
+package rift
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+// RestartStrategy selects what a Supervisor does when a child panics
+type RestartStrategy int
+
+const (
+	RestartNever     RestartStrategy = iota // leave the child failed
+	RestartOneForOne                        // restart only the panicking child, at once
+	RestartBackoff                          // restart the child after an exponential backoff
+)
+
+// Default backoff bounds for RestartBackoff
+const (
+	DefaultBackoffMin = 100 * time.Millisecond
+	DefaultBackoffMax = 30 * time.Second
+)
+
+// SupervisorOptions configures a Supervisor
+type SupervisorOptions struct {
+	Strategy    RestartStrategy
+	MaxRestarts int           // restarts per child before it is failed; 0 = unlimited
+	BackoffMin  time.Duration // first RestartBackoff delay, doubled per restart
+	BackoffMax  time.Duration // RestartBackoff delay cap
+
+	// OnPanic is called with each recovered panic. The default prints it
+	// like Go does.
+	OnPanic func(name string, r interface{})
+}
+
+// ChildState is the lifecycle state of a supervised goroutine
+type ChildState int
+
+const (
+	ChildRunning    ChildState = iota
+	ChildRestarting            // panicked, waiting to restart
+	ChildStopped               // returned normally or stopped by Shutdown
+	ChildFailed                // panicked and will not restart
+)
+
+// String returns the state name
+func (s ChildState) String() string {
+	switch s {
+	case ChildRunning:
+		return "running"
+	case ChildRestarting:
+		return "restarting"
+	case ChildStopped:
+		return "stopped"
+	case ChildFailed:
+		return "failed"
+	}
+	return fmt.Sprintf("ChildState(%d)", int(s))
+}
+
+// ChildStatus is a snapshot of one supervised goroutine
+type ChildStatus struct {
+	Name      string
+	State     ChildState
+	Restarts  int
+	LastPanic interface{}
+	Token     *RiftToken
+}
+
+// ============================================================================
+// Supervisor
+// ============================================================================
+
+// child is a supervised goroutine. Its token is governed while it runs.
+type child struct {
+	name      string
+	fn        func(context.Context)
+	token     *RiftToken
+	state     ChildState
+	restarts  int
+	lastPanic interface{}
+}
+
+// Supervisor runs governed goroutines, restarting them after panics
+// according to its strategy
+type Supervisor struct {
+	opts   SupervisorOptions
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+	lock   sync.Mutex
+	nextID int
+	kids   map[int]*child
+}
+
+// NewSupervisor creates a supervisor
+func NewSupervisor(opts SupervisorOptions) *Supervisor {
+	if opts.BackoffMin <= 0 {
+		opts.BackoffMin = DefaultBackoffMin
+	}
+	if opts.BackoffMax < opts.BackoffMin {
+		opts.BackoffMax = max(DefaultBackoffMax, opts.BackoffMin)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Supervisor{opts: opts, ctx: ctx, cancel: cancel, kids: make(map[int]*child)}
+}
+
+// Go starts fn as a supervised goroutine and returns its token. fn's
+// context is cancelled by Shutdown; fn should return when it is done.
+// After Shutdown, Go starts nothing and returns nil.
+func (s *Supervisor) Go(name string, fn func(ctx context.Context)) *RiftToken {
+	token := NewRiftToken(TokenGoChan, NewRiftMemorySpan(SpanFixed, 4096))
+	token.Value.StringVal = name
+	token.SetBit(TokenInitialized)
+	token.Validate()
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if s.ctx.Err() != nil {
+		return nil
+	}
+	s.nextID++
+	c := &child{name: name, fn: fn, token: token}
+	s.kids[s.nextID] = c
+	s.wg.Add(1)
+	go s.run(c)
+	return token
+}
+
+// run executes a child until it returns, fails or is shut down
+func (s *Supervisor) run(c *child) {
+	defer s.wg.Done()
+	for {
+		r, panicked := s.invoke(c)
+		if !panicked {
+			s.finish(c, ChildStopped)
+			return
+		}
+
+		s.lock.Lock()
+		c.lastPanic = r
+		giveUp := s.opts.Strategy == RestartNever ||
+			s.opts.MaxRestarts > 0 && c.restarts >= s.opts.MaxRestarts
+		if !giveUp {
+			c.state = ChildRestarting
+		}
+		s.lock.Unlock()
+		auditEmit(AuditPanic, c.token, fmt.Sprint(r))
+		if giveUp {
+			s.finish(c, ChildFailed)
+			return
+		}
+
+		if s.opts.Strategy == RestartBackoff {
+			timer := time.NewTimer(s.backoff(c.restarts))
+			select {
+			case <-timer.C:
+			case <-s.ctx.Done():
+				timer.Stop()
+			}
+		}
+		if s.ctx.Err() != nil {
+			s.finish(c, ChildStopped)
+			return
+		}
+
+		s.lock.Lock()
+		c.restarts++
+		c.state = ChildRunning
+		s.lock.Unlock()
+		c.token.SetBit(TokenInitialized)
+		c.token.Validate()
+		auditEmit(AuditRestart, c.token, "")
+	}
+}
+
+// invoke calls the child's function, recovering a panic
+func (s *Supervisor) invoke(c *child) (r interface{}, panicked bool) {
+	defer func() {
+		if r = recover(); r != nil {
+			panicked = true
+			if s.opts.OnPanic != nil {
+				s.opts.OnPanic(c.name, r)
+			} else {
+				fmt.Printf("Rift-governed goroutine %s panicked: %v\n", c.name, r)
+			}
+		}
+	}()
+	c.fn(s.ctx)
+	return nil, false
+}
+
+// finish records a child's final state and revokes its token's governance
+func (s *Supervisor) finish(c *child, state ChildState) {
+	s.lock.Lock()
+	c.state = state
+	s.lock.Unlock()
+	c.token.ClearBit(TokenGoverned)
+}
+
+// backoff returns the RestartBackoff delay before restart number n+1
+func (s *Supervisor) backoff(n int) time.Duration {
+	d := s.opts.BackoffMin
+	for i := 0; i < n && d < s.opts.BackoffMax; i++ {
+		d *= 2
+	}
+	return min(d, s.opts.BackoffMax)
+}
+
+// ============================================================================
+// Status and Teardown
+// ============================================================================
+
+// Status returns a snapshot of every child in start order
+func (s *Supervisor) Status() []ChildStatus {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	ids := make([]int, 0, len(s.kids))
+	for id := range s.kids {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+
+	out := make([]ChildStatus, len(ids))
+	for i, id := range ids {
+		c := s.kids[id]
+		out[i] = ChildStatus{Name: c.name, State: c.state, Restarts: c.restarts, LastPanic: c.lastPanic, Token: c.token}
+	}
+	return out
+}
+
+// Alive returns how many children are running or waiting to restart
+func (s *Supervisor) Alive() int {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	n := 0
+	for _, c := range s.kids {
+		if c.state == ChildRunning || c.state == ChildRestarting {
+			n++
+		}
+	}
+	return n
+}
+
+// Wait blocks until every child has stopped or failed
+func (s *Supervisor) Wait() {
+	s.wg.Wait()
+}
+
+// Shutdown cancels the children's context, stops restarts, and waits for
+// the children to return or ctx to be done
+func (s *Supervisor) Shutdown(ctx context.Context) error {
+	s.lock.Lock()
+	s.cancel()
+	s.lock.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		s.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return contextError("shutdown", CodeCanceled, ctx.Err())
+	}
+}
@@ -0,0 +1,115 @@
+// go/target/token_version.go
+// Token Versioning and Optimistic Concurrency - Go Implementation
+
+package rift
+
+// ErrConflict matches, via errors.Is, the error CompareAndSetValue returns
+// when the token changed since the expected version
+var ErrConflict error = &GovernanceError{Code: CodeConflict, Op: "compare and set", Detail: "version changed"}
+
+// ============================================================================
+// Versions
+// ============================================================================
+
+// Version returns the token's value version, which starts at 0 and
+// increases with every change of the value: SetValue, CompareAndSetValue,
+// Delete, collapse, and a transaction commit or rollback
+func (t *RiftToken) Version() uint64 {
+	return t.version.Load()
+}
+
+// GetValueVersion is GetValue also returning the version of the value read,
+// for a later CompareAndSetValue
+func (t *RiftToken) GetValueVersion() (RiftTokenValue, uint64, error) {
+	if err := t.checkAccess("get", AccessRead); err != nil {
+		return RiftTokenValue{}, 0, err
+	}
+	t.Refresh()
+	src := t.readSource()
+	src.valueLock.Lock()
+	defer src.valueLock.Unlock()
+	if !src.HasBit(TokenInitialized) {
+		return RiftTokenValue{}, 0, govErr(CodeNotInitialized, "get", "token value not initialized")
+	}
+	return src.Value, t.version.Load(), nil
+}
+
+// CompareAndSetValue is SetValue succeeding only while the token is still
+// at version expected. Otherwise it changes nothing and returns an error
+// matching ErrConflict, and the caller may read the value again and retry.
+// With GetValueVersion this updates read-heavy governed data without
+// holding the token lock:
+//
+//	for {
+//		v, ver, err := t.GetValueVersion()
+//		...
+//		err = t.CompareAndSetValue(ver, next(v))
+//		if !errors.Is(err, rift.ErrConflict) {
+//			break
+//		}
+//	}
+func (t *RiftToken) CompareAndSetValue(expected uint64, val RiftTokenValue) error {
+	need := AccessUpdate
+	if !t.readSource().HasBit(TokenInitialized) {
+		need = AccessCreate
+	}
+	if err := t.checkAccess("set", need); err != nil {
+		return err
+	}
+	return t.writeValue(val, &expected)
+}
+
+// ============================================================================
+// Writes
+// ============================================================================
+
+// writeValue sets the value as SetValue does. When expected is not nil the
+// version is compared first, under the same valueLock as the write.
+func (t *RiftToken) writeValue(val RiftTokenValue, expected *uint64) error {
+	if err := t.constraintError("set", val); err != nil {
+		return t.violation(t.located(err))
+	}
+	sh := t.shared.Load()
+	if sh != nil {
+		if err := sharedValueError(val); err != nil {
+			return t.located(err)
+		}
+	}
+	t.valueLock.Lock()
+	if expected != nil {
+		if v := t.version.Load(); v != *expected {
+			t.valueLock.Unlock()
+			return t.located(govErr(CodeConflict, "compare and set", "version %d, expected %d", v, *expected))
+		}
+	}
+	old := t.previousValue()
+	t.Value = val
+	t.version.Add(1)
+	t.SetBit(TokenInitialized)
+	t.shadowDirty = true
+	t.resealLocked()
+	if sh != nil {
+		sh.store(t)
+	}
+	t.valueLock.Unlock()
+
+	auditEmit(AuditSetValue, t, "")
+	t.fireChange(old, val)
+	return nil
+}
+
+// storeValue replaces the value and bumps the version
+func (t *RiftToken) storeValue(val RiftTokenValue) {
+	t.valueLock.Lock()
+	t.storeLocked(val)
+	t.valueLock.Unlock()
+}
+
+// storeLocked implements storeValue; valueLock held
+func (t *RiftToken) storeLocked(val RiftTokenValue) {
+	t.Value = val
+	t.version.Add(1)
+	if sh := t.shared.Load(); sh != nil {
+		sh.store(t)
+	}
+}
@@ -0,0 +1,96 @@
+package rift
+
+import (
+	"errors"
+	"sync"
+	"testing"
+)
+
+func TestCompareAndSetValue(t *testing.T) {
+	tok := NewRiftToken(TokenGoInt, NewRiftMemorySpan(SpanFixed, 64))
+	if err := tok.CompareAndSetValue(0, RiftTokenValue{IntVal: 1}); err != nil {
+		t.Fatalf("first CompareAndSetValue: %v", err)
+	}
+	v, ver, err := tok.GetValueVersion()
+	if err != nil || v.IntVal != 1 || ver != 1 {
+		t.Fatalf("GetValueVersion = %d, %d, %v; want 1, 1", v.IntVal, ver, err)
+	}
+
+	if err := tok.CompareAndSetValue(ver, RiftTokenValue{IntVal: 2}); err != nil {
+		t.Fatalf("CompareAndSetValue at current version: %v", err)
+	}
+	err = tok.CompareAndSetValue(ver, RiftTokenValue{IntVal: 3})
+	if !errors.Is(err, ErrConflict) {
+		t.Fatalf("CompareAndSetValue at stale version %d: %v, want ErrConflict", ver, err)
+	}
+	if v, ver, _ := tok.GetValueVersion(); v.IntVal != 2 || ver != 2 {
+		t.Fatalf("after stale CompareAndSetValue = %d at %d, want 2 at 2", v.IntVal, ver)
+	}
+}
+
+// TestCompareAndSetValueShadow compare-and-sets a clean shadow, whose first
+// write reads the origin's value while another goroutine writes it; run
+// with -race
+func TestCompareAndSetValueShadow(t *testing.T) {
+	origin := NewRiftToken(TokenGoInt, NewRiftMemorySpan(SpanFixed, 64))
+	if err := origin.SetValue(RiftTokenValue{IntVal: 0}); err != nil {
+		t.Fatal(err)
+	}
+	var wg sync.WaitGroup
+	wg.Go(func() {
+		for i := range 200 {
+			origin.SetValue(RiftTokenValue{IntVal: int64(i)})
+		}
+	})
+	for range 200 {
+		shadow := origin.Shadow()
+		if err := shadow.CompareAndSetValue(shadow.Version(), RiftTokenValue{IntVal: -1}); err != nil {
+			t.Fatal(err)
+		}
+		shadow.Discard()
+	}
+	wg.Wait()
+}
+
+// TestCompareAndSetValueConcurrent increments one token from many
+// goroutines with read, compare-and-set, retry loops: no increment may be
+// lost (run with -race)
+func TestCompareAndSetValueConcurrent(t *testing.T) {
+	tok := NewRiftToken(TokenGoInt, NewRiftMemorySpan(SpanFixed, 64))
+	if err := tok.SetValue(RiftTokenValue{IntVal: 0}); err != nil {
+		t.Fatal(err)
+	}
+
+	const goroutines, n = 8, 200
+	var wg sync.WaitGroup
+	for range goroutines {
+		wg.Go(func() {
+			for range n {
+				for {
+					v, ver, err := tok.GetValueVersion()
+					if err != nil {
+						t.Error(err)
+						return
+					}
+					err = tok.CompareAndSetValue(ver, RiftTokenValue{IntVal: v.IntVal + 1})
+					if err == nil {
+						break
+					}
+					if !errors.Is(err, ErrConflict) {
+						t.Error(err)
+						return
+					}
+				}
+			}
+		})
+	}
+	wg.Wait()
+
+	v, ver, err := tok.GetValueVersion()
+	if want := int64(goroutines * n); err != nil || v.IntVal != want {
+		t.Fatalf("value = %d, %v; want %d", v.IntVal, err, want)
+	}
+	if want := uint64(goroutines*n + 1); ver != want {
+		t.Fatalf("version = %d, want %d", ver, want)
+	}
+}
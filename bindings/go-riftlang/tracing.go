@@ -0,0 +1,152 @@
+// go/target/tracing.go
+// OpenTelemetry Tracing - Go Implementation
+
+package rift
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// tracerName is the instrumentation scope reported to the TracerProvider
+const tracerName = "github.com/obinexus/riftlang/bindings/go-riftlang"
+
+// Token span event names
+const (
+	traceEventLock     = "rift.lock"
+	traceEventValidate = "rift.validate"
+	traceEventCollapse = "rift.collapse"
+)
+
+// activeTracer holds the tracer installed by EnableTracing
+var activeTracer atomic.Pointer[tracerHolder]
+
+// tracerHolder boxes a trace.Tracer for atomic.Pointer
+type tracerHolder struct {
+	tracer trace.Tracer
+}
+
+// boundSpan boxes the span a token's operations are recorded on
+type boundSpan struct {
+	span trace.Span
+}
+
+// EnableTracing turns on OpenTelemetry instrumentation using tp; nil turns
+// it off. Every Match then produces a "rift.match" span. Token Lock,
+// Validate and Collapse record events on the span of the context passed to
+// LockContext (or WithTokenContext) while that context holds the lock.
+func EnableTracing(tp trace.TracerProvider) {
+	if tp == nil {
+		activeTracer.Store(nil)
+		return
+	}
+	activeTracer.Store(&tracerHolder{tracer: tp.Tracer(tracerName)})
+}
+
+// TracingEnabled reports whether EnableTracing installed a provider
+func TracingEnabled() bool {
+	return activeTracer.Load() != nil
+}
+
+// ============================================================================
+// Match Spans
+// ============================================================================
+
+// startMatchSpan starts a match span under ctx, or returns nil when
+// tracing is off
+func (e *PatternEngine) startMatchSpan(ctx context.Context) trace.Span {
+	h := activeTracer.Load()
+	if h == nil {
+		return nil
+	}
+	_, span := h.tracer.Start(ctx, "rift.match", trace.WithAttributes(
+		attribute.String("rift.engine.mode", e.Mode().String()),
+	))
+	return span
+}
+
+// endMatchSpan records a match outcome on span and ends it
+func endMatchSpan(span trace.Span, result *MatchResult, err error, elapsed time.Duration) {
+	if span == nil {
+		return
+	}
+	span.SetAttributes(attribute.Float64("rift.match.latency_ms", float64(elapsed.Nanoseconds())/1e6))
+	switch {
+	case err != nil:
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	case result.Matched:
+		span.SetAttributes(
+			attribute.Bool("rift.match.matched", true),
+			attribute.Int64("rift.pattern.id", int64(result.TransformID)),
+			attribute.Int64("rift.pattern.priority", int64(result.Priority)),
+		)
+	default:
+		span.SetAttributes(attribute.Bool("rift.match.matched", false))
+	}
+	span.End()
+}
+
+// ============================================================================
+// Token Events
+// ============================================================================
+
+// bindSpan makes the recording span of ctx, if any, the target of the
+// token's trace events until it is unbound
+func (t *RiftToken) bindSpan(ctx context.Context) {
+	if activeTracer.Load() == nil {
+		return
+	}
+	if span := trace.SpanFromContext(ctx); span.IsRecording() {
+		t.span.Store(&boundSpan{span: span})
+	}
+}
+
+// unbindSpan stops recording the token's events
+func (t *RiftToken) unbindSpan() {
+	if t.span.Load() != nil {
+		t.span.Store(nil)
+	}
+}
+
+// traceEvent adds an event to the token's bound span
+func (t *RiftToken) traceEvent(name string, attrs ...attribute.KeyValue) {
+	b := t.span.Load()
+	if b == nil || activeTracer.Load() == nil {
+		return
+	}
+	attrs = append(attrs, attribute.String("rift.token.type", TokenTypeName(t.Type)))
+	b.span.AddEvent(name, trace.WithAttributes(attrs...))
+}
+
+// traceValidate records a validation outcome
+func (t *RiftToken) traceValidate(err *GovernanceError) {
+	if t.span.Load() == nil {
+		return
+	}
+	if err != nil {
+		t.traceEvent(traceEventValidate, attribute.Bool("rift.valid", false), attribute.String("rift.error.code", err.Code.String()))
+		return
+	}
+	t.traceEvent(traceEventValidate, attribute.Bool("rift.valid", true))
+}
+
+// traceCollapse records a collapse to the selected state
+func (t *RiftToken) traceCollapse(selectedIndex uint32, states int, detail string) {
+	if t.span.Load() == nil {
+		return
+	}
+	attrs := []attribute.KeyValue{
+		attribute.Int64("rift.collapse.index", int64(selectedIndex)),
+		attribute.Int("rift.collapse.states", states),
+	}
+	if detail != "" {
+		attrs = append(attrs, attribute.String("rift.collapse.detail", detail))
+	}
+	t.traceEvent(traceEventCollapse, attrs...)
+}
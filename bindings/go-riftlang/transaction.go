@@ -0,0 +1,264 @@
+// go/target/transaction.go
+// Value Change Transactions - Go Implementation
+
+package rift
+
+import (
+	"fmt"
+	"sort"
+	"sync/atomic"
+)
+
+// ErrTxDone matches, via errors.Is, the error a Tx returns once its
+// transaction has committed or rolled back
+var ErrTxDone error = &GovernanceError{Code: CodeTxDone, Op: "tx", Detail: "transaction already finished"}
+
+// nextOrderID numbers tokens for the lock order of Transaction
+var nextOrderID atomic.Uint64
+
+// lockOrder returns t's position in the lock order, assigning one on first
+// use
+func (t *RiftToken) lockOrder() uint64 {
+	if id := t.orderID.Load(); id != 0 {
+		return id
+	}
+	t.orderID.CompareAndSwap(0, nextOrderID.Add(1))
+	return t.orderID.Load()
+}
+
+// ============================================================================
+// Tx
+// ============================================================================
+
+// Tx buffers value changes to the tokens joined to a transaction. Each
+// joined token gets a shadow (see Shadow) that takes its SetValue and
+// Delete calls, so reads through the Tx see the buffered values while the
+// tokens themselves are untouched until commit. A Tx belongs to the
+// goroutine running its transaction.
+type Tx struct {
+	shadows map[*RiftToken]*RiftToken // token -> its buffer
+	joined  []*RiftToken              // in join order
+	done    bool                      // committing or finished: no joins
+	retired bool
+}
+
+// Join adds t to the transaction and returns its buffer, a shadow whose
+// SetValue and Delete are applied to t on commit. Until then the buffer
+// reads through to t without its lock; GetValue takes it. Joining t again
+// returns the same buffer. Join returns nil once the transaction has
+// finished, or if deadlock detection refuses t's read lock.
+func (tx *Tx) Join(t *RiftToken) *RiftToken {
+	if tx.done || t == nil {
+		return nil
+	}
+	if shadow, ok := tx.shadows[t]; ok {
+		return shadow
+	}
+	if !t.RLock() {
+		return nil
+	}
+	shadow := t.Shadow()
+	t.RUnlock()
+	if tx.shadows == nil {
+		tx.shadows = make(map[*RiftToken]*RiftToken)
+	}
+	tx.shadows[t] = shadow
+	tx.joined = append(tx.joined, t)
+	return shadow
+}
+
+// SetValue joins t and buffers val as its new value
+func (tx *Tx) SetValue(t *RiftToken, val RiftTokenValue) error {
+	shadow := tx.Join(t)
+	if shadow == nil {
+		return tx.joinErr("set", t)
+	}
+	return shadow.SetValue(val)
+}
+
+// Delete joins t and buffers the deletion of its value
+func (tx *Tx) Delete(t *RiftToken) error {
+	shadow := tx.Join(t)
+	if shadow == nil {
+		return tx.joinErr("delete", t)
+	}
+	return shadow.Delete()
+}
+
+// GetValue joins t and returns its value as the transaction sees it: the
+// buffered value, or t's current value read under its read lock
+func (tx *Tx) GetValue(t *RiftToken) (RiftTokenValue, error) {
+	shadow := tx.Join(t)
+	if shadow == nil {
+		return RiftTokenValue{}, tx.joinErr("get", t)
+	}
+	if shadow.isDirty() {
+		return shadow.GetValue()
+	}
+	if !t.RLock() {
+		return RiftTokenValue{}, t.located(govErr(CodeDeadlock, "get", "lock would complete a wait cycle"))
+	}
+	defer t.RUnlock()
+	return shadow.GetValue()
+}
+
+// joinErr explains why Join returned nil
+func (tx *Tx) joinErr(op string, t *RiftToken) error {
+	if t == nil {
+		return govErr(CodeNilToken, op, "token is nil")
+	}
+	if !tx.done {
+		return t.located(govErr(CodeDeadlock, op, "lock would complete a wait cycle"))
+	}
+	return ErrTxDone
+}
+
+// ============================================================================
+// Transaction
+// ============================================================================
+
+// Transaction runs fn with a new Tx and then commits the changes it
+// buffered, or rolls them back if fn returns an error or panics. Commit
+// validates every changed buffer, then locks the changed tokens in a
+// deterministic order, so concurrent transactions over the same tokens
+// cannot deadlock, checks their access masks, and applies the changes with
+// all locks held: other lock holders see either none or all of them.
+// Commit fails, changing nothing, if a buffer does not validate, a mask
+// denies a change, or with CodeDeadlock if deadlock detection refuses a
+// lock. Joined tokens get an AuditTxCommit or AuditTxRollback event.
+//
+// fn must not hold the lock of a joined token when it returns, and must not
+// keep using tx after.
+func Transaction(fn func(tx *Tx) error) error {
+	tx := &Tx{}
+	defer func() {
+		if r := recover(); r != nil {
+			tx.finish(AuditTxRollback, "panic")
+			panic(r)
+		}
+	}()
+	if err := fn(tx); err != nil {
+		tx.finish(AuditTxRollback, err.Error())
+		return err
+	}
+	return tx.commit()
+}
+
+// commit applies the buffered changes atomically
+func (tx *Tx) commit() error {
+	tx.done = true
+	var changed []*RiftToken
+	for _, t := range tx.joined {
+		if tx.shadows[t].isDirty() {
+			changed = append(changed, t)
+		}
+	}
+
+	for _, t := range changed {
+		if shadow := tx.shadows[t]; shadow.HasBit(TokenInitialized) {
+			if err := shadow.ValidateErr(); err != nil {
+				tx.finish(AuditTxRollback, err.Error())
+				return err
+			}
+		}
+	}
+
+	if err := lockSorted("commit", changed); err != nil {
+		tx.finish(AuditTxRollback, err.Error())
+		return err
+	}
+	defer unlockAll(changed)
+
+	for _, t := range changed {
+		if err := t.checkAccess("commit", txAccess(t, tx.shadows[t])); err != nil {
+			tx.finish(AuditTxRollback, err.Error())
+			return err
+		}
+	}
+
+	// The masks were checked above, so a failure here means one was revoked
+	// meanwhile: restore the tokens already changed
+	type prior struct {
+		value       RiftTokenValue
+		initialized bool
+	}
+	priors := make([]prior, len(changed))
+	for i, t := range changed {
+		priors[i] = prior{t.snapshotValue(), t.HasBit(TokenInitialized)}
+		if err := tx.apply(t); err != nil {
+			for j := i - 1; j >= 0; j-- {
+				changed[j].restore(priors[j].value, priors[j].initialized)
+			}
+			tx.finish(AuditTxRollback, err.Error())
+			return err
+		}
+	}
+	tx.finish(AuditTxCommit, fmt.Sprintf("%d of %d tokens changed", len(changed), len(tx.joined)))
+	return nil
+}
+
+// apply writes t's buffered change to t; t's lock held
+func (tx *Tx) apply(t *RiftToken) error {
+	shadow := tx.shadows[t]
+	if shadow.HasBit(TokenInitialized) {
+		return t.SetValue(shadow.Value)
+	}
+	return t.Delete()
+}
+
+// finish retires the buffers and audits each joined token as kind
+func (tx *Tx) finish(kind AuditEventKind, detail string) {
+	if tx.retired {
+		return
+	}
+	tx.done, tx.retired = true, true
+	for _, t := range tx.joined {
+		tx.shadows[t].retireShadow()
+		auditEmit(kind, t, detail)
+	}
+}
+
+// txAccess returns the access bits committing shadow's change to t needs,
+// as SetValue and Delete check them
+func txAccess(t, shadow *RiftToken) uint32 {
+	switch {
+	case !shadow.HasBit(TokenInitialized):
+		return AccessDelete
+	case t.HasBit(TokenInitialized):
+		return AccessUpdate
+	}
+	return AccessCreate
+}
+
+// restore puts back a value replaced by a failed commit; t's lock held
+func (t *RiftToken) restore(old RiftTokenValue, initialized bool) {
+	cur := t.Value
+	t.storeValue(old)
+	if initialized {
+		t.SetBit(TokenInitialized)
+	} else {
+		t.ClearBit(TokenInitialized | TokenGoverned)
+	}
+	t.reseal()
+	t.fireChange(cur, old)
+}
+
+// lockSorted sorts distinct tokens into lock order and locks them,
+// releasing those already locked if deadlock detection refuses one
+func lockSorted(op string, tokens []*RiftToken) error {
+	sort.Slice(tokens, func(i, j int) bool { return tokens[i].lockOrder() < tokens[j].lockOrder() })
+	for i, t := range tokens {
+		if !t.Lock() {
+			unlockAll(tokens[:i])
+			return t.located(govErr(CodeDeadlock, op, "lock would complete a wait cycle"))
+		}
+	}
+	return nil
+}
+
+// unlockAll releases the locks of tokens in reverse order
+func unlockAll(tokens []*RiftToken) {
+	for i := len(tokens) - 1; i >= 0; i-- {
+		tokens[i].Unlock()
+	}
+}
@@ -0,0 +1,168 @@
+package rift
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+// newTxTokens returns n governed int tokens holding 0
+func newTxTokens(t *testing.T, n int) []*RiftToken {
+	t.Helper()
+	tokens := make([]*RiftToken, n)
+	for i := range tokens {
+		tokens[i] = NewRiftToken(TokenGoInt, NewRiftMemorySpan(SpanFixed, 64))
+		if err := tokens[i].SetValue(RiftTokenValue{IntVal: 0}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return tokens
+}
+
+// wantValues fails unless tokens hold want, in order
+func wantValues(t *testing.T, tokens []*RiftToken, want ...int64) {
+	t.Helper()
+	for i, tok := range tokens {
+		if got, err := tok.GetValue(); err != nil || got.IntVal != want[i] {
+			t.Errorf("token %d = %d, %v; want %d", i, got.IntVal, err, want[i])
+		}
+	}
+}
+
+func TestTransactionCommit(t *testing.T) {
+	toks := newTxTokens(t, 3)
+	err := Transaction(func(tx *Tx) error {
+		if err := tx.SetValue(toks[0], RiftTokenValue{IntVal: 1}); err != nil {
+			return err
+		}
+		if err := tx.SetValue(toks[1], RiftTokenValue{IntVal: 2}); err != nil {
+			return err
+		}
+		if got, err := tx.GetValue(toks[0]); err != nil || got.IntVal != 1 {
+			t.Errorf("buffered value = %d, %v; want 1", got.IntVal, err)
+		}
+		if got, _ := toks[0].GetValue(); got.IntVal != 0 {
+			t.Errorf("token changed to %d before commit", got.IntVal)
+		}
+		_, err := tx.GetValue(toks[2])
+		return err
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantValues(t, toks, 1, 2, 0)
+	if v := toks[2].Version(); v != 1 {
+		t.Errorf("token only read has version %d, want 1", v)
+	}
+}
+
+func TestTransactionRollback(t *testing.T) {
+	t.Run("error", func(t *testing.T) {
+		toks := newTxTokens(t, 2)
+		fail := errors.New("fail")
+		var kept *Tx
+		err := Transaction(func(tx *Tx) error {
+			kept = tx
+			tx.SetValue(toks[0], RiftTokenValue{IntVal: 1})
+			tx.SetValue(toks[1], RiftTokenValue{IntVal: 2})
+			return fail
+		})
+		if err != fail {
+			t.Fatalf("Transaction error %v, want %v", err, fail)
+		}
+		wantValues(t, toks, 0, 0)
+		if err := kept.SetValue(toks[0], RiftTokenValue{IntVal: 3}); !errors.Is(err, ErrTxDone) {
+			t.Errorf("SetValue after rollback: %v, want ErrTxDone", err)
+		}
+	})
+
+	t.Run("revoked mask", func(t *testing.T) {
+		toks := newTxTokens(t, 2)
+		err := Transaction(func(tx *Tx) error {
+			tx.SetValue(toks[0], RiftTokenValue{IntVal: 1})
+			tx.SetValue(toks[1], RiftTokenValue{IntVal: 2})
+			toks[1].Memory.Revoke(AccessUpdate)
+			return nil
+		})
+		if code := ErrorCodeOf(err); code != CodePermissionDenied {
+			t.Fatalf("Transaction error %v, want %v", err, CodePermissionDenied)
+		}
+		wantValues(t, toks, 0, 0)
+	})
+
+	t.Run("panic", func(t *testing.T) {
+		toks := newTxTokens(t, 2)
+		defer func() {
+			if r := recover(); r != "boom" {
+				t.Fatalf("recovered %v, want the panic rethrown", r)
+			}
+			wantValues(t, toks, 0, 0)
+		}()
+		Transaction(func(tx *Tx) error {
+			tx.SetValue(toks[0], RiftTokenValue{IntVal: 1})
+			tx.SetValue(toks[1], RiftTokenValue{IntVal: 2})
+			panic("boom")
+		})
+	})
+}
+
+// TestTransactionOppositeOrders commits transactions joining the same
+// tokens in opposite orders: commit locks them in lock order, so none
+// deadlock, and every commit is seen whole
+func TestTransactionOppositeOrders(t *testing.T) {
+	SetDeadlockDetection(DeadlockError)
+	t.Cleanup(func() { SetDeadlockDetection(DeadlockOff) })
+
+	toks := newTxTokens(t, 3)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		var wg sync.WaitGroup
+		for g := range 8 {
+			wg.Go(func() {
+				order := toks
+				if g%2 == 1 {
+					order = []*RiftToken{toks[2], toks[1], toks[0]}
+				}
+				for i := range 50 {
+					id := int64(g*1000 + i)
+					err := Transaction(func(tx *Tx) error {
+						for _, tok := range order {
+							if err := tx.SetValue(tok, RiftTokenValue{IntVal: id}); err != nil {
+								return err
+							}
+						}
+						return nil
+					})
+					if err != nil {
+						t.Error(err)
+						return
+					}
+				}
+			})
+		}
+		wg.Go(func() {
+			for range 100 {
+				locked := []*RiftToken{toks[0], toks[1], toks[2]}
+				if err := lockSorted("check", locked); err != nil {
+					t.Error(err)
+					return
+				}
+				a, b, c := toks[0].snapshotValue(), toks[1].snapshotValue(), toks[2].snapshotValue()
+				unlockAll(locked)
+				if a.IntVal != b.IntVal || b.IntVal != c.IntVal {
+					t.Errorf("saw a partial commit: %d, %d, %d", a.IntVal, b.IntVal, c.IntVal)
+					return
+				}
+			}
+		})
+		wg.Wait()
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(30 * time.Second):
+		t.Fatal("transactions in opposite orders did not complete")
+	}
+}
@@ -0,0 +1,645 @@
+// go/target/transform/transform.go
+// AST-based Rift Governance Transformer - Go Implementation
+
+// Package transform applies Rift governance rewrites to Go source using
+// go/ast, with the regex PatternEngine kept as a line-oriented fallback.
+package transform
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"strconv"
+	"strings"
+
+	rift "github.com/obinexus/riftlang/bindings/go-riftlang"
+)
+
+// RiftImportPath is the import path injected into rewritten files
+const RiftImportPath = "github.com/obinexus/riftlang/bindings/go-riftlang"
+
+// Mode selects how source is transformed
+type Mode int
+
+const (
+	ModeAST   Mode = iota // structural rewrites via go/ast
+	ModeRegex             // line-oriented PatternEngine rewrites
+)
+
+// ============================================================================
+// Rules
+// ============================================================================
+
+// Rule names a structural rewrite that can be enabled or disabled
+type Rule uint32
+
+const (
+	RuleVar  Rule = 1 << iota // var x T = v  ->  var x T = rift.Declare[T]("x", v)
+	RuleFunc                  // func f()     ->  registered via rift.Func("f", f)
+	RuleGo                    // go f(a)      ->  { a0 := a; rift.Go(func() { f(a0) }) }
+
+	RuleAll = RuleVar | RuleFunc | RuleGo
+)
+
+// ParseRules converts a comma-separated rule list (var, func, go or all)
+// into a Rule set
+func ParseRules(list string) (Rule, error) {
+	var rules Rule
+	for _, name := range strings.Split(list, ",") {
+		switch strings.TrimSpace(name) {
+		case "":
+		case "var":
+			rules |= RuleVar
+		case "func":
+			rules |= RuleFunc
+		case "go":
+			rules |= RuleGo
+		case "all":
+			rules |= RuleAll
+		default:
+			return 0, fmt.Errorf("unknown rule %q", name)
+		}
+	}
+	return rules, nil
+}
+
+// ============================================================================
+// Transformer
+// ============================================================================
+
+// Transformer rewrites Go source under Rift governance
+type Transformer struct {
+	Mode     Mode
+	Rules    Rule
+	Fallback bool                // use the regex engine when parsing fails
+	Engine   *rift.PatternEngine // regex engine for ModeRegex / fallback
+}
+
+// New creates a transformer in AST mode with all rules and regex fallback
+func New() *Transformer {
+	return &Transformer{
+		Mode:     ModeAST,
+		Rules:    RuleAll,
+		Fallback: true,
+		Engine:   rift.CreateDefaultEngine(),
+	}
+}
+
+// Result reports what a transformation did
+type Result struct {
+	Output    []byte
+	Mode      Mode // mode actually used (ModeRegex after a fallback)
+	Rewrites  int
+	ParseErr  error // set when AST parsing failed and fallback was used
+	Unchanged bool
+}
+
+// Source transforms a single Go source file
+func (t *Transformer) Source(filename string, src []byte) (*Result, error) {
+	if t.Mode == ModeRegex {
+		return t.regex(src), nil
+	}
+
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, filename, src, parser.ParseComments)
+	if err != nil {
+		if !t.Fallback {
+			return nil, fmt.Errorf("parse %s: %w", filename, err)
+		}
+		res := t.regex(src)
+		res.ParseErr = err
+		return res, nil
+	}
+
+	rw := newRewriter(t.Rules, file)
+	rw.file(file)
+	if rw.count == 0 {
+		return &Result{Output: src, Mode: ModeAST, Unchanged: true}, nil
+	}
+	addImport(file)
+
+	var buf bytes.Buffer
+	if err := format.Node(&buf, fset, file); err != nil {
+		return nil, fmt.Errorf("format %s: %w", filename, err)
+	}
+	return &Result{Output: buf.Bytes(), Mode: ModeAST, Rewrites: rw.count}, nil
+}
+
+// regex applies the PatternEngine to each line, keeping indentation
+func (t *Transformer) regex(src []byte) *Result {
+	engine := t.Engine
+	if engine == nil {
+		engine = rift.CreateDefaultEngine()
+	}
+
+	lines := strings.Split(string(src), "\n")
+	count := 0
+	for i, line := range lines {
+		m := engine.Match(line)
+		if !m.Matched {
+			continue
+		}
+		indent := line[:len(line)-len(strings.TrimLeft(line, " \t"))]
+		lines[i] = indent + m.Output
+		count++
+	}
+	return &Result{
+		Output:    []byte(strings.Join(lines, "\n")),
+		Mode:      ModeRegex,
+		Rewrites:  count,
+		Unchanged: count == 0,
+	}
+}
+
+// AddPatterns adds .rift pattern declarations to the engine. As in
+// rift.DefaultGoPatterns, $N in a right pattern expands to the left
+// pattern's capture group N; other text, braces included, is literal.
+func AddPatterns(engine *rift.PatternEngine, patterns []rift.PolicyPattern) error {
+	for _, p := range patterns {
+		if _, err := engine.AddPairSubstitution(p.Left, p.Right, p.Priority, rift.SubstituteIndexed); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ============================================================================
+// AST Rewriter
+// ============================================================================
+
+type rewriter struct {
+	rules Rule
+	count int
+
+	// What the file declares, for telling which go statement operands
+	// need evaluating before the goroutine starts
+	consts  map[string]bool // constants, at any level
+	funcs   map[string]bool // top-level functions
+	imports map[string]bool // package names
+	idents  map[string]bool // every identifier, for fresh temporaries
+}
+
+func newRewriter(rules Rule, f *ast.File) *rewriter {
+	rw := &rewriter{
+		rules:   rules,
+		consts:  make(map[string]bool),
+		funcs:   make(map[string]bool),
+		imports: make(map[string]bool),
+		idents:  make(map[string]bool),
+	}
+	for _, imp := range f.Imports {
+		path, _ := strconv.Unquote(imp.Path.Value)
+		name := path[strings.LastIndex(path, "/")+1:]
+		if imp.Name != nil {
+			name = imp.Name.Name
+		}
+		rw.imports[name] = true
+	}
+	for _, decl := range f.Decls {
+		if d, ok := decl.(*ast.FuncDecl); ok && d.Recv == nil {
+			rw.funcs[d.Name.Name] = true
+		}
+	}
+	ast.Inspect(f, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.Ident:
+			rw.idents[n.Name] = true
+		case *ast.GenDecl:
+			if n.Tok == token.CONST {
+				for _, spec := range n.Specs {
+					for _, name := range spec.(*ast.ValueSpec).Names {
+						rw.consts[name.Name] = true
+					}
+				}
+			}
+		}
+		return true
+	})
+	return rw
+}
+
+func (rw *rewriter) file(f *ast.File) {
+	registered := funcRegistrations(f)
+	var decls []ast.Decl
+	for _, decl := range f.Decls {
+		switch d := decl.(type) {
+		case *ast.GenDecl:
+			if rw.rules&RuleVar != 0 && d.Tok == token.VAR {
+				rw.varDecl(d)
+			}
+			decls = append(decls, d)
+		case *ast.FuncDecl:
+			decls = append(decls, d)
+			if d.Body != nil {
+				rw.block(d.Body)
+			}
+			if rw.rules&RuleFunc != 0 && registrable(d) && !registered[d.Name.Name] {
+				decls = append(decls, funcRegistration(d.Name.Name))
+				rw.count++
+			}
+		default:
+			decls = append(decls, d)
+		}
+	}
+	f.Decls = decls
+}
+
+// block rewrites statements inside function bodies
+func (rw *rewriter) block(body *ast.BlockStmt) {
+	ast.Inspect(body, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.DeclStmt:
+			if d, ok := n.Decl.(*ast.GenDecl); ok && d.Tok == token.VAR && rw.rules&RuleVar != 0 {
+				rw.varDecl(d)
+			}
+		case *ast.BlockStmt:
+			if rw.rules&RuleGo != 0 {
+				for i, stmt := range n.List {
+					if g, ok := stmt.(*ast.GoStmt); ok {
+						n.List[i] = rw.goStmt(g)
+						rw.count++
+					}
+				}
+			}
+		case *ast.CaseClause:
+			rw.goStmts(n.Body)
+		case *ast.CommClause:
+			rw.goStmts(n.Body)
+		}
+		return true
+	})
+}
+
+func (rw *rewriter) goStmts(list []ast.Stmt) {
+	if rw.rules&RuleGo == 0 {
+		return
+	}
+	for i, stmt := range list {
+		if g, ok := stmt.(*ast.GoStmt); ok {
+			list[i] = rw.goStmt(g)
+			rw.count++
+		}
+	}
+}
+
+// varDecl turns each `var x T = v` spec into
+// `var x T = rift.Declare[T]("x", v)`, and `var x = v` into
+// `var x = rift.Declare("x", v)`, so x keeps its type. The explicit type
+// argument keeps untyped constants converting to T.
+func (rw *rewriter) varDecl(d *ast.GenDecl) {
+	for _, spec := range d.Specs {
+		vs, ok := spec.(*ast.ValueSpec)
+		if !ok || (len(vs.Values) != 0 && len(vs.Values) != len(vs.Names)) {
+			continue // skip multi-value calls like var a, b = f()
+		}
+		if vs.Type == nil && len(vs.Values) == 0 || hasBlank(vs.Names) || governedValues(vs) {
+			continue
+		}
+		if len(vs.Values) == 0 && zeroValue(vs.Type) == nil {
+			continue // var mu sync.Mutex: Declare would copy its zero value
+		}
+		values := make([]ast.Expr, len(vs.Names))
+		for i, name := range vs.Names {
+			var v, orig ast.Expr
+			if len(vs.Values) > 0 {
+				v, orig = vs.Values[i], vs.Values[i]
+			} else {
+				v, orig = zeroValue(vs.Type), vs.Type
+			}
+			call := riftCallAt(orig.Pos(), orig.End(), "Declare", strLit(name.Name), v)
+			if vs.Type != nil {
+				call.Fun = &ast.IndexExpr{X: call.Fun, Lbrack: orig.Pos(), Index: vs.Type, Rbrack: orig.Pos()}
+			}
+			values[i] = call
+		}
+		vs.Values = values
+		rw.count++
+	}
+}
+
+// governedValues reports whether every value of vs is already a
+// rift.Declare call, as an earlier rewrite leaves it, or a rift.Var call,
+// as rewrites before Declare did, so rewriting is idempotent
+func governedValues(vs *ast.ValueSpec) bool {
+	for _, v := range vs.Values {
+		if !isRiftCall(v, "Declare") && !isRiftCall(v, "Var") {
+			return false
+		}
+	}
+	return len(vs.Values) > 0
+}
+
+// funcRegistrations returns the names registered by the file's top-level
+// `var _ = rift.Func("name", name)` declarations
+func funcRegistrations(f *ast.File) map[string]bool {
+	names := make(map[string]bool)
+	for _, decl := range f.Decls {
+		d, ok := decl.(*ast.GenDecl)
+		if !ok || d.Tok != token.VAR {
+			continue
+		}
+		for _, spec := range d.Specs {
+			vs, ok := spec.(*ast.ValueSpec)
+			if !ok {
+				continue
+			}
+			for _, v := range vs.Values {
+				if isRiftCall(v, "Func") {
+					if id, ok := v.(*ast.CallExpr).Args[1].(*ast.Ident); ok {
+						names[id.Name] = true
+					}
+				}
+			}
+		}
+	}
+	return names
+}
+
+// isRiftCall reports whether e calls rift.fn, instantiated or not, with a
+// name and one more argument, the shape of the Declare and Func calls the
+// rewriter builds
+func isRiftCall(e ast.Expr, fn string) bool {
+	call, ok := e.(*ast.CallExpr)
+	if !ok || len(call.Args) != 2 {
+		return false
+	}
+	fun := call.Fun
+	if ix, ok := fun.(*ast.IndexExpr); ok {
+		fun = ix.X
+	}
+	sel, ok := fun.(*ast.SelectorExpr)
+	if !ok || sel.Sel.Name != fn {
+		return false
+	}
+	pkg, ok := sel.X.(*ast.Ident)
+	return ok && pkg.Name == "rift"
+}
+
+func hasBlank(names []*ast.Ident) bool {
+	for _, name := range names {
+		if name.Name == "_" {
+			return true
+		}
+	}
+	return false
+}
+
+// registrable reports whether a func can be referenced as a value
+func registrable(d *ast.FuncDecl) bool {
+	if d.Recv != nil || d.Name.Name == "init" || d.Name.Name == "_" {
+		return false
+	}
+	return d.Type.TypeParams == nil || len(d.Type.TypeParams.List) == 0
+}
+
+// funcRegistration builds `var _ = rift.Func("name", name)`
+func funcRegistration(name string) ast.Decl {
+	return &ast.GenDecl{
+		Tok: token.VAR,
+		Specs: []ast.Spec{&ast.ValueSpec{
+			Names:  []*ast.Ident{ast.NewIdent("_")},
+			Values: []ast.Expr{riftCallAt(token.NoPos, token.NoPos, "Func", strLit(name), ast.NewIdent(name))},
+		}},
+	}
+}
+
+// goStmt builds `rift.Go(func() { call })`. A go statement evaluates the
+// function value and arguments before the goroutine starts, so operands
+// that might change meanwhile are first bound to temporaries, giving
+//
+//	{
+//		a0 := a
+//		rift.Go(func() { f(a0) })
+//	}
+//
+// Constants, function literals, top-level and package functions and
+// builtins are left in place; binding an untyped constant would change
+// the type it converts to. A function literal's arguments are passed to
+// a wrapper taking its parameters instead (see goLit).
+func (rw *rewriter) goStmt(g *ast.GoStmt) ast.Stmt {
+	pos, end := g.Pos(), g.End()
+	if lit, ok := g.Call.Fun.(*ast.FuncLit); ok && !rw.constantArgs(g.Call.Args) {
+		return rw.goLit(g, lit)
+	}
+	call := &ast.CallExpr{Fun: g.Call.Fun, Lparen: g.Call.Lparen, Ellipsis: g.Call.Ellipsis, Rparen: g.Call.Rparen}
+	var names, values []ast.Expr
+	bind := func(e ast.Expr, prefix string) ast.Expr {
+		name := rw.fresh(prefix)
+		names = append(names, name)
+		values = append(values, e)
+		return ast.NewIdent(name.Name)
+	}
+	if !rw.staticFunc(g.Call.Fun) {
+		call.Fun = bind(g.Call.Fun, "goFn")
+	}
+	for _, arg := range g.Call.Args {
+		if !rw.constant(arg) {
+			arg = bind(arg, "goArg")
+		}
+		call.Args = append(call.Args, arg)
+	}
+
+	stmt := &ast.ExprStmt{X: riftCallAt(pos, end, "Go", &ast.FuncLit{
+		Type: &ast.FuncType{Func: pos, Params: &ast.FieldList{}},
+		Body: &ast.BlockStmt{Lbrace: pos, List: []ast.Stmt{&ast.ExprStmt{X: call}}, Rbrace: end},
+	})}
+	if len(names) == 0 {
+		return stmt
+	}
+	// Positioned at the operands, so the printer keeps them on one line
+	return &ast.BlockStmt{Lbrace: pos, List: []ast.Stmt{
+		&ast.AssignStmt{Lhs: names, TokPos: values[0].Pos(), Tok: token.DEFINE, Rhs: values},
+		stmt,
+	}, Rbrace: end}
+}
+
+// goLit rewrites `go func(p T) { ... }(a)` into
+//
+//	func(goArg0 T) { rift.Go(func() { func(p T) { ... }(goArg0) }) }(a)
+//
+// so the arguments are evaluated first and converted to the literal's
+// own parameter types, and the literal and its comments stay in order
+func (rw *rewriter) goLit(g *ast.GoStmt, lit *ast.FuncLit) ast.Stmt {
+	pos, end := g.Pos(), g.End()
+	params := &ast.FieldList{}
+	call := &ast.CallExpr{Fun: lit, Lparen: g.Call.Lparen, Rparen: g.Call.Rparen}
+	for _, field := range lit.Type.Params.List {
+		for range max(len(field.Names), 1) {
+			name := rw.fresh("goArg")
+			params.List = append(params.List, &ast.Field{Names: []*ast.Ident{name}, Type: field.Type})
+			call.Args = append(call.Args, ast.NewIdent(name.Name))
+			if _, ok := field.Type.(*ast.Ellipsis); ok {
+				call.Ellipsis = g.Call.Rparen
+			}
+		}
+	}
+
+	body := &ast.ExprStmt{X: riftCallAt(pos, end, "Go", &ast.FuncLit{
+		Type: &ast.FuncType{Func: pos, Params: &ast.FieldList{}},
+		Body: &ast.BlockStmt{Lbrace: pos, List: []ast.Stmt{&ast.ExprStmt{X: call}}, Rbrace: end},
+	})}
+	return &ast.ExprStmt{X: &ast.CallExpr{
+		Fun: &ast.FuncLit{
+			Type: &ast.FuncType{Func: pos, Params: params},
+			Body: &ast.BlockStmt{Lbrace: pos, List: []ast.Stmt{body}, Rbrace: end},
+		},
+		Lparen:   g.Call.Rparen,
+		Args:     g.Call.Args,
+		Ellipsis: g.Call.Ellipsis,
+		Rparen:   g.Call.Rparen,
+	}}
+}
+
+// constantArgs reports whether every argument is a constant
+func (rw *rewriter) constantArgs(args []ast.Expr) bool {
+	for _, arg := range args {
+		if !rw.constant(arg) {
+			return false
+		}
+	}
+	return true
+}
+
+// staticFunc reports whether fun names a function whose value cannot
+// change before the goroutine starts: a function literal, a builtin, or a
+// top-level or imported package function
+func (rw *rewriter) staticFunc(fun ast.Expr) bool {
+	switch fun := fun.(type) {
+	case *ast.FuncLit:
+		return true
+	case *ast.ParenExpr:
+		return rw.staticFunc(fun.X)
+	case *ast.Ident:
+		return rw.funcs[fun.Name] || builtins[fun.Name]
+	case *ast.IndexExpr:
+		return rw.staticFunc(fun.X)
+	case *ast.IndexListExpr:
+		return rw.staticFunc(fun.X)
+	case *ast.SelectorExpr:
+		pkg, ok := fun.X.(*ast.Ident)
+		return ok && rw.imports[pkg.Name]
+	}
+	return false
+}
+
+// constant reports whether e is a constant expression: literals, the
+// predeclared constants and the file's own constants, combined
+func (rw *rewriter) constant(e ast.Expr) bool {
+	switch e := e.(type) {
+	case *ast.BasicLit:
+		return true
+	case *ast.Ident:
+		switch e.Name {
+		case "nil", "true", "false", "iota":
+			return true
+		}
+		return rw.consts[e.Name]
+	case *ast.ParenExpr:
+		return rw.constant(e.X)
+	case *ast.UnaryExpr:
+		return e.Op != token.ARROW && e.Op != token.AND && rw.constant(e.X)
+	case *ast.BinaryExpr:
+		return rw.constant(e.X) && rw.constant(e.Y)
+	}
+	return false
+}
+
+// fresh returns an identifier starting with prefix that the file does not
+// use
+func (rw *rewriter) fresh(prefix string) *ast.Ident {
+	for i := 0; ; i++ {
+		name := prefix + strconv.Itoa(i)
+		if !rw.idents[name] {
+			rw.idents[name] = true
+			return ast.NewIdent(name)
+		}
+	}
+}
+
+// builtins are the predeclared functions a go statement may call
+var builtins = map[string]bool{
+	"append": true, "cap": true, "clear": true, "close": true, "complex": true,
+	"copy": true, "delete": true, "imag": true, "len": true, "make": true,
+	"max": true, "min": true, "new": true, "panic": true, "print": true,
+	"println": true, "real": true, "recover": true,
+}
+
+// zeroValue returns an expression for the zero value of typ, or nil if
+// typ may be a struct or array, whose zero value may hold a lock that
+// copying it into a token would copy
+func zeroValue(typ ast.Expr) ast.Expr {
+	switch t := typ.(type) {
+	case *ast.Ident:
+		switch t.Name {
+		case "string":
+			return strLit("")
+		case "bool":
+			return ast.NewIdent("false")
+		case "int", "int8", "int16", "int32", "int64",
+			"uint", "uint8", "uint16", "uint32", "uint64", "uintptr",
+			"float32", "float64", "byte", "rune":
+			return &ast.CallExpr{Fun: t, Args: []ast.Expr{&ast.BasicLit{Kind: token.INT, Value: "0"}}}
+		}
+		return nil
+	case *ast.ArrayType:
+		if t.Len != nil {
+			return nil
+		}
+	case *ast.StarExpr, *ast.MapType, *ast.ChanType, *ast.FuncType, *ast.InterfaceType:
+	default:
+		return nil
+	}
+	// *new(T)
+	return &ast.StarExpr{X: &ast.CallExpr{Fun: ast.NewIdent("new"), Args: []ast.Expr{typ}}}
+}
+
+// riftCallAt builds rift.fn(args...) positioned over the source range it
+// replaces, so the printer keeps surrounding comments in place
+func riftCallAt(pos, end token.Pos, fn string, args ...ast.Expr) *ast.CallExpr {
+	return &ast.CallExpr{
+		Fun: &ast.SelectorExpr{
+			X:   &ast.Ident{NamePos: pos, Name: "rift"},
+			Sel: &ast.Ident{NamePos: pos, Name: fn},
+		},
+		Lparen: pos,
+		Args:   args,
+		Rparen: end,
+	}
+}
+
+func strLit(s string) *ast.BasicLit {
+	return &ast.BasicLit{Kind: token.STRING, Value: strconv.Quote(s)}
+}
+
+// addImport adds the rift import unless the file already has it
+func addImport(f *ast.File) {
+	for _, imp := range f.Imports {
+		if path, _ := strconv.Unquote(imp.Path.Value); path == RiftImportPath {
+			return
+		}
+	}
+
+	spec := &ast.ImportSpec{
+		Name: ast.NewIdent("rift"),
+		Path: strLit(RiftImportPath),
+	}
+	f.Imports = append(f.Imports, spec)
+
+	for _, decl := range f.Decls {
+		if d, ok := decl.(*ast.GenDecl); ok && d.Tok == token.IMPORT {
+			if !d.Lparen.IsValid() {
+				d.Lparen = d.Pos()
+				d.Rparen = d.End()
+			}
+			d.Specs = append(d.Specs, spec)
+			return
+		}
+	}
+	f.Decls = append([]ast.Decl{&ast.GenDecl{
+		Tok:   token.IMPORT,
+		Specs: []ast.Spec{spec},
+	}}, f.Decls...)
+}
@@ -0,0 +1,139 @@
+package transform
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	rift "github.com/obinexus/riftlang/bindings/go-riftlang"
+)
+
+var update = flag.Bool("update", false, "rewrite the .golden files")
+
+// goldenCases returns the testdata inputs, by name
+func goldenCases(t *testing.T) []string {
+	t.Helper()
+	inputs, err := filepath.Glob(filepath.Join("testdata", "*.input"))
+	if err != nil || len(inputs) == 0 {
+		t.Fatalf("no testdata inputs: %v", err)
+	}
+	return inputs
+}
+
+// transform rewrites src with every rule, failing the test on error
+func transform(t *testing.T, name string, src []byte) []byte {
+	t.Helper()
+	tr := New()
+	tr.Fallback = false
+	res, err := tr.Source(name, src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return res.Output
+}
+
+func TestSourceGolden(t *testing.T) {
+	for _, input := range goldenCases(t) {
+		name := strings.TrimSuffix(filepath.Base(input), ".input")
+		t.Run(name, func(t *testing.T) {
+			src, err := os.ReadFile(input)
+			if err != nil {
+				t.Fatal(err)
+			}
+			out := transform(t, input, src)
+
+			golden := strings.TrimSuffix(input, ".input") + ".golden"
+			if *update {
+				if err := os.WriteFile(golden, out, 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			want, err := os.ReadFile(golden)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(out, want) {
+				t.Errorf("output differs from %s:\n%s", golden, out)
+			}
+
+			if again := transform(t, golden, out); !bytes.Equal(again, out) {
+				t.Errorf("rewriting the output again changed it:\n%s", again)
+			}
+		})
+	}
+}
+
+// TestSourceProgramsRun compiles and runs each input before and after the
+// rewrite: the rewritten program must build and print the same
+func TestSourceProgramsRun(t *testing.T) {
+	if testing.Short() {
+		t.Skip("builds programs")
+	}
+	goTool, err := exec.LookPath("go")
+	if err != nil {
+		t.Skip("go tool not found")
+	}
+	for _, input := range goldenCases(t) {
+		name := strings.TrimSuffix(filepath.Base(input), ".input")
+		t.Run(name, func(t *testing.T) {
+			src, err := os.ReadFile(input)
+			if err != nil {
+				t.Fatal(err)
+			}
+			want := runProgram(t, goTool, src)
+			got := runProgram(t, goTool, transform(t, input, src))
+			if got != want {
+				t.Errorf("rewritten program printed\n%s\nthe original\n%s", got, want)
+			}
+		})
+	}
+}
+
+// runProgram builds and runs src as a main package inside this module, so
+// it can import the rift package, returning what it prints
+func runProgram(t *testing.T, goTool string, src []byte) string {
+	t.Helper()
+	dir, err := os.MkdirTemp("testdata", "run-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.RemoveAll(dir) })
+	if err := os.WriteFile(filepath.Join(dir, "main.go"), src, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	cmd := exec.Command(goTool, "run", ".")
+	cmd.Dir = dir
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("go run: %v\n%s\n%s", err, out, src)
+	}
+	return string(out)
+}
+
+// TestAddPatternsCaptureGroups rewrites with a policy pattern whose right
+// side uses a capture group, which must expand, and braces, which must not
+func TestAddPatternsCaptureGroups(t *testing.T) {
+	policy, err := rift.ParsePolicy(`pattern "log\\.Print\\((\\w+)\\)" -> "rift.Log(\"{msg}\", $1)" {
+  priority: 100
+}
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	engine := rift.NewPatternEngine("classical")
+	if err := AddPatterns(engine, policy.Patterns); err != nil {
+		t.Fatal(err)
+	}
+	tr := &Transformer{Mode: ModeRegex, Engine: engine}
+	res, err := tr.Source("log.go", []byte("\tlog.Print(status)\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := string(res.Output), "\trift.Log(\"{msg}\", status)\n"; got != want {
+		t.Errorf("rewrote to %q, want %q", got, want)
+	}
+}
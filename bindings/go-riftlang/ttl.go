@@ -0,0 +1,144 @@
+// go/target/ttl.go
+// Token Expiry and TTL Reaper - Go Implementation
+
+package rift
+
+import (
+	"sync"
+	"time"
+)
+
+// ErrExpired matches, via errors.Is, the error Validate returns for a token
+// past its TTL
+var ErrExpired error = &GovernanceError{Code: CodeExpired, Op: "validate", Detail: "token expired"}
+
+// ttlTokens tracks the tokens with a TTL until they are reaped or their TTL
+// is cleared
+var ttlTokens struct {
+	sync.Mutex
+	set map[*RiftToken]struct{}
+}
+
+// ============================================================================
+// TTL
+// ============================================================================
+
+// SetTTL makes the token expire d from now: Validate then fails with
+// CodeExpired (see ErrExpired) and the expiry is audited as AuditExpire.
+// The token is tracked for ReapExpired until it is reaped; d <= 0 clears
+// the TTL.
+func (t *RiftToken) SetTTL(d time.Duration) {
+	ttlTokens.Lock()
+	defer ttlTokens.Unlock()
+	if d <= 0 {
+		t.expiresAt.Store(0)
+		delete(ttlTokens.set, t)
+		return
+	}
+	t.expiresAt.Store(clockNow().Add(d).UnixNano())
+	t.expiryAudited.Store(false)
+	if ttlTokens.set == nil {
+		ttlTokens.set = make(map[*RiftToken]struct{})
+	}
+	ttlTokens.set[t] = struct{}{}
+}
+
+// ExpiresAt returns when the token expires, and false if it has no TTL
+func (t *RiftToken) ExpiresAt() (time.Time, bool) {
+	at := t.expiresAt.Load()
+	if at == 0 {
+		return time.Time{}, false
+	}
+	return time.Unix(0, at), true
+}
+
+// Expired reports whether the token's TTL has passed
+func (t *RiftToken) Expired() bool {
+	at := t.expiresAt.Load()
+	return at != 0 && clockNow().UnixNano() >= at
+}
+
+// expiryError returns the validation error of an expired token, auditing
+// the expiry the first time it is seen, or nil
+func (t *RiftToken) expiryError() *GovernanceError {
+	if !t.Expired() {
+		return nil
+	}
+	if t.expiryAudited.CompareAndSwap(false, true) {
+		auditEmit(AuditExpire, t, "ttl elapsed")
+	}
+	return govErr(CodeExpired, "validate", "token expired at %s", time.Unix(0, t.expiresAt.Load()).Format(time.RFC3339Nano))
+}
+
+// ============================================================================
+// Reaper
+// ============================================================================
+
+// ReapExpired clears the value and releases the memory span of every
+// tracked token past its TTL, returning how many it reaped. A token whose
+// lock would complete a wait cycle is left for the next call.
+func ReapExpired() int {
+	ttlTokens.Lock()
+	var expired []*RiftToken
+	for t := range ttlTokens.set {
+		if t.Expired() {
+			expired = append(expired, t)
+		}
+	}
+	ttlTokens.Unlock()
+
+	reaped := 0
+	for _, t := range expired {
+		if !t.Lock() {
+			continue
+		}
+		t.expiryError()
+		t.Value = RiftTokenValue{}
+		t.ClearBit(TokenInitialized | TokenGoverned)
+		if t.Memory != nil {
+			t.Memory.Release()
+		}
+		t.reseal()
+		auditEmit(AuditExpire, t, "reaped")
+		t.Unlock()
+
+		ttlTokens.Lock()
+		delete(ttlTokens.set, t)
+		ttlTokens.Unlock()
+		reaped++
+	}
+	return reaped
+}
+
+// TTLReaper calls ReapExpired in the background
+type TTLReaper struct {
+	stop chan struct{}
+	done chan struct{}
+	once sync.Once
+}
+
+// StartTTLReaper reaps expired tokens every interval of the package clock
+// (see SetClock) until Stop
+func StartTTLReaper(interval time.Duration) *TTLReaper {
+	r := &TTLReaper{stop: make(chan struct{}), done: make(chan struct{})}
+	ticker := CurrentClock().NewTicker(interval)
+	go func() {
+		defer close(r.done)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C():
+				ReapExpired()
+			case <-r.stop:
+				return
+			}
+		}
+	}()
+	return r
+}
+
+// Stop halts the reaper and waits for a reap in progress to finish
+func (r *TTLReaper) Stop() {
+	r.once.Do(func() { close(r.stop) })
+	<-r.done
+}
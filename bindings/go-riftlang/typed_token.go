@@ -0,0 +1,235 @@
+// go/target/typed_token.go
+// Generic Typed Tokens - Go Implementation
+
+package rift
+
+import (
+	"fmt"
+	"maps"
+	"reflect"
+	"slices"
+)
+
+// ============================================================================
+// Token[T]
+// ============================================================================
+
+// Token is a compile-time typed view over a RiftToken. The governance bits
+// and memory span live on the embedded RiftToken.
+type Token[T any] struct {
+	*RiftToken
+}
+
+// NewToken creates a typed token with a token type inferred from T
+func NewToken[T any]() *Token[T] {
+	memory := NewRiftMemorySpan(SpanFixed, 64)
+	return &Token[T]{RiftToken: NewRiftToken(tokenTypeFor[T](), memory)}
+}
+
+// TypedVar creates an initialized, validated typed token
+func TypedVar[T any](value T) *Token[T] {
+	token := NewToken[T]()
+	token.Set(value)
+	token.Validate()
+	return token
+}
+
+// AsToken wraps an existing RiftToken in a typed view
+func AsToken[T any](token *RiftToken) *Token[T] {
+	return &Token[T]{RiftToken: token}
+}
+
+// Get returns the token value as T
+func (t *Token[T]) Get() (T, error) {
+	var out T
+	val, err := t.GetValue()
+	if err != nil {
+		return out, err
+	}
+
+	switch p := any(&out).(type) {
+	case *int:
+		*p = int(val.IntVal)
+	case *int8:
+		*p = int8(val.IntVal)
+	case *int16:
+		*p = int16(val.IntVal)
+	case *int32:
+		*p = int32(val.IntVal)
+	case *int64:
+		*p = val.IntVal
+	case *uint:
+		*p = uint(val.IntVal)
+	case *uint8:
+		*p = uint8(val.IntVal)
+	case *uint16:
+		*p = uint16(val.IntVal)
+	case *uint32:
+		*p = uint32(val.IntVal)
+	case *uint64:
+		*p = uint64(val.IntVal)
+	case *float32:
+		*p = float32(val.FloatVal)
+	case *float64:
+		*p = val.FloatVal
+	case *string:
+		*p = val.StringVal
+	case *[]*RiftToken:
+		*p = val.ArrVal
+	default:
+		if val.PtrVal == nil {
+			return out, nil
+		}
+		v, ok := val.PtrVal.(T)
+		if !ok {
+			return out, fmt.Errorf("token value is %T, not %T", val.PtrVal, out)
+		}
+		out = v
+	}
+	return out, nil
+}
+
+// Set stores value in the field matching T and marks the token initialized
+func (t *Token[T]) Set(value T) error {
+	if t.RiftToken == nil || !t.HasBit(TokenAllocated) {
+		return fmt.Errorf("token not allocated")
+	}
+
+	var val RiftTokenValue
+	switch v := any(value).(type) {
+	case int:
+		val.IntVal = int64(v)
+	case int8:
+		val.IntVal = int64(v)
+	case int16:
+		val.IntVal = int64(v)
+	case int32:
+		val.IntVal = int64(v)
+	case int64:
+		val.IntVal = v
+	case uint:
+		val.IntVal = int64(v)
+	case uint8:
+		val.IntVal = int64(v)
+	case uint16:
+		val.IntVal = int64(v)
+	case uint32:
+		val.IntVal = int64(v)
+	case uint64:
+		val.IntVal = int64(v)
+	case float32:
+		val.FloatVal = float64(v)
+	case float64:
+		val.FloatVal = v
+	case string:
+		val.StringVal = v
+	case []*RiftToken:
+		val.ArrVal = v
+	default:
+		val.PtrVal = value
+	}
+
+	return t.SetValue(val)
+}
+
+// ============================================================================
+// Typed Constructors
+// ============================================================================
+
+// Each typed constructor returns the governance error that kept its token
+// from validating or registering, and then no token.
+
+// Int creates a governed TokenGoInt variable registered in DefaultRegistry
+func Int(name string, value int64) (*RiftToken, error) {
+	return typedVar(name, TokenGoInt, SpanFixed, 8, RiftTokenValue{IntVal: value})
+}
+
+// Float creates a governed TokenGoFloat variable registered in
+// DefaultRegistry
+func Float(name string, value float64) (*RiftToken, error) {
+	return typedVar(name, TokenGoFloat, SpanFixed, 8, RiftTokenValue{FloatVal: value})
+}
+
+// Str creates a governed TokenGoString variable registered in
+// DefaultRegistry, its span sized to the string's UTF-8 bytes rather than
+// its runes
+func Str(name string, value string) (*RiftToken, error) {
+	return typedVar(name, TokenGoString, SpanFixed, uint64(len(value)), RiftTokenValue{StringVal: value})
+}
+
+// Slice creates a governed TokenGoSlice variable registered in
+// DefaultRegistry holding a copy of value, its row span sized to the
+// copy's elements; spare capacity in value is not reserved. The copy is
+// laid out as a RiftSlice[T] holds its elements.
+func Slice[T any](name string, value []T) (*RiftToken, error) {
+	held := slices.Clone(value)
+	bytes := sizeOf[T]() * uint64(len(held))
+	return typedVar(name, TokenGoSlice, SpanRow, bytes, RiftTokenValue{PtrVal: held})
+}
+
+// Map creates a governed TokenGoMap variable registered in DefaultRegistry
+// holding a copy of value, its row span sized to the map's entries. The
+// copy is laid out as a RiftMap[K, V] holds its entries.
+func Map[K comparable, V any](name string, value map[K]V) (*RiftToken, error) {
+	bytes := (sizeOf[K]() + sizeOf[V]()) * uint64(len(value))
+	if value == nil {
+		value = make(map[K]V)
+	}
+	return typedVar(name, TokenGoMap, SpanRow, bytes, RiftTokenValue{PtrVal: maps.Clone(value)})
+}
+
+// Declare registers a governed variable holding value in DefaultRegistry,
+// its token typed for T as TypedVar's is, and returns value unchanged. The
+// AST transformer wraps declarations in it, so a rewritten `var x T = v`
+// leaves x a T; later assignments to x do not reach the token.
+func Declare[T any](name string, value T) T {
+	token := TypedVar(value)
+	DefaultRegistry.Register(name, token.RiftToken)
+	return value
+}
+
+// typedVar creates, validates and registers a token of tokenType holding
+// val, releasing it if either fails. Each constructor's signature ties the
+// token type to the value's Go type, where Var makes every token a
+// TokenGoInt.
+func typedVar(name string, tokenType, spanType int, bytes uint64, val RiftTokenValue) (*RiftToken, error) {
+	token := NewRiftToken(tokenType, NewRiftMemorySpan(spanType, bytes))
+	token.Value = val
+	token.SetBit(TokenInitialized)
+	err := token.ValidateErr()
+	if err == nil {
+		_, err = DefaultRegistry.RegisterErr(name, token)
+	}
+	if err != nil {
+		token.Release()
+		return nil, err
+	}
+	return token, nil
+}
+
+// tokenTypeFor maps a Go type to the matching Rift token type
+func tokenTypeFor[T any]() int {
+	return tokenTypeOf(reflect.TypeOf((*T)(nil)).Elem())
+}
+
+// tokenTypeOf maps a Go type to the matching Rift token type. Values that
+// are neither numbers, strings, slices, maps nor channels are held in
+// PtrVal as TokenGoPtr.
+func tokenTypeOf(typ reflect.Type) int {
+	switch typ.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return TokenGoInt
+	case reflect.Float32, reflect.Float64:
+		return TokenGoFloat
+	case reflect.String:
+		return TokenGoString
+	case reflect.Slice, reflect.Array:
+		return TokenGoSlice
+	case reflect.Map:
+		return TokenGoMap
+	case reflect.Chan:
+		return TokenGoChan
+	}
+	return TokenGoPtr
+}
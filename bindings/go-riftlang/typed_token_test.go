@@ -0,0 +1,112 @@
+package rift
+
+import (
+	"reflect"
+	"testing"
+)
+
+// TestTypedConstructorSpans checks each typed constructor sizes its span
+// from the payload it holds
+func TestTypedConstructorSpans(t *testing.T) {
+	spare := make([]int64, 3, 100)
+	tests := []struct {
+		name      string
+		create    func() (*RiftToken, error)
+		tokenType int
+		bytes     uint64
+	}{
+		{"int", func() (*RiftToken, error) { return Int("spans.int", 7) }, TokenGoInt, 8},
+		{"float", func() (*RiftToken, error) { return Float("spans.float", 1.5) }, TokenGoFloat, 8},
+		{"string", func() (*RiftToken, error) { return Str("spans.str", "hello") }, TokenGoString, 5},
+		{"multibyte string", func() (*RiftToken, error) { return Str("spans.utf8", "héllo, 世界") }, TokenGoString, 14},
+		{"empty string", func() (*RiftToken, error) { return Str("spans.empty", "") }, TokenGoString, 0},
+		{"slice", func() (*RiftToken, error) { return Slice("spans.slice", []int32{1, 2, 3}) }, TokenGoSlice, 12},
+		{"slice with spare capacity", func() (*RiftToken, error) { return Slice("spans.spare", spare) }, TokenGoSlice, 24},
+		{"nil slice", func() (*RiftToken, error) { return Slice[int64]("spans.nil", nil) }, TokenGoSlice, 0},
+		{"map", func() (*RiftToken, error) { return Map("spans.map", map[int32]int64{1: 1, 2: 2}) }, TokenGoMap, 24},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tok, err := tt.create()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tok.Type != tt.tokenType {
+				t.Errorf("Type = %d, want %d", tok.Type, tt.tokenType)
+			}
+			if got := tok.Memory.Bytes; got != tt.bytes {
+				t.Errorf("span of %d bytes, want %d", got, tt.bytes)
+			}
+			if err := tok.ValidateErr(); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
+
+// TestTypedConstructorRefused checks a constructor whose token fails
+// validation returns the error and registers nothing
+func TestTypedConstructorRefused(t *testing.T) {
+	SetQuota(&QuotaPolicy{SpanTokens: map[int]int{SpanRow: SpanTokenCount(SpanRow)}})
+	t.Cleanup(func() { SetQuota(nil) })
+
+	tok, err := Slice("refused.slice", []int64{1})
+	if code := ErrorCodeOf(err); code != CodeQuotaExceeded || tok != nil {
+		t.Fatalf("Slice = %v, %v; want no token and %v", tok, err, CodeQuotaExceeded)
+	}
+	if _, ok := DefaultRegistry.Lookup("refused.slice"); ok {
+		t.Error("refused token registered")
+	}
+}
+
+// TestSliceHoldsCopy checks Slice copies its payload and sizes the span to
+// the copy
+func TestSliceHoldsCopy(t *testing.T) {
+	src := make([]int64, 2, 50)
+	src[0], src[1] = 1, 2
+	tok, err := Slice("copy.slice", src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	src[0] = 9
+
+	val, err := tok.GetValue()
+	if err != nil {
+		t.Fatal(err)
+	}
+	held := val.PtrVal.([]int64)
+	if held[0] != 1 {
+		t.Errorf("held[0] = %d after changing the source, want 1", held[0])
+	}
+	if got, want := spanCapacity(tok, sizeOf[int64]()), len(held); got != want {
+		t.Errorf("span holds %d elements, payload %d", got, want)
+	}
+}
+
+func TestTokenTypeOf(t *testing.T) {
+	var x int
+	tests := []struct {
+		value any
+		want  int
+	}{
+		{int8(1), TokenGoInt},
+		{uint64(1), TokenGoInt},
+		{1.5, TokenGoFloat},
+		{"s", TokenGoString},
+		{[]int{1}, TokenGoSlice},
+		{[2]int{}, TokenGoSlice},
+		{map[string]int{}, TokenGoMap},
+		{make(chan int), TokenGoChan},
+		{true, TokenGoPtr},
+		{struct{ A int }{}, TokenGoPtr},
+		{&x, TokenGoPtr},
+	}
+	for _, tt := range tests {
+		if got := tokenTypeOf(reflect.TypeOf(tt.value)); got != tt.want {
+			t.Errorf("tokenTypeOf(%T) = %s, want %s", tt.value, TokenTypeName(got), TokenTypeName(tt.want))
+		}
+	}
+	if tok := TypedVar(true); tok.Type != TokenGoPtr {
+		t.Errorf("TypedVar(true) type %s, want GoPtr", TokenTypeName(tok.Type))
+	}
+}
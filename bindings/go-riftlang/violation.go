@@ -0,0 +1,99 @@
+// go/target/violation.go
+// Governance Violation Handlers - Go Implementation
+
+package rift
+
+import (
+	"fmt"
+	"log"
+	"sync/atomic"
+)
+
+// ============================================================================
+// Handlers
+// ============================================================================
+
+// ViolationHandler is called on every governance violation: an access the
+// token's mask refuses, a failed validation, a governed value changed
+// behind its token, or an unlock by a goroutine not holding the lock. It runs after the violation is audited and before the
+// error is returned, possibly with the token's lock held, so it must not
+// lock t. A handler that returns lets the caller see the error as usual.
+type ViolationHandler func(t *RiftToken, err *GovernanceError)
+
+// ViolationError only returns the error, the default. Installing it on a
+// token keeps that token in error mode under a stricter global handler.
+func ViolationError(t *RiftToken, err *GovernanceError) {}
+
+// ViolationLog logs the violation and returns the error, for development
+func ViolationLog(t *RiftToken, err *GovernanceError) {
+	log.Printf("rift: governance violation: %v", err)
+}
+
+// ViolationPanic panics with the error, for strict deployments that must
+// hard-fail on any ungoverned access
+func ViolationPanic(t *RiftToken, err *GovernanceError) {
+	panic(err)
+}
+
+// ViolationHandlerNamed returns the built-in handler for a policy's
+// on_violation setting: "error" (or ""), "log" or "panic"
+func ViolationHandlerNamed(name string) (ViolationHandler, error) {
+	switch name {
+	case "", "error":
+		return ViolationError, nil
+	case "log":
+		return ViolationLog, nil
+	case "panic":
+		return ViolationPanic, nil
+	}
+	return nil, fmt.Errorf("unknown violation handler %q", name)
+}
+
+// ============================================================================
+// Installation
+// ============================================================================
+
+var violationHandler atomic.Pointer[ViolationHandler]
+
+// SetViolationHandler installs h for every token without a handler of its
+// own; nil restores the default ViolationError
+func SetViolationHandler(h ViolationHandler) {
+	if h == nil {
+		violationHandler.Store(nil)
+		return
+	}
+	violationHandler.Store(&h)
+}
+
+// SetViolationHandler installs h for the token's violations, overriding the
+// global handler; nil reverts the token to the global handler
+func (t *RiftToken) SetViolationHandler(h ViolationHandler) {
+	hs := t.hookSet()
+	hs.lock.Lock()
+	hs.violation = h
+	hs.lock.Unlock()
+}
+
+// ============================================================================
+// Dispatch
+// ============================================================================
+
+// violation passes err to the token's handler, or the global one, and
+// returns err
+func (t *RiftToken) violation(err *GovernanceError) *GovernanceError {
+	var h ViolationHandler
+	if hs := t.hooks.Load(); hs != nil {
+		hs.lock.Lock()
+		h = hs.violation
+		hs.lock.Unlock()
+	}
+	if h == nil {
+		if p := violationHandler.Load(); p != nil {
+			h = *p
+		}
+	}
+	if h != nil {
+		h(t, err)
+	}
+	return err
+}
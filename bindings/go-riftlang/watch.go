@@ -0,0 +1,220 @@
+// go/target/watch.go
+// Policy and Pattern Set Hot Reload - Go Implementation
+
+package rift
+
+import (
+	"fmt"
+	"path/filepath"
+	"sync"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// ============================================================================
+// Watcher
+// ============================================================================
+
+// Watcher reloads a .rift file into a pattern engine or policy each time
+// the file changes, so a long-running service picks up governance changes
+// without a restart. The file's directory is watched, so editors that save
+// by writing a new file and renaming it over the old one are seen too. A
+// file that fails to load leaves the previous rules in place.
+type Watcher struct {
+	path   string
+	reload func() error
+	fsw    *fsnotify.Watcher
+	done   chan struct{}
+
+	lock     sync.Mutex
+	onReload func(err error)
+	lastErr  error
+	reloads  uint64
+}
+
+// newWatcher watches path, calling reload after each change
+func newWatcher(path string, reload func() error) (*Watcher, error) {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return nil, fmt.Errorf("watch %s: %w", path, err)
+	}
+	fsw, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, fmt.Errorf("watch %s: %w", path, err)
+	}
+	if err := fsw.Add(filepath.Dir(abs)); err != nil {
+		fsw.Close()
+		return nil, fmt.Errorf("watch %s: %w", path, err)
+	}
+	w := &Watcher{path: abs, reload: reload, fsw: fsw, done: make(chan struct{})}
+	go w.run()
+	return w, nil
+}
+
+// run reloads on events for the watched file until Close
+func (w *Watcher) run() {
+	defer close(w.done)
+	for {
+		select {
+		case ev, ok := <-w.fsw.Events:
+			if !ok {
+				return
+			}
+			// A file renamed over the watched one arrives as a Create
+			if filepath.Clean(ev.Name) != w.path || !ev.Has(fsnotify.Write) && !ev.Has(fsnotify.Create) {
+				continue
+			}
+			w.finish(w.reload())
+		case err, ok := <-w.fsw.Errors:
+			if !ok {
+				return
+			}
+			w.finish(fmt.Errorf("watch %s: %w", w.path, err))
+		}
+	}
+}
+
+// finish records the outcome of a reload and reports it to the hook
+func (w *Watcher) finish(err error) {
+	w.lock.Lock()
+	w.lastErr = err
+	if err == nil {
+		w.reloads++
+	}
+	fn := w.onReload
+	w.lock.Unlock()
+	if fn != nil {
+		fn(err)
+	}
+}
+
+// OnReload registers fn to run after every reload attempt, with the error
+// that kept the previous rules in place or nil. It replaces any earlier
+// hook; nil removes it.
+func (w *Watcher) OnReload(fn func(err error)) {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	w.onReload = fn
+}
+
+// Path returns the absolute path of the watched file
+func (w *Watcher) Path() string {
+	return w.path
+}
+
+// Reloads returns the number of successful reloads
+func (w *Watcher) Reloads() uint64 {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	return w.reloads
+}
+
+// Err returns the error of the last reload attempt, or nil if it
+// succeeded
+func (w *Watcher) Err() error {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	return w.lastErr
+}
+
+// Close stops watching. The rules last loaded stay in place.
+func (w *Watcher) Close() error {
+	err := w.fsw.Close()
+	<-w.done
+	return err
+}
+
+// ============================================================================
+// Pattern Engines
+// ============================================================================
+
+// LoadPatterns replaces the engine's pairs with the pattern declarations
+// of a .rift file, added as AddPair adds a literal pair and governed as
+// declared. All patterns compile before anything changes, and the swap
+// happens under the engine lock, so concurrent matches see either the old
+// pair set or the new one. Replaced pairs are gone, including pairs added
+// by other means, and the new pairs get new TransformIDs.
+func (e *PatternEngine) LoadPatterns(path string) error {
+	policy, err := LoadPolicy(path)
+	if err != nil {
+		return err
+	}
+	pairs := make([]*BipartitePair, len(policy.Patterns))
+	for i, p := range policy.Patterns {
+		if pairs[i], err = newPair(p.Left, p.Right, p.Priority, SubstituteNone); err != nil {
+			return fmt.Errorf("patterns %s: %w", path, err)
+		}
+		pairs[i].IsGoverned = p.Governed
+	}
+
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	tie := e.index.tie
+	e.pairs = make([]*BipartitePair, 0, len(pairs))
+	e.index = newPairIndex()
+	e.index.tie = tie
+	for _, pair := range pairs {
+		e.addPair(pair)
+	}
+	e.version++
+	return nil
+}
+
+// WatchFile loads the pattern declarations of a .rift file into the
+// engine, as LoadPatterns does, and again every time the file changes
+func (e *PatternEngine) WatchFile(path string) (*Watcher, error) {
+	if err := e.LoadPatterns(path); err != nil {
+		return nil, err
+	}
+	return newWatcher(path, func() error { return e.LoadPatterns(path) })
+}
+
+// ============================================================================
+// Policies
+// ============================================================================
+
+// Watch loads the .rift file at path into the policy and again every time
+// the file changes. Each reload swaps every setting at once under the
+// policy's lock, and the policy's methods read a consistent version. While
+// watched, read the policy's fields through Current. Watch must be called
+// before the policy is shared between goroutines.
+func (p *Policy) Watch(path string) (*Watcher, error) {
+	if p.lock == nil {
+		p.lock = new(sync.RWMutex)
+	}
+	if err := p.Reload(path); err != nil {
+		return nil, err
+	}
+	return newWatcher(path, func() error { return p.Reload(path) })
+}
+
+// Reload replaces the policy's settings with those of the .rift file at
+// path. On error the policy is unchanged.
+func (p *Policy) Reload(path string) error {
+	next, err := LoadPolicy(path)
+	if err != nil {
+		return err
+	}
+	if p.lock == nil {
+		*p = *next
+		return nil
+	}
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	next.lock = p.lock
+	*p = *next
+	return nil
+}
+
+// Current returns the policy's settings as of now: the policy itself when
+// it is not watched, otherwise a copy that later reloads leave unchanged
+func (p *Policy) Current() *Policy {
+	if p.lock == nil {
+		return p
+	}
+	p.lock.RLock()
+	defer p.lock.RUnlock()
+	cp := *p
+	cp.lock = nil
+	return &cp
+}